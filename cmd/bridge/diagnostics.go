@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// diagnosticsCheckInterval is how often cameras are checked for a due diagnostics poll
+const diagnosticsCheckInterval = 15 * time.Second
+
+// DiagnosticsStatus is the outcome of a camera's last diagnostics poll
+type DiagnosticsStatus struct {
+	Available bool                   `json:"available"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Missing   []string               `json:"missing,omitempty"` // Fields not found in the response
+	Error     string                 `json:"error,omitempty"`
+	PolledAt  time.Time              `json:"polled_at"`
+}
+
+// cameraDiagnostics holds the last diagnostics poll per camera. Kept in memory
+// only; diagnostics are informational and never affect captures.
+type cameraDiagnostics struct {
+	mu     sync.Mutex
+	status map[string]DiagnosticsStatus
+}
+
+// watchDiagnostics polls due camera diagnostics until stop is closed, which also
+// cancels a poll in progress
+func (b *Bridge) watchDiagnostics(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(diagnosticsCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.pollDiagnostics(ctx, time.Now())
+		}
+	}
+}
+
+// pollDiagnostics polls each enabled camera whose diagnostics interval has passed,
+// and drops the status of cameras that no longer have diagnostics
+func (b *Bridge) pollDiagnostics(ctx context.Context, now time.Time) {
+	configured := make(map[string]bool)
+	for _, cam := range b.configService.ListCameras() {
+		d := cam.Diagnostics
+		if !cam.Enabled || d == nil {
+			continue
+		}
+		configured[cam.ID] = true
+		last, ok := b.diagnostics.get(cam.ID)
+		if ok && now.Sub(last.PolledAt) < time.Duration(d.EffectiveIntervalSeconds())*time.Second {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		status := fetchDiagnostics(ctx, cam, now)
+		b.diagnostics.record(cam.ID, status)
+		switch {
+		case !status.Available && (!ok || last.Available):
+			b.log.Warn("Camera diagnostics unavailable", "camera", cam.ID, "error", status.Error)
+		case status.Available && ok && !last.Available:
+			b.log.Info("Camera diagnostics available again", "camera", cam.ID)
+		}
+	}
+	b.diagnostics.retain(configured)
+}
+
+// fetchDiagnostics polls one camera's diagnostics endpoint
+func fetchDiagnostics(ctx context.Context, cam config.Camera, now time.Time) DiagnosticsStatus {
+	conf := cameraConfig(cam)
+	result, err := camera.FetchDiagnostics(ctx, conf)
+	if err != nil {
+		return DiagnosticsStatus{Error: err.Error(), PolledAt: now}
+	}
+	return DiagnosticsStatus{Available: true, Fields: result.Fields, Missing: result.Missing, PolledAt: now}
+}
+
+// get returns the camera's last diagnostics poll
+func (s *cameraDiagnostics) get(cameraID string) (DiagnosticsStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, ok := s.status[cameraID]
+	return status, ok
+}
+
+// record stores the outcome of a poll, successful or not
+func (s *cameraDiagnostics) record(cameraID string, status DiagnosticsStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == nil {
+		s.status = make(map[string]DiagnosticsStatus)
+	}
+	s.status[cameraID] = status
+}
+
+// retain drops the status of cameras not in ids
+func (s *cameraDiagnostics) retain(ids map[string]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id := range s.status {
+		if !ids[id] {
+			delete(s.status, id)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+)
+
+func TestPollDiagnostics(t *testing.T) {
+	var polls atomic.Int32
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"temp":42}`))
+	}))
+	defer srv.Close()
+
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	cam := config.Camera{
+		ID:                     "kspb",
+		Name:                   "North",
+		Enabled:                true,
+		Type:                   "http",
+		SnapshotURL:            "http://cam.invalid/snap.jpg",
+		CaptureIntervalSeconds: 60,
+		Upload:                 &config.Upload{Host: "upload.invalid", Username: "u", Password: "p"},
+		Diagnostics:            &config.Diagnostics{URL: srv.URL, IntervalSeconds: 60, Fields: map[string]string{"temperature": "temp"}},
+	}
+	if err := svc.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+	bridge := &Bridge{configService: svc, log: logger.Default()}
+
+	now := time.Now()
+	bridge.pollDiagnostics(context.Background(), now)
+	status, ok := bridge.diagnostics.get(cam.ID)
+	if !ok || !status.Available || status.Fields["temperature"] != float64(42) {
+		t.Fatalf("status = %+v, want temperature 42", status)
+	}
+
+	// Not polled again before the interval; a failure then marks it unavailable
+	failing.Store(true)
+	bridge.pollDiagnostics(context.Background(), now.Add(30*time.Second))
+	if polls.Load() != 1 {
+		t.Fatalf("polls = %d, want 1 within the interval", polls.Load())
+	}
+	bridge.pollDiagnostics(context.Background(), now.Add(time.Minute))
+	status, _ = bridge.diagnostics.get(cam.ID)
+	if status.Available || status.Error == "" || status.Fields != nil {
+		t.Errorf("status after failure = %+v, want unavailable", status)
+	}
+
+	// Removing diagnostics drops the status
+	if err := svc.UpdateCamera(cam.ID, func(c *config.Camera) error {
+		c.Diagnostics = nil
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateCamera: %v", err)
+	}
+	bridge.pollDiagnostics(context.Background(), now.Add(2*time.Minute))
+	if _, ok := bridge.diagnostics.get(cam.ID); ok {
+		t.Error("status kept after diagnostics were removed")
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+)
+
+// cameraLogLevels holds each camera's log level. Workers log through a logger that
+// reads the level on every message, so a change applies without restarting them.
+type cameraLogLevels struct {
+	mu     sync.Mutex
+	levels map[string]*slog.LevelVar
+}
+
+// set applies cam.LogLevel, or base when unset, returning the camera's level and
+// whether an existing level changed
+func (c *cameraLogLevels) set(cam config.Camera, base slog.Level) (*slog.LevelVar, bool) {
+	level, ok := logger.ParseLevel(cam.LogLevel)
+	if !ok {
+		level = base
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.levels == nil {
+		c.levels = make(map[string]*slog.LevelVar)
+	}
+	lv, exists := c.levels[cam.ID]
+	if !exists {
+		lv = new(slog.LevelVar)
+		c.levels[cam.ID] = lv
+	}
+	changed := exists && lv.Level() != level
+	lv.Set(level)
+	return lv, changed
+}
+
+// remove forgets a deleted camera's level
+func (c *cameraLogLevels) remove(cameraID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.levels, cameraID)
+}
+
+// cameraLogger returns the logger for a camera's capture and upload messages
+func (b *Bridge) cameraLogger(cam config.Camera) *logger.Logger {
+	level, _ := b.logLevels.set(cam, b.log.Level())
+	return b.log.WithLevel(level)
+}
+
+// applyCameraLogLevel updates the log level of a camera's running worker
+func (b *Bridge) applyCameraLogLevel(cam config.Camera) {
+	if level, changed := b.logLevels.set(cam, b.log.Level()); changed {
+		b.log.Info("Camera log level changed", "camera", cam.ID, "level", level.Level())
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+func TestCameraLogLevels(t *testing.T) {
+	var levels cameraLogLevels
+	cam := config.Camera{ID: "kspb"}
+
+	lv, changed := levels.set(cam, slog.LevelInfo)
+	if lv.Level() != slog.LevelInfo || changed {
+		t.Fatalf("unset level = %v (changed %v), want the base level", lv.Level(), changed)
+	}
+
+	// An override changes the same level the running worker logs with
+	cam.LogLevel = "debug"
+	again, changed := levels.set(cam, slog.LevelInfo)
+	if again != lv || lv.Level() != slog.LevelDebug || !changed {
+		t.Errorf("override = %v (changed %v), want debug in place", lv.Level(), changed)
+	}
+	if _, changed := levels.set(cam, slog.LevelInfo); changed {
+		t.Error("reapplying the same level reported a change")
+	}
+
+	levels.remove(cam.ID)
+	if fresh, _ := levels.set(cam, slog.LevelInfo); fresh == lv {
+		t.Error("removed camera kept its level")
+	}
+}
+
+func TestCameraSnapshot_IgnoresLogLevel(t *testing.T) {
+	cam := config.Camera{ID: "kspb", Name: "North"}
+	debug := cam
+	debug.LogLevel = "debug"
+	if !bytes.Equal(cameraSnapshot(cam), cameraSnapshot(debug)) {
+		t.Error("a log level change would restart the worker")
+	}
+	renamed := cam
+	renamed.Name = "South"
+	if bytes.Equal(cameraSnapshot(cam), cameraSnapshot(renamed)) {
+		t.Error("other changes must still restart the worker")
+	}
+}
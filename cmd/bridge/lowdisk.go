@@ -0,0 +1,95 @@
+package main
+
+import (
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/pkg/health"
+)
+
+// diskCheckInterval is how often queue disk usage is checked for low_disk_image
+const diskCheckInterval = 30 * time.Second
+
+// lowDiskPolicy reduces image quality on every camera while the queue disk is
+// filling up, and restores it once the disk recovers
+type lowDiskPolicy struct {
+	reduction *image.Reduction // Shared by every camera's image processor
+	active    bool
+}
+
+// update applies cfg for the current disk level, returning true when reduction
+// was switched on or off. Reduction starts at the trigger level and only stops once
+// the disk is healthy again, so usage hovering at a threshold does not flap.
+func (p *lowDiskPolicy) update(cfg *config.LowDiskImage, level health.Level) bool {
+	want := p.active
+	switch {
+	case cfg == nil || !cfg.Enabled:
+		want = false
+	case level == health.LevelHealthy:
+		want = false
+	case level == health.LevelCritical || cfg.TriggerLevel != "critical":
+		want = true
+	}
+
+	if want {
+		// Settings may have changed while active, so the caps are always refreshed
+		p.reduction.Set(&config.ImageProcessing{Quality: lowDiskQuality(cfg), MaxWidth: cfg.MaxWidth})
+	} else {
+		p.reduction.Set(nil)
+	}
+	changed := want != p.active
+	p.active = want
+	return changed
+}
+
+// lowDiskQuality returns the JPEG quality applied while disk space is low
+func lowDiskQuality(cfg *config.LowDiskImage) int {
+	if cfg.Quality == 0 {
+		return config.DefaultLowDiskQuality
+	}
+	return cfg.Quality
+}
+
+// watchDiskSpace checks queue disk usage until stop is closed, reducing image
+// quality per low_disk_image
+func (b *Bridge) watchDiskSpace(stop <-chan struct{}) {
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+	for {
+		b.checkDiskSpace()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDiskSpace applies low_disk_image for the current disk level
+func (b *Bridge) checkDiskSpace() {
+	var cfg *config.LowDiskImage
+	if g := b.configService.GetGlobal().Global; g != nil {
+		cfg = g.LowDiskImage
+	}
+	if (cfg == nil || !cfg.Enabled) && !b.lowDisk.active {
+		return // Nothing to do, so skip the disk stats
+	}
+
+	stats := b.systemMonitor.GetStats()
+	if !b.lowDisk.update(cfg, stats.DiskLevel) {
+		return
+	}
+	if b.lowDisk.active {
+		b.log.Warn("Queue disk filling up - reducing image quality on all cameras",
+			"disk_percent", stats.DiskPercent,
+			"disk_free_mb", stats.DiskFreeMB,
+			"level", stats.DiskLevel,
+			"quality", lowDiskQuality(cfg),
+			"max_width", cfg.MaxWidth)
+	} else {
+		b.log.Info("Queue disk recovered - image quality restored",
+			"disk_percent", stats.DiskPercent,
+			"level", stats.DiskLevel)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/pkg/health"
+)
+
+func TestLowDiskPolicy_update(t *testing.T) {
+	p := lowDiskPolicy{reduction: &image.Reduction{}}
+	cfg := &config.LowDiskImage{Enabled: true}
+
+	steps := []struct {
+		level   health.Level
+		changed bool
+		active  bool
+	}{
+		{health.LevelHealthy, false, false},
+		{health.LevelWarning, true, true},
+		{health.LevelCritical, false, true},
+		{health.LevelWarning, false, true}, // Stays reduced until the disk is healthy
+		{health.LevelHealthy, true, false},
+	}
+	for i, s := range steps {
+		changed := p.update(cfg, s.level)
+		if changed != s.changed || p.active != s.active || p.reduction.Active() != s.active {
+			t.Errorf("step %d (%s): changed=%v active=%v reduction=%v", i, s.level, changed, p.active, p.reduction.Active())
+		}
+	}
+
+	// A critical trigger ignores warnings
+	cfg.TriggerLevel = "critical"
+	if p.update(cfg, health.LevelWarning) || p.active {
+		t.Error("critical trigger activated at warning")
+	}
+	if !p.update(cfg, health.LevelCritical) || !p.active {
+		t.Error("critical trigger did not activate at critical")
+	}
+
+	// Disabling the setting lifts the reduction immediately
+	cfg.Enabled = false
+	if !p.update(cfg, health.LevelCritical) || p.active || p.reduction.Active() {
+		t.Error("disabled policy still active")
+	}
+}
+
+func TestLowDiskQuality(t *testing.T) {
+	if q := lowDiskQuality(&config.LowDiskImage{}); q != config.DefaultLowDiskQuality {
+		t.Errorf("default quality = %d", q)
+	}
+	if q := lowDiskQuality(&config.LowDiskImage{Quality: 45}); q != 45 {
+		t.Errorf("quality = %d, want 45", q)
+	}
+}
@@ -1,24 +1,34 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/alert"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/history"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/metrics"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/mqtt"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/scheduler"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/statsd"
 	timehealth "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/tunnel"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/update"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/web"
@@ -75,7 +85,22 @@ type Bridge struct {
 	systemMonitor   *health.SystemMonitor
 	timeHealth      *timehealth.TimeHealth
 	resourceLimiter *resource.Limiter
+	alerts          *alert.Notifier
+	sharedFetch     *camera.SharedFetch // Coalesces captures of identical sources
+	frameHistory    *history.Store      // Recent frames kept for review in the web console
+	uploadPool      *upload.Pool        // Shared connections for cameras on one upload account
+	lowDisk         lowDiskPolicy       // Reduces image quality while the queue disk fills up
+	diskWatchStop   chan struct{}
+	timelapses      timelapseBuilds // Last daily timelapse per camera
+	timelapseStop   chan struct{}
+	retryStop       chan struct{} // Stops retrying failed camera workers
+	workersMu       sync.Mutex    // Serializes config changes with worker retries
 	log             *logger.Logger
+	configDir       string // Where the shutdown snapshot is written
+
+	// Last diagnostics poll per camera
+	diagnostics     cameraDiagnostics
+	diagnosticsStop chan struct{}
 
 	// Preview cache (in-memory only)
 	lastCaptures map[string]*CachedImage
@@ -84,6 +109,28 @@ type Bridge struct {
 	// Worker status tracking
 	cameraWorkerStatus map[string]*CameraWorkerStatus
 	workerStatusMu     sync.RWMutex
+
+	// SSH tunnels for cameras behind CGNAT, by camera ID
+	tunnels   map[string]*tunnel.Tunnel
+	tunnelsMu sync.Mutex
+
+	// Last /metrics snapshot, served while shedding work
+	lastMetrics *metrics.Snapshot
+	metricsMu   sync.Mutex
+
+	// Optional MQTT client for capture commands and events
+	mqtt         *mqtt.Client
+	mqttPrefix   string
+	mqttSettings config.MQTT // Settings mqtt was started with
+	mqttMu       sync.Mutex
+
+	// Optional StatsD metrics export
+	statsd         *statsd.Client
+	statsdSettings config.StatsD // Settings statsd was started with
+	statsdMu       sync.Mutex
+
+	// Per-camera log levels, changed without restarting workers
+	logLevels cameraLogLevels
 }
 
 // CameraWorkerStatus tracks the runtime status of a camera worker
@@ -99,6 +146,10 @@ type CameraWorkerStatus struct {
 	QueuedImages       int
 	CurrentlyCapturing bool
 	CurrentlyUploading bool
+	RetryAttempts      int       // Automatic start retries since the camera was last saved
+	NextRetry          time.Time // Next automatic start retry; zero if none is scheduled
+
+	appliedConfig []byte // Camera config the running worker was started with (JSON)
 }
 
 // CachedImage holds a captured image with metadata
@@ -141,12 +192,37 @@ func main() {
 		"configDir", configDir,
 		"legacyPath", legacyConfigPath)
 
-	configService, err := config.InitOrMigrate(configDir, legacyConfigPath)
+	serviceOpts, err := configServiceOptions()
+	if err != nil {
+		log.Error("Invalid config service option", "error", err)
+		os.Exit(1)
+	}
+	configService, err := config.InitOrMigrateWithOptions(configDir, legacyConfigPath, serviceOpts)
 	if err != nil {
 		log.Error("Failed to initialize config service", "error", err)
 		os.Exit(1)
 	}
 	log.Info("Config service initialized", "dir", configDir)
+	if configService.IsReadOnly() {
+		log.Warn("Config is from a newer bridge version or failed to migrate - running read-only, changes will not be saved",
+			"version", configService.GetGlobal().Version,
+			"supported", config.CurrentVersion)
+	}
+
+	logConfigProblems(log, configService.Check())
+
+	strict := strictStartupEnabled(configService.GetGlobal())
+	if strict {
+		log.Info("Strict startup enabled - unrecoverable init failures will exit non-zero")
+	}
+
+	if err := checkWritable(configDir); err != nil {
+		if strict {
+			log.Error("Config directory is not writable", "dir", configDir, "error", err)
+			os.Exit(1)
+		}
+		log.Warn("Config directory is not writable - changes will not persist", "dir", configDir, "error", err)
+	}
 
 	// Create update checker
 	updateChecker := update.NewChecker(Version, GitCommit)
@@ -159,6 +235,12 @@ func main() {
 		queuePath = "/dev/shm/aviationwx"
 	}
 
+	// Frame history lives with the config so it survives restarts, unlike the queue
+	historyPath := os.Getenv("AVIATIONWX_HISTORY_PATH")
+	if historyPath == "" {
+		historyPath = filepath.Join(configDir, "history")
+	}
+
 	// Initialize time health (SNTP)
 	global := configService.GetGlobal()
 	var timeHealth *timehealth.TimeHealth
@@ -179,11 +261,21 @@ func main() {
 	// Create resource limiter for background work throttling
 	// On devices with < 1GB RAM, this will serialize image processing
 	resourceConfig := resource.DefaultConfig()
+	if g := configService.GetGlobal().Global; g != nil && g.MaxConcurrentRequests > 0 {
+		resourceConfig.MaxConcurrentWebRequests = g.MaxConcurrentRequests
+	}
+	resourceConfig.GoroutineCeiling = goroutineCeiling(configService.GetGlobal())
+	applyExifToolHangPolicy(configService.GetGlobal())
+	applyExifToolStayOpen(configService.GetGlobal())
+	applyExifToolRecheck(configService.GetGlobal())
+	resourceConfig.Logger = log
 	resourceLimiter := resource.NewLimiter(resourceConfig)
 
 	log.Info("Resource limiter initialized",
 		"max_image_processing", resourceConfig.MaxConcurrentImageProcessing,
 		"max_exif_operations", resourceConfig.MaxConcurrentExifOperations,
+		"max_web_requests", resourceConfig.MaxConcurrentWebRequests,
+		"goroutine_ceiling", resourceConfig.GoroutineCeiling,
 		"num_cpu", runtime.NumCPU(),
 		"gomaxprocs", runtime.GOMAXPROCS(0))
 
@@ -194,13 +286,28 @@ func main() {
 		systemMonitor:      health.NewSystemMonitor(queuePath),
 		timeHealth:         timeHealth,
 		resourceLimiter:    resourceLimiter,
+		sharedFetch:        camera.NewSharedFetch(sharedFetchReuse(configService.GetGlobal())),
+		frameHistory:       history.NewStore(historyPath),
+		uploadPool:         upload.NewPool(0),
+		lowDisk:            lowDiskPolicy{reduction: &image.Reduction{}},
+		diskWatchStop:      make(chan struct{}),
+		timelapseStop:      make(chan struct{}),
+		retryStop:          make(chan struct{}),
+		diagnosticsStop:    make(chan struct{}),
 		log:                log,
+		configDir:          configDir,
 		lastCaptures:       make(map[string]*CachedImage),
 		cameraWorkerStatus: make(map[string]*CameraWorkerStatus),
+		tunnels:            make(map[string]*tunnel.Tunnel),
 	}
+	bridge.alerts = alert.NewNotifier(bridge.alertWebhookURL, log)
 
 	// Initialize orchestrator
 	if err := bridge.initOrchestrator(); err != nil {
+		if strict {
+			log.Error("Could not initialize orchestrator", "error", err)
+			os.Exit(1)
+		}
 		log.Warn("Could not initialize orchestrator - cameras disabled", "error", err)
 	}
 
@@ -208,18 +315,33 @@ func main() {
 	bridge.webServer = web.NewServer(web.ServerConfig{
 		ConfigService:   configService,
 		GetStatus:       bridge.getStatus,
+		GetStatusPage:   bridge.getStatusPage,
+		GetSummary:      bridge.getSummary,
 		TestCamera:      bridge.testCamera,
 		TestUpload:      bridge.testUpload,
+		TestRoundTrip:   bridge.testUploadRoundTrip,
 		GetCameraImage:  bridge.getCameraImage,
 		GetWorkerStatus: bridge.getWorkerStatus,
+		GetQuality:      bridge.getCameraQuality,
+		GetHistory:      bridge.frameHistory.List,
+		GetHistoryFrame: bridge.frameHistory.Get,
+		ExifToolVersion: exifToolVersion,
+		FindExifTool:    func() interface{} { return timehealth.RediscoverExifTool() },
+		Metrics:         metrics.Handler(bridge.metricsSnapshot),
+		ResourceLimiter: resourceLimiter,
 	})
 
 	// Subscribe to config changes
 	configService.Subscribe(bridge.handleConfigEvent)
 
-	// Start orchestrator if we have cameras
+	go bridge.watchDiskSpace(bridge.diskWatchStop)
+	go bridge.watchTimelapses(bridge.timelapseStop)
+	go bridge.retryFailedWorkers(bridge.retryStop)
+	go bridge.watchDiagnostics(bridge.diagnosticsStop)
+
+	// Start orchestrator; without cameras it idles until one is added
 	cameras := configService.ListCameras()
-	if bridge.orchestrator != nil && len(cameras) > 0 {
+	if bridge.orchestrator != nil {
 		if err := bridge.orchestrator.Start(); err != nil {
 			log.Warn("Failed to start orchestrator", "error", err)
 		} else {
@@ -227,6 +349,14 @@ func main() {
 		}
 	}
 
+	// Connect to the MQTT broker for capture commands and events, if configured
+	if err := bridge.restartMQTT(configService.GetGlobal().MQTT); err != nil {
+		log.Warn("Could not start MQTT - continuing without it", "error", err)
+	}
+	if err := bridge.restartStatsD(configService.GetGlobal().StatsD); err != nil {
+		log.Warn("Could not start StatsD export - continuing without it", "error", err)
+	}
+
 	// Start web server with panic recovery
 	webErrChan := make(chan error, 1)
 	go func() {
@@ -241,6 +371,11 @@ func main() {
 		log.Info("Web console available",
 			"url", fmt.Sprintf("http://localhost:%d", port),
 			"password", configService.GetWebPassword())
+		if configService.UsingDefaultWebPassword() {
+			log.Warn("Web console is using the default password - change it in Settings",
+				"policy", configService.DefaultPasswordPolicy(),
+				"listen", bridge.webServer.ListenAddr())
+		}
 		if err := bridge.webServer.Start(); err != nil {
 			log.Error("Web server error", "error", err)
 			webErrChan <- err
@@ -251,28 +386,302 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	exitCode := 0
 	select {
 	case <-sigChan:
 		log.Info("Shutting down gracefully...")
 	case err := <-webErrChan:
 		log.Error("Fatal error - shutting down", "error", err)
+		if strict {
+			exitCode = 1
+		}
 	}
 
-	// Stop services
-	if bridge.updateChecker != nil {
-		bridge.updateChecker.Stop()
+	// Stop services, then log the run summary and write the shutdown snapshot
+	bridge.shutdown()
+
+	log.Info("Goodbye!")
+	if exitCode != 0 {
+		os.Exit(exitCode)
 	}
-	if bridge.orchestrator != nil {
-		bridge.orchestrator.Stop()
+}
+
+// strictStartupEnabled reports whether unrecoverable init failures should exit non-zero.
+// AVIATIONWX_STRICT_STARTUP overrides the config so supervisors can enable it per unit.
+func strictStartupEnabled(global config.GlobalSettings) bool {
+	if v := os.Getenv("AVIATIONWX_STRICT_STARTUP"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		return err == nil && enabled
 	}
+	return global.Global != nil && global.Global.StrictStartup
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if err := bridge.webServer.Stop(ctx); err != nil {
-		log.Error("Error stopping server", "error", err)
+// logConfigProblems reports configuration errors found at startup. The bridge still
+// starts; an affected camera may fail to capture or upload until it is fixed.
+func logConfigProblems(log *logger.Logger, problems []config.Problem) {
+	for _, p := range problems {
+		log.Warn("Config problem", "camera", p.Camera, "field", p.Field, "error", p.Message)
+	}
+	if len(problems) > 0 {
+		log.Warn("Config has problems - see /api/config/validation", "count", len(problems))
+	}
+}
+
+// configServiceOptions reads AVIATIONWX_FUTURE_CONFIG ("read_only" or "refuse"),
+// which controls startup when the config comes from a newer bridge version or fails
+// to migrate, and the config event queue settings (AVIATIONWX_CONFIG_EVENT_QUEUE/_OVERFLOW).
+// An unknown AVIATIONWX_FUTURE_CONFIG is an error: a misspelled "refuse" would
+// otherwise silently run read-only.
+func configServiceOptions() (config.ServiceOptions, error) {
+	var opts config.ServiceOptions
+	if v := os.Getenv("AVIATIONWX_FUTURE_CONFIG"); v != "" {
+		opts.FutureVersion = config.FutureVersionPolicy(strings.ToLower(v))
+		if !opts.FutureVersion.Valid() {
+			return opts, fmt.Errorf("AVIATIONWX_FUTURE_CONFIG must be %q or %q, got %q",
+				config.FutureVersionReadOnly, config.FutureVersionRefuse, v)
+		}
+	}
+	if v := os.Getenv("AVIATIONWX_CONFIG_EVENT_QUEUE"); v != "" {
+		if size, err := strconv.Atoi(v); err == nil {
+			opts.EventQueueSize = size
+		}
+	}
+	if v := os.Getenv("AVIATIONWX_CONFIG_EVENT_OVERFLOW"); v != "" {
+		opts.EventOverflow = config.EventOverflowPolicy(strings.ToLower(v))
 	}
+	return opts, nil
+}
 
-	log.Info("Goodbye!")
+// timePolicy returns the configured capture behavior while time is unhealthy
+func timePolicy(global config.GlobalSettings) string {
+	if global.Global == nil || global.Global.TimeAuthority == nil {
+		return ""
+	}
+	return global.Global.TimeAuthority.UnhealthyPolicy
+}
+
+// timeRegressionPolicy returns the timestamp regression policy and tolerance
+func timeRegressionPolicy(global config.GlobalSettings) (string, time.Duration) {
+	if global.Global == nil || global.Global.TimeAuthority == nil {
+		return "", 0
+	}
+	ta := global.Global.TimeAuthority
+	return ta.RegressionPolicy, time.Duration(ta.RegressionToleranceSeconds) * time.Second
+}
+
+// uploadQuietHours converts a configured quiet window, logging and ignoring it if invalid
+func (b *Bridge) uploadQuietHours(q *config.QuietHours) *scheduler.QuietHours {
+	if q == nil {
+		return nil
+	}
+	window, err := scheduler.ParseQuietHours(q.Start, q.End)
+	if err != nil {
+		b.log.Warn("Ignoring invalid upload quiet hours", "start", q.Start, "end", q.End, "error", err)
+		return nil
+	}
+	return window
+}
+
+// globalQuietHours returns the configured global upload quiet window, if any
+func (b *Bridge) globalQuietHours(global config.GlobalSettings) *scheduler.QuietHours {
+	if global.Global == nil {
+		return nil
+	}
+	return b.uploadQuietHours(global.Global.UploadQuietHours)
+}
+
+// uploadConcurrency returns the auto-tuning range, or nil for fixed concurrency.
+// Max defaults to the configured fixed value so enabling tuning alone never raises it
+func uploadConcurrency(global config.GlobalSettings, maxConcurrent int) *scheduler.ConcurrencyTuning {
+	if global.Global == nil || global.Global.UploadConcurrency == nil || !global.Global.UploadConcurrency.AutoTune {
+		return nil
+	}
+	uc := global.Global.UploadConcurrency
+	tuning := &scheduler.ConcurrencyTuning{
+		Min:           uc.Min,
+		Max:           uc.Max,
+		TargetLatency: time.Duration(uc.TargetLatencySeconds) * time.Second,
+	}
+	if tuning.Max == 0 {
+		tuning.Max = min(maxConcurrent, config.MaxAutoTuneConcurrency)
+	}
+	return tuning
+}
+
+// uploadCircuitBreaker returns the breaker settings, or nil when it is disabled
+func uploadCircuitBreaker(global config.GlobalSettings) *scheduler.CircuitBreaker {
+	if global.Global == nil || global.Global.UploadCircuitBreaker == nil || !global.Global.UploadCircuitBreaker.Enabled {
+		return nil
+	}
+	cb := global.Global.UploadCircuitBreaker
+	return &scheduler.CircuitBreaker{
+		FailureThreshold: cb.FailureThreshold,
+		Cooldown:         time.Duration(cb.CooldownSeconds) * time.Second,
+	}
+}
+
+// defaultClockJumpThreshold is the wall-clock jump treated as a suspend/resume
+const defaultClockJumpThreshold = 2 * time.Minute
+
+// clockJumpThreshold returns the clock jump threshold, or 0 when detection is disabled
+func clockJumpThreshold(global config.GlobalSettings) time.Duration {
+	if global.Global == nil || global.Global.ClockJump == nil || !global.Global.ClockJump.Enabled {
+		return 0
+	}
+	if secs := global.Global.ClockJump.ThresholdSeconds; secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultClockJumpThreshold
+}
+
+// queueFallbackPath is where queues move if the queue filesystem turns read-only;
+// AVIATIONWX_QUEUE_FALLBACK_PATH overrides the config ("" = no fallback)
+func queueFallbackPath(global config.GlobalSettings) string {
+	if p := os.Getenv("AVIATIONWX_QUEUE_FALLBACK_PATH"); p != "" {
+		return p
+	}
+	if global.Global == nil {
+		return ""
+	}
+	return global.Global.QueueFallbackPath
+}
+
+// defaultThroughputHalfLife smooths achieved capture and upload rates
+const defaultThroughputHalfLife = 5 * time.Minute
+
+// throughputHalfLife returns the half-life of the achieved rates, or 0 when they
+// are not tracked
+func throughputHalfLife(g *config.Global) time.Duration {
+	if g == nil || g.ThroughputRates == nil || !g.ThroughputRates.Enabled {
+		return 0
+	}
+	if secs := g.ThroughputRates.HalfLifeSeconds; secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultThroughputHalfLife
+}
+
+// sharedFetchReuse returns how long a completed shared fetch is reused
+func sharedFetchReuse(global config.GlobalSettings) time.Duration {
+	if global.Global == nil {
+		return 0
+	}
+	return time.Duration(global.Global.SharedFetchReuseMs) * time.Millisecond
+}
+
+// uploadConnectionInterval returns the configured gap between new upload
+// connections; 0 uses the upload worker default
+func uploadConnectionInterval(global config.GlobalSettings) time.Duration {
+	if global.Global == nil {
+		return 0
+	}
+	return time.Duration(global.Global.UploadConnectionIntervalMs) * time.Millisecond
+}
+
+// uploadBandwidthLimit returns the configured total upload rate in bytes per second
+// (0 = unlimited)
+func uploadBandwidthLimit(global config.GlobalSettings) int64 {
+	if global.Global == nil {
+		return 0
+	}
+	return int64(global.Global.UploadMaxKBps) * 1024
+}
+
+// goroutineCeiling returns the configured goroutine ceiling (0 = disabled)
+func goroutineCeiling(global config.GlobalSettings) int {
+	if global.Global == nil {
+		return 0
+	}
+	return global.Global.GoroutineCeiling
+}
+
+// applyExifToolHangPolicy sets when repeated exiftool hangs switch stamping to the
+// builtin writer
+func applyExifToolHangPolicy(global config.GlobalSettings) {
+	var limit, cooldownSecs int
+	if global.Global != nil {
+		limit = global.Global.ExifToolHangLimit
+		cooldownSecs = global.Global.ExifToolHangCooldownSeconds
+	}
+	timehealth.SetExifToolHangPolicy(limit, time.Duration(cooldownSecs)*time.Second)
+}
+
+// applyExifToolStayOpen enables or disables the persistent exiftool process
+func applyExifToolStayOpen(global config.GlobalSettings) {
+	var enabled bool
+	var idleSecs int
+	if global.Global != nil {
+		enabled = global.Global.ExifToolStayOpen
+		idleSecs = global.Global.ExifToolIdleSeconds
+	}
+	timehealth.SetExifToolStayOpen(enabled, time.Duration(idleSecs)*time.Second)
+}
+
+// applyExifToolRecheck sets how often a missing exiftool is looked for again
+func applyExifToolRecheck(global config.GlobalSettings) {
+	var secs int
+	if global.Global != nil {
+		secs = global.Global.ExifToolRecheckSeconds
+	}
+	timehealth.SetExifToolRecheckInterval(time.Duration(secs) * time.Second)
+}
+
+// alertWebhookURL returns the configured alert webhook, read per alert so config
+// changes apply without a restart
+func (b *Bridge) alertWebhookURL() string {
+	if g := b.configService.GetGlobal().Global; g != nil {
+		return g.AlertWebhookURL
+	}
+	return ""
+}
+
+// handleSLAChange forwards a camera freshness SLA breach or recovery to the alert webhook
+func (b *Bridge) handleSLAChange(e scheduler.SLAEvent) {
+	event := alert.Event{
+		Type:     "sla_recovered",
+		BridgeID: bridgeID(),
+		CameraID: e.CameraID,
+		Message:  fmt.Sprintf("camera %s is uploading fresh images again", e.CameraID),
+		Time:     time.Now(),
+		Details: map[string]interface{}{
+			"sla_seconds": int64(e.SLA.Seconds()),
+			"age_seconds": int64(e.Age.Seconds()),
+		},
+	}
+	if e.Breached {
+		event.Type = "sla_breached"
+		event.Message = fmt.Sprintf("camera %s has not uploaded a fresh image in %s (SLA %s)",
+			e.CameraID, e.Age.Round(time.Second), e.SLA)
+	}
+	if !e.LastUpload.IsZero() {
+		event.Details["last_upload"] = e.LastUpload.UTC().Format(time.RFC3339)
+	}
+	if b.alerts != nil {
+		b.alerts.Notify(event)
+	}
+	b.publishMQTTEvent(event)
+}
+
+// defaultEventCooldown is the minimum gap between event-triggered captures
+const defaultEventCooldown = 10 * time.Second
+
+// onvifEventCooldown returns the configured gap between event-triggered captures
+func onvifEventCooldown(onvif *config.ONVIF) time.Duration {
+	if onvif == nil || onvif.Events == nil || onvif.Events.CooldownSeconds <= 0 {
+		return defaultEventCooldown
+	}
+	return time.Duration(onvif.Events.CooldownSeconds) * time.Second
+}
+
+// checkWritable verifies a directory accepts new files by creating and removing a probe
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".write-probe-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
 }
 
 // initOrchestrator initializes the orchestrator and adds cameras
@@ -289,13 +698,47 @@ func (b *Bridge) initOrchestrator() error {
 		maxConcurrent = global.Global.MaxConcurrentUploads
 	}
 
+	var compactionSecs, orphanMaxAgeSecs, reconcileSecs int // 0 uses orchestrator defaults
+	if global.Queue != nil {
+		compactionSecs = global.Queue.CompactionSeconds
+		orphanMaxAgeSecs = global.Queue.OrphanMaxAgeSecs
+		reconcileSecs = global.Queue.ReconcileSeconds
+	}
+	regressionPolicy, regressionTolerance := timeRegressionPolicy(global)
+	queueMaxTotalMB, queueMaxHeapMB := queueManagerLimits(global)
+
+	// Frame numbers live with the config so they survive restarts and queue wipes
+	sequences, err := scheduler.OpenSequenceStore(filepath.Join(b.configDir, "sequences.json"))
+	if err != nil {
+		b.log.Warn("Frame sequences reset - numbering restarts at 1", "error", err)
+	}
+
 	orch, err := scheduler.NewOrchestrator(scheduler.OrchestratorConfig{
-		QueueBasePath:        queuePath,
-		QueueMaxTotalMB:      100,
-		QueueMaxHeapMB:       400,
-		MaxConcurrentUploads: maxConcurrent,
-		ResourceLimiter:      b.resourceLimiter,
-		Logger:               b.log,
+		QueueBasePath:         queuePath,
+		QueueMaxTotalMB:       queueMaxTotalMB,
+		QueueMaxHeapMB:        queueMaxHeapMB,
+		MaxConcurrentUploads:  maxConcurrent,
+		UploadConcurrency:     uploadConcurrency(global, maxConcurrent),
+		UploadCircuitBreaker:  uploadCircuitBreaker(global),
+		ConnectionInterval:    uploadConnectionInterval(global),
+		UploadQuietHours:      b.globalQuietHours(global),
+		OnSLAChange:           b.handleSLAChange,
+		OnUploadFailure:       b.handleUploadFailure,
+		Timezone:              global.Timezone,
+		TimePolicy:            timePolicy(global),
+		RegressionPolicy:      regressionPolicy,
+		RegressionTolerance:   regressionTolerance,
+		QueueCompactionSecs:   compactionSecs,
+		QueueOrphanMaxAgeSecs: orphanMaxAgeSecs,
+		QueueReconcileSecs:    reconcileSecs,
+		ResourceLimiter:       b.resourceLimiter,
+		Sequences:             sequences,
+		Logger:                b.log,
+
+		StayActiveWithoutCameras: global.Global != nil && global.Global.StayActiveWithoutCameras,
+		ClockJumpThreshold:       clockJumpThreshold(global),
+		QueueFallbackPath:        queueFallbackPath(global),
+		UploadMaxBytesPerSec:     uploadBandwidthLimit(global),
 	})
 	if err != nil {
 		return fmt.Errorf("create orchestrator: %w", err)
@@ -336,18 +779,10 @@ func (b *Bridge) updateTimezone(timezone string) error {
 
 	b.log.Info("Updating timezone", "new_timezone", timezone)
 
-	// Create new authority with updated timezone
-	authorityConfig := timehealth.DefaultAuthorityConfig()
-	authorityConfig.Timezone = timezone
-
-	authority, err := timehealth.NewAuthority(b.timeHealth, authorityConfig)
-	if err != nil {
-		return fmt.Errorf("create authority: %w", err)
+	if err := b.orchestrator.SetTimezone(timezone); err != nil {
+		return fmt.Errorf("set timezone: %w", err)
 	}
 
-	// Update orchestrator's authority
-	b.orchestrator.SetTimeAuthority(authority)
-
 	b.log.Info("Timezone updated successfully", "timezone", timezone)
 	return nil
 }
@@ -399,7 +834,26 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 	b.cameraWorkerStatus[camConfig.ID] = status
 	b.workerStatusMu.Unlock()
 
-	b.log.Info("Camera added", "camera", camConfig.ID, "interval_secs", camConfig.CaptureIntervalSeconds)
+	b.log.Info("Camera added", "camera", camConfig.ID, "interval_secs", camConfig.EffectiveCaptureIntervalSeconds())
+
+	// Route the camera through its SSH tunnel, closed again if the worker fails to start
+	if camConfig.Tunnel != nil {
+		tun, tunneled, err := b.openTunnel(camConfig)
+		if err != nil {
+			status.LastError = fmt.Sprintf("Open tunnel failed: %v", err)
+			status.ErrorCount++
+			return fmt.Errorf("open tunnel: %w", err)
+		}
+		b.tunnelsMu.Lock()
+		b.tunnels[camConfig.ID] = tun
+		b.tunnelsMu.Unlock()
+		camConfig = tunneled
+		defer func() {
+			if !status.Running {
+				b.closeTunnel(camConfig.ID)
+			}
+		}()
+	}
 
 	// Create camera instance
 	cam, err := b.createCamera(camConfig)
@@ -408,6 +862,17 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 		status.ErrorCount++
 		return fmt.Errorf("create camera: %w", err)
 	}
+	if rd := camConfig.Rediscovery; rd != nil && rd.Enabled {
+		cam = camera.NewRediscoveringCamera(cam, cameraConfig(camConfig), camera.RediscoveryConfig{
+			DeviceID:         rd.DeviceID,
+			FailureThreshold: rd.FailureThreshold,
+			MinInterval:      time.Duration(rd.MinIntervalMinutes) * time.Minute,
+		})
+	}
+	// Folder cameras read local files, so there is no fetch worth sharing
+	if g := b.configService.GetGlobal().Global; g != nil && g.SharedFetch && b.sharedFetch != nil && camConfig.Type != "folder" {
+		cam = b.sharedFetch.Wrap(cam, camera.SourceKey(cameraConfig(camConfig)))
+	}
 
 	// Create image processor
 	var imgProcessor *image.Processor
@@ -416,6 +881,7 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 	} else {
 		imgProcessor = image.NewProcessor(nil)
 	}
+	imgProcessor.WithReduction(b.lowDisk.reduction)
 
 	// Use remote_path from config, default to "." (upload directly to base_path)
 	// Each camera has unique credentials with its own chroot, so no subdirectory needed
@@ -424,22 +890,88 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 		remotePath = "."
 	}
 
-	schedConfig := scheduler.CameraConfig{
-		RemotePath:     remotePath,
-		ImageProcessor: imgProcessor,
+	interval := camConfig.EffectiveCaptureIntervalSeconds()
+	queueLimits := cameraQueueLimits(b.configService.GetGlobal(), camConfig, interval)
+	if queueLimits != (scheduler.QueueLimits{}) {
+		// Zero fields keep the queue default
+		b.log.Info("Camera queue limits",
+			"camera", camConfig.ID,
+			"max_files", queueLimits.MaxFiles,
+			"max_size_mb", queueLimits.MaxSizeMB,
+			"max_age", queueLimits.MaxAge,
+			"prefer_pause_over_thin", queueLimits.PreferPause)
+	}
+	// The total cap thins every queue once reached, so a larger camera limit never fills
+	if totalMB, _ := queueManagerLimits(b.configService.GetGlobal()); queueLimits.MaxSizeMB > totalMB {
+		b.log.Warn("Camera queue limit exceeds the total queue cap; queues are thinned at the cap",
+			"camera", camConfig.ID,
+			"max_size_mb", queueLimits.MaxSizeMB,
+			"max_total_size_mb", totalMB)
 	}
 
-	// Get capture interval
-	interval := camConfig.CaptureIntervalSeconds
-	if interval == 0 {
-		interval = 60
+	schedConfig := scheduler.CameraConfig{
+		RemotePath:        remotePath,
+		ImageProcessor:    imgProcessor,
+		TrimJPEG:          camConfig.TrimJPEG,
+		UploadServer:      uploadServer(camConfig.Upload),
+		RepairJPEG:        camConfig.RepairJPEG,
+		RawConverter:      rawConverter(camConfig.Raw),
+		DedupWindow:       camConfig.DedupWindow,
+		MaxUploadAttempts: camConfig.MaxUploadAttempts,
+		RemoteDedup:       time.Duration(camConfig.RemoteDedupMinutes) * time.Minute,
+		SizeBand:          sizeBand(camConfig.UploadSizeBand),
+		EventCooldown:     onvifEventCooldown(camConfig.ONVIF),
+		MinInterval:       time.Duration(camConfig.MinIntervalSeconds) * time.Second,
+		CatchupThreshold:  scheduler.CatchupThresholdForInterval(interval, camConfig.CatchupMinutes),
+		QualitySampleRate: camConfig.QualitySampleRate,
+		ExifNote:          camConfig.ExifNote,
+		JPEGComment:       camConfig.JPEGComment,
+		ExifSequence:      camConfig.ExifSequence,
+		ExifStampRetries:  camConfig.ExifStampRetries,
+		TimeSource:        timehealth.Preference(camConfig.TimeSource),
+		SettleDelay:       time.Duration(camConfig.SettleDelaySeconds) * time.Second,
+		Thumbnail:         thumbnailConfig(camConfig.Thumbnail),
+		Regions:           b.regionConfigs(camConfig),
+		Spectrogram:       spectrogramConfig(camConfig.Spectrogram),
+		OfflineImage:      b.offlineImageConfig(camConfig),
+		QuietHours:        b.uploadQuietHours(camConfig.UploadQuietHours),
+		FreshnessSLA:      time.Duration(camConfig.FreshnessSLASeconds) * time.Second,
+		LatestName:        camConfig.LatestName,
+		LiveOnly:          camConfig.LiveOnly,
+		Queue:             queueLimits,
+		Logger:            b.cameraLogger(camConfig),
+	}
+	schedConfig.FilenameTimeTokens = camConfig.FilenameTimeTokens
+	schedConfig.OutageSlowdown = outageSlowdown(camConfig.UploadOutageSlowdown)
+	schedConfig.PostCaptureHook = postCaptureHook(camConfig.PostCaptureHook)
+	if camConfig.Upload != nil {
+		schedConfig.UploadBatchSize = camConfig.Upload.BatchSize
+	}
+	if g := b.configService.GetGlobal().Global; g != nil {
+		schedConfig.CaptureTimeout = time.Duration(g.CaptureTimeoutSeconds) * time.Second
+		schedConfig.CaptureHangMargin = time.Duration(g.CaptureHangMarginSeconds) * time.Second
+		schedConfig.ThroughputHalfLife = throughputHalfLife(g)
+	}
+	if w := camConfig.Wakeup; w != nil && schedConfig.CaptureTimeout > 0 &&
+		time.Duration(w.DelayMs)*time.Millisecond >= schedConfig.CaptureTimeout {
+		b.log.Warn("Camera wakeup delay exceeds the capture timeout - every capture will time out",
+			"camera", camConfig.ID,
+			"delay_ms", w.DelayMs,
+			"capture_timeout", schedConfig.CaptureTimeout)
+	}
+	if camConfig.RTSP != nil {
+		schedConfig.SpoolThresholdBytes = int64(camConfig.RTSP.SpoolThresholdKB) * 1024
 	}
 
 	// Create uploader
 	var uploader upload.Client
 	if camConfig.Upload != nil {
 		var err error
-		uploader, err = b.createUploader(camConfig.Upload)
+		if g := b.configService.GetGlobal().Global; g != nil && g.ShareUploadConnections && b.uploadPool != nil {
+			uploader, err = b.uploadPool.ClientFromConfig(camConfig.ID, *camConfig.Upload)
+		} else {
+			uploader, err = b.createUploader(camConfig.Upload)
+		}
 		if err != nil {
 			status.LastError = fmt.Sprintf("Create uploader failed: %v", err)
 			status.ErrorCount++
@@ -452,7 +984,7 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 	}
 
 	// Add to orchestrator
-	if err := b.orchestrator.AddCamera(cam, schedConfig, interval, uploader, b.updatePreviewCache); err != nil {
+	if err := b.orchestrator.AddCamera(cam, schedConfig, interval, uploader, b.handleCapture); err != nil {
 		status.LastError = fmt.Sprintf("Add to orchestrator failed: %v", err)
 		status.ErrorCount++
 		return fmt.Errorf("add to orchestrator: %w", err)
@@ -461,6 +993,7 @@ func (b *Bridge) addCamera(camConfig config.Camera) error {
 	// Success
 	status.Running = true
 	status.LastError = ""
+	status.appliedConfig = cameraSnapshot(camConfig)
 	b.log.Info("Camera worker started successfully",
 		"camera", camConfig.ID,
 		"type", camConfig.Type,
@@ -494,12 +1027,137 @@ func (b *Bridge) getWorkerStatus(cameraID string) map[string]interface{} {
 	if !status.LastAttempt.IsZero() {
 		result["worker_last_attempt"] = status.LastAttempt.Format(time.RFC3339)
 	}
+	if status.RetryAttempts > 0 {
+		result["worker_retry_attempts"] = status.RetryAttempts
+	}
+	if !status.NextRetry.IsZero() {
+		result["worker_next_retry"] = status.NextRetry.Format(time.RFC3339)
+	}
+
+	if ts, ok := b.tunnelStatus(cameraID); ok {
+		result["tunnel"] = ts
+	}
+	if diag, ok := b.diagnostics.get(cameraID); ok {
+		result["diagnostics_status"] = diag
+	}
+
+	if b.orchestrator != nil {
+		if stats, ok := b.orchestrator.GetCaptureStats(cameraID); ok && stats.Settling {
+			result["worker_settling"] = true
+			result["worker_settle_until"] = stats.SettleUntil.Format(time.RFC3339)
+		}
+	}
 
 	return result
 }
 
+// openTunnel starts the camera's SSH tunnel and returns a copy of camConfig whose
+// snapshot or RTSP URL points at the forwarded local port
+func (b *Bridge) openTunnel(camConfig config.Camera) (*tunnel.Tunnel, config.Camera, error) {
+	tc := camConfig.Tunnel
+
+	cameraURL := camConfig.SnapshotURL
+	if camConfig.Type == "rtsp" && camConfig.RTSP != nil {
+		cameraURL = camConfig.RTSP.URL
+	}
+	target := tc.Target
+	if target == "" {
+		var err error
+		if target, err = tunnel.TargetFromURL(cameraURL); err != nil {
+			return nil, camConfig, err
+		}
+	}
+
+	var privateKey []byte
+	if tc.PrivateKeyPath != "" {
+		var err error
+		if privateKey, err = os.ReadFile(tc.PrivateKeyPath); err != nil {
+			return nil, camConfig, fmt.Errorf("read private key: %w", err)
+		}
+	}
+
+	tun, err := tunnel.New(tunnel.Config{
+		Host:                  tc.Host,
+		Port:                  tc.Port,
+		Username:              tc.Username,
+		Password:              tc.Password,
+		PrivateKey:            privateKey,
+		HostKeyFingerprint:    tc.HostKeyFingerprint,
+		InsecureIgnoreHostKey: tc.InsecureIgnoreHostKey,
+		Target:                target,
+		LocalPort:             tc.LocalPort,
+		Logger:                b.log,
+	})
+	if err != nil {
+		return nil, camConfig, err
+	}
+
+	localURL, err := tunnel.RewriteURL(cameraURL, tun.LocalAddr())
+	if err != nil {
+		_ = tun.Close()
+		return nil, camConfig, err
+	}
+	if camConfig.Type == "rtsp" && camConfig.RTSP != nil {
+		rtsp := *camConfig.RTSP
+		rtsp.URL = localURL
+		camConfig.RTSP = &rtsp
+	} else {
+		camConfig.SnapshotURL = localURL
+	}
+
+	if tc.InsecureIgnoreHostKey {
+		b.log.Warn("Tunnel host key is not verified", "camera", camConfig.ID, "ssh_host", tc.Host)
+	}
+	tun.Start()
+	return tun, camConfig, nil
+}
+
+// closeTunnel stops a camera's SSH tunnel, if it has one
+func (b *Bridge) closeTunnel(cameraID string) {
+	b.tunnelsMu.Lock()
+	tun := b.tunnels[cameraID]
+	delete(b.tunnels, cameraID)
+	b.tunnelsMu.Unlock()
+	if tun != nil {
+		_ = tun.Close() // Listener close errors are irrelevant on shutdown
+	}
+}
+
+// closeTunnels stops all SSH tunnels
+func (b *Bridge) closeTunnels() {
+	b.tunnelsMu.Lock()
+	ids := make([]string, 0, len(b.tunnels))
+	for id := range b.tunnels {
+		ids = append(ids, id)
+	}
+	b.tunnelsMu.Unlock()
+	for _, id := range ids {
+		b.closeTunnel(id)
+	}
+}
+
+// tunnelStatus returns the health of a camera's SSH tunnel
+func (b *Bridge) tunnelStatus(cameraID string) (tunnel.Status, bool) {
+	b.tunnelsMu.Lock()
+	defer b.tunnelsMu.Unlock()
+	tun, ok := b.tunnels[cameraID]
+	if !ok {
+		return tunnel.Status{}, false
+	}
+	return tun.Status(), true
+}
+
 // createCamera creates a camera instance from config
 func (b *Bridge) createCamera(camConfig config.Camera) (camera.Camera, error) {
+	if camConfig.TLS != nil && camConfig.TLS.InsecureSkipVerify {
+		b.log.Warn("Camera TLS certificate verification is disabled - the camera can be impersonated; prefer tls.pinned_sha256",
+			"camera", camConfig.ID)
+	}
+	return camera.NewCamera(cameraConfig(camConfig))
+}
+
+// cameraConfig converts a camera's config to the capture backend's
+func cameraConfig(camConfig config.Camera) camera.Config {
 	cameraConf := camera.Config{
 		ID:          camConfig.ID,
 		Type:        camConfig.Type,
@@ -522,18 +1180,88 @@ func (b *Bridge) createCamera(camConfig config.Camera) (camera.Camera, error) {
 			Password:     camConfig.ONVIF.Password,
 			ProfileToken: camConfig.ONVIF.ProfileToken,
 		}
+		if camConfig.ONVIF.Events != nil {
+			cameraConf.ONVIF.EventTopics = camConfig.ONVIF.Events.Topics
+		}
 	}
 
+	if camConfig.TLS != nil {
+		cameraConf.TLS = &camera.TLSConfig{
+			InsecureSkipVerify: camConfig.TLS.InsecureSkipVerify,
+			CAFile:             camConfig.TLS.CAFile,
+			PinnedSHA256:       camConfig.TLS.PinnedSHA256,
+		}
+	}
+
+	for _, rule := range camConfig.FailOnHeaders {
+		cameraConf.FailHeaders = append(cameraConf.FailHeaders, camera.HeaderRule{Header: rule.Header, Value: rule.Value})
+	}
+	cameraConf.ConditionalRequests = camConfig.ConditionalRequests
+	cameraConf.MaxSnapshotBytes = int64(camConfig.MaxSnapshotKB) * 1024
+	// Trimming and repair run after capture and fix the frame's tail themselves
+	cameraConf.TolerateMissingEOI = camConfig.TrimJPEG || camConfig.RepairJPEG
+	cameraConf.Raw = camConfig.Raw != nil
+
 	if camConfig.RTSP != nil {
 		cameraConf.RTSP = &camera.RTSPConfig{
 			URL:       camConfig.RTSP.URL,
 			Username:  camConfig.RTSP.Username,
 			Password:  camConfig.RTSP.Password,
 			Substream: camConfig.RTSP.Substream,
+
+			ReconnectInitial: time.Duration(camConfig.RTSP.ReconnectInitialSeconds) * time.Second,
+			ReconnectMax:     time.Duration(camConfig.RTSP.ReconnectMaxSeconds) * time.Second,
+		}
+	}
+
+	if camConfig.Folder != nil {
+		cameraConf.Folder = &camera.FolderConfig{
+			Path:             camConfig.Folder.Path,
+			DeleteAfterQueue: camConfig.Folder.DeleteAfterQueue,
 		}
 	}
 
-	return camera.NewCamera(cameraConf)
+	if w := camConfig.Wakeup; w != nil {
+		cameraConf.Wakeup = &camera.WakeupConfig{
+			URL:         w.URL,
+			Method:      w.Method,
+			Body:        w.Body,
+			ContentType: w.ContentType,
+			Delay:       time.Duration(w.DelayMs) * time.Millisecond,
+		}
+		if w.Auth != nil {
+			cameraConf.Wakeup.Auth = &camera.AuthConfig{
+				Type:     w.Auth.Type,
+				Username: w.Auth.Username,
+				Password: w.Auth.Password,
+				Token:    w.Auth.Token,
+			}
+		}
+	}
+
+	if d := camConfig.Diagnostics; d != nil {
+		cameraConf.Diagnostics = &camera.DiagnosticsConfig{URL: d.URL, Fields: d.Fields}
+		if d.Auth != nil {
+			cameraConf.Diagnostics.Auth = &camera.AuthConfig{
+				Type:     d.Auth.Type,
+				Username: d.Auth.Username,
+				Password: d.Auth.Password,
+				Token:    d.Auth.Token,
+			}
+		}
+	}
+
+	if camConfig.Agent != nil {
+		cameraConf.Agent = &camera.AgentConfig{
+			URL:      camConfig.Agent.URL,
+			CameraID: camConfig.Agent.CameraID,
+			CertFile: camConfig.Agent.CertFile,
+			KeyFile:  camConfig.Agent.KeyFile,
+			CAFile:   camConfig.Agent.CAFile,
+		}
+	}
+
+	return cameraConf
 }
 
 // createUploader creates an upload client from config
@@ -544,6 +1272,8 @@ func (b *Bridge) createUploader(uploadConfig *config.Upload) (upload.Client, err
 // handleConfigEvent handles config change events from ConfigService
 func (b *Bridge) handleConfigEvent(event config.ConfigEvent) {
 	b.log.Info("Config event received", "type", event.Type, "camera", event.CameraID)
+	b.workersMu.Lock()
+	defer b.workersMu.Unlock()
 
 	switch event.Type {
 	case "camera_added":
@@ -567,6 +1297,11 @@ func (b *Bridge) handleConfigEvent(event config.ConfigEvent) {
 			b.log.Error("Failed to get camera config", "camera", event.CameraID, "error", err)
 			return
 		}
+		b.applyCameraLogLevel(*camConfig)
+		if b.workerRunsConfig(*camConfig) {
+			b.log.Debug("Camera config unchanged, keeping worker", "camera", event.CameraID)
+			return
+		}
 
 		// Remove old worker
 		if b.orchestrator != nil {
@@ -576,6 +1311,10 @@ func (b *Bridge) handleConfigEvent(event config.ConfigEvent) {
 					"error", err)
 			}
 		}
+		b.closeTunnel(event.CameraID)
+		if b.uploadPool != nil {
+			b.uploadPool.Release(event.CameraID)
+		}
 
 		// Clean up status
 		b.workerStatusMu.Lock()
@@ -598,11 +1337,21 @@ func (b *Bridge) handleConfigEvent(event config.ConfigEvent) {
 					"error", err)
 			}
 		}
+		b.closeTunnel(event.CameraID)
+		if b.uploadPool != nil {
+			b.uploadPool.Release(event.CameraID)
+		}
+		b.logLevels.remove(event.CameraID)
 
 		// Clean up caches
 		b.captureMu.Lock()
 		delete(b.lastCaptures, event.CameraID)
 		b.captureMu.Unlock()
+		if b.frameHistory != nil {
+			if err := b.frameHistory.Remove(event.CameraID); err != nil {
+				b.log.Warn("Failed to remove frame history", "camera", event.CameraID, "error", err)
+			}
+		}
 
 		b.workerStatusMu.Lock()
 		delete(b.cameraWorkerStatus, event.CameraID)
@@ -619,17 +1368,69 @@ func (b *Bridge) handleConfigEvent(event config.ConfigEvent) {
 			b.log.Error("Failed to update timezone", "error", err)
 		}
 
+		if b.orchestrator != nil {
+			b.orchestrator.SetTimePolicy(timePolicy(global))
+			b.orchestrator.SetRegressionPolicy(timeRegressionPolicy(global))
+			b.orchestrator.SetUploadQuietHours(b.globalQuietHours(global))
+			b.orchestrator.SetUploadConnectionInterval(uploadConnectionInterval(global))
+			b.orchestrator.SetUploadBandwidthLimit(uploadBandwidthLimit(global))
+		}
+		if b.sharedFetch != nil {
+			b.sharedFetch.SetReuseWindow(sharedFetchReuse(global))
+		}
+		if b.resourceLimiter != nil {
+			b.resourceLimiter.SetGoroutineCeiling(goroutineCeiling(global))
+		}
+		applyExifToolHangPolicy(global)
+		applyExifToolStayOpen(global)
+		applyExifToolRecheck(global)
+
 		// Restart SNTP service with new config
 		if err := b.restartSNTP(global.SNTP); err != nil {
 			b.log.Error("Failed to restart SNTP", "error", err)
 		}
 
+		// Reconnect MQTT only when its settings changed
+		if b.mqttChanged(global.MQTT) {
+			if err := b.restartMQTT(global.MQTT); err != nil {
+				b.log.Error("Failed to restart MQTT", "error", err)
+			}
+		}
+		if b.statsdChanged(global.StatsD) {
+			if err := b.restartStatsD(global.StatsD); err != nil {
+				b.log.Error("Failed to restart StatsD export", "error", err)
+			}
+		}
+
 		b.log.Info("Global config updated",
 			"timezone", global.Timezone,
 			"sntp_enabled", global.SNTP != nil && global.SNTP.Enabled)
 	}
 }
 
+// cameraSnapshot serializes a camera config for change detection; nil never matches.
+// The log level is left out, as it is applied to the running worker.
+func cameraSnapshot(cam config.Camera) []byte {
+	cam.LogLevel = ""
+	data, err := json.Marshal(cam)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// workerRunsConfig reports whether the camera's worker is running with exactly this
+// config, so re-saving an unchanged camera does not restart it
+func (b *Bridge) workerRunsConfig(cam config.Camera) bool {
+	b.workerStatusMu.RLock()
+	status, ok := b.cameraWorkerStatus[cam.ID]
+	b.workerStatusMu.RUnlock()
+	if !ok || !status.Running || status.appliedConfig == nil {
+		return false
+	}
+	return bytes.Equal(status.appliedConfig, cameraSnapshot(cam))
+}
+
 // updatePreviewCache stores the last captured image for preview
 func (b *Bridge) updatePreviewCache(cameraID string, imageData []byte, captureTime time.Time) {
 	b.captureMu.Lock()
@@ -641,6 +1442,24 @@ func (b *Bridge) updatePreviewCache(cameraID string, imageData []byte, captureTi
 	b.log.Debug("Preview cache updated", "camera", cameraID, "size", len(imageData))
 }
 
+// addHistoryFrame keeps a processed frame in the camera's on-device history, if enabled
+func (b *Bridge) addHistoryFrame(cameraID string, imageData []byte, captureTime time.Time) {
+	if b.frameHistory == nil {
+		return
+	}
+	cam, err := b.configService.GetCamera(cameraID)
+	if err != nil || cam.History == nil || !cam.History.Enabled {
+		return
+	}
+	limits := history.Limits{
+		MaxFrames: cam.History.MaxFrames,
+		MaxBytes:  int64(cam.History.MaxSizeMB) * 1024 * 1024,
+	}
+	if err := b.frameHistory.Add(cameraID, imageData, captureTime, limits); err != nil {
+		b.log.Warn("Failed to save history frame", "camera", cameraID, "error", err)
+	}
+}
+
 // getCameraImage returns the cached preview image for a camera
 func (b *Bridge) getCameraImage(cameraID string) ([]byte, error) {
 	b.captureMu.RLock()
@@ -661,6 +1480,15 @@ func (b *Bridge) getCameraImage(cameraID string) ([]byte, error) {
 
 // testCamera tests a camera configuration
 func (b *Bridge) testCamera(camConfig config.Camera) ([]byte, error) {
+	if camConfig.Tunnel != nil {
+		tun, tunneled, err := b.openTunnel(camConfig)
+		if err != nil {
+			return nil, fmt.Errorf("open tunnel: %w", err)
+		}
+		defer tun.Close()
+		camConfig = tunneled
+	}
+
 	cam, err := b.createCamera(camConfig)
 	if err != nil {
 		return nil, fmt.Errorf("create camera: %w", err)
@@ -692,8 +1520,36 @@ func (b *Bridge) testUpload(uploadConfig config.Upload) error {
 	return nil
 }
 
+// testUploadRoundTrip uploads a small test image with an upload configuration,
+// proving write access, and removes it unless keep is set
+func (b *Bridge) testUploadRoundTrip(uploadConfig config.Upload, keep bool) (interface{}, error) {
+	client, err := b.createUploader(&uploadConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create uploader: %w", err)
+	}
+	tester, ok := client.(upload.RoundTripTester)
+	if !ok {
+		return nil, fmt.Errorf("upload client does not support test uploads")
+	}
+	return tester.TestRoundTrip(keep)
+}
+
+// getCameraQuality returns the rolling quality self-check series for a camera
+func (b *Bridge) getCameraQuality(cameraID string) (interface{}, bool) {
+	if b.orchestrator == nil {
+		return nil, false
+	}
+	return b.orchestrator.GetQualitySeries(cameraID)
+}
+
 // getStatus returns the current bridge status
 func (b *Bridge) getStatus() interface{} {
+	return b.getStatusPage(web.StatusQuery{})
+}
+
+// getStatusPage returns the bridge status with per-camera detail (orchestrator camera
+// stats and tunnels) limited to the cameras the query selects
+func (b *Bridge) getStatusPage(q web.StatusQuery) interface{} {
 	global := b.configService.GetGlobal()
 	cameras := b.configService.ListCameras()
 
@@ -706,23 +1562,27 @@ func (b *Bridge) getStatus() interface{} {
 
 	queuedImages := 0
 	uploadsToday := int64(0)
+	var orchStatus *scheduler.OrchestratorStatus
 	if b.orchestrator != nil {
-		orchStatus := b.orchestrator.GetStatus()
-		for _, camStatus := range orchStatus.CameraStats {
-			queuedImages += camStatus.QueueStats.ImageCount
-		}
-		uploadsToday = orchStatus.UploadStats.UploadsToday
+		s := b.orchestrator.GetStatusPage(scheduler.StatusPage{CameraID: q.CameraID, Offset: q.Offset, Limit: q.Limit})
+		orchStatus = &s
+		queuedImages = s.GlobalQueueStats.TotalImages
+		uploadsToday = s.UploadStats.UploadsToday
 	}
 
 	status := map[string]interface{}{
-		"version":        Version,
-		"commit":         GitCommit,
-		"update_channel": getUpdateChannel(global.UpdateChannel),
-		"timezone":       global.Timezone,
-		"cameras":        enabledCameras,
-		"total_cameras":  len(cameras),
-		"queued_images":  queuedImages,
-		"uploads_today":  uploadsToday,
+		"version":               Version,
+		"commit":                GitCommit,
+		"update_channel":        getUpdateChannel(global.UpdateChannel),
+		"timezone":              global.Timezone,
+		"cameras":               enabledCameras,
+		"total_cameras":         len(cameras),
+		"queued_images":         queuedImages,
+		"uploads_today":         uploadsToday,
+		"config_version":        global.Version,
+		"config_readonly":       b.configService.IsReadOnly(),
+		"config_events_dropped": b.configService.DroppedEvents(),
+		"default_password":      b.configService.UsingDefaultWebPassword(),
 	}
 
 	// Add system health if available
@@ -738,12 +1598,56 @@ func (b *Bridge) getStatus() interface{} {
 			"disk_total_mb": sysStats.DiskTotalMB,
 			"uptime":        sysStats.Uptime,
 		}
+		status["low_disk_image_active"] = b.lowDisk.reduction.Active()
 	}
 
 	// Add orchestrator status with detailed camera stats
-	if b.orchestrator != nil {
-		orchStatus := b.orchestrator.GetStatus()
-		status["orchestrator"] = orchStatus
+	if orchStatus != nil {
+		status["orchestrator"] = *orchStatus
+	}
+
+	if b.sharedFetch != nil && global.Global != nil && global.Global.SharedFetch {
+		status["shared_fetch"] = b.sharedFetch.Stats()
+	}
+	if b.uploadPool != nil && global.Global != nil && global.Global.ShareUploadConnections {
+		status["upload_accounts"] = b.uploadPool.Stats()
+	}
+	if mqttStatus, ok := b.mqttStatus(); ok {
+		status["mqtt"] = mqttStatus
+	}
+	if statsdStatus, ok := b.statsdStatus(); ok {
+		status["statsd"] = statsdStatus
+	}
+	if b.resourceLimiter != nil {
+		status["resources"] = b.resourceLimiter.GetStats()
+	}
+	status["exiftool"] = timehealth.ExifToolHangStats()
+	status["exiftool_discovery"] = timehealth.ExifToolDiscoveryStatus()
+	if stayOpen := timehealth.ExifToolStayOpenStatus(); stayOpen.Enabled {
+		status["exiftool_stay_open"] = stayOpen
+	}
+	if timelapses := b.timelapses.snapshot(); timelapses != nil {
+		status["timelapses"] = timelapses
+	}
+
+	// Add SSH tunnel health for tunneled cameras
+	var onPage map[string]bool // nil = every camera
+	if q != (web.StatusQuery{}) && orchStatus != nil {
+		onPage = make(map[string]bool, len(orchStatus.CameraStats))
+		for _, cs := range orchStatus.CameraStats {
+			onPage[cs.CameraID] = true
+		}
+	}
+	b.tunnelsMu.Lock()
+	tunnels := make(map[string]tunnel.Status, len(b.tunnels))
+	for id, tun := range b.tunnels {
+		if onPage == nil || onPage[id] {
+			tunnels[id] = tun.Status()
+		}
+	}
+	b.tunnelsMu.Unlock()
+	if len(tunnels) > 0 {
+		status["tunnels"] = tunnels
 	}
 
 	// Add time health if available
@@ -771,6 +1675,293 @@ func (b *Bridge) getStatus() interface{} {
 	return status
 }
 
+// BridgeSummary is the compact status served at /api/summary for multi-bridge dashboards
+type BridgeSummary struct {
+	BridgeID        string          `json:"bridge_id"`
+	Version         string          `json:"version"`
+	CameraCount     int             `json:"camera_count"`
+	Cameras         []CameraSummary `json:"cameras"`
+	SystemLevel     string          `json:"system_level"`
+	UpdateAvailable bool            `json:"update_available"`
+}
+
+// CameraSummary is the per-camera part of BridgeSummary
+type CameraSummary struct {
+	ID               string  `json:"id"`
+	Healthy          bool    `json:"healthy"`
+	LastUploadAgeSec *int64  `json:"last_upload_age_seconds"` // null until the first upload
+	QueuePercent     float64 `json:"queue_percent"`
+	SLABreached      *bool   `json:"sla_breached,omitempty"` // Only for cameras with a freshness SLA
+}
+
+// summaryUploadFailureLimit matches the consecutive failures at which uploads back off
+const summaryUploadFailureLimit = 3
+
+// bridgeID identifies this bridge on fleet dashboards: AVIATIONWX_BRIDGE_ID or the hostname
+func bridgeID() string {
+	if id := os.Getenv("AVIATIONWX_BRIDGE_ID"); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return host
+}
+
+// uploadServer returns the host:port a camera uploads to, or "" without upload settings
+func uploadServer(u *config.Upload) string {
+	if u == nil || u.Host == "" {
+		return ""
+	}
+	return upload.ServerAddress(*u)
+}
+
+// thumbnailConfig builds the scheduler's thumbnail rendition settings, or nil if disabled
+func thumbnailConfig(t *config.Thumbnail) *scheduler.ThumbnailConfig {
+	if t == nil {
+		return nil
+	}
+	return &scheduler.ThumbnailConfig{
+		Processor:  image.NewProcessor(t.ImageProcessing()),
+		RemotePath: t.EffectiveRemotePath(),
+	}
+}
+
+// regionConfigs builds the scheduler's region crops. Like the full image, they honor
+// the low disk reduction.
+func (b *Bridge) regionConfigs(cam config.Camera) []scheduler.RegionConfig {
+	if len(cam.Regions) == 0 {
+		return nil
+	}
+	regions := make([]scheduler.RegionConfig, 0, len(cam.Regions))
+	for _, r := range cam.Regions {
+		regions = append(regions, scheduler.RegionConfig{
+			Name:       r.Name,
+			Processor:  image.NewProcessor(r.ImageProcessing(cam.Image)).WithCrop(r.Rect).WithReduction(b.lowDisk.reduction),
+			RemotePath: r.EffectiveRemotePath(),
+		})
+	}
+	return regions
+}
+
+// spectrogramConfig builds the scheduler's spectrogram settings, or nil if disabled
+func spectrogramConfig(s *config.Spectrogram) *scheduler.SpectrogramConfig {
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	return &scheduler.SpectrogramConfig{
+		Duration:   time.Duration(s.EffectiveDurationSeconds()) * time.Second,
+		Interval:   time.Duration(s.EffectiveIntervalSeconds()) * time.Second,
+		RemotePath: s.EffectiveRemotePath(),
+		Width:      s.Width,
+		Height:     s.Height,
+	}
+}
+
+// sizeBand converts a camera's upload size band to bytes
+func sizeBand(b *config.UploadSizeBand) *scheduler.SizeBand {
+	if b == nil {
+		return nil
+	}
+	return &scheduler.SizeBand{
+		MinBytes: int64(b.MinKB) * 1024,
+		MaxBytes: int64(b.MaxKB) * 1024,
+		Keep:     b.OnReject == "keep",
+	}
+}
+
+// Upload outage slowdown defaults
+const (
+	defaultOutageSlowdownAfter      = 10 * time.Minute
+	defaultOutageSlowdownMultiplier = 4
+)
+
+// outageSlowdown returns the camera's upload outage slowdown, or nil when unset
+func outageSlowdown(s *config.UploadOutageSlowdown) *scheduler.OutageSlowdown {
+	if s == nil {
+		return nil
+	}
+	slowdown := &scheduler.OutageSlowdown{
+		After:      time.Duration(s.AfterMinutes) * time.Minute,
+		Multiplier: s.Multiplier,
+	}
+	if slowdown.After == 0 {
+		slowdown.After = defaultOutageSlowdownAfter
+	}
+	if slowdown.Multiplier == 0 {
+		slowdown.Multiplier = defaultOutageSlowdownMultiplier
+	}
+	return slowdown
+}
+
+// postCaptureHook returns the camera's post-capture hook, or nil when unset. A zero
+// timeout takes the scheduler's default.
+func postCaptureHook(h *config.PostCaptureHook) *scheduler.PostCaptureHook {
+	if h == nil {
+		return nil
+	}
+	return &scheduler.PostCaptureHook{
+		Command:     h.Command,
+		Timeout:     time.Duration(h.TimeoutSeconds) * time.Second,
+		PassThrough: h.OnFailure == "passthrough",
+	}
+}
+
+// rawConverter returns the camera's raw frame converter, or nil for JPEG cameras
+func rawConverter(raw *config.RawInput) *image.RawConverter {
+	if raw == nil {
+		return nil
+	}
+	return image.NewRawConverter(raw)
+}
+
+// offlineImageConfig loads a camera's offline image, or returns nil if none is
+// configured or it cannot be used
+func (b *Bridge) offlineImageConfig(cam config.Camera) *scheduler.OfflineImageConfig {
+	if cam.OfflineImagePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(cam.OfflineImagePath)
+	if err == nil && !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		err = fmt.Errorf("not a JPEG")
+	}
+	if err != nil {
+		b.log.Warn("Offline image not used",
+			"camera", cam.ID,
+			"path", cam.OfflineImagePath,
+			"error", err)
+		return nil
+	}
+	return &scheduler.OfflineImageConfig{
+		Data:  data,
+		After: time.Duration(cam.EffectiveOfflineAfterSeconds()) * time.Second,
+	}
+}
+
+// minCameraUpWindow is the smallest default up window, leaving fast cameras time to upload
+const minCameraUpWindow = 5 * time.Minute
+
+// cameraUpWindow returns how recent a camera's last capture and upload must be for
+// camera_up: the global setting, or three capture intervals (at least minCameraUpWindow)
+func cameraUpWindow(global config.GlobalSettings, cam config.Camera) time.Duration {
+	if global.Global != nil && global.Global.CameraUpWindowSeconds > 0 {
+		return time.Duration(global.Global.CameraUpWindowSeconds) * time.Second
+	}
+	interval := cam.EffectiveCaptureIntervalSeconds()
+	return max(3*time.Duration(interval)*time.Second, minCameraUpWindow)
+}
+
+// metricsSnapshot gathers the /metrics gauges for every enabled camera. While the
+// resource limiter sheds work, the previous snapshot is served instead.
+func (b *Bridge) metricsSnapshot() metrics.Snapshot {
+	b.metricsMu.Lock()
+	defer b.metricsMu.Unlock()
+	if b.lastMetrics != nil && b.resourceLimiter != nil && b.resourceLimiter.ShouldShed(resource.WorkMetrics) {
+		return *b.lastMetrics
+	}
+
+	snapshot := metrics.Snapshot{Version: Version, Commit: GitCommit}
+
+	var orchStatus scheduler.OrchestratorStatus
+	if b.orchestrator != nil {
+		orchStatus = b.orchestrator.GetStatus()
+	}
+	lastCapture := make(map[string]time.Time, len(orchStatus.CameraStats))
+	rates := make(map[string]*metrics.Rates)
+	for _, cs := range orchStatus.CameraStats {
+		lastCapture[cs.CameraID] = cs.LastSuccess
+		if t := cs.CaptureStats.Throughput; t != nil {
+			rates[cs.CameraID] = &metrics.Rates{
+				ConfiguredCapturesPerMin: t.ConfiguredPerMin,
+				CapturesPerMin:           t.CapturesPerMin,
+				CapturedBytesPerMin:      t.CapturedBytesPerMin,
+				UploadsPerMin:            t.UploadsPerMin,
+				UploadedBytesPerMin:      t.UploadedBytesPerMin,
+			}
+		}
+	}
+
+	global := b.configService.GetGlobal()
+	for _, cam := range b.configService.ListCameras() {
+		if !cam.Enabled {
+			continue
+		}
+		snapshot.Cameras = append(snapshot.Cameras, metrics.Camera{
+			ID:          cam.ID,
+			LastCapture: lastCapture[cam.ID],
+			LastUpload:  orchStatus.UploadStats.PerCameraSuccess[cam.ID],
+			UpWindow:    cameraUpWindow(global, cam),
+			Rates:       rates[cam.ID],
+		})
+	}
+	b.lastMetrics = &snapshot
+	return snapshot
+}
+
+// getSummary returns a curated projection of getStatus for frequent polling
+func (b *Bridge) getSummary() interface{} {
+	summary := BridgeSummary{
+		BridgeID:    bridgeID(),
+		Version:     Version,
+		Cameras:     []CameraSummary{},
+		SystemLevel: "unknown",
+	}
+
+	var orchStatus scheduler.OrchestratorStatus
+	if b.orchestrator != nil {
+		orchStatus = b.orchestrator.GetStatus()
+	}
+	running := make(map[string]scheduler.CameraStatus, len(orchStatus.CameraStats))
+	for _, cs := range orchStatus.CameraStats {
+		running[cs.CameraID] = cs
+	}
+
+	now := time.Now()
+	for _, cam := range b.configService.ListCameras() {
+		if !cam.Enabled {
+			continue
+		}
+		summary.CameraCount++
+		cs, ok := running[cam.ID]
+		summary.Cameras = append(summary.Cameras, summarizeCamera(cam.ID, cs, ok, orchStatus.UploadStats, now))
+	}
+
+	if b.systemMonitor != nil {
+		summary.SystemLevel = string(b.systemMonitor.GetStats().OverallLevel)
+	}
+	if b.updateChecker != nil {
+		summary.UpdateAvailable = b.updateChecker.Status().UpdateAvailable
+	}
+	return summary
+}
+
+// summarizeCamera reduces a camera's status to the fleet essentials. A camera is healthy
+// when its worker is running, capture is not backing off, its queue is not critical and
+// uploads are not backing off or breaching the camera's freshness SLA.
+func summarizeCamera(id string, cs scheduler.CameraStatus, running bool, uploads scheduler.UploadStats, now time.Time) CameraSummary {
+	summary := CameraSummary{ID: id}
+	if last, ok := uploads.PerCameraSuccess[id]; ok {
+		age := int64(now.Sub(last).Seconds())
+		summary.LastUploadAgeSec = &age
+	}
+	freshness, hasSLA := uploads.Freshness[id]
+	if hasSLA {
+		breached := freshness.Breached
+		summary.SLABreached = &breached
+	}
+	if !running {
+		return summary
+	}
+
+	summary.QueuePercent = cs.QueueStats.CapacityPercent
+	summary.Healthy = !cs.IsBackingOff &&
+		cs.QueueStats.HealthLevel != "critical" &&
+		uploads.PerCameraFailures[id] <= summaryUploadFailureLimit &&
+		!freshness.Breached
+	return summary
+}
+
 // getUpdateChannel normalizes the update channel value
 func getUpdateChannel(channel string) string {
 	if channel == "" || channel == "latest" {
@@ -782,3 +1973,12 @@ func getUpdateChannel(channel string) string {
 	// Default to latest for unknown values
 	return "latest"
 }
+
+// exifToolVersion reports the installed exiftool version for support bundles
+func exifToolVersion() (string, error) {
+	helper, err := timehealth.DefaultExifToolHelper()
+	if err != nil {
+		return "", err
+	}
+	return helper.GetVersion()
+}
@@ -6,9 +6,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/scheduler"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/tunnel"
 )
 
 func TestBridge_testCamera_Success(t *testing.T) {
@@ -112,3 +116,227 @@ func TestBridge_testCamera_CreateError(t *testing.T) {
 		t.Errorf("error should mention create camera: %v", err)
 	}
 }
+
+func TestBridge_openTunnel(t *testing.T) {
+	bridge := &Bridge{log: logger.Default(), tunnels: make(map[string]*tunnel.Tunnel)}
+	tc := &config.Tunnel{Host: "jump.example", Username: "u", Password: "p", InsecureIgnoreHostKey: true}
+
+	rtspCam := config.Camera{
+		ID:     "rtsp-cam",
+		Type:   "rtsp",
+		RTSP:   &config.RTSP{URL: "rtsp://10.0.0.9/stream1"},
+		Tunnel: tc,
+	}
+	tun, tunneled, err := bridge.openTunnel(rtspCam)
+	if err != nil {
+		t.Fatalf("openTunnel: %v", err)
+	}
+	defer tun.Close()
+
+	if want := "rtsp://" + tun.LocalAddr() + "/stream1"; tunneled.RTSP.URL != want {
+		t.Errorf("RTSP URL = %q, want %q", tunneled.RTSP.URL, want)
+	}
+	if rtspCam.RTSP.URL != "rtsp://10.0.0.9/stream1" {
+		t.Error("original config should not be modified")
+	}
+	if got := tun.Status().Target; got != "10.0.0.9:554" {
+		t.Errorf("Target = %q, want default RTSP port", got)
+	}
+
+	withTarget := *tc
+	withTarget.Target = "192.168.0.20:8080"
+	httpCam := config.Camera{ID: "http-cam", Type: "http", SnapshotURL: "http://cam.local/snap.jpg", Tunnel: &withTarget}
+	tun2, tunneled, err := bridge.openTunnel(httpCam)
+	if err != nil {
+		t.Fatalf("openTunnel: %v", err)
+	}
+	defer tun2.Close()
+	if want := "http://" + tun2.LocalAddr() + "/snap.jpg"; tunneled.SnapshotURL != want {
+		t.Errorf("SnapshotURL = %q, want %q", tunneled.SnapshotURL, want)
+	}
+	if got := tun2.Status().Target; got != "192.168.0.20:8080" {
+		t.Errorf("Target = %q, want explicit target", got)
+	}
+}
+
+func TestStrictStartupEnabled(t *testing.T) {
+	strictGlobal := config.GlobalSettings{Global: &config.Global{StrictStartup: true}}
+
+	tests := []struct {
+		name   string
+		env    string
+		global config.GlobalSettings
+		want   bool
+	}{
+		{"default lenient", "", config.GlobalSettings{}, false},
+		{"enabled in config", "", strictGlobal, true},
+		{"env enables", "true", config.GlobalSettings{}, true},
+		{"env overrides config", "false", strictGlobal, false},
+		{"invalid env is lenient", "yes please", strictGlobal, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AVIATIONWX_STRICT_STARTUP", tt.env)
+			if got := strictStartupEnabled(tt.global); got != tt.want {
+				t.Errorf("strictStartupEnabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigServiceOptions_FutureConfig(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    config.FutureVersionPolicy
+		wantErr bool
+	}{
+		{"", "", false},
+		{"REFUSE", config.FutureVersionRefuse, false},
+		{"read_only", config.FutureVersionReadOnly, false},
+		{"refuze", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("AVIATIONWX_FUTURE_CONFIG", tt.env)
+		opts, err := configServiceOptions()
+		if (err != nil) != tt.wantErr || (err == nil && opts.FutureVersion != tt.want) {
+			t.Errorf("%q: policy = %q, err = %v", tt.env, opts.FutureVersion, err)
+		}
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	if err := checkWritable(t.TempDir()); err != nil {
+		t.Errorf("checkWritable(tempdir) = %v, want nil", err)
+	}
+	if err := checkWritable("/nonexistent/aviationwx-test"); err == nil {
+		t.Error("checkWritable(missing dir) = nil, want error")
+	}
+}
+
+func TestSummarizeCamera(t *testing.T) {
+	now := time.Now()
+	uploads := scheduler.UploadStats{
+		PerCameraFailures: map[string]int64{"cam-a": 0, "cam-b": 5},
+		PerCameraSuccess:  map[string]time.Time{"cam-a": now.Add(-90 * time.Second)},
+	}
+	healthy := scheduler.CameraStatus{
+		CameraID:   "cam-a",
+		QueueStats: queue.QueueStats{HealthLevel: "healthy", CapacityPercent: 12.5},
+	}
+
+	got := summarizeCamera("cam-a", healthy, true, uploads, now)
+	if !got.Healthy || got.QueuePercent != 12.5 {
+		t.Errorf("cam-a summary = %+v, want healthy at 12.5%%", got)
+	}
+	if got.LastUploadAgeSec == nil || *got.LastUploadAgeSec != 90 {
+		t.Errorf("LastUploadAgeSec = %v, want 90", got.LastUploadAgeSec)
+	}
+
+	got = summarizeCamera("cam-b", scheduler.CameraStatus{CameraID: "cam-b"}, true, uploads, now)
+	if got.Healthy {
+		t.Error("camera with upload backoff should be unhealthy")
+	}
+	if got.LastUploadAgeSec != nil {
+		t.Error("camera that never uploaded should report a null age")
+	}
+
+	critical := healthy
+	critical.QueueStats.HealthLevel = "critical"
+	if got := summarizeCamera("cam-a", critical, true, uploads, now); got.Healthy {
+		t.Error("camera with a critical queue should be unhealthy")
+	}
+
+	if got := summarizeCamera("cam-c", scheduler.CameraStatus{}, false, uploads, now); got.Healthy {
+		t.Error("camera without a running worker should be unhealthy")
+	}
+	if got := summarizeCamera("cam-a", healthy, true, uploads, now); got.SLABreached != nil {
+		t.Error("camera without a freshness SLA should omit sla_breached")
+	}
+
+	uploads.Freshness = map[string]scheduler.FreshnessStatus{"cam-a": {SLASeconds: 60, AgeSeconds: 90, Breached: true}}
+	got = summarizeCamera("cam-a", healthy, true, uploads, now)
+	if got.Healthy || got.SLABreached == nil || !*got.SLABreached {
+		t.Errorf("camera breaching its SLA should be unhealthy and flagged, got %+v", got)
+	}
+}
+
+func TestBridge_getSummary(t *testing.T) {
+	t.Setenv("AVIATIONWX_BRIDGE_ID", "test-bridge")
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	svc.AddCamera(config.Camera{ID: "cam-on", Name: "On", Type: "http", Enabled: true})
+	svc.AddCamera(config.Camera{ID: "cam-off", Name: "Off", Type: "http"})
+
+	bridge := &Bridge{configService: svc, log: logger.Default()}
+	summary := bridge.getSummary().(BridgeSummary)
+
+	if summary.BridgeID != "test-bridge" || summary.CameraCount != 1 {
+		t.Errorf("summary = %+v, want test-bridge with 1 camera", summary)
+	}
+	if len(summary.Cameras) != 1 || summary.Cameras[0].ID != "cam-on" || summary.Cameras[0].Healthy {
+		t.Errorf("cameras = %+v, want unhealthy cam-on (no orchestrator)", summary.Cameras)
+	}
+}
+
+func TestBridge_workerRunsConfig(t *testing.T) {
+	cam := config.Camera{ID: "cam-1", Name: "One", Type: "http", Enabled: true, SnapshotURL: "http://10.0.0.2/snap.jpg"}
+	bridge := &Bridge{cameraWorkerStatus: map[string]*CameraWorkerStatus{
+		"cam-1": {CameraID: "cam-1", Running: true, appliedConfig: cameraSnapshot(cam)},
+	}}
+
+	if !bridge.workerRunsConfig(cam) {
+		t.Error("identical config should keep the running worker")
+	}
+	changed := cam
+	changed.Image = &config.ImageProcessing{Quality: 70}
+	if bridge.workerRunsConfig(changed) {
+		t.Error("changed config should restart the worker")
+	}
+	bridge.cameraWorkerStatus["cam-1"].Running = false
+	if bridge.workerRunsConfig(cam) {
+		t.Error("a worker that failed to start should be retried")
+	}
+}
+
+func TestCameraUpWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   int
+		interval int
+		want     time.Duration
+	}{
+		{"default interval", 0, 0, 5 * time.Minute},
+		{"three slow intervals", 0, 600, 30 * time.Minute},
+		{"configured", 120, 600, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global := config.GlobalSettings{Global: &config.Global{CameraUpWindowSeconds: tt.window}}
+			if got := cameraUpWindow(global, config.Camera{CaptureIntervalSeconds: tt.interval}); got != tt.want {
+				t.Errorf("cameraUpWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBridge_metricsSnapshot(t *testing.T) {
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	svc.AddCamera(config.Camera{ID: "cam-on", Name: "On", Type: "http", Enabled: true})
+	svc.AddCamera(config.Camera{ID: "cam-off", Name: "Off", Type: "http"})
+
+	bridge := &Bridge{configService: svc, log: logger.Default()}
+	snapshot := bridge.metricsSnapshot()
+
+	if snapshot.Version != Version || snapshot.Commit != GitCommit {
+		t.Errorf("build info = %q/%q", snapshot.Version, snapshot.Commit)
+	}
+	if len(snapshot.Cameras) != 1 || snapshot.Cameras[0].ID != "cam-on" || snapshot.Cameras[0].Up(time.Now()) {
+		t.Errorf("cameras = %+v, want down cam-on (no orchestrator)", snapshot.Cameras)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/alert"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/mqtt"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
+)
+
+// MQTT status payloads on <prefix>/status; "offline" is also the last will
+const (
+	mqttOnline  = "online"
+	mqttOffline = "offline"
+)
+
+// mqttTopicPrefix returns the configured topic prefix without a trailing slash
+func mqttTopicPrefix(m *config.MQTT) string {
+	if prefix := strings.TrimRight(m.TopicPrefix, "/"); prefix != "" {
+		return prefix
+	}
+	return "aviationwx/bridge/" + bridgeID()
+}
+
+// mqttClientConfig builds the client settings: subscribe to capture commands and keep
+// a retained online/offline status
+func mqttClientConfig(m *config.MQTT, prefix string) mqtt.Config {
+	cfg := mqtt.Config{
+		Broker:        m.Broker,
+		ClientID:      m.ClientID,
+		Username:      m.Username,
+		Password:      m.Password,
+		KeepAlive:     time.Duration(m.KeepAliveSeconds) * time.Second,
+		Will:          &mqtt.Message{Topic: prefix + "/status", Payload: []byte(mqttOffline), Retain: true},
+		Birth:         &mqtt.Message{Topic: prefix + "/status", Payload: []byte(mqttOnline), Retain: true},
+		Subscriptions: []string{prefix + "/camera/+/capture"},
+	}
+	if cfg.ClientID == "" {
+		cfg.ClientID = "aviationwx-bridge-" + bridgeID()
+	}
+	if m.TLS {
+		host, _, _ := net.SplitHostPort(m.Broker)
+		cfg.TLS = &tls.Config{ServerName: host, InsecureSkipVerify: m.TLSInsecureSkipVerify}
+	}
+	return cfg
+}
+
+// restartMQTT replaces the MQTT client with one for the given settings, or just stops
+// it when MQTT is disabled
+func (b *Bridge) restartMQTT(m *config.MQTT) error {
+	b.mqttMu.Lock()
+	old := b.mqtt
+	b.mqtt, b.mqttPrefix, b.mqttSettings = nil, "", config.MQTT{}
+	b.mqttMu.Unlock()
+	if old != nil {
+		old.Stop()
+		b.log.Info("Stopped MQTT client")
+	}
+
+	if m == nil || !m.Enabled {
+		return nil
+	}
+	prefix := mqttTopicPrefix(m)
+	cfg := mqttClientConfig(m, prefix)
+	cfg.OnMessage = func(topic string, _ []byte) { b.handleMQTTCommand(prefix, topic) }
+	cfg.Logger = b.log
+	client, err := mqtt.New(cfg)
+	if err != nil {
+		return fmt.Errorf("create mqtt client: %w", err)
+	}
+	client.Start()
+
+	b.mqttMu.Lock()
+	b.mqtt, b.mqttPrefix, b.mqttSettings = client, prefix, *m
+	b.mqttMu.Unlock()
+	b.log.Info("MQTT client started", "broker", m.Broker, "topic_prefix", prefix)
+	return nil
+}
+
+// mqttChanged reports whether m differs from the settings the client is running with
+func (b *Bridge) mqttChanged(m *config.MQTT) bool {
+	var settings config.MQTT
+	if m != nil && m.Enabled {
+		settings = *m
+	}
+	b.mqttMu.Lock()
+	defer b.mqttMu.Unlock()
+	return settings != b.mqttSettings
+}
+
+// stopMQTT disconnects from the broker, publishing the offline status
+func (b *Bridge) stopMQTT() {
+	_ = b.restartMQTT(nil)
+}
+
+// handleMQTTCommand triggers a capture for <prefix>/camera/<id>/capture; the frame is
+// queued and uploaded like any other capture
+func (b *Bridge) handleMQTTCommand(prefix, topic string) {
+	cameraID, ok := strings.CutPrefix(topic, prefix+"/camera/")
+	if ok {
+		cameraID, ok = strings.CutSuffix(cameraID, "/capture")
+	}
+	if !ok || cameraID == "" || strings.Contains(cameraID, "/") {
+		b.log.Warn("Ignoring unexpected MQTT command", "topic", topic)
+		return
+	}
+	if b.orchestrator == nil {
+		b.log.Warn("MQTT capture command ignored - cameras disabled", "camera", cameraID)
+		return
+	}
+	if err := b.orchestrator.TriggerCapture(cameraID, "mqtt"); err != nil {
+		b.log.Warn("MQTT capture command failed", "camera", cameraID, "error", err)
+		return
+	}
+	b.log.Info("MQTT capture requested", "camera", cameraID)
+}
+
+// publishMQTTEvent publishes an event as JSON to <prefix>/camera/<id>/events. Events
+// are dropped while MQTT is disabled or disconnected.
+func (b *Bridge) publishMQTTEvent(event alert.Event) {
+	b.mqttMu.Lock()
+	client, prefix := b.mqtt, b.mqttPrefix
+	b.mqttMu.Unlock()
+	if client == nil {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_ = client.Publish(prefix+"/camera/"+event.CameraID+"/events", payload, false)
+}
+
+// mqttStatus returns the broker connection status, if MQTT is enabled
+func (b *Bridge) mqttStatus() (mqtt.Status, bool) {
+	b.mqttMu.Lock()
+	client := b.mqtt
+	b.mqttMu.Unlock()
+	if client == nil {
+		return mqtt.Status{}, false
+	}
+	return client.Status(), true
+}
+
+// handleCapture updates the preview cache (unless shedding work) and frame history,
+// and announces the capture over MQTT
+func (b *Bridge) handleCapture(cameraID string, imageData []byte, captureTime time.Time) {
+	if b.resourceLimiter == nil || !b.resourceLimiter.ShouldShed(resource.WorkPreview) {
+		b.updatePreviewCache(cameraID, imageData, captureTime)
+	}
+	b.addHistoryFrame(cameraID, imageData, captureTime)
+	b.publishMQTTEvent(alert.Event{
+		Type:     "capture_done",
+		BridgeID: bridgeID(),
+		CameraID: cameraID,
+		Message:  fmt.Sprintf("camera %s captured an image", cameraID),
+		Time:     time.Now(),
+		Details: map[string]interface{}{
+			"capture_time": captureTime.UTC().Format(time.RFC3339),
+			"bytes":        len(imageData),
+		},
+	})
+}
+
+// handleUploadFailure announces a failed upload over MQTT
+func (b *Bridge) handleUploadFailure(cameraID string, err error) {
+	b.publishMQTTEvent(alert.Event{
+		Type:     "upload_failed",
+		BridgeID: bridgeID(),
+		CameraID: cameraID,
+		Message:  fmt.Sprintf("camera %s upload failed: %v", cameraID, err),
+		Time:     time.Now(),
+	})
+}
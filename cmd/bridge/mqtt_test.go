@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+func TestMQTTClientConfig(t *testing.T) {
+	t.Setenv("AVIATIONWX_BRIDGE_ID", "kspb")
+
+	m := &config.MQTT{Enabled: true, Broker: "mqtt.local:8883", TLS: true, TopicPrefix: "site/kspb/"}
+	prefix := mqttTopicPrefix(m)
+	if prefix != "site/kspb" {
+		t.Errorf("prefix = %q, want trailing slash trimmed", prefix)
+	}
+	cfg := mqttClientConfig(m, prefix)
+	if cfg.ClientID != "aviationwx-bridge-kspb" {
+		t.Errorf("client id = %q", cfg.ClientID)
+	}
+	if len(cfg.Subscriptions) != 1 || cfg.Subscriptions[0] != "site/kspb/camera/+/capture" {
+		t.Errorf("subscriptions = %v", cfg.Subscriptions)
+	}
+	if cfg.Will.Topic != "site/kspb/status" || string(cfg.Will.Payload) != mqttOffline || !cfg.Will.Retain {
+		t.Errorf("will = %+v", cfg.Will)
+	}
+	if cfg.TLS == nil || cfg.TLS.ServerName != "mqtt.local" {
+		t.Errorf("tls = %+v, want server name from broker", cfg.TLS)
+	}
+
+	if got := mqttTopicPrefix(&config.MQTT{}); got != "aviationwx/bridge/kspb" {
+		t.Errorf("default prefix = %q", got)
+	}
+}
+
+func TestBridge_mqttChanged(t *testing.T) {
+	b := &Bridge{}
+	if b.mqttChanged(nil) || b.mqttChanged(&config.MQTT{Broker: "mqtt.local:1883"}) {
+		t.Error("disabled settings should not count as a change while MQTT is off")
+	}
+	enabled := &config.MQTT{Enabled: true, Broker: "mqtt.local:1883"}
+	if !b.mqttChanged(enabled) {
+		t.Error("enabling MQTT should count as a change")
+	}
+	b.mqttSettings = *enabled
+	if b.mqttChanged(&config.MQTT{Enabled: true, Broker: "mqtt.local:1883"}) {
+		t.Error("identical settings should not restart the client")
+	}
+	if !b.mqttChanged(&config.MQTT{Enabled: true, Broker: "mqtt.local:1883", TopicPrefix: "x"}) {
+		t.Error("a new topic prefix should restart the client")
+	}
+}
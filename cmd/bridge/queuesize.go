@@ -0,0 +1,106 @@
+package main
+
+import (
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/scheduler"
+)
+
+// Queue auto-sizing from survival_minutes
+const (
+	defaultFrameSizeKB = 300
+	minAutoQueueFiles  = 10
+	maxAutoQueueFiles  = 10000
+
+	// queueHeadroom keeps a full outage's frames below the degraded threshold (80%),
+	// where thinning starts
+	queueHeadroom = 1.25
+)
+
+// Queue manager defaults, for queue.max_total_size_mb and queue.max_heap_mb
+const (
+	defaultQueueMaxTotalMB = 100
+	defaultQueueMaxHeapMB  = 400
+)
+
+// queueManagerLimits returns the total queue size cap (all cameras) and the heap
+// limit, in MB
+func queueManagerLimits(global config.GlobalSettings) (totalMB, heapMB int) {
+	totalMB, heapMB = defaultQueueMaxTotalMB, defaultQueueMaxHeapMB
+	if global.Queue != nil {
+		if global.Queue.MaxTotalSizeMB > 0 {
+			totalMB = global.Queue.MaxTotalSizeMB
+		}
+		if global.Queue.MaxHeapMB > 0 {
+			heapMB = global.Queue.MaxHeapMB
+		}
+	}
+	return totalMB, heapMB
+}
+
+// cameraQueueLimits returns a camera's queue limits. Explicit max_files,
+// max_size_mb and max_age_seconds (the camera's, then queue.defaults) win; unset
+// limits are sized from survival_minutes (likewise) and the capture interval, or
+// left to the queue defaults. prefer_pause_over_thin is on if either sets it.
+func cameraQueueLimits(global config.GlobalSettings, cam config.Camera, intervalSecs int) scheduler.QueueLimits {
+	var defaults config.QueueCamera
+	if global.Queue != nil && global.Queue.Defaults != nil {
+		defaults = *global.Queue.Defaults
+	}
+	var own config.QueueCamera
+	if cam.Queue != nil {
+		own = *cam.Queue
+	}
+	pick := func(own, fallback int) int {
+		if own > 0 {
+			return own
+		}
+		return max(fallback, 0)
+	}
+
+	limits := autoQueueLimits(pick(own.SurvivalMinutes, defaults.SurvivalMinutes),
+		pick(own.FrameSizeKB, defaults.FrameSizeKB), intervalSecs)
+	if files := pick(own.MaxFiles, defaults.MaxFiles); files > 0 {
+		limits.MaxFiles = files
+	}
+	if size := pick(own.MaxSizeMB, defaults.MaxSizeMB); size > 0 {
+		limits.MaxSizeMB = size
+	}
+	if age := pick(own.MaxAgeSeconds, defaults.MaxAgeSeconds); age > 0 {
+		limits.MaxAge = time.Duration(age) * time.Second
+	}
+
+	// Thresholds are fractions, picked the same way
+	pickFraction := func(own, fallback float64) float64 {
+		if own > 0 {
+			return own
+		}
+		return max(fallback, 0)
+	}
+	limits.PreferPause = own.PreferPauseOverThin || defaults.PreferPauseOverThin
+	if limits.PreferPause {
+		limits.PauseAt = pickFraction(own.PauseThreshold, defaults.PauseThreshold)
+	}
+	limits.ResumeAt = pickFraction(own.ResumeThreshold, defaults.ResumeThreshold)
+	return limits
+}
+
+// autoQueueLimits sizes a queue to hold survivalMinutes of frames captured every
+// intervalSecs, with headroom. The age limit is never below the queue default (1h).
+func autoQueueLimits(survivalMinutes, frameSizeKB, intervalSecs int) scheduler.QueueLimits {
+	if survivalMinutes <= 0 || intervalSecs <= 0 {
+		return scheduler.QueueLimits{}
+	}
+	if frameSizeKB <= 0 {
+		frameSizeKB = defaultFrameSizeKB
+	}
+	survival := time.Duration(survivalMinutes) * time.Minute
+	frames := (int(survival/time.Second) + intervalSecs - 1) / intervalSecs
+	files := min(max(int(float64(frames)*queueHeadroom+0.5), minAutoQueueFiles), maxAutoQueueFiles)
+	return scheduler.QueueLimits{
+		MaxFiles:  files,
+		MaxSizeMB: (files*frameSizeKB + 1023) / 1024,
+		MaxAge:    max(time.Duration(float64(survival)*queueHeadroom), time.Hour),
+	}
+}
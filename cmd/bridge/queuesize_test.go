@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/scheduler"
+)
+
+func TestCameraQueueLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *config.QueueCamera
+		cam      *config.QueueCamera
+		interval int
+		want     scheduler.QueueLimits
+	}{
+		{name: "nothing set", interval: 60},
+		{
+			// 2h at 60s = 120 frames, +25% = 150 files of 300 KB
+			name:     "survival from defaults",
+			defaults: &config.QueueCamera{SurvivalMinutes: 120},
+			interval: 60,
+			want:     scheduler.QueueLimits{MaxFiles: 150, MaxSizeMB: 44, MaxAge: 150 * time.Minute},
+		},
+		{
+			name:     "camera survival and frame size",
+			defaults: &config.QueueCamera{SurvivalMinutes: 120},
+			cam:      &config.QueueCamera{SurvivalMinutes: 10, FrameSizeKB: 1024},
+			interval: 30,
+			want:     scheduler.QueueLimits{MaxFiles: 25, MaxSizeMB: 25, MaxAge: time.Hour},
+		},
+		{
+			name:     "explicit limits override",
+			defaults: &config.QueueCamera{SurvivalMinutes: 120, MaxSizeMB: 20},
+			cam:      &config.QueueCamera{MaxFiles: 500},
+			interval: 60,
+			want:     scheduler.QueueLimits{MaxFiles: 500, MaxSizeMB: 20, MaxAge: 150 * time.Minute},
+		},
+		{
+			name:     "clamped to max files",
+			cam:      &config.QueueCamera{SurvivalMinutes: 7 * 24 * 60},
+			interval: 1,
+			want:     scheduler.QueueLimits{MaxFiles: 10000, MaxSizeMB: 2930, MaxAge: 210 * time.Hour},
+		},
+		{
+			name:     "explicit only",
+			cam:      &config.QueueCamera{MaxAgeSeconds: 600},
+			interval: 60,
+			want:     scheduler.QueueLimits{MaxAge: 10 * time.Minute},
+		},
+		{
+			name:     "prefer pause from defaults",
+			defaults: &config.QueueCamera{PreferPauseOverThin: true, PauseThreshold: 0.7, ResumeThreshold: 0.4},
+			cam:      &config.QueueCamera{ResumeThreshold: 0.5},
+			interval: 60,
+			want:     scheduler.QueueLimits{PreferPause: true, PauseAt: 0.7, ResumeAt: 0.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			global := config.GlobalSettings{Queue: &config.QueueGlobal{Defaults: tt.defaults}}
+			got := cameraQueueLimits(global, config.Camera{Queue: tt.cam}, tt.interval)
+			if got != tt.want {
+				t.Errorf("cameraQueueLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueManagerLimits(t *testing.T) {
+	if total, heap := queueManagerLimits(config.GlobalSettings{}); total != 100 || heap != 400 {
+		t.Errorf("defaults = %d/%d MB, want 100/400", total, heap)
+	}
+	global := config.GlobalSettings{Queue: &config.QueueGlobal{MaxTotalSizeMB: 3000, MaxHeapMB: 200}}
+	if total, heap := queueManagerLimits(global); total != 3000 || heap != 200 {
+		t.Errorf("configured = %d/%d MB, want 3000/200", total, heap)
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	timehealth "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// shutdownSnapshotFile is written to the config directory when shutdown_snapshot is enabled
+const shutdownSnapshotFile = "last_shutdown.json"
+
+// webStopTimeout bounds the web server's graceful shutdown
+const webStopTimeout = 10 * time.Second
+
+// ShutdownSummary is the concise record of a run, logged on every shutdown
+type ShutdownSummary struct {
+	StoppedAt      time.Time `json:"stopped_at"`
+	UptimeSeconds  int64     `json:"uptime_seconds"`
+	UploadsSuccess int64     `json:"uploads_success"` // This session
+	UploadsFailed  int64     `json:"uploads_failed"`
+	FramesDropped  int64     `json:"frames_dropped"` // Queued frames thinned, expired or abandoned
+	CameraCount    int       `json:"camera_count"`
+	CamerasHealthy int       `json:"cameras_healthy"`
+	StepErrors     []string  `json:"step_errors,omitempty"` // Shutdown steps that failed
+}
+
+// shutdownStep is one stage of the graceful shutdown, run in order
+type shutdownStep struct {
+	name string
+	run  func() error
+}
+
+// shutdown stops services in order, then flushes the final summary and snapshot. A
+// failing or panicking step is logged and skipped, so the flush always runs.
+func (b *Bridge) shutdown() {
+	steps := []shutdownStep{
+		{"update checker", func() error {
+			if b.updateChecker != nil {
+				b.updateChecker.Stop()
+			}
+			return nil
+		}},
+		{"disk watcher", func() error {
+			if b.diskWatchStop != nil {
+				close(b.diskWatchStop)
+			}
+			return nil
+		}},
+		{"worker retry", func() error {
+			if b.retryStop != nil {
+				close(b.retryStop)
+			}
+			return nil
+		}},
+		{"diagnostics poller", func() error {
+			if b.diagnosticsStop != nil {
+				close(b.diagnosticsStop)
+			}
+			return nil
+		}},
+		{"timelapse builder", func() error {
+			if b.timelapseStop != nil {
+				close(b.timelapseStop)
+			}
+			return nil
+		}},
+		{"orchestrator", func() error {
+			if b.orchestrator != nil {
+				b.orchestrator.Stop()
+			}
+			return nil
+		}},
+		{"exiftool", func() error {
+			timehealth.StopExifToolStayOpen()
+			return nil
+		}},
+		{"upload connections", func() error {
+			if b.uploadPool != nil {
+				b.uploadPool.Close()
+			}
+			return nil
+		}},
+		{"mqtt", func() error {
+			b.stopMQTT()
+			return nil
+		}},
+		{"statsd", func() error {
+			b.stopStatsD()
+			return nil
+		}},
+		{"tunnels", func() error {
+			b.closeTunnels()
+			return nil
+		}},
+		{"web server", func() error {
+			if b.webServer == nil {
+				return nil
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), webStopTimeout)
+			defer cancel()
+			return b.webServer.Stop(ctx)
+		}},
+	}
+
+	var stepErrors []string
+	for _, step := range steps {
+		if err := b.runShutdownStep(step); err != nil {
+			b.log.Error("Shutdown step failed", "step", step.name, "error", err)
+			stepErrors = append(stepErrors, fmt.Sprintf("%s: %v", step.name, err))
+		}
+	}
+
+	b.flushShutdownState(stepErrors)
+}
+
+// runShutdownStep runs a step, converting a panic into an error
+func (b *Bridge) runShutdownStep(step shutdownStep) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("Shutdown step panicked", "step", step.name, "panic", r, "stack", string(debug.Stack()))
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return step.run()
+}
+
+// flushShutdownState logs the shutdown summary and, when shutdown_snapshot is enabled,
+// writes it with the last status to the config directory
+func (b *Bridge) flushShutdownState(stepErrors []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("Shutdown flush panicked", "panic", r)
+		}
+	}()
+
+	summary := b.shutdownSummary(stepErrors)
+	b.log.Info("Shutdown summary",
+		"uptime_seconds", summary.UptimeSeconds,
+		"uploads_success", summary.UploadsSuccess,
+		"uploads_failed", summary.UploadsFailed,
+		"frames_dropped", summary.FramesDropped,
+		"cameras_healthy", fmt.Sprintf("%d/%d", summary.CamerasHealthy, summary.CameraCount),
+		"step_errors", len(summary.StepErrors))
+
+	global := b.configService.GetGlobal()
+	if global.Global == nil || !global.Global.ShutdownSnapshot || b.configDir == "" {
+		return
+	}
+	path := filepath.Join(b.configDir, shutdownSnapshotFile)
+	if err := writeShutdownSnapshot(path, summary, b.getStatus()); err != nil {
+		b.log.Error("Could not write shutdown snapshot", "path", path, "error", err)
+		return
+	}
+	b.log.Info("Shutdown snapshot written", "path", path)
+}
+
+// shutdownSummary gathers session totals from the (stopped) orchestrator
+func (b *Bridge) shutdownSummary(stepErrors []string) ShutdownSummary {
+	summary := ShutdownSummary{StoppedAt: time.Now().UTC(), StepErrors: stepErrors}
+
+	if b.orchestrator != nil {
+		status := b.orchestrator.GetStatus()
+		summary.UptimeSeconds = int64(status.Uptime.Seconds())
+		summary.UploadsSuccess = status.UploadStats.UploadsSuccess
+		summary.UploadsFailed = status.UploadStats.UploadsFailed
+		for _, cs := range status.CameraStats {
+			q := cs.QueueStats
+			summary.FramesDropped += q.ImagesThinned + q.ImagesExpired + q.ImagesAbandoned
+		}
+	}
+
+	bridgeSummary := b.getSummary().(BridgeSummary)
+	summary.CameraCount = bridgeSummary.CameraCount
+	for _, cam := range bridgeSummary.Cameras {
+		if cam.Healthy {
+			summary.CamerasHealthy++
+		}
+	}
+	return summary
+}
+
+// writeShutdownSnapshot writes the summary and status atomically (tmp + rename)
+func writeShutdownSnapshot(path string, summary ShutdownSummary, status interface{}) error {
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"summary": summary,
+		"status":  status,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal snapshot: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath) // Best-effort cleanup
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+)
+
+func TestBridge_runShutdownStep(t *testing.T) {
+	bridge := &Bridge{log: logger.Default()}
+
+	err := bridge.runShutdownStep(shutdownStep{"explodes", func() error { panic("boom") }})
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("panicking step: got %v, want error containing boom", err)
+	}
+	if err := bridge.runShutdownStep(shutdownStep{"ok", func() error { return nil }}); err != nil {
+		t.Errorf("ok step: got %v", err)
+	}
+}
+
+func TestBridge_shutdownSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := config.NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	svc.AddCamera(config.Camera{ID: "cam-on", Name: "On", Type: "http", Enabled: true})
+
+	bridge := &Bridge{
+		configService:      svc,
+		log:                logger.Default(),
+		configDir:          dir,
+		cameraWorkerStatus: make(map[string]*CameraWorkerStatus),
+	}
+	path := filepath.Join(dir, shutdownSnapshotFile)
+
+	// Disabled by default: summary is logged only
+	bridge.shutdown()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("snapshot written while disabled: %v", err)
+	}
+
+	if err := svc.UpdateGlobal(func(g *config.GlobalSettings) error {
+		g.Global = &config.Global{ShutdownSnapshot: true}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+	bridge.flushShutdownState([]string{"web server: timeout"})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	var snapshot struct {
+		Summary ShutdownSummary        `json:"summary"`
+		Status  map[string]interface{} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("decode snapshot: %v", err)
+	}
+	if snapshot.Summary.CameraCount != 1 || snapshot.Summary.CamerasHealthy != 0 || snapshot.Summary.StoppedAt.IsZero() {
+		t.Errorf("unexpected summary: %+v", snapshot.Summary)
+	}
+	if len(snapshot.Summary.StepErrors) != 1 || snapshot.Status == nil {
+		t.Errorf("snapshot missing step errors or status: %+v", snapshot)
+	}
+}
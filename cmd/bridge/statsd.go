@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/statsd"
+)
+
+// restartStatsD replaces the StatsD client with one for the given settings, or just
+// stops it when StatsD is disabled
+func (b *Bridge) restartStatsD(s *config.StatsD) error {
+	b.statsdMu.Lock()
+	old := b.statsd
+	b.statsd, b.statsdSettings = nil, config.StatsD{}
+	b.statsdMu.Unlock()
+	if old != nil {
+		old.Stop()
+		b.log.Info("Stopped StatsD export")
+	}
+
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	client, err := statsd.New(statsd.Config{
+		Address:       s.Address,
+		Prefix:        s.Prefix,
+		FlushInterval: time.Duration(s.FlushIntervalSeconds) * time.Second,
+		Sample:        b.statsdSample,
+		Logger:        b.log,
+	})
+	if err != nil {
+		return fmt.Errorf("create statsd client: %w", err)
+	}
+	client.Start()
+
+	b.statsdMu.Lock()
+	b.statsd, b.statsdSettings = client, *s
+	b.statsdMu.Unlock()
+	b.log.Info("StatsD export started", "address", s.Address)
+	return nil
+}
+
+// statsdChanged reports whether s differs from the settings the client is running with
+func (b *Bridge) statsdChanged(s *config.StatsD) bool {
+	var settings config.StatsD
+	if s != nil && s.Enabled {
+		settings = *s
+	}
+	b.statsdMu.Lock()
+	defer b.statsdMu.Unlock()
+	return settings != b.statsdSettings
+}
+
+// stopStatsD sends a final flush and stops the export
+func (b *Bridge) stopStatsD() {
+	_ = b.restartStatsD(nil)
+}
+
+// statsdStatus returns delivery counters, if StatsD is enabled
+func (b *Bridge) statsdStatus() (statsd.Status, bool) {
+	b.statsdMu.Lock()
+	client := b.statsd
+	b.statsdMu.Unlock()
+	if client == nil {
+		return statsd.Status{}, false
+	}
+	return client.Status(), true
+}
+
+// statsdSample reads the core metrics from the orchestrator's stats: bridge-wide
+// upload and queue totals, and per-camera capture, queue and upload figures under
+// camera.<id>.
+func (b *Bridge) statsdSample() statsd.Sample {
+	sample := statsd.Sample{Counters: map[string]int64{}, Gauges: map[string]float64{}}
+	if b.orchestrator == nil {
+		return sample
+	}
+	status := b.orchestrator.GetStatus()
+
+	up := status.UploadStats
+	sample.Counters["uploads.success"] = up.UploadsSuccess
+	sample.Counters["uploads.failed"] = up.UploadsFailed
+	sample.Counters["uploads.retried"] = up.UploadsRetried
+	sample.Counters["uploads.abandoned"] = up.UploadsAbandoned
+	sample.Counters["uploads.auth_failures"] = up.AuthFailures
+	sample.Gauges["uploads.active"] = float64(up.ActiveUploads)
+	sample.Gauges["uploads.last_ms"] = up.LastUploadMs
+	sample.Gauges["queue.images"] = float64(status.GlobalQueueStats.TotalImages)
+	sample.Gauges["queue.size_mb"] = status.GlobalQueueStats.TotalSizeMB
+
+	for _, cs := range status.CameraStats {
+		prefix := "camera." + statsd.Name(cs.CameraID) + "."
+		sample.Counters[prefix+"captures"] = cs.CaptureStats.CapturesTotal
+		sample.Counters[prefix+"captures_failed"] = cs.CaptureStats.CapturesFailed
+		sample.Counters[prefix+"uploads_failed"] = up.PerCameraFailures[cs.CameraID]
+		sample.Gauges[prefix+"queue.images"] = float64(cs.QueueStats.ImageCount)
+		sample.Gauges[prefix+"queue.size_mb"] = cs.QueueStats.TotalSizeMB
+		if t := cs.CaptureStats.LastTiming; t != nil {
+			sample.Gauges[prefix+"capture.last_ms"] = t.TotalMs
+		}
+	}
+	return sample
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/history"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/timelapse"
+)
+
+// timelapseCheckInterval is how often cameras are checked for a due daily timelapse
+const timelapseCheckInterval = time.Minute
+
+// timelapseDayFormat names a timelapse after the local day it covers
+const timelapseDayFormat = "2006-01-02"
+
+// TimelapseStatus is the outcome of a camera's last daily timelapse
+type TimelapseStatus struct {
+	Day        string    `json:"day"`
+	Frames     int       `json:"frames"`
+	Skipped    int       `json:"skipped"`
+	Bytes      int       `json:"bytes"`
+	RemotePath string    `json:"remote_path,omitempty"`
+	BuiltAt    time.Time `json:"built_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// timelapseBuilds tracks which day each camera's timelapse was last built for, so
+// each day is attempted once. Kept in memory: a restart during the build hour
+// builds and uploads the same day again, replacing the earlier file.
+type timelapseBuilds struct {
+	mu     sync.Mutex
+	status map[string]TimelapseStatus
+}
+
+// watchTimelapses builds due daily timelapses until stop is closed, which also
+// cancels a build in progress
+func (b *Bridge) watchTimelapses(stop <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(timelapseCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.checkTimelapses(ctx, time.Now())
+		}
+	}
+}
+
+// checkTimelapses builds the previous local day's timelapse for each camera whose
+// build hour it is and that has none yet. Builds wait while the bridge is under
+// resource pressure; a day whose build hour passes under pressure is skipped.
+func (b *Bridge) checkTimelapses(ctx context.Context, now time.Time) {
+	if b.frameHistory == nil {
+		return
+	}
+	local := now.In(b.timelapseLocation())
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	start := today.AddDate(0, 0, -1)
+	day := start.Format(timelapseDayFormat)
+
+	for _, cam := range b.configService.ListCameras() {
+		t := cam.Timelapse
+		if !cam.Enabled || t == nil || !t.Enabled || cam.History == nil || !cam.History.Enabled {
+			continue
+		}
+		if local.Hour() != t.EffectiveHour() || b.timelapses.built(cam.ID) == day {
+			continue
+		}
+		if b.resourceLimiter != nil && b.resourceLimiter.IsUnderPressure() {
+			b.log.Debug("Deferring timelapse while under resource pressure", "camera", cam.ID, "day", day)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		status := b.buildTimelapse(ctx, cam, start, today)
+		b.timelapses.record(cam.ID, status)
+		if status.Error != "" {
+			b.log.Warn("Daily timelapse failed", "camera", cam.ID, "day", day, "error", status.Error)
+			continue
+		}
+		b.log.Info("Daily timelapse uploaded",
+			"camera", cam.ID,
+			"day", day,
+			"frames", status.Frames,
+			"skipped", status.Skipped,
+			"size", status.Bytes,
+			"remote_path", status.RemotePath)
+	}
+}
+
+// buildTimelapse renders the camera's history frames in [start, end) and uploads
+// the result as <remote_path>/<day>.<format>
+func (b *Bridge) buildTimelapse(ctx context.Context, cam config.Camera, start, end time.Time) TimelapseStatus {
+	t := cam.Timelapse
+	status := TimelapseStatus{Day: start.Format(timelapseDayFormat), BuiltAt: time.Now()}
+	fail := func(err error) TimelapseStatus {
+		status.Error = err.Error()
+		return status
+	}
+
+	retained, err := b.frameHistory.List(cam.ID)
+	if err != nil {
+		return fail(fmt.Errorf("list history: %w", err))
+	}
+	frames := timelapseFrames(retained, start, end, func(ts int64) ([]byte, error) {
+		return b.frameHistory.Get(cam.ID, ts)
+	})
+	if len(frames) == 0 {
+		return fail(fmt.Errorf("no history frames for the day"))
+	}
+
+	opts := timelapse.Options{Format: t.Format, FPS: t.FPS, MaxWidth: t.MaxWidth, MaxFrames: t.MaxFrames}
+	var slots timelapse.Slots
+	if b.resourceLimiter != nil {
+		slots = b.resourceLimiter
+	}
+	result, err := timelapse.Render(ctx, frames, opts, slots)
+	if err != nil {
+		return fail(err)
+	}
+	status.Frames, status.Skipped, status.Bytes = result.Frames, result.Skipped, len(result.Data)
+
+	if cam.Upload == nil {
+		return fail(fmt.Errorf("no upload configured"))
+	}
+	uploader, err := b.createUploader(cam.Upload)
+	if err != nil {
+		return fail(fmt.Errorf("create uploader: %w", err))
+	}
+	format := t.Format
+	if format == "" {
+		format = timelapse.FormatMP4
+	}
+	status.RemotePath = path.Join(strings.TrimSuffix(t.EffectiveRemotePath(), "/"), status.Day+"."+format)
+	if err := uploader.Upload(status.RemotePath, result.Data); err != nil {
+		return fail(fmt.Errorf("upload: %w", err))
+	}
+	return status
+}
+
+// timelapseFrames returns the retained frames (newest first) captured in
+// [start, end), oldest first
+func timelapseFrames(retained []history.Frame, start, end time.Time, load func(ts int64) ([]byte, error)) []timelapse.Frame {
+	var frames []timelapse.Frame
+	for i := len(retained) - 1; i >= 0; i-- {
+		f := retained[i]
+		if f.Time.Before(start) || !f.Time.Before(end) {
+			continue
+		}
+		ts := f.Timestamp
+		frames = append(frames, timelapse.Frame{Time: f.Time, Load: func() ([]byte, error) { return load(ts) }})
+	}
+	return frames
+}
+
+// timelapseLocation returns the bridge's configured timezone, which defines the day
+func (b *Bridge) timelapseLocation() *time.Location {
+	if tz := b.configService.GetGlobal().Timezone; tz != "" {
+		if loc, err := time.LoadLocation(tz); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// built returns the day the camera's timelapse was last attempted for
+func (s *timelapseBuilds) built(cameraID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status[cameraID].Day
+}
+
+// record stores the outcome of a build, successful or not
+func (s *timelapseBuilds) record(cameraID string, status TimelapseStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status == nil {
+		s.status = make(map[string]TimelapseStatus)
+	}
+	s.status[cameraID] = status
+}
+
+// snapshot returns the last build outcome per camera, or nil before any build
+func (s *timelapseBuilds) snapshot() map[string]TimelapseStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.status) == 0 {
+		return nil
+	}
+	result := make(map[string]TimelapseStatus, len(s.status))
+	for id, st := range s.status {
+		result[id] = st
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/history"
+)
+
+func TestTimelapseFrames_SelectsDayOldestFirst(t *testing.T) {
+	loc := time.FixedZone("PDT", -7*3600)
+	start := time.Date(2026, 10, 15, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+
+	// History lists newest first
+	at := func(d time.Duration) history.Frame {
+		ft := start.Add(d)
+		return history.Frame{Timestamp: ft.UnixMilli(), Time: ft}
+	}
+	retained := []history.Frame{
+		at(25 * time.Hour), // Today
+		at(24 * time.Hour), // Midnight belongs to today
+		at(23*time.Hour + 59*time.Minute),
+		at(12 * time.Hour),
+		at(0),
+		at(-time.Minute), // The day before
+	}
+
+	var loaded []int64
+	frames := timelapseFrames(retained, start, end, func(ts int64) ([]byte, error) {
+		loaded = append(loaded, ts)
+		return nil, nil
+	})
+	if len(frames) != 3 {
+		t.Fatalf("got %d frames, want 3", len(frames))
+	}
+	want := []time.Duration{0, 12 * time.Hour, 23*time.Hour + 59*time.Minute}
+	for i, f := range frames {
+		if !f.Time.Equal(start.Add(want[i])) {
+			t.Errorf("frame %d at %v, want %v", i, f.Time, start.Add(want[i]))
+		}
+		f.Load()
+	}
+	if len(loaded) != 3 || loaded[0] != start.UnixMilli() {
+		t.Errorf("loaded %v, want each frame's own timestamp", loaded)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// workerRetryCheckInterval is how often failed camera workers are checked for a due retry
+const workerRetryCheckInterval = 15 * time.Second
+
+// Worker retry backoff: the first retry waits workerRetryFirstDelay, doubling after
+// each failed attempt up to the configured maximum
+const (
+	workerRetryFirstDelay         = 30 * time.Second
+	defaultWorkerRetryAttempts    = 10
+	defaultWorkerRetryMaxInterval = 15 * time.Minute
+)
+
+// workerRetryPolicy returns the retry limits, or ok false when retries are disabled
+func workerRetryPolicy(global config.GlobalSettings) (maxAttempts int, maxDelay time.Duration, ok bool) {
+	if global.Global == nil || global.Global.WorkerRetry == nil || !global.Global.WorkerRetry.Enabled {
+		return 0, 0, false
+	}
+	wr := global.Global.WorkerRetry
+	maxAttempts = wr.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWorkerRetryAttempts
+	}
+	maxDelay = time.Duration(wr.MaxIntervalSeconds) * time.Second
+	if maxDelay <= 0 {
+		maxDelay = defaultWorkerRetryMaxInterval
+	}
+	return maxAttempts, maxDelay, true
+}
+
+// workerRetryDelay is the wait before retry attempt n (counting from 0)
+func workerRetryDelay(attempt int, maxDelay time.Duration) time.Duration {
+	delay := workerRetryFirstDelay
+	for i := 0; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// retryFailedWorkers periodically restarts camera workers that failed to start,
+// while worker_retry is enabled
+func (b *Bridge) retryFailedWorkers(stop <-chan struct{}) {
+	ticker := time.NewTicker(workerRetryCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			b.retryDueWorkers(now)
+		}
+	}
+}
+
+// retryDueWorkers starts each enabled camera whose worker failed to start and whose
+// next retry is due, scheduling the following retry if it fails again
+func (b *Bridge) retryDueWorkers(now time.Time) {
+	maxAttempts, maxDelay, ok := workerRetryPolicy(b.configService.GetGlobal())
+	if !ok || b.orchestrator == nil {
+		return
+	}
+	b.workersMu.Lock()
+	defer b.workersMu.Unlock()
+
+	due := make(map[string]int) // Camera ID -> attempts so far
+	b.workerStatusMu.Lock()
+	for id, status := range b.cameraWorkerStatus {
+		if status.Running || status.LastError == "" || status.RetryAttempts >= maxAttempts {
+			status.NextRetry = time.Time{}
+			continue
+		}
+		if status.NextRetry.IsZero() {
+			status.NextRetry = status.LastAttempt.Add(workerRetryDelay(status.RetryAttempts, maxDelay))
+		}
+		if !now.Before(status.NextRetry) {
+			due[id] = status.RetryAttempts
+		}
+	}
+	b.workerStatusMu.Unlock()
+
+	for id, attempts := range due {
+		cam, err := b.configService.GetCamera(id)
+		if err != nil || !cam.Enabled {
+			continue
+		}
+		if b.uploadPool != nil {
+			b.uploadPool.Release(id)
+		}
+		err = b.addCamera(*cam)
+
+		// addCamera replaced the status; carry the attempt count over
+		b.workerStatusMu.Lock()
+		status := b.cameraWorkerStatus[id]
+		status.RetryAttempts = attempts + 1
+		if err != nil && status.RetryAttempts < maxAttempts {
+			status.NextRetry = status.LastAttempt.Add(workerRetryDelay(status.RetryAttempts, maxDelay))
+		}
+		next := status.NextRetry
+		b.workerStatusMu.Unlock()
+
+		switch {
+		case err == nil:
+			b.log.Info("Camera worker started on automatic retry", "camera", id, "attempt", attempts+1)
+		case next.IsZero():
+			b.log.Warn("Camera worker retries exhausted - edit the camera to try again",
+				"camera", id, "attempts", attempts+1, "error", err)
+		default:
+			b.log.Warn("Camera worker retry failed", "camera", id, "attempt", attempts+1, "next_retry", next, "error", err)
+		}
+	}
+}
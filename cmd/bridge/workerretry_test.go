@@ -0,0 +1,91 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/scheduler"
+)
+
+func TestWorkerRetryDelay(t *testing.T) {
+	for attempt, want := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		if got := workerRetryDelay(attempt, 5*time.Minute); got != want {
+			t.Errorf("workerRetryDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryDueWorkers_Schedule(t *testing.T) {
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	cam := config.Camera{
+		ID:                     "kspb",
+		Name:                   "North",
+		Enabled:                true,
+		Type:                   "http",
+		SnapshotURL:            "http://cam.invalid/snap.jpg",
+		CaptureIntervalSeconds: 60,
+		Upload:                 &config.Upload{Host: "upload.invalid", Username: "u", Password: "p"},
+	}
+	if err := svc.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+	orchConfig := scheduler.DefaultOrchestratorConfig()
+	orchConfig.QueueBasePath = t.TempDir()
+	orch, err := scheduler.NewOrchestrator(orchConfig)
+	if err != nil {
+		t.Fatalf("NewOrchestrator: %v", err)
+	}
+	defer orch.Stop()
+
+	failed := time.Now()
+	status := &CameraWorkerStatus{CameraID: cam.ID, LastError: "Open tunnel failed", LastAttempt: failed}
+	bridge := &Bridge{
+		configService:      svc,
+		orchestrator:       orch,
+		log:                logger.Default(),
+		cameraWorkerStatus: map[string]*CameraWorkerStatus{cam.ID: status},
+	}
+
+	bridge.retryDueWorkers(failed.Add(time.Hour))
+	if !status.NextRetry.IsZero() {
+		t.Fatal("retry scheduled while worker_retry is disabled")
+	}
+
+	if err := svc.UpdateGlobal(func(g *config.GlobalSettings) error {
+		if g.Global == nil {
+			g.Global = &config.Global{}
+		}
+		g.Global.WorkerRetry = &config.WorkerRetry{Enabled: true, MaxAttempts: 2}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+	bridge.retryDueWorkers(failed)
+	if want := failed.Add(workerRetryFirstDelay); !status.NextRetry.Equal(want) {
+		t.Errorf("NextRetry = %v, want %v", status.NextRetry, want)
+	}
+	if got := bridge.getWorkerStatus(cam.ID)["worker_next_retry"]; got == nil {
+		t.Error("worker_next_retry missing from worker status")
+	}
+
+	// A camera disabled since the failure is left alone
+	if err := svc.UpdateCamera(cam.ID, func(c *config.Camera) error { c.Enabled = false; return nil }); err != nil {
+		t.Fatalf("UpdateCamera: %v", err)
+	}
+	bridge.retryDueWorkers(failed.Add(time.Hour))
+	if bridge.cameraWorkerStatus[cam.ID] != status || status.RetryAttempts != 0 {
+		t.Error("disabled camera was retried")
+	}
+
+	// Out of attempts, nothing more is scheduled
+	status.RetryAttempts = 2
+	bridge.retryDueWorkers(failed.Add(time.Hour))
+	if !status.NextRetry.IsZero() {
+		t.Errorf("NextRetry = %v after the last attempt, want none", status.NextRetry)
+	}
+}
@@ -0,0 +1,94 @@
+// Package alert delivers operator alerts to an optional webhook
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"sync/atomic"
+	"time"
+)
+
+// Timeout for webhook requests
+const requestTimeout = 10 * time.Second
+
+// Event is the JSON payload posted to the alert webhook
+type Event struct {
+	Type     string                 `json:"type"` // e.g. "sla_breached", "sla_recovered"
+	BridgeID string                 `json:"bridge_id"`
+	CameraID string                 `json:"camera_id,omitempty"`
+	Message  string                 `json:"message"`
+	Time     time.Time              `json:"time"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Logger is the logging interface used by Notifier
+type Logger interface {
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// Notifier posts events to the webhook returned by url. An empty URL disables
+// delivery, so the target can change with config without rebuilding the notifier.
+type Notifier struct {
+	url    func() string
+	client *http.Client
+	logger Logger
+	sent   atomic.Int64
+	failed atomic.Int64
+}
+
+// NewNotifier creates a notifier that reads the webhook URL on every send
+func NewNotifier(url func() string, logger Logger) *Notifier {
+	return &Notifier{
+		url:    url,
+		client: &http.Client{Timeout: requestTimeout},
+		logger: logger,
+	}
+}
+
+// Notify delivers an event in the background; failures are logged, never retried,
+// because a stale alert is worse than a missing one
+func (n *Notifier) Notify(event Event) {
+	url := n.url()
+	if url == "" {
+		return
+	}
+	go func() {
+		if err := n.post(url, event); err != nil {
+			n.failed.Add(1)
+			n.logger.Warn("Alert webhook failed", "type", event.Type, "camera", event.CameraID, "error", err)
+			return
+		}
+		n.sent.Add(1)
+	}()
+}
+
+func (n *Notifier) post(url string, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	resp, err := n.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// Drop the URL from the error: webhook URLs often carry a token, and the
+		// error is logged
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("%s webhook: %w", urlErr.Op, urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Stats returns the number of delivered and failed webhook posts
+func (n *Notifier) Stats() (sent, failed int64) {
+	return n.sent.Load(), n.failed.Load()
+}
@@ -0,0 +1,78 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Warn(string, ...interface{}) {}
+
+func TestNotifier_PostsEvent(t *testing.T) {
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		var e Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		received <- e
+	}))
+	defer server.Close()
+
+	n := NewNotifier(func() string { return server.URL }, nopLogger{})
+	n.Notify(Event{Type: "sla_breached", CameraID: "cam1", Message: "stale"})
+
+	select {
+	case e := <-received:
+		if e.Type != "sla_breached" || e.CameraID != "cam1" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestNotifier_DisabledAndFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	url := ""
+	n := NewNotifier(func() string { return url }, nopLogger{})
+	n.Notify(Event{Type: "sla_breached"}) // No URL: dropped silently
+
+	url = server.URL
+	n.Notify(Event{Type: "sla_breached"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, failed := n.Stats(); failed == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if sent, failed := n.Stats(); sent != 0 || failed != 1 {
+		t.Errorf("sent=%d failed=%d, want 0/1", sent, failed)
+	}
+}
+
+func TestNotifier_ErrorOmitsURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL + "/services/hooksecret"
+	server.Close()
+
+	n := NewNotifier(func() string { return url }, nopLogger{})
+	err := n.post(url, Event{Type: "sla_breached"})
+	if err == nil || strings.Contains(err.Error(), "hooksecret") {
+		t.Errorf("err = %v, want a failure without the URL", err)
+	}
+}
@@ -0,0 +1,200 @@
+package camera
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bridge agent protocol
+//
+// An agent is a small service on a host that can reach cameras the bridge cannot.
+// The bridge asks it for one fresh snapshot at a time and does everything else
+// (time stamping, queueing, uploads) itself:
+//
+//	GET <url>/v1/capture?camera=<camera id>
+//
+// A 200 response carries the JPEG as its body. Any other status is a failed capture
+// whose plain-text body explains why (e.g. 404 for an unknown camera, 502 when the
+// camera did not answer). The agent must return a fresh frame, never a cached one.
+// Requests use HTTPS with mutual TLS: the agent verifies the bridge's client
+// certificate and the bridge verifies the agent's against a configured CA.
+const agentCapturePath = "/v1/capture"
+
+// maxAgentErrorBody bounds how much of an agent's error response is kept
+const maxAgentErrorBody = 200
+
+// AgentConfig reaches a camera through a bridge agent
+type AgentConfig struct {
+	URL      string // Agent base URL, e.g. https://10.0.5.2:8443
+	CameraID string // Camera name on the agent; default: the bridge's camera ID
+	CertFile string // PEM client certificate presented to the agent
+	KeyFile  string // PEM key for CertFile
+	CAFile   string // PEM bundle trusted for the agent's certificate; default: system roots
+}
+
+// AgentStatus describes whether the bridge can reach a camera's agent
+type AgentStatus struct {
+	URL                 string    `json:"url"`
+	Reachable           bool      `json:"reachable"` // Last request got a response, whatever the capture outcome
+	LastContact         time.Time `json:"last_contact,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"` // Requests the agent did not answer
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// AgentReporter is implemented by cameras captured through a bridge agent
+type AgentReporter interface {
+	Camera
+
+	// AgentStatus reports reachability of the agent
+	AgentStatus() AgentStatus
+}
+
+// AgentCamera implements Camera by asking a bridge agent for snapshots
+type AgentCamera struct {
+	config     Config
+	client     *http.Client
+	captureURL string
+
+	mu     sync.Mutex
+	status AgentStatus
+}
+
+// NewAgentCamera creates an agent camera. Returns an error if the agent URL or
+// client certificate is missing or unreadable.
+func NewAgentCamera(config Config) (*AgentCamera, error) {
+	agent := config.Agent
+	if agent == nil || agent.URL == "" {
+		return nil, fmt.Errorf("agent.url is required for agent camera")
+	}
+	base, err := url.Parse(strings.TrimSuffix(agent.URL, "/"))
+	if err != nil || base.Scheme != "https" || base.Host == "" {
+		return nil, fmt.Errorf("agent.url must be an https URL")
+	}
+	tlsConfig, err := agentTLS(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	remoteID := agent.CameraID
+	if remoteID == "" {
+		remoteID = config.ID
+	}
+	return &AgentCamera{
+		config:     config,
+		client:     &http.Client{Timeout: timeout, Transport: transport},
+		captureURL: base.String() + agentCapturePath + "?camera=" + url.QueryEscape(remoteID),
+		status:     AgentStatus{URL: base.String()},
+	}, nil
+}
+
+// agentTLS builds the mutual TLS configuration for an agent
+func agentTLS(agent *AgentConfig) (*tls.Config, error) {
+	if agent.CertFile == "" || agent.KeyFile == "" {
+		return nil, fmt.Errorf("agent.cert_file and agent.key_file are required")
+	}
+	cert, err := tls.LoadX509KeyPair(agent.CertFile, agent.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load agent client certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if agent.CAFile != "" {
+		pem, err := os.ReadFile(agent.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read agent ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("agent ca_file %s contains no PEM certificates", agent.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// Capture asks the agent for a fresh snapshot
+func (c *AgentCamera) Capture(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.captureURL, nil)
+	if err != nil {
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "create request", Err: err}
+	}
+	req.Header.Set("Accept", "image/jpeg")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.recordContact(false, err.Error())
+		if ctx.Err() == context.DeadlineExceeded || isTimeoutError(err) {
+			return nil, &TimeoutError{CameraID: c.config.ID, Timeout: c.client.Timeout}
+		}
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "agent unreachable", Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxAgentErrorBody))
+		msg := fmt.Sprintf("agent returned HTTP %d", resp.StatusCode)
+		if reason := strings.TrimSpace(string(body)); reason != "" {
+			msg += ": " + reason
+		}
+		c.recordContact(true, msg)
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, &AuthError{CameraID: c.config.ID, Message: msg}
+		}
+		return nil, &CaptureError{CameraID: c.config.ID, Message: msg}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.recordContact(true, err.Error())
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "read agent response", Err: err}
+	}
+	if len(data) == 0 {
+		c.recordContact(true, "empty response body")
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "empty response body"}
+	}
+	c.recordContact(true, "")
+	return data, nil
+}
+
+// recordContact updates reachability after a request; errMsg describes a failure,
+// whether or not the agent answered
+func (c *AgentCamera) recordContact(answered bool, errMsg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.Reachable = answered
+	c.status.LastError = errMsg
+	if answered {
+		c.status.LastContact = time.Now()
+		c.status.ConsecutiveFailures = 0
+	} else {
+		c.status.ConsecutiveFailures++
+	}
+}
+
+// AgentStatus reports reachability of the agent
+func (c *AgentCamera) AgentStatus() AgentStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// ID returns the camera identifier
+func (c *AgentCamera) ID() string { return c.config.ID }
+
+// Type returns the camera type
+func (c *AgentCamera) Type() string { return "agent" }
@@ -0,0 +1,143 @@
+package camera
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and key, returning their
+// paths and the parsed certificate
+func writeClientCert(t *testing.T) (certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bridge"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client-key.pem")
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	cert, _ = x509.ParseCertificate(der)
+	return certFile, keyFile, cert
+}
+
+func TestAgentCamera_CaptureOverMutualTLS(t *testing.T) {
+	certFile, keyFile, clientCert := writeClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != agentCapturePath {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Query().Get("camera") {
+		case "north":
+			w.Write([]byte("frame"))
+		case "offline":
+			http.Error(w, "camera did not answer", http.StatusBadGateway)
+		default:
+			http.Error(w, "unknown camera", http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Refused handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "agent-ca.pem")
+	os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600)
+
+	newCam := func(id, remote string) *AgentCamera {
+		t.Helper()
+		cam, err := NewAgentCamera(Config{ID: id, Type: "agent", Agent: &AgentConfig{
+			URL: server.URL + "/", CameraID: remote, CertFile: certFile, KeyFile: keyFile, CAFile: caFile,
+		}})
+		if err != nil {
+			t.Fatalf("NewAgentCamera: %v", err)
+		}
+		return cam
+	}
+
+	// The bridge camera ID names the camera on the agent unless overridden
+	cam := newCam("north", "")
+	data, err := cam.Capture(context.Background())
+	if err != nil || string(data) != "frame" {
+		t.Fatalf("Capture() = %q, %v", data, err)
+	}
+	if s := cam.AgentStatus(); !s.Reachable || s.LastContact.IsZero() || s.LastError != "" {
+		t.Errorf("status after capture = %+v", s)
+	}
+
+	// A camera failure behind a reachable agent keeps the agent reachable
+	cam = newCam("kspb-south", "offline")
+	if _, err := cam.Capture(context.Background()); err == nil {
+		t.Fatal("Capture() succeeded for an offline camera")
+	}
+	if s := cam.AgentStatus(); !s.Reachable || s.LastError != "agent returned HTTP 502: camera did not answer" {
+		t.Errorf("status after camera failure = %+v", s)
+	}
+
+	// Without a client certificate the agent refuses the connection
+	cam = newCam("north", "")
+	cam.client.Transport.(*http.Transport).TLSClientConfig.Certificates = nil
+	_, err = cam.Capture(context.Background())
+	var captureErr *CaptureError
+	if !errors.As(err, &captureErr) {
+		t.Fatalf("Capture() without client cert = %v, want CaptureError", err)
+	}
+	if s := cam.AgentStatus(); s.Reachable || s.ConsecutiveFailures != 1 {
+		t.Errorf("status after refused connection = %+v", s)
+	}
+}
+
+func TestNewAgentCamera_ConfigErrors(t *testing.T) {
+	certFile, keyFile, _ := writeClientCert(t)
+	tests := []struct {
+		name  string
+		agent *AgentConfig
+	}{
+		{"missing agent", nil},
+		{"plain http", &AgentConfig{URL: "http://agent:8080", CertFile: certFile, KeyFile: keyFile}},
+		{"no client certificate", &AgentConfig{URL: "https://agent:8443"}},
+		{"unreadable key", &AgentConfig{URL: "https://agent:8443", CertFile: certFile, KeyFile: certFile}},
+		{"missing ca file", &AgentConfig{URL: "https://agent:8443", CertFile: certFile, KeyFile: keyFile, CAFile: "/nonexistent/ca.pem"}},
+	}
+	for _, tt := range tests {
+		if _, err := NewAgentCamera(Config{ID: "cam", Type: "agent", Agent: tt.agent}); err == nil {
+			t.Errorf("%s: NewAgentCamera succeeded", tt.name)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package camera
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoAudio means the camera's stream has no audio track
+var ErrNoAudio = errors.New("stream has no audio")
+
+// AudioSampleRate is the rate clips are resampled to, enough for sounds up to 8 kHz
+const AudioSampleRate = 16000
+
+// maxAudioClip bounds a clip, so a misbehaving stream cannot grow one without limit
+const maxAudioClip = 60 * time.Second
+
+// AudioClip is mono 16-bit PCM audio
+type AudioClip struct {
+	Samples    []int16
+	SampleRate int
+}
+
+// AudioSource is implemented by cameras that can record audio from their stream
+type AudioSource interface {
+	CaptureAudio(ctx context.Context, d time.Duration) (AudioClip, error)
+}
+
+// CaptureAudio records d of the stream's first audio track with ffmpeg, on its own
+// connection. Returns ErrNoAudio when the stream has none. Frame captures and the
+// reconnect backoff are unaffected.
+func (c *RTSPCamera) CaptureAudio(ctx context.Context, d time.Duration) (AudioClip, error) {
+	d = min(d, maxAudioClip)
+	timeout := time.Duration(c.config.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 20 * time.Second
+	}
+	captureCtx, cancel := context.WithTimeout(ctx, timeout+d)
+	defer cancel()
+
+	// -map 0:a:0 fails fast with "matches no streams" when there is no audio
+	args := []string{
+		"-rtsp_transport", "tcp",
+		"-i", c.streamURL(),
+		"-map", "0:a:0",
+		"-t", strconv.FormatFloat(d.Seconds(), 'f', 3, 64),
+		"-ac", "1",
+		"-ar", strconv.Itoa(AudioSampleRate),
+		"-f", "s16le",
+		"-",
+	}
+	cmd := exec.CommandContext(captureCtx, "ffmpeg", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if captureCtx.Err() == context.DeadlineExceeded {
+			return AudioClip{}, &TimeoutError{CameraID: c.config.ID, Timeout: timeout + d}
+		}
+		if strings.Contains(stderr.String(), "matches no streams") {
+			return AudioClip{}, ErrNoAudio
+		}
+		return AudioClip{}, &CaptureError{
+			CameraID: c.config.ID,
+			Message:  fmt.Sprintf("ffmpeg audio capture failed: %s", strings.TrimSpace(stderr.String())),
+			Err:      err,
+		}
+	}
+	if len(out) < 2 {
+		return AudioClip{}, ErrNoAudio
+	}
+
+	samples := make([]int16, len(out)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(out[2*i:]))
+	}
+	return AudioClip{Samples: samples, SampleRate: AudioSampleRate}, nil
+}
@@ -0,0 +1,48 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeAudioFFmpeg puts an ffmpeg on PATH that runs script
+func fakeAudioFFmpeg(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ffmpeg"), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestRTSPCamera_CaptureAudio(t *testing.T) {
+	cam, err := NewRTSPCamera(Config{ID: "rtsp", Type: "rtsp", RTSP: &RTSPConfig{URL: "rtsp://cam/stream"}})
+	if err != nil {
+		t.Fatalf("NewRTSPCamera: %v", err)
+	}
+
+	// Two little-endian samples: 1 and -2
+	fakeAudioFFmpeg(t, `printf '\001\000\376\377'`)
+	clip, err := cam.CaptureAudio(context.Background(), time.Second)
+	if err != nil {
+		t.Fatalf("CaptureAudio: %v", err)
+	}
+	if clip.SampleRate != AudioSampleRate || len(clip.Samples) != 2 || clip.Samples[0] != 1 || clip.Samples[1] != -2 {
+		t.Errorf("clip = %+v", clip)
+	}
+
+	fakeAudioFFmpeg(t, `echo "Stream map '0:a:0' matches no streams." >&2; exit 1`)
+	if _, err := cam.CaptureAudio(context.Background(), time.Second); !errors.Is(err, ErrNoAudio) {
+		t.Errorf("stream without audio: error = %v, want ErrNoAudio", err)
+	}
+
+	fakeAudioFFmpeg(t, `echo "Connection refused" >&2; exit 1`)
+	var captureErr *CaptureError
+	if _, err := cam.CaptureAudio(context.Background(), time.Second); !errors.As(err, &captureErr) {
+		t.Errorf("failed connection: error = %v, want CaptureError", err)
+	}
+}
@@ -0,0 +1,124 @@
+package camera
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiagnosticsConfig is a camera's JSON status endpoint, polled apart from captures
+type DiagnosticsConfig struct {
+	URL    string
+	Auth   *AuthConfig       // Default: none (the camera's auth is not sent)
+	Fields map[string]string // Status name to dotted JSON path, e.g. "system.temp_c"
+}
+
+// Diagnostics is the result of one diagnostics poll
+type Diagnostics struct {
+	Fields  map[string]interface{} // Extracted string, number and bool values
+	Missing []string               // Field names whose path was absent or not a scalar
+}
+
+// maxDiagnosticsBody bounds how much of a diagnostics response is read
+const maxDiagnosticsBody = 256 * 1024
+
+// FetchDiagnostics requests config.Diagnostics.URL and extracts its fields. The
+// camera's TLS settings and timeout apply; a failed request or a body that is not
+// JSON is an error, a missing field is not.
+func FetchDiagnostics(ctx context.Context, config Config) (Diagnostics, error) {
+	d := config.Diagnostics
+	if d == nil || d.URL == "" {
+		return Diagnostics{}, fmt.Errorf("diagnostics.url is required")
+	}
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+	client, err := newHTTPClient(config.TLS, timeout)
+	if err != nil {
+		return Diagnostics{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.URL, nil)
+	if err != nil {
+		return Diagnostics{}, fmt.Errorf("create diagnostics request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if d.Auth != nil {
+		if err := setAuth(req, config.ID, d.Auth); err != nil {
+			return Diagnostics{}, err
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Diagnostics{}, fmt.Errorf("diagnostics request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Diagnostics{}, fmt.Errorf("diagnostics HTTP status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiagnosticsBody+1))
+	if err != nil {
+		return Diagnostics{}, fmt.Errorf("read diagnostics: %w", err)
+	}
+	if len(body) > maxDiagnosticsBody {
+		return Diagnostics{}, fmt.Errorf("diagnostics response exceeds %d bytes", maxDiagnosticsBody)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return Diagnostics{}, fmt.Errorf("diagnostics response is not JSON: %w", err)
+	}
+	return extractDiagnostics(doc, d.Fields), nil
+}
+
+// extractDiagnostics looks up each field's path in doc
+func extractDiagnostics(doc interface{}, fields map[string]string) Diagnostics {
+	result := Diagnostics{Fields: make(map[string]interface{}, len(fields))}
+	for name, path := range fields {
+		value, ok := jsonPath(doc, path)
+		if !ok {
+			result.Missing = append(result.Missing, name)
+			continue
+		}
+		result.Fields[name] = value
+	}
+	sort.Strings(result.Missing)
+	return result
+}
+
+// jsonPath follows a dotted path of object keys and array indexes (e.g.
+// "sensors.0.temp") and returns the scalar value at its end
+func jsonPath(doc interface{}, path string) (interface{}, bool) {
+	value := doc
+	for _, part := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	switch value.(type) {
+	case string, float64, bool:
+		return value, true
+	}
+	return nil, false
+}
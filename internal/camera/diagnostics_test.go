@@ -0,0 +1,68 @@
+package camera
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFetchDiagnostics_ExtractsFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer diag" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"system":{"temp_c":61.5,"firmware":"2.4.1","ir":true},"sensors":[{"lux":120}],"tags":["a"]}`))
+	}))
+	defer srv.Close()
+
+	result, err := FetchDiagnostics(context.Background(), Config{
+		ID: "north",
+		Diagnostics: &DiagnosticsConfig{
+			URL:  srv.URL,
+			Auth: &AuthConfig{Type: "bearer", Token: "diag"},
+			Fields: map[string]string{
+				"temperature": "system.temp_c",
+				"firmware":    "system.firmware",
+				"ir":          "system.ir",
+				"lux":         "sensors.0.lux",
+				"uptime":      "system.uptime",
+				"tags":        "tags",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("FetchDiagnostics() error = %v", err)
+	}
+	want := map[string]interface{}{"temperature": 61.5, "firmware": "2.4.1", "ir": true, "lux": float64(120)}
+	if !reflect.DeepEqual(result.Fields, want) {
+		t.Errorf("Fields = %v, want %v", result.Fields, want)
+	}
+	if !reflect.DeepEqual(result.Missing, []string{"tags", "uptime"}) {
+		t.Errorf("Missing = %v, want the absent and non-scalar fields", result.Missing)
+	}
+}
+
+func TestFetchDiagnostics_Failures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("<html>status</html>"))
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{"/broken": "HTTP status 503", "/html": "not JSON"} {
+		_, err := FetchDiagnostics(context.Background(), Config{
+			ID:          "north",
+			Diagnostics: &DiagnosticsConfig{URL: srv.URL + path, Fields: map[string]string{"t": "t"}},
+		})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("FetchDiagnostics(%s) error = %v, want %q", path, err, want)
+		}
+	}
+}
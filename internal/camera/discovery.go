@@ -0,0 +1,129 @@
+package camera
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// wsDiscoveryAddr is the WS-Discovery multicast group ONVIF devices listen on
+var wsDiscoveryAddr = "239.255.255.250:3702"
+
+// DiscoveredDevice is an ONVIF device that answered a WS-Discovery probe
+type DiscoveredDevice struct {
+	Endpoint string   `json:"endpoint"` // Stable endpoint reference, e.g. urn:uuid:...
+	XAddrs   []string `json:"xaddrs"`   // Device service URLs at the device's current address
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// Host returns the host of the device's first service URL
+func (d DiscoveredDevice) Host() string {
+	for _, addr := range d.XAddrs {
+		if host := urlHost(addr); host != "" {
+			return host
+		}
+	}
+	return ""
+}
+
+// Matches reports whether id (a MAC address, serial number or endpoint UUID) appears
+// in the device's endpoint reference or scopes. Case and separators are ignored, so
+// "AA:BB:CC:DD:EE:FF" matches an endpoint ending in "aabbccddeeff".
+func (d DiscoveredDevice) Matches(id string) bool {
+	want := normalizeDeviceID(id)
+	if want == "" {
+		return false
+	}
+	return strings.Contains(normalizeDeviceID(d.Endpoint+" "+strings.Join(d.Scopes, " ")), want)
+}
+
+// normalizeDeviceID lower-cases s and keeps only letters and digits
+func normalizeDeviceID(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+const wsProbeTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<e:Envelope xmlns:e="http://www.w3.org/2003/05/soap-envelope" xmlns:w="http://schemas.xmlsoap.org/ws/2004/08/addressing" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:dn="http://www.onvif.org/ver10/network/wsdl">
+<e:Header><w:MessageID>uuid:%s</w:MessageID><w:To e:mustUnderstand="true">urn:schemas-xmlsoap-org:ws:2005:04:discovery</w:To><w:Action e:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2005/04/discovery/Probe</w:Action></e:Header>
+<e:Body><d:Probe><d:Types>dn:NetworkVideoTransmitter</d:Types></d:Probe></e:Body>
+</e:Envelope>`
+
+type wsProbeMatches struct {
+	Matches []struct {
+		Address string `xml:"EndpointReference>Address"`
+		Scopes  string `xml:"Scopes"`
+		XAddrs  string `xml:"XAddrs"`
+	} `xml:"Body>ProbeMatches>ProbeMatch"`
+}
+
+// DiscoverONVIF multicasts a WS-Discovery probe on the local network and collects the
+// ONVIF devices that answer within wait (or until ctx is done)
+func DiscoverONVIF(ctx context.Context, wait time.Duration) ([]DiscoveredDevice, error) {
+	group, err := net.ResolveUDPAddr("udp4", wsDiscoveryAddr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve ws-discovery address: %w", err)
+	}
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("open ws-discovery socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.WriteTo([]byte(fmt.Sprintf(wsProbeTemplate, newMessageID())), group); err != nil {
+		return nil, fmt.Errorf("send ws-discovery probe: %w", err)
+	}
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetReadDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	seen := make(map[string]bool)
+	var devices []DiscoveredDevice
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			// The read deadline ends collection; replies so far are the result
+			return devices, nil
+		}
+		var resp wsProbeMatches
+		if xml.Unmarshal(buf[:n], &resp) != nil {
+			continue
+		}
+		for _, m := range resp.Matches {
+			endpoint := strings.TrimSpace(m.Address)
+			if endpoint == "" || seen[endpoint] {
+				continue
+			}
+			seen[endpoint] = true
+			devices = append(devices, DiscoveredDevice{
+				Endpoint: endpoint,
+				XAddrs:   strings.Fields(m.XAddrs),
+				Scopes:   strings.Fields(m.Scopes),
+			})
+		}
+	}
+}
+
+// newMessageID returns a random UUID for a WS-Addressing MessageID
+func newMessageID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
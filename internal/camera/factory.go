@@ -5,9 +5,19 @@ import (
 )
 
 // NewCamera creates a camera instance based on the configuration type.
-// Supports "http", "onvif", and "rtsp" camera types.
+// Supports "http", "onvif", "rtsp", "folder" and "agent" camera types, each
+// optionally woken before capture (see WakeupConfig).
 // Returns an error if the camera type is unsupported or configuration is invalid.
 func NewCamera(config Config) (Camera, error) {
+	cam, err := newBackend(config)
+	if err != nil || config.Wakeup == nil {
+		return cam, err
+	}
+	return NewWakeupCamera(cam, config)
+}
+
+// newBackend creates the camera that performs the capture itself
+func newBackend(config Config) (Camera, error) {
 	switch config.Type {
 	case "http":
 		return NewHTTPCamera(config)
@@ -15,6 +25,10 @@ func NewCamera(config Config) (Camera, error) {
 		return NewONVIFCamera(config)
 	case "rtsp":
 		return NewRTSPCamera(config)
+	case "folder":
+		return NewFolderCamera(config)
+	case "agent":
+		return NewAgentCamera(config)
 	default:
 		return nil, fmt.Errorf("unsupported camera type: %s", config.Type)
 	}
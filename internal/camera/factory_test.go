@@ -47,6 +47,16 @@ func TestNewCamera(t *testing.T) {
 			wantErr:  false,
 			wantType: "rtsp",
 		},
+		{
+			name: "folder camera",
+			config: Config{
+				ID:     "folder-cam",
+				Type:   "folder",
+				Folder: &FolderConfig{Path: "/var/lib/captures"},
+			},
+			wantErr:  false,
+			wantType: "folder",
+		},
 		{
 			name: "unsupported type",
 			config: Config{
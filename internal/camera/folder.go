@@ -0,0 +1,149 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoImage means a capture found nothing new to capture (e.g. an empty folder).
+// The cycle is skipped without counting as a failure.
+var ErrNoImage = errors.New("no new image")
+
+// ErrNotModified is the ErrNoImage of an HTTP camera that answered a conditional
+// request with 304 Not Modified
+var ErrNotModified = fmt.Errorf("%w: not modified (HTTP 304)", ErrNoImage)
+
+// folderSettleTime skips files modified this recently, which the writing process
+// may not have finished
+const folderSettleTime = 2 * time.Second
+
+// FolderConfig reads frames that an external capture process writes to a directory
+type FolderConfig struct {
+	Path             string
+	DeleteAfterQueue bool // Remove each file once its frame is queued for upload
+}
+
+// QueuedNotifier is implemented by cameras that act on a frame once it is safely
+// queued, e.g. a folder camera deleting the file it read
+type QueuedNotifier interface {
+	FrameQueued()
+}
+
+// FolderCamera implements Camera by reading the newest image file in a directory.
+// Only files newer than the last one captured are taken, so an idle folder skips
+// cycles instead of uploading the same frame repeatedly, and leftover older files
+// never send the camera back in time.
+type FolderCamera struct {
+	config Config
+
+	mu      sync.Mutex
+	last    folderFile // Last file captured
+	pending string     // File captured but not yet queued
+}
+
+// folderFile is an image file and when it was last written
+type folderFile struct {
+	path    string
+	modTime time.Time
+}
+
+// NewFolderCamera creates a folder camera. Returns an error if the path is missing.
+func NewFolderCamera(config Config) (*FolderCamera, error) {
+	if config.Folder == nil || config.Folder.Path == "" {
+		return nil, fmt.Errorf("folder.path is required for folder camera")
+	}
+	return &FolderCamera{config: config}, nil
+}
+
+// Capture returns the newest image in the folder, or ErrNoImage when there is none
+// or it was already captured
+func (c *FolderCamera) Capture(ctx context.Context) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	newest, err := c.newestFile(time.Now())
+	if err != nil {
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "read folder", Err: err}
+	}
+
+	c.mu.Lock()
+	seen := newest.path == "" || !newest.modTime.After(c.last.modTime)
+	c.mu.Unlock()
+	if seen {
+		return nil, ErrNoImage
+	}
+
+	data, err := os.ReadFile(newest.path)
+	if err != nil {
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "read " + filepath.Base(newest.path), Err: err}
+	}
+	if len(data) == 0 {
+		return nil, &CaptureError{CameraID: c.config.ID, Message: "empty file " + filepath.Base(newest.path)}
+	}
+
+	c.mu.Lock()
+	c.last = newest
+	c.pending = newest.path
+	c.mu.Unlock()
+	return data, nil
+}
+
+// FrameQueued deletes the last captured file when configured to
+func (c *FolderCamera) FrameQueued() {
+	c.mu.Lock()
+	path := c.pending
+	c.pending = ""
+	c.mu.Unlock()
+	if path == "" || !c.config.Folder.DeleteAfterQueue {
+		return
+	}
+	_ = os.Remove(path) // Best-effort; a file left behind is not captured again
+}
+
+// newestFile returns the most recently modified, settled JPEG in the folder, or a
+// zero folderFile if there is none. Hidden and temporary files are ignored.
+func (c *FolderCamera) newestFile(now time.Time) (folderFile, error) {
+	entries, err := os.ReadDir(c.config.Folder.Path)
+	if err != nil {
+		return folderFile{}, err
+	}
+	var newest folderFile
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || strings.HasPrefix(name, ".") || !c.frameExt(filepath.Ext(name)) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || now.Sub(info.ModTime()) < folderSettleTime {
+			continue
+		}
+		if newest.path == "" || info.ModTime().After(newest.modTime) {
+			newest = folderFile{path: filepath.Join(c.config.Folder.Path, name), modTime: info.ModTime()}
+		}
+	}
+	return newest, nil
+}
+
+// frameExt reports whether files with extension ext are frames: JPEG, plus the
+// raw formats when the camera converts raw frames
+func (c *FolderCamera) frameExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".jpg", ".jpeg":
+		return true
+	case ".png", ".fits", ".fit", ".fts":
+		return c.config.Raw
+	}
+	return false
+}
+
+// ID returns the camera identifier
+func (c *FolderCamera) ID() string { return c.config.ID }
+
+// Type returns the camera type
+func (c *FolderCamera) Type() string { return c.config.Type }
@@ -0,0 +1,121 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeFrame writes a file in dir dated age ago
+func writeFrame(t *testing.T, dir, name, data string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	when := time.Now().Add(-age)
+	if err := os.Chtimes(path, when, when); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFolderCamera_CapturesNewestOnce(t *testing.T) {
+	dir := t.TempDir()
+	cam, err := NewFolderCamera(Config{ID: "folder-cam", Type: "folder", Folder: &FolderConfig{Path: dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err := cam.Capture(ctx); !errors.Is(err, ErrNoImage) {
+		t.Fatalf("empty folder: err = %v, want ErrNoImage", err)
+	}
+
+	writeFrame(t, dir, "older.jpg", "older", time.Minute)
+	writeFrame(t, dir, "newer.JPG", "newer", 30*time.Second)
+	writeFrame(t, dir, "notes.txt", "text", time.Second*10)
+	writeFrame(t, dir, ".partial.jpg", "hidden", time.Second*10)
+	writeFrame(t, dir, "writing.jpg", "in progress", 0)
+
+	data, err := cam.Capture(ctx)
+	if err != nil || string(data) != "newer" {
+		t.Fatalf("Capture = %q, %v; want the newest settled jpeg", data, err)
+	}
+	if _, err := cam.Capture(ctx); !errors.Is(err, ErrNoImage) {
+		t.Errorf("unchanged folder: err = %v, want ErrNoImage", err)
+	}
+
+	// The in-progress file is captured once it has settled
+	writeFrame(t, dir, "writing.jpg", "done", 5*time.Second)
+	if data, err := cam.Capture(ctx); err != nil || string(data) != "done" {
+		t.Errorf("Capture after write finished = %q, %v", data, err)
+	}
+	cam.FrameQueued()
+	if _, err := os.Stat(filepath.Join(dir, "writing.jpg")); err != nil {
+		t.Error("file deleted without delete_after_queue")
+	}
+}
+
+func TestFolderCamera_DeleteAfterQueue(t *testing.T) {
+	dir := t.TempDir()
+	cam, err := NewFolderCamera(Config{ID: "folder-cam", Type: "folder", Folder: &FolderConfig{Path: dir, DeleteAfterQueue: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	older := writeFrame(t, dir, "a.jpg", "a", time.Minute)
+	newer := writeFrame(t, dir, "b.jpg", "b", 30*time.Second)
+
+	if _, err := cam.Capture(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(newer); err != nil {
+		t.Fatal("file deleted before its frame was queued")
+	}
+	cam.FrameQueued()
+	if _, err := os.Stat(newer); !os.IsNotExist(err) {
+		t.Error("queued file not deleted")
+	}
+	if _, err := os.Stat(older); err != nil {
+		t.Error("only the captured file is deleted")
+	}
+	if _, err := cam.Capture(context.Background()); !errors.Is(err, ErrNoImage) {
+		t.Errorf("older leftover file captured: err = %v", err)
+	}
+}
+
+func TestFolderCamera_MissingFolder(t *testing.T) {
+	cam, err := NewFolderCamera(Config{ID: "folder-cam", Type: "folder", Folder: &FolderConfig{Path: filepath.Join(t.TempDir(), "gone")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var captureErr *CaptureError
+	if _, err := cam.Capture(context.Background()); !errors.As(err, &captureErr) {
+		t.Errorf("missing folder: err = %v, want a capture error", err)
+	}
+}
+
+func TestFolderCamera_RawExtensions(t *testing.T) {
+	dir := t.TempDir()
+	writeFrame(t, dir, "frame.jpg", "jpeg", time.Minute)
+	writeFrame(t, dir, "sky.FITS", "fits", 30*time.Second)
+
+	cam, err := NewFolderCamera(Config{ID: "folder-cam", Type: "folder", Folder: &FolderConfig{Path: dir}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := cam.Capture(context.Background()); err != nil || string(data) != "jpeg" {
+		t.Errorf("Capture = %q, %v; want FITS ignored without raw", data, err)
+	}
+
+	raw, err := NewFolderCamera(Config{ID: "folder-cam", Type: "folder", Folder: &FolderConfig{Path: dir}, Raw: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := raw.Capture(context.Background()); err != nil || string(data) != "fits" {
+		t.Errorf("raw Capture = %q, %v; want the newer FITS file", data, err)
+	}
+}
@@ -0,0 +1,29 @@
+package camera
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HeaderRule fails a capture whose HTTP response carries a header signalling that the
+// camera is offline, e.g. a proxy serving a cached placeholder with a 200 status
+type HeaderRule struct {
+	Header string
+	Value  string // Case-insensitive match of the whole value; "" = header present with any value
+}
+
+// checkHeaderRules returns a CaptureError naming the first rule the response matches
+func checkHeaderRules(cameraID string, rules []HeaderRule, h http.Header) error {
+	for _, rule := range rules {
+		for _, value := range h.Values(rule.Header) {
+			if rule.Value == "" || strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(rule.Value)) {
+				return &CaptureError{
+					CameraID: cameraID,
+					Message:  fmt.Sprintf("response header %s: %q matches fail_on_headers rule", http.CanonicalHeaderKey(rule.Header), value),
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -1,18 +1,34 @@
 package camera
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
+// maxNotModified is how many 304 answers in a row are trusted before a snapshot is
+// fetched unconditionally, in case the camera's validators never change
+const maxNotModified = 10
+
+// DefaultMaxSnapshotBytes caps a snapshot body when no limit is configured
+const DefaultMaxSnapshotBytes = 20 << 20
+
 // HTTPCamera implements Camera interface for HTTP snapshot URLs
 type HTTPCamera struct {
 	config Config
 	client *http.Client
+
+	// Validators of the last snapshot, for conditional requests
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+	notModified  int // 304 answers in a row
 }
 
 // NewHTTPCamera creates a new HTTP camera instance.
@@ -27,11 +43,14 @@ func NewHTTPCamera(config Config) (*HTTPCamera, error) {
 		timeout = 15 * time.Second // Default timeout
 	}
 
+	client, err := newHTTPClient(config.TLS, timeout)
+	if err != nil {
+		return nil, err
+	}
+
 	return &HTTPCamera{
 		config: config,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+		client: client,
 	}, nil
 }
 
@@ -60,6 +79,7 @@ func (c *HTTPCamera) Capture(ctx context.Context) ([]byte, error) {
 	req.Header.Set("Cache-Control", "no-cache, no-store, must-revalidate")
 	req.Header.Set("Pragma", "no-cache")
 	req.Header.Set("Expires", "0")
+	conditional := c.config.ConditionalRequests && c.setValidators(req)
 
 	// Add authentication if configured
 	if c.config.Auth != nil {
@@ -96,6 +116,13 @@ func (c *HTTPCamera) Capture(ctx context.Context) ([]byte, error) {
 		}
 	}
 
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		c.mu.Lock()
+		c.notModified++
+		c.mu.Unlock()
+		return nil, ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return nil, &CaptureError{
 			CameraID: c.config.ID,
@@ -103,26 +130,102 @@ func (c *HTTPCamera) Capture(ctx context.Context) ([]byte, error) {
 		}
 	}
 
-	// Read image data
-	data, err := io.ReadAll(resp.Body)
+	if err := checkHeaderRules(c.config.ID, c.config.FailHeaders, resp.Header); err != nil {
+		return nil, err
+	}
+
+	data, err := c.readSnapshot(ctx, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, &CaptureError{
+			CameraID: c.config.ID,
+			Message:  "empty response body",
+		}
+	}
+
+	if c.config.ConditionalRequests {
+		c.mu.Lock()
+		c.etag, c.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		c.notModified = 0
+		c.mu.Unlock()
+	}
+	return data, nil
+}
+
+// readSnapshot reads the snapshot body up to the size limit. Go already fails a body
+// shorter than its Content-Length; a chunked body has none, so a JPEG must instead end
+// with its EOI marker. The request context's deadline covers the whole read, however
+// slowly the chunks arrive.
+func (c *HTTPCamera) readSnapshot(ctx context.Context, resp *http.Response) ([]byte, error) {
+	limit := c.config.MaxSnapshotBytes
+	if limit <= 0 {
+		limit = DefaultMaxSnapshotBytes
+	}
+	if resp.ContentLength > limit {
+		return nil, &CaptureError{
+			CameraID: c.config.ID,
+			Message:  fmt.Sprintf("snapshot of %d bytes exceeds the %d byte limit", resp.ContentLength, limit),
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
 	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) || isTimeoutError(err) {
+			return nil, &TimeoutError{CameraID: c.config.ID, Timeout: c.client.Timeout}
+		}
 		return nil, &CaptureError{
 			CameraID: c.config.ID,
 			Message:  "read response body",
 			Err:      err,
 		}
 	}
-
-	if len(data) == 0 {
+	if int64(len(data)) > limit {
 		return nil, &CaptureError{
 			CameraID: c.config.ID,
-			Message:  "empty response body",
+			Message:  fmt.Sprintf("snapshot exceeds the %d byte limit", limit),
 		}
 	}
 
+	if resp.ContentLength < 0 && !c.config.TolerateMissingEOI && !jpegComplete(data) {
+		return nil, &CaptureError{
+			CameraID: c.config.ID,
+			Message:  "incomplete snapshot: chunked response ended without a JPEG EOI marker",
+		}
+	}
 	return data, nil
 }
 
+// jpegComplete reports whether data ends with a JPEG EOI marker, ignoring trailing
+// line breaks and padding. Data that is not a JPEG is left to later checks.
+func jpegComplete(data []byte) bool {
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return true
+	}
+	return bytes.HasSuffix(bytes.TrimRight(data, "\r\n\x00 "), []byte{0xFF, 0xD9})
+}
+
+// setValidators adds If-None-Match and If-Modified-Since from the last snapshot,
+// reporting whether the request became conditional. After maxNotModified 304s in
+// a row the request is left unconditional.
+func (c *HTTPCamera) setValidators(req *http.Request) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.notModified >= maxNotModified {
+		c.notModified = 0
+		return false
+	}
+	if c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+	if c.lastModified != "" {
+		req.Header.Set("If-Modified-Since", c.lastModified)
+	}
+	return c.etag != "" || c.lastModified != ""
+}
+
 // ID returns the camera identifier
 func (c *HTTPCamera) ID() string {
 	return c.config.ID
@@ -135,13 +238,16 @@ func (c *HTTPCamera) Type() string {
 
 // addAuth adds authentication headers to the request
 func (c *HTTPCamera) addAuth(req *http.Request) error {
-	auth := c.config.Auth
+	return setAuth(req, c.config.ID, c.config.Auth)
+}
 
+// setAuth adds auth's credentials to a request made for cameraID
+func setAuth(req *http.Request, cameraID string, auth *AuthConfig) error {
 	switch auth.Type {
 	case "basic":
 		if auth.Username == "" || auth.Password == "" {
 			return &AuthError{
-				CameraID: c.config.ID,
+				CameraID: cameraID,
 				Message:  "username and password required for basic auth",
 			}
 		}
@@ -151,7 +257,7 @@ func (c *HTTPCamera) addAuth(req *http.Request) error {
 		// Digest auth not implemented - falls back to basic auth
 		if auth.Username == "" || auth.Password == "" {
 			return &AuthError{
-				CameraID: c.config.ID,
+				CameraID: cameraID,
 				Message:  "username and password required for digest auth",
 			}
 		}
@@ -160,7 +266,7 @@ func (c *HTTPCamera) addAuth(req *http.Request) error {
 	case "bearer":
 		if auth.Token == "" {
 			return &AuthError{
-				CameraID: c.config.ID,
+				CameraID: cameraID,
 				Message:  "token required for bearer auth",
 			}
 		}
@@ -168,7 +274,7 @@ func (c *HTTPCamera) addAuth(req *http.Request) error {
 
 	default:
 		return &AuthError{
-			CameraID: c.config.ID,
+			CameraID: cameraID,
 			Message:  fmt.Sprintf("unsupported auth type: %s", auth.Type),
 		}
 	}
@@ -2,6 +2,7 @@ package camera
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -187,6 +188,43 @@ func TestHTTPCamera_Capture_EmptyBody(t *testing.T) {
 	}
 }
 
+func TestHTTPCamera_Capture_FailHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Camera-Status", r.URL.Query().Get("status"))
+		if r.URL.Query().Get("cached") != "" {
+			w.Header().Set("X-Cache", "HIT")
+		}
+		w.Write([]byte("placeholder"))
+	}))
+	defer server.Close()
+
+	rules := []HeaderRule{{Header: "x-camera-status", Value: "Offline"}, {Header: "X-Cache"}}
+	tests := []struct {
+		query    string
+		wantRule string
+	}{
+		{"status=online", ""},
+		{"status=offline", `X-Camera-Status: "offline"`},
+		{"status=online&cached=1", `X-Cache: "HIT"`},
+	}
+	for _, tt := range tests {
+		cam, err := NewHTTPCamera(Config{ID: "proxy-cam", SnapshotURL: server.URL + "/snap.jpg?" + tt.query, FailHeaders: rules})
+		if err != nil {
+			t.Fatalf("NewHTTPCamera: %v", err)
+		}
+		data, err := cam.Capture(context.Background())
+		if tt.wantRule == "" {
+			if err != nil || string(data) != "placeholder" {
+				t.Errorf("%s: Capture() = %q, %v", tt.query, data, err)
+			}
+			continue
+		}
+		if !isCaptureErrorType(err, nil) || !strings.Contains(err.Error(), tt.wantRule) {
+			t.Errorf("%s: error = %v, want capture error naming %s", tt.query, err, tt.wantRule)
+		}
+	}
+}
+
 func TestHTTPCamera_Capture_BasicAuth(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		username, password, ok := r.BasicAuth()
@@ -441,3 +479,116 @@ func isAuthErrorType(err error, target *AuthError) bool {
 	_, ok := err.(*AuthError)
 	return ok
 }
+
+func TestHTTPCamera_ConditionalRequests(t *testing.T) {
+	var conditional []bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match") == `"frame-1"`)
+		if r.Header.Get("If-None-Match") == `"frame-1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"frame-1"`)
+		w.Write([]byte("frame"))
+	}))
+	defer server.Close()
+
+	cam, err := NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL, ConditionalRequests: true})
+	if err != nil {
+		t.Fatalf("NewHTTPCamera: %v", err)
+	}
+	if data, err := cam.Capture(context.Background()); err != nil || string(data) != "frame" {
+		t.Fatalf("first Capture() = %q, %v", data, err)
+	}
+	for i := 0; i < maxNotModified; i++ {
+		if _, err := cam.Capture(context.Background()); !errors.Is(err, ErrNotModified) || !errors.Is(err, ErrNoImage) {
+			t.Fatalf("Capture() %d error = %v, want ErrNotModified", i, err)
+		}
+	}
+
+	// A long run of 304s is checked with one unconditional fetch
+	if data, err := cam.Capture(context.Background()); err != nil || string(data) != "frame" {
+		t.Fatalf("Capture() after %d 304s = %q, %v", maxNotModified, data, err)
+	}
+	if conditional[0] || !conditional[1] || conditional[len(conditional)-1] {
+		t.Errorf("conditional requests = %v", conditional)
+	}
+}
+
+func TestHTTPCamera_UnrequestedNotModifiedFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	cam, _ := NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL})
+	if _, err := cam.Capture(context.Background()); err == nil || errors.Is(err, ErrNoImage) {
+		t.Errorf("Capture() error = %v, want a failed capture", err)
+	}
+}
+
+// chunkedServer streams body in flushed pieces, so the response has no Content-Length,
+// pausing between pieces
+func chunkedServer(t *testing.T, pause time.Duration, pieces ...[]byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range pieces {
+			w.Write(p)
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(pause):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPCamera_Capture_Chunked(t *testing.T) {
+	head, tail := []byte{0xFF, 0xD8, 0xFF, 0xE0, 1, 2, 3}, []byte{4, 5, 0xFF, 0xD9, '\r', '\n'}
+
+	server := chunkedServer(t, 0, head, tail)
+	cam, _ := NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL})
+	data, err := cam.Capture(context.Background())
+	if err != nil || len(data) != len(head)+len(tail) {
+		t.Fatalf("Capture() = %d bytes, %v; want the whole chunked body", len(data), err)
+	}
+
+	// Without Content-Length, a JPEG that stops short of its EOI is incomplete
+	server = chunkedServer(t, 0, head, []byte{4, 5})
+	cam, _ = NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL})
+	var captureErr *CaptureError
+	if _, err := cam.Capture(context.Background()); !errors.As(err, &captureErr) || !strings.Contains(captureErr.Message, "EOI") {
+		t.Errorf("Capture() error = %v, want an incomplete snapshot", err)
+	}
+
+	// Unless the capture pipeline repairs it
+	cam, _ = NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL, TolerateMissingEOI: true})
+	if _, err := cam.Capture(context.Background()); err != nil {
+		t.Errorf("Capture() with TolerateMissingEOI error = %v", err)
+	}
+
+	// Chunked bodies are cut off at the size limit
+	cam, _ = NewHTTPCamera(Config{ID: "cam", SnapshotURL: chunkedServer(t, 0, head, tail).URL, MaxSnapshotBytes: 8})
+	if _, err := cam.Capture(context.Background()); !errors.As(err, &captureErr) || !strings.Contains(captureErr.Message, "limit") {
+		t.Errorf("Capture() error = %v, want the size limit", err)
+	}
+}
+
+func TestHTTPCamera_Capture_SlowChunkedHonorsDeadline(t *testing.T) {
+	server := chunkedServer(t, 10*time.Second, []byte{0xFF, 0xD8}, []byte{0xFF, 0xD9})
+	cam, _ := NewHTTPCamera(Config{ID: "cam", SnapshotURL: server.URL, TimeoutSeconds: 30})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := cam.Capture(ctx)
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Errorf("Capture() error = %v, want TimeoutError", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Capture() took %v, should stop at the deadline", elapsed)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/korylprince/go-onvif"
@@ -21,6 +22,16 @@ type ONVIFCamera struct {
 	snapshotURI string // Cached snapshot URI
 	mediaXAddr  string // Media service XAddr
 	mediaNS     string // Cached media namespace (v1 or v2)
+
+	// Event subscription (see onvif_events.go)
+	eventClient      *onvif.Client
+	eventsAddr       string // Cached event service XAddr
+	eventLifetime    time.Duration
+	eventPullTimeout time.Duration
+	eventRetryMin    time.Duration
+	eventRetryMax    time.Duration
+	eventsMu         sync.Mutex
+	eventStatus      EventStatus
 }
 
 // NewONVIFCamera creates a new ONVIF camera instance.
@@ -44,8 +55,13 @@ func NewONVIFCamera(config Config) (*ONVIFCamera, error) {
 		timeout = 15 * time.Second // Default timeout
 	}
 
-	httpClient := &http.Client{
-		Timeout: timeout,
+	httpClient, err := newHTTPClient(config.TLS, timeout)
+	if err != nil {
+		return nil, err
+	}
+	eventClient, err := newEventClient(config.ONVIF, config.TLS, timeout, defaultEventPullTimeout)
+	if err != nil {
+		return nil, err
 	}
 
 	onvifClient := &onvif.Client{
@@ -55,9 +71,14 @@ func NewONVIFCamera(config Config) (*ONVIFCamera, error) {
 	}
 
 	return &ONVIFCamera{
-		config:      config,
-		httpClient:  httpClient,
-		onvifClient: onvifClient,
+		config:           config,
+		httpClient:       httpClient,
+		onvifClient:      onvifClient,
+		eventClient:      eventClient,
+		eventLifetime:    defaultEventLifetime,
+		eventPullTimeout: defaultEventPullTimeout,
+		eventRetryMin:    defaultEventRetryMin,
+		eventRetryMax:    defaultEventRetryMax,
 	}, nil
 }
 
@@ -161,6 +182,10 @@ func (c *ONVIFCamera) Capture(ctx context.Context) ([]byte, error) {
 				}
 			}
 
+			if err := checkHeaderRules(c.config.ID, c.config.FailHeaders, retryResp.Header); err != nil {
+				return nil, err
+			}
+
 			// Read image data from retry response
 			retryData, retryErr := io.ReadAll(retryResp.Body)
 			if retryErr != nil {
@@ -209,6 +234,10 @@ func (c *ONVIFCamera) Capture(ctx context.Context) ([]byte, error) {
 		}
 	}
 
+	if err := checkHeaderRules(c.config.ID, c.config.FailHeaders, resp.Header); err != nil {
+		return nil, err
+	}
+
 	// Read image data
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
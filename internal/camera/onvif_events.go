@@ -0,0 +1,349 @@
+package camera
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/korylprince/go-onvif"
+	"github.com/korylprince/go-onvif/soap"
+)
+
+// namespaceWSNotification is the WS-BaseNotification namespace used by Renew/Unsubscribe
+const namespaceWSNotification = "http://docs.oasis-open.org/wsn/b-2"
+
+// PullPoint subscription defaults
+const (
+	defaultEventLifetime    = 60 * time.Second // Requested subscription lifetime
+	defaultEventPullTimeout = 5 * time.Second  // Long-poll time per PullMessages
+	defaultEventRetryMin    = 5 * time.Second
+	defaultEventRetryMax    = 2 * time.Minute
+	eventMessageLimit       = 16
+)
+
+// pullPoint is an established PullPoint subscription
+type pullPoint struct {
+	address   string
+	expiresAt time.Time // Local clock; camera clocks are often wrong
+}
+
+// EventsEnabled reports whether event topics are configured
+func (c *ONVIFCamera) EventsEnabled() bool {
+	return len(c.config.ONVIF.EventTopics) > 0
+}
+
+// EventStatus returns a snapshot of the event subscription state
+func (c *ONVIFCamera) EventStatus() EventStatus {
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+	status := c.eventStatus
+	status.Topics = append([]string(nil), c.config.ONVIF.EventTopics...)
+	return status
+}
+
+// WatchEvents subscribes to the camera's event service and pulls messages until ctx is
+// done, renewing the subscription before it lapses and resubscribing with backoff after
+// any failure. Interval capture is unaffected, so a broken subscription only loses the
+// extra event-triggered frames.
+func (c *ONVIFCamera) WatchEvents(ctx context.Context, trigger func(topic string)) {
+	if !c.EventsEnabled() {
+		return
+	}
+
+	retry := c.eventRetryMin
+	for ctx.Err() == nil {
+		sub, err := c.subscribe()
+		if err != nil {
+			c.recordEventError(fmt.Errorf("subscribe: %w", err))
+			if !sleepCtx(ctx, retry) {
+				return
+			}
+			retry = min(retry*2, c.eventRetryMax)
+			continue
+		}
+		retry = c.eventRetryMin
+
+		err = c.pullLoop(ctx, sub, trigger)
+		c.unsubscribe(sub)
+		c.eventsMu.Lock()
+		c.eventStatus.Active = false
+		c.eventsMu.Unlock()
+		if err != nil {
+			c.recordEventError(err)
+			if !sleepCtx(ctx, retry) {
+				return
+			}
+		}
+	}
+}
+
+// pullLoop pulls messages from an established subscription until ctx is done (nil) or
+// a pull/renew fails
+func (c *ONVIFCamera) pullLoop(ctx context.Context, sub *pullPoint, trigger func(topic string)) error {
+	for ctx.Err() == nil {
+		// Renew once a third of the lifetime remains so one slow pull cannot let it lapse
+		if time.Until(sub.expiresAt) < c.eventLifetime/3 {
+			if err := c.renew(sub); err != nil {
+				return fmt.Errorf("renew: %w", err)
+			}
+		}
+
+		messages, err := c.pullMessages(sub)
+		if err != nil {
+			return fmt.Errorf("pull messages: %w", err)
+		}
+		for _, msg := range messages {
+			if topic, ok := c.matchEvent(msg); ok {
+				c.eventsMu.Lock()
+				c.eventStatus.EventsMatched++
+				c.eventStatus.LastEventTime = time.Now()
+				c.eventStatus.LastEventTopic = topic
+				c.eventsMu.Unlock()
+				trigger(topic)
+			}
+		}
+	}
+	return nil
+}
+
+// eventsXAddr discovers the event service address (cached)
+func (c *ONVIFCamera) eventsXAddr() (string, error) {
+	if c.eventsAddr != "" {
+		return c.eventsAddr, nil
+	}
+	services, err := c.eventClient.GetServices(c.config.ONVIF.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("get services: %w", err)
+	}
+	addr := services.URL(onvif.NamespaceEvents)
+	if addr == "" {
+		return "", fmt.Errorf("event service not found")
+	}
+	c.eventsAddr = addr
+	return addr, nil
+}
+
+// subscribe creates a PullPoint subscription
+func (c *ONVIFCamera) subscribe() (*pullPoint, error) {
+	addr, err := c.eventsXAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	type CreatePullPointSubscription struct {
+		XMLName                xml.Name `xml:"tev:CreatePullPointSubscription"`
+		InitialTerminationTime string   `xml:"tev:InitialTerminationTime"`
+	}
+
+	envelope, err := c.eventClient.Do(&onvif.Request{
+		URL:        addr,
+		Namespaces: soap.Namespaces{"tev": onvif.NamespaceEvents},
+		Body:       &CreatePullPointSubscription{InitialTerminationTime: xsdDuration(c.eventLifetime)},
+	})
+	if err != nil {
+		// The service address may have changed (e.g. DHCP); rediscover next time
+		c.eventsAddr = ""
+		return nil, err
+	}
+
+	type CreatePullPointSubscriptionResponse struct {
+		XMLName         xml.Name `xml:"CreatePullPointSubscriptionResponse"`
+		Address         string   `xml:"SubscriptionReference>Address"`
+		CurrentTime     string   `xml:"CurrentTime"`
+		TerminationTime string   `xml:"TerminationTime"`
+	}
+
+	var resp CreatePullPointSubscriptionResponse
+	if err := envelope.Body.Unmarshal(&resp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	address := strings.TrimSpace(resp.Address)
+	if address == "" {
+		return nil, fmt.Errorf("subscription address not found in response")
+	}
+
+	sub := &pullPoint{
+		address:   address,
+		expiresAt: time.Now().Add(grantedLifetime(resp.CurrentTime, resp.TerminationTime, c.eventLifetime)),
+	}
+
+	now := time.Now()
+	c.eventsMu.Lock()
+	c.eventStatus.Active = true
+	c.eventStatus.SubscribedAt = now
+	c.eventStatus.LastRenewal = now
+	c.eventStatus.ExpiresAt = sub.expiresAt
+	c.eventStatus.Subscriptions++
+	c.eventsMu.Unlock()
+	return sub, nil
+}
+
+// renew extends the subscription lifetime
+func (c *ONVIFCamera) renew(sub *pullPoint) error {
+	type Renew struct {
+		XMLName         xml.Name `xml:"wsnt:Renew"`
+		TerminationTime string   `xml:"wsnt:TerminationTime"`
+	}
+
+	envelope, err := c.eventClient.Do(&onvif.Request{
+		URL:        sub.address,
+		Namespaces: soap.Namespaces{"wsnt": namespaceWSNotification},
+		Body:       &Renew{TerminationTime: xsdDuration(c.eventLifetime)},
+	})
+	if err != nil {
+		return err
+	}
+
+	type RenewResponse struct {
+		XMLName         xml.Name `xml:"RenewResponse"`
+		CurrentTime     string   `xml:"CurrentTime"`
+		TerminationTime string   `xml:"TerminationTime"`
+	}
+
+	var resp RenewResponse
+	if err := envelope.Body.Unmarshal(&resp); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+
+	now := time.Now()
+	sub.expiresAt = now.Add(grantedLifetime(resp.CurrentTime, resp.TerminationTime, c.eventLifetime))
+	c.eventsMu.Lock()
+	c.eventStatus.LastRenewal = now
+	c.eventStatus.ExpiresAt = sub.expiresAt
+	c.eventsMu.Unlock()
+	return nil
+}
+
+// unsubscribe releases the subscription on the camera (best-effort; it expires anyway)
+func (c *ONVIFCamera) unsubscribe(sub *pullPoint) {
+	type Unsubscribe struct {
+		XMLName xml.Name `xml:"wsnt:Unsubscribe"`
+	}
+	c.eventClient.Do(&onvif.Request{
+		URL:        sub.address,
+		Namespaces: soap.Namespaces{"wsnt": namespaceWSNotification},
+		Body:       &Unsubscribe{},
+	})
+}
+
+// notificationMessage is one event from a PullMessages response
+type notificationMessage struct {
+	Topic string       `xml:"Topic"`
+	Data  []simpleItem `xml:"Message>Message>Data>SimpleItem"`
+}
+
+type simpleItem struct {
+	Name  string `xml:"Name,attr"`
+	Value string `xml:"Value,attr"`
+}
+
+// pullMessages long-polls the subscription for new events
+func (c *ONVIFCamera) pullMessages(sub *pullPoint) ([]notificationMessage, error) {
+	type PullMessages struct {
+		XMLName      xml.Name `xml:"tev:PullMessages"`
+		Timeout      string   `xml:"tev:Timeout"`
+		MessageLimit int      `xml:"tev:MessageLimit"`
+	}
+
+	envelope, err := c.eventClient.Do(&onvif.Request{
+		URL:        sub.address,
+		Namespaces: soap.Namespaces{"tev": onvif.NamespaceEvents},
+		Body:       &PullMessages{Timeout: xsdDuration(c.eventPullTimeout), MessageLimit: eventMessageLimit},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type PullMessagesResponse struct {
+		XMLName  xml.Name              `xml:"PullMessagesResponse"`
+		Messages []notificationMessage `xml:"NotificationMessage"`
+	}
+
+	var resp PullMessagesResponse
+	if err := envelope.Body.Unmarshal(&resp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	return resp.Messages, nil
+}
+
+// matchEvent reports whether a message is a configured topic in its active state.
+// State events (e.g. IsMotion) are sent on both edges; a "false" data item is the
+// end of the event and does not trigger a capture.
+func (c *ONVIFCamera) matchEvent(msg notificationMessage) (string, bool) {
+	topic := strings.TrimSpace(msg.Topic)
+	lower := strings.ToLower(topic)
+
+	matched := false
+	for _, want := range c.config.ONVIF.EventTopics {
+		if want != "" && strings.Contains(lower, strings.ToLower(want)) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return "", false
+	}
+
+	for _, item := range msg.Data {
+		if strings.EqualFold(strings.TrimSpace(item.Value), "false") {
+			return "", false
+		}
+	}
+	return topic, true
+}
+
+func (c *ONVIFCamera) recordEventError(err error) {
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+	c.eventStatus.Active = false
+	c.eventStatus.LastError = err.Error()
+	c.eventStatus.LastErrorTime = time.Now()
+}
+
+// grantedLifetime returns how long the camera granted the subscription for, using the
+// difference of its own timestamps so camera clock skew does not matter. Falls back to
+// the requested lifetime when the camera omits or garbles them.
+func grantedLifetime(current, termination string, requested time.Duration) time.Duration {
+	cur, err1 := time.Parse(time.RFC3339, strings.TrimSpace(current))
+	term, err2 := time.Parse(time.RFC3339, strings.TrimSpace(termination))
+	if err1 != nil || err2 != nil {
+		return requested
+	}
+	if lifetime := term.Sub(cur); lifetime > 0 {
+		return lifetime
+	}
+	return requested
+}
+
+// xsdDuration formats d as an XML Schema duration (e.g. PT60S)
+func xsdDuration(d time.Duration) string {
+	return fmt.Sprintf("PT%dS", int(d.Round(time.Second).Seconds()))
+}
+
+// sleepCtx waits for d, returning false if ctx ends first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// newEventClient returns an ONVIF client whose HTTP timeout leaves room for the
+// PullMessages long-poll on top of the normal request timeout
+func newEventClient(cfg *ONVIFConfig, tlsConfig *TLSConfig, requestTimeout, pullTimeout time.Duration) (*onvif.Client, error) {
+	httpClient, err := newHTTPClient(tlsConfig, requestTimeout+pullTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &onvif.Client{
+		Username:   cfg.Username,
+		Password:   cfg.Password,
+		HTTPClient: httpClient,
+	}, nil
+}
@@ -0,0 +1,240 @@
+package camera
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const soapEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope" xmlns:tds="http://www.onvif.org/ver10/device/wsdl" xmlns:tev="http://www.onvif.org/ver10/events/wsdl" xmlns:wsnt="http://docs.oasis-open.org/wsn/b-2" xmlns:wsa="http://www.w3.org/2005/08/addressing" xmlns:tt="http://www.onvif.org/ver10/schema"><env:Body>%s</env:Body></env:Envelope>`
+
+const soapFault = `<env:Fault><env:Code><env:Value>env:Receiver</env:Value></env:Code><env:Reason><env:Text xml:lang="en">%s</env:Text></env:Reason></env:Fault>`
+
+func motionMessage(isMotion string) string {
+	return `<wsnt:NotificationMessage><wsnt:Topic Dialect="http://www.onvif.org/ver10/tev/topicExpression/ConcreteSet">tns1:RuleEngine/CellMotionDetector/Motion</wsnt:Topic>` +
+		`<wsnt:Message><tt:Message UtcTime="2025-01-01T00:00:00Z" PropertyOperation="Changed"><tt:Source><tt:SimpleItem Name="VideoSourceConfigurationToken" Value="1"/></tt:Source>` +
+		`<tt:Data><tt:SimpleItem Name="IsMotion" Value="` + isMotion + `"/></tt:Data></tt:Message></wsnt:Message></wsnt:NotificationMessage>`
+}
+
+// fakeEventCamera is a minimal ONVIF device with an event service
+type fakeEventCamera struct {
+	mu            sync.Mutex
+	pulls         int
+	pullResponses []string // Message XML per pull; "fault" fails the pull
+	subscriptions atomic.Int32
+	renewals      atomic.Int32
+	unsubscribes  atomic.Int32
+	grant         time.Duration
+}
+
+func (f *fakeEventCamera) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := string(body)
+		now := time.Now().UTC()
+		var resp string
+
+		switch {
+		case strings.Contains(req, "GetServices"):
+			resp = `<tds:GetServicesResponse><tds:Service><tds:Namespace>http://www.onvif.org/ver10/events/wsdl</tds:Namespace>` +
+				`<tds:XAddr>http://` + r.Host + `/onvif/events</tds:XAddr></tds:Service></tds:GetServicesResponse>`
+		case strings.Contains(req, "CreatePullPointSubscription"):
+			f.subscriptions.Add(1)
+			resp = `<tev:CreatePullPointSubscriptionResponse><tev:SubscriptionReference><wsa:Address>http://` + r.Host + `/onvif/subscription/1</wsa:Address></tev:SubscriptionReference>` +
+				`<wsnt:CurrentTime>` + now.Format(time.RFC3339) + `</wsnt:CurrentTime><wsnt:TerminationTime>` + now.Add(f.grant).Format(time.RFC3339) + `</wsnt:TerminationTime></tev:CreatePullPointSubscriptionResponse>`
+		case strings.Contains(req, "Renew"):
+			f.renewals.Add(1)
+			resp = `<wsnt:RenewResponse><wsnt:TerminationTime>` + now.Add(f.grant).Format(time.RFC3339) + `</wsnt:TerminationTime><wsnt:CurrentTime>` + now.Format(time.RFC3339) + `</wsnt:CurrentTime></wsnt:RenewResponse>`
+		case strings.Contains(req, "Unsubscribe"):
+			f.unsubscribes.Add(1)
+			resp = `<wsnt:UnsubscribeResponse/>`
+		case strings.Contains(req, "PullMessages"):
+			f.mu.Lock()
+			var messages string
+			if f.pulls < len(f.pullResponses) {
+				messages = f.pullResponses[f.pulls]
+			}
+			f.pulls++
+			f.mu.Unlock()
+			if messages == "fault" {
+				resp = fmt.Sprintf(soapFault, "subscription expired")
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+			resp = `<tev:PullMessagesResponse><tev:CurrentTime>` + now.Format(time.RFC3339) + `</tev:CurrentTime><tev:TerminationTime>` + now.Add(f.grant).Format(time.RFC3339) + `</tev:TerminationTime>` + messages + `</tev:PullMessagesResponse>`
+		default:
+			t.Errorf("unexpected request: %s", req)
+		}
+
+		w.Header().Set("Content-Type", "application/soap+xml")
+		fmt.Fprintf(w, soapEnvelope, resp)
+	}
+}
+
+func newTestEventCamera(t *testing.T, fake *fakeEventCamera, topics ...string) *ONVIFCamera {
+	server := httptest.NewServer(fake.handler(t))
+	t.Cleanup(server.Close)
+
+	cam, err := NewONVIFCamera(Config{
+		ID:   "onvif-events",
+		Type: "onvif",
+		ONVIF: &ONVIFConfig{
+			Endpoint:    strings.TrimPrefix(server.URL, "http://"),
+			Username:    "admin",
+			Password:    "secret",
+			EventTopics: topics,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewONVIFCamera: %v", err)
+	}
+	cam.eventRetryMin = 10 * time.Millisecond
+	cam.eventRetryMax = 50 * time.Millisecond
+	return cam
+}
+
+func TestONVIFCamera_WatchEvents(t *testing.T) {
+	fake := &fakeEventCamera{
+		grant:         time.Minute,
+		pullResponses: []string{"", motionMessage("true"), motionMessage("false")},
+	}
+	cam := newTestEventCamera(t, fake, "cellmotiondetector")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	triggered := make(chan string, 4)
+	done := make(chan struct{})
+	go func() {
+		cam.WatchEvents(ctx, func(topic string) { triggered <- topic })
+		close(done)
+	}()
+
+	select {
+	case topic := <-triggered:
+		if topic != "tns1:RuleEngine/CellMotionDetector/Motion" {
+			t.Errorf("topic = %q", topic)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected motion event to trigger a capture")
+	}
+
+	status := cam.EventStatus()
+	if !status.Active || status.Subscriptions != 1 || status.EventsMatched != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if len(status.Topics) != 1 || status.Topics[0] != "cellmotiondetector" {
+		t.Errorf("Topics = %v", status.Topics)
+	}
+
+	cancel()
+	<-done
+	if fake.unsubscribes.Load() != 1 {
+		t.Errorf("expected unsubscribe on stop, got %d", fake.unsubscribes.Load())
+	}
+	select {
+	case topic := <-triggered:
+		t.Errorf("motion end should not trigger, got %q", topic)
+	default:
+	}
+	if cam.EventStatus().Active {
+		t.Error("subscription should be inactive after stop")
+	}
+}
+
+func TestONVIFCamera_WatchEventsRenewsAndResubscribes(t *testing.T) {
+	// Granted lifetime below a third of the requested one forces a renewal every pull
+	fake := &fakeEventCamera{
+		grant:         time.Second,
+		pullResponses: []string{"", "", "fault", "", motionMessage("true")},
+	}
+	cam := newTestEventCamera(t, fake, "CellMotionDetector")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	triggered := make(chan string, 4)
+	go cam.WatchEvents(ctx, func(topic string) { triggered <- topic })
+
+	select {
+	case <-triggered:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected capture trigger after resubscribing")
+	}
+
+	if got := fake.subscriptions.Load(); got != 2 {
+		t.Errorf("subscriptions = %d, want 2 (resubscribe after failed pull)", got)
+	}
+	if fake.renewals.Load() == 0 {
+		t.Error("expected subscription to be renewed")
+	}
+	status := cam.EventStatus()
+	if !strings.Contains(status.LastError, "pull messages") || status.LastErrorTime.IsZero() {
+		t.Errorf("expected pull failure in status, got %+v", status)
+	}
+}
+
+func TestONVIFCamera_EventsDisabled(t *testing.T) {
+	cam, err := NewONVIFCamera(Config{
+		ID:    "no-events",
+		ONVIF: &ONVIFConfig{Endpoint: "127.0.0.1:1", Username: "u", Password: "p"},
+	})
+	if err != nil {
+		t.Fatalf("NewONVIFCamera: %v", err)
+	}
+	if cam.EventsEnabled() {
+		t.Error("events should be disabled without topics")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		cam.WatchEvents(context.Background(), func(string) {})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("WatchEvents should return immediately when disabled")
+	}
+}
+
+func TestONVIFCamera_MatchEvent(t *testing.T) {
+	cam := &ONVIFCamera{config: Config{ONVIF: &ONVIFConfig{EventTopics: []string{"CellMotionDetector", "FieldDetector"}}}}
+
+	tests := []struct {
+		name  string
+		msg   notificationMessage
+		match bool
+	}{
+		{"motion start", notificationMessage{Topic: "tns1:RuleEngine/CellMotionDetector/Motion", Data: []simpleItem{{"IsMotion", "true"}}}, true},
+		{"motion end", notificationMessage{Topic: "tns1:RuleEngine/CellMotionDetector/Motion", Data: []simpleItem{{"IsMotion", "false"}}}, false},
+		{"no data", notificationMessage{Topic: "tns1:RuleEngine/FieldDetector/ObjectsInside"}, true},
+		{"case insensitive", notificationMessage{Topic: "tns1:ruleengine/cellmotiondetector/motion"}, true},
+		{"other topic", notificationMessage{Topic: "tns1:VideoSource/ImageTooDark", Data: []simpleItem{{"State", "true"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := cam.matchEvent(tt.msg); ok != tt.match {
+				t.Errorf("matchEvent = %v, want %v", ok, tt.match)
+			}
+		})
+	}
+}
+
+func TestGrantedLifetime(t *testing.T) {
+	// Camera clock an hour off still yields the granted duration
+	if got := grantedLifetime("2020-01-01T01:00:00Z", "2020-01-01T01:01:30Z", time.Minute); got != 90*time.Second {
+		t.Errorf("got %v, want 90s", got)
+	}
+	if got := grantedLifetime("", "garbage", time.Minute); got != time.Minute {
+		t.Errorf("got %v, want requested lifetime", got)
+	}
+	if got := xsdDuration(90 * time.Second); got != "PT90S" {
+		t.Errorf("xsdDuration = %q", got)
+	}
+}
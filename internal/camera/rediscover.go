@@ -0,0 +1,339 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rediscovery defaults; probes are rate limited so a camera that is simply off does
+// not flood the network with multicast
+const (
+	DefaultRediscoveryThreshold = 3
+	DefaultRediscoveryInterval  = 15 * time.Minute
+	rediscoveryProbeWait        = 3 * time.Second
+)
+
+// RediscoveryConfig relocates a camera whose DHCP address changed, finding it by a
+// stable identifier in WS-Discovery replies
+type RediscoveryConfig struct {
+	DeviceID         string        // MAC address, serial number or endpoint UUID
+	FailureThreshold int           // Consecutive connection failures before probing. Default: 3
+	MinInterval      time.Duration // Minimum time between probes. Default: 15m
+}
+
+// RediscoveryStatus describes where a rediscovering camera is being reached
+type RediscoveryStatus struct {
+	DeviceID            string    `json:"device_id"`
+	ConfiguredHost      string    `json:"configured_host"`
+	CurrentHost         string    `json:"current_host"`         // Differs from configured_host after a relocation
+	ConsecutiveFailures int       `json:"consecutive_failures"` // Connection failures since the last good capture
+	Probes              int64     `json:"probes"`
+	Relocations         int64     `json:"relocations"`
+	LastProbe           time.Time `json:"last_probe,omitempty"`
+	LastRelocation      time.Time `json:"last_relocation,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+// Rediscoverer is implemented by cameras that relocate themselves after an address change
+type Rediscoverer interface {
+	Camera
+
+	// RediscoveryStatus reports the configured and current address and probe history
+	RediscoveryStatus() RediscoveryStatus
+}
+
+// RediscoveringCamera wraps a camera and, after repeated connection failures, probes
+// the local network for the same device. When it answers from a new address the
+// camera is recreated with that host in its URLs; the config itself is not changed.
+type RediscoveringCamera struct {
+	config    Config
+	rd        RediscoveryConfig
+	newCamera func(Config) (Camera, error)
+	discover  func(context.Context, time.Duration) ([]DiscoveredDevice, error)
+
+	mu      sync.Mutex
+	cam     Camera
+	changed chan struct{} // Closed when cam is replaced
+	probing bool
+	status  RediscoveryStatus
+}
+
+// NewRediscoveringCamera wraps cam, created from cfg, with rediscovery
+func NewRediscoveringCamera(cam Camera, cfg Config, rd RediscoveryConfig) *RediscoveringCamera {
+	if rd.FailureThreshold <= 0 {
+		rd.FailureThreshold = DefaultRediscoveryThreshold
+	}
+	if rd.MinInterval <= 0 {
+		rd.MinInterval = DefaultRediscoveryInterval
+	}
+	host := urlHost(primaryURL(cfg))
+	return &RediscoveringCamera{
+		config:    cfg,
+		rd:        rd,
+		newCamera: NewCamera,
+		discover:  DiscoverONVIF,
+		cam:       cam,
+		changed:   make(chan struct{}),
+		status:    RediscoveryStatus{DeviceID: rd.DeviceID, ConfiguredHost: host, CurrentHost: host},
+	}
+}
+
+// current returns the camera for the current address
+func (c *RediscoveringCamera) current() Camera {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cam
+}
+
+// Capture fetches a frame, relocating the camera and retrying once if it could not
+// be reached often enough
+func (c *RediscoveringCamera) Capture(ctx context.Context) ([]byte, error) {
+	data, err := c.current().Capture(ctx)
+	if c.recordResult(err) && c.relocate(ctx) {
+		data, err = c.current().Capture(ctx)
+		c.recordResult(err)
+	}
+	return data, err
+}
+
+// CaptureTo streams a frame when the current camera supports it
+func (c *RediscoveringCamera) CaptureTo(ctx context.Context, w io.Writer) (int64, error) {
+	n, err := c.captureTo(ctx, w)
+	if n == 0 && c.recordResult(err) && c.relocate(ctx) {
+		n, err = c.captureTo(ctx, w)
+		c.recordResult(err)
+	}
+	return n, err
+}
+
+func (c *RediscoveringCamera) captureTo(ctx context.Context, w io.Writer) (int64, error) {
+	cam := c.current()
+	if streamer, ok := cam.(StreamingCamera); ok {
+		return streamer.CaptureTo(ctx, w)
+	}
+	data, err := cam.Capture(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// recordResult counts connection failures and reports whether a probe is due. Other
+// errors leave the count alone: the camera answered, or the failure says nothing
+// about its address.
+func (c *RediscoveringCamera) recordResult(err error) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.status.ConsecutiveFailures = 0
+		return false
+	}
+	if !isConnectionError(err) {
+		return false
+	}
+	c.status.ConsecutiveFailures++
+	return c.status.ConsecutiveFailures >= c.rd.FailureThreshold
+}
+
+// relocate probes for the device and switches to its new address. Returns true if
+// the camera now points somewhere new.
+func (c *RediscoveringCamera) relocate(ctx context.Context) bool {
+	c.mu.Lock()
+	if c.probing || time.Since(c.status.LastProbe) < c.rd.MinInterval {
+		c.mu.Unlock()
+		return false
+	}
+	c.probing = true
+	c.status.LastProbe = time.Now()
+	c.status.Probes++
+	currentHost := c.status.CurrentHost
+	c.mu.Unlock()
+
+	host, cam, err := c.find(ctx, currentHost)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.probing = false
+	if err != nil {
+		c.status.LastError = err.Error()
+		return false
+	}
+	c.cam = cam
+	close(c.changed)
+	c.changed = make(chan struct{})
+	c.status.CurrentHost = host
+	c.status.Relocations++
+	c.status.LastRelocation = time.Now()
+	c.status.ConsecutiveFailures = 0
+	c.status.LastError = ""
+	return true
+}
+
+// find looks for the device on the network and builds a camera for its new host
+func (c *RediscoveringCamera) find(ctx context.Context, currentHost string) (string, Camera, error) {
+	devices, err := c.discover(ctx, rediscoveryProbeWait)
+	if err != nil {
+		return "", nil, fmt.Errorf("discovery probe: %w", err)
+	}
+	for _, d := range devices {
+		if !d.Matches(c.rd.DeviceID) {
+			continue
+		}
+		host := d.Host()
+		if host == "" {
+			return "", nil, fmt.Errorf("device %s answered without a service address", c.rd.DeviceID)
+		}
+		if host == currentHost {
+			return "", nil, fmt.Errorf("device %s is still at %s", c.rd.DeviceID, host)
+		}
+		cam, err := c.newCamera(relocatedConfig(c.config, host))
+		if err != nil {
+			return "", nil, fmt.Errorf("create camera at %s: %w", host, err)
+		}
+		return host, cam, nil
+	}
+	return "", nil, fmt.Errorf("device %s not found among %d discovered devices", c.rd.DeviceID, len(devices))
+}
+
+// RediscoveryStatus returns the configured and current address and probe history
+func (c *RediscoveringCamera) RediscoveryStatus() RediscoveryStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// ID returns the camera identifier
+func (c *RediscoveringCamera) ID() string { return c.config.ID }
+
+// Type returns the camera type
+func (c *RediscoveringCamera) Type() string { return c.config.Type }
+
+// Unwrap returns the camera for the current address
+func (c *RediscoveringCamera) Unwrap() Camera { return c.current() }
+
+// EventsEnabled reports whether the current camera has event-triggered capture
+func (c *RediscoveringCamera) EventsEnabled() bool {
+	src, ok := c.current().(EventSource)
+	return ok && src.EventsEnabled()
+}
+
+// WatchEvents watches the current camera's events, moving the subscription to the
+// new address after a relocation
+func (c *RediscoveringCamera) WatchEvents(ctx context.Context, trigger func(topic string)) {
+	for {
+		c.mu.Lock()
+		cam, changed := c.cam, c.changed
+		c.mu.Unlock()
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if src, ok := cam.(EventSource); ok && src.EventsEnabled() {
+				src.WatchEvents(watchCtx, trigger)
+			} else {
+				<-watchCtx.Done()
+			}
+		}()
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return
+		case <-changed:
+			cancel()
+			<-done
+		}
+	}
+}
+
+// EventStatus reports the current camera's event subscription
+func (c *RediscoveringCamera) EventStatus() EventStatus {
+	if src, ok := c.current().(EventSource); ok {
+		return src.EventStatus()
+	}
+	return EventStatus{}
+}
+
+// isConnectionError reports whether err means the camera could not be reached, as
+// opposed to it answering with an error
+func isConnectionError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var timeout *TimeoutError
+	var netErr net.Error
+	if errors.As(err, &timeout) || errors.As(err, &netErr) {
+		return true
+	}
+	// ffmpeg reports RTSP connection failures only in its output
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"connection refused", "no route to host", "host is down", "network is unreachable", "connection timed out"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// primaryURL returns the URL that identifies the camera's address for its type
+func primaryURL(cfg Config) string {
+	switch {
+	case cfg.Type == "onvif" && cfg.ONVIF != nil:
+		return cfg.ONVIF.Endpoint
+	case cfg.Type == "rtsp" && cfg.RTSP != nil:
+		return cfg.RTSP.URL
+	}
+	return cfg.SnapshotURL
+}
+
+// relocatedConfig returns cfg with the host of every camera URL replaced, keeping
+// ports, paths and credentials
+func relocatedConfig(cfg Config, host string) Config {
+	cfg.SnapshotURL = replaceURLHost(cfg.SnapshotURL, host)
+	if cfg.ONVIF != nil {
+		onvif := *cfg.ONVIF
+		onvif.Endpoint = replaceURLHost(onvif.Endpoint, host)
+		cfg.ONVIF = &onvif
+	}
+	if cfg.RTSP != nil {
+		rtsp := *cfg.RTSP
+		rtsp.URL = replaceURLHost(rtsp.URL, host)
+		cfg.RTSP = &rtsp
+	}
+	return cfg
+}
+
+// replaceURLHost swaps the host of raw, keeping its port
+func replaceURLHost(raw, host string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+	if port := u.Port(); port != "" {
+		u.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	} else {
+		u.Host = host
+	}
+	return u.String()
+}
+
+// urlHost returns the host name of raw, or "" if it has none
+func urlHost(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
@@ -0,0 +1,159 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDiscoverONVIF(t *testing.T) {
+	responder, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer responder.Close()
+	go func() {
+		buf := make([]byte, 8192)
+		n, from, err := responder.ReadFrom(buf)
+		if err != nil || !strings.Contains(string(buf[:n]), "NetworkVideoTransmitter") {
+			return
+		}
+		reply := `<?xml version="1.0"?>
+<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://www.w3.org/2003/05/soap-envelope" xmlns:d="http://schemas.xmlsoap.org/ws/2005/04/discovery" xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing">
+<SOAP-ENV:Body><d:ProbeMatches><d:ProbeMatch>
+<a:EndpointReference><a:Address>urn:uuid:2419d68a-2dd2-21b2-a205-ac:cc:8e:12:34:56</a:Address></a:EndpointReference>
+<d:Scopes>onvif://www.onvif.org/name/North onvif://www.onvif.org/hardware/M3045</d:Scopes>
+<d:XAddrs>http://192.168.1.57/onvif/device_service</d:XAddrs>
+</d:ProbeMatch></d:ProbeMatches></SOAP-ENV:Body></SOAP-ENV:Envelope>`
+		responder.WriteTo([]byte(reply), from)
+		responder.WriteTo([]byte(reply), from) // Duplicate replies are common
+	}()
+
+	orig := wsDiscoveryAddr
+	wsDiscoveryAddr = responder.LocalAddr().String()
+	defer func() { wsDiscoveryAddr = orig }()
+
+	devices, err := DiscoverONVIF(context.Background(), 300*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DiscoverONVIF: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Fatalf("devices = %+v, want 1", devices)
+	}
+	d := devices[0]
+	if d.Host() != "192.168.1.57" || len(d.Scopes) != 2 {
+		t.Errorf("device = %+v", d)
+	}
+	if !d.Matches("AC-CC-8E-12-34-56") || !d.Matches("2419D68A") || d.Matches("ac:cc:8e:00:00:00") {
+		t.Error("device id matching ignores case and separators only")
+	}
+}
+
+func TestRelocatedConfig(t *testing.T) {
+	cfg := Config{
+		SnapshotURL: "http://10.0.0.5:8080/snap.jpg?res=hd",
+		ONVIF:       &ONVIFConfig{Endpoint: "http://10.0.0.5/onvif/device_service"},
+		RTSP:        &RTSPConfig{URL: "rtsp://admin:pw@10.0.0.5:554/stream1"},
+	}
+	got := relocatedConfig(cfg, "10.0.0.9")
+	if got.SnapshotURL != "http://10.0.0.9:8080/snap.jpg?res=hd" {
+		t.Errorf("snapshot url = %s", got.SnapshotURL)
+	}
+	if got.ONVIF.Endpoint != "http://10.0.0.9/onvif/device_service" {
+		t.Errorf("onvif endpoint = %s", got.ONVIF.Endpoint)
+	}
+	if got.RTSP.URL != "rtsp://admin:pw@10.0.0.9:554/stream1" {
+		t.Errorf("rtsp url = %s", got.RTSP.URL)
+	}
+	if cfg.RTSP.URL != "rtsp://admin:pw@10.0.0.5:554/stream1" {
+		t.Error("original config must not be modified")
+	}
+}
+
+func TestIsConnectionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&TimeoutError{CameraID: "c"}, true},
+		{&CaptureError{CameraID: "c", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, true},
+		{&CaptureError{CameraID: "c", Message: "ffmpeg capture failed: Connection refused"}, true},
+		{&CaptureError{CameraID: "c", Message: "HTTP status 500"}, false},
+		{&AuthError{CameraID: "c"}, false},
+		{fmt.Errorf("capture: %w", context.Canceled), false},
+	}
+	for _, tt := range tests {
+		if got := isConnectionError(tt.err); got != tt.want {
+			t.Errorf("isConnectionError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// newMovedCamera returns a camera configured at 127.0.0.2, where nothing listens, for
+// a server actually reachable on the same port at 127.0.0.1
+func newMovedCamera(t *testing.T, rd RediscoveryConfig, devices []DiscoveredDevice) *RediscoveringCamera {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("frame"))
+	}))
+	t.Cleanup(server.Close)
+	u, _ := url.Parse(server.URL)
+
+	cfg := Config{ID: "dhcp-cam", Type: "http", SnapshotURL: "http://127.0.0.2:" + u.Port() + "/snap.jpg", TimeoutSeconds: 2}
+	cam, err := NewCamera(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRediscoveringCamera(cam, cfg, rd)
+	r.discover = func(context.Context, time.Duration) ([]DiscoveredDevice, error) { return devices, nil }
+	return r
+}
+
+func TestRediscoveringCamera_Relocates(t *testing.T) {
+	devices := []DiscoveredDevice{
+		{Endpoint: "urn:uuid:other", XAddrs: []string{"http://127.0.0.3/onvif/device_service"}},
+		{Endpoint: "urn:uuid:1234-accc8e123456", XAddrs: []string{"http://127.0.0.1/onvif/device_service"}},
+	}
+	cam := newMovedCamera(t, RediscoveryConfig{DeviceID: "AC:CC:8E:12:34:56", FailureThreshold: 2}, devices)
+
+	if _, err := cam.Capture(context.Background()); err == nil {
+		t.Fatal("first capture should fail at the old address")
+	}
+	if s := cam.RediscoveryStatus(); s.Probes != 0 || s.ConsecutiveFailures != 1 {
+		t.Fatalf("probed before the failure threshold: %+v", s)
+	}
+
+	data, err := cam.Capture(context.Background())
+	if err != nil || string(data) != "frame" {
+		t.Fatalf("capture after relocation = %q, %v", data, err)
+	}
+	s := cam.RediscoveryStatus()
+	if s.ConfiguredHost != "127.0.0.2" || s.CurrentHost != "127.0.0.1" || s.Relocations != 1 || s.ConsecutiveFailures != 0 {
+		t.Errorf("status = %+v", s)
+	}
+	if got := Underlying(cam).(*HTTPCamera).config.SnapshotURL; !strings.HasPrefix(got, "http://127.0.0.1:") {
+		t.Errorf("underlying camera url = %s", got)
+	}
+}
+
+func TestRediscoveringCamera_RateLimitsProbes(t *testing.T) {
+	cam := newMovedCamera(t, RediscoveryConfig{DeviceID: "accc8e123456", FailureThreshold: 1, MinInterval: time.Hour}, nil)
+
+	for i := 0; i < 3; i++ {
+		cam.Capture(context.Background())
+	}
+	s := cam.RediscoveryStatus()
+	if s.Probes != 1 || s.Relocations != 0 {
+		t.Errorf("probes = %d relocations = %d, want one probe per interval", s.Probes, s.Relocations)
+	}
+	if !strings.Contains(s.LastError, "not found") || s.ConsecutiveFailures != 3 {
+		t.Errorf("status = %+v", s)
+	}
+}
@@ -1,18 +1,31 @@
 package camera
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/url"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
+// Stream reconnect backoff defaults
+const (
+	defaultReconnectInitial = 2 * time.Second
+	defaultReconnectMax     = 60 * time.Second
+)
+
 // RTSPCamera implements Camera interface for RTSP stream cameras.
 // Uses ffmpeg to capture a single frame from the RTSP stream.
 type RTSPCamera struct {
 	config Config
+
+	mu        sync.Mutex
+	reconnect ReconnectStatus
 }
 
 // NewRTSPCamera creates a new RTSP camera instance.
@@ -34,6 +47,28 @@ func NewRTSPCamera(config Config) (*RTSPCamera, error) {
 // Captures a single frame and exits immediately (no long-running decoder state).
 // Always returns fresh data - never cached or stale images.
 func (c *RTSPCamera) Capture(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := c.CaptureTo(ctx, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// CaptureTo streams a fresh snapshot from ffmpeg's stdout into w.
+// Used for high-resolution streams so large keyframes can go straight to disk.
+// After a failed connection, the next one waits out the reconnect backoff.
+func (c *RTSPCamera) CaptureTo(ctx context.Context, w io.Writer) (int64, error) {
+	if err := c.waitReconnect(ctx); err != nil {
+		return 0, err
+	}
+	n, err := c.captureFrame(ctx, w)
+	if ctx.Err() == nil {
+		c.recordConnect(err) // A cancelled capture says nothing about the stream
+	}
+	return n, err
+}
+
+func (c *RTSPCamera) captureFrame(ctx context.Context, w io.Writer) (int64, error) {
 	timeout := time.Duration(c.config.TimeoutSeconds) * time.Second
 	if timeout == 0 {
 		timeout = 20 * time.Second // Default RTSP timeout
@@ -43,27 +78,7 @@ func (c *RTSPCamera) Capture(ctx context.Context) ([]byte, error) {
 	captureCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Build final RTSP URL with all modifications
-	rtspURL := c.config.RTSP.URL
-
-	// If substream is preferred and URL supports it, modify URL
-	// (This is camera-specific - some cameras have substream URLs)
-	if c.config.RTSP.Substream {
-		// Try common substream patterns (camera-specific)
-		// Most cameras use stream1 for main, stream2 for substream
-		// This is a best-effort attempt - actual implementation may vary
-		rtspURL = c.modifyURLForSubstream(rtspURL)
-	}
-
-	// Add authentication if provided and not already in URL
-	if c.config.RTSP.Username != "" && c.config.RTSP.Password != "" {
-		if !containsCredentials(rtspURL) {
-			rtspURL = fmt.Sprintf("rtsp://%s:%s@%s",
-				c.config.RTSP.Username,
-				c.config.RTSP.Password,
-				extractHostPath(rtspURL))
-		}
-	}
+	rtspURL := c.streamURL()
 
 	// Build ffmpeg command
 	// -rtsp_transport tcp: Use TCP for more reliable connection
@@ -89,12 +104,15 @@ func (c *RTSPCamera) Capture(ctx context.Context) ([]byte, error) {
 	var stderrBuf strings.Builder
 	cmd.Stderr = &stderrBuf
 
-	// Capture stdout (image data)
-	output, err := cmd.Output()
+	// Stream stdout (image data) to the caller's writer
+	counter := &countingWriter{w: w}
+	cmd.Stdout = counter
+
+	err := cmd.Run()
 	if err != nil {
 		// Check if error is due to timeout
 		if captureCtx.Err() == context.DeadlineExceeded {
-			return nil, &TimeoutError{
+			return 0, &TimeoutError{
 				CameraID: c.config.ID,
 				Timeout:  timeout,
 			}
@@ -109,27 +127,52 @@ func (c *RTSPCamera) Capture(ctx context.Context) ([]byte, error) {
 
 		// Check for authentication errors
 		if isAuthError(err) || strings.Contains(stderrMsg, "401") {
-			return nil, &AuthError{
+			return 0, &AuthError{
 				CameraID: c.config.ID,
 				Message:  "RTSP authentication failed",
 			}
 		}
 
-		return nil, &CaptureError{
+		return 0, &CaptureError{
 			CameraID: c.config.ID,
 			Message:  errMsg,
 			Err:      err,
 		}
 	}
 
-	if len(output) == 0 {
-		return nil, &CaptureError{
+	if counter.n == 0 {
+		return 0, &CaptureError{
 			CameraID: c.config.ID,
 			Message:  "ffmpeg returned empty output",
 		}
 	}
 
-	return output, nil
+	return counter.n, nil
+}
+
+// streamURL builds the final RTSP URL with the substream and credentials applied
+func (c *RTSPCamera) streamURL() string {
+	rtspURL := c.config.RTSP.URL
+
+	// If substream is preferred and URL supports it, modify URL
+	// (This is camera-specific - some cameras have substream URLs)
+	if c.config.RTSP.Substream {
+		// Try common substream patterns (camera-specific)
+		// Most cameras use stream1 for main, stream2 for substream
+		// This is a best-effort attempt - actual implementation may vary
+		rtspURL = c.modifyURLForSubstream(rtspURL)
+	}
+
+	// Add authentication if provided and not already in URL
+	if c.config.RTSP.Username != "" && c.config.RTSP.Password != "" {
+		if !containsCredentials(rtspURL) {
+			rtspURL = fmt.Sprintf("rtsp://%s:%s@%s",
+				c.config.RTSP.Username,
+				c.config.RTSP.Password,
+				extractHostPath(rtspURL))
+		}
+	}
+	return rtspURL
 }
 
 // ID returns the camera identifier
@@ -142,8 +185,93 @@ func (c *RTSPCamera) Type() string {
 	return "rtsp"
 }
 
+// ReconnectStatus returns stream reconnect attempts and the current backoff
+func (c *RTSPCamera) ReconnectStatus() ReconnectStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reconnect
+}
+
+// waitReconnect delays a reconnect until the backoff expires. If the wait would
+// outlast ctx, the capture fails at once without touching the stream.
+func (c *RTSPCamera) waitReconnect(ctx context.Context) error {
+	c.mu.Lock()
+	if c.reconnect.ConsecutiveFailures == 0 {
+		c.mu.Unlock()
+		return nil
+	}
+	wait := time.Until(c.reconnect.NextAttempt)
+	if deadline, ok := ctx.Deadline(); ok && wait > time.Until(deadline) {
+		c.reconnect.Deferred++
+		c.mu.Unlock()
+		return &CaptureError{
+			CameraID: c.config.ID,
+			Message:  fmt.Sprintf("stream reconnect backoff: next attempt in %v", wait.Round(time.Second)),
+		}
+	}
+	c.reconnect.Attempts++
+	c.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordConnect resets the backoff after a frame, or extends it after a failure
+func (c *RTSPCamera) recordConnect(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		c.reconnect.ConsecutiveFailures = 0
+		c.reconnect.Backoff = 0
+		c.reconnect.NextAttempt = time.Time{}
+		return
+	}
+	c.reconnect.ConsecutiveFailures++
+	c.reconnect.Backoff = c.reconnectDelay(c.reconnect.ConsecutiveFailures)
+	c.reconnect.NextAttempt = time.Now().Add(c.reconnect.Backoff)
+	c.reconnect.LastError = err.Error()
+}
+
+// reconnectDelay doubles from the initial delay up to the cap, then randomizes the
+// upper half so cameras behind one recovering NVR spread out their reconnects
+func (c *RTSPCamera) reconnectDelay(failures int) time.Duration {
+	delay, maxDelay := c.config.RTSP.ReconnectInitial, c.config.RTSP.ReconnectMax
+	if delay <= 0 {
+		delay = defaultReconnectInitial
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultReconnectMax
+	}
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
 // Helper functions
 
+// countingWriter tracks how many bytes were written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
 // modifyURLForSubstream attempts to modify RTSP URL for substream
 // This is camera-specific and may need adjustment per camera model
 func (c *RTSPCamera) modifyURLForSubstream(url string) string {
@@ -2,8 +2,12 @@ package camera
 
 import (
 	"context"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewRTSPCamera(t *testing.T) {
@@ -180,3 +184,91 @@ func TestRTSPCamera_Capture_FFmpegNotAvailable(t *testing.T) {
 		t.Skip("ffmpeg is available - skipping ffmpeg not available test")
 	}
 }
+
+// fakeFFmpeg puts an ffmpeg on PATH that fails while failFile exists, else prints a frame
+func fakeFFmpeg(t *testing.T) (failFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	failFile = filepath.Join(dir, "fail")
+	script := "#!/bin/sh\nif [ -e " + failFile + " ]; then echo 'Connection refused' >&2; exit 1; fi\nprintf frame\n"
+	if err := os.WriteFile(filepath.Join(dir, "ffmpeg"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return failFile
+}
+
+func TestRTSPCamera_ReconnectBackoff(t *testing.T) {
+	failFile := fakeFFmpeg(t)
+	os.WriteFile(failFile, nil, 0644)
+
+	cam, err := NewRTSPCamera(Config{
+		ID:   "nvr-1",
+		Type: "rtsp",
+		RTSP: &RTSPConfig{URL: "rtsp://10.0.0.9/stream1", ReconnectInitial: 20 * time.Millisecond, ReconnectMax: 40 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cam.Capture(context.Background()); err == nil {
+		t.Fatal("expected connection failure")
+	}
+	status := cam.ReconnectStatus()
+	if status.ConsecutiveFailures != 1 || status.Attempts != 0 || status.Backoff < 10*time.Millisecond || status.Backoff > 20*time.Millisecond {
+		t.Fatalf("after first failure: %+v", status)
+	}
+
+	// Reconnecting waits out the backoff
+	start := time.Now()
+	cam.Capture(context.Background())
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond {
+		t.Errorf("reconnect did not wait for backoff (%v)", elapsed)
+	}
+	status = cam.ReconnectStatus()
+	if status.ConsecutiveFailures != 2 || status.Attempts != 1 || status.Backoff > 40*time.Millisecond {
+		t.Errorf("after second failure: %+v", status)
+	}
+
+	// A backoff longer than the capture deadline fails without connecting
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := cam.Capture(ctx); err == nil || !strings.Contains(err.Error(), "reconnect backoff") {
+		t.Errorf("expected deferred reconnect, got %v", err)
+	}
+	if status := cam.ReconnectStatus(); status.Deferred != 1 || status.ConsecutiveFailures != 2 {
+		t.Errorf("after deferral: %+v", status)
+	}
+
+	// A frame resets the backoff
+	os.Remove(failFile)
+	if data, err := cam.Capture(context.Background()); err != nil || string(data) != "frame" {
+		t.Fatalf("capture after recovery: %q, %v", data, err)
+	}
+	status = cam.ReconnectStatus()
+	if status.ConsecutiveFailures != 0 || status.Backoff != 0 || status.Attempts != 2 {
+		t.Errorf("after recovery: %+v", status)
+	}
+}
+
+func TestRTSPCamera_ReconnectDelay(t *testing.T) {
+	cam, _ := NewRTSPCamera(Config{ID: "c", RTSP: &RTSPConfig{URL: "rtsp://x/", ReconnectInitial: time.Second, ReconnectMax: 10 * time.Second}})
+	for failures, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 100: 10 * time.Second} {
+		for i := 0; i < 20; i++ {
+			if got := cam.reconnectDelay(failures); got < want/2 || got > want {
+				t.Errorf("reconnectDelay(%d) = %v, want in [%v, %v]", failures, got, want/2, want)
+			}
+		}
+	}
+}
+
+func TestUnderlying(t *testing.T) {
+	cam, _ := NewRTSPCamera(Config{ID: "c", RTSP: &RTSPConfig{URL: "rtsp://x/"}})
+	wrapped := NewSharedFetch(0).Wrap(cam, "key")
+	if _, ok := wrapped.(Reconnector); ok {
+		t.Fatal("wrapper should not expose the stream interface itself")
+	}
+	if _, ok := Underlying(wrapped).(Reconnector); !ok {
+		t.Error("Underlying should reach the RTSP camera")
+	}
+}
@@ -0,0 +1,200 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SourceKey identifies the physical source behind a camera config: type, URLs,
+// credentials and header rules. Cameras with equal keys fetch identical bytes; any difference in auth
+// gives a different key. The key is hashed so credentials are not held in plain text.
+func SourceKey(config Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", config.Type, config.SnapshotURL)
+	if a := config.Auth; a != nil {
+		fmt.Fprintf(h, "auth\x00%s\x00%s\x00%s\x00%s\x00", a.Type, a.Username, a.Password, a.Token)
+	}
+	if o := config.ONVIF; o != nil {
+		fmt.Fprintf(h, "onvif\x00%s\x00%s\x00%s\x00%s\x00", o.Endpoint, o.Username, o.Password, o.ProfileToken)
+	}
+	if r := config.RTSP; r != nil {
+		fmt.Fprintf(h, "rtsp\x00%s\x00%s\x00%s\x00%t\x00", r.URL, r.Username, r.Password, r.Substream)
+	}
+	if a := config.Agent; a != nil {
+		remoteID := a.CameraID
+		if remoteID == "" {
+			remoteID = config.ID
+		}
+		fmt.Fprintf(h, "agent\x00%s\x00%s\x00%s\x00%s\x00", a.URL, remoteID, a.CertFile, a.CAFile)
+	}
+	if t := config.TLS; t != nil {
+		fmt.Fprintf(h, "tls\x00%t\x00%s\x00%s\x00", t.InsecureSkipVerify, t.CAFile, t.PinnedSHA256)
+	}
+	if w := config.Wakeup; w != nil {
+		// A different preset or wake request yields a different frame
+		fmt.Fprintf(h, "wakeup\x00%s\x00%s\x00%s\x00%s\x00%d\x00", w.URL, w.Method, w.Body, w.ContentType, w.Delay)
+		if a := w.Auth; a != nil {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", a.Type, a.Username, a.Password, a.Token)
+		}
+	}
+	if config.ConditionalRequests {
+		// A 304 only means "unchanged" to the camera that saw the previous frame
+		fmt.Fprint(h, "conditional\x00")
+	}
+	for _, rule := range config.FailHeaders {
+		// Rules fail the shared fetch itself, so cameras with different rules cannot share
+		fmt.Fprintf(h, "fail_header\x00%s\x00%s\x00", rule.Header, rule.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// SharedFetchStats counts fetches made and captures served from another camera's fetch
+type SharedFetchStats struct {
+	Fetches int64 `json:"fetches"`
+	Shared  int64 `json:"shared"`
+}
+
+// SharedFetch coalesces captures of identical sources: a capture that starts while
+// another camera's fetch of the same source is in flight (or finished less than the
+// reuse window ago) gets a copy of those bytes instead of fetching again. Each camera
+// still applies its own processing.
+type SharedFetch struct {
+	mu       sync.Mutex
+	inflight map[string]*sharedCall
+	reuse    time.Duration
+	stats    SharedFetchStats
+}
+
+type sharedCall struct {
+	done     chan struct{}
+	data     []byte
+	err      error
+	finished time.Time
+}
+
+// NewSharedFetch creates a coalescing group reusing completed fetches for reuse
+// (0 = only join fetches still in flight)
+func NewSharedFetch(reuse time.Duration) *SharedFetch {
+	return &SharedFetch{
+		inflight: make(map[string]*sharedCall),
+		reuse:    reuse,
+	}
+}
+
+// SetReuseWindow changes how long a completed fetch is reused; applies to the next capture
+func (g *SharedFetch) SetReuseWindow(reuse time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.reuse = reuse
+}
+
+// Stats returns fetch and share counters
+func (g *SharedFetch) Stats() SharedFetchStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stats
+}
+
+// Wrap returns cam with captures coalesced by key (see SourceKey). Event-capable
+// cameras keep their events. Streaming captures are not shared, so a wrapped camera
+// always captures into memory.
+func (g *SharedFetch) Wrap(cam Camera, key string) Camera {
+	shared := &sharedCamera{Camera: cam, group: g, key: key}
+	if events, ok := cam.(EventSource); ok {
+		return &sharedEventCamera{sharedCamera: shared, events: events}
+	}
+	return shared
+}
+
+// do returns the result of the fetch for key, starting one with fetch if none is
+// in flight or recent enough. The fetch outlives a cancelled caller (bounded by the
+// caller's deadline) so cameras waiting on it are not failed by another's shutdown.
+func (g *SharedFetch) do(ctx context.Context, key string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if call, ok := g.inflight[key]; ok {
+		select {
+		case <-call.done:
+			if call.err == nil && time.Since(call.finished) < g.reuse {
+				g.stats.Shared++
+				g.mu.Unlock()
+				return bytes.Clone(call.data), nil
+			}
+		default:
+			g.stats.Shared++
+			g.mu.Unlock()
+			return waitShared(ctx, call)
+		}
+	}
+	call := &sharedCall{done: make(chan struct{})}
+	g.inflight[key] = call
+	g.stats.Fetches++
+	g.mu.Unlock()
+
+	fetchCtx := context.WithoutCancel(ctx)
+	cancel := context.CancelFunc(func() {})
+	if deadline, ok := ctx.Deadline(); ok {
+		fetchCtx, cancel = context.WithDeadline(fetchCtx, deadline)
+	}
+	go func() {
+		defer cancel()
+		data, err := fetch(fetchCtx)
+
+		g.mu.Lock()
+		call.data, call.err, call.finished = data, err, time.Now()
+		if err != nil || g.reuse <= 0 {
+			delete(g.inflight, key)
+		}
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	return waitShared(ctx, call)
+}
+
+// waitShared returns a private copy of the call's frame, since callers may process
+// it in place while other cameras still read it
+func waitShared(ctx context.Context, call *sharedCall) ([]byte, error) {
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return nil, call.err
+		}
+		return bytes.Clone(call.data), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// sharedCamera captures through its SharedFetch group
+type sharedCamera struct {
+	Camera
+	group *SharedFetch
+	key   string
+}
+
+// Capture returns a fresh frame, shared with cameras of the same source capturing now
+func (c *sharedCamera) Capture(ctx context.Context) ([]byte, error) {
+	return c.group.do(ctx, c.key, c.Camera.Capture)
+}
+
+// Unwrap returns the camera doing the actual fetches
+func (c *sharedCamera) Unwrap() Camera { return c.Camera }
+
+// sharedEventCamera is a sharedCamera that also forwards ONVIF-style events
+type sharedEventCamera struct {
+	*sharedCamera
+	events EventSource
+}
+
+func (c *sharedEventCamera) EventsEnabled() bool { return c.events.EventsEnabled() }
+
+func (c *sharedEventCamera) WatchEvents(ctx context.Context, trigger func(topic string)) {
+	c.events.WatchEvents(ctx, trigger)
+}
+
+func (c *sharedEventCamera) EventStatus() EventStatus { return c.events.EventStatus() }
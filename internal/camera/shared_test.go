@@ -0,0 +1,171 @@
+package camera
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSourceKey(t *testing.T) {
+	base := Config{ID: "north", Type: "http", SnapshotURL: "http://10.0.0.9/snap.jpg",
+		Auth: &AuthConfig{Type: "basic", Username: "viewer", Password: "a"}}
+
+	crop := base
+	crop.ID, crop.Name, crop.TimeoutSeconds = "south", "South crop", 30
+	if SourceKey(base) != SourceKey(crop) {
+		t.Error("cameras differing only in ID, name and timeout should share a key")
+	}
+
+	otherAuth := base
+	otherAuth.Auth = &AuthConfig{Type: "basic", Username: "viewer", Password: "b"}
+	otherURL := base
+	otherURL.SnapshotURL = "http://10.0.0.9/snap2.jpg"
+	otherRules := base
+	otherRules.FailHeaders = []HeaderRule{{Header: "X-Camera-Status", Value: "offline"}}
+	for name, cfg := range map[string]Config{"auth": otherAuth, "url": otherURL, "header rules": otherRules} {
+		if SourceKey(base) == SourceKey(cfg) {
+			t.Errorf("different %s should give a different key", name)
+		}
+	}
+}
+
+func TestSharedFetch_CoalescesConcurrentCaptures(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte("frame"))
+	}))
+	defer server.Close()
+
+	group := NewSharedFetch(0)
+	var cams []Camera
+	for _, id := range []string{"crop-a", "crop-b", "crop-c"} {
+		cfg := Config{ID: id, Type: "http", SnapshotURL: server.URL}
+		cam, _ := NewHTTPCamera(cfg)
+		cams = append(cams, group.Wrap(cam, SourceKey(cfg)))
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]byte, len(cams))
+	for i, cam := range cams {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := cam.Capture(context.Background())
+			if err != nil {
+				t.Errorf("%s: %v", cam.ID(), err)
+			}
+			results[i] = data
+		}()
+	}
+	// Let every capture join the in-flight fetch before it completes
+	deadline := time.Now().Add(2 * time.Second)
+	for group.Stats().Shared < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+	for i, data := range results {
+		if string(data) != "frame" {
+			t.Errorf("camera %d got %q", i, data)
+		}
+	}
+	results[0][0] = 'X'
+	if results[1][0] != 'f' {
+		t.Error("cameras should receive independent copies of the frame")
+	}
+	if stats := group.Stats(); stats.Fetches != 1 || stats.Shared != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// Without a reuse window the next capture fetches again
+	if _, err := cams[0].Capture(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests after sequential capture = %d, want 2", n)
+	}
+}
+
+func TestSharedFetch_ReuseWindow(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("frame"))
+	}))
+	defer server.Close()
+
+	group := NewSharedFetch(time.Minute)
+	cfg := Config{ID: "crop-a", Type: "http", SnapshotURL: server.URL}
+	inner, _ := NewHTTPCamera(cfg)
+	cam := group.Wrap(inner, SourceKey(cfg))
+
+	for i := 0; i < 3; i++ {
+		if _, err := cam.Capture(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("requests within reuse window = %d, want 1", n)
+	}
+
+	group.SetReuseWindow(0)
+	if _, err := cam.Capture(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests after disabling reuse = %d, want 2", n)
+	}
+}
+
+func TestSharedFetch_LeaderCancelDoesNotFailWaiters(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte("frame"))
+	}))
+	defer server.Close()
+
+	group := NewSharedFetch(0)
+	cfg := Config{ID: "crop-a", Type: "http", SnapshotURL: server.URL}
+	inner, _ := NewHTTPCamera(cfg)
+	cam := group.Wrap(inner, SourceKey(cfg))
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cam.Capture(leaderCtx)
+		leaderErr <- err
+	}()
+	for group.Stats().Fetches == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiter := make(chan []byte, 1)
+	go func() {
+		data, _ := cam.Capture(context.Background())
+		waiter <- data
+	}()
+	for group.Stats().Shared == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+	close(release)
+	if data := <-waiter; string(data) != "frame" {
+		t.Errorf("waiter got %q, want the shared frame", data)
+	}
+}
@@ -0,0 +1,78 @@
+package camera
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTTPCamera_TLSVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("frame"))
+	}))
+	defer server.Close()
+
+	leaf := server.Certificate()
+	sum := sha256.Sum256(leaf.Raw)
+	pin := strings.ToUpper(hex.EncodeToString(sum[:]))
+	colonPin := strings.Join(splitEvery(pin, 2), ":")
+
+	caFile := filepath.Join(t.TempDir(), "camera-ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		wantErr bool
+	}{
+		{"default verification rejects self-signed", nil, true},
+		{"insecure skip verify", &TLSConfig{InsecureSkipVerify: true}, false},
+		{"pinned fingerprint", &TLSConfig{PinnedSHA256: pin}, false},
+		{"pinned fingerprint with colons", &TLSConfig{PinnedSHA256: colonPin}, false},
+		{"wrong pin", &TLSConfig{PinnedSHA256: strings.Repeat("00", 32)}, true},
+		{"ca bundle", &TLSConfig{CAFile: caFile}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cam, err := NewHTTPCamera(Config{ID: "tls-cam", SnapshotURL: server.URL, TLS: tt.tls})
+			if err != nil {
+				t.Fatalf("NewHTTPCamera: %v", err)
+			}
+			data, err := cam.Capture(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Capture() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && string(data) != "frame" {
+				t.Errorf("Capture() = %q", data)
+			}
+		})
+	}
+}
+
+func TestNewHTTPCamera_TLSConfigErrors(t *testing.T) {
+	for name, cfg := range map[string]*TLSConfig{
+		"missing ca file": {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"malformed pin":   {PinnedSHA256: "not-a-fingerprint"},
+	} {
+		if _, err := NewHTTPCamera(Config{ID: "tls-cam", SnapshotURL: "https://cam.local/snap.jpg", TLS: cfg}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func splitEvery(s string, n int) []string {
+	var parts []string
+	for len(s) > n {
+		parts, s = append(parts, s[:n]), s[n:]
+	}
+	return append(parts, s)
+}
@@ -2,6 +2,7 @@ package camera
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -15,10 +16,80 @@ type Camera interface {
 	// ID returns the camera identifier
 	ID() string
 
-	// Type returns the camera type ("http", "onvif", "rtsp")
+	// Type returns the camera type ("http", "onvif", "rtsp", "folder", "agent")
 	Type() string
 }
 
+// StreamingCamera is implemented by cameras that can write a capture directly to w,
+// avoiding a full in-memory copy of large frames
+type StreamingCamera interface {
+	Camera
+
+	// CaptureTo writes a fresh snapshot to w and returns the number of bytes written
+	CaptureTo(ctx context.Context, w io.Writer) (int64, error)
+}
+
+// EventSource is implemented by cameras that can trigger captures from events the
+// camera itself detects (e.g. ONVIF motion)
+type EventSource interface {
+	Camera
+
+	// EventsEnabled reports whether event-triggered capture is configured
+	EventsEnabled() bool
+
+	// WatchEvents keeps an event subscription alive until ctx is done, calling trigger
+	// with the topic of each matching event. Failures are retried internally.
+	WatchEvents(ctx context.Context, trigger func(topic string))
+
+	// EventStatus reports the health of the event subscription
+	EventStatus() EventStatus
+}
+
+// Reconnector is implemented by stream cameras that back off reconnecting after a
+// failed stream connection, independently of capture-level backoff
+type Reconnector interface {
+	Camera
+
+	// ReconnectStatus reports stream reconnect attempts and the current backoff
+	ReconnectStatus() ReconnectStatus
+}
+
+// ReconnectStatus describes stream connection stability for monitoring
+type ReconnectStatus struct {
+	Attempts            int64         `json:"attempts"`             // Connections attempted after a failed one
+	Deferred            int64         `json:"deferred"`             // Captures failed without connecting, backoff outlasting the capture timeout
+	ConsecutiveFailures int           `json:"consecutive_failures"` // Reset by a successful frame
+	Backoff             time.Duration `json:"backoff"`
+	NextAttempt         time.Time     `json:"next_attempt,omitempty"`
+	LastError           string        `json:"last_error,omitempty"`
+}
+
+// Underlying returns the backend camera behind any wrappers (see SharedFetch)
+func Underlying(cam Camera) Camera {
+	for {
+		wrapper, ok := cam.(interface{ Unwrap() Camera })
+		if !ok {
+			return cam
+		}
+		cam = wrapper.Unwrap()
+	}
+}
+
+// EventStatus describes a camera event subscription for monitoring
+type EventStatus struct {
+	Active         bool      `json:"active"` // Subscription currently established
+	Topics         []string  `json:"topics"`
+	SubscribedAt   time.Time `json:"subscribed_at,omitempty"`
+	LastRenewal    time.Time `json:"last_renewal,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at,omitempty"` // Local time the subscription lapses without renewal
+	Subscriptions  int64     `json:"subscriptions"`        // Times a subscription was created
+	EventsMatched  int64     `json:"events_matched"`
+	LastEventTime  time.Time `json:"last_event_time,omitempty"`
+	LastEventTopic string    `json:"last_event_topic,omitempty"`
+	LastError      string    `json:"last_error,omitempty"`
+	LastErrorTime  time.Time `json:"last_error_time,omitempty"`
+}
+
 // Config represents camera configuration
 type Config struct {
 	ID             string
@@ -28,7 +99,37 @@ type Config struct {
 	Auth           *AuthConfig
 	ONVIF          *ONVIFConfig
 	RTSP           *RTSPConfig
+	Folder         *FolderConfig
+	Agent          *AgentConfig
+	TLS            *TLSConfig // HTTPS verification for http and onvif cameras
 	TimeoutSeconds int
+
+	// FailHeaders treat a 200 snapshot response as a failed capture when a header
+	// matches (http and onvif cameras)
+	FailHeaders []HeaderRule
+
+	// ConditionalRequests sends the last snapshot's ETag and Last-Modified back, so
+	// a camera can answer 304 when its frame has not changed (http cameras)
+	ConditionalRequests bool
+
+	// MaxSnapshotBytes caps the snapshot body read (http cameras). Default: 20 MB
+	MaxSnapshotBytes int64
+
+	// TolerateMissingEOI passes on chunked snapshots that do not end in a JPEG EOI
+	// marker, for cameras whose frames are trimmed or repaired after capture
+	TolerateMissingEOI bool
+
+	// Raw frames (16-bit PNG or FITS) are converted after capture; folder cameras
+	// then also pick up .png, .fits, .fit and .fts files
+	Raw bool
+
+	// Wakeup sends a request that prepares the camera before each capture (see
+	// WakeupConfig)
+	Wakeup *WakeupConfig
+
+	// Diagnostics is a JSON status endpoint polled by FetchDiagnostics, not by
+	// captures
+	Diagnostics *DiagnosticsConfig
 }
 
 // AuthConfig represents HTTP authentication configuration
@@ -45,6 +146,10 @@ type ONVIFConfig struct {
 	Username     string
 	Password     string
 	ProfileToken string
+
+	// EventTopics enables capture on camera events whose topic contains one of these
+	// (case-insensitive), e.g. "CellMotionDetector". Empty = interval capture only
+	EventTopics []string
 }
 
 // RTSPConfig represents RTSP camera configuration
@@ -53,6 +158,11 @@ type RTSPConfig struct {
 	Username  string
 	Password  string
 	Substream bool
+
+	// Stream reconnect backoff after a failed connection: jittered, doubling from
+	// ReconnectInitial up to ReconnectMax. Defaults: 2s and 60s
+	ReconnectInitial time.Duration
+	ReconnectMax     time.Duration
 }
 
 // Error types for camera operations
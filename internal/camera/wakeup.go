@@ -0,0 +1,134 @@
+package camera
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WakeupConfig is a request that prepares a camera for capture, e.g. waking its
+// sensor or moving a PTZ camera to a preset. Each capture sends it, waits Delay,
+// then takes the snapshot. The request, the delay and the snapshot all share the
+// capture's deadline; a failed wakeup is a failed capture.
+type WakeupConfig struct {
+	URL         string
+	Method      string        // Default: GET
+	Body        string        // Request body, e.g. a preset command
+	ContentType string        // Content-Type for Body; default: none
+	Auth        *AuthConfig   // Default: none (the camera's auth is not sent)
+	Delay       time.Duration // Wait after the wakeup before the snapshot
+}
+
+// maxWakeupBody bounds how much of a wakeup response is read
+const maxWakeupBody = 64 * 1024
+
+// wakeupCamera wakes the camera it wraps before every capture
+type wakeupCamera struct {
+	Camera
+	id     string
+	wakeup WakeupConfig
+	client *http.Client
+}
+
+// NewWakeupCamera returns cam with each capture preceded by config.Wakeup. The
+// wrapped camera captures into memory; events are still forwarded.
+func NewWakeupCamera(cam Camera, config Config) (Camera, error) {
+	if config.Wakeup == nil || config.Wakeup.URL == "" {
+		return nil, fmt.Errorf("wakeup.url is required")
+	}
+	timeout := time.Duration(config.TimeoutSeconds) * time.Second
+	if timeout == 0 {
+		timeout = 15 * time.Second
+	}
+	client, err := newHTTPClient(config.TLS, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	woken := &wakeupCamera{Camera: cam, id: config.ID, wakeup: *config.Wakeup, client: client}
+	if woken.wakeup.Method == "" {
+		woken.wakeup.Method = http.MethodGet
+	}
+	if events, ok := cam.(EventSource); ok {
+		return &wakeupEventCamera{wakeupCamera: woken, events: events}, nil
+	}
+	return woken, nil
+}
+
+// Capture sends the wakeup request, waits the configured delay, then captures
+func (c *wakeupCamera) Capture(ctx context.Context) ([]byte, error) {
+	if err := c.wake(ctx); err != nil {
+		return nil, err
+	}
+	return c.Camera.Capture(ctx)
+}
+
+// wake sends the wakeup request and waits out the delay. Any 2xx response counts
+// as success; the body is discarded.
+func (c *wakeupCamera) wake(ctx context.Context) error {
+	var body io.Reader
+	if c.wakeup.Body != "" {
+		body = strings.NewReader(c.wakeup.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, c.wakeup.Method, c.wakeup.URL, body)
+	if err != nil {
+		return &CaptureError{CameraID: c.id, Message: "create wakeup request", Err: err}
+	}
+	if c.wakeup.ContentType != "" {
+		req.Header.Set("Content-Type", c.wakeup.ContentType)
+	}
+	if c.wakeup.Auth != nil {
+		if err := setAuth(req, c.id, c.wakeup.Auth); err != nil {
+			return err
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded || isTimeoutError(err) {
+			return &TimeoutError{CameraID: c.id, Timeout: c.client.Timeout}
+		}
+		return &CaptureError{CameraID: c.id, Message: "wakeup request failed", Err: err}
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxWakeupBody))
+	resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return &AuthError{CameraID: c.id, Message: fmt.Sprintf("wakeup request rejected (HTTP %d)", resp.StatusCode)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &CaptureError{CameraID: c.id, Message: fmt.Sprintf("wakeup HTTP status %d", resp.StatusCode)}
+	}
+
+	if c.wakeup.Delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(c.wakeup.Delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return &CaptureError{CameraID: c.id, Message: "capture deadline reached during wakeup delay", Err: ctx.Err()}
+	}
+}
+
+// Unwrap returns the camera that takes the snapshot
+func (c *wakeupCamera) Unwrap() Camera { return c.Camera }
+
+// wakeupEventCamera is a wakeupCamera that also forwards ONVIF-style events
+type wakeupEventCamera struct {
+	*wakeupCamera
+	events EventSource
+}
+
+func (c *wakeupEventCamera) EventsEnabled() bool { return c.events.EventsEnabled() }
+
+func (c *wakeupEventCamera) WatchEvents(ctx context.Context, trigger func(topic string)) {
+	c.events.WatchEvents(ctx, trigger)
+}
+
+func (c *wakeupEventCamera) EventStatus() EventStatus { return c.events.EventStatus() }
@@ -0,0 +1,129 @@
+package camera
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// wakeupServer serves /preset and /snapshot, recording each request and when it arrived
+type wakeupServer struct {
+	*httptest.Server
+	presetStatus int
+
+	mu       sync.Mutex
+	requests []string
+	times    []time.Time
+}
+
+func newWakeupServer(t *testing.T) *wakeupServer {
+	s := &wakeupServer{presetStatus: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+string(body))
+		s.times = append(s.times, time.Now())
+		status := s.presetStatus
+		s.mu.Unlock()
+		if r.URL.Path == "/preset" {
+			if user, pass, _ := r.BasicAuth(); user != "ptz" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("frame"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *wakeupServer) newCamera(t *testing.T, delay time.Duration) Camera {
+	t.Helper()
+	cam, err := NewCamera(Config{
+		ID:          "ptz",
+		Type:        "http",
+		SnapshotURL: s.URL + "/snapshot",
+		Wakeup: &WakeupConfig{
+			URL:         s.URL + "/preset",
+			Method:      http.MethodPost,
+			Body:        "preset=2",
+			ContentType: "application/x-www-form-urlencoded",
+			Auth:        &AuthConfig{Type: "basic", Username: "ptz", Password: "secret"},
+			Delay:       delay,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewCamera: %v", err)
+	}
+	return cam
+}
+
+func TestWakeupCamera_WakesThenWaitsThenCaptures(t *testing.T) {
+	s := newWakeupServer(t)
+	cam := s.newCamera(t, 100*time.Millisecond)
+
+	data, err := cam.Capture(context.Background())
+	if err != nil || string(data) != "frame" {
+		t.Fatalf("Capture() = %q, %v", data, err)
+	}
+	if len(s.requests) != 2 || s.requests[0] != "POST /preset preset=2" || s.requests[1] != "GET /snapshot " {
+		t.Fatalf("requests = %q, want wakeup then snapshot", s.requests)
+	}
+	if gap := s.times[1].Sub(s.times[0]); gap < 100*time.Millisecond {
+		t.Errorf("snapshot %v after wakeup, want at least the 100ms delay", gap)
+	}
+	if _, ok := Underlying(cam).(*HTTPCamera); !ok {
+		t.Errorf("Underlying() = %T, want *HTTPCamera", Underlying(cam))
+	}
+}
+
+func TestWakeupCamera_FailedWakeupFailsCapture(t *testing.T) {
+	s := newWakeupServer(t)
+	s.presetStatus = http.StatusInternalServerError
+	cam := s.newCamera(t, 0)
+
+	_, err := cam.Capture(context.Background())
+	var captureErr *CaptureError
+	if !errors.As(err, &captureErr) || captureErr.Message != "wakeup HTTP status 500" {
+		t.Fatalf("Capture() error = %v, want wakeup CaptureError", err)
+	}
+	if len(s.requests) != 1 {
+		t.Errorf("requests = %q, want no snapshot after a failed wakeup", s.requests)
+	}
+}
+
+func TestWakeupCamera_DelayCountsAgainstCaptureDeadline(t *testing.T) {
+	s := newWakeupServer(t)
+	cam := s.newCamera(t, 5*time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := cam.Capture(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Capture() error = %v, want the capture deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Capture() took %v, should stop at the deadline", elapsed)
+	}
+	if len(s.requests) != 1 {
+		t.Errorf("requests = %q, want no snapshot after the deadline", s.requests)
+	}
+}
+
+func TestSourceKey_Wakeup(t *testing.T) {
+	base := Config{Type: "http", SnapshotURL: "http://cam/snap"}
+	preset1, preset2 := base, base
+	preset1.Wakeup = &WakeupConfig{URL: "http://cam/ptz", Body: "preset=1"}
+	preset2.Wakeup = &WakeupConfig{URL: "http://cam/ptz", Body: "preset=2"}
+	if SourceKey(preset1) == SourceKey(preset2) || SourceKey(preset1) == SourceKey(base) {
+		t.Error("cameras with different wakeups must not share fetches")
+	}
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Steps of writeFileAtomic, reported to saveStepHook
+const (
+	stepWriteTemp = "write_temp"
+	stepSyncTemp  = "sync_temp"
+	stepRename    = "rename"
+	stepBackup    = "backup"
+)
+
+// saveStepHook, when set by tests, runs before each step of writeFileAtomic; an error
+// aborts the save there, simulating a crash at that point
+var saveStepHook func(step string) error
+
+func runSaveStep(step string) error {
+	if saveStepHook == nil {
+		return nil
+	}
+	return saveStepHook(step)
+}
+
+// tempFilePrefix marks in-progress writes; leftovers are removed on startup
+const tempFilePrefix = ".tmp-"
+
+// writeFileAtomic replaces path with data so that path always holds either the old or
+// the new content: the data is written and fsynced to a temp file in the same
+// directory, which is then renamed over path. The original is never moved away.
+// Only after the rename is the previous content written to path.bak (best-effort).
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	previous, readErr := os.ReadFile(path)
+
+	if err := runSaveStep(stepWriteTemp); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, tempFilePrefix+filepath.Base(path)+"-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := runSaveStep(stepSyncTemp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("chmod temp file: %w", err)
+	}
+
+	if err := runSaveStep(stepRename); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	committed = true
+	syncDir(dir)
+
+	// The new config is durable; keep the previous version for manual rollback/recovery
+	if readErr == nil {
+		if err := runSaveStep(stepBackup); err != nil {
+			return nil
+		}
+		if err := writeFileViaTemp(path+".bak", previous, perm); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not backup %s: %v\n", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
+
+// writeFileViaTemp writes data via a temp file and rename so a crash never leaves a
+// truncated file behind; unlike writeFileAtomic it neither fsyncs nor keeps a backup
+func writeFileViaTemp(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), tempFilePrefix+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename survives power loss (best-effort)
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// recoverConfigFiles repairs the config directory after an interrupted save:
+// leftover temp files are removed, and a missing global.json is restored from
+// global.json.bak. Camera files are not restored because a missing camera file
+// with a backup is indistinguishable from a deleted camera.
+func recoverConfigFiles(baseDir string) {
+	for _, dir := range []string{baseDir, filepath.Join(baseDir, "cameras")} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasPrefix(entry.Name(), tempFilePrefix) {
+				os.Remove(filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+
+	globalPath := filepath.Join(baseDir, "global.json")
+	if _, err := os.Stat(globalPath); !os.IsNotExist(err) {
+		return
+	}
+	backup, err := os.ReadFile(globalPath + ".bak")
+	if err != nil {
+		return
+	}
+	if err := writeFileViaTemp(globalPath, backup, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: global.json missing and restore from backup failed: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: global.json was missing; restored from global.json.bak\n")
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var errCrash = errors.New("simulated crash")
+
+func TestWriteFileAtomic_InterruptedAtEachStep(t *testing.T) {
+	tests := []struct {
+		step       string
+		wantMain   string
+		wantBackup string // "" = no backup written
+	}{
+		{stepWriteTemp, "old", ""},
+		{stepSyncTemp, "old", ""},
+		{stepRename, "old", ""},
+		{stepBackup, "new", ""},
+		{"", "new", "old"}, // No interruption
+	}
+
+	for _, tt := range tests {
+		name := tt.step
+		if name == "" {
+			name = "complete"
+		}
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "global.json")
+			if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			saveStepHook = func(step string) error {
+				if step == tt.step {
+					return errCrash
+				}
+				return nil
+			}
+			defer func() { saveStepHook = nil }()
+
+			err := writeFileAtomic(path, []byte("new"), 0644)
+			if tt.step != "" && tt.step != stepBackup && !errors.Is(err, errCrash) {
+				t.Errorf("writeFileAtomic() error = %v, want simulated crash", err)
+			}
+
+			got, readErr := os.ReadFile(path)
+			if readErr != nil {
+				t.Fatalf("config file missing after interruption: %v", readErr)
+			}
+			if string(got) != tt.wantMain {
+				t.Errorf("config = %q, want %q", got, tt.wantMain)
+			}
+
+			backup, backupErr := os.ReadFile(path + ".bak")
+			if tt.wantBackup == "" {
+				if backupErr == nil {
+					t.Errorf("unexpected backup %q", backup)
+				}
+			} else if string(backup) != tt.wantBackup {
+				t.Errorf("backup = %q, want %q", backup, tt.wantBackup)
+			}
+		})
+	}
+}
+
+func TestWriteFileAtomic_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cam.json")
+	if err := writeFileAtomic(path, []byte("data"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("no backup expected for a new file")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v, want 0644", info.Mode().Perm())
+	}
+}
+
+func TestNewService_RecoversMissingGlobalFromBackup(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if err := svc.UpdateGlobal(func(g *GlobalSettings) error {
+		g.Timezone = "America/Denver"
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+	// A second save makes global.json.bak hold the Denver settings
+	if err := svc.UpdateGlobal(func(g *GlobalSettings) error {
+		g.Timezone = "America/Chicago"
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+
+	// Simulate a crash that lost global.json and left a partial temp file
+	if err := os.Remove(filepath.Join(dir, "global.json")); err != nil {
+		t.Fatal(err)
+	}
+	leftover := filepath.Join(dir, tempFilePrefix+"global.json-123")
+	if err := os.WriteFile(leftover, []byte("{partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc, err = InitOrMigrate(dir, filepath.Join(dir, "missing-legacy.json"))
+	if err != nil {
+		t.Fatalf("InitOrMigrate: %v", err)
+	}
+	if tz := svc.GetGlobal().Timezone; tz != "America/Denver" {
+		t.Errorf("Timezone = %q, want recovered America/Denver", tz)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Error("leftover temp file should be removed on startup")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), tempFilePrefix) {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
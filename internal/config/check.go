@@ -0,0 +1,68 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Problem is one configuration error found by Check
+type Problem struct {
+	Camera  string `json:"camera,omitempty"` // Empty for global settings
+	Field   string `json:"field,omitempty"`  // Setting at fault, when known
+	Message string `json:"message"`
+}
+
+// Check validates the loaded global settings and every camera, collecting one
+// problem per invalid section instead of stopping at the first. Cameras with
+// problems are still loaded; this only reports why one may not be working.
+func (s *Service) Check() []Problem {
+	s.mu.RLock()
+	global := *s.global
+	cameras := make([]Camera, 0, len(s.cameras))
+	for _, cam := range s.cameras {
+		cameras = append(cameras, *cam)
+	}
+	s.mu.RUnlock()
+	sort.Slice(cameras, func(i, j int) bool { return cameras[i].ID < cameras[j].ID })
+
+	var problems []Problem
+	if err := ValidateGlobal(global.Global); err != nil {
+		problems = append(problems, newProblem("", err))
+	}
+	if err := ValidateMQTT(global.MQTT); err != nil {
+		problems = append(problems, newProblem("", err))
+	}
+	if err := ValidateStatsD(global.StatsD); err != nil {
+		problems = append(problems, newProblem("", err))
+	}
+	if err := ValidateWebConsole(global.WebConsole); err != nil {
+		problems = append(problems, newProblem("", err))
+	}
+	for i := range cameras {
+		if err := ValidateCamera(&cameras[i]); err != nil {
+			problems = append(problems, newProblem(cameras[i].ID, err))
+		}
+	}
+	return problems
+}
+
+// newProblem builds a Problem, taking the field from the leading setting name that
+// validation messages start with (e.g. "folder.path: ..." or "type must be ...")
+func newProblem(cameraID string, err error) Problem {
+	msg := err.Error()
+	p := Problem{Camera: cameraID, Message: msg}
+	if i := strings.IndexAny(msg, ": "); i > 0 && isFieldName(msg[:i]) {
+		p.Field = msg[:i]
+	}
+	return p
+}
+
+// isFieldName reports whether s looks like a JSON setting name or dotted path
+func isFieldName(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServiceCheck(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	upload := &Upload{Host: "example.com", Username: "test", Password: "pass"}
+	for _, cam := range []Camera{
+		{ID: "good", Name: "Good", Type: "http", SnapshotURL: "http://cam/snap.jpg", Upload: upload},
+		{ID: "bad-type", Name: "Bad type", Type: "webcam", Upload: upload},
+		{ID: "no-upload", Name: "No upload", Type: "http", SnapshotURL: "http://cam/snap.jpg"},
+		{ID: "tunnel", Name: "Tunnel", Type: "onvif", ONVIF: &ONVIF{Endpoint: "http://cam/onvif", Username: "u", Password: "p"}, Tunnel: &Tunnel{Host: "jump"}, Upload: upload},
+	} {
+		if err := svc.AddCamera(cam); err != nil {
+			t.Fatalf("AddCamera: %v", err)
+		}
+	}
+	if err := svc.UpdateGlobal(func(g *GlobalSettings) error {
+		g.MQTT = &MQTT{Enabled: true}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+
+	var got [][2]string
+	for _, p := range svc.Check() {
+		got = append(got, [2]string{p.Camera, p.Field})
+	}
+	want := [][2]string{
+		{"", "mqtt.broker"},
+		{"bad-type", "type"},
+		{"no-upload", "upload"},
+		{"tunnel", "tunnel"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// EventOverflowPolicy controls what happens when a listener's event queue is full
+type EventOverflowPolicy string
+
+const (
+	EventOverflowBlock      EventOverflowPolicy = "block"       // Wait up to EventBlockTimeout for room, then drop (default)
+	EventOverflowDropOldest EventOverflowPolicy = "drop_oldest" // Discard the oldest queued event
+)
+
+// Event delivery defaults
+const (
+	DefaultEventQueueSize    = 64
+	DefaultEventBlockTimeout = 5 * time.Second
+)
+
+// eventListener delivers events to one subscriber in order from a bounded queue
+// drained by a single goroutine
+type eventListener struct {
+	fn           func(ConfigEvent)
+	events       chan ConfigEvent
+	policy       EventOverflowPolicy
+	blockTimeout time.Duration
+	dropped      atomic.Int64
+}
+
+func newEventListener(fn func(ConfigEvent), opts ServiceOptions) *eventListener {
+	size := opts.EventQueueSize
+	if size <= 0 {
+		size = DefaultEventQueueSize
+	}
+	timeout := opts.EventBlockTimeout
+	if timeout <= 0 {
+		timeout = DefaultEventBlockTimeout
+	}
+	policy := opts.EventOverflow
+	if policy != EventOverflowDropOldest {
+		policy = EventOverflowBlock
+	}
+
+	l := &eventListener{
+		fn:           fn,
+		events:       make(chan ConfigEvent, size),
+		policy:       policy,
+		blockTimeout: timeout,
+	}
+	go l.run()
+	return l
+}
+
+func (l *eventListener) run() {
+	for event := range l.events {
+		l.fn(event)
+	}
+}
+
+// enqueue adds an event, applying the overflow policy when the queue is full.
+// Callers must be serialized (Service.notifyMu) so drop-oldest cannot livelock.
+func (l *eventListener) enqueue(event ConfigEvent) {
+	select {
+	case l.events <- event:
+		return
+	default:
+	}
+
+	if l.policy == EventOverflowDropOldest {
+		for {
+			select {
+			case old := <-l.events:
+				l.dropped.Add(1)
+				fmt.Fprintf(os.Stderr, "WARNING: config listener queue full, dropped %s event\n", old.Type)
+			default:
+			}
+			select {
+			case l.events <- event:
+				return
+			default:
+			}
+		}
+	}
+
+	timer := time.NewTimer(l.blockTimeout)
+	defer timer.Stop()
+	select {
+	case l.events <- event:
+	case <-timer.C:
+		l.dropped.Add(1)
+		fmt.Fprintf(os.Stderr, "WARNING: config listener blocked for %s, dropped %s event\n", l.blockTimeout, event.Type)
+	}
+}
+
+// Subscribe registers a listener for config changes. Each listener receives events
+// in order on its own goroutine; listeners may call back into the Service.
+func (s *Service) Subscribe(fn func(ConfigEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, newEventListener(fn, s.opts))
+}
+
+// DroppedEvents returns the number of events discarded because a listener fell behind
+func (s *Service) DroppedEvents() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var total int64
+	for _, l := range s.listeners {
+		total += l.dropped.Load()
+	}
+	return total
+}
+
+// notifyListeners records an event for delivery by flushEvents (caller must hold lock).
+// Recording under the lock keeps events in the order changes were applied.
+func (s *Service) notifyListeners(event ConfigEvent) {
+	if len(s.listeners) > 0 {
+		s.pendingEvents = append(s.pendingEvents, event)
+	}
+}
+
+// flushEvents hands pending events to listeners. It must run after s.mu is released
+// (deferred before the lock in each writer) because a blocked enqueue waits on a
+// listener that may itself need the lock.
+func (s *Service) flushEvents() {
+	s.notifyMu.Lock()
+	defer s.notifyMu.Unlock()
+
+	s.mu.Lock()
+	events := s.pendingEvents
+	s.pendingEvents = nil
+	listeners := s.listeners
+	s.mu.Unlock()
+
+	for _, event := range events {
+		for _, l := range listeners {
+			l.enqueue(event)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func addTestCameras(t *testing.T, svc *Service, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		cam := Camera{ID: fmt.Sprintf("cam-%d", i), Name: "Test", Type: "http"}
+		if err := svc.AddCamera(cam); err != nil {
+			t.Fatalf("AddCamera: %v", err)
+		}
+	}
+}
+
+func TestEvents_DeliveredInOrder(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	const n = 20
+	var (
+		mu       sync.Mutex
+		received []string
+		done     = make(chan struct{})
+	)
+	svc.Subscribe(func(event ConfigEvent) {
+		// Listeners may read back from the service while writers are active
+		svc.ListCameras()
+		mu.Lock()
+		received = append(received, event.CameraID)
+		if len(received) == n {
+			close(done)
+		}
+		mu.Unlock()
+	})
+
+	addTestCameras(t, svc, n)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+
+	for i, id := range received {
+		if want := fmt.Sprintf("cam-%d", i); id != want {
+			t.Fatalf("event %d = %s, want %s (events out of order: %v)", i, id, want, received)
+		}
+	}
+	if svc.DroppedEvents() != 0 {
+		t.Errorf("DroppedEvents = %d, want 0", svc.DroppedEvents())
+	}
+}
+
+func TestEvents_DropOldest(t *testing.T) {
+	svc, err := NewServiceWithOptions(t.TempDir(), ServiceOptions{
+		EventQueueSize: 2,
+		EventOverflow:  EventOverflowDropOldest,
+	})
+	if err != nil {
+		t.Fatalf("NewServiceWithOptions: %v", err)
+	}
+
+	gate := make(chan struct{})
+	received := make(chan string, 10)
+	svc.Subscribe(func(event ConfigEvent) {
+		<-gate
+		received <- event.CameraID
+	})
+
+	const n = 6
+	addTestCameras(t, svc, n)
+	close(gate)
+
+	dropped := svc.DroppedEvents()
+	if dropped == 0 {
+		t.Fatal("expected events to be dropped while the listener was stalled")
+	}
+
+	var got []string
+	timeout := time.After(2 * time.Second)
+	for int64(len(got))+dropped < n {
+		select {
+		case id := <-received:
+			got = append(got, id)
+		case <-timeout:
+			t.Fatalf("timeout: received %v, dropped %d", got, dropped)
+		}
+	}
+	if last := got[len(got)-1]; last != fmt.Sprintf("cam-%d", n-1) {
+		t.Errorf("newest event should survive, last received = %s", last)
+	}
+}
+
+func TestEvents_BlockTimeout(t *testing.T) {
+	svc, err := NewServiceWithOptions(t.TempDir(), ServiceOptions{
+		EventQueueSize:    1,
+		EventOverflow:     EventOverflowBlock,
+		EventBlockTimeout: 20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewServiceWithOptions: %v", err)
+	}
+
+	gate := make(chan struct{})
+	defer close(gate)
+	svc.Subscribe(func(event ConfigEvent) {
+		<-gate
+	})
+
+	start := time.Now()
+	addTestCameras(t, svc, 4)
+
+	if svc.DroppedEvents() == 0 {
+		t.Error("expected events to be dropped after the block timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("writers blocked for %v, want bounded by the block timeout", elapsed)
+	}
+}
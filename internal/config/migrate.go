@@ -21,6 +21,9 @@ func MigrateFromLegacy(legacyPath string, newBaseDir string) error {
 	if err := json.Unmarshal(data, &legacy); err != nil {
 		return fmt.Errorf("parse legacy config: %w", err)
 	}
+	if legacy.Version > CurrentVersion {
+		return &UnsupportedVersionError{Version: legacy.Version}
+	}
 
 	// Create new service
 	svc, err := NewService(newBaseDir)
@@ -61,11 +64,19 @@ func MigrateFromLegacy(legacyPath string, newBaseDir string) error {
 
 // InitOrMigrate initializes ConfigService, migrating from legacy format if needed
 func InitOrMigrate(baseDir string, legacyPath string) (*Service, error) {
+	return InitOrMigrateWithOptions(baseDir, legacyPath, ServiceOptions{})
+}
+
+// InitOrMigrateWithOptions is InitOrMigrate with explicit service options
+func InitOrMigrateWithOptions(baseDir string, legacyPath string, opts ServiceOptions) (*Service, error) {
+	// A global.json lost mid-save is restored from its backup, not re-migrated
+	recoverConfigFiles(baseDir)
+
 	// Check if new format already exists
 	globalPath := filepath.Join(baseDir, "global.json")
 	if _, err := os.Stat(globalPath); err == nil {
 		// New format exists, just load it
-		return NewService(baseDir)
+		return NewServiceWithOptions(baseDir, opts)
 	}
 
 	// Check if legacy config exists
@@ -74,11 +85,11 @@ func InitOrMigrate(baseDir string, legacyPath string) (*Service, error) {
 		if err := MigrateFromLegacy(legacyPath, baseDir); err != nil {
 			return nil, fmt.Errorf("migrate legacy config: %w", err)
 		}
-		return NewService(baseDir)
+		return NewServiceWithOptions(baseDir, opts)
 	}
 
 	// Neither exists, create fresh
-	return NewService(baseDir)
+	return NewServiceWithOptions(baseDir, opts)
 }
 
 // NormalizeUploadConfig ensures upload config has sensible defaults and backward compatibility.
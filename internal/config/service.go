@@ -2,14 +2,20 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
+// ErrCameraExists is returned when adding a camera whose ID is taken, including by
+// an ID differing only by case
+var ErrCameraExists = errors.New("camera already exists")
+
 // Service provides centralized config management with file-per-camera storage
 // This eliminates shared mutable state and pointer synchronization issues
 //
@@ -25,7 +31,7 @@ import (
 //   - Single source of truth (Service owns all config)
 //   - No shared pointers (returns copies)
 //   - Atomic operations (all updates transactional)
-//   - Event-driven (non-blocking notifications)
+//   - Event-driven (ordered, bounded notifications per listener)
 type Service struct {
 	baseDir string
 	mu      sync.RWMutex
@@ -34,8 +40,15 @@ type Service struct {
 	global  *GlobalSettings
 	cameras map[string]*Camera // Key: camera ID
 
-	// Event listeners (called asynchronously)
-	listeners []func(event ConfigEvent)
+	opts ServiceOptions
+
+	// Event listeners, each fed in order from its own bounded queue
+	listeners     []*eventListener
+	pendingEvents []ConfigEvent // Recorded under mu, delivered by flushEvents
+	notifyMu      sync.Mutex    // Serializes delivery so listeners see events in order
+
+	// Set when config came from a newer bridge version; all writes are refused
+	readOnly bool
 }
 
 // GlobalSettings holds bridge-wide configuration
@@ -50,6 +63,17 @@ type GlobalSettings struct {
 	Queue                 *QueueGlobal `json:"queue,omitempty"`                   // Queue settings
 	SNTP                  *SNTP        `json:"sntp,omitempty"`                    // Time sync settings
 	WebConsole            *WebConsole  `json:"web_console,omitempty"`             // Web console settings
+	MQTT                  *MQTT        `json:"mqtt,omitempty"`                    // Optional MQTT commands and events
+	StatsD                *StatsD      `json:"statsd,omitempty"`                  // Optional StatsD metrics export
+}
+
+// ServiceOptions configures NewServiceWithOptions
+type ServiceOptions struct {
+	FutureVersion FutureVersionPolicy // Default: read_only
+
+	EventQueueSize    int                 // Pending events per listener (default: 64)
+	EventOverflow     EventOverflowPolicy // Default: block
+	EventBlockTimeout time.Duration       // Max wait under the block policy (default: 5s)
 }
 
 // ConfigEvent represents a configuration change
@@ -58,12 +82,17 @@ type ConfigEvent struct {
 	CameraID string // Empty for global events
 }
 
-// NewService creates a config service
+// NewService creates a config service with default options
 func NewService(baseDir string) (*Service, error) {
+	return NewServiceWithOptions(baseDir, ServiceOptions{})
+}
+
+// NewServiceWithOptions creates a config service
+func NewServiceWithOptions(baseDir string, opts ServiceOptions) (*Service, error) {
 	s := &Service{
-		baseDir:   baseDir,
-		cameras:   make(map[string]*Camera),
-		listeners: []func(ConfigEvent){},
+		baseDir: baseDir,
+		opts:    opts,
+		cameras: make(map[string]*Camera),
 	}
 
 	// Ensure directories exist
@@ -71,8 +100,22 @@ func NewService(baseDir string) (*Service, error) {
 		return nil, fmt.Errorf("create config directories: %w", err)
 	}
 
-	// Load existing config
-	if err := s.reload(); err != nil {
+	// Repair the directory after an interrupted save, then load existing config
+	recoverConfigFiles(baseDir)
+	err := s.reload()
+	var versionErr *UnsupportedVersionError
+	var migrationErr *MigrationError
+	if errors.As(err, &versionErr) || errors.As(err, &migrationErr) {
+		// Never replace a newer or unmigratable config with defaults
+		if opts.FutureVersion == FutureVersionRefuse {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "WARNING: %v; running read-only, config changes will not be saved\n", err)
+		s.readOnly = true
+		err = nil
+	}
+
+	if err != nil {
 		// If reload fails, use defaults
 		defaultSNTP := DefaultSNTP()
 		s.global = &GlobalSettings{
@@ -86,7 +129,7 @@ func NewService(baseDir string) (*Service, error) {
 			WebConsole: &WebConsole{
 				Enabled:  true,
 				Port:     1229,
-				Password: "aviationwx",
+				Password: DefaultWebPassword,
 			},
 		}
 		// Save defaults
@@ -97,14 +140,24 @@ func NewService(baseDir string) (*Service, error) {
 		// If SNTP wasn't configured, add defaults
 		defaultSNTP := DefaultSNTP()
 		s.global.SNTP = &defaultSNTP
-		if err := s.saveGlobal(); err != nil {
-			return nil, fmt.Errorf("save SNTP defaults: %w", err)
+		if !s.readOnly {
+			if err := s.saveGlobal(); err != nil {
+				return nil, fmt.Errorf("save SNTP defaults: %w", err)
+			}
 		}
 	}
 
 	return s, nil
 }
 
+// IsReadOnly reports whether config writes are refused (config from a newer version,
+// or one that failed to migrate)
+func (s *Service) IsReadOnly() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.readOnly
+}
+
 // GetGlobal returns a copy of global config (thread-safe)
 func (s *Service) GetGlobal() GlobalSettings {
 	s.mu.RLock()
@@ -114,9 +167,14 @@ func (s *Service) GetGlobal() GlobalSettings {
 
 // UpdateGlobal updates global config atomically
 func (s *Service) UpdateGlobal(fn func(*GlobalSettings) error) error {
+	defer s.flushEvents()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
 	// Make a copy
 	updated := *s.global
 
@@ -132,14 +190,14 @@ func (s *Service) UpdateGlobal(fn func(*GlobalSettings) error) error {
 	}
 
 	path := filepath.Join(s.baseDir, "global.json")
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := writeFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("write global config: %w", err)
 	}
 
 	// Update in-memory
 	s.global = &updated
 
-	// Notify listeners (async)
+	// Notify listeners (delivered after unlock)
 	s.notifyListeners(ConfigEvent{Type: "global_updated"})
 
 	return nil
@@ -180,12 +238,25 @@ func (s *Service) ListCameras() []Camera {
 
 // AddCamera adds a new camera atomically
 func (s *Service) AddCamera(cam Camera) error {
+	defer s.flushEvents()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
+	cam.ID = NormalizeCameraID(cam.ID)
+	if err := ValidateCameraID(cam.ID); err != nil {
+		return err
+	}
+
 	// Check for duplicate
 	if _, exists := s.cameras[cam.ID]; exists {
-		return fmt.Errorf("camera already exists: %s", cam.ID)
+		return fmt.Errorf("%w: %s", ErrCameraExists, cam.ID)
+	}
+	if other := s.caseCollisionLocked(cam.ID); other != "" {
+		return fmt.Errorf("%w: %s differs only by case from %s", ErrCameraExists, cam.ID, other)
 	}
 
 	// Save to disk first (fail-safe)
@@ -197,17 +268,34 @@ func (s *Service) AddCamera(cam Camera) error {
 	copy := cam
 	s.cameras[cam.ID] = &copy
 
-	// Notify listeners (async)
+	// Notify listeners (delivered after unlock)
 	s.notifyListeners(ConfigEvent{Type: "camera_added", CameraID: cam.ID})
 
 	return nil
 }
 
+// caseCollisionLocked returns an existing camera ID equal to id ignoring case, or ""
+// (caller must hold lock). Such IDs would share queue directories on
+// case-insensitive filesystems and are easily confused on the server.
+func (s *Service) caseCollisionLocked(id string) string {
+	for existing := range s.cameras {
+		if strings.EqualFold(existing, id) {
+			return existing
+		}
+	}
+	return ""
+}
+
 // UpdateCamera updates an existing camera atomically
 func (s *Service) UpdateCamera(id string, fn func(*Camera) error) error {
+	defer s.flushEvents()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
 	cam, exists := s.cameras[id]
 	if !exists {
 		return fmt.Errorf("camera not found: %s", id)
@@ -232,7 +320,7 @@ func (s *Service) UpdateCamera(id string, fn func(*Camera) error) error {
 	// Update in-memory
 	s.cameras[id] = &updated
 
-	// Notify listeners (async)
+	// Notify listeners (delivered after unlock)
 	s.notifyListeners(ConfigEvent{Type: "camera_updated", CameraID: id})
 
 	return nil
@@ -240,9 +328,14 @@ func (s *Service) UpdateCamera(id string, fn func(*Camera) error) error {
 
 // DeleteCamera removes a camera atomically
 func (s *Service) DeleteCamera(id string) error {
+	defer s.flushEvents()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.readOnly {
+		return ErrReadOnly
+	}
+
 	if _, exists := s.cameras[id]; !exists {
 		return fmt.Errorf("camera not found: %s", id)
 	}
@@ -256,32 +349,49 @@ func (s *Service) DeleteCamera(id string) error {
 	// Update in-memory
 	delete(s.cameras, id)
 
-	// Notify listeners (async)
+	// Notify listeners (delivered after unlock)
 	s.notifyListeners(ConfigEvent{Type: "camera_deleted", CameraID: id})
 
 	return nil
 }
 
-// Subscribe registers a listener for config changes
-// Listeners are called asynchronously (non-blocking)
-func (s *Service) Subscribe(fn func(ConfigEvent)) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.listeners = append(s.listeners, fn)
-}
-
 // GetWebPassword returns the web console password
 func (s *Service) GetWebPassword() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	if s.global.WebConsole == nil {
-		return "aviationwx"
+		return DefaultWebPassword
 	}
 	if s.global.WebConsole.Password != "" {
 		return s.global.WebConsole.Password
 	}
-	return "aviationwx"
+	return DefaultWebPassword
+}
+
+// UsingDefaultWebPassword reports whether the web console password is the default
+func (s *Service) UsingDefaultWebPassword() bool {
+	return s.GetWebPassword() == DefaultWebPassword
+}
+
+// DefaultPasswordPolicy returns the web console's default password policy, with
+// the default applied
+func (s *Service) DefaultPasswordPolicy() string {
+	if policy := s.GetWebConsole().DefaultPasswordPolicy; policy != "" {
+		return policy
+	}
+	return DefaultPasswordWarn
+}
+
+// GetWebConsole returns the web console settings, or defaults if unset
+func (s *Service) GetWebConsole() WebConsole {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.global.WebConsole == nil {
+		return DefaultWebConsole()
+	}
+	return *s.global.WebConsole
 }
 
 // GetWebPort returns the web console port
@@ -308,8 +418,17 @@ func (s *Service) reload() error {
 	if err := json.Unmarshal(data, &global); err != nil {
 		return fmt.Errorf("parse global config: %w", err)
 	}
+
+	// A newer or unmigratable config is still loaded, read-only (see NewServiceWithOptions)
+	migrated, versionErr := migrateGlobal(&global)
 	s.global = &global
 
+	if migrated && versionErr == nil {
+		if err := s.saveGlobal(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save migrated global config: %v\n", err)
+		}
+	}
+
 	// Load all camera configs
 	camerasDir := filepath.Join(s.baseDir, "cameras")
 	entries, err := os.ReadDir(camerasDir)
@@ -337,35 +456,38 @@ func (s *Service) reload() error {
 			return fmt.Errorf("parse camera file %s: %w", entry.Name(), err)
 		}
 
+		// A hand-edited ID that is not a safe file name would be written outside the
+		// cameras directory on the next save
+		if err := ValidateCameraID(cam.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping camera file %s: %v\n", entry.Name(), err)
+			continue
+		}
+		if other := s.caseCollisionLocked(cam.ID); other != "" {
+			fmt.Fprintf(os.Stderr, "Warning: camera %s differs only by case from %s; rename one\n", cam.ID, other)
+		}
+
 		// Normalize upload config for backward compatibility
 		NormalizeUploadConfig(cam.Upload)
 
 		s.cameras[cam.ID] = &cam
 	}
 
-	return nil
+	// Cameras are loaded even for a newer or unmigratable version so a read-only
+	// bridge keeps capturing
+	return versionErr
 }
 
 // saveGlobal saves global config to disk (caller must hold lock)
 func (s *Service) saveGlobal() error {
 	path := filepath.Join(s.baseDir, "global.json")
 
-	// Backup existing file before overwriting
-	if _, err := os.Stat(path); err == nil {
-		backupPath := path + ".bak"
-		if err := copyFile(path, backupPath); err != nil {
-			// Log warning but don't fail - backup is best-effort
-			fmt.Fprintf(os.Stderr, "Warning: could not backup global config: %v\n", err)
-		}
-	}
-
 	data, err := json.MarshalIndent(s.global, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	// Write with proper permissions (0644 = rw-r--r--)
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// Write with proper permissions (0644 = rw-r--r--); the previous file becomes global.json.bak
+	if err := writeFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("write global config: %w", err)
 	}
 
@@ -382,22 +504,13 @@ func (s *Service) saveGlobal() error {
 func (s *Service) saveCameraFile(cam Camera) error {
 	path := filepath.Join(s.baseDir, "cameras", cam.ID+".json")
 
-	// Backup existing file before overwriting
-	if _, err := os.Stat(path); err == nil {
-		backupPath := path + ".bak"
-		if err := copyFile(path, backupPath); err != nil {
-			// Log warning but don't fail - backup is best-effort
-			fmt.Fprintf(os.Stderr, "Warning: could not backup camera config: %v\n", err)
-		}
-	}
-
 	data, err := json.MarshalIndent(cam, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	// Write with proper permissions (0644 = rw-r--r--)
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// Write with proper permissions (0644 = rw-r--r--); the previous file becomes <id>.json.bak
+	if err := writeFileAtomic(path, data, 0644); err != nil {
 		return fmt.Errorf("write camera config: %w", err)
 	}
 
@@ -410,45 +523,3 @@ func (s *Service) saveCameraFile(cam Camera) error {
 
 	return nil
 }
-
-// notifyListeners calls all registered listeners (caller must hold lock)
-func (s *Service) notifyListeners(event ConfigEvent) {
-	for _, fn := range s.listeners {
-		// Call async to avoid blocking the caller
-		go fn(event)
-	}
-}
-
-// copyFile creates a backup copy of a file
-func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
-	if err != nil {
-		return fmt.Errorf("open source: %w", err)
-	}
-	defer func() {
-		if err := sourceFile.Close(); err != nil {
-			return
-		}
-	}()
-
-	destFile, err := os.Create(dst)
-	if err != nil {
-		return fmt.Errorf("create destination: %w", err)
-	}
-	defer func() {
-		if err := destFile.Close(); err != nil {
-			return
-		}
-	}()
-
-	if _, err := io.Copy(destFile, sourceFile); err != nil {
-		return fmt.Errorf("copy data: %w", err)
-	}
-
-	// Sync to ensure data is written to disk
-	if err := destFile.Sync(); err != nil {
-		return fmt.Errorf("sync destination: %w", err)
-	}
-
-	return nil
-}
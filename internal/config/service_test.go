@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -102,6 +104,31 @@ func TestAddCamera_Duplicate(t *testing.T) {
 	}
 }
 
+func TestAddCamera_IDSafety(t *testing.T) {
+	svc, _ := NewService(t.TempDir())
+
+	if err := svc.AddCamera(Camera{ID: " Cam1\t", Name: "Camera 1", Type: "http"}); err != nil {
+		t.Fatalf("AddCamera with surrounding whitespace failed: %v", err)
+	}
+	if _, err := svc.GetCamera("Cam1"); err != nil {
+		t.Errorf("camera not stored under its trimmed ID: %v", err)
+	}
+
+	err := svc.AddCamera(Camera{ID: "cam1", Name: "Camera 1 again", Type: "http"})
+	if !errors.Is(err, ErrCameraExists) {
+		t.Errorf("AddCamera(cam1) error = %v, want ErrCameraExists", err)
+	}
+
+	for _, id := range []string{"../escape", "a/b", "cam.1", "two words", strings.Repeat("c", MaxCameraIDLength+1)} {
+		if err := svc.AddCamera(Camera{ID: id, Name: "Bad", Type: "http"}); err == nil {
+			t.Errorf("AddCamera(%q) succeeded, want an invalid ID error", id)
+		}
+	}
+	if n := len(svc.ListCameras()); n != 1 {
+		t.Errorf("ListCameras() = %d cameras, want 1", n)
+	}
+}
+
 func TestUpdateCamera(t *testing.T) {
 	tmpDir := t.TempDir()
 	svc, _ := NewService(tmpDir)
@@ -1,5 +1,13 @@
 package config
 
+import (
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"net/netip"
+	"strings"
+)
+
 // Config represents the root configuration structure
 // Version 2 uses per-camera upload credentials
 type Config struct {
@@ -19,30 +27,223 @@ type Config struct {
 type Camera struct {
 	ID      string `json:"id"`      // Unique identifier (used for queue directory)
 	Name    string `json:"name"`    // Display name
-	Type    string `json:"type"`    // "http", "onvif", "rtsp"
+	Type    string `json:"type"`    // "http", "onvif", "rtsp", "folder", "agent"
 	Enabled bool   `json:"enabled"` // Whether camera is active
 
 	// Capture settings
-	SnapshotURL            string `json:"snapshot_url,omitempty"`             // For HTTP type
-	Auth                   *Auth  `json:"auth,omitempty"`                     // Camera authentication
-	ONVIF                  *ONVIF `json:"onvif,omitempty"`                    // ONVIF settings
-	RTSP                   *RTSP  `json:"rtsp,omitempty"`                     // RTSP settings
-	CaptureIntervalSeconds int    `json:"capture_interval_seconds,omitempty"` // 1-1800, default 60
+	SnapshotURL            string  `json:"snapshot_url,omitempty"`             // For HTTP type
+	Auth                   *Auth   `json:"auth,omitempty"`                     // Camera authentication
+	ONVIF                  *ONVIF  `json:"onvif,omitempty"`                    // ONVIF settings
+	RTSP                   *RTSP   `json:"rtsp,omitempty"`                     // RTSP settings
+	Folder                 *Folder `json:"folder,omitempty"`                   // Folder settings
+	Agent                  *Agent  `json:"agent,omitempty"`                    // Bridge agent settings
+	CaptureIntervalSeconds int     `json:"capture_interval_seconds,omitempty"` // 1-1800, default 60
+
+	// CapturesPerHour is an alternative to CaptureIntervalSeconds (set one or the
+	// other), converted to the nearest whole-second interval. 2-3600
+	CapturesPerHour int `json:"captures_per_hour,omitempty"`
+
+	// MinIntervalSeconds is a floor between captures for cameras that lock up when
+	// polled too often; it overrides a shorter interval and defers early triggers.
+	// Default: 0 (none), max 1800
+	MinIntervalSeconds int `json:"min_interval_seconds,omitempty"`
+
+	// Tunnel reaches an http or rtsp camera through an SSH local port forward the
+	// bridge opens, for cameras behind CGNAT. Default: none (connect directly)
+	Tunnel *Tunnel `json:"tunnel,omitempty"`
+
+	// Wakeup sends a request before each capture that prepares the camera, e.g. wakes
+	// its sensor or moves a PTZ camera to a preset. Default: none
+	Wakeup *Wakeup `json:"wakeup,omitempty"`
+
+	// Diagnostics polls a JSON status endpoint on the camera at its own interval and
+	// shows selected fields in the camera's status. Failures never affect captures.
+	// Default: none
+	Diagnostics *Diagnostics `json:"diagnostics,omitempty"`
+
+	// TLS controls certificate verification for HTTPS snapshot and ONVIF URLs.
+	// Default: full verification against the system roots
+	TLS *CameraTLS `json:"tls,omitempty"`
+
+	// FailOnHeaders treats a snapshot response as a failed capture, despite a 200 status
+	// and image body, when one of these headers matches (http and onvif). Default: none
+	FailOnHeaders []HeaderRule `json:"fail_on_headers,omitempty"`
+
+	// ConditionalRequests sends If-None-Match/If-Modified-Since from the previous
+	// snapshot; a 304 answer skips the cycle as unchanged (http only). Default: false
+	ConditionalRequests bool `json:"conditional_requests,omitempty"`
+
+	// MaxSnapshotKB caps the snapshot size read from the camera (http only).
+	// Default: 20480 (20 MB)
+	MaxSnapshotKB int `json:"max_snapshot_kb,omitempty"`
+
+	// Rediscovery finds the camera again by a stable identifier (WS-Discovery) when
+	// its DHCP address changes. Default: none
+	Rediscovery *Rediscovery `json:"rediscovery,omitempty"`
 
 	// Image processing (bandwidth control)
 	Image *ImageProcessing `json:"image,omitempty"` // Resolution/quality settings
 
+	// Thumbnail uploads a second, smaller rendition of each capture to its own
+	// remote path. Default: none
+	Thumbnail *Thumbnail `json:"thumbnail,omitempty"`
+
+	// Spectrogram records audio from an RTSP stream after captures and uploads it as
+	// a spectrogram image to its own remote path. Default: none
+	Spectrogram *Spectrogram `json:"spectrogram,omitempty"`
+
+	// Regions crops named parts of each capture (e.g. two runway ends) and uploads
+	// each as its own image to its own remote path. Default: none
+	Regions []Region `json:"regions,omitempty"`
+
+	// OfflineImagePath is an operator-supplied "camera offline" JPEG. Once captures
+	// have failed for OfflineAfterSeconds (default 900), it is queued at the capture
+	// interval, stamped with the current time, until a capture succeeds. Default: none
+	OfflineImagePath    string `json:"offline_image_path,omitempty"`
+	OfflineAfterSeconds int    `json:"offline_after_seconds,omitempty"`
+
+	// LogLevel overrides the log level for this camera's capture and upload messages:
+	// "debug", "info", "warn" or "error". Applied without restarting the camera.
+	// Default: the bridge's level (LOG_LEVEL)
+	LogLevel string `json:"log_level,omitempty"`
+
+	// TrimJPEG discards bytes before the JPEG SOI and after the matching EOI
+	// (e.g. HTTP preamble or multipart trailers some cameras include). Default: false
+	TrimJPEG bool `json:"trim_jpeg,omitempty"`
+
+	// RepairJPEG salvages frames whose only defect is a missing or partial EOI
+	// marker, or one stray byte after it. Scan data is never changed. Default: false
+	RepairJPEG bool `json:"repair_jpeg,omitempty"`
+
+	// Raw converts high bit-depth frames (16-bit PNG or FITS) to 8-bit JPEG with a
+	// tone curve before any other processing (http and folder). Default: none
+	Raw *RawInput `json:"raw,omitempty"`
+
+	// QualitySampleRate is the fraction of frames (0-1) analyzed for luminance,
+	// sharpness, dimensions and size to spot gradual degradation. Default: 0 (disabled)
+	QualitySampleRate float64 `json:"quality_sample_rate,omitempty"`
+
+	// DedupWindow suppresses frames identical to any of the last N distinct frames,
+	// catching frozen or looping cameras. 1 = consecutive only. Default: 0 (disabled)
+	DedupWindow int `json:"dedup_window,omitempty"`
+
+	// MaxUploadAttempts drops a frame after this many failed upload cycles (each already
+	// retried once) so one rejected frame cannot block newer ones. Default: 0 (never drop)
+	MaxUploadAttempts int `json:"max_upload_attempts,omitempty"`
+
+	// RemoteDedupMinutes lists the camera's upload directory when its worker starts and
+	// drops frames queued in the last this many minutes that the server already has,
+	// e.g. after an unclean shutdown. The server must allow listing. Default: 0 (disabled)
+	RemoteDedupMinutes int `json:"remote_dedup_minutes,omitempty"`
+
+	// UploadSizeBand refuses to upload frames smaller or larger than expected, as a
+	// final guard against corrupt or misconfigured frames. Default: none
+	UploadSizeBand *UploadSizeBand `json:"upload_size_band,omitempty"`
+
+	// FilenameTimeTokens adds the frame's time provenance to its uploaded filename
+	// for servers that route by name: any of "source", "confidence" and "warn",
+	// e.g. 1735142730000_low.jpg. Default: none (timestamp only)
+	FilenameTimeTokens []string `json:"filename_time_tokens,omitempty"`
+
+	// UploadOutageSlowdown captures less often while the camera's uploads have been
+	// failing for a while, and restores the rate once one succeeds. Default: none
+	UploadOutageSlowdown *UploadOutageSlowdown `json:"upload_outage_slowdown,omitempty"`
+
+	// PostCaptureHook runs an external command on each processed frame before it is
+	// stamped and queued. Set in the config file only. Default: none
+	PostCaptureHook *PostCaptureHook `json:"post_capture_hook,omitempty"`
+
+	// ExifNote is an operator note (e.g. station identifier) written to each image's
+	// EXIF ImageDescription. The UserComment bridge marker is left unchanged
+	ExifNote string `json:"exif_note,omitempty"`
+
+	// JPEGComment also writes the bridge marker, camera ID and ExifNote to a JPEG
+	// COM segment, readable without EXIF parsing. Default: false
+	JPEGComment bool `json:"jpeg_comment,omitempty"`
+
+	// ExifSequence writes a per-camera frame number, kept across restarts, to each
+	// stamped image's EXIF ImageNumber so the server can detect gaps. Default: false
+	ExifSequence bool `json:"exif_sequence,omitempty"`
+
+	// ExifStampRetries is the number of extra exiftool attempts before falling back
+	// to the builtin EXIF writer. Default: 0 (1 retry), max 3
+	ExifStampRetries int `json:"exif_stamp_retries,omitempty"`
+
+	// TimeSource selects the clock observation times come from: "bridge", "camera"
+	// (trusted EXIF clock, e.g. GPS-synced) or "camera_if_within_tolerance". Without
+	// camera EXIF the bridge clock is used. Default: "camera_if_within_tolerance"
+	TimeSource string `json:"time_source,omitempty"`
+
+	// SettleDelaySeconds delays the first capture after the camera is started or
+	// re-added, so frames taken while the camera boots are skipped. Default: 0, max 300
+	SettleDelaySeconds int `json:"settle_delay_seconds,omitempty"`
+
 	// Upload settings (per-camera SFTP credentials)
 	Upload *Upload `json:"upload"` // SFTP credentials for this camera
 
 	// Queue settings (optional, uses global defaults if not set)
 	Queue *QueueCamera `json:"queue,omitempty"`
 
+	// CatchupMinutes is the upload backlog, in minutes of captures at this camera's
+	// interval, above which newest images are uploaded first. Default: 10
+	CatchupMinutes int `json:"catchup_minutes,omitempty"`
+
+	// LiveOnly drops the older backlog while catching up, uploading only the newest
+	// frame, for cameras feeding a live display rather than an archive. Default: false
+	LiveOnly bool `json:"live_only,omitempty"`
+
+	// UploadQuietHours overrides the global quiet window for this camera; equal
+	// start and end disable quiet hours for it
+	UploadQuietHours *QuietHours `json:"upload_quiet_hours,omitempty"`
+
+	// LatestName is a file name in the camera's upload directory (e.g. "latest.jpg")
+	// overwritten with each newly uploaded frame, for viewers that fetch a fixed URL.
+	// Default: "" (timestamped files only)
+	LatestName string `json:"latest_name,omitempty"`
+
+	// History keeps the last frames on the bridge for review in the web console
+	// (/api/cameras/{id}/history), separate from the upload queue. Default: none
+	History *History `json:"history,omitempty"`
+
+	// Timelapse renders the previous day's history frames into a video once a day
+	// and uploads it. Requires history. Default: none
+	Timelapse *Timelapse `json:"timelapse,omitempty"`
+
+	// FreshnessSLASeconds flags the camera as breaching its SLA (and alerts) when the
+	// last successful upload is older than this. Default: 0 (no SLA)
+	FreshnessSLASeconds int `json:"freshness_sla_seconds,omitempty"`
+
 	// Deprecated fields
 	RemotePath      string `json:"remote_path,omitempty"`      // Deprecated: always upload to root
 	IntervalSeconds int    `json:"interval_seconds,omitempty"` // Deprecated: use CaptureIntervalSeconds
 }
 
+// DefaultCaptureIntervalSeconds applies when neither capture rate setting is set
+const DefaultCaptureIntervalSeconds = 60
+
+// DefaultOfflineAfterSeconds is how long captures must fail before the offline image is used
+const DefaultOfflineAfterSeconds = 900
+
+// EffectiveOfflineAfterSeconds returns OfflineAfterSeconds with the default applied
+func (c *Camera) EffectiveOfflineAfterSeconds() int {
+	if c.OfflineAfterSeconds <= 0 {
+		return DefaultOfflineAfterSeconds
+	}
+	return c.OfflineAfterSeconds
+}
+
+// EffectiveCaptureIntervalSeconds returns the capture interval from whichever of
+// CaptureIntervalSeconds and CapturesPerHour is set, or the default
+func (c *Camera) EffectiveCaptureIntervalSeconds() int {
+	switch {
+	case c.CaptureIntervalSeconds > 0:
+		return c.CaptureIntervalSeconds
+	case c.CapturesPerHour > 0:
+		return max((3600+c.CapturesPerHour/2)/c.CapturesPerHour, 1)
+	}
+	return DefaultCaptureIntervalSeconds
+}
+
 // ImageProcessing controls image resolution and quality for bandwidth management
 // This is OPTIONAL - by default, images are uploaded exactly as received from the camera.
 // Only configure this if you need to reduce bandwidth usage.
@@ -60,6 +261,137 @@ type ImageProcessing struct {
 	// 0 = no re-encoding (use original)
 	// Recommended: 70-90 for weather images if re-encoding is needed
 	Quality int `json:"quality,omitempty"`
+
+	// Rotate turns the image clockwise by 0, 90, 180 or 270 degrees before resizing,
+	// for cameras mounted on their side or upside down. EXIF orientation is ignored
+	Rotate int `json:"rotate,omitempty"`
+
+	// PrivacyZones are filled with PrivacyFill in the source image, before rotation
+	// and resizing, so they cover the same area at any output size
+	PrivacyZones []PrivacyZone `json:"privacy_zones,omitempty"`
+	PrivacyFill  string        `json:"privacy_fill,omitempty"` // "#rrggbb", default black
+}
+
+// Rect is a rectangle in a camera's source image. Coordinates are source image
+// pixels, or fractions (0-1) of the source width and height when Normalized is set.
+type Rect struct {
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Width      float64 `json:"width"`
+	Height     float64 `json:"height"`
+	Normalized bool    `json:"normalized,omitempty"`
+}
+
+// PrivacyZone is a rectangle blanked out of every frame, e.g. a neighbor's yard
+type PrivacyZone = Rect
+
+// Thumbnail configures a camera's thumbnail rendition, derived from the processed
+// full image and uploaded as an independent file
+type Thumbnail struct {
+	MaxWidth   int    `json:"max_width,omitempty"`   // Default: 320 when neither size is set
+	MaxHeight  int    `json:"max_height,omitempty"`  // 0 = no limit
+	Quality    int    `json:"quality,omitempty"`     // Default: 75
+	RemotePath string `json:"remote_path,omitempty"` // Default: "thumb"; must differ from the camera's remote_path
+}
+
+// Thumbnail defaults
+const (
+	DefaultThumbnailWidth      = 320
+	DefaultThumbnailQuality    = 75
+	DefaultThumbnailRemotePath = "thumb"
+)
+
+// ImageProcessing returns the resize/quality settings for the thumbnail, with defaults applied
+func (t *Thumbnail) ImageProcessing() *ImageProcessing {
+	p := &ImageProcessing{MaxWidth: t.MaxWidth, MaxHeight: t.MaxHeight, Quality: t.Quality}
+	if p.MaxWidth == 0 && p.MaxHeight == 0 {
+		p.MaxWidth = DefaultThumbnailWidth
+	}
+	if p.Quality == 0 {
+		p.Quality = DefaultThumbnailQuality
+	}
+	return p
+}
+
+// EffectiveRemotePath returns the thumbnail's remote path, with the default applied
+func (t *Thumbnail) EffectiveRemotePath() string {
+	if t.RemotePath == "" {
+		return DefaultThumbnailRemotePath
+	}
+	return t.RemotePath
+}
+
+// Region is a named part of a camera's view, cropped from each capture and uploaded
+// as an independent image. The rectangle is in the camera's original image; the
+// camera's privacy zones and rotation apply, its resize and quality do not.
+type Region struct {
+	Name string `json:"name"` // Lowercase letters, digits, - and _
+	Rect
+	MaxWidth   int    `json:"max_width,omitempty"`   // 0 = no limit
+	MaxHeight  int    `json:"max_height,omitempty"`  // 0 = no limit
+	Quality    int    `json:"quality,omitempty"`     // Default: 90
+	RemotePath string `json:"remote_path,omitempty"` // Default: the region name
+}
+
+// ImageProcessing returns the settings for the region's image: cam's privacy zones
+// and rotation, with the region's own size and quality
+func (r *Region) ImageProcessing(cam *ImageProcessing) *ImageProcessing {
+	p := &ImageProcessing{MaxWidth: r.MaxWidth, MaxHeight: r.MaxHeight, Quality: r.Quality}
+	if cam != nil {
+		p.Rotate = cam.Rotate
+		p.PrivacyZones = cam.PrivacyZones
+		p.PrivacyFill = cam.PrivacyFill
+	}
+	return p
+}
+
+// EffectiveRemotePath returns the region's remote path, with the default applied
+func (r *Region) EffectiveRemotePath() string {
+	if r.RemotePath == "" {
+		return r.Name
+	}
+	return r.RemotePath
+}
+
+// Spectrogram configures audio spectrograms for RTSP cameras with an audio track
+type Spectrogram struct {
+	Enabled         bool   `json:"enabled"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"` // Clip length; default 5
+	IntervalSeconds int    `json:"interval_seconds,omitempty"` // Minimum time between spectrograms; default 300
+	RemotePath      string `json:"remote_path,omitempty"`      // Default: "spectrogram"
+	Width           int    `json:"width,omitempty"`            // Default: 640
+	Height          int    `json:"height,omitempty"`           // Default: 240
+}
+
+// Spectrogram defaults
+const (
+	DefaultSpectrogramSeconds    = 5
+	DefaultSpectrogramInterval   = 300
+	DefaultSpectrogramRemotePath = "spectrogram"
+)
+
+// EffectiveDurationSeconds returns the clip length, with the default applied
+func (s *Spectrogram) EffectiveDurationSeconds() int {
+	if s.DurationSeconds <= 0 {
+		return DefaultSpectrogramSeconds
+	}
+	return s.DurationSeconds
+}
+
+// EffectiveIntervalSeconds returns the minimum time between spectrograms, with the default applied
+func (s *Spectrogram) EffectiveIntervalSeconds() int {
+	if s.IntervalSeconds <= 0 {
+		return DefaultSpectrogramInterval
+	}
+	return s.IntervalSeconds
+}
+
+// EffectiveRemotePath returns the spectrogram remote path, with the default applied
+func (s *Spectrogram) EffectiveRemotePath() string {
+	if s.RemotePath == "" {
+		return DefaultSpectrogramRemotePath
+	}
+	return s.RemotePath
 }
 
 // Upload represents upload settings (SFTP only)
@@ -74,6 +406,18 @@ type Upload struct {
 
 	TimeoutConnectSeconds int `json:"timeout_connect_seconds,omitempty"` // Default: 60
 	TimeoutUploadSeconds  int `json:"timeout_upload_seconds,omitempty"`  // Default: 300 (5 minutes)
+
+	// MkdirEveryUpload ensures the remote directory exists before every upload instead
+	// of remembering it until a connection failure or missing-directory error. Default: false
+	MkdirEveryUpload bool `json:"mkdir_every_upload,omitempty"`
+
+	// VerifySize reads back the uploaded file's size and treats a mismatch as a failed
+	// upload, keeping the frame queued for retry. Default: false
+	VerifySize bool `json:"verify_size,omitempty"`
+
+	// BatchSize uploads up to this many queued frames over one connection before
+	// releasing it, draining a backlog with fewer logins. Default: 1 (max 50)
+	BatchSize int `json:"batch_size,omitempty"`
 }
 
 // DefaultUpload returns default upload settings (SFTP)
@@ -117,7 +461,22 @@ func (i *ImageProcessing) NeedsProcessing() bool {
 	if i == nil {
 		return false
 	}
-	return i.MaxWidth > 0 || i.MaxHeight > 0 || i.Quality > 0
+	return i.MaxWidth > 0 || i.MaxHeight > 0 || i.Quality > 0 || i.Rotate != 0 || len(i.PrivacyZones) > 0
+}
+
+// PrivacyFillColor returns the privacy zone fill color; black when unset
+func (i *ImageProcessing) PrivacyFillColor() (color.RGBA, error) {
+	fill := color.RGBA{A: 255}
+	if i == nil || i.PrivacyFill == "" {
+		return fill, nil
+	}
+	s := strings.TrimPrefix(i.PrivacyFill, "#")
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 3 {
+		return fill, fmt.Errorf("privacy_fill must be a #rrggbb color")
+	}
+	fill.R, fill.G, fill.B = b[0], b[1], b[2]
+	return fill, nil
 }
 
 // Auth represents HTTP authentication for camera access
@@ -134,6 +493,19 @@ type ONVIF struct {
 	Username     string `json:"username"`
 	Password     string `json:"password"`
 	ProfileToken string `json:"profile_token,omitempty"`
+
+	// Events triggers extra captures on camera-side events (PullPoint subscription)
+	Events *ONVIFEvents `json:"events,omitempty"`
+}
+
+// ONVIFEvents configures event-triggered capture for ONVIF cameras
+type ONVIFEvents struct {
+	// Topics to capture on, matched case-insensitively as substrings of the event
+	// topic (e.g. "CellMotionDetector", "RuleEngine/FieldDetector")
+	Topics []string `json:"topics"`
+
+	// CooldownSeconds is the minimum time between event-triggered captures. Default: 10
+	CooldownSeconds int `json:"cooldown_seconds,omitempty"`
 }
 
 // RTSP represents RTSP camera settings
@@ -142,16 +514,362 @@ type RTSP struct {
 	Username  string `json:"username,omitempty"`
 	Password  string `json:"password,omitempty"`
 	Substream bool   `json:"substream,omitempty"`
+
+	// SpoolThresholdKB streams frames larger than this to disk instead of holding
+	// them in memory. Default: 0 (disabled)
+	SpoolThresholdKB int `json:"spool_threshold_kb,omitempty"`
+
+	// Jittered exponential backoff between stream connections after one fails,
+	// reset by a successful frame. Defaults: 2 and 60
+	ReconnectInitialSeconds int `json:"reconnect_initial_seconds,omitempty"`
+	ReconnectMaxSeconds     int `json:"reconnect_max_seconds,omitempty"`
+}
+
+// Folder reads frames an external capture process writes to a local directory. Each
+// cycle takes the newest JPEG not yet captured; an empty folder skips the cycle.
+type Folder struct {
+	Path             string `json:"path"`                         // Directory to read; must exist
+	DeleteAfterQueue bool   `json:"delete_after_queue,omitempty"` // Remove each file once queued
+}
+
+// Agent captures through a bridge agent: a small service on a host that can reach
+// cameras this bridge cannot, authenticated with mutual TLS
+type Agent struct {
+	URL      string `json:"url"`                 // Agent base URL; must be https
+	CameraID string `json:"camera_id,omitempty"` // Camera name on the agent; default: this camera's id
+	CertFile string `json:"cert_file"`           // PEM client certificate presented to the agent
+	KeyFile  string `json:"key_file"`            // PEM key for cert_file
+	CAFile   string `json:"ca_file,omitempty"`   // PEM CA bundle for the agent's certificate; default: system roots
+}
+
+// Wakeup is a request sent before each capture, followed by a delay for the camera
+// to settle. Its failure fails the capture.
+type Wakeup struct {
+	URL         string `json:"url"`                    // http or https
+	Method      string `json:"method,omitempty"`       // GET, POST or PUT; default: GET
+	Body        string `json:"body,omitempty"`         // Request body, e.g. a preset command
+	ContentType string `json:"content_type,omitempty"` // Content-Type for body
+	Auth        *Auth  `json:"auth,omitempty"`         // Default: none (camera auth is not sent)
+	DelayMs     int    `json:"delay_ms,omitempty"`     // Wait before the snapshot; max 10000
+}
+
+// UploadSizeBand is the accepted frame size range at upload time
+type UploadSizeBand struct {
+	MinKB    int    `json:"min_kb,omitempty"`    // 0 = no minimum
+	MaxKB    int    `json:"max_kb,omitempty"`    // 0 = no maximum
+	OnReject string `json:"on_reject,omitempty"` // "drop" (default) or "keep" (needs max_upload_attempts)
+}
+
+// UploadOutageSlowdown multiplies the capture interval during an upload outage
+type UploadOutageSlowdown struct {
+	AfterMinutes int     `json:"after_minutes,omitempty"` // Failing this long with no success; default 10
+	Multiplier   float64 `json:"multiplier,omitempty"`    // Default: 4
+}
+
+// PostCaptureHook is an external command that receives a frame on stdin and writes
+// the frame to upload on stdout (nothing = unchanged). It runs without a shell.
+type PostCaptureHook struct {
+	Command        []string `json:"command"`                   // Program (absolute path) and arguments
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"` // Default: 10, max 120
+	OnFailure      string   `json:"on_failure,omitempty"`      // "fail" (default) drops the frame, "passthrough" keeps it unhooked
+}
+
+// RawInput is the tone mapping from a high bit-depth frame to 8-bit. Samples at
+// the black percentile and below become black, those at the white percentile and
+// above white; the curve shapes the levels in between.
+type RawInput struct {
+	Curve           string  `json:"curve,omitempty"`            // linear (default), gamma, log or asinh
+	Gamma           float64 `json:"gamma,omitempty"`            // For gamma; default 2.2
+	Stretch         float64 `json:"stretch,omitempty"`          // For log and asinh, higher lifts faint detail; default 10
+	BlackPercentile float64 `json:"black_percentile,omitempty"` // Default: 0 (the darkest sample)
+	WhitePercentile float64 `json:"white_percentile,omitempty"` // Default: 100 (the brightest sample)
+}
+
+// Diagnostics is a camera status endpoint polled for display, e.g. a device
+// temperature or firmware version
+type Diagnostics struct {
+	URL             string            `json:"url"`                        // http or https; must return JSON
+	Auth            *Auth             `json:"auth,omitempty"`             // Default: none (camera auth is not sent)
+	IntervalSeconds int               `json:"interval_seconds,omitempty"` // Default: 300
+	Fields          map[string]string `json:"fields"`                     // Status name to dotted JSON path, e.g. "system.temp_c"
+}
+
+// DefaultDiagnosticsIntervalSeconds is how often diagnostics are polled by default
+const DefaultDiagnosticsIntervalSeconds = 300
+
+// EffectiveIntervalSeconds returns the diagnostics poll interval, with the default applied
+func (d *Diagnostics) EffectiveIntervalSeconds() int {
+	if d.IntervalSeconds <= 0 {
+		return DefaultDiagnosticsIntervalSeconds
+	}
+	return d.IntervalSeconds
+}
+
+// Tunnel is an outbound SSH local port forward to a camera. The camera URL's host and
+// port are replaced by the forwarded local port on 127.0.0.1.
+type Tunnel struct {
+	Host           string `json:"host"`                       // SSH server that can reach the camera
+	Port           int    `json:"port,omitempty"`             // Default: 22
+	Username       string `json:"username"`                   // SSH login
+	Password       string `json:"password,omitempty"`         // Password auth, or
+	PrivateKeyPath string `json:"private_key_path,omitempty"` // Unencrypted PEM key file
+
+	// HostKeyFingerprint pins the SSH server key, e.g. "SHA256:..." from ssh-keygen -lf.
+	// Required unless InsecureIgnoreHostKey is set
+	HostKeyFingerprint    string `json:"host_key_fingerprint,omitempty"`
+	InsecureIgnoreHostKey bool   `json:"insecure_ignore_host_key,omitempty"`
+
+	// Target is the camera's host:port as seen from the SSH server.
+	// Default: host and port of the camera URL
+	Target    string `json:"target,omitempty"`
+	LocalPort int    `json:"local_port,omitempty"` // Default: 0 (any free port)
+}
+
+// CameraTLS relaxes or pins certificate verification for a camera with a self-signed
+// certificate. Pinning keeps the connection authenticated; skipping verification does not.
+type CameraTLS struct {
+	// InsecureSkipVerify accepts any certificate, so anyone on the network path can
+	// impersonate the camera. Prefer CAFile or PinnedSHA256
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`       // PEM bundle trusted instead of the system roots
+	PinnedSHA256       string `json:"pinned_sha256,omitempty"` // Hex SHA-256 fingerprint of the camera certificate
+}
+
+// HeaderRule matches a response header a camera or proxy uses to signal it is offline,
+// e.g. {"header": "X-Camera-Status", "value": "offline"}
+type HeaderRule struct {
+	Header string `json:"header"`
+	Value  string `json:"value,omitempty"` // Case-insensitive whole-value match; empty = header present
+}
+
+// Rediscovery relocates a DHCP camera after repeated connection failures by probing
+// the local network with ONVIF WS-Discovery. The effective address is kept in memory;
+// the configured URLs are not rewritten.
+type Rediscovery struct {
+	Enabled bool `json:"enabled"`
+
+	// DeviceID is matched against each reply's endpoint UUID and scopes, ignoring case
+	// and separators: a MAC address, serial number or the endpoint UUID
+	DeviceID string `json:"device_id"`
+
+	FailureThreshold   int `json:"failure_threshold,omitempty"`    // Consecutive connection failures before probing. Default: 3
+	MinIntervalMinutes int `json:"min_interval_minutes,omitempty"` // Minimum time between probes. Default: 15, min 5
+}
+
+// History retains recent processed frames on disk, evicting the oldest first when
+// either limit is reached
+type History struct {
+	Enabled   bool `json:"enabled"`
+	MaxFrames int  `json:"max_frames,omitempty"`  // Default: 50, max 3000
+	MaxSizeMB int  `json:"max_size_mb,omitempty"` // Default: 20, max 2000
+}
+
+// Timelapse builds a daily summary from the camera's history. Frames are sampled
+// evenly from local midnight to midnight in the bridge's timezone.
+type Timelapse struct {
+	Enabled    bool   `json:"enabled"`
+	Format     string `json:"format,omitempty"`      // "mp4" (default) or "gif"
+	FPS        int    `json:"fps,omitempty"`         // Default: 10
+	Hour       *int   `json:"hour,omitempty"`        // Local hour to build at. Default: 2
+	RemotePath string `json:"remote_path,omitempty"` // Default: "timelapse"
+	MaxWidth   int    `json:"max_width,omitempty"`   // Default: 640
+	MaxFrames  int    `json:"max_frames,omitempty"`  // Default: 240
+}
+
+// Timelapse defaults
+const (
+	DefaultTimelapseHour       = 2
+	DefaultTimelapseRemotePath = "timelapse"
+)
+
+// EffectiveHour returns the local hour the timelapse is built, with the default applied
+func (t *Timelapse) EffectiveHour() int {
+	if t.Hour == nil {
+		return DefaultTimelapseHour
+	}
+	return *t.Hour
+}
+
+// EffectiveRemotePath returns the timelapse's remote path, with the default applied
+func (t *Timelapse) EffectiveRemotePath() string {
+	if t.RemotePath == "" {
+		return DefaultTimelapseRemotePath
+	}
+	return t.RemotePath
+}
+
+// UploadConcurrency auto-tunes concurrent uploads: one more after a run of fast,
+// successful uploads, halved on failures, timeouts, auth backoff or slow uploads.
+// Starts at max_concurrent_uploads. Applied on restart
+type UploadConcurrency struct {
+	AutoTune             bool `json:"auto_tune"`
+	Min                  int  `json:"min,omitempty"`                    // Default: 1
+	Max                  int  `json:"max,omitempty"`                    // Default: max_concurrent_uploads; at most 8
+	TargetLatencySeconds int  `json:"target_latency_seconds,omitempty"` // Slower uploads count as congestion. Default: 60
+}
+
+// UploadCircuitBreaker stops uploads to a server that cannot be reached: after
+// FailureThreshold consecutive failures, uploads to it pause for CooldownSeconds,
+// then one probe upload decides whether they resume
+type UploadCircuitBreaker struct {
+	Enabled          bool `json:"enabled"`
+	FailureThreshold int  `json:"failure_threshold,omitempty"` // Default: 5
+	CooldownSeconds  int  `json:"cooldown_seconds,omitempty"`  // Default: 60
+}
+
+// WorkerRetry retries starting camera workers that failed to start (e.g. a camera
+// unreachable when saved): first after 30 seconds, then doubling up to
+// MaxIntervalSeconds, at most MaxAttempts times until the camera is next edited
+type WorkerRetry struct {
+	Enabled            bool `json:"enabled"`
+	MaxAttempts        int  `json:"max_attempts,omitempty"`         // Default: 10
+	MaxIntervalSeconds int  `json:"max_interval_seconds,omitempty"` // Default: 900
+}
+
+// ClockJump detects the wall clock jumping, as after a system suspend and resume or
+// a manual clock change: capture timers are realigned instead of catching up, the
+// daily upload counter follows the new date and time health is checked again
+type ClockJump struct {
+	Enabled          bool `json:"enabled"`
+	ThresholdSeconds int  `json:"threshold_seconds,omitempty"` // Default: 120
+}
+
+// ThroughputRates tracks each camera's achieved captures and bytes per minute, both
+// captured and uploaded, as decaying averages shown in status and metrics next to
+// the configured capture rate
+type ThroughputRates struct {
+	Enabled         bool `json:"enabled"`
+	HalfLifeSeconds int  `json:"half_life_seconds,omitempty"` // Default: 300
 }
 
 // Global represents global settings
 type Global struct {
-	CaptureTimeoutSeconds int            `json:"capture_timeout_seconds,omitempty"` // Default: 30
-	RTSPTimeoutSeconds    int            `json:"rtsp_timeout_seconds,omitempty"`    // Default: 10
-	MaxConcurrentUploads  int            `json:"max_concurrent_uploads,omitempty"`  // Default: 2 (conservative for slow networks)
-	Backoff               *Backoff       `json:"backoff,omitempty"`
-	DegradedMode          *DegradedMode  `json:"degraded_mode,omitempty"`
-	TimeAuthority         *TimeAuthority `json:"time_authority,omitempty"`
+	CaptureTimeoutSeconds int                `json:"capture_timeout_seconds,omitempty"` // Default: 30
+	RTSPTimeoutSeconds    int                `json:"rtsp_timeout_seconds,omitempty"`    // Default: 10
+	MaxConcurrentUploads  int                `json:"max_concurrent_uploads,omitempty"`  // Default: 2 (conservative for slow networks)
+	UploadConcurrency     *UploadConcurrency `json:"upload_concurrency,omitempty"`      // Optional auto-tuning of concurrent uploads
+	MaxConcurrentRequests int                `json:"max_concurrent_requests,omitempty"` // Default: 4 expensive web requests in flight
+	Backoff               *Backoff           `json:"backoff,omitempty"`
+	DegradedMode          *DegradedMode      `json:"degraded_mode,omitempty"`
+	TimeAuthority         *TimeAuthority     `json:"time_authority,omitempty"`
+
+	// UploadCircuitBreaker pauses uploads to an unreachable server. Default: disabled
+	UploadCircuitBreaker *UploadCircuitBreaker `json:"upload_circuit_breaker,omitempty"`
+
+	// WorkerRetry retries camera workers that failed to start. Default: disabled
+	WorkerRetry *WorkerRetry `json:"worker_retry,omitempty"`
+
+	// UploadConnectionIntervalMs is the minimum time between new upload connections
+	// across all cameras, keeping logins below fail2ban thresholds. Default: 2000
+	UploadConnectionIntervalMs int `json:"upload_connection_interval_ms,omitempty"`
+
+	// UploadMaxKBps caps the total upload rate across all cameras and concurrent
+	// uploads, in KB/s, so uploads do not saturate a shared link. Default: 0 (unlimited)
+	UploadMaxKBps int `json:"upload_max_kbps,omitempty"`
+
+	// StrictStartup exits non-zero on unrecoverable init failures so a supervisor
+	// restarts the bridge instead of it running degraded. Default: false
+	StrictStartup bool `json:"strict_startup,omitempty"`
+
+	// UploadQuietHours suspends uploads during a daily window in the configured
+	// timezone; capture and queueing continue. Default: none
+	UploadQuietHours *QuietHours `json:"upload_quiet_hours,omitempty"`
+
+	// CaptureHangMarginSeconds is how long past capture_timeout_seconds a capture that
+	// ignores cancellation is waited for before it is abandoned. Default: 15
+	CaptureHangMarginSeconds int `json:"capture_hang_margin_seconds,omitempty"`
+
+	// AlertWebhookURL receives a JSON POST for operator alerts such as freshness SLA
+	// breaches and recoveries. Default: none (alerts are only logged)
+	AlertWebhookURL string `json:"alert_webhook_url,omitempty"`
+
+	// CameraUpWindowSeconds is how recent a camera's last successful capture and upload
+	// must both be for /metrics to report camera_up 1.
+	// Default: three capture intervals, at least 300
+	CameraUpWindowSeconds int `json:"camera_up_window_seconds,omitempty"`
+
+	// ShutdownSnapshot writes the run summary and last status to last_shutdown.json in
+	// the config directory on graceful shutdown. Default: false (summary is only logged)
+	ShutdownSnapshot bool `json:"shutdown_snapshot,omitempty"`
+
+	// SharedFetch lets cameras with the same source URL and credentials (e.g. several
+	// crops of one wide camera) share a single fetch when they capture together.
+	// Applies to cameras started after the change. Default: false
+	SharedFetch bool `json:"shared_fetch,omitempty"`
+
+	// ShareUploadConnections keeps one upload connection open per account (host, port,
+	// username and password) for all cameras using it, instead of logging in for every
+	// upload. Cameras take turns on it and keep their own paths. Applies to cameras
+	// started after the change. Default: false
+	ShareUploadConnections bool `json:"share_upload_connections,omitempty"`
+
+	// SharedFetchReuseMs reuses a completed shared fetch for captures starting this
+	// soon after it, not just ones overlapping it. Default: 0, max 10000
+	SharedFetchReuseMs int `json:"shared_fetch_reuse_ms,omitempty"`
+
+	// GoroutineCeiling is a soft limit on total goroutines; above it previews,
+	// fresh /metrics collection and quality self-checks are skipped until the count
+	// falls back below 90% of it. Default: 0 (disabled), minimum 50
+	GoroutineCeiling int `json:"goroutine_ceiling,omitempty"`
+
+	// LowDiskImage lowers image quality on every camera while the queue disk is
+	// filling up, and restores it once the disk recovers. Default: disabled
+	LowDiskImage *LowDiskImage `json:"low_disk_image,omitempty"`
+
+	// ExifToolHangLimit is how many exiftool runs in a row may be killed for hanging
+	// before stamping switches to the builtin writer for ExifToolHangCooldownSeconds.
+	// Defaults: 3 and 300
+	ExifToolHangLimit           int `json:"exiftool_hang_limit,omitempty"`
+	ExifToolHangCooldownSeconds int `json:"exiftool_hang_cooldown_seconds,omitempty"`
+
+	// ExifToolStayOpen stamps frames through one persistent exiftool process
+	// (-stay_open) instead of starting exiftool per frame, falling back to a
+	// one-shot run if it fails. It is stopped after ExifToolIdleSeconds without
+	// frames. Defaults: disabled, 300
+	ExifToolStayOpen    bool `json:"exiftool_stay_open,omitempty"`
+	ExifToolIdleSeconds int  `json:"exiftool_idle_seconds,omitempty"`
+
+	// ExifToolRecheckSeconds is how often exiftool is looked for again while it is
+	// missing, so installing it takes effect without a restart. Default: 300
+	ExifToolRecheckSeconds int `json:"exiftool_recheck_seconds,omitempty"`
+
+	// QueueFallbackPath is where camera queues move if the queue filesystem turns
+	// read-only, e.g. a directory on the SD card. "" = no fallback: captures are
+	// dropped until the queue filesystem is writable again. Read at startup
+	QueueFallbackPath string `json:"queue_fallback_path,omitempty"`
+
+	// StayActiveWithoutCameras keeps the upload loop and queue maintenance running
+	// while no camera is enabled. Default: false, the bridge idles until a camera is
+	// enabled or added (saves power on battery or solar sites). Read at startup
+	StayActiveWithoutCameras bool `json:"stay_active_without_cameras,omitempty"`
+
+	// ClockJump realigns capture timers after a suspend/resume or clock change.
+	// Default: disabled. Read at startup
+	ClockJump *ClockJump `json:"clock_jump,omitempty"`
+
+	// ThroughputRates tracks achieved capture and upload rates per camera.
+	// Default: disabled. Applied when a camera is (re)started
+	ThroughputRates *ThroughputRates `json:"throughput_rates,omitempty"`
+}
+
+// LowDiskImage caps JPEG quality and width on every camera while queue disk usage
+// is at or above TriggerLevel, so frames take less space; the caps are lifted once
+// usage is back below the warning level. A cap never raises a camera's own setting.
+type LowDiskImage struct {
+	Enabled      bool   `json:"enabled"`
+	TriggerLevel string `json:"trigger_level,omitempty"` // "warning" (70% used, default) or "critical" (85%)
+	Quality      int    `json:"quality,omitempty"`       // JPEG quality while reduced. Default: 60
+	MaxWidth     int    `json:"max_width,omitempty"`     // Width cap while reduced. Default: 0 (no cap)
+}
+
+// DefaultLowDiskQuality is the JPEG quality applied while disk space is low
+const DefaultLowDiskQuality = 60
+
+// QuietHours is a daily window given as "HH:MM" local times; a start later than
+// the end crosses midnight (e.g. 22:00-06:00)
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
 }
 
 // Backoff represents exponential backoff settings
@@ -196,19 +914,127 @@ type WebConsole struct {
 	Port     int    `json:"port,omitempty"`     // Default: 1229
 	Password string `json:"password,omitempty"` // Default: "aviationwx"
 
+	// Protection for operational endpoints, so load balancers can poll health
+	// while metrics stays protected (or vice versa)
+	HealthAuth   string `json:"health_auth,omitempty"`   // "none" (default), "token", "basic"
+	MetricsAuth  string `json:"metrics_auth,omitempty"`  // "none" (default), "token", "basic"
+	MetricsToken string `json:"metrics_token,omitempty"` // Bearer token for "token" mode
+
+	// DefaultPasswordPolicy sets what happens while the password is still the
+	// default: "warn" (default), "require_change" or "localhost_only"
+	DefaultPasswordPolicy string `json:"default_password_policy,omitempty"`
+
+	// TrustedProxies lists reverse proxies (CIDRs or IPs) whose X-Forwarded-For and
+	// X-Forwarded-Proto headers are trusted. Default: none, headers are ignored
+	TrustedProxies []string `json:"trusted_proxies,omitempty"`
+
+	// CheckUploadOnSave tests a camera's upload login when it is added, or its upload
+	// settings change, through the API, rejecting the save if it fails. A request
+	// with ?skip_connectivity_check=true saves anyway. Default: false
+	CheckUploadOnSave bool `json:"check_upload_on_save,omitempty"`
+
+	// Restream serves each camera's latest frames as a low-framerate MJPEG stream
+	// at /api/cameras/{id}/stream.mjpeg. Default: disabled
+	Restream *Restream `json:"restream,omitempty"`
+
 	// Deprecated: use Password instead
 	BasicAuth *BasicAuth `json:"basic_auth,omitempty"`
 }
 
+// Restream limits the MJPEG restream of cached frames
+type Restream struct {
+	Enabled    bool `json:"enabled"`
+	MaxFPS     int  `json:"max_fps,omitempty"`     // Frames per second per client; default 1
+	MaxClients int  `json:"max_clients,omitempty"` // Open streams across all cameras; default 2
+}
+
+// Restream defaults
+const (
+	DefaultRestreamMaxFPS     = 1
+	DefaultRestreamMaxClients = 2
+)
+
+// EffectiveMaxFPS returns MaxFPS or its default
+func (r *Restream) EffectiveMaxFPS() int {
+	if r.MaxFPS <= 0 {
+		return DefaultRestreamMaxFPS
+	}
+	return r.MaxFPS
+}
+
+// EffectiveMaxClients returns MaxClients or its default
+func (r *Restream) EffectiveMaxClients() int {
+	if r.MaxClients <= 0 {
+		return DefaultRestreamMaxClients
+	}
+	return r.MaxClients
+}
+
+// TrustedProxyPrefixes parses TrustedProxies; a bare IP is a single-address prefix
+func (wc WebConsole) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(wc.TrustedProxies))
+	for _, s := range wc.TrustedProxies {
+		if prefix, err := netip.ParsePrefix(s); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an IP address or CIDR", s)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Endpoint protection modes for WebConsole.HealthAuth and WebConsole.MetricsAuth
+const (
+	EndpointAuthNone  = "none"  // Unauthenticated
+	EndpointAuthToken = "token" // Authorization: Bearer <metrics_token>
+	EndpointAuthBasic = "basic" // Web console basic auth
+)
+
+// DefaultWebPassword is the web console password shipped with every install
+const DefaultWebPassword = "aviationwx"
+
+// Policies for WebConsole.DefaultPasswordPolicy while the default password is in use
+const (
+	DefaultPasswordWarn          = "warn"           // Log and flag in status only
+	DefaultPasswordRequireChange = "require_change" // Refuse config changes except the password
+	DefaultPasswordLocalhostOnly = "localhost_only" // Serve the console on localhost only
+)
+
 // DefaultWebConsole returns default web console settings
 func DefaultWebConsole() WebConsole {
 	return WebConsole{
 		Enabled:  true,
 		Port:     1229,
-		Password: "aviationwx",
+		Password: DefaultWebPassword,
 	}
 }
 
+// MQTT represents the optional MQTT bridge: capture commands in, events out
+type MQTT struct {
+	Enabled               bool   `json:"enabled,omitempty"`
+	Broker                string `json:"broker,omitempty"`                   // host:port, e.g. "mqtt.local:1883"
+	TLS                   bool   `json:"tls,omitempty"`                      // Connect with TLS (usually port 8883)
+	TLSInsecureSkipVerify bool   `json:"tls_insecure_skip_verify,omitempty"` // Accept self-signed broker certificates
+	ClientID              string `json:"client_id,omitempty"`                // Default: aviationwx-bridge-<bridge id>
+	Username              string `json:"username,omitempty"`
+	Password              string `json:"password,omitempty"`
+	TopicPrefix           string `json:"topic_prefix,omitempty"`       // Default: aviationwx/bridge/<bridge id>
+	KeepAliveSeconds      int    `json:"keep_alive_seconds,omitempty"` // Default: 60
+}
+
+// StatsD pushes core capture, upload and queue metrics to a StatsD collector over UDP
+type StatsD struct {
+	Enabled              bool   `json:"enabled,omitempty"`
+	Address              string `json:"address,omitempty"`                // host:port, e.g. "statsd.local:8125"
+	Prefix               string `json:"prefix,omitempty"`                 // Default: aviationwx_bridge
+	FlushIntervalSeconds int    `json:"flush_interval_seconds,omitempty"` // Default: 10
+}
+
 // BasicAuth represents basic authentication settings (deprecated)
 type BasicAuth struct {
 	Username string `json:"username,omitempty"`
@@ -217,12 +1043,15 @@ type BasicAuth struct {
 
 // QueueGlobal represents global queue manager settings
 type QueueGlobal struct {
-	BasePath           string       `json:"base_path,omitempty"`            // Default: "/dev/shm/aviationwx"
-	MaxTotalSizeMB     int          `json:"max_total_size_mb,omitempty"`    // Default: 100 (all cameras)
-	MemoryCheckSeconds int          `json:"memory_check_seconds,omitempty"` // Default: 5
-	EmergencyThinRatio float64      `json:"emergency_thin_ratio,omitempty"` // Default: 0.5
-	MaxHeapMB          int          `json:"max_heap_mb,omitempty"`          // Default: 400 (for 512MB Pi)
-	Defaults           *QueueCamera `json:"defaults,omitempty"`             // Default settings for cameras
+	BasePath           string       `json:"base_path,omitempty"`              // Default: "/dev/shm/aviationwx"
+	MaxTotalSizeMB     int          `json:"max_total_size_mb,omitempty"`      // Default: 100 (all cameras)
+	MemoryCheckSeconds int          `json:"memory_check_seconds,omitempty"`   // Default: 5
+	EmergencyThinRatio float64      `json:"emergency_thin_ratio,omitempty"`   // Default: 0.5
+	MaxHeapMB          int          `json:"max_heap_mb,omitempty"`            // Default: 400 (for 512MB Pi)
+	CompactionSeconds  int          `json:"compaction_seconds,omitempty"`     // Default: 600
+	OrphanMaxAgeSecs   int          `json:"orphan_max_age_seconds,omitempty"` // Default: 600
+	ReconcileSeconds   int          `json:"reconcile_seconds,omitempty"`      // Default: 60
+	Defaults           *QueueCamera `json:"defaults,omitempty"`               // Default settings for cameras
 }
 
 // QueueCamera represents per-camera queue settings
@@ -239,6 +1068,17 @@ type QueueCamera struct {
 	ThresholdCritical      float64 `json:"threshold_critical,omitempty"`     // Default: 0.95
 	PauseCaptureOnCritical bool    `json:"pause_capture_critical,omitempty"` // Default: true
 	ResumeThreshold        float64 `json:"resume_threshold,omitempty"`       // Default: 0.70
+
+	// SurvivalMinutes sizes max_files, max_size_mb and max_age_seconds to hold this
+	// many minutes of frames at the capture interval; explicit limits still win.
+	// FrameSizeKB is the frame size assumed for max_size_mb. Default: 0 (off), 300
+	SurvivalMinutes int `json:"survival_minutes,omitempty"`
+	FrameSizeKB     int `json:"frame_size_kb,omitempty"`
+
+	// PreferPauseOverThin never thins the queue: capture pauses at PauseThreshold of
+	// capacity instead, until it drains below ResumeThreshold. Default: false, 0.80
+	PreferPauseOverThin bool    `json:"prefer_pause_over_thin,omitempty"`
+	PauseThreshold      float64 `json:"pause_threshold,omitempty"`
 }
 
 // TimeAuthority represents time authority settings
@@ -246,6 +1086,16 @@ type TimeAuthority struct {
 	CameraToleranceSeconds   int `json:"camera_tolerance_seconds,omitempty"`    // Default: 5
 	CameraWarnDriftSeconds   int `json:"camera_warn_drift_seconds,omitempty"`   // Default: 30
 	CameraRejectDriftSeconds int `json:"camera_reject_drift_seconds,omitempty"` // Default: 300
+
+	// Capture behavior while bridge time is unhealthy: "stamp_low" (default),
+	// "unstamped", or "pause"
+	UnhealthyPolicy string `json:"unhealthy_policy,omitempty"`
+
+	// Handling of frames timestamped earlier than the newest queued frame (clock stepped
+	// backward): "clamp" (default), "reject", or "auto" (clamp while NTP is healthy,
+	// reject while unhealthy). Regressions within the tolerance are ignored. Default: 2
+	RegressionPolicy           string `json:"regression_policy,omitempty"`
+	RegressionToleranceSeconds int    `json:"regression_tolerance_seconds,omitempty"`
 }
 
 // IsFirstRun returns true if this appears to be an unconfigured installation
@@ -256,7 +1106,7 @@ func (c *Config) IsFirstRun() bool {
 // GetWebPassword returns the web console password with fallback to default
 func (c *Config) GetWebPassword() string {
 	if c.WebConsole == nil {
-		return "aviationwx"
+		return DefaultWebPassword
 	}
 	if c.WebConsole.Password != "" {
 		return c.WebConsole.Password
@@ -265,7 +1115,7 @@ func (c *Config) GetWebPassword() string {
 	if c.WebConsole.BasicAuth != nil && c.WebConsole.BasicAuth.Password != "" {
 		return c.WebConsole.BasicAuth.Password
 	}
-	return "aviationwx"
+	return DefaultWebPassword
 }
 
 // GetWebPort returns the web console port with fallback to default
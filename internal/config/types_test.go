@@ -145,6 +145,11 @@ func TestImageProcessing_NeedsProcessing(t *testing.T) {
 			config:   &ImageProcessing{Quality: 85},
 			expected: true,
 		},
+		{
+			name:     "rotation set returns true",
+			config:   &ImageProcessing{Rotate: 180},
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -220,3 +225,40 @@ func TestDefaultWebConsole(t *testing.T) {
 		t.Errorf("Password = %v, want aviationwx", wc.Password)
 	}
 }
+
+func TestThumbnail_Defaults(t *testing.T) {
+	thumb := &Thumbnail{}
+	p := thumb.ImageProcessing()
+	if p.MaxWidth != DefaultThumbnailWidth || p.MaxHeight != 0 || p.Quality != DefaultThumbnailQuality {
+		t.Errorf("defaults = %+v", p)
+	}
+	if thumb.EffectiveRemotePath() != "thumb" {
+		t.Errorf("EffectiveRemotePath() = %q, want thumb", thumb.EffectiveRemotePath())
+	}
+
+	thumb = &Thumbnail{MaxHeight: 200, Quality: 60, RemotePath: "live"}
+	if p := thumb.ImageProcessing(); p.MaxWidth != 0 || p.MaxHeight != 200 || p.Quality != 60 {
+		t.Errorf("explicit settings = %+v", p)
+	}
+	if thumb.EffectiveRemotePath() != "live" {
+		t.Errorf("EffectiveRemotePath() = %q, want live", thumb.EffectiveRemotePath())
+	}
+}
+
+func TestCamera_EffectiveCaptureIntervalSeconds(t *testing.T) {
+	tests := []struct {
+		cam  Camera
+		want int
+	}{
+		{Camera{}, DefaultCaptureIntervalSeconds},
+		{Camera{CaptureIntervalSeconds: 600}, 600},
+		{Camera{CapturesPerHour: 6}, 600},
+		{Camera{CapturesPerHour: 7}, 514},
+		{Camera{CapturesPerHour: 3600}, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.cam.EffectiveCaptureIntervalSeconds(); got != tt.want {
+			t.Errorf("%+v: got %d, want %d", tt.cam, got, tt.want)
+		}
+	}
+}
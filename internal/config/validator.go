@@ -1,8 +1,16 @@
 package config
 
 import (
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path"
 	"strings"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
 )
 
 // Validate validates the configuration
@@ -33,37 +41,432 @@ func Validate(c *Config) error {
 			return fmt.Errorf("camera[%d]: %w", i, err)
 		}
 
-		// Check for duplicate IDs
-		if cameraIDs[cam.ID] {
+		// Check for duplicate IDs; case-insensitive filesystems would share their queues
+		if cameraIDs[strings.ToLower(cam.ID)] {
 			return fmt.Errorf("camera[%d]: duplicate camera ID: %s", i, cam.ID)
 		}
-		cameraIDs[cam.ID] = true
+		cameraIDs[strings.ToLower(cam.ID)] = true
 	}
 
 	return nil
 }
 
-// validateCamera validates a single camera configuration
+// Circuit breaker limits: enough failures to ride out a blip, and a cooldown short
+// enough that a recovered server is noticed within the hour
+const (
+	MaxBreakerFailureThreshold = 100
+	MaxBreakerCooldownSeconds  = 3600
+)
+
+// Worker retry limits
+const (
+	MaxWorkerRetryAttempts        = 1000
+	MaxWorkerRetryIntervalSeconds = 86400
+)
+
+// Restream limits; each client re-sends every frame, so both multiply bandwidth
+const (
+	MaxRestreamFPS     = 5
+	MaxRestreamClients = 10
+)
+
+// Clock jump threshold bounds; jumps are checked every few seconds, so a shorter
+// threshold would mistake a busy moment for a resume
+const (
+	MinClockJumpThresholdSeconds = 10
+	MaxClockJumpThresholdSeconds = 86400
+)
+
+// Throughput rate half-life bounds; shorter follows every capture, longer lags
+// changes by hours
+const (
+	MinThroughputHalfLifeSeconds = 30
+	MaxThroughputHalfLifeSeconds = 3600
+)
+
+// MaxUploadConnectionIntervalMs caps upload_connection_interval_ms; every upload
+// waits its turn, so a longer gap would throttle the whole bridge
+const MaxUploadConnectionIntervalMs = 60000
+
+// MaxUploadKBps caps upload_max_kbps (1 GB/s)
+const MaxUploadKBps = 1024 * 1024
+
+// CapturesPerHour bounds, matching capture intervals of 1800 down to 1 seconds
+const (
+	MinCapturesPerHour = 2
+	MaxCapturesPerHour = 3600
+)
+
+// ValidateCaptureRate checks that at most one of capture_interval_seconds and
+// captures_per_hour is set, and that captures_per_hour is in range
+func ValidateCaptureRate(cam *Camera) error {
+	if cam.CaptureIntervalSeconds != 0 && cam.CapturesPerHour != 0 {
+		return fmt.Errorf("captures_per_hour cannot be combined with capture_interval_seconds")
+	}
+	if cam.CapturesPerHour != 0 && (cam.CapturesPerHour < MinCapturesPerHour || cam.CapturesPerHour > MaxCapturesPerHour) {
+		return fmt.Errorf("captures_per_hour must be between %d and %d", MinCapturesPerHour, MaxCapturesPerHour)
+	}
+	return nil
+}
+
+// MaxSettleDelaySeconds caps settle_delay_seconds
+const MaxSettleDelaySeconds = 300
+
+// MaxMinIntervalSeconds caps min_interval_seconds at the longest capture interval
+const MaxMinIntervalSeconds = 1800
+
+// MaxRemoteDedupMinutes caps remote_dedup_minutes at a day of queued frames
+const MaxRemoteDedupMinutes = 1440
+
+// Upload outage slowdown limits
+const (
+	MaxOutageSlowdownAfterMinutes = 1440
+	MaxOutageSlowdownMultiplier   = 60
+)
+
+// MaxWakeupDelayMs caps wakeup.delay_ms, which counts against the capture timeout
+const MaxWakeupDelayMs = 10000
+
+// Bounds for diagnostics polling
+const (
+	MinDiagnosticsIntervalSeconds = 30
+	MaxDiagnosticsIntervalSeconds = 86400
+	MaxDiagnosticsFields          = 16
+)
+
+// MinRediscoveryIntervalMinutes is the shortest allowed time between rediscovery
+// probes, which are multicast to the whole network
+const MinRediscoveryIntervalMinutes = 5
+
+// History limits keep the on-device frame history from filling the storage card
+const (
+	MaxHistoryFrames = 3000
+	MaxHistorySizeMB = 2000
+)
+
+// Spectrogram limits
+const (
+	MaxSpectrogramSeconds = 30
+	MaxSpectrogramSize    = 2000
+)
+
+// MaxPrivacyZones caps image.privacy_zones per camera
+const MaxPrivacyZones = 32
+
+// MaxRegions caps regions per camera; each crops and encodes every capture
+const MaxRegions = 8
+
+// MaxSnapshotSizeKB caps max_snapshot_kb; the whole snapshot is held in memory
+const MaxSnapshotSizeKB = 100 * 1024
+
+// Queue auto-sizing limits
+const (
+	MaxQueueSurvivalMinutes = 7 * 24 * 60
+	MaxQueueFrameSizeKB     = 20 * 1024
+)
+
+// Timelapse limits
+const (
+	MaxTimelapseFPS    = 60
+	MaxTimelapseFrames = 1000
+)
+
+// MaxSharedFetchReuseMs caps shared_fetch_reuse_ms; a frame reused longer than this
+// would be stamped noticeably later than it was taken
+const MaxSharedFetchReuseMs = 10000
+
+// MinGoroutineCeiling keeps the goroutine ceiling above an idle bridge's own count,
+// which would otherwise shed work permanently
+const MinGoroutineCeiling = 50
+
+// MaxExifToolHangCooldownSeconds caps exiftool_hang_cooldown_seconds
+const MaxExifToolHangCooldownSeconds = 3600
+
+// MaxExifToolIdleSeconds caps exiftool_idle_seconds
+const MaxExifToolIdleSeconds = 86400
+
+// MaxExifToolRecheckSeconds caps exiftool_recheck_seconds
+const MaxExifToolRecheckSeconds = 86400
+
+// MaxAutoTuneConcurrency caps auto-tuned upload concurrency; each upload is a separate
+// login, and more simultaneous logins risk fail2ban
+const MaxAutoTuneConcurrency = 8
+
+// ValidateGlobal validates global operational settings
+func ValidateGlobal(g *Global) error {
+	if g == nil {
+		return nil
+	}
+	if g.UploadConnectionIntervalMs < 0 || g.UploadConnectionIntervalMs > MaxUploadConnectionIntervalMs {
+		return fmt.Errorf("upload_connection_interval_ms must be between 0 and %d", MaxUploadConnectionIntervalMs)
+	}
+	if g.UploadMaxKBps < 0 || g.UploadMaxKBps > MaxUploadKBps {
+		return fmt.Errorf("upload_max_kbps must be between 0 and %d", MaxUploadKBps)
+	}
+	if ta := g.TimeAuthority; ta != nil {
+		switch ta.RegressionPolicy {
+		case "", "clamp", "reject", "auto":
+		default:
+			return fmt.Errorf("time_authority.regression_policy must be clamp, reject or auto")
+		}
+		switch ta.UnhealthyPolicy {
+		case "", "stamp_low", "unstamped", "pause":
+		default:
+			return fmt.Errorf("time_authority.unhealthy_policy must be stamp_low, unstamped or pause")
+		}
+		if ta.RegressionToleranceSeconds < 0 {
+			return fmt.Errorf("time_authority.regression_tolerance_seconds cannot be negative")
+		}
+	}
+	if g.SharedFetchReuseMs < 0 || g.SharedFetchReuseMs > MaxSharedFetchReuseMs {
+		return fmt.Errorf("shared_fetch_reuse_ms must be between 0 and %d", MaxSharedFetchReuseMs)
+	}
+	if g.CameraUpWindowSeconds < 0 {
+		return fmt.Errorf("camera_up_window_seconds cannot be negative")
+	}
+	if g.GoroutineCeiling != 0 && g.GoroutineCeiling < MinGoroutineCeiling {
+		return fmt.Errorf("goroutine_ceiling must be 0 (disabled) or at least %d", MinGoroutineCeiling)
+	}
+	if ld := g.LowDiskImage; ld != nil && ld.Enabled {
+		if ld.TriggerLevel != "" && ld.TriggerLevel != "warning" && ld.TriggerLevel != "critical" {
+			return fmt.Errorf("low_disk_image.trigger_level must be warning or critical")
+		}
+		if ld.Quality < 0 || ld.Quality > 100 {
+			return fmt.Errorf("low_disk_image.quality must be between 0 and 100")
+		}
+		if ld.MaxWidth < 0 {
+			return fmt.Errorf("low_disk_image.max_width cannot be negative")
+		}
+	}
+	if q := g.UploadQuietHours; q != nil {
+		if err := validateQuietHours(q, false); err != nil {
+			return fmt.Errorf("upload_quiet_hours: %w", err)
+		}
+	}
+	if g.ExifToolHangLimit < 0 {
+		return fmt.Errorf("exiftool_hang_limit cannot be negative")
+	}
+	if g.ExifToolHangCooldownSeconds < 0 || g.ExifToolHangCooldownSeconds > MaxExifToolHangCooldownSeconds {
+		return fmt.Errorf("exiftool_hang_cooldown_seconds must be between 0 and %d", MaxExifToolHangCooldownSeconds)
+	}
+	if g.ExifToolIdleSeconds < 0 || g.ExifToolIdleSeconds > MaxExifToolIdleSeconds {
+		return fmt.Errorf("exiftool_idle_seconds must be between 0 and %d", MaxExifToolIdleSeconds)
+	}
+	if g.ExifToolRecheckSeconds < 0 || g.ExifToolRecheckSeconds > MaxExifToolRecheckSeconds {
+		return fmt.Errorf("exiftool_recheck_seconds must be between 0 and %d", MaxExifToolRecheckSeconds)
+	}
+	if g.QueueFallbackPath != "" && !path.IsAbs(g.QueueFallbackPath) {
+		return fmt.Errorf("queue_fallback_path must be an absolute path")
+	}
+	if cb := g.UploadCircuitBreaker; cb != nil && cb.Enabled {
+		if cb.FailureThreshold < 0 || cb.FailureThreshold > MaxBreakerFailureThreshold {
+			return fmt.Errorf("upload_circuit_breaker.failure_threshold must be between 0 and %d", MaxBreakerFailureThreshold)
+		}
+		if cb.CooldownSeconds < 0 || cb.CooldownSeconds > MaxBreakerCooldownSeconds {
+			return fmt.Errorf("upload_circuit_breaker.cooldown_seconds must be between 0 and %d", MaxBreakerCooldownSeconds)
+		}
+	}
+	if wr := g.WorkerRetry; wr != nil && wr.Enabled {
+		if wr.MaxAttempts < 0 || wr.MaxAttempts > MaxWorkerRetryAttempts {
+			return fmt.Errorf("worker_retry.max_attempts must be between 0 and %d", MaxWorkerRetryAttempts)
+		}
+		if wr.MaxIntervalSeconds < 0 || wr.MaxIntervalSeconds > MaxWorkerRetryIntervalSeconds {
+			return fmt.Errorf("worker_retry.max_interval_seconds must be between 0 and %d", MaxWorkerRetryIntervalSeconds)
+		}
+	}
+	if cj := g.ClockJump; cj != nil && cj.Enabled && cj.ThresholdSeconds != 0 {
+		if cj.ThresholdSeconds < MinClockJumpThresholdSeconds || cj.ThresholdSeconds > MaxClockJumpThresholdSeconds {
+			return fmt.Errorf("clock_jump.threshold_seconds must be between %d and %d", MinClockJumpThresholdSeconds, MaxClockJumpThresholdSeconds)
+		}
+	}
+	if tr := g.ThroughputRates; tr != nil && tr.Enabled && tr.HalfLifeSeconds != 0 {
+		if tr.HalfLifeSeconds < MinThroughputHalfLifeSeconds || tr.HalfLifeSeconds > MaxThroughputHalfLifeSeconds {
+			return fmt.Errorf("throughput_rates.half_life_seconds must be between %d and %d", MinThroughputHalfLifeSeconds, MaxThroughputHalfLifeSeconds)
+		}
+	}
+	if uc := g.UploadConcurrency; uc != nil && uc.AutoTune {
+		if uc.Min < 0 || uc.Max < 0 || uc.Max > MaxAutoTuneConcurrency {
+			return fmt.Errorf("upload_concurrency min and max must be between 0 and %d", MaxAutoTuneConcurrency)
+		}
+		if uc.Max > 0 && uc.Min > uc.Max {
+			return fmt.Errorf("upload_concurrency.min cannot exceed upload_concurrency.max")
+		}
+		if uc.TargetLatencySeconds < 0 {
+			return fmt.Errorf("upload_concurrency.target_latency_seconds cannot be negative")
+		}
+	}
+	return nil
+}
+
+// ValidateWebConsole validates web console settings
+func ValidateWebConsole(wc *WebConsole) error {
+	if wc == nil {
+		return nil
+	}
+	switch wc.DefaultPasswordPolicy {
+	case "", DefaultPasswordWarn, DefaultPasswordRequireChange, DefaultPasswordLocalhostOnly:
+	default:
+		return fmt.Errorf("web_console.default_password_policy must be warn, require_change or localhost_only")
+	}
+	// Unknown modes fall back to basic auth at runtime; refuse them so a typo does
+	// not quietly disable a configured metrics_token
+	for _, ep := range []struct{ field, mode string }{
+		{"health_auth", wc.HealthAuth},
+		{"metrics_auth", wc.MetricsAuth},
+	} {
+		switch ep.mode {
+		case "", EndpointAuthNone, EndpointAuthToken, EndpointAuthBasic:
+		default:
+			return fmt.Errorf("web_console.%s must be none, token or basic", ep.field)
+		}
+	}
+	if _, err := wc.TrustedProxyPrefixes(); err != nil {
+		return fmt.Errorf("web_console.trusted_proxies: %w", err)
+	}
+	if rs := wc.Restream; rs != nil && rs.Enabled {
+		if rs.MaxFPS < 0 || rs.MaxFPS > MaxRestreamFPS {
+			return fmt.Errorf("web_console.restream.max_fps must be between 0 and %d", MaxRestreamFPS)
+		}
+		if rs.MaxClients < 0 || rs.MaxClients > MaxRestreamClients {
+			return fmt.Errorf("web_console.restream.max_clients must be between 0 and %d", MaxRestreamClients)
+		}
+	}
+	return nil
+}
+
+// ValidateMQTT validates MQTT settings; disabled settings are not checked
+func ValidateMQTT(m *MQTT) error {
+	if m == nil || !m.Enabled {
+		return nil
+	}
+	if m.Broker == "" {
+		return fmt.Errorf("mqtt.broker is required")
+	}
+	if _, _, err := net.SplitHostPort(m.Broker); err != nil {
+		return fmt.Errorf("mqtt.broker must be host:port")
+	}
+	if strings.ContainsAny(m.TopicPrefix, "+#") {
+		return fmt.Errorf("mqtt.topic_prefix cannot contain wildcards")
+	}
+	if m.KeepAliveSeconds < 0 || m.KeepAliveSeconds > 65535 {
+		return fmt.Errorf("mqtt.keep_alive_seconds must be between 0 and 65535")
+	}
+	return nil
+}
+
+// MaxStatsDFlushIntervalSeconds caps statsd.flush_interval_seconds
+const MaxStatsDFlushIntervalSeconds = 3600
+
+// ValidateStatsD validates StatsD settings; disabled settings are not checked
+func ValidateStatsD(s *StatsD) error {
+	if s == nil || !s.Enabled {
+		return nil
+	}
+	if s.Address == "" {
+		return fmt.Errorf("statsd.address is required")
+	}
+	if _, _, err := net.SplitHostPort(s.Address); err != nil {
+		return fmt.Errorf("statsd.address must be host:port")
+	}
+	if strings.ContainsAny(s.Prefix, ":|@ \t\n") {
+		return fmt.Errorf("statsd.prefix cannot contain ':', '|', '@' or whitespace")
+	}
+	if s.FlushIntervalSeconds < 0 || s.FlushIntervalSeconds > MaxStatsDFlushIntervalSeconds {
+		return fmt.Errorf("statsd.flush_interval_seconds must be between 0 and %d", MaxStatsDFlushIntervalSeconds)
+	}
+	return nil
+}
+
+// validateCamera validates a single camera of the legacy single-file configuration
 func validateCamera(cam *Camera, index int) error {
-	if cam.ID == "" {
-		return fmt.Errorf("id is required")
+	if err := validateCameraSettings(cam); err != nil {
+		return err
+	}
+
+	// Validate interval
+	if cam.IntervalSeconds < 30 {
+		return fmt.Errorf("interval_seconds must be at least 30")
+	}
+
+	// Validate remote path
+	if cam.RemotePath == "" {
+		return fmt.Errorf("remote_path is required")
+	}
+	if strings.Contains(cam.RemotePath, "..") {
+		return fmt.Errorf("remote_path cannot contain '..'")
+	}
+	if strings.HasPrefix(cam.RemotePath, "/") {
+		return fmt.Errorf("remote_path cannot start with '/'")
+	}
+
+	return nil
+}
+
+// ValidateCamera validates a camera as stored by the config service, which carries
+// its own upload credentials
+func ValidateCamera(cam *Camera) error {
+	if err := validateCameraSettings(cam); err != nil {
+		return err
+	}
+	if cam.Upload == nil {
+		return fmt.Errorf("upload is required")
+	}
+	if cam.Upload.Host == "" {
+		return fmt.Errorf("upload.host is required")
+	}
+	if cam.Upload.Username == "" {
+		return fmt.Errorf("upload.username is required")
+	}
+	if cam.Upload.BatchSize < 0 || cam.Upload.BatchSize > MaxUploadBatchSize {
+		return fmt.Errorf("upload.batch_size must be between 0 and %d", MaxUploadBatchSize)
 	}
+	return nil
+}
+
+// MaxUploadBatchSize caps upload.batch_size so one camera cannot hold a shared
+// connection for too long
+const MaxUploadBatchSize = 50
 
-	// Validate ID format (alphanumeric + hyphens)
-	for _, r := range cam.ID {
+// MaxCameraIDLength caps camera IDs, which name queue directories, config files and
+// remote paths
+const MaxCameraIDLength = 64
+
+// NormalizeCameraID trims surrounding whitespace from a camera ID as entered
+func NormalizeCameraID(id string) string {
+	return strings.TrimSpace(id)
+}
+
+// ValidateCameraID checks that id is safe as a file and directory name: letters,
+// digits and hyphens only, so no path separators, dots or whitespace
+func ValidateCameraID(id string) error {
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+	if len(id) > MaxCameraIDLength {
+		return fmt.Errorf("id must be at most %d characters", MaxCameraIDLength)
+	}
+	for _, r := range id {
 		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-') {
 			return fmt.Errorf("id contains invalid characters (alphanumeric and hyphens only)")
 		}
 	}
+	return nil
+}
+
+// validateCameraSettings checks the camera settings shared by both config formats
+func validateCameraSettings(cam *Camera) error {
+	if err := ValidateCameraID(cam.ID); err != nil {
+		return err
+	}
 
 	if cam.Name == "" {
 		return fmt.Errorf("name is required")
 	}
 
 	// Validate type
-	validTypes := map[string]bool{"http": true, "onvif": true, "rtsp": true}
+	validTypes := map[string]bool{"http": true, "onvif": true, "rtsp": true, "folder": true, "agent": true}
 	if !validTypes[cam.Type] {
-		return fmt.Errorf("type must be 'http', 'onvif', or 'rtsp'")
+		return fmt.Errorf("type must be 'http', 'onvif', 'rtsp', 'folder', or 'agent'")
 	}
 
 	// Type-specific validation
@@ -92,23 +495,732 @@ func validateCamera(cam *Camera, index int) error {
 		if cam.RTSP.URL == "" {
 			return fmt.Errorf("rtsp.url is required")
 		}
+		if cam.RTSP.ReconnectInitialSeconds < 0 || cam.RTSP.ReconnectMaxSeconds < 0 {
+			return fmt.Errorf("rtsp reconnect backoff cannot be negative")
+		}
+		if cam.RTSP.ReconnectMaxSeconds > 0 && cam.RTSP.ReconnectInitialSeconds > cam.RTSP.ReconnectMaxSeconds {
+			return fmt.Errorf("rtsp.reconnect_initial_seconds cannot exceed rtsp.reconnect_max_seconds")
+		}
+		if cam.RTSP.SpoolThresholdKB != 0 {
+			if err := validateSpool(cam); err != nil {
+				return fmt.Errorf("rtsp.spool_threshold_kb: %w", err)
+			}
+		}
+	case "folder":
+		if cam.Folder == nil || cam.Folder.Path == "" {
+			return fmt.Errorf("folder.path is required for folder type")
+		}
+		info, err := os.Stat(cam.Folder.Path)
+		if err != nil {
+			return fmt.Errorf("folder.path: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("folder.path %q is not a directory", cam.Folder.Path)
+		}
+	case "agent":
+		if cam.Agent == nil || cam.Agent.URL == "" {
+			return fmt.Errorf("agent.url is required for agent type")
+		}
+		if u, err := url.Parse(cam.Agent.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("agent.url must be an https URL")
+		}
+		if cam.Agent.CertFile == "" || cam.Agent.KeyFile == "" {
+			return fmt.Errorf("agent.cert_file and agent.key_file are required for mutual TLS")
+		}
 	}
 
-	// Validate interval
-	if cam.IntervalSeconds < 30 {
-		return fmt.Errorf("interval_seconds must be at least 30")
+	if err := ValidateCaptureRate(cam); err != nil {
+		return err
 	}
 
-	// Validate remote path
-	if cam.RemotePath == "" {
-		return fmt.Errorf("remote_path is required")
+	if cam.Tunnel != nil {
+		if err := validateTunnel(cam); err != nil {
+			return fmt.Errorf("tunnel: %w", err)
+		}
 	}
-	if strings.Contains(cam.RemotePath, "..") {
-		return fmt.Errorf("remote_path cannot contain '..'")
+
+	if cam.Wakeup != nil {
+		if err := validateWakeup(cam); err != nil {
+			return fmt.Errorf("wakeup: %w", err)
+		}
 	}
-	if strings.HasPrefix(cam.RemotePath, "/") {
-		return fmt.Errorf("remote_path cannot start with '/'")
+
+	if cam.Raw != nil {
+		if err := validateRawInput(cam); err != nil {
+			return fmt.Errorf("raw: %w", err)
+		}
+	}
+
+	if cam.Diagnostics != nil {
+		if err := validateDiagnostics(cam); err != nil {
+			return fmt.Errorf("diagnostics: %w", err)
+		}
+	}
+
+	if cam.TLS != nil {
+		if err := validateCameraTLS(cam); err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+	}
+
+	if len(cam.FailOnHeaders) > 0 {
+		if err := validateHeaderRules(cam); err != nil {
+			return fmt.Errorf("fail_on_headers: %w", err)
+		}
+	}
+
+	if cam.ConditionalRequests && cam.Type != "http" {
+		return fmt.Errorf("conditional_requests is only supported for http cameras")
+	}
+	if cam.MaxSnapshotKB != 0 {
+		if cam.Type != "http" {
+			return fmt.Errorf("max_snapshot_kb is only supported for http cameras")
+		}
+		if cam.MaxSnapshotKB < 0 || cam.MaxSnapshotKB > MaxSnapshotSizeKB {
+			return fmt.Errorf("max_snapshot_kb must be between 0 and %d", MaxSnapshotSizeKB)
+		}
+	}
+
+	if cam.Rediscovery != nil && cam.Rediscovery.Enabled {
+		if err := validateRediscovery(cam); err != nil {
+			return fmt.Errorf("rediscovery: %w", err)
+		}
+	}
+
+	if cam.Image != nil {
+		switch cam.Image.Rotate {
+		case 0, 90, 180, 270:
+		default:
+			return fmt.Errorf("image.rotate must be 0, 90, 180 or 270")
+		}
+		if err := validatePrivacyZones(cam); err != nil {
+			return fmt.Errorf("image: %w", err)
+		}
+	}
+
+	if cam.Thumbnail != nil {
+		if err := validateThumbnail(cam); err != nil {
+			return fmt.Errorf("thumbnail: %w", err)
+		}
+	}
+
+	if cam.Spectrogram != nil && cam.Spectrogram.Enabled {
+		if err := validateSpectrogram(cam); err != nil {
+			return fmt.Errorf("spectrogram: %w", err)
+		}
 	}
 
+	if len(cam.Regions) > 0 {
+		if err := validateRegions(cam); err != nil {
+			return fmt.Errorf("regions: %w", err)
+		}
+	}
+
+	if cam.QualitySampleRate < 0 || cam.QualitySampleRate > 1 {
+		return fmt.Errorf("quality_sample_rate must be between 0 and 1")
+	}
+
+	if cam.DedupWindow < 0 {
+		return fmt.Errorf("dedup_window cannot be negative")
+	}
+
+	if cam.ONVIF != nil && cam.ONVIF.Events != nil && cam.ONVIF.Events.CooldownSeconds < 0 {
+		return fmt.Errorf("onvif.events.cooldown_seconds cannot be negative")
+	}
+
+	if cam.LatestName != "" {
+		if err := validateLatestName(cam.LatestName); err != nil {
+			return fmt.Errorf("latest_name: %w", err)
+		}
+	}
+
+	if cam.History != nil {
+		if cam.History.MaxFrames < 0 || cam.History.MaxFrames > MaxHistoryFrames {
+			return fmt.Errorf("history.max_frames must be between 0 and %d", MaxHistoryFrames)
+		}
+		if cam.History.MaxSizeMB < 0 || cam.History.MaxSizeMB > MaxHistorySizeMB {
+			return fmt.Errorf("history.max_size_mb must be between 0 and %d", MaxHistorySizeMB)
+		}
+	}
+
+	if cam.Queue != nil {
+		if cam.Queue.SurvivalMinutes < 0 || cam.Queue.SurvivalMinutes > MaxQueueSurvivalMinutes {
+			return fmt.Errorf("queue.survival_minutes must be between 0 and %d", MaxQueueSurvivalMinutes)
+		}
+		if cam.Queue.FrameSizeKB < 0 || cam.Queue.FrameSizeKB > MaxQueueFrameSizeKB {
+			return fmt.Errorf("queue.frame_size_kb must be between 0 and %d", MaxQueueFrameSizeKB)
+		}
+		if err := validateQueuePause(cam.Queue); err != nil {
+			return fmt.Errorf("queue: %w", err)
+		}
+	}
+
+	if cam.Timelapse != nil && cam.Timelapse.Enabled {
+		if err := validateTimelapse(cam); err != nil {
+			return fmt.Errorf("timelapse: %w", err)
+		}
+	}
+
+	if cam.FreshnessSLASeconds < 0 {
+		return fmt.Errorf("freshness_sla_seconds cannot be negative")
+	}
+
+	if cam.LogLevel != "" {
+		if _, ok := logger.ParseLevel(cam.LogLevel); !ok {
+			return fmt.Errorf("log_level must be debug, info, warn or error")
+		}
+	}
+
+	if cam.OfflineAfterSeconds < 0 {
+		return fmt.Errorf("offline_after_seconds cannot be negative")
+	}
+	if cam.OfflineImagePath != "" {
+		info, err := os.Stat(cam.OfflineImagePath)
+		if err != nil {
+			return fmt.Errorf("offline_image_path: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("offline_image_path %q is not a file", cam.OfflineImagePath)
+		}
+	}
+
+	if cam.ExifStampRetries < 0 {
+		return fmt.Errorf("exif_stamp_retries cannot be negative")
+	}
+
+	switch cam.TimeSource {
+	case "", "bridge", "camera", "camera_if_within_tolerance":
+	default:
+		return fmt.Errorf("time_source must be 'bridge', 'camera', or 'camera_if_within_tolerance'")
+	}
+
+	if cam.MinIntervalSeconds < 0 || cam.MinIntervalSeconds > MaxMinIntervalSeconds {
+		return fmt.Errorf("min_interval_seconds must be between 0 and %d", MaxMinIntervalSeconds)
+	}
+
+	if cam.SettleDelaySeconds < 0 || cam.SettleDelaySeconds > MaxSettleDelaySeconds {
+		return fmt.Errorf("settle_delay_seconds must be between 0 and %d", MaxSettleDelaySeconds)
+	}
+
+	if cam.MaxUploadAttempts < 0 {
+		return fmt.Errorf("max_upload_attempts cannot be negative")
+	}
+
+	if cam.RemoteDedupMinutes < 0 || cam.RemoteDedupMinutes > MaxRemoteDedupMinutes {
+		return fmt.Errorf("remote_dedup_minutes must be between 0 and %d", MaxRemoteDedupMinutes)
+	}
+
+	if b := cam.UploadSizeBand; b != nil {
+		if err := validateUploadSizeBand(b, cam.MaxUploadAttempts); err != nil {
+			return fmt.Errorf("upload_size_band: %w", err)
+		}
+	}
+
+	if s := cam.UploadOutageSlowdown; s != nil {
+		if s.AfterMinutes < 0 || s.AfterMinutes > MaxOutageSlowdownAfterMinutes {
+			return fmt.Errorf("upload_outage_slowdown.after_minutes must be between 0 and %d", MaxOutageSlowdownAfterMinutes)
+		}
+		if s.Multiplier != 0 && (s.Multiplier <= 1 || s.Multiplier > MaxOutageSlowdownMultiplier) {
+			return fmt.Errorf("upload_outage_slowdown.multiplier must be greater than 1 and at most %d", MaxOutageSlowdownMultiplier)
+		}
+	}
+
+	if q := cam.UploadQuietHours; q != nil {
+		if err := validateQuietHours(q, true); err != nil {
+			return fmt.Errorf("upload_quiet_hours: %w", err)
+		}
+	}
+
+	if h := cam.PostCaptureHook; h != nil {
+		if err := validatePostCaptureHook(cam); err != nil {
+			return fmt.Errorf("post_capture_hook: %w", err)
+		}
+	}
+
+	validTokens := map[string]bool{"source": true, "confidence": true, "warn": true}
+	seenTokens := make(map[string]bool, len(cam.FilenameTimeTokens))
+	for _, token := range cam.FilenameTimeTokens {
+		if !validTokens[token] {
+			return fmt.Errorf("filename_time_tokens: unknown token %q (valid: source, confidence, warn)", token)
+		}
+		if seenTokens[token] {
+			return fmt.Errorf("filename_time_tokens: duplicate token %q", token)
+		}
+		seenTokens[token] = true
+	}
+
+	if cam.CatchupMinutes < 0 {
+		return fmt.Errorf("catchup_minutes cannot be negative")
+	}
+
+	return nil
+}
+
+// validateTunnel validates a camera's SSH tunnel settings
+func validateTunnel(cam *Camera) error {
+	t := cam.Tunnel
+	if cam.Type != "http" && cam.Type != "rtsp" {
+		return fmt.Errorf("only supported for http and rtsp cameras")
+	}
+	if t.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if t.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if t.Password == "" && t.PrivateKeyPath == "" {
+		return fmt.Errorf("password or private_key_path is required")
+	}
+	if t.HostKeyFingerprint == "" && !t.InsecureIgnoreHostKey {
+		return fmt.Errorf("host_key_fingerprint is required (or set insecure_ignore_host_key)")
+	}
+	if t.Port < 0 || t.Port > 65535 || t.LocalPort < 0 || t.LocalPort > 65535 {
+		return fmt.Errorf("port and local_port must be between 0 and 65535")
+	}
+	return nil
+}
+
+// validateWakeup validates a camera's pre-capture wakeup request. A tunnel only
+// forwards the camera URL, so the wakeup URL would bypass it.
+func validateWakeup(cam *Camera) error {
+	wk := cam.Wakeup
+	if cam.Type == "folder" || cam.Type == "agent" {
+		return fmt.Errorf("not supported for %s cameras", cam.Type)
+	}
+	if cam.Tunnel != nil {
+		return fmt.Errorf("cannot be combined with tunnel")
+	}
+	if u, err := url.Parse(wk.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	switch wk.Method {
+	case "", "GET", "POST", "PUT":
+	default:
+		return fmt.Errorf("method must be GET, POST or PUT")
+	}
+	if wk.Auth != nil {
+		switch wk.Auth.Type {
+		case "basic", "digest", "bearer":
+		default:
+			return fmt.Errorf("auth.type must be 'basic', 'digest', or 'bearer'")
+		}
+	}
+	if wk.DelayMs < 0 || wk.DelayMs > MaxWakeupDelayMs {
+		return fmt.Errorf("delay_ms must be between 0 and %d", MaxWakeupDelayMs)
+	}
+	return nil
+}
+
+// validateUploadSizeBand checks the band is a real range. Kept frames stay at the
+// head of the queue, so they must eventually be dropped by max_upload_attempts.
+func validateUploadSizeBand(b *UploadSizeBand, maxUploadAttempts int) error {
+	if b.MinKB < 0 || b.MaxKB < 0 {
+		return fmt.Errorf("min_kb and max_kb cannot be negative")
+	}
+	if b.MinKB == 0 && b.MaxKB == 0 {
+		return fmt.Errorf("min_kb or max_kb is required")
+	}
+	if b.MaxKB > 0 && b.MaxKB <= b.MinKB {
+		return fmt.Errorf("max_kb must be greater than min_kb")
+	}
+	switch b.OnReject {
+	case "", "drop":
+	case "keep":
+		if maxUploadAttempts <= 0 {
+			return fmt.Errorf("on_reject 'keep' requires max_upload_attempts")
+		}
+	default:
+		return fmt.Errorf("on_reject must be 'drop' or 'keep'")
+	}
+	return nil
+}
+
+// MaxPostCaptureHookTimeoutSeconds caps post_capture_hook.timeout_seconds; the hook
+// runs inside the capture job
+const MaxPostCaptureHookTimeoutSeconds = 120
+
+// validatePostCaptureHook validates a camera's post-capture hook. The program must be
+// an absolute path: it runs without a shell or PATH lookup.
+func validatePostCaptureHook(cam *Camera) error {
+	h := cam.PostCaptureHook
+	if len(h.Command) == 0 || h.Command[0] == "" {
+		return fmt.Errorf("command is required")
+	}
+	if !path.IsAbs(h.Command[0]) {
+		return fmt.Errorf("command must start with an absolute program path")
+	}
+	if h.TimeoutSeconds < 0 || h.TimeoutSeconds > MaxPostCaptureHookTimeoutSeconds {
+		return fmt.Errorf("timeout_seconds must be between 0 and %d", MaxPostCaptureHookTimeoutSeconds)
+	}
+	switch h.OnFailure {
+	case "", "fail", "passthrough":
+	default:
+		return fmt.Errorf("on_failure must be 'fail' or 'passthrough'")
+	}
+	// Region crops are cut from the unhooked frame, so a privacy hook would leak
+	// through them (spooled frames are refused by validateSpool)
+	if len(cam.Regions) > 0 {
+		return fmt.Errorf("cannot be used with regions, which are cropped from the unhooked frame")
+	}
+	return nil
+}
+
+// validateRawInput validates a camera's raw frame conversion. Only http and folder
+// cameras can deliver raw frames.
+func validateRawInput(cam *Camera) error {
+	r := cam.Raw
+	if cam.Type != "http" && cam.Type != "folder" {
+		return fmt.Errorf("only supported for http and folder cameras")
+	}
+	switch r.Curve {
+	case "", "linear", "gamma", "log", "asinh":
+	default:
+		return fmt.Errorf("curve must be linear, gamma, log or asinh")
+	}
+	if r.Gamma < 0 || r.Gamma > 10 {
+		return fmt.Errorf("gamma must be between 0 and 10")
+	}
+	if r.Stretch < 0 || r.Stretch > 10000 {
+		return fmt.Errorf("stretch must be between 0 and 10000")
+	}
+	white := r.WhitePercentile
+	if white == 0 {
+		white = 100
+	}
+	if r.BlackPercentile < 0 || white > 100 || r.BlackPercentile >= white {
+		return fmt.Errorf("black_percentile and white_percentile must satisfy 0 <= black < white <= 100")
+	}
+	return nil
+}
+
+// validateDiagnostics validates a camera's diagnostics endpoint. Like a wakeup URL,
+// it would bypass a tunnel.
+func validateDiagnostics(cam *Camera) error {
+	d := cam.Diagnostics
+	if cam.Tunnel != nil {
+		return fmt.Errorf("cannot be combined with tunnel")
+	}
+	if u, err := url.Parse(d.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http or https URL")
+	}
+	if d.Auth != nil {
+		switch d.Auth.Type {
+		case "basic", "digest", "bearer":
+		default:
+			return fmt.Errorf("auth.type must be 'basic', 'digest', or 'bearer'")
+		}
+	}
+	if d.IntervalSeconds != 0 && (d.IntervalSeconds < MinDiagnosticsIntervalSeconds || d.IntervalSeconds > MaxDiagnosticsIntervalSeconds) {
+		return fmt.Errorf("interval_seconds must be between %d and %d", MinDiagnosticsIntervalSeconds, MaxDiagnosticsIntervalSeconds)
+	}
+	if len(d.Fields) == 0 || len(d.Fields) > MaxDiagnosticsFields {
+		return fmt.Errorf("fields must have between 1 and %d entries", MaxDiagnosticsFields)
+	}
+	for name, path := range d.Fields {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("field names must not be empty")
+		}
+		if path == "" || strings.HasPrefix(path, ".") || strings.HasSuffix(path, ".") || strings.Contains(path, "..") {
+			return fmt.Errorf("fields.%s: path %q must be a dotted JSON path", name, path)
+		}
+	}
+	return nil
+}
+
+// validateCameraTLS checks HTTPS verification settings. Skipping verification cannot be
+// combined with a CA bundle or pin, which would silently not apply.
+func validateCameraTLS(cam *Camera) error {
+	t := cam.TLS
+	if cam.Type != "http" && cam.Type != "onvif" {
+		return fmt.Errorf("only supported for http and onvif cameras")
+	}
+	if t.InsecureSkipVerify && (t.CAFile != "" || t.PinnedSHA256 != "") {
+		return fmt.Errorf("insecure_skip_verify cannot be combined with ca_file or pinned_sha256")
+	}
+	if t.PinnedSHA256 != "" {
+		fp := strings.ReplaceAll(strings.TrimSpace(t.PinnedSHA256), ":", "")
+		if _, err := hex.DecodeString(fp); err != nil || len(fp) != 64 {
+			return fmt.Errorf("pinned_sha256 must be a hex SHA-256 fingerprint")
+		}
+	}
+	return nil
+}
+
+// validateHeaderRules checks header names are plain HTTP field names
+func validateHeaderRules(cam *Camera) error {
+	if cam.Type != "http" && cam.Type != "onvif" {
+		return fmt.Errorf("only supported for http and onvif cameras")
+	}
+	for i, rule := range cam.FailOnHeaders {
+		if rule.Header == "" || strings.ContainsAny(rule.Header, " \t:\r\n") {
+			return fmt.Errorf("rule %d: header must be an HTTP header name", i+1)
+		}
+	}
+	return nil
+}
+
+// validateRediscovery checks rediscovery settings; a tunneled camera's address is the
+// SSH server's view, which local discovery cannot see
+func validateRediscovery(cam *Camera) error {
+	r := cam.Rediscovery
+	if cam.Type == "folder" || cam.Type == "agent" {
+		return fmt.Errorf("not supported for %s cameras", cam.Type)
+	}
+	if cam.Tunnel != nil {
+		return fmt.Errorf("cannot be combined with tunnel")
+	}
+	if strings.TrimSpace(r.DeviceID) == "" {
+		return fmt.Errorf("device_id is required")
+	}
+	if r.FailureThreshold < 0 {
+		return fmt.Errorf("failure_threshold cannot be negative")
+	}
+	if r.MinIntervalMinutes != 0 && r.MinIntervalMinutes < MinRediscoveryIntervalMinutes {
+		return fmt.Errorf("min_interval_minutes must be at least %d", MinRediscoveryIntervalMinutes)
+	}
+	return nil
+}
+
+// validateSpool checks a camera's RTSP spooling. Spooled frames never enter memory,
+// so they cannot be processed, trimmed, repaired, compared, sampled, cropped or hooked;
+// features that need that are refused rather than silently skipped.
+func validateSpool(cam *Camera) error {
+	if cam.RTSP.SpoolThresholdKB < 0 {
+		return fmt.Errorf("cannot be negative")
+	}
+	var conflicts []string
+	if cam.Image.NeedsProcessing() {
+		conflicts = append(conflicts, "image")
+	}
+	if cam.TrimJPEG {
+		conflicts = append(conflicts, "trim_jpeg")
+	}
+	if cam.RepairJPEG {
+		conflicts = append(conflicts, "repair_jpeg")
+	}
+	if cam.DedupWindow > 0 {
+		conflicts = append(conflicts, "dedup_window")
+	}
+	if cam.QualitySampleRate > 0 {
+		conflicts = append(conflicts, "quality_sample_rate")
+	}
+	if cam.Thumbnail != nil {
+		conflicts = append(conflicts, "thumbnail")
+	}
+	if len(cam.Regions) > 0 {
+		conflicts = append(conflicts, "regions")
+	}
+	if cam.PostCaptureHook != nil {
+		conflicts = append(conflicts, "post_capture_hook")
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("cannot be used with %s, which spooled frames skip", strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+// validateQuietHours parses a quiet window's times as the scheduler does (HH:MM).
+// An empty window is refused unless allowEmpty: equal start and end are how a
+// camera opts out of the global window.
+func validateQuietHours(q *QuietHours, allowEmpty bool) error {
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return fmt.Errorf("start must be a time as HH:MM, got %q", q.Start)
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return fmt.Errorf("end must be a time as HH:MM, got %q", q.End)
+	}
+	if start.Equal(end) && !allowEmpty {
+		return fmt.Errorf("start and end cannot be equal")
+	}
+	return nil
+}
+
+// validateLatestName checks the stable file name is a plain name that cannot clash
+// with the millisecond-timestamp names of uploaded frames
+func validateLatestName(name string) error {
+	if len(name) > 100 || strings.ContainsAny(name, "/\\") || name == "." || name == ".." {
+		return fmt.Errorf("must be a file name of at most 100 characters, without a directory")
+	}
+	if strings.Trim(strings.TrimSuffix(name, ".jpg"), "0123456789") == "" {
+		return fmt.Errorf("cannot be a timestamp file name")
+	}
+	return nil
+}
+
+// validatePrivacyZones checks a camera's privacy zones and their fill color
+func validatePrivacyZones(cam *Camera) error {
+	img := cam.Image
+	if len(img.PrivacyZones) == 0 {
+		return nil
+	}
+	if len(img.PrivacyZones) > MaxPrivacyZones {
+		return fmt.Errorf("at most %d privacy_zones are allowed", MaxPrivacyZones)
+	}
+	if _, err := img.PrivacyFillColor(); err != nil {
+		return err
+	}
+	for i, z := range img.PrivacyZones {
+		if z.X < 0 || z.Y < 0 || z.Width <= 0 || z.Height <= 0 {
+			return fmt.Errorf("privacy_zones[%d]: x and y must not be negative, width and height must be positive", i)
+		}
+		if z.Normalized && (z.X+z.Width > 1 || z.Y+z.Height > 1) {
+			return fmt.Errorf("privacy_zones[%d]: normalized zone must lie within 0-1", i)
+		}
+	}
+	return nil
+}
+
+// validateTimelapse checks the daily timelapse, which is built from history frames
+func validateTimelapse(cam *Camera) error {
+	t := cam.Timelapse
+	if cam.History == nil || !cam.History.Enabled {
+		return fmt.Errorf("requires history to be enabled")
+	}
+	if t.Format != "" && t.Format != "mp4" && t.Format != "gif" {
+		return fmt.Errorf("format must be mp4 or gif")
+	}
+	if t.FPS < 0 || t.FPS > MaxTimelapseFPS {
+		return fmt.Errorf("fps must be between 0 and %d", MaxTimelapseFPS)
+	}
+	if t.Hour != nil && (*t.Hour < 0 || *t.Hour > 23) {
+		return fmt.Errorf("hour must be between 0 and 23")
+	}
+	if t.MaxWidth < 0 {
+		return fmt.Errorf("max_width cannot be negative")
+	}
+	if t.MaxFrames < 0 || t.MaxFrames > MaxTimelapseFrames {
+		return fmt.Errorf("max_frames must be between 0 and %d", MaxTimelapseFrames)
+	}
+	return nil
+}
+
+// validateThumbnail checks the thumbnail rendition; its files would overwrite the full
+// images if both shared a remote directory, since filenames are the capture timestamp
+func validateThumbnail(cam *Camera) error {
+	t := cam.Thumbnail
+	if t.MaxWidth < 0 || t.MaxHeight < 0 {
+		return fmt.Errorf("max_width and max_height cannot be negative")
+	}
+	if t.Quality < 0 || t.Quality > 100 {
+		return fmt.Errorf("quality must be between 0 and 100")
+	}
+	fullPath := cam.RemotePath
+	if fullPath == "" {
+		fullPath = "."
+	}
+	if cleanRemotePath(t.EffectiveRemotePath()) == cleanRemotePath(fullPath) {
+		return fmt.Errorf("remote_path must differ from the camera's remote_path")
+	}
+	return nil
+}
+
+func validateSpectrogram(cam *Camera) error {
+	s := cam.Spectrogram
+	if cam.Type != "rtsp" {
+		return fmt.Errorf("only rtsp cameras have audio")
+	}
+	if s.DurationSeconds < 0 || s.DurationSeconds > MaxSpectrogramSeconds {
+		return fmt.Errorf("duration_seconds must be between 0 and %d", MaxSpectrogramSeconds)
+	}
+	if s.IntervalSeconds < 0 {
+		return fmt.Errorf("interval_seconds cannot be negative")
+	}
+	if s.Width < 0 || s.Width > MaxSpectrogramSize || s.Height < 0 || s.Height > MaxSpectrogramSize {
+		return fmt.Errorf("width and height must be between 0 and %d", MaxSpectrogramSize)
+	}
+	remote := cleanRemotePath(s.EffectiveRemotePath())
+	fullPath := cam.RemotePath
+	if fullPath == "" {
+		fullPath = "."
+	}
+	if remote == cleanRemotePath(fullPath) {
+		return fmt.Errorf("remote_path must differ from the camera's remote_path")
+	}
+	if cam.Thumbnail != nil && remote == cleanRemotePath(cam.Thumbnail.EffectiveRemotePath()) {
+		return fmt.Errorf("remote_path must differ from the thumbnail's remote_path")
+	}
 	return nil
 }
+
+// validateQueuePause checks the pause and resume thresholds. A resume threshold at or
+// above the pause threshold would resume capture straight into another pause.
+func validateQueuePause(q *QueueCamera) error {
+	if q.PauseThreshold < 0 || q.PauseThreshold > 1 {
+		return fmt.Errorf("pause_threshold must be between 0 and 1")
+	}
+	if q.ResumeThreshold < 0 || q.ResumeThreshold >= 1 {
+		return fmt.Errorf("resume_threshold must be between 0 and 1")
+	}
+	if !q.PreferPauseOverThin {
+		return nil
+	}
+	pause, resume := q.PauseThreshold, q.ResumeThreshold
+	if pause == 0 {
+		pause = 0.80
+	}
+	if resume == 0 {
+		resume = 0.70
+	}
+	if resume >= pause {
+		return fmt.Errorf("resume_threshold must be below pause_threshold")
+	}
+	return nil
+}
+
+func validateRegions(cam *Camera) error {
+	if len(cam.Regions) > MaxRegions {
+		return fmt.Errorf("at most %d regions are allowed", MaxRegions)
+	}
+	fullPath := cam.RemotePath
+	if fullPath == "" {
+		fullPath = "."
+	}
+	// Every upload of the camera names files by capture timestamp, so each needs its own directory
+	used := map[string]string{cleanRemotePath(fullPath): "the camera's remote_path"}
+	if cam.Thumbnail != nil {
+		used[cleanRemotePath(cam.Thumbnail.EffectiveRemotePath())] = "the thumbnail's remote_path"
+	}
+	if cam.Spectrogram != nil && cam.Spectrogram.Enabled {
+		used[cleanRemotePath(cam.Spectrogram.EffectiveRemotePath())] = "the spectrogram's remote_path"
+	}
+	names := make(map[string]bool, len(cam.Regions))
+	for i, r := range cam.Regions {
+		if r.Name == "" {
+			return fmt.Errorf("regions[%d]: name is required", i)
+		}
+		for _, c := range r.Name {
+			if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '_') {
+				return fmt.Errorf("regions[%d]: name may only contain lowercase letters, digits, - and _", i)
+			}
+		}
+		if names[r.Name] {
+			return fmt.Errorf("regions[%d]: duplicate name %q", i, r.Name)
+		}
+		names[r.Name] = true
+		if r.X < 0 || r.Y < 0 || r.Width <= 0 || r.Height <= 0 {
+			return fmt.Errorf("regions[%d]: x and y must not be negative, width and height must be positive", i)
+		}
+		if r.Normalized && (r.X+r.Width > 1 || r.Y+r.Height > 1) {
+			return fmt.Errorf("regions[%d]: normalized region must lie within 0-1", i)
+		}
+		if r.MaxWidth < 0 || r.MaxHeight < 0 {
+			return fmt.Errorf("regions[%d]: max_width and max_height cannot be negative", i)
+		}
+		if r.Quality < 0 || r.Quality > 100 {
+			return fmt.Errorf("regions[%d]: quality must be between 0 and 100", i)
+		}
+		remote := cleanRemotePath(r.EffectiveRemotePath())
+		if other, ok := used[remote]; ok {
+			return fmt.Errorf("regions[%d]: remote_path must differ from %s", i, other)
+		}
+		used[remote] = fmt.Sprintf("region %s's remote_path", r.Name)
+	}
+	return nil
+}
+
+// cleanRemotePath normalizes a remote path for comparison
+func cleanRemotePath(p string) string {
+	return path.Clean(strings.TrimPrefix(p, "/"))
+}
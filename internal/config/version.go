@@ -0,0 +1,100 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// CurrentVersion is the newest config schema version this bridge understands
+const CurrentVersion = 2
+
+// ErrReadOnly is returned by writes when config was loaded from a newer schema version,
+// or from one that failed to migrate
+var ErrReadOnly = errors.New("config is read-only: written by a newer bridge version or not migratable")
+
+// UnsupportedVersionError reports a config written by a newer bridge
+type UnsupportedVersionError struct {
+	Version int
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	return fmt.Sprintf("config version %d is newer than supported version %d", e.Version, CurrentVersion)
+}
+
+// MigrationError reports a config that could not be migrated to CurrentVersion
+type MigrationError struct {
+	Err error
+}
+
+func (e *MigrationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// FutureVersionPolicy controls how a config from a newer bridge version is handled
+type FutureVersionPolicy string
+
+// Valid reports whether p is a known policy ("" is the default, read_only)
+func (p FutureVersionPolicy) Valid() bool {
+	return p == "" || p == FutureVersionReadOnly || p == FutureVersionRefuse
+}
+
+const (
+	// FutureVersionReadOnly loads the config but refuses all writes, so fields this
+	// version does not understand are never overwritten
+	FutureVersionReadOnly FutureVersionPolicy = "read_only"
+	// FutureVersionRefuse fails startup. It also applies to a config that fails to
+	// migrate, which is otherwise loaded read-only the same way.
+	FutureVersionRefuse FutureVersionPolicy = "refuse"
+)
+
+// migration upgrades global settings from version From to From+1.
+// Add an entry here whenever the schema changes; keep entries in order.
+type migration struct {
+	From        int
+	Description string
+	Apply       func(g *GlobalSettings) error
+}
+
+var migrations = []migration{
+	{
+		From:        1,
+		Description: "per-camera upload credentials (cameras are migrated by MigrateFromLegacy)",
+		Apply:       func(g *GlobalSettings) error { return nil },
+	},
+}
+
+// migrateGlobal brings g up to CurrentVersion, logging each step.
+// Returns true if g was changed and should be saved.
+func migrateGlobal(g *GlobalSettings) (bool, error) {
+	if g.Version == 0 {
+		fmt.Fprintf(os.Stderr, "Config migration: version missing, assuming %d\n", CurrentVersion)
+		g.Version = CurrentVersion
+		return true, nil
+	}
+	if g.Version > CurrentVersion {
+		return false, &UnsupportedVersionError{Version: g.Version}
+	}
+
+	changed := false
+	for _, m := range migrations {
+		if m.From != g.Version {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "Config migration: v%d -> v%d: %s\n", m.From, m.From+1, m.Description)
+		if err := m.Apply(g); err != nil {
+			return changed, &MigrationError{Err: fmt.Errorf("migrate config v%d -> v%d: %w", m.From, m.From+1, err)}
+		}
+		g.Version = m.From + 1
+		changed = true
+	}
+
+	if g.Version != CurrentVersion {
+		return changed, &MigrationError{Err: fmt.Errorf("no migration path from config version %d", g.Version)}
+	}
+	return changed, nil
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGlobalVersion(t *testing.T, dir string, version int) {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{"version": version, "timezone": "UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "global.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readGlobalVersion(t *testing.T, dir string) int {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "global.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g GlobalSettings
+	if err := json.Unmarshal(data, &g); err != nil {
+		t.Fatal(err)
+	}
+	return g.Version
+}
+
+func TestNewService_MissingVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeGlobalVersion(t, dir, 0)
+
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if svc.IsReadOnly() {
+		t.Error("service should not be read-only")
+	}
+	if got := readGlobalVersion(t, dir); got != CurrentVersion {
+		t.Errorf("saved version = %d, want %d", got, CurrentVersion)
+	}
+}
+
+func TestNewService_MigratesOlderVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeGlobalVersion(t, dir, 1)
+
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if got := svc.GetGlobal().Version; got != CurrentVersion {
+		t.Errorf("version = %d, want %d", got, CurrentVersion)
+	}
+	if got := readGlobalVersion(t, dir); got != CurrentVersion {
+		t.Errorf("saved version = %d, want %d", got, CurrentVersion)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "global.json.bak")); err != nil {
+		t.Errorf("expected backup of pre-migration config: %v", err)
+	}
+}
+
+func TestNewService_FutureVersionReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeGlobalVersion(t, dir, CurrentVersion+1)
+
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if !svc.IsReadOnly() {
+		t.Fatal("service should be read-only for a newer config version")
+	}
+	if got := svc.GetGlobal().Version; got != CurrentVersion+1 {
+		t.Errorf("version = %d, want %d", got, CurrentVersion+1)
+	}
+
+	err = svc.UpdateGlobal(func(g *GlobalSettings) error {
+		g.Timezone = "America/Denver"
+		return nil
+	})
+	if !errors.Is(err, ErrReadOnly) {
+		t.Errorf("UpdateGlobal() error = %v, want ErrReadOnly", err)
+	}
+	if err := svc.AddCamera(Camera{ID: "cam1", Name: "Cam", Type: "http"}); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddCamera() error = %v, want ErrReadOnly", err)
+	}
+	if got := readGlobalVersion(t, dir); got != CurrentVersion+1 {
+		t.Errorf("file was rewritten: version = %d, want %d", got, CurrentVersion+1)
+	}
+}
+
+func TestNewService_FutureVersionRefuse(t *testing.T) {
+	dir := t.TempDir()
+	writeGlobalVersion(t, dir, CurrentVersion+1)
+
+	_, err := NewServiceWithOptions(dir, ServiceOptions{FutureVersion: FutureVersionRefuse})
+	var versionErr *UnsupportedVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("NewServiceWithOptions() error = %v, want UnsupportedVersionError", err)
+	}
+	if versionErr.Version != CurrentVersion+1 {
+		t.Errorf("error version = %d, want %d", versionErr.Version, CurrentVersion+1)
+	}
+	if got := readGlobalVersion(t, dir); got != CurrentVersion+1 {
+		t.Errorf("file was rewritten: version = %d, want %d", got, CurrentVersion+1)
+	}
+}
+
+func TestMigrations_Contiguous(t *testing.T) {
+	for i, m := range migrations {
+		if m.From+1 > CurrentVersion {
+			t.Errorf("migration %d targets v%d beyond CurrentVersion %d", i, m.From+1, CurrentVersion)
+		}
+		if i > 0 && m.From != migrations[i-1].From+1 {
+			t.Errorf("migration %d starts at v%d, want v%d", i, m.From, migrations[i-1].From+1)
+		}
+	}
+}
+
+func TestNewService_UnmigratableVersionReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeGlobalVersion(t, dir, -1) // No migration path
+
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	if !svc.IsReadOnly() {
+		t.Fatal("service should be read-only for a config that fails to migrate")
+	}
+	if got := readGlobalVersion(t, dir); got != -1 {
+		t.Errorf("file was rewritten: version = %d, want -1", got)
+	}
+
+	_, err = NewServiceWithOptions(dir, ServiceOptions{FutureVersion: FutureVersionRefuse})
+	var migrationErr *MigrationError
+	if !errors.As(err, &migrationErr) {
+		t.Errorf("NewServiceWithOptions() error = %v, want MigrationError", err)
+	}
+}
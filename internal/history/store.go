@@ -0,0 +1,183 @@
+// Package history keeps the last few processed frames of each camera on disk for
+// local review in the web console. Unlike the upload queue it never drains: frames
+// stay until newer ones push them out of the count and size limits.
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults applied when a limit is zero
+const (
+	DefaultMaxFrames = 50
+	DefaultMaxBytes  = 20 * 1024 * 1024
+)
+
+const frameExt = ".jpg"
+
+// ErrNotFound is returned for a frame that is not (or no longer) retained
+var ErrNotFound = errors.New("frame not found")
+
+// Limits bounds the frames retained for one camera; the oldest are evicted first
+type Limits struct {
+	MaxFrames int   // Default: 50
+	MaxBytes  int64 // Default: 20 MB
+}
+
+// Frame describes a retained frame. Timestamp (Unix milliseconds of the capture)
+// identifies it in Get.
+type Frame struct {
+	Timestamp int64     `json:"ts"`
+	Time      time.Time `json:"time"`
+	Size      int64     `json:"size"`
+}
+
+// Store writes frames under <dir>/<camera id>/<unix ms>.jpg
+type Store struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewStore creates a store rooted at dir; directories are created on first write
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Add retains a frame captured at t and evicts the oldest frames beyond limits. A
+// frame with the same millisecond timestamp replaces the earlier one.
+func (s *Store) Add(cameraID string, data []byte, t time.Time, limits Limits) error {
+	dir, err := s.cameraDir(cameraID)
+	if err != nil {
+		return err
+	}
+	if limits.MaxFrames <= 0 {
+		limits.MaxFrames = DefaultMaxFrames
+	}
+	if limits.MaxBytes <= 0 {
+		limits.MaxBytes = DefaultMaxBytes
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create history directory: %w", err)
+	}
+	path := filepath.Join(dir, strconv.FormatInt(t.UnixMilli(), 10)+frameExt)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write history frame: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("write history frame: %w", err)
+	}
+	return s.evict(dir, limits)
+}
+
+// evict removes the oldest frames until the rest fit within limits
+func (s *Store) evict(dir string, limits Limits) error {
+	frames, err := listFrames(dir)
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, f := range frames {
+		total += f.Size
+	}
+	// frames is newest first; drop from the end
+	for len(frames) > 0 && (len(frames) > limits.MaxFrames || total > limits.MaxBytes) {
+		oldest := frames[len(frames)-1]
+		if err := os.Remove(framePath(dir, oldest.Timestamp)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("evict history frame: %w", err)
+		}
+		total -= oldest.Size
+		frames = frames[:len(frames)-1]
+	}
+	return nil
+}
+
+// List returns the camera's retained frames, newest first
+func (s *Store) List(cameraID string) ([]Frame, error) {
+	dir, err := s.cameraDir(cameraID)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return listFrames(dir)
+}
+
+// Get returns the frame with the given Unix millisecond timestamp
+func (s *Store) Get(cameraID string, ts int64) ([]byte, error) {
+	dir, err := s.cameraDir(cameraID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(framePath(dir, ts))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Remove deletes all frames retained for a camera
+func (s *Store) Remove(cameraID string) error {
+	dir, err := s.cameraDir(cameraID)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return os.RemoveAll(dir)
+}
+
+// cameraDir returns the camera's directory, rejecting IDs that would escape the store
+func (s *Store) cameraDir(cameraID string) (string, error) {
+	if cameraID == "" || cameraID == "." || cameraID == ".." || strings.ContainsAny(cameraID, `/\`) {
+		return "", fmt.Errorf("invalid camera id %q", cameraID)
+	}
+	return filepath.Join(s.dir, cameraID), nil
+}
+
+func framePath(dir string, ts int64) string {
+	return filepath.Join(dir, strconv.FormatInt(ts, 10)+frameExt)
+}
+
+// listFrames reads the frames in dir, newest first. A missing directory has none.
+func listFrames(dir string) ([]Frame, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read history directory: %w", err)
+	}
+	frames := make([]Frame, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, frameExt) {
+			continue
+		}
+		ts, err := strconv.ParseInt(strings.TrimSuffix(name, frameExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		frames = append(frames, Frame{Timestamp: ts, Time: time.UnixMilli(ts).UTC(), Size: info.Size()})
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].Timestamp > frames[j].Timestamp })
+	return frames, nil
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_AddListGet(t *testing.T) {
+	s := NewStore(t.TempDir())
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		if err := s.Add("cam-1", []byte{byte(i)}, base.Add(time.Duration(i)*time.Minute), Limits{}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	frames, err := s.List("cam-1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("frames = %d, want 3", len(frames))
+	}
+	if !frames[0].Time.Equal(base.Add(2*time.Minute)) || frames[0].Size != 1 {
+		t.Errorf("newest frame = %+v", frames[0])
+	}
+
+	data, err := s.Get("cam-1", frames[2].Timestamp)
+	if err != nil || !bytes.Equal(data, []byte{0}) {
+		t.Errorf("Get oldest = %v, %v", data, err)
+	}
+	if _, err := s.Get("cam-1", 42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing = %v, want ErrNotFound", err)
+	}
+	if frames, _ := s.List("cam-2"); len(frames) != 0 {
+		t.Errorf("unknown camera has %d frames", len(frames))
+	}
+}
+
+func TestStore_EvictsOldestFirst(t *testing.T) {
+	s := NewStore(t.TempDir())
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	frame := make([]byte, 100)
+
+	for i := 0; i < 5; i++ {
+		if err := s.Add("cam-1", frame, base.Add(time.Duration(i)*time.Minute), Limits{MaxFrames: 3}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	frames, _ := s.List("cam-1")
+	if len(frames) != 3 || !frames[2].Time.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("count limit kept %+v", frames)
+	}
+
+	// A tighter size limit evicts down to what fits
+	if err := s.Add("cam-1", frame, base.Add(5*time.Minute), Limits{MaxFrames: 10, MaxBytes: 250}); err != nil {
+		t.Fatal(err)
+	}
+	frames, _ = s.List("cam-1")
+	if len(frames) != 2 || !frames[1].Time.Equal(base.Add(4*time.Minute)) {
+		t.Errorf("size limit kept %+v", frames)
+	}
+}
+
+func TestStore_RejectsPathCameraIDs(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(filepath.Join(dir, "history"))
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if err := s.Add(id, []byte("x"), time.Now(), Limits{}); err == nil {
+			t.Errorf("Add(%q) accepted", id)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); !os.IsNotExist(err) {
+		t.Error("frame written outside the store")
+	}
+}
+
+func TestStore_Remove(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if err := s.Add("cam-1", []byte("x"), time.Now(), Limits{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("cam-1"); err != nil {
+		t.Fatal(err)
+	}
+	if frames, _ := s.List("cam-1"); len(frames) != 0 {
+		t.Errorf("frames after Remove = %d", len(frames))
+	}
+}
@@ -0,0 +1,81 @@
+package image
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// Masks reports whether the processor fills privacy zones. Such frames must not be
+// uploaded when processing fails, or the zones would be exposed.
+func (p *Processor) Masks() bool {
+	return p.config != nil && len(p.config.PrivacyZones) > 0
+}
+
+// maskPrivacyZones returns img with every privacy zone filled. Zones are rounded
+// outward to whole pixels. A pixel zone outside the image is an error rather than
+// being clipped: the camera's resolution has likely changed, so the zone may no
+// longer cover what it was drawn for.
+func maskPrivacyZones(img image.Image, cfg *config.ImageProcessing) (image.Image, error) {
+	fill, err := cfg.PrivacyFillColor()
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	rects := make([]image.Rectangle, 0, len(cfg.PrivacyZones))
+	for i, z := range cfg.PrivacyZones {
+		r, err := zoneRect(z, bounds.Dx(), bounds.Dy())
+		if err != nil {
+			return nil, fmt.Errorf("privacy zone %d: %w", i, err)
+		}
+		rects = append(rects, r.Add(bounds.Min))
+	}
+
+	dst, ok := img.(draw.Image)
+	if !ok {
+		rgba := image.NewRGBA(bounds)
+		draw.Draw(rgba, bounds, img, bounds.Min, draw.Src)
+		dst = rgba
+	}
+	src := image.NewUniform(fill)
+	for _, r := range rects {
+		draw.Draw(dst, r, src, image.Point{}, draw.Src)
+	}
+	return dst, nil
+}
+
+// cropImage returns the part of img inside r. Like a privacy zone, r is rounded
+// outward and a pixel crop outside the image is an error.
+func cropImage(img image.Image, r config.Rect) (image.Image, error) {
+	bounds := img.Bounds()
+	rect, err := zoneRect(r, bounds.Dx(), bounds.Dy())
+	if err != nil {
+		return nil, fmt.Errorf("crop: %w", err)
+	}
+	rect = rect.Add(bounds.Min)
+	if sub, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect), nil
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst, nil
+}
+
+// zoneRect converts z to pixel coordinates in a width x height image
+func zoneRect(z config.Rect, width, height int) (image.Rectangle, error) {
+	x0, y0, x1, y1 := z.X, z.Y, z.X+z.Width, z.Y+z.Height
+	if z.Normalized {
+		x0, x1 = x0*float64(width), x1*float64(width)
+		y0, y1 = y0*float64(height), y1*float64(height)
+	}
+	r := image.Rect(int(math.Floor(x0)), int(math.Floor(y0)), int(math.Ceil(x1)), int(math.Ceil(y1)))
+	if r.Empty() || !r.In(image.Rect(0, 0, width, height)) {
+		return image.Rectangle{}, fmt.Errorf("%v outside the %dx%d image", r, width, height)
+	}
+	return r, nil
+}
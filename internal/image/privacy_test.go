@@ -0,0 +1,131 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// nearColor reports whether c is within a JPEG tolerance of want
+func nearColor(c color.Color, want color.RGBA) bool {
+	r, g, b, _ := c.RGBA()
+	near := func(got uint32, want uint8) bool {
+		d := int(got>>8) - int(want)
+		return d >= -12 && d <= 12
+	}
+	return near(r, want.R) && near(g, want.G) && near(b, want.B)
+}
+
+func decode(t *testing.T, data []byte) image.Image {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	return img
+}
+
+func TestProcess_PrivacyZones(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	tests := []struct {
+		name   string
+		cfg    config.ImageProcessing
+		masked []image.Point // Inside the zone in the output image
+		clear  image.Point   // Outside it
+	}{
+		{
+			name:   "pixel zone, default black",
+			cfg:    config.ImageProcessing{PrivacyZones: []config.PrivacyZone{{X: 0, Y: 0, Width: 160, Height: 120}}},
+			masked: []image.Point{{8, 8}, {150, 110}},
+			clear:  image.Pt(630, 470),
+		},
+		{
+			name: "normalized zone, resized",
+			cfg: config.ImageProcessing{MaxWidth: 320, PrivacyFill: "#ff0000",
+				PrivacyZones: []config.PrivacyZone{{X: 0.5, Y: 0.5, Width: 0.5, Height: 0.5, Normalized: true}}},
+			masked: []image.Point{{168, 128}, {312, 232}},
+			clear:  image.Pt(8, 8),
+		},
+		{
+			// Top-left source zone ends up top-right after rotating 90 degrees, and
+			// the 640x480 frame becomes 240x320
+			name: "pixel zone, rotated and resized",
+			cfg: config.ImageProcessing{Rotate: 90, MaxWidth: 240, PrivacyFill: "#FF0000",
+				PrivacyZones: []config.PrivacyZone{{X: 0, Y: 0, Width: 320, Height: 240}}},
+			masked: []image.Point{{128, 8}, {232, 152}},
+			clear:  image.Pt(8, 312),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NewProcessor(&tt.cfg).Process(createTestJPEG(640, 480))
+			if err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			img := decode(t, out)
+			want, _ := tt.cfg.PrivacyFillColor()
+			if tt.cfg.PrivacyFill != "" && want != red {
+				t.Fatalf("fill = %v, want red", want)
+			}
+			for _, p := range tt.masked {
+				if c := img.At(p.X, p.Y); !nearColor(c, want) {
+					t.Errorf("pixel %v = %v, want fill %v", p, c, want)
+				}
+			}
+			if nearColor(img.At(tt.clear.X, tt.clear.Y), want) {
+				t.Errorf("pixel %v outside the zone was masked", tt.clear)
+			}
+		})
+	}
+}
+
+func TestProcess_PrivacyZoneOutsideImage(t *testing.T) {
+	p := NewProcessor(&config.ImageProcessing{
+		PrivacyZones: []config.PrivacyZone{{X: 600, Y: 0, Width: 100, Height: 100}},
+	})
+	if !p.Masks() {
+		t.Fatal("Masks() = false with privacy zones")
+	}
+	if _, err := p.Process(createTestJPEG(640, 480)); err == nil {
+		t.Error("zone past the image edge should fail rather than be clipped")
+	}
+	if NewProcessor(&config.ImageProcessing{Quality: 80}).Masks() {
+		t.Error("Masks() = true without privacy zones")
+	}
+}
+
+func TestProcess_Crop(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+
+	// Zones are masked in the original image before cropping
+	masked := &config.ImageProcessing{PrivacyFill: "#ff0000",
+		PrivacyZones: []config.PrivacyZone{{X: 0, Y: 0, Width: 160, Height: 120}}}
+	out, err := NewProcessor(masked).WithCrop(config.Rect{X: 0, Y: 0, Width: 320, Height: 240}).Process(createTestJPEG(640, 480))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	img := decode(t, out)
+	if got := img.Bounds().Size(); got != image.Pt(320, 240) {
+		t.Errorf("cropped size = %v, want 320x240", got)
+	}
+	if !nearColor(img.At(8, 8), red) || nearColor(img.At(300, 220), red) {
+		t.Error("privacy zone not masked in the crop")
+	}
+
+	// A crop alone still processes; normalized crops are resized afterwards
+	right := config.Rect{X: 0.5, Y: 0, Width: 0.5, Height: 1, Normalized: true}
+	out, err = NewProcessor(&config.ImageProcessing{MaxWidth: 160}).WithCrop(right).Process(createTestJPEG(640, 480))
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if got := decode(t, out).Bounds().Size(); got != image.Pt(160, 240) {
+		t.Errorf("resized crop = %v, want 160x240", got)
+	}
+
+	if _, err := NewProcessor(nil).WithCrop(config.Rect{X: 600, Width: 100, Height: 100}).Process(createTestJPEG(640, 480)); err == nil {
+		t.Error("crop past the image edge should fail rather than be clipped")
+	}
+}
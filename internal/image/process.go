@@ -15,9 +15,15 @@ import (
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
 )
 
+// defaultEncodeQuality is used when the image must be re-encoded (resize or
+// rotation) but no quality is configured
+const defaultEncodeQuality = 90
+
 // Processor handles image resizing and quality adjustment
 type Processor struct {
-	config *config.ImageProcessing
+	config    *config.ImageProcessing
+	reduction *Reduction   // Optional shared caps, e.g. while disk space is low
+	crop      *config.Rect // Optional part of the source image to keep
 }
 
 // NewProcessor creates a new image processor with the given settings
@@ -25,17 +31,37 @@ func NewProcessor(cfg *config.ImageProcessing) *Processor {
 	return &Processor{config: cfg}
 }
 
+// WithReduction makes the processor honor r's caps while r is active
+func (p *Processor) WithReduction(r *Reduction) *Processor {
+	p.reduction = r
+	return p
+}
+
+// WithCrop makes the processor keep only r of each image. The crop follows privacy
+// masking and precedes rotation, so r is in the original image's coordinates.
+func (p *Processor) WithCrop(r config.Rect) *Processor {
+	p.crop = &r
+	return p
+}
+
 // Process applies configured transformations to image data
 // Returns the processed JPEG image data, or the original if no processing is needed
 func (p *Processor) Process(data []byte) ([]byte, error) {
+	cfg := p.config
+	if p.reduction != nil {
+		cfg = p.reduction.apply(cfg)
+	}
+
 	// Default: no processing, return original image as-is
-	if p.config == nil || !p.config.NeedsProcessing() {
+	if (cfg == nil || !cfg.NeedsProcessing()) && p.crop == nil {
 		return data, nil
 	}
+	if cfg == nil {
+		cfg = &config.ImageProcessing{}
+	}
 
 	// Check if any processing is needed
-	needsResize := p.config.MaxWidth > 0 || p.config.MaxHeight > 0
-	_ = p.config.Quality > 0 // Quality is applied during encoding
+	needsResize := cfg.MaxWidth > 0 || cfg.MaxHeight > 0
 
 	// Decode the image
 	img, format, err := image.Decode(bytes.NewReader(data))
@@ -43,14 +69,35 @@ func (p *Processor) Process(data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to decode image: %w", err)
 	}
 
+	// Mask in source coordinates, so zones cover the same area after any rotation or resize
+	if len(cfg.PrivacyZones) > 0 {
+		if img, err = maskPrivacyZones(img, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.crop != nil {
+		if img, err = cropImage(img, *p.crop); err != nil {
+			return nil, err
+		}
+	}
+
+	// Rotate before resizing so the size limits apply to the upright image
+	if cfg.Rotate != 0 {
+		img = rotateImage(img, cfg.Rotate)
+	}
+
 	// Resize if needed
 	if needsResize {
-		img = p.resize(img)
+		img = resize(img, cfg)
 	}
 
 	// Encode as JPEG with quality setting
 	var buf bytes.Buffer
-	quality := p.config.GetQuality()
+	quality := cfg.GetQuality()
+	if quality == 0 {
+		quality = defaultEncodeQuality // Geometry changed but no quality configured
+	}
 
 	opts := &jpeg.Options{Quality: quality}
 	if err := jpeg.Encode(&buf, img, opts); err != nil {
@@ -70,13 +117,13 @@ func (p *Processor) Process(data []byte) ([]byte, error) {
 
 // resize scales the image to fit within MaxWidth and MaxHeight
 // Maintains aspect ratio - image will fit within the bounds
-func (p *Processor) resize(img image.Image) image.Image {
+func resize(img image.Image, cfg *config.ImageProcessing) image.Image {
 	bounds := img.Bounds()
 	origWidth := bounds.Dx()
 	origHeight := bounds.Dy()
 
-	maxW := p.config.MaxWidth
-	maxH := p.config.MaxHeight
+	maxW := cfg.MaxWidth
+	maxH := cfg.MaxHeight
 
 	// If no limits, return original
 	if maxW <= 0 && maxH <= 0 {
@@ -156,6 +203,45 @@ func resizeImage(src image.Image, width, height int) image.Image {
 	return dst
 }
 
+// rotateImage turns src clockwise by degrees (90, 180 or 270); other values return
+// src unchanged. Width and height swap for 90 and 270.
+//
+// Yields every 50 rows like resizeImage.
+func rotateImage(src image.Image, degrees int) image.Image {
+	bounds := src.Bounds()
+	srcW := bounds.Dx()
+	srcH := bounds.Dy()
+
+	var dst *image.RGBA
+	var mapPixel func(x, y int) (int, int) // Destination to source coordinates
+	switch degrees {
+	case 90:
+		dst = image.NewRGBA(image.Rect(0, 0, srcH, srcW))
+		mapPixel = func(x, y int) (int, int) { return y, srcH - 1 - x }
+	case 180:
+		dst = image.NewRGBA(image.Rect(0, 0, srcW, srcH))
+		mapPixel = func(x, y int) (int, int) { return srcW - 1 - x, srcH - 1 - y }
+	case 270:
+		dst = image.NewRGBA(image.Rect(0, 0, srcH, srcW))
+		mapPixel = func(x, y int) (int, int) { return srcW - 1 - y, x }
+	default:
+		return src
+	}
+
+	dstBounds := dst.Bounds()
+	for y := 0; y < dstBounds.Dy(); y++ {
+		if y%50 == 0 && y > 0 {
+			runtime.Gosched()
+		}
+		for x := 0; x < dstBounds.Dx(); x++ {
+			px, py := mapPixel(x, y)
+			dst.Set(x, y, src.At(bounds.Min.X+px, bounds.Min.Y+py))
+		}
+	}
+
+	return dst
+}
+
 // EstimateSize estimates the output file size for given dimensions and quality
 // Returns approximate bytes
 func EstimateSize(width, height, quality int) int {
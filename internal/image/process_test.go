@@ -447,6 +447,87 @@ func TestProcessor_Resize_AspectRatio(t *testing.T) {
 	}
 }
 
+func TestProcessor_Process_Rotate(t *testing.T) {
+	tests := []struct {
+		degrees    int
+		wantWidth  int
+		wantHeight int
+	}{
+		{90, 300, 400},
+		{180, 400, 300},
+		{270, 300, 400},
+	}
+
+	for _, tt := range tests {
+		p := NewProcessor(&config.ImageProcessing{Rotate: tt.degrees})
+		result, err := p.Process(createTestJPEG(400, 300))
+		if err != nil {
+			t.Fatalf("Rotate %d: Process failed: %v", tt.degrees, err)
+		}
+
+		img, _, err := image.Decode(bytes.NewReader(result))
+		if err != nil {
+			t.Fatalf("Rotate %d: failed to decode result: %v", tt.degrees, err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.wantWidth || b.Dy() != tt.wantHeight {
+			t.Errorf("Rotate %d: got %dx%d, want %dx%d", tt.degrees, b.Dx(), b.Dy(), tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+func TestProcessor_Process_RotateThenResize(t *testing.T) {
+	// Limits apply to the rotated image: 400x300 turned to 300x400, then fit in 300x200
+	p := NewProcessor(&config.ImageProcessing{Rotate: 90, MaxWidth: 300, MaxHeight: 200})
+	result, err := p.Process(createTestJPEG(400, 300))
+	if err != nil {
+		t.Fatalf("Process failed: %v", err)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(result))
+	if err != nil {
+		t.Fatalf("Failed to decode result: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 150 || b.Dy() != 200 {
+		t.Errorf("got %dx%d, want 150x200", b.Dx(), b.Dy())
+	}
+}
+
+func TestRotateImage_PixelMapping(t *testing.T) {
+	// 3x2 source with a distinct value per pixel:
+	//   0 1 2
+	//   3 4 5
+	src := image.NewGray(image.Rect(0, 0, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+
+	tests := []struct {
+		degrees int
+		want    [][]uint8 // Rows of the rotated image
+	}{
+		{90, [][]uint8{{3, 0}, {4, 1}, {5, 2}}},
+		{180, [][]uint8{{5, 4, 3}, {2, 1, 0}}},
+		{270, [][]uint8{{2, 5}, {1, 4}, {0, 3}}},
+		{0, [][]uint8{{0, 1, 2}, {3, 4, 5}}},
+	}
+
+	for _, tt := range tests {
+		result := rotateImage(src, tt.degrees)
+		b := result.Bounds()
+		if b.Dy() != len(tt.want) || b.Dx() != len(tt.want[0]) {
+			t.Errorf("Rotate %d: got %dx%d, want %dx%d", tt.degrees, b.Dx(), b.Dy(), len(tt.want[0]), len(tt.want))
+			continue
+		}
+		for y, row := range tt.want {
+			for x, want := range row {
+				if got := color.GrayModel.Convert(result.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y; got != want {
+					t.Errorf("Rotate %d: pixel (%d,%d) = %d, want %d", tt.degrees, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
 func BenchmarkProcessor_Process(b *testing.B) {
 	cfg := &config.ImageProcessing{
 		MaxWidth:  1280,
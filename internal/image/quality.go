@@ -0,0 +1,95 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"runtime"
+)
+
+// qualityGridWidth bounds analysis cost: frames are sampled on a grid at most this wide
+const qualityGridWidth = 320
+
+// QualityMetrics summarizes a frame for tracking imagery quality over time
+type QualityMetrics struct {
+	Width         int     `json:"width"`
+	Height        int     `json:"height"`
+	SizeBytes     int     `json:"size_bytes"`
+	MeanLuminance float64 `json:"mean_luminance"` // 0-255
+	Sharpness     float64 `json:"sharpness"`      // Variance of the Laplacian; drops with fog or defocus
+}
+
+// AnalyzeQuality decodes an image and computes luminance and sharpness on a
+// subsampled grayscale grid, so cost stays roughly constant regardless of resolution.
+func AnalyzeQuality(data []byte) (QualityMetrics, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return QualityMetrics{}, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	metrics := QualityMetrics{
+		Width:     bounds.Dx(),
+		Height:    bounds.Dy(),
+		SizeBytes: len(data),
+	}
+	if metrics.Width == 0 || metrics.Height == 0 {
+		return metrics, nil
+	}
+
+	step := metrics.Width / qualityGridWidth
+	if step < 1 {
+		step = 1
+	}
+	gridW := (metrics.Width + step - 1) / step
+	gridH := (metrics.Height + step - 1) / step
+
+	gray := make([]float64, gridW*gridH)
+	var sum float64
+	for gy := 0; gy < gridH; gy++ {
+		// Cooperative yielding, as in resizeImage, to keep the web console responsive
+		if gy%50 == 0 && gy > 0 {
+			runtime.Gosched()
+		}
+		for gx := 0; gx < gridW; gx++ {
+			l := luminance(img, bounds.Min.X+gx*step, bounds.Min.Y+gy*step)
+			gray[gy*gridW+gx] = l
+			sum += l
+		}
+	}
+	metrics.MeanLuminance = sum / float64(len(gray))
+	metrics.Sharpness = laplacianVariance(gray, gridW, gridH)
+
+	return metrics, nil
+}
+
+// luminance returns Rec. 601 luma (0-255), reading the Y plane directly for JPEGs
+func luminance(img image.Image, x, y int) float64 {
+	if ycc, ok := img.(*image.YCbCr); ok {
+		return float64(ycc.Y[ycc.YOffset(x, y)])
+	}
+	r, g, b, _ := img.At(x, y).RGBA()
+	return (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+}
+
+// laplacianVariance computes the variance of the 4-neighbour Laplacian over the interior
+func laplacianVariance(gray []float64, w, h int) float64 {
+	if w < 3 || h < 3 {
+		return 0
+	}
+
+	var sum, sumSq float64
+	n := 0
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			i := y*w + x
+			lap := 4*gray[i] - gray[i-1] - gray[i+1] - gray[i-w] - gray[i+w]
+			sum += lap
+			sumSq += lap * lap
+			n++
+		}
+	}
+
+	mean := sum / float64(n)
+	return sumSq/float64(n) - mean*mean
+}
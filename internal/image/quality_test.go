@@ -0,0 +1,80 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func encodeGray(t *testing.T, width, height int, pixel func(x, y int) uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: pixel(x, y)})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAnalyzeQuality_Dimensions(t *testing.T) {
+	data := createTestJPEG(800, 600)
+
+	m, err := AnalyzeQuality(data)
+	if err != nil {
+		t.Fatalf("AnalyzeQuality() error = %v", err)
+	}
+	if m.Width != 800 || m.Height != 600 {
+		t.Errorf("dimensions = %dx%d, want 800x600", m.Width, m.Height)
+	}
+	if m.SizeBytes != len(data) {
+		t.Errorf("SizeBytes = %d, want %d", m.SizeBytes, len(data))
+	}
+}
+
+func TestAnalyzeQuality_Luminance(t *testing.T) {
+	dark := encodeGray(t, 64, 64, func(x, y int) uint8 { return 20 })
+	bright := encodeGray(t, 64, 64, func(x, y int) uint8 { return 230 })
+
+	d, err := AnalyzeQuality(dark)
+	if err != nil {
+		t.Fatalf("AnalyzeQuality(dark) error = %v", err)
+	}
+	b, err := AnalyzeQuality(bright)
+	if err != nil {
+		t.Fatalf("AnalyzeQuality(bright) error = %v", err)
+	}
+
+	if d.MeanLuminance > 30 || b.MeanLuminance < 220 {
+		t.Errorf("luminance dark=%.1f bright=%.1f, want ~20 and ~230", d.MeanLuminance, b.MeanLuminance)
+	}
+}
+
+func TestAnalyzeQuality_Sharpness(t *testing.T) {
+	flat := encodeGray(t, 128, 128, func(x, y int) uint8 { return 128 })
+	checker := encodeGray(t, 128, 128, func(x, y int) uint8 {
+		if (x/4+y/4)%2 == 0 {
+			return 0
+		}
+		return 255
+	})
+
+	f, _ := AnalyzeQuality(flat)
+	c, _ := AnalyzeQuality(checker)
+
+	if c.Sharpness <= f.Sharpness {
+		t.Errorf("checkerboard sharpness %.1f should exceed flat %.1f", c.Sharpness, f.Sharpness)
+	}
+}
+
+func TestAnalyzeQuality_InvalidData(t *testing.T) {
+	if _, err := AnalyzeQuality([]byte("not an image")); err == nil {
+		t.Error("expected error for invalid image data")
+	}
+}
@@ -0,0 +1,383 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// Raw tone-mapping curves
+const (
+	CurveLinear = "linear"
+	CurveGamma  = "gamma"
+	CurveLog    = "log"
+	CurveAsinh  = "asinh"
+)
+
+// Defaults for the curve parameters
+const (
+	DefaultRawGamma   = 2.2
+	DefaultRawStretch = 10.0
+)
+
+// maxRawPixels bounds the frame size a raw conversion will allocate for
+const maxRawPixels = 64 << 20
+
+// maxPercentileSamples bounds how many samples are sorted to find the black and
+// white levels; larger frames are sampled evenly
+const maxPercentileSamples = 1 << 20
+
+// fitsBlock is the FITS header and data block size
+const fitsBlock = 2880
+
+// RawConverter turns high bit-depth frames (16-bit PNG or FITS) into 8-bit JPEG
+// with a configurable tone curve. JPEG frames pass through unchanged.
+type RawConverter struct {
+	config config.RawInput
+}
+
+// NewRawConverter creates a converter for cfg, applying defaults
+func NewRawConverter(cfg *config.RawInput) *RawConverter {
+	c := &RawConverter{}
+	if cfg != nil {
+		c.config = *cfg
+	}
+	if c.config.Curve == "" {
+		c.config.Curve = CurveLinear
+	}
+	if c.config.Gamma <= 0 {
+		c.config.Gamma = DefaultRawGamma
+	}
+	if c.config.Stretch <= 0 {
+		c.config.Stretch = DefaultRawStretch
+	}
+	if c.config.WhitePercentile <= 0 {
+		c.config.WhitePercentile = 100
+	}
+	return c
+}
+
+// rawFrame is a decoded frame as planar samples, top row first
+type rawFrame struct {
+	width, height int
+	planes        [][]float64 // One plane (gray) or three (RGB); NaN marks blank samples
+}
+
+// Convert decodes data as 16-bit PNG or FITS and returns it tone-mapped to an 8-bit
+// JPEG. Any other format, or a FITS variant it cannot read, is an error.
+func (c *RawConverter) Convert(data []byte) ([]byte, error) {
+	var frame *rawFrame
+	var err error
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		return data, nil // Already 8-bit JPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		frame, err = decodePNG(data)
+	case bytes.HasPrefix(data, []byte("SIMPLE  =")):
+		frame, err = decodeFITS(data)
+	default:
+		return nil, fmt.Errorf("unsupported raw format (want PNG, FITS or JPEG)")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	img := c.toneMap(frame)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: defaultEncodeQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// toneMap maps the frame's samples between the black and white levels through the
+// curve to 8 bits
+func (c *RawConverter) toneMap(f *rawFrame) image.Image {
+	black, white := levels(f.planes, c.config.BlackPercentile, c.config.WhitePercentile)
+	span := white - black
+	curve := c.curve()
+	lut := func(v float64) uint8 {
+		if math.IsNaN(v) || span <= 0 {
+			return 0
+		}
+		x := (v - black) / span
+		x = math.Max(0, math.Min(1, x))
+		return uint8(math.Round(curve(x) * 255))
+	}
+
+	rect := image.Rect(0, 0, f.width, f.height)
+	if len(f.planes) == 1 {
+		img := image.NewGray(rect)
+		for i, v := range f.planes[0] {
+			if i%(f.width*50) == 0 && i > 0 {
+				runtime.Gosched()
+			}
+			img.Pix[i] = lut(v)
+		}
+		return img
+	}
+	img := image.NewRGBA(rect)
+	for i := range f.planes[0] {
+		if i%(f.width*50) == 0 && i > 0 {
+			runtime.Gosched()
+		}
+		img.Pix[i*4] = lut(f.planes[0][i])
+		img.Pix[i*4+1] = lut(f.planes[1][i])
+		img.Pix[i*4+2] = lut(f.planes[2][i])
+		img.Pix[i*4+3] = 0xFF
+	}
+	return img
+}
+
+// curve returns the configured curve on [0, 1]
+func (c *RawConverter) curve() func(float64) float64 {
+	s := c.config.Stretch
+	switch c.config.Curve {
+	case CurveGamma:
+		inv := 1 / c.config.Gamma
+		return func(x float64) float64 { return math.Pow(x, inv) }
+	case CurveLog:
+		return func(x float64) float64 { return math.Log1p(s*x) / math.Log1p(s) }
+	case CurveAsinh:
+		return func(x float64) float64 { return math.Asinh(s*x) / math.Asinh(s) }
+	}
+	return func(x float64) float64 { return x }
+}
+
+// levels returns the sample values at the black and white percentiles over all
+// planes, ignoring blank samples
+func levels(planes [][]float64, blackPct, whitePct float64) (float64, float64) {
+	total := len(planes) * len(planes[0])
+	step := total/maxPercentileSamples + 1
+	samples := make([]float64, 0, total/step+1)
+	for i := 0; i < total; i += step {
+		if v := planes[i%len(planes)][i/len(planes)]; !math.IsNaN(v) {
+			samples = append(samples, v)
+		}
+	}
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	sort.Float64s(samples)
+	at := func(pct float64) float64 {
+		i := int(math.Round(pct / 100 * float64(len(samples)-1)))
+		return samples[max(0, min(len(samples)-1, i))]
+	}
+	return at(blackPct), at(whitePct)
+}
+
+// decodePNG reads a PNG of any bit depth into planes
+func decodePNG(data []byte) (*rawFrame, error) {
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode PNG: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxRawPixels {
+		return nil, fmt.Errorf("PNG of %dx%d exceeds the raw frame size limit", cfg.Width, cfg.Height)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode PNG: %w", err)
+	}
+
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	f := &rawFrame{width: w, height: h}
+	switch src := img.(type) {
+	case *image.Gray16:
+		plane := make([]float64, w*h)
+		for y := 0; y < h; y++ {
+			row := src.Pix[y*src.Stride:]
+			for x := 0; x < w; x++ {
+				plane[y*w+x] = float64(binary.BigEndian.Uint16(row[x*2:]))
+			}
+		}
+		f.planes = [][]float64{plane}
+	case *image.Gray:
+		plane := make([]float64, w*h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				plane[y*w+x] = float64(src.Pix[y*src.Stride+x]) * 257
+			}
+		}
+		f.planes = [][]float64{plane}
+	default:
+		// Color PNGs, 8 or 16 bits; alpha is ignored
+		f.planes = [][]float64{make([]float64, w*h), make([]float64, w*h), make([]float64, w*h)}
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+				f.planes[0][y*w+x] = float64(r)
+				f.planes[1][y*w+x] = float64(g)
+				f.planes[2][y*w+x] = float64(bl)
+			}
+		}
+	}
+	return f, nil
+}
+
+// fitsHeader holds the primary header keywords the decoder uses
+type fitsHeader struct {
+	bitpix       int
+	naxis        []int
+	bzero        float64
+	bscale       float64
+	blank        *int64
+	headerLength int
+}
+
+// decodeFITS reads the primary image of a FITS file: 2D gray, or 3D with three
+// planes as RGB. Extensions, including tile-compressed images, are not read.
+func decodeFITS(data []byte) (*rawFrame, error) {
+	hdr, err := parseFITSHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	switch hdr.bitpix {
+	case 8, 16, 32, 64, -32, -64:
+	default:
+		return nil, fmt.Errorf("unsupported FITS BITPIX %d", hdr.bitpix)
+	}
+	channels := 1
+	switch {
+	case len(hdr.naxis) == 0:
+		return nil, fmt.Errorf("FITS file has no primary image (extensions and tile compression are not supported)")
+	case len(hdr.naxis) == 2:
+	case len(hdr.naxis) == 3 && (hdr.naxis[2] == 1 || hdr.naxis[2] == 3):
+		channels = hdr.naxis[2]
+	default:
+		return nil, fmt.Errorf("unsupported FITS NAXIS %d (want a 2D image or 3 color planes)", len(hdr.naxis))
+	}
+	w, h := hdr.naxis[0], hdr.naxis[1]
+	if w <= 0 || h <= 0 || w*h > maxRawPixels {
+		return nil, fmt.Errorf("unsupported FITS image size %dx%d", w, h)
+	}
+
+	size := abs(hdr.bitpix) / 8
+	n := w * h
+	body := data[hdr.headerLength:]
+	if len(body) < n*channels*size {
+		return nil, fmt.Errorf("truncated FITS data: %d of %d bytes", len(body), n*channels*size)
+	}
+
+	f := &rawFrame{width: w, height: h}
+	for ch := 0; ch < channels; ch++ {
+		plane := make([]float64, n)
+		for i := 0; i < n; i++ {
+			raw, blank := fitsSample(body[(ch*n+i)*size:], hdr)
+			if blank {
+				plane[flipRow(i, w, h)] = math.NaN()
+				continue
+			}
+			plane[flipRow(i, w, h)] = hdr.bzero + hdr.bscale*raw
+		}
+		f.planes = append(f.planes, plane)
+	}
+	return f, nil
+}
+
+// fitsSample reads one big-endian sample, reporting BLANK or NaN values
+func fitsSample(b []byte, hdr fitsHeader) (float64, bool) {
+	var v int64
+	switch hdr.bitpix {
+	case 8:
+		v = int64(b[0])
+	case 16:
+		v = int64(int16(binary.BigEndian.Uint16(b)))
+	case 32:
+		v = int64(int32(binary.BigEndian.Uint32(b)))
+	case 64:
+		v = int64(binary.BigEndian.Uint64(b))
+	case -32:
+		f := float64(math.Float32frombits(binary.BigEndian.Uint32(b)))
+		return f, math.IsNaN(f)
+	case -64:
+		f := math.Float64frombits(binary.BigEndian.Uint64(b))
+		return f, math.IsNaN(f)
+	}
+	return float64(v), hdr.blank != nil && v == *hdr.blank
+}
+
+// flipRow maps a FITS sample index (first row at the bottom) to a top-first index
+func flipRow(i, w, h int) int {
+	return (h-1-i/w)*w + i%w
+}
+
+// parseFITSHeader reads the primary header's 80-character cards up to END
+func parseFITSHeader(data []byte) (fitsHeader, error) {
+	hdr := fitsHeader{bscale: 1}
+	naxis := -1
+	axes := map[int]int{}
+	for off := 0; ; off += 80 {
+		if off+80 > len(data) {
+			return hdr, fmt.Errorf("truncated FITS header")
+		}
+		card := string(data[off : off+80])
+		key := strings.TrimSpace(card[:8])
+		if key == "END" {
+			hdr.headerLength = (off/fitsBlock + 1) * fitsBlock
+			break
+		}
+		if card[8:10] != "= " {
+			continue // COMMENT, HISTORY and the like
+		}
+		value := strings.TrimSpace(card[10:])
+		if i := strings.Index(value, "/"); i >= 0 && !strings.HasPrefix(value, "'") {
+			value = strings.TrimSpace(value[:i])
+		}
+		var err error
+		switch {
+		case key == "SIMPLE" && value != "T":
+			return hdr, fmt.Errorf("FITS file does not conform to the standard (SIMPLE = %s)", value)
+		case key == "BITPIX":
+			hdr.bitpix, err = strconv.Atoi(value)
+		case key == "NAXIS":
+			naxis, err = strconv.Atoi(value)
+		case strings.HasPrefix(key, "NAXIS"):
+			var axis, length int
+			if axis, err = strconv.Atoi(key[5:]); err == nil {
+				length, err = strconv.Atoi(value)
+				axes[axis] = length
+			}
+		case key == "BZERO":
+			hdr.bzero, err = strconv.ParseFloat(value, 64)
+		case key == "BSCALE":
+			hdr.bscale, err = strconv.ParseFloat(value, 64)
+		case key == "BLANK":
+			var blank int64
+			blank, err = strconv.ParseInt(value, 10, 64)
+			hdr.blank = &blank
+		}
+		if err != nil {
+			return hdr, fmt.Errorf("invalid FITS %s value %q", key, value)
+		}
+	}
+	if naxis < 0 || hdr.bitpix == 0 {
+		return hdr, fmt.Errorf("FITS header is missing BITPIX or NAXIS")
+	}
+	for i := 1; i <= naxis; i++ {
+		length, ok := axes[i]
+		if !ok {
+			return hdr, fmt.Errorf("FITS header is missing NAXIS%d", i)
+		}
+		hdr.naxis = append(hdr.naxis, length)
+	}
+	return hdr, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
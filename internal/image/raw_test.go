@@ -0,0 +1,126 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// fitsFile builds a minimal FITS file with the given header cards and data
+func fitsFile(cards []string, data []byte) []byte {
+	var hdr strings.Builder
+	for _, c := range append(cards, "END") {
+		hdr.WriteString(fmt.Sprintf("%-80s", c))
+	}
+	for hdr.Len()%fitsBlock != 0 {
+		hdr.WriteByte(' ')
+	}
+	return append([]byte(hdr.String()), data...)
+}
+
+// decodeGray decodes a converted JPEG and returns its gray level at (x, y)
+func decodeGray(t *testing.T, data []byte, x, y int) uint8 {
+	t.Helper()
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("converted frame is not a JPEG: %v", err)
+	}
+	return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y
+}
+
+func TestRawConverter_PNG16(t *testing.T) {
+	src := image.NewGray16(image.Rect(0, 0, 16, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			v := uint16(1000) // Dark background
+			if x >= 8 {
+				v = 5000
+			}
+			src.SetGray16(x, y, color.Gray16{Y: v})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatalf("encode PNG: %v", err)
+	}
+
+	// The levels stretch to the frame's own range, not the full 16 bits
+	out, err := NewRawConverter(nil).Convert(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	if dark, bright := decodeGray(t, out, 2, 8), decodeGray(t, out, 13, 8); dark > 10 || bright < 245 {
+		t.Errorf("levels = %d and %d, want stretched to black and white", dark, bright)
+	}
+}
+
+func TestRawConverter_FITS(t *testing.T) {
+	// 4x2 unsigned 16-bit via BZERO; the first row stored is the bottom one
+	data := make([]byte, 0, 16)
+	for _, v := range []uint16{0, 0, 0, 0, 40000, 40000, 40000, 40000} {
+		data = binary.BigEndian.AppendUint16(data, uint16(int32(v)-32768))
+	}
+	file := fitsFile([]string{
+		"SIMPLE  =                    T",
+		"BITPIX  =                   16",
+		"NAXIS   =                    2",
+		"NAXIS1  =                    4",
+		"NAXIS2  =                    2",
+		"BZERO   =                32768 / unsigned",
+		"COMMENT sky camera",
+	}, data)
+
+	out, err := NewRawConverter(&config.RawInput{Curve: CurveAsinh}).Convert(file)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+	img, _ := jpeg.Decode(bytes.NewReader(out))
+	if b := img.Bounds(); b.Dx() != 4 || b.Dy() != 2 {
+		t.Fatalf("size = %v, want 4x2", b)
+	}
+	if top, bottom := decodeGray(t, out, 1, 0), decodeGray(t, out, 1, 1); top < 200 || bottom > 50 {
+		t.Errorf("top %d, bottom %d: want FITS rows flipped so the bright row is on top", top, bottom)
+	}
+}
+
+func TestRawConverter_Curves(t *testing.T) {
+	for curve, want := range map[string]float64{CurveLinear: 0.25, CurveGamma: 0.533, CurveLog: 0.522, CurveAsinh: 0.549} {
+		got := NewRawConverter(&config.RawInput{Curve: curve}).curve()(0.25)
+		if got < want-0.01 || got > want+0.01 {
+			t.Errorf("%s(0.25) = %.3f, want %.3f", curve, got, want)
+		}
+	}
+}
+
+func TestRawConverter_Unsupported(t *testing.T) {
+	jpegFrame := encodeGray(t, 8, 8, func(x, y int) uint8 { return 128 })
+	if out, err := NewRawConverter(nil).Convert(jpegFrame); err != nil || !bytes.Equal(out, jpegFrame) {
+		t.Errorf("JPEG frame: Convert() = %d bytes, %v; want it unchanged", len(out), err)
+	}
+
+	tests := map[string]struct {
+		data []byte
+		want string
+	}{
+		"tiff": {[]byte("II*\x00rest"), "unsupported raw format"},
+		"bitpix": {fitsFile([]string{"SIMPLE  =                    T", "BITPIX  =                   12", "NAXIS   =                    2", "NAXIS1  =                    1", "NAXIS2  =                    1"}, make([]byte, 4)),
+			"unsupported FITS BITPIX 12"},
+		"compressed": {fitsFile([]string{"SIMPLE  =                    T", "BITPIX  =                    8", "NAXIS   =                    0", "EXTEND  =                    T"}, nil),
+			"no primary image"},
+		"truncated": {fitsFile([]string{"SIMPLE  =                    T", "BITPIX  =                   16", "NAXIS   =                    2", "NAXIS1  =                   10", "NAXIS2  =                   10"}, make([]byte, 20)),
+			"truncated FITS data"},
+	}
+	for name, tt := range tests {
+		if _, err := NewRawConverter(nil).Convert(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Convert() error = %v, want %q", name, err, tt.want)
+		}
+	}
+}
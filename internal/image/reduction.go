@@ -0,0 +1,64 @@
+package image
+
+import (
+	"sync"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+// Reduction temporarily caps JPEG quality and width for every processor sharing it,
+// e.g. to slow queue growth while disk space is low. The zero value is inactive.
+type Reduction struct {
+	mu     sync.RWMutex
+	limits *config.ImageProcessing // nil when inactive
+}
+
+// Set starts capping processed images at limits' quality and max width (0 = no cap
+// on that setting); nil lifts the caps
+func (r *Reduction) Set(limits *config.ImageProcessing) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limits == nil {
+		r.limits = nil
+		return
+	}
+	copy := *limits
+	r.limits = &copy
+}
+
+// Active reports whether images are currently being reduced
+func (r *Reduction) Active() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.limits != nil
+}
+
+// apply returns cfg with the active caps applied. A cap only ever lowers a
+// camera's own setting; cfg itself is never modified.
+func (r *Reduction) apply(cfg *config.ImageProcessing) *config.ImageProcessing {
+	r.mu.RLock()
+	limits := r.limits
+	r.mu.RUnlock()
+	if limits == nil {
+		return cfg
+	}
+
+	reduced := config.ImageProcessing{}
+	if cfg != nil {
+		reduced = *cfg
+	}
+	reduced.Quality = lowerLimit(reduced.GetQuality(), limits.Quality)
+	reduced.MaxWidth = lowerLimit(reduced.MaxWidth, limits.MaxWidth)
+	return &reduced
+}
+
+// lowerLimit returns the tighter of two limits where 0 means unlimited
+func lowerLimit(current, limit int) int {
+	if limit > 0 && (current == 0 || limit < current) {
+		return limit
+	}
+	return current
+}
@@ -0,0 +1,68 @@
+package image
+
+import (
+	"bytes"
+	"image"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+func TestReduction_apply(t *testing.T) {
+	r := &Reduction{}
+	own := &config.ImageProcessing{MaxWidth: 1920, Quality: 90}
+	if got := r.apply(own); got != own {
+		t.Errorf("inactive reduction changed config: %+v", got)
+	}
+
+	r.Set(&config.ImageProcessing{Quality: 60, MaxWidth: 1280})
+	if got := r.apply(own); got.Quality != 60 || got.MaxWidth != 1280 {
+		t.Errorf("reduced = %+v, want quality 60, width 1280", got)
+	}
+	if own.Quality != 90 || own.MaxWidth != 1920 {
+		t.Errorf("camera config modified: %+v", own)
+	}
+
+	// Caps never raise a camera's own tighter settings
+	if got := r.apply(&config.ImageProcessing{MaxWidth: 640, Quality: 50}); got.Quality != 50 || got.MaxWidth != 640 {
+		t.Errorf("reduced = %+v, want quality 50, width 640", got)
+	}
+
+	// Without a quality cap the camera's own quality setting is kept
+	r.Set(&config.ImageProcessing{MaxWidth: 800})
+	if got := r.apply(nil); got.Quality != 0 || got.MaxWidth != 800 {
+		t.Errorf("reduced nil config = %+v", got)
+	}
+
+	r.Set(nil)
+	if r.Active() || r.apply(own) != own {
+		t.Error("reduction still active after Set(nil)")
+	}
+}
+
+func TestProcessor_WithReduction(t *testing.T) {
+	r := &Reduction{}
+	p := NewProcessor(nil).WithReduction(r)
+	original := createTestJPEG(800, 600)
+
+	result, err := p.Process(original)
+	if err != nil || !bytes.Equal(result, original) {
+		t.Fatalf("inactive reduction processed the image: %v", err)
+	}
+
+	r.Set(&config.ImageProcessing{Quality: 40, MaxWidth: 400})
+	result, err = p.Process(original)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	img, _, err := image.Decode(bytes.NewReader(result))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if w := img.Bounds().Dx(); w != 400 {
+		t.Errorf("width = %d, want 400", w)
+	}
+	if len(result) >= len(original) {
+		t.Errorf("reduced image is %d bytes, original %d", len(result), len(original))
+	}
+}
@@ -0,0 +1,155 @@
+package image
+
+import "errors"
+
+// Errors returned by TrimJPEG
+var (
+	ErrNoSOI = errors.New("no JPEG start-of-image marker found")
+	ErrNoEOI = errors.New("no JPEG end-of-image marker found")
+)
+
+// TrimJPEG returns the bytes from the first SOI marker through its matching EOI,
+// discarding any preamble or trailing garbage around the image.
+//
+// Segments are walked by their declared lengths so that markers inside metadata
+// (e.g. an EXIF thumbnail, which is a complete JPEG in APP1) are never mistaken
+// for the end of the outer image. Returns the number of leading and trailing
+// bytes removed; the returned slice aliases data.
+func TrimJPEG(data []byte) (trimmed []byte, leading, trailing int, err error) {
+	start := findSOI(data)
+	if start < 0 {
+		return nil, 0, 0, ErrNoSOI
+	}
+
+	end, err := findEOI(data, start+2)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return data[start:end], start, len(data) - end, nil
+}
+
+// findSOI locates FF D8 FF, the SOI marker followed by the first segment marker.
+// Requiring the trailing FF avoids matching FF D8 that happens to appear in a preamble.
+func findSOI(data []byte) int {
+	for i := 0; i+2 < len(data); i++ {
+		if data[i] == 0xFF && data[i+1] == 0xD8 && data[i+2] == 0xFF {
+			return i
+		}
+	}
+	return -1
+}
+
+// JPEGRepair describes the fix applied by RepairJPEG
+type JPEGRepair string
+
+const (
+	RepairNone         JPEGRepair = ""
+	RepairAppendedEOI  JPEGRepair = "appended_eoi"       // Scan data ran to the end; FF D9 appended
+	RepairCompletedEOI JPEGRepair = "completed_eoi"      // Data ended with a lone FF; D9 appended
+	RepairTrimmedByte  JPEGRepair = "trimmed_stray_byte" // One stray byte after EOI removed
+)
+
+// RepairJPEG fixes defects at the very end of an otherwise well-formed JPEG: a
+// missing or half-written EOI after the scan data, or a single stray byte after the
+// EOI. Headers and scan data are never modified, and anything else (preamble,
+// truncated headers, longer trailers) is returned unchanged with RepairNone or an
+// error. data must start with SOI; a repaired result never aliases data.
+//
+// A frame truncated mid-scan also lacks its EOI and cannot be told apart, so the
+// salvaged image may have a gray lower portion; callers opt in per camera.
+func RepairJPEG(data []byte) ([]byte, JPEGRepair, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return data, RepairNone, ErrNoSOI
+	}
+
+	end, inScan, err := walkSegments(data, 2)
+	switch {
+	case err == nil && end == len(data)-1:
+		return append([]byte(nil), data[:end]...), RepairTrimmedByte, nil
+	case err == nil:
+		return data, RepairNone, nil
+	case !inScan:
+		return data, RepairNone, err
+	}
+
+	repaired := make([]byte, len(data), len(data)+2)
+	copy(repaired, data)
+	if data[len(data)-1] == 0xFF {
+		return append(repaired, 0xD9), RepairCompletedEOI, nil
+	}
+	return append(repaired, 0xFF, 0xD9), RepairAppendedEOI, nil
+}
+
+// findEOI walks marker segments starting at pos and returns the offset just past
+// the EOI marker that terminates the image.
+func findEOI(data []byte, pos int) (int, error) {
+	end, _, err := walkSegments(data, pos)
+	return end, err
+}
+
+// walkSegments implements findEOI. When no EOI is found, inScan reports whether the
+// data ran out inside entropy-coded scan data (as opposed to in headers or segments).
+func walkSegments(data []byte, pos int) (end int, inScan bool, err error) {
+	for pos < len(data) {
+		if data[pos] != 0xFF {
+			return 0, false, ErrNoEOI
+		}
+		// Skip fill bytes preceding a marker
+		for pos < len(data) && data[pos] == 0xFF {
+			pos++
+		}
+		if pos >= len(data) {
+			return 0, false, ErrNoEOI
+		}
+
+		marker := data[pos]
+		pos++
+
+		switch {
+		case marker == 0xD9: // EOI
+			return pos, false, nil
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7): // TEM, RSTn have no length
+			continue
+		}
+
+		if pos+2 > len(data) {
+			return 0, false, ErrNoEOI
+		}
+		length := int(data[pos])<<8 | int(data[pos+1])
+		if length < 2 || pos+length > len(data) {
+			return 0, false, ErrNoEOI
+		}
+		pos += length
+
+		if marker == 0xDA { // SOS: entropy-coded data follows the header
+			pos = skipEntropyData(data, pos)
+			if pos >= len(data) {
+				return 0, true, ErrNoEOI
+			}
+		}
+	}
+	return 0, false, ErrNoEOI
+}
+
+// skipEntropyData advances past entropy-coded scan data to the next real marker.
+// Within scan data, FF 00 is a stuffed byte and FF D0-D7 are restart markers.
+func skipEntropyData(data []byte, pos int) int {
+	for pos+1 < len(data) {
+		if data[pos] != 0xFF {
+			pos++
+			continue
+		}
+		next := data[pos+1]
+		if next == 0x00 || (next >= 0xD0 && next <= 0xD7) {
+			pos += 2
+			continue
+		}
+		if next == 0xFF {
+			pos++
+			continue
+		}
+		return pos
+	}
+	return len(data)
+}
@@ -0,0 +1,165 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// withThumbnail inserts an APP1 segment containing a complete JPEG right after SOI,
+// mimicking an EXIF thumbnail with its own SOI/EOI markers.
+func withThumbnail(outer, thumb []byte) []byte {
+	payload := append([]byte("Exif\x00\x00"), thumb...)
+	length := len(payload) + 2
+
+	var buf bytes.Buffer
+	buf.Write(outer[:2])
+	buf.Write([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)})
+	buf.Write(payload)
+	buf.Write(outer[2:])
+	return buf.Bytes()
+}
+
+func TestTrimJPEG_Clean(t *testing.T) {
+	data := createTestJPEG(64, 48)
+
+	trimmed, leading, trailing, err := TrimJPEG(data)
+	if err != nil {
+		t.Fatalf("TrimJPEG() error = %v", err)
+	}
+	if leading != 0 || trailing != 0 {
+		t.Errorf("expected no trimming, got leading=%d trailing=%d", leading, trailing)
+	}
+	if !bytes.Equal(trimmed, data) {
+		t.Error("clean JPEG should be returned unchanged")
+	}
+}
+
+func TestTrimJPEG_PreambleAndTrailer(t *testing.T) {
+	jpegData := createTestJPEG(64, 48)
+	preamble := []byte("HTTP/1.1 200 OK\r\nContent-Type: image/jpeg\r\n\r\n")
+	trailer := []byte("\r\n--boundary--\r\n")
+
+	data := append(append(append([]byte{}, preamble...), jpegData...), trailer...)
+
+	trimmed, leading, trailing, err := TrimJPEG(data)
+	if err != nil {
+		t.Fatalf("TrimJPEG() error = %v", err)
+	}
+	if leading != len(preamble) {
+		t.Errorf("leading = %d, want %d", leading, len(preamble))
+	}
+	if trailing != len(trailer) {
+		t.Errorf("trailing = %d, want %d", trailing, len(trailer))
+	}
+	if !bytes.Equal(trimmed, jpegData) {
+		t.Error("trimmed data does not match original JPEG")
+	}
+}
+
+func TestTrimJPEG_EmbeddedThumbnail(t *testing.T) {
+	jpegData := withThumbnail(createTestJPEG(64, 48), createTestJPEG(8, 8))
+	data := append(append([]byte{}, jpegData...), []byte("garbage")...)
+
+	trimmed, _, trailing, err := TrimJPEG(data)
+	if err != nil {
+		t.Fatalf("TrimJPEG() error = %v", err)
+	}
+	if trailing != len("garbage") {
+		t.Errorf("trailing = %d, want %d", trailing, len("garbage"))
+	}
+	if !bytes.Equal(trimmed, jpegData) {
+		t.Errorf("trimmed length = %d, want %d (thumbnail EOI must not end the image)", len(trimmed), len(jpegData))
+	}
+}
+
+func TestTrimJPEG_Errors(t *testing.T) {
+	jpegData := createTestJPEG(64, 48)
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"empty", nil, ErrNoSOI},
+		{"not a jpeg", []byte("hello world"), ErrNoSOI},
+		{"truncated", jpegData[:len(jpegData)/2], ErrNoEOI},
+		{"missing EOI", jpegData[:len(jpegData)-2], ErrNoEOI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := TrimJPEG(tt.data)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("TrimJPEG() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepairJPEG(t *testing.T) {
+	jpegData := createTestJPEG(64, 48)
+	body := jpegData[:len(jpegData)-2] // Without EOI
+
+	tests := []struct {
+		name   string
+		data   []byte
+		want   []byte
+		repair JPEGRepair
+	}{
+		{"complete", jpegData, jpegData, RepairNone},
+		{"missing EOI", body, jpegData, RepairAppendedEOI},
+		{"half EOI", jpegData[:len(jpegData)-1], jpegData, RepairCompletedEOI},
+		{"stray byte", append(append([]byte{}, jpegData...), 0x00), jpegData, RepairTrimmedByte},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, repair, err := RepairJPEG(tt.data)
+			if err != nil {
+				t.Fatalf("RepairJPEG() error = %v", err)
+			}
+			if repair != tt.repair {
+				t.Errorf("repair = %q, want %q", repair, tt.repair)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("repaired length = %d, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestRepairJPEG_LeavesOtherDefects(t *testing.T) {
+	jpegData := createTestJPEG(64, 48)
+	trailer := append(append([]byte{}, jpegData...), []byte("garbage")...)
+
+	got, repair, err := RepairJPEG(trailer)
+	if err != nil || repair != RepairNone || !bytes.Equal(got, trailer) {
+		t.Errorf("longer trailer should be left for TrimJPEG: repair=%q err=%v", repair, err)
+	}
+
+	// Truncated inside the headers, before any scan data
+	if _, repair, err := RepairJPEG(jpegData[:20]); !errors.Is(err, ErrNoEOI) || repair != RepairNone {
+		t.Errorf("truncated headers: repair=%q err=%v, want ErrNoEOI", repair, err)
+	}
+
+	preamble := append([]byte("HTTP"), jpegData...)
+	if _, _, err := RepairJPEG(preamble); !errors.Is(err, ErrNoSOI) {
+		t.Errorf("preamble: err = %v, want ErrNoSOI", err)
+	}
+}
+
+func TestRepairJPEG_DoesNotAlias(t *testing.T) {
+	jpegData := createTestJPEG(64, 48)
+	body := make([]byte, len(jpegData)-2, len(jpegData)+16)
+	copy(body, jpegData)
+
+	repaired, _, err := RepairJPEG(body)
+	if err != nil {
+		t.Fatalf("RepairJPEG() error = %v", err)
+	}
+	repaired[0] = 0x00
+	if body[0] != 0xFF {
+		t.Error("repaired slice must not share storage with the input")
+	}
+}
@@ -2,6 +2,7 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"os"
@@ -57,18 +58,8 @@ func New(cfg Config) *Logger {
 		globalBuffer = NewBuffer(1000) // Keep last 1000 log entries
 	}
 
-	// Parse level
-	var level slog.Level
-	switch strings.ToLower(cfg.Level) {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn", "warning":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
+	level, ok := ParseLevel(cfg.Level)
+	if !ok {
 		level = slog.LevelInfo
 	}
 
@@ -78,10 +69,11 @@ func New(cfg Config) *Logger {
 		output = os.Stdout
 	}
 
-	// Create handler
+	// Create handler. It writes every level; levelHandler filters, so loggers
+	// derived with WithLevel can log below the configured level.
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{
-		Level: level,
+		Level: slog.LevelDebug,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			// Shorten time format
 			if a.Key == slog.TimeKey {
@@ -102,7 +94,7 @@ func New(cfg Config) *Logger {
 	}
 
 	return &Logger{
-		slog:   slog.New(handler),
+		slog:   slog.New(&levelHandler{Handler: handler, level: level}),
 		level:  level,
 		format: cfg.Format,
 		buffer: globalBuffer,
@@ -164,6 +156,60 @@ func (l *Logger) With(keysAndValues ...interface{}) *Logger {
 	}
 }
 
+// WithLevel returns a logger writing at level or above instead of the configured
+// level, which may be lower. level may change later, e.g. a *slog.LevelVar, to
+// adjust one component's verbosity at runtime.
+func (l *Logger) WithLevel(level slog.Leveler) *Logger {
+	handler := l.slog.Handler()
+	if h, ok := handler.(*levelHandler); ok {
+		handler = h.Handler
+	}
+	return &Logger{
+		slog:   slog.New(&levelHandler{Handler: handler, level: level}),
+		level:  l.level,
+		format: l.format,
+		buffer: l.buffer,
+	}
+}
+
+// Level returns the configured minimum level
+func (l *Logger) Level() slog.Level {
+	return l.level
+}
+
+// ParseLevel parses debug, info, warn (or warning) and error, in any case
+func ParseLevel(s string) (slog.Level, bool) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return slog.LevelInfo, false
+}
+
+// levelHandler applies a minimum level to a handler that accepts every level
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
 // GetSlog returns the underlying slog.Logger
 func (l *Logger) GetSlog() *slog.Logger {
 	return l.slog
@@ -2,6 +2,7 @@ package logger
 
 import (
 	"bytes"
+	"log/slog"
 	"strings"
 	"testing"
 )
@@ -110,6 +111,30 @@ func TestLogger_DebugFiltering(t *testing.T) {
 	}
 }
 
+func TestLogger_WithLevel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := New(Config{Level: "info", Format: "text", Output: buf})
+	level := new(slog.LevelVar)
+	level.Set(slog.LevelDebug)
+	cam := base.With("camera", "kspb").WithLevel(level)
+
+	cam.Debug("camera debug")
+	base.Debug("base debug")
+	if out := buf.String(); !strings.Contains(out, "camera debug") || !strings.Contains(out, "camera=kspb") ||
+		strings.Contains(out, "base debug") {
+		t.Errorf("want only the overridden logger at debug, got: %s", out)
+	}
+
+	// The level applies to loggers derived from it and can change at runtime
+	buf.Reset()
+	level.Set(slog.LevelWarn)
+	cam.With("job", 1).Info("camera info")
+	base.Info("base info")
+	if out := buf.String(); strings.Contains(out, "camera info") || !strings.Contains(out, "base info") {
+		t.Errorf("want camera info filtered at warn, got: %s", out)
+	}
+}
+
 func TestLogger_JSONFormat(t *testing.T) {
 	buf := &bytes.Buffer{}
 	l := New(Config{
@@ -0,0 +1,133 @@
+// Package metrics serves a small, stable set of Prometheus text-format gauges for
+// alerting rules. Metric names and labels are part of the public interface.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Camera is the state behind one camera's gauges
+type Camera struct {
+	ID          string
+	LastCapture time.Time     // Last successful capture (zero if none)
+	LastUpload  time.Time     // Last successful upload (zero if none)
+	UpWindow    time.Duration // How recent both must be for camera_up to be 1
+	Rates       *Rates        // Achieved throughput; nil when not tracked
+}
+
+// Rates is a camera's configured capture rate and its smoothed achieved rates, per minute
+type Rates struct {
+	ConfiguredCapturesPerMin float64
+	CapturesPerMin           float64
+	CapturedBytesPerMin      float64
+	UploadsPerMin            float64
+	UploadedBytesPerMin      float64
+}
+
+// Up reports whether the camera captured and uploaded successfully within its window
+func (c Camera) Up(now time.Time) bool {
+	if c.LastCapture.IsZero() || c.LastUpload.IsZero() {
+		return false
+	}
+	return now.Sub(c.LastCapture) <= c.UpWindow && now.Sub(c.LastUpload) <= c.UpWindow
+}
+
+// Snapshot is everything rendered by one scrape
+type Snapshot struct {
+	Version string
+	Commit  string
+	Cameras []Camera
+}
+
+// Handler serves the metrics produced by snapshot, which is called on every scrape
+func Handler(snapshot func() Snapshot) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w, snapshot(), time.Now())
+	})
+}
+
+// Write renders s in the Prometheus text exposition format
+func Write(w io.Writer, s Snapshot, now time.Time) {
+	cameras := append([]Camera(nil), s.Cameras...)
+	sort.Slice(cameras, func(i, j int) bool { return cameras[i].ID < cameras[j].ID })
+
+	header(w, "bridge_build_info", "Bridge build information; the value is always 1.")
+	fmt.Fprintf(w, "bridge_build_info{version=\"%s\",commit=\"%s\",goversion=\"%s\"} 1\n",
+		escape(s.Version), escape(s.Commit), escape(runtime.Version()))
+
+	header(w, "camera_up", "1 if the camera captured and uploaded successfully within its up window, else 0.")
+	for _, c := range cameras {
+		up := 0
+		if c.Up(now) {
+			up = 1
+		}
+		fmt.Fprintf(w, "camera_up{camera=\"%s\"} %d\n", escape(c.ID), up)
+	}
+
+	header(w, "camera_up_window_seconds", "Maximum age of the last capture and upload for camera_up to be 1.")
+	for _, c := range cameras {
+		fmt.Fprintf(w, "camera_up_window_seconds{camera=\"%s\"} %g\n", escape(c.ID), c.UpWindow.Seconds())
+	}
+
+	header(w, "camera_last_capture_timestamp_seconds", "Unix time of the last successful capture, 0 if none.")
+	for _, c := range cameras {
+		fmt.Fprintf(w, "camera_last_capture_timestamp_seconds{camera=\"%s\"} %d\n", escape(c.ID), unix(c.LastCapture))
+	}
+
+	header(w, "camera_last_upload_timestamp_seconds", "Unix time of the last successful upload, 0 if none.")
+	for _, c := range cameras {
+		fmt.Fprintf(w, "camera_last_upload_timestamp_seconds{camera=\"%s\"} %d\n", escape(c.ID), unix(c.LastUpload))
+	}
+
+	rateGauge(w, cameras, "camera_configured_captures_per_minute", "Captures per minute the capture interval asks for.",
+		func(r *Rates) float64 { return r.ConfiguredCapturesPerMin })
+	rateGauge(w, cameras, "camera_captures_per_minute", "Smoothed frames captured and queued per minute.",
+		func(r *Rates) float64 { return r.CapturesPerMin })
+	rateGauge(w, cameras, "camera_captured_bytes_per_minute", "Smoothed bytes captured and queued per minute.",
+		func(r *Rates) float64 { return r.CapturedBytesPerMin })
+	rateGauge(w, cameras, "camera_uploads_per_minute", "Smoothed frames uploaded per minute.",
+		func(r *Rates) float64 { return r.UploadsPerMin })
+	rateGauge(w, cameras, "camera_uploaded_bytes_per_minute", "Smoothed bytes uploaded per minute.",
+		func(r *Rates) float64 { return r.UploadedBytesPerMin })
+}
+
+// rateGauge writes one throughput gauge for the cameras that track rates; it is
+// left out entirely when none do
+func rateGauge(w io.Writer, cameras []Camera, name, help string, value func(*Rates) float64) {
+	wrote := false
+	for _, c := range cameras {
+		if c.Rates == nil {
+			continue
+		}
+		if !wrote {
+			header(w, name, help)
+			wrote = true
+		}
+		fmt.Fprintf(w, "%s{camera=\"%s\"} %g\n", name, escape(c.ID), value(c.Rates))
+	}
+}
+
+func header(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+func unix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// labelEscaper escapes label values per the text exposition format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
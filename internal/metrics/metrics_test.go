@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCamera_Up(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := 3 * time.Minute
+
+	tests := []struct {
+		name    string
+		capture time.Duration // Age of the last capture (-1 = never)
+		upload  time.Duration // Age of the last upload (-1 = never)
+		want    bool
+	}{
+		{"both recent", time.Minute, 2 * time.Minute, true},
+		{"at window edge", window, window, true},
+		{"stale capture", 4 * time.Minute, time.Minute, false},
+		{"stale upload", time.Minute, 10 * time.Minute, false},
+		{"never uploaded", time.Minute, -1, false},
+		{"never captured", -1, time.Minute, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Camera{ID: "cam", UpWindow: window}
+			if tt.capture >= 0 {
+				c.LastCapture = now.Add(-tt.capture)
+			}
+			if tt.upload >= 0 {
+				c.LastUpload = now.Add(-tt.upload)
+			}
+			if got := c.Up(now); got != tt.want {
+				t.Errorf("Up() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var sb strings.Builder
+	Write(&sb, Snapshot{
+		Version: "1.2.3",
+		Commit:  "abc\"def",
+		Cameras: []Camera{
+			{ID: "south", UpWindow: time.Minute},
+			{ID: "north", LastCapture: now.Add(-10 * time.Second), LastUpload: now.Add(-20 * time.Second), UpWindow: 3 * time.Minute},
+		},
+	}, now)
+	out := sb.String()
+
+	for _, want := range []string{
+		"# TYPE bridge_build_info gauge\n",
+		`bridge_build_info{version="1.2.3",commit="abc\"def",goversion="go`,
+		"# TYPE camera_up gauge\n",
+		"camera_up{camera=\"north\"} 1\ncamera_up{camera=\"south\"} 0\n",
+		"camera_up_window_seconds{camera=\"north\"} 180\n",
+		"camera_last_capture_timestamp_seconds{camera=\"north\"} 1748779190\n",
+		"camera_last_upload_timestamp_seconds{camera=\"north\"} 1748779180\n",
+		"camera_last_upload_timestamp_seconds{camera=\"south\"} 0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWrite_Rates(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var sb strings.Builder
+	Write(&sb, Snapshot{Cameras: []Camera{{ID: "south"}}}, now)
+	if strings.Contains(sb.String(), "per_minute") {
+		t.Errorf("rate gauges written with no camera tracking rates:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	Write(&sb, Snapshot{Cameras: []Camera{
+		{ID: "south"},
+		{ID: "north", Rates: &Rates{ConfiguredCapturesPerMin: 2, CapturesPerMin: 1.5, CapturedBytesPerMin: 300000, UploadsPerMin: 1.25, UploadedBytesPerMin: 250000}},
+	}}, now)
+	out := sb.String()
+	for _, want := range []string{
+		"# TYPE camera_configured_captures_per_minute gauge\n",
+		"camera_configured_captures_per_minute{camera=\"north\"} 2\n",
+		"camera_captures_per_minute{camera=\"north\"} 1.5\n",
+		"camera_captured_bytes_per_minute{camera=\"north\"} 300000\n",
+		"camera_uploads_per_minute{camera=\"north\"} 1.25\n",
+		"camera_uploaded_bytes_per_minute{camera=\"north\"} 250000\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "per_minute{camera=\"south\"}") {
+		t.Errorf("rates written for a camera without them:\n%s", out)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(func() Snapshot { return Snapshot{Version: "dev", Commit: "unknown"} })
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `bridge_build_info{version="dev",commit="unknown"`) {
+		t.Errorf("unexpected body:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,333 @@
+// Package mqtt is a minimal MQTT 3.1.1 client for bridging commands and events:
+// QoS 0 publish and subscribe, a last-will status message and automatic reconnection.
+// It covers what the bridge needs without pulling in a third-party client.
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Defaults
+const (
+	defaultKeepAlive = 60 * time.Second
+	dialTimeout      = 10 * time.Second
+	writeTimeout     = 10 * time.Second
+	defaultRetryMin  = time.Second
+	defaultRetryMax  = time.Minute
+)
+
+// ErrNotConnected is returned by Publish while the broker connection is down
+var ErrNotConnected = errors.New("mqtt: not connected")
+
+// Message is an application message
+type Message struct {
+	Topic   string
+	Payload []byte
+	Retain  bool
+}
+
+// Logger is the logging interface used by Client
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// Config configures a Client
+type Config struct {
+	Broker    string      // host:port
+	TLS       *tls.Config // nil for plain TCP
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration // Default: 60s
+
+	Will  *Message // Published by the broker if the connection is lost
+	Birth *Message // Published after every connect, typically clearing the will
+
+	Subscriptions []string // Topic filters, subscribed at QoS 0 after every connect
+	OnMessage     func(topic string, payload []byte)
+
+	Logger Logger
+}
+
+// Status describes the broker connection for monitoring
+type Status struct {
+	Connected     bool      `json:"connected"`
+	Connects      int64     `json:"connects"`
+	Published     int64     `json:"published"`
+	Dropped       int64     `json:"dropped"` // Publishes skipped while disconnected
+	Received      int64     `json:"received"`
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorTime time.Time `json:"last_error_time,omitempty"`
+}
+
+// Client keeps a connection to an MQTT broker, reconnecting with backoff
+type Client struct {
+	config Config
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	retryMin time.Duration
+	retryMax time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	status Status
+
+	writeMu sync.Mutex // Serializes packets on conn
+}
+
+// New validates cfg and returns a client; call Start to connect
+func New(cfg Config) (*Client, error) {
+	if cfg.Broker == "" {
+		return nil, fmt.Errorf("broker is required")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Broker); err != nil {
+		return nil, fmt.Errorf("broker must be host:port: %w", err)
+	}
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("client id is required")
+	}
+	if cfg.KeepAlive <= 0 {
+		cfg.KeepAlive = defaultKeepAlive
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		config:   cfg,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		retryMin: defaultRetryMin,
+		retryMax: defaultRetryMax,
+	}, nil
+}
+
+// Start connects in the background and keeps the connection up until Stop
+func (c *Client) Start() {
+	go c.run()
+}
+
+// Stop publishes the will message (a clean disconnect suppresses it), disconnects
+// and waits for the connection loop to exit
+func (c *Client) Stop() {
+	if will := c.config.Will; will != nil {
+		_ = c.Publish(will.Topic, will.Payload, will.Retain) // Best effort
+	}
+	c.cancel()
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+	if conn != nil {
+		c.writeMu.Lock()
+		_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		_, _ = conn.Write([]byte{packetDisconnect << 4, 0})
+		c.writeMu.Unlock()
+		conn.Close()
+	}
+	<-c.done
+}
+
+// Status returns connection health and counters
+func (c *Client) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Publish sends a QoS 0 message. Messages are not buffered while disconnected.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	c.mu.Lock()
+	conn := c.conn
+	if conn == nil {
+		c.status.Dropped++
+		c.mu.Unlock()
+		return ErrNotConnected
+	}
+	c.mu.Unlock()
+
+	if err := c.write(conn, encodePublish(topic, payload, retain)); err != nil {
+		c.mu.Lock()
+		c.status.Dropped++
+		c.mu.Unlock()
+		return err
+	}
+	c.mu.Lock()
+	c.status.Published++
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) write(conn net.Conn, packet []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := conn.Write(packet)
+	return err
+}
+
+func (c *Client) run() {
+	defer close(c.done)
+	backoff := c.retryMin
+	for c.ctx.Err() == nil {
+		conn, err := c.connect()
+		if err != nil {
+			c.recordError(err)
+			c.logWarn("MQTT connect failed", "broker", c.config.Broker, "retry_in", backoff, "error", err)
+			select {
+			case <-time.After(backoff):
+			case <-c.ctx.Done():
+				return
+			}
+			backoff = min(backoff*2, c.retryMax)
+			continue
+		}
+		backoff = c.retryMin
+		c.logInfo("MQTT connected", "broker", c.config.Broker)
+
+		err = c.serve(conn)
+		c.mu.Lock()
+		c.conn = nil
+		c.status.Connected = false
+		c.mu.Unlock()
+		conn.Close()
+		if c.ctx.Err() != nil {
+			return
+		}
+		c.recordError(err)
+		c.logWarn("MQTT connection lost", "broker", c.config.Broker, "error", err)
+	}
+}
+
+// connect dials the broker, completes the CONNECT handshake, subscribes and
+// publishes the birth message
+func (c *Client) connect() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	var conn net.Conn
+	var err error
+	if c.config.TLS != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: c.config.TLS}).DialContext(c.ctx, "tcp", c.config.Broker)
+	} else {
+		conn, err = dialer.DialContext(c.ctx, "tcp", c.config.Broker)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.write(conn, encodeConnect(c.config)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("send connect: %w", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(dialTimeout))
+	kind, _, body, err := readPacket(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("read connack: %w", err)
+	}
+	if kind != packetConnack || len(body) != 2 {
+		conn.Close()
+		return nil, fmt.Errorf("expected connack, got packet type %d", kind)
+	}
+	if code := body[1]; code != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("connection refused: %s", connackReason(code))
+	}
+
+	if len(c.config.Subscriptions) > 0 {
+		if err := c.write(conn, encodeSubscribe(1, c.config.Subscriptions)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("subscribe: %w", err)
+		}
+	}
+
+	c.mu.Lock()
+	c.conn = conn
+	c.status.Connected = true
+	c.status.Connects++
+	c.mu.Unlock()
+
+	if birth := c.config.Birth; birth != nil {
+		_ = c.Publish(birth.Topic, birth.Payload, birth.Retain)
+	}
+	return conn, nil
+}
+
+// serve reads packets until the connection fails, pinging to keep it alive
+func (c *Client) serve(conn net.Conn) error {
+	keepAlive := c.config.KeepAlive
+	stopPing := make(chan struct{})
+	defer close(stopPing)
+	go func() {
+		ticker := time.NewTicker(keepAlive / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := c.write(conn, []byte{packetPingreq << 4, 0}); err != nil {
+					conn.Close() // Unblocks the reader
+					return
+				}
+			case <-c.ctx.Done():
+				conn.Close() // Stop raced with connect
+				return
+			case <-stopPing:
+				return
+			}
+		}
+	}()
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(keepAlive * 3 / 2))
+		kind, flags, body, err := readPacket(conn)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case packetPublish:
+			topic, payload, packetID, err := decodePublish(flags, body)
+			if err != nil {
+				return err
+			}
+			if qos := (flags >> 1) & 3; qos == 1 {
+				_ = c.write(conn, []byte{packetPuback << 4, 2, byte(packetID >> 8), byte(packetID)})
+			}
+			c.mu.Lock()
+			c.status.Received++
+			c.mu.Unlock()
+			if c.config.OnMessage != nil {
+				c.config.OnMessage(topic, payload)
+			}
+		case packetSuback:
+			for _, code := range body[min(2, len(body)):] {
+				if code == 0x80 {
+					c.logWarn("MQTT subscription rejected by broker", "broker", c.config.Broker)
+				}
+			}
+		}
+	}
+}
+
+func (c *Client) recordError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.LastError = err.Error()
+	c.status.LastErrorTime = time.Now()
+}
+
+func (c *Client) logInfo(msg string, keysAndValues ...interface{}) {
+	if c.config.Logger != nil {
+		c.config.Logger.Info(msg, keysAndValues...)
+	}
+}
+
+func (c *Client) logWarn(msg string, keysAndValues ...interface{}) {
+	if c.config.Logger != nil {
+		c.config.Logger.Warn(msg, keysAndValues...)
+	}
+}
@@ -0,0 +1,292 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBroker accepts MQTT connections, records CONNECT fields, subscriptions and
+// publishes, and can push messages to the connected client
+type fakeBroker struct {
+	listener   net.Listener
+	refuseCode byte
+
+	mu            sync.Mutex
+	conns         []net.Conn
+	connects      []connectInfo
+	subscriptions []string
+	published     chan Message
+	disconnects   int
+}
+
+type connectInfo struct {
+	clientID, username, password, willTopic string
+	keepAlive                               uint16
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	b := &fakeBroker{listener: listener, published: make(chan Message, 16)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			b.mu.Lock()
+			b.conns = append(b.conns, conn)
+			b.mu.Unlock()
+			go b.serve(conn)
+		}
+	}()
+	t.Cleanup(func() {
+		listener.Close()
+		b.dropAll()
+	})
+	return b
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		kind, flags, body, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+		switch kind {
+		case packetConnect:
+			b.mu.Lock()
+			b.connects = append(b.connects, parseConnect(body))
+			code := b.refuseCode
+			b.mu.Unlock()
+			conn.Write([]byte{packetConnack << 4, 2, 0, code})
+		case packetSubscribe:
+			id := body[:2]
+			for rest := body[2:]; len(rest) > 2; {
+				n := int(binary.BigEndian.Uint16(rest))
+				b.mu.Lock()
+				b.subscriptions = append(b.subscriptions, string(rest[2:2+n]))
+				b.mu.Unlock()
+				rest = rest[3+n:]
+			}
+			conn.Write([]byte{packetSuback << 4, 3, id[0], id[1], 0})
+		case packetPublish:
+			topic, payload, _, _ := decodePublish(flags, body)
+			b.published <- Message{Topic: topic, Payload: payload, Retain: flags&1 == 1}
+		case packetPingreq:
+			conn.Write([]byte{packetPingresp << 4, 0})
+		case packetDisconnect:
+			b.mu.Lock()
+			b.disconnects++
+			b.mu.Unlock()
+			return
+		}
+	}
+}
+
+func parseConnect(body []byte) connectInfo {
+	readString := func() string {
+		n := int(binary.BigEndian.Uint16(body))
+		s := string(body[2 : 2+n])
+		body = body[2+n:]
+		return s
+	}
+	readString() // Protocol name
+	flags := body[1]
+	info := connectInfo{keepAlive: binary.BigEndian.Uint16(body[2:])}
+	body = body[4:]
+	info.clientID = readString()
+	if flags&0x04 != 0 {
+		info.willTopic = readString()
+		readString() // Will payload
+	}
+	if flags&0x80 != 0 {
+		info.username = readString()
+	}
+	if flags&0x40 != 0 {
+		info.password = readString()
+	}
+	return info
+}
+
+// push sends a QoS 1 message to every connected client
+func (b *fakeBroker) push(topic, payload string) {
+	body := appendString(nil, topic)
+	body = append(body, 0, 7) // Packet ID
+	body = append(body, payload...)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.conns {
+		c.Write(packet(packetPublish<<4|0x02, body))
+	}
+}
+
+func (b *fakeBroker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range b.conns {
+		c.Close()
+	}
+	b.conns = nil
+}
+
+func (b *fakeBroker) expectPublish(t *testing.T) Message {
+	t.Helper()
+	select {
+	case m := <-b.published:
+		return m
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for publish")
+		return Message{}
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestClient(t *testing.T, broker *fakeBroker, onMessage func(string, []byte)) *Client {
+	t.Helper()
+	c, err := New(Config{
+		Broker:        broker.listener.Addr().String(),
+		ClientID:      "bridge-test",
+		Username:      "bridge",
+		Password:      "secret",
+		KeepAlive:     30 * time.Second,
+		Will:          &Message{Topic: "awx/status", Payload: []byte("offline"), Retain: true},
+		Birth:         &Message{Topic: "awx/status", Payload: []byte("online"), Retain: true},
+		Subscriptions: []string{"awx/camera/+/capture"},
+		OnMessage:     onMessage,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c.retryMin, c.retryMax = 10*time.Millisecond, 20*time.Millisecond
+	return c
+}
+
+func TestClient_ConnectSubscribePublish(t *testing.T) {
+	broker := newFakeBroker(t)
+	received := make(chan string, 1)
+	c := newTestClient(t, broker, func(topic string, payload []byte) {
+		received <- topic + " " + string(payload)
+	})
+	c.Start()
+
+	if m := broker.expectPublish(t); m.Topic != "awx/status" || string(m.Payload) != "online" || !m.Retain {
+		t.Errorf("birth message = %+v", m)
+	}
+	broker.mu.Lock()
+	info, subs := broker.connects[0], broker.subscriptions
+	broker.mu.Unlock()
+	if info.clientID != "bridge-test" || info.username != "bridge" || info.password != "secret" || info.willTopic != "awx/status" || info.keepAlive != 30 {
+		t.Errorf("connect = %+v", info)
+	}
+	if len(subs) != 1 || subs[0] != "awx/camera/+/capture" {
+		t.Errorf("subscriptions = %v", subs)
+	}
+
+	broker.push("awx/camera/north/capture", "now")
+	select {
+	case got := <-received:
+		if got != "awx/camera/north/capture now" {
+			t.Errorf("received %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("command not delivered")
+	}
+
+	if err := c.Publish("awx/camera/north/event", []byte(`{"type":"capture_done"}`), false); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if m := broker.expectPublish(t); m.Topic != "awx/camera/north/event" {
+		t.Errorf("event topic = %q", m.Topic)
+	}
+
+	c.Stop()
+	if m := broker.expectPublish(t); string(m.Payload) != "offline" {
+		t.Errorf("stop should publish the will payload, got %q", m.Payload)
+	}
+	waitFor(t, "disconnect", func() bool {
+		broker.mu.Lock()
+		defer broker.mu.Unlock()
+		return broker.disconnects == 1
+	})
+	if status := c.Status(); status.Connected || status.Published != 3 || status.Received != 1 {
+		t.Errorf("status after stop = %+v", status)
+	}
+}
+
+func TestClient_ReconnectsAndResubscribes(t *testing.T) {
+	broker := newFakeBroker(t)
+	c := newTestClient(t, broker, nil)
+	c.Start()
+	defer c.Stop()
+	broker.expectPublish(t)
+
+	broker.dropAll()
+	broker.expectPublish(t) // Birth again after reconnecting
+
+	status := c.Status()
+	if !status.Connected || status.Connects != 2 || status.LastError == "" {
+		t.Errorf("status after reconnect = %+v", status)
+	}
+	broker.mu.Lock()
+	defer broker.mu.Unlock()
+	if len(broker.subscriptions) != 2 {
+		t.Errorf("expected resubscribe, subscriptions = %v", broker.subscriptions)
+	}
+}
+
+func TestClient_ConnectRefused(t *testing.T) {
+	broker := newFakeBroker(t)
+	broker.refuseCode = 5
+	c := newTestClient(t, broker, nil)
+	c.Start()
+	defer c.Stop()
+
+	waitFor(t, "refusal", func() bool { return strings.Contains(c.Status().LastError, "not authorized") })
+	if err := c.Publish("awx/x", nil, false); err != ErrNotConnected {
+		t.Errorf("Publish while disconnected = %v, want ErrNotConnected", err)
+	}
+	if c.Status().Dropped != 1 {
+		t.Errorf("dropped = %d, want 1", c.Status().Dropped)
+	}
+}
+
+func TestNew_ConfigErrors(t *testing.T) {
+	for _, cfg := range []Config{
+		{ClientID: "x"},
+		{Broker: "broker.local", ClientID: "x"},
+		{Broker: "broker.local:1883"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestRemainingLength(t *testing.T) {
+	for _, n := range []int{0, 127, 128, 16383, 16384, 200000} {
+		p := packet(packetPublish<<4, make([]byte, n))
+		kind, _, body, err := readPacket(strings.NewReader(string(p)))
+		if err != nil || kind != packetPublish || len(body) != n {
+			t.Errorf("length %d: kind=%d len=%d err=%v", n, kind, len(body), err)
+		}
+	}
+}
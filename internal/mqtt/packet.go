@@ -0,0 +1,182 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Control packet types (MQTT 3.1.1 section 2.2.1)
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetSubscribe  = 8
+	packetSuback     = 9
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// maxPacketSize bounds incoming packets; commands and acks are tiny
+const maxPacketSize = 1 << 20
+
+// encodeConnect builds a CONNECT packet with a clean session
+func encodeConnect(cfg Config) []byte {
+	flags := byte(0x02) // Clean session
+	var payload []byte
+	payload = appendString(payload, cfg.ClientID)
+	if will := cfg.Will; will != nil {
+		flags |= 0x04
+		if will.Retain {
+			flags |= 0x20
+		}
+		payload = appendString(payload, will.Topic)
+		payload = appendBytes(payload, will.Payload)
+	}
+	if cfg.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, cfg.Username)
+		if cfg.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, cfg.Password)
+		}
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags) // Protocol level 4 = 3.1.1
+	body = binary.BigEndian.AppendUint16(body, uint16(cfg.KeepAlive.Seconds()))
+	body = append(body, payload...)
+	return packet(packetConnect<<4, body)
+}
+
+// encodeSubscribe builds a SUBSCRIBE packet requesting QoS 0 for each filter
+func encodeSubscribe(packetID uint16, filters []string) []byte {
+	body := binary.BigEndian.AppendUint16(nil, packetID)
+	for _, f := range filters {
+		body = appendString(body, f)
+		body = append(body, 0)
+	}
+	return packet(packetSubscribe<<4|0x02, body)
+}
+
+// encodePublish builds a QoS 0 PUBLISH packet
+func encodePublish(topic string, payload []byte, retain bool) []byte {
+	header := byte(packetPublish << 4)
+	if retain {
+		header |= 0x01
+	}
+	return packet(header, append(appendString(nil, topic), payload...))
+}
+
+// decodePublish splits a PUBLISH body into topic, payload and (for QoS > 0) packet ID
+func decodePublish(flags byte, body []byte) (topic string, payload []byte, packetID uint16, err error) {
+	if len(body) < 2 {
+		return "", nil, 0, errors.New("short publish")
+	}
+	n := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+n {
+		return "", nil, 0, errors.New("short publish topic")
+	}
+	topic, rest := string(body[2:2+n]), body[2+n:]
+	if (flags>>1)&3 > 0 {
+		if len(rest) < 2 {
+			return "", nil, 0, errors.New("short publish packet id")
+		}
+		packetID, rest = binary.BigEndian.Uint16(rest), rest[2:]
+	}
+	return topic, rest, packetID, nil
+}
+
+// readPacket reads one control packet, returning its type, header flags and body
+func readPacket(r io.Reader) (kind, flags byte, body []byte, err error) {
+	br := byteReader{r}
+	header, err := br.ReadByte()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	length, err := readRemainingLength(br)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if length > maxPacketSize {
+		return 0, 0, nil, fmt.Errorf("packet of %d bytes exceeds limit", length)
+	}
+	body = make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, 0, nil, err
+	}
+	return header >> 4, header & 0x0F, body, nil
+}
+
+// byteReader reads single bytes without buffering past the current packet
+type byteReader struct{ r io.Reader }
+
+func (b byteReader) ReadByte() (byte, error) {
+	var buf [1]byte
+	_, err := io.ReadFull(b.r, buf[:])
+	return buf[0], err
+}
+
+func readRemainingLength(br io.ByteReader) (int, error) {
+	length, shift := 0, 0
+	for i := 0; i < 4; i++ {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length |= int(b&0x7F) << shift
+		if b&0x80 == 0 {
+			return length, nil
+		}
+		shift += 7
+	}
+	return 0, errors.New("malformed remaining length")
+}
+
+// packet prefixes body with the fixed header
+func packet(header byte, body []byte) []byte {
+	out := []byte{header}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+func appendString(b []byte, s string) []byte {
+	return appendBytes(b, []byte(s))
+}
+
+func appendBytes(b, data []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
+
+// connackReason describes a CONNACK return code
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client id rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad username or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CompactionResult describes one compaction pass over a queue directory
+type CompactionResult struct {
+	Time           time.Time `json:"time"`
+	OrphansRemoved int       `json:"orphans_removed"`
+	OrphanBytes    int64     `json:"orphan_bytes"`
+	CountDrift     int       `json:"count_drift"`      // Files on disk minus tracked count, before correction
+	SizeDriftBytes int64     `json:"size_drift_bytes"` // Bytes on disk minus tracked size, before correction
+}
+
+// isOrphanCandidate reports whether name is a partial write that a crash could leave behind
+func isOrphanCandidate(name string) bool {
+	if strings.HasSuffix(name, ".tmp") || strings.HasSuffix(name, ".uploading") {
+		return true
+	}
+	matched, _ := filepath.Match(spoolFilePattern, name)
+	return matched
+}
+
+// Compact removes partial-write leftovers older than orphanAge and reconciles the
+// tracked image count and size against the files actually on disk.
+// Younger leftovers are kept since they may belong to a write still in progress.
+func (q *Queue) Compact(orphanAge time.Duration) CompactionResult {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := CompactionResult{Time: time.Now()}
+
+	entries, err := os.ReadDir(q.state.Directory)
+	if err != nil {
+		q.logger.Warn("Queue compaction failed",
+			"camera", q.state.CameraID,
+			"error", err)
+		return result
+	}
+
+	cutoff := time.Now().Add(-orphanAge)
+	for _, entry := range entries {
+		if entry.IsDir() || !isOrphanCandidate(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(q.state.Directory, entry.Name())); err != nil {
+			continue
+		}
+		result.OrphansRemoved++
+		result.OrphanBytes += info.Size()
+	}
+
+	countDrift, sizeDrift, err := q.reconcileLocked()
+	if err != nil {
+		q.logger.Warn("Queue compaction failed",
+			"camera", q.state.CameraID,
+			"error", err)
+		return result
+	}
+	result.CountDrift = countDrift
+	result.SizeDriftBytes = sizeDrift
+
+	if result.OrphansRemoved > 0 {
+		q.logger.Info("Removed orphaned partial files",
+			"camera", q.state.CameraID,
+			"count", result.OrphansRemoved,
+			"bytes", result.OrphanBytes)
+	}
+
+	q.lastCompaction = &result
+	return result
+}
+
+// CompactAll runs a compaction pass on every queue
+func (m *Manager) CompactAll(orphanAge time.Duration) []CompactionResult {
+	results := make([]CompactionResult, 0)
+	for _, q := range m.GetAllQueues() {
+		results = append(results, q.Compact(orphanAge))
+	}
+	return results
+}
+
+// StartCompactionWorker periodically sweeps orphaned partial files and reconciles queue state
+func (m *Manager) StartCompactionWorker(ctx context.Context, interval, orphanAge time.Duration) {
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.logger.Info("Compaction worker started",
+		"interval", interval.String(),
+		"orphan_age", orphanAge.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Compaction worker stopped")
+			return
+		case <-ticker.C:
+			m.CompactAll(orphanAge)
+		}
+	}
+}
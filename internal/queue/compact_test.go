@@ -0,0 +1,90 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueue_CompactRemovesOldOrphans(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	old := time.Now().Add(-time.Hour)
+	orphans := []string{"1735142730000.jpg.tmp", "1735142731000.jpg.uploading", "spool-42.jpg"}
+	for _, name := range orphans {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("partial"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fresh := filepath.Join(dir, "1735142732000.jpg.tmp")
+	if err := os.WriteFile(fresh, []byte("in progress"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := q.Compact(10 * time.Minute)
+
+	if result.OrphansRemoved != len(orphans) {
+		t.Errorf("OrphansRemoved = %d, want %d", result.OrphansRemoved, len(orphans))
+	}
+	if result.OrphanBytes != int64(len(orphans)*len("partial")) {
+		t.Errorf("OrphanBytes = %d, want %d", result.OrphanBytes, len(orphans)*len("partial"))
+	}
+	for _, name := range orphans {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+	if _, err := os.Stat(fresh); err != nil {
+		t.Error("recent partial file should be kept")
+	}
+	if q.GetStats().LastCompaction == nil {
+		t.Error("expected LastCompaction in stats")
+	}
+}
+
+func TestQueue_CompactCorrectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(createTestJPEG(1024), now.Add(time.Duration(-i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	// Remove a file behind the queue's back
+	images, _ := q.Peek(1)
+	if err := os.Remove(images[0].FilePath); err != nil {
+		t.Fatal(err)
+	}
+
+	result := q.Compact(10 * time.Minute)
+
+	if result.CountDrift != -1 {
+		t.Errorf("CountDrift = %d, want -1", result.CountDrift)
+	}
+	if result.SizeDriftBytes != -images[0].SizeBytes {
+		t.Errorf("SizeDriftBytes = %d, want %d", result.SizeDriftBytes, -images[0].SizeBytes)
+	}
+	if q.GetImageCount() != 2 {
+		t.Errorf("ImageCount = %d, want 2 after correction", q.GetImageCount())
+	}
+
+	// A second pass finds nothing to correct
+	if again := q.Compact(10 * time.Minute); again.CountDrift != 0 || again.SizeDriftBytes != 0 {
+		t.Errorf("expected no drift on second pass, got %+v", again)
+	}
+}
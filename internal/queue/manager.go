@@ -54,6 +54,9 @@ func (m *Manager) CreateQueue(cameraID string, config QueueConfig) (*Queue, erro
 	if err != nil {
 		return nil, fmt.Errorf("create queue: %w", err)
 	}
+	if m.globalConfig.FallbackPath != "" {
+		queue.SetFallbackDirectory(filepath.Join(m.globalConfig.FallbackPath, cameraID))
+	}
 
 	m.queues[cameraID] = queue
 	m.logger.Info("Queue created",
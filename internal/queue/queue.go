@@ -45,7 +45,24 @@ func NewQueue(cameraID, directory string, config QueueConfig, logger Logger) (*Q
 func (q *Queue) scanDirectory() error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+	return q.scanDirectoryLocked()
+}
+
+// scanDirectoryLocked is scanDirectory for callers holding the lock
+func (q *Queue) scanDirectoryLocked() error {
+	// Spool files left behind by a crash mid-capture are incomplete
+	if stale, _ := filepath.Glob(filepath.Join(q.state.Directory, spoolFilePattern)); len(stale) > 0 {
+		for _, path := range stale {
+			_ = os.Remove(path)
+		}
+		q.logger.Info("Removed stale spool files",
+			"camera", q.state.CameraID,
+			"count", len(stale))
+	}
 
+	if info, err := os.Stat(q.state.Directory); err == nil {
+		q.scannedModTime = info.ModTime()
+	}
 	files, err := q.listFilesSortedLocked()
 	if err != nil {
 		return err
@@ -84,24 +101,15 @@ func (q *Queue) Enqueue(imageData []byte, observationTime time.Time, timeSource,
 		return ErrCapturePaused
 	}
 
-	// Validate image data
-	if len(imageData) < 100 {
-		return ErrInvalidImage
-	}
-
-	// Validate observation time
-	now := time.Now().UTC()
-	if observationTime.After(now.Add(5 * time.Second)) {
-		return ErrImageFromFuture
+	imageSize := int64(len(imageData))
+	if err := q.validateEnqueueLocked(imageSize, observationTime); err != nil {
+		return err
 	}
-
-	maxAge := time.Duration(q.config.MaxAgeSeconds) * time.Second
-	if now.Sub(observationTime) > maxAge {
-		return ErrImageExpired
+	observationTime, err := q.checkRegressionLocked(observationTime)
+	if err != nil {
+		return err
 	}
 
-	imageSize := int64(len(imageData))
-
 	// Pre-check: ensure we have space before attempting write
 	// This prevents "no space" errors which are harder to recover from
 	if !q.hasSpaceForImageLocked(imageSize) {
@@ -111,22 +119,16 @@ func (q *Queue) Enqueue(imageData []byte, observationTime time.Time, timeSource,
 		q.emergencyThinForSpaceLocked(imageSize)
 	}
 
-	// Generate filename from observation time (milliseconds for uniqueness)
-	filename := fmt.Sprintf("%d.jpg", observationTime.UnixMilli())
-	filePath := filepath.Join(q.state.Directory, filename)
-
-	// Handle duplicate timestamp (add 1ms)
-	for {
-		if _, err := os.Stat(filePath); os.IsNotExist(err) {
-			break
-		}
-		observationTime = observationTime.Add(time.Millisecond)
-		filename = fmt.Sprintf("%d.jpg", observationTime.UnixMilli())
-		filePath = filepath.Join(q.state.Directory, filename)
-	}
+	filename, filePath, observationTime := q.uniqueFilePathLocked(observationTime)
 
 	// Attempt to write file (with retry on space error)
 	written, err := q.writeImageWithRetry(filePath, imageData)
+	if isReadOnlyError(err) {
+		if err = q.handleReadOnlyLocked(err); err == nil {
+			filename, filePath, observationTime = q.uniqueFilePathLocked(observationTime)
+			written, err = q.writeImageWithRetry(filePath, imageData)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -143,9 +145,106 @@ func (q *Queue) Enqueue(imageData []byte, observationTime time.Time, timeSource,
 		return ErrQueueFull
 	}
 
-	// Update state
+	q.recordWritableLocked()
+	q.recordEnqueuedLocked(filename, imageSize, observationTime)
+	return nil
+}
+
+// CreateSpoolFile creates a temporary file in the queue directory for streaming a
+// large capture straight to disk. It is ignored by Dequeue until passed to EnqueueFile.
+func (q *Queue) CreateSpoolFile() (*os.File, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	f, err := os.CreateTemp(q.state.Directory, spoolFilePattern)
+	if isReadOnlyError(err) {
+		if err = q.handleReadOnlyLocked(err); err == nil {
+			f, err = os.CreateTemp(q.state.Directory, spoolFilePattern)
+		}
+	}
+	return f, err
+}
+
+// EnqueueFile adds an image already written to disk (see CreateSpoolFile) by renaming
+// it into place. The file is removed if it cannot be enqueued.
+func (q *Queue) EnqueueFile(path string, observationTime time.Time, timeSource, timeConfidence string) (err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	defer func() {
+		if err != nil {
+			_ = os.Remove(path) // Best effort cleanup
+		}
+	}()
+
+	if q.state.CapturePaused {
+		return ErrCapturePaused
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat spool file: %w", err)
+	}
+	if err := q.validateEnqueueLocked(info.Size(), observationTime); err != nil {
+		return err
+	}
+	if observationTime, err = q.checkRegressionLocked(observationTime); err != nil {
+		return err
+	}
+
+	// Same pre-check as Enqueue: the spool file already holds its space, but a
+	// large frame must not leave the next write without room
+	if !q.hasSpaceForImageLocked(info.Size()) {
+		q.logger.Warn("Low space detected, triggering preemptive cleanup",
+			"camera", q.state.CameraID,
+			"image_size", info.Size())
+		q.emergencyThinForSpaceLocked(info.Size())
+	}
+
+	filename, filePath, observationTime := q.uniqueFilePathLocked(observationTime)
+	if err := os.Rename(path, filePath); err != nil {
+		return fmt.Errorf("rename to final: %w", err)
+	}
+
+	q.recordWritableLocked()
+	q.recordEnqueuedLocked(filename, info.Size(), observationTime)
+	return nil
+}
+
+// validateEnqueueLocked checks image size and observation time (must hold lock)
+func (q *Queue) validateEnqueueLocked(sizeBytes int64, observationTime time.Time) error {
+	if sizeBytes < 100 {
+		return ErrInvalidImage
+	}
+
+	now := time.Now().UTC()
+	if observationTime.After(now.Add(5 * time.Second)) {
+		return ErrImageFromFuture
+	}
+
+	maxAge := time.Duration(q.config.MaxAgeSeconds) * time.Second
+	if now.Sub(observationTime) > maxAge {
+		return ErrImageExpired
+	}
+	return nil
+}
+
+// uniqueFilePathLocked generates a filename from observation time (milliseconds),
+// bumping by 1ms until it does not collide with an existing file (must hold lock)
+func (q *Queue) uniqueFilePathLocked(observationTime time.Time) (string, string, time.Time) {
+	for {
+		filename := fmt.Sprintf("%d.jpg", observationTime.UnixMilli())
+		filePath := filepath.Join(q.state.Directory, filename)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return filename, filePath, observationTime
+		}
+		observationTime = observationTime.Add(time.Millisecond)
+	}
+}
+
+// recordEnqueuedLocked updates state after a file lands in the queue (must hold lock)
+func (q *Queue) recordEnqueuedLocked(filename string, sizeBytes int64, observationTime time.Time) {
 	q.state.ImageCount++
-	q.state.TotalSizeBytes += imageSize
+	q.state.TotalSizeBytes += sizeBytes
 	q.state.ImagesQueued++
 
 	if observationTime.After(q.state.NewestTimestamp) {
@@ -166,8 +265,6 @@ func (q *Queue) Enqueue(imageData []byte, observationTime time.Time, timeSource,
 		"camera", q.state.CameraID,
 		"filename", filename,
 		"queue_size", q.state.ImageCount)
-
-	return nil
 }
 
 // writeImageWithRetry attempts to write an image, retrying once after cleanup if space error
@@ -424,6 +521,28 @@ func (q *Queue) MarkUploaded(img *QueuedImage) error {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 
+	if err := q.removeImageLocked(img); err != nil {
+		return fmt.Errorf("remove uploaded file: %w", err)
+	}
+	q.state.ImagesUploaded++
+	return nil
+}
+
+// MarkAbandoned removes an image the uploader gave up on (e.g. one the server keeps
+// rejecting) so newer frames are not stuck behind it
+func (q *Queue) MarkAbandoned(img *QueuedImage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := q.removeImageLocked(img); err != nil {
+		return fmt.Errorf("remove abandoned file: %w", err)
+	}
+	q.state.ImagesAbandoned++
+	return nil
+}
+
+// removeImageLocked deletes an image file and updates queue state (caller must hold lock)
+func (q *Queue) removeImageLocked(img *QueuedImage) error {
 	if err := os.Remove(img.FilePath); err != nil {
 		if os.IsNotExist(err) {
 			// Already removed, update state anyway
@@ -431,7 +550,7 @@ func (q *Queue) MarkUploaded(img *QueuedImage) error {
 				"camera", q.state.CameraID,
 				"filename", img.Filename)
 		} else {
-			return fmt.Errorf("remove uploaded file: %w", err)
+			return err
 		}
 	}
 
@@ -443,23 +562,13 @@ func (q *Queue) MarkUploaded(img *QueuedImage) error {
 	if q.state.TotalSizeBytes < 0 {
 		q.state.TotalSizeBytes = 0
 	}
-	q.state.ImagesUploaded++
 
 	// Recalculate oldest timestamp
 	q.recalculateOldestLocked()
 
 	// Check if we can resume capture
 	q.updateHealthLevelLocked()
-	if q.state.CapturePaused && q.canResumeCaptureLocked() {
-		q.state.CapturePaused = false
-		select {
-		case q.resumeCapture <- struct{}{}:
-		default:
-		}
-		q.logger.Info("Capture resumed",
-			"camera", q.state.CameraID,
-			"queue_size", q.state.ImageCount)
-	}
+	q.resumeCaptureIfReadyLocked()
 
 	return nil
 }
@@ -497,6 +606,7 @@ func (q *Queue) ExpireOldImages() int {
 	if expired > 0 {
 		q.recalculateOldestLocked()
 		q.updateHealthLevelLocked()
+		q.resumeCaptureIfReadyLocked()
 		q.logger.Info("Expired old images",
 			"camera", q.state.CameraID,
 			"expired", expired,
@@ -596,6 +706,8 @@ func (q *Queue) thinQueue() {
 	if removed > 0 {
 		q.recalculateOldestLocked()
 		q.updateHealthLevelLocked()
+		// Uploads may be stalled (offline, quiet hours), so thinning must be able to resume capture
+		q.resumeCaptureIfReadyLocked()
 		q.logger.Info("Queue thinned",
 			"camera", q.state.CameraID,
 			"removed", removed,
@@ -650,6 +762,77 @@ func (q *Queue) EmergencyThin(keepRatio float64) int {
 	return removed
 }
 
+// DropOlderThan removes every image older than img, for cameras that only care
+// about the newest frame. Images for which keep returns true (e.g. ones being
+// uploaded) are left alone. Removals count as thinned. Returns the number removed.
+func (q *Queue) DropOlderThan(img *QueuedImage, keep func(path string) bool) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	files, err := q.listFilesSortedLocked()
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, file := range files {
+		if !parseTimestampFromFilename(file.Name()).Before(img.Timestamp) {
+			break // Sorted oldest first
+		}
+		filePath := filepath.Join(q.state.Directory, file.Name())
+		if keep != nil && keep(filePath) {
+			continue
+		}
+		if err := os.Remove(filePath); err == nil {
+			q.state.ImageCount--
+			q.state.TotalSizeBytes -= file.Size()
+			q.state.ImagesThinned++
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		q.recalculateOldestLocked()
+		q.updateHealthLevelLocked()
+		q.resumeCaptureIfReadyLocked()
+	}
+	return removed
+}
+
+// RemoveAlreadyUploaded deletes frames captured at or after since whose timestamp
+// (Unix milliseconds) is in uploaded, e.g. frames the server received just before an
+// unclean shutdown, and returns how many were removed
+func (q *Queue) RemoveAlreadyUploaded(since time.Time, uploaded map[int64]bool) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	files, err := q.listFilesSortedLocked()
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, file := range files {
+		ts := parseTimestampFromFilename(file.Name())
+		if ts.Before(since) || !uploaded[ts.UnixMilli()] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(q.state.Directory, file.Name())); err == nil {
+			q.state.ImageCount--
+			q.state.TotalSizeBytes -= file.Size()
+			q.state.AlreadyUploaded++
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		q.recalculateOldestLocked()
+		q.updateHealthLevelLocked()
+		q.resumeCaptureIfReadyLocked()
+	}
+	return removed
+}
+
 // GetHealthLevel returns the current health level
 func (q *Queue) GetHealthLevel() HealthLevel {
 	q.mu.RLock()
@@ -701,6 +884,16 @@ func (q *Queue) GetStats() QueueStats {
 		ImagesUploaded:  q.state.ImagesUploaded,
 		ImagesThinned:   q.state.ImagesThinned,
 		ImagesExpired:   q.state.ImagesExpired,
+		ImagesAbandoned: q.state.ImagesAbandoned,
+		LastCompaction:  q.lastCompaction,
+
+		FilesystemReadOnly: !q.state.ReadOnlySince.IsZero(),
+		ReadOnlySince:      q.state.ReadOnlySince,
+		FallbackDirectory:  q.fallbackDirectoryLocked(),
+
+		TimestampRegressions: q.state.TimestampRegressions,
+		DriftCorrections:     q.state.DriftCorrections,
+		AlreadyUploaded:      q.state.AlreadyUploaded,
 	}
 }
 
@@ -766,16 +959,6 @@ func (q *Queue) updateHealthLevelLocked() {
 	switch {
 	case capacityPct >= q.config.ThresholdCritical:
 		q.state.HealthLevel = HealthCritical
-		if q.config.PauseCaptureOnCritical && !q.state.CapturePaused {
-			q.state.CapturePaused = true
-			select {
-			case q.pauseCapture <- struct{}{}:
-			default:
-			}
-			q.logger.Warn("Queue critical - pausing capture",
-				"camera", q.state.CameraID,
-				"capacity_percent", capacityPct*100)
-		}
 
 	case capacityPct >= q.config.ThresholdDegraded:
 		q.state.HealthLevel = HealthDegraded
@@ -787,6 +970,22 @@ func (q *Queue) updateHealthLevelLocked() {
 		q.state.HealthLevel = HealthHealthy
 	}
 
+	pauseAt := q.config.ThresholdCritical
+	if q.config.PauseThreshold > 0 {
+		pauseAt = q.config.PauseThreshold
+	}
+	if capacityPct >= pauseAt && q.config.PauseCaptureOnCritical && !q.state.CapturePaused {
+		q.state.CapturePaused = true
+		select {
+		case q.pauseCapture <- struct{}{}:
+		default:
+		}
+		q.logger.Warn("Queue nearly full - pausing capture",
+			"camera", q.state.CameraID,
+			"health", q.state.HealthLevel.String(),
+			"capacity_percent", capacityPct*100)
+	}
+
 	if q.state.HealthLevel != prevLevel {
 		q.logger.Info("Queue health changed",
 			"camera", q.state.CameraID,
@@ -821,6 +1020,22 @@ func (q *Queue) canResumeCaptureLocked() bool {
 	return capacityPct < q.config.ResumeThreshold
 }
 
+// resumeCaptureIfReadyLocked lifts a critical pause once the queue has drained below
+// the resume threshold (must hold lock)
+func (q *Queue) resumeCaptureIfReadyLocked() {
+	if !q.state.CapturePaused || !q.canResumeCaptureLocked() {
+		return
+	}
+	q.state.CapturePaused = false
+	select {
+	case q.resumeCapture <- struct{}{}:
+	default:
+	}
+	q.logger.Info("Capture resumed",
+		"camera", q.state.CameraID,
+		"queue_size", q.state.ImageCount)
+}
+
 func (q *Queue) recalculateOldestLocked() {
 	files, err := q.listFilesSortedLocked()
 	if err != nil || len(files) == 0 {
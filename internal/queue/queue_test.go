@@ -2,6 +2,7 @@ package queue
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -282,6 +283,34 @@ func TestQueue_EmergencyThin(t *testing.T) {
 	}
 }
 
+func TestQueue_DropOlderThan(t *testing.T) {
+	q, err := NewQueue("test-camera", t.TempDir(), DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	base := time.Now().UTC().Truncate(time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(createTestJPEG(1024), base.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	all, _ := q.Peek(5)
+	newest := all[3]
+
+	// The oldest frame is in flight and must survive
+	removed := q.DropOlderThan(newest, func(path string) bool { return path == all[0].FilePath })
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	left, _ := q.Peek(5)
+	if len(left) != 3 || left[0].Filename != all[0].Filename || left[1].Filename != newest.Filename {
+		t.Errorf("remaining = %v", left)
+	}
+	if state := q.GetState(); state.ImageCount != 3 || state.ImagesThinned != 2 {
+		t.Errorf("state count=%d thinned=%d", state.ImageCount, state.ImagesThinned)
+	}
+}
+
 func TestQueue_CapturePause(t *testing.T) {
 	dir := t.TempDir()
 	config := DefaultQueueConfig()
@@ -327,6 +356,85 @@ func TestQueue_CapturePause(t *testing.T) {
 	}
 }
 
+func TestQueue_ThinningResumesCapture(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultQueueConfig()
+	config.MaxFiles = 10
+	config.ThresholdCritical = 0.9
+	config.ProtectNewest = 1
+	config.ProtectOldest = 1
+
+	q, err := NewQueue("test-camera", dir, config, nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	// Fill to critical with no uploads draining the queue
+	imageData := createTestJPEG(1024)
+	for i := 0; i < 9; i++ {
+		ts := time.Now().UTC().Add(time.Duration(i) * time.Millisecond)
+		if err := q.Enqueue(imageData, ts, "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue %d failed: %v", i, err)
+		}
+	}
+	if !q.IsCapturePaused() {
+		t.Fatal("expected capture to be paused at critical level")
+	}
+
+	q.thinQueue()
+
+	if q.GetImageCount() >= 9 {
+		t.Fatalf("expected thinning to remove images, count = %d", q.GetImageCount())
+	}
+	if q.IsCapturePaused() {
+		t.Error("expected capture to resume once thinning drops below the resume threshold")
+	}
+}
+
+func TestQueue_PauseThreshold(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultQueueConfig()
+	config.MaxFiles = 10
+	config.ThinningEnabled = false
+	config.PauseThreshold = 0.6
+	config.ResumeThreshold = 0.5
+
+	q, err := NewQueue("test-camera", dir, config, nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	// Pauses at 60%, well before critical, and nothing is thinned
+	imageData := createTestJPEG(1024)
+	for i := 0; i < 6; i++ {
+		ts := time.Now().UTC().Add(time.Duration(i) * time.Millisecond)
+		if err := q.Enqueue(imageData, ts, "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue %d failed: %v", i, err)
+		}
+	}
+	if !q.IsCapturePaused() || q.GetStats().HealthLevel == HealthCritical.String() {
+		t.Fatalf("paused = %v at %s, want paused before critical", q.IsCapturePaused(), q.GetStats().HealthLevel)
+	}
+	q.thinQueue()
+	if q.GetImageCount() != 6 {
+		t.Errorf("thinned to %d images, want all 6 kept", q.GetImageCount())
+	}
+
+	// Uploads drain it below the resume threshold
+	for i := 0; i < 2; i++ {
+		img, err := q.Dequeue()
+		if err != nil {
+			t.Fatalf("Dequeue failed: %v", err)
+		}
+		if err := q.MarkUploaded(img); err != nil {
+			t.Fatalf("MarkUploaded failed: %v", err)
+		}
+		if paused := q.IsCapturePaused(); paused != (i == 0) {
+			t.Errorf("after %d uploads paused = %v", i+1, paused)
+		}
+	}
+}
+
 func TestQueue_Peek(t *testing.T) {
 	dir := t.TempDir()
 	config := DefaultQueueConfig()
@@ -545,3 +653,79 @@ func BenchmarkQueue_Dequeue(b *testing.B) {
 		_, _ = q.Dequeue()
 	}
 }
+
+func TestQueue_EnqueueFile(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	f, err := q.CreateSpoolFile()
+	if err != nil {
+		t.Fatalf("CreateSpoolFile failed: %v", err)
+	}
+	imageData := createTestJPEG(2048)
+	if _, err := f.Write(imageData); err != nil {
+		t.Fatalf("write spool file: %v", err)
+	}
+	f.Close()
+
+	// Spool files must not be visible to Dequeue before EnqueueFile
+	if _, err := q.Dequeue(); err != ErrQueueEmpty {
+		t.Errorf("Dequeue before EnqueueFile: got %v, want ErrQueueEmpty", err)
+	}
+
+	if err := q.EnqueueFile(f.Name(), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("EnqueueFile failed: %v", err)
+	}
+
+	img, err := q.Dequeue()
+	if err != nil {
+		t.Fatalf("Dequeue failed: %v", err)
+	}
+	if img.SizeBytes != int64(len(imageData)) {
+		t.Errorf("SizeBytes = %d, want %d", img.SizeBytes, len(imageData))
+	}
+	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+		t.Error("spool file should have been renamed into place")
+	}
+}
+
+func TestQueue_EnqueueFile_RemovesRejected(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	f, _ := q.CreateSpoolFile()
+	f.Write([]byte("too small"))
+	f.Close()
+
+	if err := q.EnqueueFile(f.Name(), time.Now().UTC(), "bridge_clock", "high"); err != ErrInvalidImage {
+		t.Errorf("EnqueueFile: got %v, want ErrInvalidImage", err)
+	}
+	if _, err := os.Stat(f.Name()); !os.IsNotExist(err) {
+		t.Error("rejected spool file should be removed")
+	}
+}
+
+func TestNewQueue_RemovesStaleSpoolFiles(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "spool-123.jpg")
+	if err := os.WriteFile(stale, createTestJPEG(1024), 0644); err != nil {
+		t.Fatalf("write stale spool: %v", err)
+	}
+
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("stale spool file should be removed on startup")
+	}
+	if q.GetImageCount() != 0 {
+		t.Errorf("ImageCount = %d, want 0", q.GetImageCount())
+	}
+}
@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// isReadOnlyError reports whether err is a write refused by a read-only filesystem,
+// e.g. after a filesystem error or a tmpfs remounted read-only
+func isReadOnlyError(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
+
+// SetFallbackDirectory sets where the queue moves if its filesystem turns read-only.
+// "" = no fallback: enqueues fail with ErrReadOnlyFilesystem until it is writable again
+func (q *Queue) SetFallbackDirectory(dir string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.fallbackDir = dir
+}
+
+// StorageStatus returns since when the queue's original directory has refused writes
+// as read-only (zero while writable) and the fallback directory in use ("" if none)
+func (q *Queue) StorageStatus() (readOnlySince time.Time, fallback string) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.state.ReadOnlySince, q.fallbackDirectoryLocked()
+}
+
+// fallbackDirectoryLocked returns the fallback directory in use, or "" while the
+// queue is in its original directory (must hold lock)
+func (q *Queue) fallbackDirectoryLocked() string {
+	if q.state.FallbackFrom == "" {
+		return ""
+	}
+	return q.state.Directory
+}
+
+// handleReadOnlyLocked records a write refused as read-only and moves the queue to
+// its fallback directory, once. nil means the write can be retried in the new
+// directory. Frames already queued stay behind: a read-only filesystem could not
+// delete them after upload. (must hold lock)
+func (q *Queue) handleReadOnlyLocked(cause error) error {
+	if q.state.ReadOnlySince.IsZero() {
+		q.state.ReadOnlySince = time.Now()
+	}
+	if q.fallbackDir == "" || q.state.FallbackFrom != "" {
+		return fmt.Errorf("%w: %v", ErrReadOnlyFilesystem, cause)
+	}
+	if err := os.MkdirAll(q.fallbackDir, 0755); err != nil {
+		return fmt.Errorf("%w: create fallback directory: %v", ErrReadOnlyFilesystem, err)
+	}
+
+	q.logger.Error("Queue filesystem is read-only, moving queue to the fallback directory",
+		"camera", q.state.CameraID,
+		"directory", q.state.Directory,
+		"fallback", q.fallbackDir,
+		"frames_left_behind", q.state.ImageCount)
+	q.state.FallbackFrom = q.state.Directory
+	q.state.Directory = q.fallbackDir
+	q.state.OldestTimestamp, q.state.NewestTimestamp = time.Time{}, time.Time{}
+	return q.scanDirectoryLocked()
+}
+
+// recordWritableLocked clears the read-only state once the original directory
+// accepts writes again (must hold lock)
+func (q *Queue) recordWritableLocked() {
+	if !q.state.ReadOnlySince.IsZero() && q.state.FallbackFrom == "" {
+		q.state.ReadOnlySince = time.Time{}
+	}
+}
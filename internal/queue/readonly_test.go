@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// markReadOnly simulates a write refused by a read-only filesystem
+func markReadOnly(q *Queue) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.handleReadOnlyLocked(&os.PathError{Op: "open", Path: q.state.Directory, Err: syscall.EROFS})
+}
+
+func TestIsReadOnlyError(t *testing.T) {
+	if !isReadOnlyError(&os.PathError{Op: "open", Path: "/x", Err: syscall.EROFS}) {
+		t.Error("EROFS should be read-only")
+	}
+	if isReadOnlyError(&os.PathError{Op: "open", Path: "/x", Err: syscall.ENOSPC}) || isReadOnlyError(nil) {
+		t.Error("only EROFS is read-only")
+	}
+}
+
+func TestQueue_ReadOnlyMovesToFallback(t *testing.T) {
+	dir := t.TempDir()
+	fallback := filepath.Join(t.TempDir(), "test-camera")
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	q.SetFallbackDirectory(fallback)
+	if err := q.Enqueue(createTestJPEG(1024), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	if err := markReadOnly(q); err != nil {
+		t.Fatalf("expected the write to be retried in the fallback, got %v", err)
+	}
+	since, inUse := q.StorageStatus()
+	if since.IsZero() || inUse != fallback {
+		t.Fatalf("StorageStatus = %v, %q; want read-only in %q", since, inUse, fallback)
+	}
+	if err := q.Enqueue(createTestJPEG(1024), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue in fallback failed: %v", err)
+	}
+	if files, _ := os.ReadDir(fallback); len(files) != 1 {
+		t.Errorf("expected 1 file in the fallback, got %d", len(files))
+	}
+
+	stats := q.GetStats()
+	if !stats.FilesystemReadOnly || stats.FallbackDirectory != fallback || stats.ImageCount != 1 {
+		t.Errorf("stats = read-only %v, fallback %q, images %d", stats.FilesystemReadOnly, stats.FallbackDirectory, stats.ImageCount)
+	}
+
+	// The fallback is used once; a read-only fallback fails
+	if err := markReadOnly(q); !errors.Is(err, ErrReadOnlyFilesystem) {
+		t.Errorf("expected ErrReadOnlyFilesystem, got %v", err)
+	}
+}
+
+func TestQueue_ReadOnlyWithoutFallback(t *testing.T) {
+	q, err := NewQueue("test-camera", t.TempDir(), DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+
+	if err := markReadOnly(q); !errors.Is(err, ErrReadOnlyFilesystem) {
+		t.Fatalf("expected ErrReadOnlyFilesystem, got %v", err)
+	}
+	if !q.GetStats().FilesystemReadOnly {
+		t.Error("expected the queue to report a read-only filesystem")
+	}
+
+	// A successful write clears it
+	if err := q.Enqueue(createTestJPEG(1024), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if since, fallback := q.StorageStatus(); !since.IsZero() || fallback != "" {
+		t.Errorf("StorageStatus = %v, %q; want writable", since, fallback)
+	}
+}
+
+func TestQueue_EnqueueFileClearsReadOnly(t *testing.T) {
+	q, err := NewQueue("test-camera", t.TempDir(), DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	if err := markReadOnly(q); !errors.Is(err, ErrReadOnlyFilesystem) {
+		t.Fatalf("expected ErrReadOnlyFilesystem, got %v", err)
+	}
+
+	f, err := q.CreateSpoolFile()
+	if err != nil {
+		t.Fatalf("CreateSpoolFile failed: %v", err)
+	}
+	_, _ = f.Write(createTestJPEG(2048))
+	f.Close()
+	if err := q.EnqueueFile(f.Name(), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("EnqueueFile failed: %v", err)
+	}
+	if since, _ := q.StorageStatus(); !since.IsZero() {
+		t.Errorf("still read-only since %v after a spooled frame was queued", since)
+	}
+}
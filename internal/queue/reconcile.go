@@ -0,0 +1,107 @@
+package queue
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// MinReconcileInterval is the shortest interval between reconciliation passes
+const MinReconcileInterval = 10 * time.Second
+
+// Reconcile re-scans the queue directory and corrects the tracked image count and
+// size if they disagree with the files on disk. The scan is skipped while the
+// directory's modification time is unchanged since the last one, so an idle queue
+// costs a single stat. Reports whether the directory was scanned.
+func (q *Queue) Reconcile() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	info, err := os.Stat(q.state.Directory)
+	if err != nil {
+		q.logger.Warn("Queue reconciliation failed",
+			"camera", q.state.CameraID,
+			"error", err)
+		return false
+	}
+	if info.ModTime().Equal(q.scannedModTime) {
+		return false
+	}
+	if _, _, err := q.reconcileLocked(); err != nil {
+		q.logger.Warn("Queue reconciliation failed",
+			"camera", q.state.CameraID,
+			"error", err)
+		return false
+	}
+	return true
+}
+
+// reconcileLocked compares the tracked state with the files on disk and corrects it,
+// returning the drift found (disk minus tracked)
+func (q *Queue) reconcileLocked() (countDrift int, sizeDriftBytes int64, err error) {
+	// Stat before listing: a change made during the scan leaves a newer mtime behind
+	var modTime time.Time
+	if info, err := os.Stat(q.state.Directory); err == nil {
+		modTime = info.ModTime()
+	}
+	files, err := q.listFilesSortedLocked()
+	if err != nil {
+		return 0, 0, err
+	}
+	q.scannedModTime = modTime
+
+	var diskBytes int64
+	for _, f := range files {
+		diskBytes += f.Size()
+	}
+	countDrift = len(files) - q.state.ImageCount
+	sizeDriftBytes = diskBytes - q.state.TotalSizeBytes
+	if countDrift == 0 && sizeDriftBytes == 0 {
+		return 0, 0, nil
+	}
+
+	q.logger.Warn("Queue state drift corrected",
+		"camera", q.state.CameraID,
+		"tracked_images", q.state.ImageCount,
+		"disk_images", len(files),
+		"count_drift", countDrift,
+		"size_drift_bytes", sizeDriftBytes)
+
+	q.state.ImageCount = len(files)
+	q.state.TotalSizeBytes = diskBytes
+	q.state.DriftCorrections++
+	q.recalculateOldestLocked()
+	q.updateHealthLevelLocked()
+	return countDrift, sizeDriftBytes, nil
+}
+
+// ReconcileAll runs a reconciliation pass on every queue
+func (m *Manager) ReconcileAll() {
+	for _, q := range m.GetAllQueues() {
+		q.Reconcile()
+	}
+}
+
+// StartReconcileWorker periodically corrects queue state that drifted from the files
+// on disk (see Queue.Reconcile)
+func (m *Manager) StartReconcileWorker(ctx context.Context, interval time.Duration) {
+	if interval < MinReconcileInterval {
+		interval = MinReconcileInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.logger.Info("Reconcile worker started",
+		"interval", interval.String())
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Reconcile worker stopped")
+			return
+		case <-ticker.C:
+			m.ReconcileAll()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package queue
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueue_ReconcileCorrectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewQueue("test-camera", dir, DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(createTestJPEG(1024), now.Add(time.Duration(-i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+
+	// Enqueue changed the directory since the initial scan
+	if !q.Reconcile() {
+		t.Fatal("expected a scan after the directory changed")
+	}
+	if q.GetStats().DriftCorrections != 0 {
+		t.Error("tracked state was already correct")
+	}
+	if q.Reconcile() {
+		t.Error("unchanged directory should be skipped")
+	}
+
+	// Add a frame behind the queue's back
+	extra := filepath.Join(dir, "1735142730000.jpg")
+	if err := os.WriteFile(extra, createTestJPEG(512), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !q.Reconcile() {
+		t.Fatal("expected a scan after an external change")
+	}
+	stats := q.GetStats()
+	if stats.ImageCount != 4 || stats.DriftCorrections != 1 {
+		t.Errorf("ImageCount = %d, DriftCorrections = %d, want 4 and 1", stats.ImageCount, stats.DriftCorrections)
+	}
+	if oldest := q.GetState().OldestTimestamp; !oldest.Equal(time.UnixMilli(1735142730000).UTC()) {
+		t.Errorf("OldestTimestamp = %v, want the external frame", oldest)
+	}
+}
@@ -0,0 +1,65 @@
+package queue
+
+import "time"
+
+// Handling of frames whose observation time is earlier than the newest queued frame,
+// e.g. after the system clock stepped backward. Filenames are the observation time,
+// so such frames would otherwise upload out of order.
+const (
+	RegressionClamp  = "clamp"  // Move the frame just after the newest queued frame (default)
+	RegressionReject = "reject" // Drop the frame with ErrTimestampRegression
+)
+
+// DefaultRegressionTolerance absorbs small disagreements such as camera EXIF time
+// running slightly behind the bridge clock
+const DefaultRegressionTolerance = 2 * time.Second
+
+// NormalizeRegressionPolicy returns policy if recognized, otherwise the default
+func NormalizeRegressionPolicy(policy string) string {
+	if policy == RegressionReject {
+		return policy
+	}
+	return RegressionClamp
+}
+
+// SetRegressionPolicy sets how timestamp regressions beyond tolerance are handled.
+// A tolerance of 0 uses DefaultRegressionTolerance.
+func (q *Queue) SetRegressionPolicy(policy string, tolerance time.Duration) {
+	if tolerance <= 0 {
+		tolerance = DefaultRegressionTolerance
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.regressionPolicy = NormalizeRegressionPolicy(policy)
+	q.regressionTolerance = tolerance
+}
+
+// checkRegressionLocked applies the regression policy, returning the observation
+// time to file the frame under (must hold lock)
+func (q *Queue) checkRegressionLocked(observationTime time.Time) (time.Time, error) {
+	newest := q.state.NewestTimestamp
+	tolerance := q.regressionTolerance
+	if tolerance <= 0 {
+		tolerance = DefaultRegressionTolerance
+	}
+	if newest.IsZero() || !observationTime.Before(newest.Add(-tolerance)) {
+		return observationTime, nil
+	}
+
+	q.state.TimestampRegressions++
+	if q.regressionPolicy == RegressionReject {
+		q.logger.Warn("Rejected frame older than the newest queued frame (clock stepped backward?)",
+			"camera", q.state.CameraID,
+			"observation_time", observationTime.Format(time.RFC3339),
+			"newest_queued", newest.Format(time.RFC3339))
+		return observationTime, ErrTimestampRegression
+	}
+
+	clamped := newest.Add(time.Millisecond)
+	q.logger.Warn("Clamped frame timestamp forward to keep queue order (clock stepped backward?)",
+		"camera", q.state.CameraID,
+		"observation_time", observationTime.Format(time.RFC3339),
+		"clamped_to", clamped.Format(time.RFC3339Nano),
+		"regression", newest.Sub(observationTime).Round(time.Millisecond))
+	return clamped, nil
+}
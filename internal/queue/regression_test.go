@@ -0,0 +1,76 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueue_TimestampRegression(t *testing.T) {
+	newest := time.Now().UTC().Add(-time.Minute).Truncate(time.Millisecond)
+
+	tests := []struct {
+		name      string
+		policy    string
+		offset    time.Duration // Observation time relative to the newest queued frame
+		wantErr   error
+		wantTime  time.Time
+		wantCount int64
+	}{
+		{"newer frame", RegressionReject, time.Second, nil, newest.Add(time.Second), 0},
+		{"within tolerance", RegressionReject, -time.Second, nil, newest.Add(-time.Second), 0},
+		{"clamped", RegressionClamp, -30 * time.Second, nil, newest.Add(time.Millisecond), 1},
+		{"unknown policy clamps", "bogus", -30 * time.Second, nil, newest.Add(time.Millisecond), 1},
+		{"rejected", RegressionReject, -30 * time.Second, ErrTimestampRegression, time.Time{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQueue("test-camera", t.TempDir(), DefaultQueueConfig(), nil)
+			if err != nil {
+				t.Fatalf("NewQueue failed: %v", err)
+			}
+			q.SetRegressionPolicy(tt.policy, 0)
+			if err := q.Enqueue(createTestJPEG(1024), newest, "bridge_clock", "high"); err != nil {
+				t.Fatalf("first Enqueue failed: %v", err)
+			}
+
+			err = q.Enqueue(createTestJPEG(1024), newest.Add(tt.offset), "bridge_clock", "high")
+			if err != tt.wantErr {
+				t.Fatalf("Enqueue error = %v, want %v", err, tt.wantErr)
+			}
+			if got := q.GetStats().TimestampRegressions; got != tt.wantCount {
+				t.Errorf("TimestampRegressions = %d, want %d", got, tt.wantCount)
+			}
+			if tt.wantErr != nil {
+				if q.GetImageCount() != 1 {
+					t.Errorf("rejected frame was queued")
+				}
+				return
+			}
+
+			images, _ := q.Peek(2)
+			found := false
+			for _, img := range images {
+				found = found || img.Timestamp.Equal(tt.wantTime)
+			}
+			if !found {
+				t.Errorf("no queued frame at %v", tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestQueue_TimestampRegressionTolerance(t *testing.T) {
+	q, err := NewQueue("test-camera", t.TempDir(), DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue failed: %v", err)
+	}
+	q.SetRegressionPolicy(RegressionReject, time.Minute)
+
+	newest := time.Now().UTC().Add(-time.Minute)
+	if err := q.Enqueue(createTestJPEG(1024), newest, "bridge_clock", "high"); err != nil {
+		t.Fatalf("first Enqueue failed: %v", err)
+	}
+	if err := q.Enqueue(createTestJPEG(1024), newest.Add(-30*time.Second), "bridge_clock", "high"); err != nil {
+		t.Errorf("regression within a 1m tolerance should be accepted: %v", err)
+	}
+}
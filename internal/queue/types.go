@@ -15,8 +15,18 @@ var (
 	ErrFileTooLarge    = errors.New("file exceeds maximum size")
 	ErrImageExpired    = errors.New("image exceeds maximum age")
 	ErrImageFromFuture = errors.New("image timestamp is in the future")
+
+	// ErrReadOnlyFilesystem is returned while the queue directory's filesystem refuses
+	// writes as read-only and no fallback directory is available
+	ErrReadOnlyFilesystem = errors.New("queue filesystem is read-only")
+
+	ErrTimestampRegression = errors.New("image timestamp is earlier than the newest queued image")
 )
 
+// spoolFilePattern names in-progress streamed captures; the non-numeric prefix keeps
+// them out of the queue listing, and the .jpg suffix lets exiftool write to them
+const spoolFilePattern = "spool-*.jpg"
+
 // HealthLevel represents the current health state of a queue
 type HealthLevel int
 
@@ -64,10 +74,19 @@ type QueueState struct {
 	CapturePaused   bool
 
 	// Statistics
-	ImagesQueued   int64 // Total ever queued
-	ImagesUploaded int64 // Total successfully uploaded
-	ImagesThinned  int64 // Total removed by thinning
-	ImagesExpired  int64 // Total removed by age
+	ImagesQueued    int64 // Total ever queued
+	ImagesUploaded  int64 // Total successfully uploaded
+	ImagesThinned   int64 // Total removed by thinning
+	ImagesExpired   int64 // Total removed by age
+	ImagesAbandoned int64 // Total dropped after exhausting upload attempts
+
+	TimestampRegressions int64 // Frames older than the newest queued one (clamped or rejected)
+	DriftCorrections     int64 // Times the tracked count/size was corrected to match the disk
+	AlreadyUploaded      int64 // Frames removed because the server already had them
+
+	// Read-only filesystem handling (see SetFallbackDirectory)
+	ReadOnlySince time.Time // Original directory refusing writes since; zero while writable
+	FallbackFrom  string    // Original directory after moving to the fallback; "" if not moved
 }
 
 // QueueConfig defines queue behavior for a single camera
@@ -90,6 +109,10 @@ type QueueConfig struct {
 	// Critical behavior
 	PauseCaptureOnCritical bool    `json:"pause_capture_critical"` // Default: true
 	ResumeThreshold        float64 `json:"resume_threshold"`       // Default: 0.70
+
+	// PauseThreshold pauses capture at this capacity instead of ThresholdCritical,
+	// e.g. to pause early rather than thin. Default: 0 (ThresholdCritical)
+	PauseThreshold float64 `json:"pause_threshold"`
 }
 
 // DefaultQueueConfig returns sensible defaults for queue configuration
@@ -116,16 +139,31 @@ type GlobalQueueConfig struct {
 	MemoryCheckSeconds int     `json:"memory_check_seconds"` // Default: 5
 	EmergencyThinRatio float64 `json:"emergency_thin_ratio"` // Default: 0.5 (keep 50%)
 	MaxHeapMB          int     `json:"max_heap_mb"`          // Default: 400 (for 512MB Pi)
+
+	// Compaction sweeps orphaned partial files and reconciles tracked state
+	CompactionSeconds   int `json:"compaction_seconds"`     // Default: 600 (10 min)
+	OrphanMaxAgeSeconds int `json:"orphan_max_age_seconds"` // Default: 600 (10 min)
+
+	// ReconcileSeconds is the interval between cheap state reconciliations, which
+	// skip queues whose directory is unchanged
+	ReconcileSeconds int `json:"reconcile_seconds"` // Default: 60
+
+	// FallbackPath is where queues move, each to its own subdirectory, if BasePath's
+	// filesystem turns read-only. Default: "" (none)
+	FallbackPath string `json:"fallback_path"`
 }
 
 // DefaultGlobalQueueConfig returns sensible defaults for global queue config
 func DefaultGlobalQueueConfig() GlobalQueueConfig {
 	return GlobalQueueConfig{
-		BasePath:           "/dev/shm/aviationwx",
-		MaxTotalSizeMB:     100,
-		MemoryCheckSeconds: 5,
-		EmergencyThinRatio: 0.5,
-		MaxHeapMB:          400,
+		BasePath:            "/dev/shm/aviationwx",
+		MaxTotalSizeMB:      100,
+		MemoryCheckSeconds:  5,
+		EmergencyThinRatio:  0.5,
+		MaxHeapMB:           400,
+		CompactionSeconds:   600,
+		OrphanMaxAgeSeconds: 600,
+		ReconcileSeconds:    60,
 	}
 }
 
@@ -143,6 +181,17 @@ type QueueStats struct {
 	ImagesUploaded  int64   `json:"images_uploaded"`
 	ImagesThinned   int64   `json:"images_thinned"`
 	ImagesExpired   int64   `json:"images_expired"`
+	ImagesAbandoned int64   `json:"images_abandoned"`
+
+	TimestampRegressions int64 `json:"timestamp_regressions"`       // Clamped or rejected out-of-order frames
+	DriftCorrections     int64 `json:"drift_corrections,omitempty"` // Tracked state corrected to match the disk
+	AlreadyUploaded      int64 `json:"already_uploaded,omitempty"`  // Removed because the server already had them
+
+	LastCompaction *CompactionResult `json:"last_compaction,omitempty"`
+
+	FilesystemReadOnly bool      `json:"queue_filesystem_readonly,omitempty"` // Original directory refuses writes
+	ReadOnlySince      time.Time `json:"readonly_since,omitempty"`
+	FallbackDirectory  string    `json:"fallback_directory,omitempty"` // Queue moved here after going read-only
 }
 
 // GlobalQueueStats provides global statistics
@@ -168,6 +217,19 @@ type Queue struct {
 
 	// Logger interface (optional)
 	logger Logger
+
+	// Result of the most recent Compact pass
+	lastCompaction *CompactionResult
+
+	// Directory mtime at the last scan; Reconcile skips the scan while it is unchanged
+	scannedModTime time.Time
+
+	// Timestamp regression handling (see SetRegressionPolicy)
+	regressionPolicy    string
+	regressionTolerance time.Duration
+
+	// Where the queue moves if its filesystem turns read-only; "" = none
+	fallbackDir string
 }
 
 // Logger interface for dependency injection
@@ -20,6 +20,7 @@ type Limiter struct {
 	// Semaphores for different work types (channel-based for stdlib compatibility)
 	imageProcessing chan struct{}
 	exifOperations  chan struct{}
+	webRequests     chan struct{}
 
 	// Adaptive throttling state
 	mu              sync.RWMutex
@@ -36,6 +37,31 @@ type Limiter struct {
 	exifWaitTimeNs      atomic.Int64
 	throttleDelayCount  atomic.Int64
 	throttleDelayTimeNs atomic.Int64
+	webRejectedCount    atomic.Int64
+
+	// Goroutine ceiling shedding state
+	goroutineCeiling atomic.Int64
+	shedMu           sync.Mutex
+	shedding         bool
+	shedSince        time.Time
+	shedCounts       map[string]int64
+}
+
+// Non-essential work skipped while the goroutine count is above the ceiling
+const (
+	WorkPreview      = "preview"       // Web console preview cache updates
+	WorkMetrics      = "metrics"       // Fresh /metrics collection (the last snapshot is served)
+	WorkQualityCheck = "quality_check" // Quality self-check sampling
+)
+
+// shedRecoveryRatio is the fraction of the ceiling the goroutine count must fall
+// below before shedding stops, so work does not flap around the ceiling
+const shedRecoveryRatio = 0.9
+
+// Logger is the logging interface used by Limiter
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
 }
 
 // Config configures the resource limiter
@@ -48,6 +74,12 @@ type Config struct {
 	// Default: 1 (exiftool is heavy and serializing prevents thrashing)
 	MaxConcurrentExifOperations int
 
+	// MaxConcurrentWebRequests caps in-flight expensive web requests (status, metrics,
+	// tests); excess requests are rejected rather than queued so a burst of
+	// dashboard clients cannot starve capture/upload work
+	// Default: 4
+	MaxConcurrentWebRequests int
+
 	// MemoryPressureThresholdMB is the heap size above which throttling kicks in
 	// Default: 200MB (suitable for Pi Zero 2 W with 512MB total)
 	MemoryPressureThresholdMB int
@@ -63,6 +95,14 @@ type Config struct {
 	// PressureCheckInterval is how often to recalculate system pressure
 	// Default: 1 second
 	PressureCheckInterval time.Duration
+
+	// GoroutineCeiling is a soft limit on total goroutines; above it non-essential
+	// background work is shed (see ShouldShed) until the count recovers.
+	// Default: 0 (disabled)
+	GoroutineCeiling int
+
+	// Logger reports when shedding starts and stops (optional)
+	Logger Logger
 }
 
 // DefaultConfig returns sensible defaults for Pi Zero 2 W
@@ -80,6 +120,7 @@ func DefaultConfig() Config {
 	return Config{
 		MaxConcurrentImageProcessing: maxImageProcessing,
 		MaxConcurrentExifOperations:  1,
+		MaxConcurrentWebRequests:     4,
 		MemoryPressureThresholdMB:    200,
 		GoroutinePressureThreshold:   100,
 		MaxThrottleDelay:             2 * time.Second,
@@ -96,6 +137,9 @@ func NewLimiter(cfg Config) *Limiter {
 	if cfg.MaxConcurrentExifOperations <= 0 {
 		cfg.MaxConcurrentExifOperations = 1
 	}
+	if cfg.MaxConcurrentWebRequests <= 0 {
+		cfg.MaxConcurrentWebRequests = 4
+	}
 	if cfg.MemoryPressureThresholdMB <= 0 {
 		cfg.MemoryPressureThresholdMB = 200
 	}
@@ -109,11 +153,15 @@ func NewLimiter(cfg Config) *Limiter {
 		cfg.PressureCheckInterval = time.Second
 	}
 
-	return &Limiter{
+	l := &Limiter{
 		imageProcessing: make(chan struct{}, cfg.MaxConcurrentImageProcessing),
 		exifOperations:  make(chan struct{}, cfg.MaxConcurrentExifOperations),
+		webRequests:     make(chan struct{}, cfg.MaxConcurrentWebRequests),
 		config:          cfg,
+		shedCounts:      make(map[string]int64),
 	}
+	l.goroutineCeiling.Store(int64(max(0, cfg.GoroutineCeiling)))
+	return l
 }
 
 // DefaultLimiter creates a limiter with default configuration
@@ -191,6 +239,23 @@ func (l *Limiter) ReleaseExifOperation() {
 	<-l.exifOperations
 }
 
+// TryAcquireWebRequest attempts to acquire a slot for an expensive web request
+// without blocking. Returns false (and counts a rejection) when at capacity.
+func (l *Limiter) TryAcquireWebRequest() bool {
+	select {
+	case l.webRequests <- struct{}{}:
+		return true
+	default:
+		l.webRejectedCount.Add(1)
+		return false
+	}
+}
+
+// ReleaseWebRequest releases a web request slot
+func (l *Limiter) ReleaseWebRequest() {
+	<-l.webRequests
+}
+
 // GetThrottleDelay returns a delay duration based on current system pressure.
 // Background workers should sleep for this duration before starting heavy work.
 // Returns 0 if system is healthy.
@@ -270,15 +335,67 @@ func (l *Limiter) IsUnderPressure() bool {
 	return l.GetPressure() > 0.3
 }
 
+// SetGoroutineCeiling changes the goroutine ceiling (0 disables shedding)
+func (l *Limiter) SetGoroutineCeiling(ceiling int) {
+	l.goroutineCeiling.Store(int64(max(0, ceiling)))
+}
+
+// ShouldShed reports whether non-essential work of the given kind (WorkPreview,
+// WorkMetrics, WorkQualityCheck) should be skipped because the goroutine count is
+// above the ceiling. Shedding continues until the count falls below 90% of the
+// ceiling; both transitions are logged. Capture and upload never call this.
+func (l *Limiter) ShouldShed(work string) bool {
+	ceiling := int(l.goroutineCeiling.Load())
+	goroutines := runtime.NumGoroutine()
+
+	l.shedMu.Lock()
+	defer l.shedMu.Unlock()
+
+	switch {
+	case ceiling <= 0:
+		if l.shedding {
+			l.stopShedding(goroutines)
+		}
+	case !l.shedding && goroutines > ceiling:
+		l.shedding = true
+		l.shedSince = time.Now()
+		if l.config.Logger != nil {
+			l.config.Logger.Warn("Goroutine count above ceiling - shedding non-essential work",
+				"goroutines", goroutines,
+				"ceiling", ceiling)
+		}
+	case l.shedding && float64(goroutines) < float64(ceiling)*shedRecoveryRatio:
+		l.stopShedding(goroutines)
+	}
+
+	if l.shedding {
+		l.shedCounts[work]++
+	}
+	return l.shedding
+}
+
+// stopShedding ends a shedding period; caller holds shedMu
+func (l *Limiter) stopShedding(goroutines int) {
+	l.shedding = false
+	if l.config.Logger != nil {
+		l.config.Logger.Info("Goroutine count recovered - resuming non-essential work",
+			"goroutines", goroutines,
+			"shed_for", time.Since(l.shedSince).Round(time.Second))
+	}
+	l.shedSince = time.Time{}
+}
+
 // Stats holds resource limiter statistics
 type Stats struct {
 	// Configuration
 	MaxImageProcessing int `json:"max_image_processing"`
 	MaxExifOperations  int `json:"max_exif_operations"`
+	MaxWebRequests     int `json:"max_web_requests"`
 
 	// Current state
 	ImageProcessingInUse int     `json:"image_processing_in_use"`
 	ExifOperationsInUse  int     `json:"exif_operations_in_use"`
+	WebRequestsInUse     int     `json:"web_requests_in_use"`
 	CurrentPressure      float64 `json:"current_pressure"`
 	IsUnderPressure      bool    `json:"is_under_pressure"`
 
@@ -289,6 +406,13 @@ type Stats struct {
 	ExifTotalWaitTime  time.Duration `json:"exif_total_wait_time"`
 	ThrottleDelayCount int64         `json:"throttle_delay_count"`
 	ThrottleTotalDelay time.Duration `json:"throttle_total_delay"`
+	WebRejectedCount   int64         `json:"web_rejected_count"`
+
+	// Goroutine ceiling shedding
+	GoroutineCeiling int              `json:"goroutine_ceiling,omitempty"`
+	Shedding         bool             `json:"shedding,omitempty"`
+	SheddingSince    time.Time        `json:"shedding_since,omitempty"`
+	ShedCounts       map[string]int64 `json:"shed_counts,omitempty"` // Skipped work by kind
 
 	// System info
 	NumCPU        int     `json:"num_cpu"`
@@ -305,11 +429,24 @@ func (l *Limiter) GetStats() Stats {
 	pressure := l.currentPressure
 	l.mu.RUnlock()
 
+	l.shedMu.Lock()
+	shedding, shedSince := l.shedding, l.shedSince
+	var shedCounts map[string]int64
+	if len(l.shedCounts) > 0 {
+		shedCounts = make(map[string]int64, len(l.shedCounts))
+		for work, n := range l.shedCounts {
+			shedCounts[work] = n
+		}
+	}
+	l.shedMu.Unlock()
+
 	return Stats{
 		MaxImageProcessing:   l.config.MaxConcurrentImageProcessing,
 		MaxExifOperations:    l.config.MaxConcurrentExifOperations,
+		MaxWebRequests:       l.config.MaxConcurrentWebRequests,
 		ImageProcessingInUse: len(l.imageProcessing),
 		ExifOperationsInUse:  len(l.exifOperations),
+		WebRequestsInUse:     len(l.webRequests),
 		CurrentPressure:      pressure,
 		IsUnderPressure:      pressure > 0.3,
 		ImageAcquireCount:    l.imageAcquireCount.Load(),
@@ -318,6 +455,11 @@ func (l *Limiter) GetStats() Stats {
 		ExifTotalWaitTime:    time.Duration(l.exifWaitTimeNs.Load()),
 		ThrottleDelayCount:   l.throttleDelayCount.Load(),
 		ThrottleTotalDelay:   time.Duration(l.throttleDelayTimeNs.Load()),
+		WebRejectedCount:     l.webRejectedCount.Load(),
+		GoroutineCeiling:     int(l.goroutineCeiling.Load()),
+		Shedding:             shedding,
+		SheddingSince:        shedSince,
+		ShedCounts:           shedCounts,
 		NumCPU:               runtime.NumCPU(),
 		NumGoroutines:        runtime.NumGoroutine(),
 		HeapAllocMB:          float64(m.HeapAlloc) / (1024 * 1024),
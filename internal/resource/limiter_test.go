@@ -2,6 +2,7 @@ package resource
 
 import (
 	"context"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -102,6 +103,32 @@ func TestExifOperationsSemaphore(t *testing.T) {
 	l.ReleaseExifOperation()
 }
 
+func TestWebRequestSemaphore(t *testing.T) {
+	l := NewLimiter(Config{MaxConcurrentWebRequests: 1})
+
+	if !l.TryAcquireWebRequest() {
+		t.Fatal("first acquire failed")
+	}
+	if l.TryAcquireWebRequest() {
+		t.Error("expected TryAcquire to fail when at capacity")
+		l.ReleaseWebRequest()
+	}
+
+	stats := l.GetStats()
+	if stats.WebRequestsInUse != 1 {
+		t.Errorf("expected WebRequestsInUse=1, got %d", stats.WebRequestsInUse)
+	}
+	if stats.WebRejectedCount != 1 {
+		t.Errorf("expected WebRejectedCount=1, got %d", stats.WebRejectedCount)
+	}
+
+	l.ReleaseWebRequest()
+	if !l.TryAcquireWebRequest() {
+		t.Error("expected TryAcquire to succeed after release")
+	}
+	l.ReleaseWebRequest()
+}
+
 func TestAcquireWithContextCancellation(t *testing.T) {
 	l := NewLimiter(Config{
 		MaxConcurrentImageProcessing: 1,
@@ -239,6 +266,68 @@ func TestPressureCalculation(t *testing.T) {
 	}
 }
 
+type recordingLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *recordingLogger) Info(msg string, _ ...interface{}) { r.record(msg) }
+func (r *recordingLogger) Warn(msg string, _ ...interface{}) { r.record(msg) }
+
+func (r *recordingLogger) record(msg string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, msg)
+}
+
+func TestShouldShed(t *testing.T) {
+	log := &recordingLogger{}
+	l := NewLimiter(Config{Logger: log})
+
+	if l.ShouldShed(WorkPreview) {
+		t.Error("shedding should be disabled without a ceiling")
+	}
+
+	// Any test binary runs more than one goroutine
+	l.SetGoroutineCeiling(1)
+	if !l.ShouldShed(WorkPreview) || !l.ShouldShed(WorkMetrics) || !l.ShouldShed(WorkPreview) {
+		t.Error("expected shedding above the ceiling")
+	}
+	stats := l.GetStats()
+	if !stats.Shedding || stats.SheddingSince.IsZero() || stats.GoroutineCeiling != 1 {
+		t.Errorf("stats while shedding = %+v", stats)
+	}
+	if stats.ShedCounts[WorkPreview] != 2 || stats.ShedCounts[WorkMetrics] != 1 {
+		t.Errorf("shed counts = %v", stats.ShedCounts)
+	}
+
+	l.SetGoroutineCeiling(1 << 20)
+	if l.ShouldShed(WorkQualityCheck) {
+		t.Error("expected shedding to stop once the count recovered")
+	}
+	if stats := l.GetStats(); stats.Shedding || stats.ShedCounts[WorkQualityCheck] != 0 {
+		t.Errorf("stats after recovery = %+v", stats)
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if len(log.msgs) != 2 {
+		t.Errorf("expected start and recovery to be logged once each, got %v", log.msgs)
+	}
+}
+
+func TestShouldShed_Hysteresis(t *testing.T) {
+	l := NewLimiter(Config{GoroutineCeiling: 1})
+	if !l.ShouldShed(WorkPreview) {
+		t.Fatal("expected shedding above the ceiling")
+	}
+	// No longer above the ceiling, but not yet below 90% of it
+	l.SetGoroutineCeiling(runtime.NumGoroutine())
+	if !l.ShouldShed(WorkPreview) {
+		t.Error("shedding should continue until the count is below 90% of the ceiling")
+	}
+}
+
 func TestGetStats(t *testing.T) {
 	l := NewLimiter(Config{
 		MaxConcurrentImageProcessing: 2,
@@ -0,0 +1,67 @@
+package scheduler
+
+import "os"
+
+// frameAttempts counts failed upload cycles per queued frame: camera ID -> file path -> count.
+// One cycle is a full uploadWithRetry call, so the in-cycle retry is not counted separately.
+type frameAttempts map[string]map[string]int
+
+// add records a failed cycle and returns the frame's total
+func (a frameAttempts) add(cameraID, path string) int {
+	frames := a[cameraID]
+	if frames == nil {
+		frames = make(map[string]int)
+		a[cameraID] = frames
+	}
+	frames[path]++
+	return frames[path]
+}
+
+// clear forgets a frame once it has been uploaded or dropped
+func (a frameAttempts) clear(cameraID, path string) {
+	if frames := a[cameraID]; frames != nil {
+		delete(frames, path)
+		if len(frames) == 0 {
+			delete(a, cameraID)
+		}
+	}
+}
+
+// prune forgets frames whose files are gone (thinned or expired by the queue)
+func (a frameAttempts) prune(cameraID string) {
+	for path := range a[cameraID] {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			a.clear(cameraID, path)
+		}
+	}
+}
+
+// recordFrameFailure counts a failed upload cycle against the task's frame and, once the
+// camera's MaxUploadAttempts is reached, drops the frame so newer ones can go out
+func (w *UploadWorker) recordFrameFailure(task uploadTask) {
+	limit := task.config.MaxUploadAttempts
+	if limit <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	w.frameAttempts.prune(task.cameraID)
+	attempts := w.frameAttempts.add(task.cameraID, task.image.FilePath)
+	if attempts < limit {
+		w.mu.Unlock()
+		return
+	}
+	w.frameAttempts.clear(task.cameraID, task.image.FilePath)
+	w.uploadsAbandoned++
+	w.mu.Unlock()
+
+	w.logger.Warn("Dropping frame after repeated upload failures",
+		"camera", task.cameraID,
+		"filename", task.image.Filename,
+		"attempts", attempts)
+	if err := task.queue.MarkAbandoned(task.image); err != nil {
+		w.logger.Error("Failed to drop abandoned frame",
+			"camera", task.cameraID,
+			"error", err)
+	}
+}
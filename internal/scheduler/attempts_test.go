@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestUploadWorker_AbandonsFrameAfterMaxAttempts(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	q, _ := queueMgr.CreateQueue("poison", queue.DefaultQueueConfig())
+	ts := time.Now().UTC().Add(-time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(minimalTestJPEG(), ts.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	config := CameraConfig{ID: "poison", MaxUploadAttempts: 3}
+	worker := NewUploadWorker(UploadWorkerConfig{})
+	worker.AddQueue("poison", q, config, &mockUploader{})
+
+	images, err := q.DequeueBatch(1, false)
+	if err != nil {
+		t.Fatalf("DequeueBatch: %v", err)
+	}
+	task := uploadTask{cameraID: "poison", image: images[0], queue: q, config: config}
+
+	// Failures in separate scheduling cycles accumulate until the limit
+	for i := 0; i < 2; i++ {
+		worker.recordFrameFailure(task)
+	}
+	if q.GetImageCount() != 2 {
+		t.Fatalf("frame dropped before limit, count = %d", q.GetImageCount())
+	}
+
+	worker.recordFrameFailure(task)
+	if q.GetImageCount() != 1 {
+		t.Errorf("expected poison frame dropped, count = %d", q.GetImageCount())
+	}
+	if _, err := os.Stat(images[0].FilePath); !os.IsNotExist(err) {
+		t.Error("abandoned frame file should be removed")
+	}
+	if got := worker.GetStats().UploadsAbandoned; got != 1 {
+		t.Errorf("UploadsAbandoned = %d, want 1", got)
+	}
+	if got := q.GetStats().ImagesAbandoned; got != 1 {
+		t.Errorf("ImagesAbandoned = %d, want 1", got)
+	}
+	if len(worker.frameAttempts) != 0 {
+		t.Errorf("attempt tracking not cleared: %v", worker.frameAttempts)
+	}
+}
+
+func TestUploadWorker_UnlimitedAttemptsKeepsFrame(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	q, _ := queueMgr.CreateQueue("cam", queue.DefaultQueueConfig())
+	if err := q.Enqueue(minimalTestJPEG(), time.Now().UTC().Add(-time.Second), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	worker := NewUploadWorker(UploadWorkerConfig{})
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam"}, &mockUploader{})
+	images, _ := q.DequeueBatch(1, false)
+	task := uploadTask{cameraID: "cam", image: images[0], queue: q, config: CameraConfig{ID: "cam"}}
+
+	for i := 0; i < 10; i++ {
+		worker.recordFrameFailure(task)
+	}
+	if q.GetImageCount() != 1 || worker.GetStats().UploadsAbandoned != 0 {
+		t.Error("frame should never be dropped when MaxUploadAttempts is 0")
+	}
+}
+
+func TestFrameAttempts_Prune(t *testing.T) {
+	dir := t.TempDir()
+	kept := dir + "/kept.jpg"
+	if err := os.WriteFile(kept, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := make(frameAttempts)
+	a.add("cam", kept)
+	a.add("cam", dir+"/thinned.jpg")
+	a.prune("cam")
+
+	if len(a["cam"]) != 1 || a["cam"][kept] != 1 {
+		t.Errorf("prune should only forget missing files, got %v", a["cam"])
+	}
+	a.clear("cam", kept)
+	if _, ok := a["cam"]; ok {
+		t.Error("empty camera entry should be removed")
+	}
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+// BandwidthStats reports the upload bandwidth limit and how much it held uploads back
+type BandwidthStats struct {
+	LimitBytesPerSec int64   `json:"limit_bytes_per_sec"`
+	SentBytes        int64   `json:"sent_bytes"`   // Bytes paced through the limit
+	WaitSeconds      float64 `json:"wait_seconds"` // Time uploads spent waiting on the limit
+}
+
+// bandwidthChunk bounds each paced read, so concurrent uploads interleave instead of
+// one sending a whole frame while the others wait
+const bandwidthChunk = 16 * 1024
+
+// bandwidthLimiter is a token bucket of bytes shared by all of a worker's uploads, so
+// the limit caps their total however many run at once. Up to a second's worth builds
+// up while idle. A reservation may take the bucket below zero and the caller sleeps
+// off the debt, so uploads waiting together are served in turn.
+type bandwidthLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second; 0 = unlimited
+	tokens float64
+	last   time.Time // Zero until the first reservation: the bucket starts full
+	sent   int64
+	waited time.Duration
+}
+
+// setLimit changes the limit in bytes per second (0 = unlimited)
+func (l *bandwidthLimiter) setLimit(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = float64(bytesPerSec)
+	l.last = time.Time{}
+}
+
+// limit returns the limit in bytes per second (0 = unlimited)
+func (l *bandwidthLimiter) limit() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int64(l.rate)
+}
+
+// wait reserves n bytes and sleeps until they may be sent, or until ctx is done
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = l.rate
+	} else {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+	}
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	l.sent += int64(n)
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		l.waited += delay
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// stats returns the limiter's stats, or nil while unlimited
+func (l *bandwidthLimiter) stats() *BandwidthStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate <= 0 {
+		return nil
+	}
+	return &BandwidthStats{
+		LimitBytesPerSec: int64(l.rate),
+		SentBytes:        l.sent,
+		WaitSeconds:      l.waited.Seconds(),
+	}
+}
+
+// reader paces r through the limiter
+func (l *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	return &throttledReader{ctx: ctx, r: r, limiter: l}
+}
+
+// throttledReader hands out at most bandwidthChunk bytes per read, each once the
+// limiter allows it
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *bandwidthLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > bandwidthChunk {
+		p = p[:bandwidthChunk]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.wait(t.ctx, n); werr != nil {
+			return 0, werr
+		}
+	}
+	return n, err
+}
+
+// SetBandwidthLimit caps the total upload rate across all concurrent uploads, in
+// bytes per second (0 = unlimited). Paced uploads in progress adjust with their next
+// chunk; others from their next upload.
+func (w *UploadWorker) SetBandwidthLimit(bytesPerSec int64) {
+	w.bandwidth.setLimit(bytesPerSec)
+}
+
+// send uploads data within the bandwidth limit. Clients that cannot pace their
+// writes wait for the whole frame's allowance before sending it.
+func (w *UploadWorker) send(uploader upload.Client, remotePath string, data []byte) error {
+	if w.bandwidth.limit() <= 0 {
+		return uploader.Upload(remotePath, data)
+	}
+	if tu, ok := uploader.(upload.ThrottledUploader); ok {
+		return tu.UploadThrottled(remotePath, data, func(r io.Reader) io.Reader {
+			return w.bandwidth.reader(w.ctx, r)
+		})
+	}
+	if err := w.bandwidth.wait(w.ctx, len(data)); err != nil {
+		return err
+	}
+	return uploader.Upload(remotePath, data)
+}
+
+// bandwidthTimeoutRate returns the slowest rate, in bytes per second, an upload can
+// expect under the bandwidth limit with every slot busy (0 = unlimited)
+func (w *UploadWorker) bandwidthTimeoutRate() int {
+	limit := w.bandwidth.limit()
+	if limit <= 0 {
+		return 0
+	}
+	rate := int(limit) / max(w.maxConcurrent, 1)
+	if rate < 1 {
+		rate = 1
+	}
+	return rate
+}
@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+// throttledUploader reads uploads through the pacing reader it is given
+type throttledUploader struct {
+	mockUploader
+	received int
+}
+
+func (u *throttledUploader) UploadThrottled(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	n, err := io.Copy(io.Discard, throttle(bytes.NewReader(data)))
+	u.received += int(n)
+	return err
+}
+
+func TestBandwidthLimiter_Paces(t *testing.T) {
+	l := &bandwidthLimiter{}
+	l.setLimit(100 * 1024)
+
+	// The first second's worth is the burst; the next 20 KB takes ~200ms
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.wait(context.Background(), 20*1024); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("120 KB at 100 KB/s with a 100 KB burst took %v, want ~200ms", elapsed)
+	}
+
+	stats := l.stats()
+	if stats == nil || stats.LimitBytesPerSec != 100*1024 || stats.SentBytes != 120*1024 || stats.WaitSeconds <= 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestBandwidthLimiter_Unlimited(t *testing.T) {
+	l := &bandwidthLimiter{}
+	start := time.Now()
+	for i := 0; i < 100; i++ {
+		_ = l.wait(context.Background(), 1<<20)
+	}
+	if time.Since(start) > 100*time.Millisecond {
+		t.Error("an unlimited limiter should not wait")
+	}
+	if l.stats() != nil {
+		t.Error("expected no stats while unlimited")
+	}
+}
+
+func TestBandwidthLimiter_Cancel(t *testing.T) {
+	l := &bandwidthLimiter{}
+	l.setLimit(1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx, 10*1024); err == nil {
+		t.Error("expected a cancelled wait to fail")
+	}
+}
+
+func TestUploadWorker_SendThrottled(t *testing.T) {
+	w := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 2, MaxBytesPerSecond: 64 * 1024})
+	defer w.Stop()
+
+	uploader := &throttledUploader{}
+	data := make([]byte, 96*1024)
+	start := time.Now()
+	if err := w.send(uploader, "a.jpg", data); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if uploader.received != len(data) {
+		t.Errorf("received %d bytes, want %d", uploader.received, len(data))
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("96 KB at 64 KB/s with a 64 KB burst took %v, want ~500ms", elapsed)
+	}
+	if got := w.GetStats().Bandwidth; got == nil || got.LimitBytesPerSec != 64*1024 {
+		t.Errorf("Bandwidth stats = %+v", got)
+	}
+
+	// Removing the limit sends straight away and hides the stats
+	w.SetBandwidthLimit(0)
+	if err := w.send(&mockUploader{}, "b.jpg", data); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if w.GetStats().Bandwidth != nil {
+		t.Error("expected no bandwidth stats without a limit")
+	}
+	if rate := w.bandwidthTimeoutRate(); rate != 0 {
+		t.Errorf("bandwidthTimeoutRate = %d without a limit", rate)
+	}
+}
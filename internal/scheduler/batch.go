@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+// uploadBatch uploads a camera's batch of frames over one connection, each marked
+// uploaded on its own, stopping at the first failure; the rest stay queued for a
+// later tick. Uploaders that cannot hold a connection open upload frame by frame.
+func (w *UploadWorker) uploadBatch(workerID int, task uploadTask) {
+	frames := append([]uploadTask{task}, task.more...)
+	var session *sessionUploader
+	if opener, ok := task.uploader.(upload.Sessioner); ok {
+		session = &sessionUploader{client: task.uploader, opener: opener}
+		defer session.close()
+	}
+
+	for i, frame := range frames {
+		frame.more = nil
+		if session != nil {
+			frame.uploader = session
+		}
+		if !w.uploadFrame(workerID, frame) {
+			return
+		}
+		if i > 0 && session != nil {
+			w.mu.Lock()
+			w.batchedUploads++
+			w.mu.Unlock()
+		}
+	}
+}
+
+// sessionUploader sends a batch's uploads over one upload.Session, opened by the
+// first upload so that its login is accounted for like any other
+type sessionUploader struct {
+	client upload.Client
+	opener upload.Sessioner
+
+	mu      sync.Mutex // Held for each upload, so a timed-out one finishes before close
+	session upload.Session
+	closed  bool
+}
+
+func (s *sessionUploader) Upload(remotePath string, data []byte) error {
+	return s.UploadThrottled(remotePath, data, nil)
+}
+
+func (s *sessionUploader) UploadThrottled(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		// An abandoned upload's retry, still within the bandwidth limit
+		if throttle != nil {
+			if t, ok := s.client.(upload.ThrottledUploader); ok {
+				return t.UploadThrottled(remotePath, data, throttle)
+			}
+			if _, err := io.Copy(io.Discard, throttle(bytes.NewReader(data))); err != nil {
+				return err
+			}
+		}
+		return s.client.Upload(remotePath, data)
+	}
+	if s.session == nil {
+		session, err := s.opener.OpenSession()
+		if err != nil {
+			return err
+		}
+		s.session = session
+	}
+	err := s.session.Upload(remotePath, data, throttle)
+	if err != nil {
+		// A failed upload ends the session; a retry opens a new one, spaced by
+		// waitToConnect since the uploader is no longer connected
+		_ = s.session.Close() // Best-effort
+		s.session = nil
+	}
+	return err
+}
+
+// TestConnection is not needed for uploads; the session is tested by using it
+func (s *sessionUploader) TestConnection() error {
+	return nil
+}
+
+// connected reports whether the session is open, so an upload needs no new login
+func (s *sessionUploader) connected() bool {
+	if !s.mu.TryLock() {
+		return true // An upload is using it
+	}
+	defer s.mu.Unlock()
+	return s.session != nil && !s.closed
+}
+
+// close closes the session. An upload abandoned at its deadline may still hold it;
+// the session is then closed once that upload returns.
+func (s *sessionUploader) close() {
+	if s.mu.TryLock() {
+		s.closeLocked()
+		s.mu.Unlock()
+		return
+	}
+	go func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closeLocked()
+	}()
+}
+
+func (s *sessionUploader) closeLocked() {
+	s.closed = true
+	if s.session != nil {
+		_ = s.session.Close() // Best-effort
+	}
+}
+
+// waitToConnect spaces out a new connection (see waitForConnection), unless
+// uploader is a batch session that is already connected
+func (w *UploadWorker) waitToConnect(uploader upload.Client) {
+	if s, ok := uploader.(*sessionUploader); ok && s.connected() {
+		return
+	}
+	w.waitForConnection()
+}
@@ -0,0 +1,183 @@
+package scheduler
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+// batchUploader records uploaded paths and counts the sessions opened. The first
+// drops session uploads fail as if the connection was lost.
+type batchUploader struct {
+	pathUploader
+	sessions int
+	closed   int
+	drops    int
+}
+
+func (b *batchUploader) OpenSession() (upload.Session, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sessions++
+	return &batchSession{b}, nil
+}
+
+type batchSession struct{ b *batchUploader }
+
+func (s *batchSession) Upload(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	s.b.mu.Lock()
+	drop := s.b.drops > 0
+	if drop {
+		s.b.drops--
+	}
+	s.b.mu.Unlock()
+	if drop {
+		return errors.New("connection lost")
+	}
+	return s.b.Upload(remotePath, data)
+}
+
+func (s *batchSession) Close() error {
+	s.b.mu.Lock()
+	defer s.b.mu.Unlock()
+	s.b.closed++
+	return nil
+}
+
+// runScheduled schedules one tick of uploads and runs the resulting tasks
+func runScheduled(w *UploadWorker) []uploadTask {
+	ch := make(chan uploadTask, 4)
+	w.scheduleUploads(ch)
+	close(ch)
+	var tasks []uploadTask
+	for task := range ch {
+		tasks = append(tasks, task)
+	}
+	run := make(chan uploadTask, len(tasks))
+	for _, task := range tasks {
+		run <- task
+	}
+	close(run)
+	w.uploadWorkerRoutine(0, run)
+	return tasks
+}
+
+func TestUploadWorker_BatchUploadsOverOneSession(t *testing.T) {
+	q := newTestQueue(t, "cam")
+	base := time.Now().UTC().Add(-time.Minute)
+	for i := 0; i < 5; i++ {
+		if err := q.Enqueue(minimalTestJPEG(), base.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	uploader := &batchUploader{}
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: time.Millisecond, RetryDelay: time.Millisecond})
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam", RemotePath: "kspb/", UploadBatchSize: 3}, uploader)
+
+	tasks := runScheduled(worker)
+	if len(tasks) != 1 || len(tasks[0].more) != 2 {
+		t.Fatalf("scheduled %d tasks, want 1 batch of 3", len(tasks))
+	}
+	if len(uploader.paths) != 3 || uploader.sessions != 1 || uploader.closed != 1 {
+		t.Errorf("uploads = %d, sessions = %d, closed = %d; want 3 uploads over 1 session", len(uploader.paths), uploader.sessions, uploader.closed)
+	}
+	if got := q.GetImageCount(); got != 2 {
+		t.Errorf("queue has %d frames, want 2 left", got)
+	}
+	if stats := worker.GetStats(); stats.BatchedUploads != 2 || stats.UploadsSuccess != 3 {
+		t.Errorf("batched = %d, success = %d; want 2 and 3", stats.BatchedUploads, stats.UploadsSuccess)
+	}
+
+	// A failure ends the batch; the frames after it stay queued
+	images, _ := q.DequeueBatch(1, false)
+	uploader.fail = map[string]bool{worker.buildRemotePath("kspb/", "cam", images[0].Timestamp, ""): true}
+	runScheduled(worker)
+	if got := q.GetImageCount(); got != 2 {
+		t.Errorf("queue has %d frames after a failed batch, want 2", got)
+	}
+	worker.inFlightMu.Lock()
+	inFlight := len(worker.inFlight)
+	worker.inFlightMu.Unlock()
+	if inFlight != 0 {
+		t.Errorf("%d frames left in flight", inFlight)
+	}
+}
+
+func TestUploadWorker_BatchWithoutSessions(t *testing.T) {
+	q := newTestQueue(t, "cam")
+	base := time.Now().UTC().Add(-time.Minute)
+	for i := 0; i < 3; i++ {
+		if err := q.Enqueue(minimalTestJPEG(), base.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	// Uploaders that cannot hold a connection still upload the whole batch
+	uploader := &pathUploader{}
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: time.Millisecond})
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam", RemotePath: "kspb/", UploadBatchSize: 5}, uploader)
+
+	runScheduled(worker)
+	if len(uploader.paths) != 3 || q.GetImageCount() != 0 {
+		t.Errorf("uploads = %d, queued = %d; want all 3 uploaded", len(uploader.paths), q.GetImageCount())
+	}
+	if stats := worker.GetStats(); stats.BatchedUploads != 0 {
+		t.Errorf("batched = %d without sessions, want 0", stats.BatchedUploads)
+	}
+}
+
+func TestSessionUploader_ClosedRetryStaysThrottled(t *testing.T) {
+	client := &throttledUploader{}
+	s := &sessionUploader{client: client, opener: &batchUploader{}, closed: true}
+
+	paced := 0
+	throttle := func(r io.Reader) io.Reader { paced++; return r }
+	if err := s.UploadThrottled("cam/1.jpg", make([]byte, 1024), throttle); err != nil {
+		t.Fatalf("UploadThrottled: %v", err)
+	}
+	if paced != 1 || client.received != 1024 {
+		t.Errorf("paced = %d, received = %d; want the retry sent through the throttle", paced, client.received)
+	}
+}
+
+func TestSessionUploader_FailureEndsSession(t *testing.T) {
+	b := &batchUploader{pathUploader: pathUploader{fail: map[string]bool{"cam/1.jpg": true}}}
+	s := &sessionUploader{client: b, opener: b}
+
+	if err := s.Upload("cam/1.jpg", []byte("jpeg")); err == nil {
+		t.Fatal("expected the upload to fail")
+	}
+	if s.connected() || b.closed != 1 {
+		t.Errorf("connected = %v, closed = %d; want the failed session closed", s.connected(), b.closed)
+	}
+	if err := s.Upload("cam/2.jpg", []byte("jpeg")); err != nil || b.sessions != 2 {
+		t.Errorf("err = %v, sessions = %d; want the next upload on a new session", err, b.sessions)
+	}
+}
+
+func TestUploadWorker_SessionRetryWaitsToConnect(t *testing.T) {
+	q := newTestQueue(t, "cam")
+	base := time.Now().UTC().Add(-time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(minimalTestJPEG(), base.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	const interval = 200 * time.Millisecond
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: interval, RetryDelay: time.Millisecond})
+	uploader := &batchUploader{drops: 1}
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam", RemotePath: "kspb/", UploadBatchSize: 3}, uploader)
+
+	// The retry logs in again, so it is spaced like any new connection
+	start := time.Now()
+	runScheduled(worker)
+	if elapsed := time.Since(start); elapsed < interval || uploader.sessions != 2 || q.GetImageCount() != 0 {
+		t.Errorf("elapsed = %v, sessions = %d, queued = %d; want the retry on a new session after %v",
+			elapsed, uploader.sessions, q.GetImageCount(), interval)
+	}
+}
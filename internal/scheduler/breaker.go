@@ -0,0 +1,223 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+// CircuitBreaker configures the per-server upload circuit breaker
+type CircuitBreaker struct {
+	FailureThreshold int           // Consecutive failures that open the breaker (default: 5)
+	Cooldown         time.Duration // Time open before a probe upload is allowed (default: 60s)
+}
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 60 * time.Second
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"    // Uploads run normally
+	BreakerOpen     = "open"      // Server unreachable; uploads are not attempted
+	BreakerHalfOpen = "half_open" // Cooldown over; one probe upload tests recovery
+)
+
+// BreakerStatus describes the circuit breaker of one upload server
+type BreakerStatus struct {
+	State               string    `json:"state"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	OpenedAt            time.Time `json:"opened_at,omitempty"` // While open or half-open
+	Opens               int64     `json:"opens"`
+}
+
+// serverBreaker is the breaker state of one upload server
+type serverBreaker struct {
+	status  BreakerStatus
+	probing bool // A half-open probe upload is in flight
+}
+
+// breakers tracks one circuit breaker per upload server, shared by every camera
+// uploading to it. Only failures to reach the server count: a server that answers,
+// even with an auth or write error, is up.
+type breakers struct {
+	config  CircuitBreaker
+	servers map[string]*serverBreaker
+}
+
+// newBreakers applies defaults to cfg
+func newBreakers(cfg CircuitBreaker) *breakers {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultBreakerThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultBreakerCooldown
+	}
+	return &breakers{config: cfg, servers: make(map[string]*serverBreaker)}
+}
+
+// ready reports whether an upload to server could start now: the breaker is
+// closed, or its cooldown is over and no probe is in flight
+func (b *breakers) ready(server string, now time.Time) bool {
+	s := b.servers[server]
+	if s == nil || s.status.State == BreakerClosed {
+		return true
+	}
+	if s.probing {
+		return false
+	}
+	return s.status.State == BreakerHalfOpen || now.Sub(s.status.OpenedAt) >= b.config.Cooldown
+}
+
+// allow is ready, but also starts the half-open probe when the breaker is not
+// closed, so later callers wait for its result
+func (b *breakers) allow(server string, now time.Time) bool {
+	if !b.ready(server, now) {
+		return false
+	}
+	if s := b.servers[server]; s != nil && s.status.State != BreakerClosed {
+		s.status.State = BreakerHalfOpen
+		s.probing = true
+	}
+	return true
+}
+
+// record updates server's breaker after an upload and returns the new state when
+// it changed. reached is false when the server could not be reached at all.
+func (b *breakers) record(server string, reached bool, now time.Time) (string, bool) {
+	s := b.servers[server]
+	if s == nil {
+		if reached {
+			return "", false
+		}
+		s = &serverBreaker{status: BreakerStatus{State: BreakerClosed}}
+		b.servers[server] = s
+	}
+	before := s.status.State
+	s.probing = false
+	if reached {
+		s.status.State = BreakerClosed
+		s.status.ConsecutiveFailures = 0
+		s.status.OpenedAt = time.Time{}
+	} else {
+		s.status.ConsecutiveFailures++
+		if before == BreakerHalfOpen || (before == BreakerClosed && s.status.ConsecutiveFailures >= b.config.FailureThreshold) {
+			s.status.State = BreakerOpen
+			s.status.OpenedAt = now
+			s.status.Opens++
+		}
+	}
+	return s.status.State, s.status.State != before
+}
+
+// release ends a probe whose upload never reached the server (e.g. the queued file
+// could not be read), so the next upload can probe instead
+func (b *breakers) release(server string) {
+	if s := b.servers[server]; s != nil {
+		s.probing = false
+	}
+}
+
+// snapshot returns the status of every server that has failed since startup
+func (b *breakers) snapshot() map[string]BreakerStatus {
+	if len(b.servers) == 0 {
+		return nil
+	}
+	result := make(map[string]BreakerStatus, len(b.servers))
+	for server, s := range b.servers {
+		result[server] = s.status
+	}
+	return result
+}
+
+// serverUnreachable reports whether err means the upload server could not be
+// reached: a failed connection (other than a rejected login) or a timeout
+func serverUnreachable(err error, isAuth bool) bool {
+	if err == nil || isAuth {
+		return false
+	}
+	var connErr *upload.ConnectionError
+	var timeoutErr *upload.TimeoutError
+	return errors.As(err, &connErr) || errors.As(err, &timeoutErr) || errors.Is(err, errUploadDeadline)
+}
+
+// uploadServer returns the server a camera uploads to
+func (w *UploadWorker) uploadServer(cameraID string) string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.configs[cameraID].UploadServer
+}
+
+// breakerReady reports whether uploads to server may be scheduled
+func (w *UploadWorker) breakerReady(server string) bool {
+	if server == "" {
+		return true
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.breakers == nil || w.breakers.ready(server, time.Now())
+}
+
+// breakerAllow claims an upload to server, taking the half-open probe if due
+func (w *UploadWorker) breakerAllow(server string) bool {
+	if server == "" {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.breakers == nil {
+		return true
+	}
+	before := w.breakers.servers[server]
+	probe := before != nil && before.status.State == BreakerOpen
+	if !w.breakers.allow(server, time.Now()) {
+		return false
+	}
+	if probe {
+		w.logger.Info("Probing upload server after circuit breaker cooldown", "server", server)
+	}
+	return true
+}
+
+// breakerRelease gives back an upload claimed with breakerAllow that never ran
+func (w *UploadWorker) breakerRelease(server string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.breakers != nil {
+		w.breakers.release(server)
+	}
+}
+
+// recordBreaker feeds an upload outcome to the camera's server breaker, logging
+// when it opens or closes
+func (w *UploadWorker) recordBreaker(cameraID string, reached bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	server := w.configs[cameraID].UploadServer
+	if w.breakers == nil || server == "" {
+		return
+	}
+	state, changed := w.breakers.record(server, reached, time.Now())
+	if !changed {
+		return
+	}
+	switch state {
+	case BreakerOpen:
+		w.logger.Warn("Upload server unreachable - circuit breaker open, pausing its uploads",
+			"server", server,
+			"consecutive_failures", w.breakers.servers[server].status.ConsecutiveFailures,
+			"cooldown", w.breakers.config.Cooldown)
+	case BreakerClosed:
+		w.logger.Info("Upload server reachable again - circuit breaker closed", "server", server)
+	}
+}
+
+// breakerStats returns breaker status per server (caller must hold lock)
+func (w *UploadWorker) breakerStats() map[string]BreakerStatus {
+	if w.breakers == nil {
+		return nil
+	}
+	return w.breakers.snapshot()
+}
@@ -0,0 +1,129 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+func TestBreakers_StateMachine(t *testing.T) {
+	b := newBreakers(CircuitBreaker{FailureThreshold: 2, Cooldown: time.Minute})
+	start := time.Now()
+	const server = "upload.example.com:2222"
+
+	b.record(server, false, start)
+	if !b.allow(server, start) {
+		t.Fatal("breaker opened before the threshold")
+	}
+	if state, changed := b.record(server, false, start); state != BreakerOpen || !changed {
+		t.Fatalf("after threshold: %s, changed=%v", state, changed)
+	}
+	if b.ready(server, start.Add(30*time.Second)) {
+		t.Error("open breaker allowed an upload during cooldown")
+	}
+
+	// After the cooldown one probe goes through; others wait for it
+	after := start.Add(time.Minute)
+	if !b.allow(server, after) || b.allow(server, after) {
+		t.Fatal("want exactly one half-open probe")
+	}
+	if got := b.snapshot()[server].State; got != BreakerHalfOpen {
+		t.Errorf("state = %s, want half_open", got)
+	}
+
+	// A failed probe reopens; a released probe lets the next upload probe
+	if state, _ := b.record(server, false, after); state != BreakerOpen {
+		t.Fatalf("failed probe left %s", state)
+	}
+	later := after.Add(time.Minute)
+	b.allow(server, later)
+	b.release(server)
+	if !b.allow(server, later) {
+		t.Fatal("released probe was not handed to the next upload")
+	}
+	if state, changed := b.record(server, true, later); state != BreakerClosed || !changed {
+		t.Errorf("successful probe: %s, changed=%v", state, changed)
+	}
+	if s := b.snapshot()[server]; s.Opens != 2 || s.ConsecutiveFailures != 0 || !s.OpenedAt.IsZero() {
+		t.Errorf("status = %+v", s)
+	}
+}
+
+func TestServerUnreachable(t *testing.T) {
+	connErr := &upload.ConnectionError{Message: "ssh dial", Err: errors.New("connection refused")}
+	tests := []struct {
+		err    error
+		isAuth bool
+		want   bool
+	}{
+		{nil, false, false},
+		{connErr, false, true},
+		{fmt.Errorf("wrapped: %w", connErr), false, true},
+		{connErr, true, false}, // Rejected login: the server answered
+		{fmt.Errorf("%w after 3m0s", errUploadDeadline), false, true},
+		{errors.New("sftp: permission denied"), false, false},
+	}
+	for _, tt := range tests {
+		if got := serverUnreachable(tt.err, tt.isAuth); got != tt.want {
+			t.Errorf("serverUnreachable(%v, %v) = %v, want %v", tt.err, tt.isAuth, got, tt.want)
+		}
+	}
+}
+
+func TestUploadWorker_CircuitBreakerSharedByServer(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	worker := NewUploadWorker(UploadWorkerConfig{
+		RetryDelay:         time.Millisecond,
+		ConnectionInterval: time.Millisecond,
+		CircuitBreaker:     &CircuitBreaker{FailureThreshold: 1, Cooldown: time.Hour},
+	})
+	down := &mockUploader{err: &upload.ConnectionError{Message: "ssh dial", Err: errors.New("connection refused")}}
+	for _, id := range []string{"north", "south", "other"} {
+		q, _ := queueMgr.CreateQueue(id, queue.DefaultQueueConfig())
+		q.Enqueue(minimalTestJPEG(), time.Now(), "bridge_clock", "high")
+		server := "down.example.com:2222"
+		uploader := upload.Client(down)
+		if id == "other" {
+			server, uploader = "up.example.com:2222", &mockUploader{}
+		}
+		worker.AddQueue(id, q, CameraConfig{ID: id, UploadServer: server}, uploader)
+	}
+
+	img, _ := worker.queues["north"].DequeueBatch(1, false)
+	if err := worker.uploadWithRetry("north", down, img[0], "north/1.jpg"); err == nil {
+		t.Fatal("upload to a down server succeeded")
+	}
+
+	// south shares the down server, so only the other server's camera is scheduled
+	workChan := make(chan uploadTask, 4)
+	worker.scheduleUploads(workChan)
+	close(workChan)
+	var scheduled []string
+	for task := range workChan {
+		scheduled = append(scheduled, task.cameraID)
+	}
+	if len(scheduled) != 1 || scheduled[0] != "other" {
+		t.Errorf("scheduled %v, want only other", scheduled)
+	}
+
+	stats := worker.GetStats().CircuitBreakers
+	if s := stats["down.example.com:2222"]; s.State != BreakerOpen || s.Opens != 1 {
+		t.Errorf("down server breaker = %+v", s)
+	}
+	if _, ok := stats["up.example.com:2222"]; ok {
+		t.Error("server that never failed should not be listed")
+	}
+}
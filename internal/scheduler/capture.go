@@ -2,12 +2,15 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"runtime/debug"
 	"sync"
 	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
 	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
@@ -18,9 +21,12 @@ type CaptureWorker struct {
 	camera          camera.Camera
 	config          CameraConfig
 	queue           *queue.Queue
+	thumbQueue      *queue.Queue // nil unless a thumbnail rendition is configured
+	spectroQueue    *queue.Queue // nil unless spectrograms are configured
 	authority       *timepkg.Authority
-	exifHelper      *timepkg.ExifToolHelper
+	exifTool        func() *timepkg.ExifToolHelper // nil, or returning nil, while exiftool is missing
 	resourceLimiter *resource.Limiter
+	sequences       *SequenceStore // Frame numbers for CameraConfig.ExifSequence
 	state           *CameraState
 	interval        time.Duration
 	ctx             context.Context
@@ -34,22 +40,103 @@ type CaptureWorker struct {
 	capturesFailed     int64
 	exifReadFailed     int64
 	exifWriteFailed    int64
+	jpegRepaired       int64
+	exifStampFallbacks int64            // Frames stamped by the builtin injector
+	captureHangs       int64            // Captures abandoned by the watchdog
+	skippedOverlap     int64            // Scheduled captures skipped because the previous one had not finished
+	cameraBusy         bool             // A camera read is running, possibly one abandoned by the watchdog
+	thumbnailsFailed   int64            // Thumbnails not queued; the full image is unaffected
+	regionsFailed      int64            // Region crops not queued; likewise
+	stampMethods       map[string]int64 // Frames per stamping method
+	lastStampMethod    string
 	nextCaptureTime    time.Time
 	currentlyCapturing bool
 	lastCaptureTime    time.Time
+	lastCaptureStart   time.Time // For CameraConfig.MinInterval
+	minIntervalHeld    int64     // Capture requests deferred or coalesced by MinInterval
+
+	// Time-unhealthy handling
+	timePolicy     string
+	timePaused     bool
+	lastConfidence timepkg.Confidence
+	lastTimeSource timepkg.TimeSource
+
+	// Handling of frames older than the newest queued frame (clock stepped backward)
+	regressionPolicy    string
+	regressionTolerance time.Duration
+
+	// Phase breakdown of the last completed capture cycle
+	lastTiming *CaptureTiming
+
+	// Quality self-check (sampled frames)
+	qualityAccum  float64
+	qualitySeries []QualitySample
+
+	// Repeated-frame suppression (nil when disabled)
+	frames             *frameHistory
+	repetitionDetected bool
+	framesSuppressed   int64
+
+	// Cycles where the camera had no new image (folder cameras, HTTP 304)
+	emptyCaptures int64
+	notModified   int64 // Empty captures answered 304 by the camera
+
+	// Event-triggered capture (cameras implementing camera.EventSource)
+	trigger       chan string
+	eventCaptures int64
+
+	// Restarts the capture interval after a suspend/resume or clock jump
+	realign chan struct{}
+
+	// Settle delay before the first capture (zero settleUntil once settled)
+	settled     bool
+	settleUntil time.Time
+
+	// Offline image (CameraConfig.OfflineImage)
+	failingSince  time.Time // Start of the current failure streak; zero after a success
+	offlineActive bool      // The offline image is being queued
+	offlineQueued int64
+
+	// Region crops, queued by region name
+	regionQueues map[string]*queue.Queue
+
+	// Audio spectrograms (spectroQueue set)
+	spectro        SpectrogramStats
+	spectroRunning bool // A spectrogram is being recorded
+
+	// Slowed capture during upload outages (CameraConfig.OutageSlowdown)
+	uploadFailingSince func() time.Time // Start of the camera's failed upload streak; zero if none
+	outage             outageState
+
+	// Smoothed capture rate (CameraConfig.ThroughputHalfLife)
+	captured *rateMeter
+
+	// Queue storage state last reported (see reportQueueStorage)
+	queueReadOnly bool
+	queueFallback string
+
+	// Post-capture hook runs (CameraConfig.PostCaptureHook)
+	hook hookState
 }
 
 // CaptureWorkerConfig configures a capture worker
 type CaptureWorkerConfig struct {
-	Camera          camera.Camera
-	CameraConfig    CameraConfig
-	Queue           *queue.Queue
-	Authority       *timepkg.Authority
-	ExifHelper      *timepkg.ExifToolHelper
-	ResourceLimiter *resource.Limiter // Optional: limits concurrent CPU-intensive work
-	IntervalSecs    int               // Capture interval in seconds (1-1800, default 60)
-	Logger          Logger
-	OnCapture       func(cameraID string, imageData []byte, captureTime time.Time) // Called after successful capture and processing
+	Camera              camera.Camera
+	CameraConfig        CameraConfig
+	Queue               *queue.Queue
+	ThumbnailQueue      *queue.Queue // Required when CameraConfig.Thumbnail is set
+	SpectrogramQueue    *queue.Queue // Required when CameraConfig.Spectrogram is set
+	RegionQueues        map[string]*queue.Queue
+	Authority           *timepkg.Authority
+	ExifTool            func() *timepkg.ExifToolHelper
+	ResourceLimiter     *resource.Limiter // Optional: limits concurrent CPU-intensive work
+	Sequences           *SequenceStore    // Required for CameraConfig.ExifSequence
+	IntervalSecs        int               // Capture interval in seconds (1-1800, default 60)
+	TimePolicy          string            // Behavior while time is unhealthy (default stamp_low)
+	RegressionPolicy    string            // Timestamp regression handling: clamp (default), reject or auto
+	RegressionTolerance time.Duration     // Regressions up to this are ignored (default 2s)
+	Logger              Logger
+	OnCapture           func(cameraID string, imageData []byte, captureTime time.Time) // Called after successful capture and processing
 }
 
 // NewCaptureWorker creates a new capture worker for a camera
@@ -68,19 +155,36 @@ func NewCaptureWorker(cfg CaptureWorkerConfig) *CaptureWorker {
 	if logger == nil {
 		logger = &defaultLogger{}
 	}
+	if floor := cfg.CameraConfig.MinInterval; interval < floor {
+		logger.Warn("Capture interval below the camera's minimum interval - using the minimum",
+			"camera", cfg.Camera.ID(),
+			"interval", interval,
+			"min_interval", floor)
+		interval = floor
+	}
 
 	return &CaptureWorker{
-		camera:          cfg.Camera,
-		config:          cfg.CameraConfig,
-		queue:           cfg.Queue,
-		authority:       cfg.Authority,
-		exifHelper:      cfg.ExifHelper,
-		resourceLimiter: cfg.ResourceLimiter,
-		interval:        interval,
-		ctx:             ctx,
-		cancel:          cancel,
-		logger:          logger,
-		onCapture:       cfg.OnCapture,
+		camera:              cfg.Camera,
+		config:              cfg.CameraConfig,
+		queue:               cfg.Queue,
+		thumbQueue:          cfg.ThumbnailQueue,
+		spectroQueue:        cfg.SpectrogramQueue,
+		regionQueues:        cfg.RegionQueues,
+		authority:           cfg.Authority,
+		exifTool:            cfg.ExifTool,
+		resourceLimiter:     cfg.ResourceLimiter,
+		sequences:           cfg.Sequences,
+		interval:            interval,
+		ctx:                 ctx,
+		cancel:              cancel,
+		logger:              logger,
+		onCapture:           cfg.OnCapture,
+		timePolicy:          NormalizeTimePolicy(cfg.TimePolicy),
+		regressionPolicy:    NormalizeRegressionPolicy(cfg.RegressionPolicy),
+		regressionTolerance: cfg.RegressionTolerance,
+		frames:              newFrameHistory(cfg.CameraConfig.DedupWindow),
+		trigger:             make(chan string, 1),
+		realign:             make(chan struct{}, 1),
 		state: &CameraState{
 			CameraID:    cfg.Camera.ID(),
 			NextAttempt: time.Now(),
@@ -91,6 +195,36 @@ func NewCaptureWorker(cfg CaptureWorkerConfig) *CaptureWorker {
 // Start begins the capture loop
 func (w *CaptureWorker) Start() {
 	go w.run()
+	if src, ok := w.eventSource(); ok {
+		go src.WatchEvents(w.ctx, w.TriggerCapture)
+	}
+}
+
+// eventSource returns the camera's event source when event-triggered capture is enabled
+func (w *CaptureWorker) eventSource() (camera.EventSource, bool) {
+	src, ok := w.camera.(camera.EventSource)
+	if !ok || !src.EventsEnabled() {
+		return nil, false
+	}
+	return src, true
+}
+
+// TriggerCapture requests an immediate capture in addition to the interval schedule.
+// Requests arriving while one is pending are coalesced.
+func (w *CaptureWorker) TriggerCapture(reason string) {
+	select {
+	case w.trigger <- reason:
+	default:
+	}
+}
+
+// Realign restarts the capture interval from now with one fresh capture, instead of
+// running the captures missed while the system was suspended or the clock jumped
+func (w *CaptureWorker) Realign() {
+	select {
+	case w.realign <- struct{}{}:
+	default:
+	}
 }
 
 // Stop stops the capture worker gracefully
@@ -115,26 +249,150 @@ func (w *CaptureWorker) GetStats() CaptureStats {
 		CapturesFailed:     w.capturesFailed,
 		ExifReadFailed:     w.exifReadFailed,
 		ExifWriteFailed:    w.exifWriteFailed,
+		JPEGRepaired:       w.jpegRepaired,
+		ExifStampFallbacks: w.exifStampFallbacks,
+		CaptureHangs:       w.captureHangs,
+		SkippedOverlap:     w.skippedOverlap,
+		MinInterval:        w.config.MinInterval,
+		MinIntervalHeld:    w.minIntervalHeld,
+		Sequence:           w.lastSequence(),
+		ThumbnailsFailed:   w.thumbnailsFailed,
+		RegionsFailed:      w.regionsFailed,
+		ExifStampMethods:   copyCounts(w.stampMethods),
+		LastStampMethod:    w.lastStampMethod,
+		RepetitionDetected: w.repetitionDetected,
+		FramesSuppressed:   w.framesSuppressed,
+		EmptyCaptures:      w.emptyCaptures,
+		NotModified:        w.notModified,
 		Interval:           w.interval,
 		QueuePaused:        w.queue.IsCapturePaused(),
 		NextCaptureTime:    w.nextCaptureTime,
 		CurrentlyCapturing: w.currentlyCapturing,
 		LastCaptureTime:    w.lastCaptureTime,
+		LatestQuality:      w.latestQualityLocked(),
+		TimeConfidence:     string(w.lastConfidence),
+		TimeSource:         string(w.lastTimeSource),
+		TimePaused:         w.timePaused,
+		LastTiming:         w.lastTiming,
+		EventCaptures:      w.eventCaptures,
+		Events:             w.eventStatus(),
+		StreamReconnect:    w.reconnectStatus(),
+		Agent:              w.agentStatus(),
+		Rediscovery:        w.rediscoveryStatus(),
+		Settling:           w.isSettlingLocked(),
+		SettleUntil:        w.settleUntil,
+		Spectrogram:        w.spectrogramStats(),
+		OfflineActive:      w.offlineActive,
+		OfflineQueued:      w.offlineQueued,
+		OutageSlowdown:     w.outageStatusLocked(),
+		Throughput:         w.throughputLocked(time.Now()),
+		PostCaptureHook:    w.hookStatsLocked(),
+	}
+}
+
+// eventStatus returns the event subscription status, or nil when events are disabled
+func (w *CaptureWorker) eventStatus() *camera.EventStatus {
+	src, ok := w.eventSource()
+	if !ok {
+		return nil
 	}
+	status := src.EventStatus()
+	return &status
+}
+
+// reconnectStatus returns stream reconnect stats, or nil for cameras without a stream
+func (w *CaptureWorker) reconnectStatus() *camera.ReconnectStatus {
+	r, ok := camera.Underlying(w.camera).(camera.Reconnector)
+	if !ok {
+		return nil
+	}
+	status := r.ReconnectStatus()
+	return &status
+}
+
+// agentStatus returns reachability of the camera's bridge agent, or nil for cameras
+// captured directly
+func (w *CaptureWorker) agentStatus() *camera.AgentStatus {
+	a, ok := camera.Underlying(w.camera).(camera.AgentReporter)
+	if !ok {
+		return nil
+	}
+	status := a.AgentStatus()
+	return &status
+}
+
+// rediscoveryStatus returns the camera's configured and current address, or nil when
+// rediscovery is disabled
+func (w *CaptureWorker) rediscoveryStatus() *camera.RediscoveryStatus {
+	for cam := w.camera; cam != nil; {
+		if r, ok := cam.(camera.Rediscoverer); ok {
+			status := r.RediscoveryStatus()
+			return &status
+		}
+		wrapper, ok := cam.(interface{ Unwrap() camera.Camera })
+		if !ok {
+			break
+		}
+		cam = wrapper.Unwrap()
+	}
+	return nil
+}
+
+// latestQualityLocked returns the most recent quality sample (caller must hold lock)
+func (w *CaptureWorker) latestQualityLocked() *QualitySample {
+	if len(w.qualitySeries) == 0 {
+		return nil
+	}
+	latest := w.qualitySeries[len(w.qualitySeries)-1]
+	return &latest
 }
 
 // CaptureStats provides capture statistics
 type CaptureStats struct {
-	CameraID           string        `json:"camera_id"`
-	CapturesTotal      int64         `json:"captures_total"`
-	CapturesFailed     int64         `json:"captures_failed"`
-	ExifReadFailed     int64         `json:"exif_read_failed"`
-	ExifWriteFailed    int64         `json:"exif_write_failed"`
-	Interval           time.Duration `json:"interval"`
-	QueuePaused        bool          `json:"queue_paused"`
-	NextCaptureTime    time.Time     `json:"next_capture_time"`
-	CurrentlyCapturing bool          `json:"currently_capturing"`
-	LastCaptureTime    time.Time     `json:"last_capture_time"`
+	CameraID           string                    `json:"camera_id"`
+	CapturesTotal      int64                     `json:"captures_total"`
+	CapturesFailed     int64                     `json:"captures_failed"`
+	ExifReadFailed     int64                     `json:"exif_read_failed"`
+	ExifWriteFailed    int64                     `json:"exif_write_failed"`
+	JPEGRepaired       int64                     `json:"jpeg_repaired"`
+	ExifStampFallbacks int64                     `json:"exif_stamp_fallbacks"` // Stamped by the builtin injector after exiftool failed
+	ExifStampMethods   map[string]int64          `json:"exif_stamp_methods,omitempty"`
+	LastStampMethod    string                    `json:"last_stamp_method,omitempty"` // exiftool, builtin or none
+	CaptureHangs       int64                     `json:"capture_hang"`                // Captures abandoned after ignoring their timeout
+	SkippedOverlap     int64                     `json:"captures_skipped_overlap"`    // Scheduled captures skipped while the previous one was unfinished
+	MinInterval        time.Duration             `json:"min_interval,omitempty"`      // Floor between capture starts
+	MinIntervalHeld    int64                     `json:"min_interval_deferred"`       // Capture requests deferred or coalesced by the floor
+	Sequence           uint32                    `json:"sequence,omitempty"`          // Last frame number stamped (exif_sequence)
+	ThumbnailsFailed   int64                     `json:"thumbnails_failed,omitempty"` // Thumbnail renditions not queued
+	RegionsFailed      int64                     `json:"regions_failed,omitempty"`    // Region crops not queued
+	RepetitionDetected bool                      `json:"repetition_detected"`
+	FramesSuppressed   int64                     `json:"frames_suppressed"`        // Repeated frames not queued
+	EmptyCaptures      int64                     `json:"empty_captures,omitempty"` // Cycles with no new image to capture
+	NotModified        int64                     `json:"unchanged_304,omitempty"`  // Of which the camera answered 304 Not Modified
+	Interval           time.Duration             `json:"interval"`
+	QueuePaused        bool                      `json:"queue_paused"`
+	NextCaptureTime    time.Time                 `json:"next_capture_time"`
+	CurrentlyCapturing bool                      `json:"currently_capturing"`
+	LastCaptureTime    time.Time                 `json:"last_capture_time"`
+	LatestQuality      *QualitySample            `json:"latest_quality,omitempty"`
+	TimeConfidence     string                    `json:"time_confidence,omitempty"` // Of the last queued capture
+	TimeSource         string                    `json:"time_source,omitempty"`     // Clock the last queued capture's time came from
+	TimePaused         bool                      `json:"time_paused"`
+	LastTiming         *CaptureTiming            `json:"last_timing,omitempty"`
+	EventCaptures      int64                     `json:"event_captures"` // Captures triggered by camera events
+	Events             *camera.EventStatus       `json:"events,omitempty"`
+	StreamReconnect    *camera.ReconnectStatus   `json:"stream_reconnect,omitempty"` // Stream cameras only
+	Agent              *camera.AgentStatus       `json:"agent,omitempty"`            // Cameras captured through a bridge agent
+	Rediscovery        *camera.RediscoveryStatus `json:"rediscovery,omitempty"`      // Configured vs current address of DHCP cameras
+	Settling           bool                      `json:"settling"`                   // Waiting out the settle delay before the first capture
+	SettleUntil        time.Time                 `json:"settle_until,omitempty"`     // End of the settle delay while settling
+	OfflineActive      bool                      `json:"offline_image_active,omitempty"`
+	OfflineQueued      int64                     `json:"offline_images_queued,omitempty"` // Offline images queued in place of frames
+	Spectrogram        *SpectrogramStats         `json:"spectrogram,omitempty"`
+	OutageSlowdown     *OutageSlowdownStatus     `json:"outage_slowdown,omitempty"` // Cameras with an outage slowdown policy
+	Throughput         *Throughput               `json:"throughput,omitempty"`      // Achieved rates, when tracked
+
+	PostCaptureHook *PostCaptureHookStats `json:"post_capture_hook,omitempty"` // Cameras with a hook
 }
 
 func (w *CaptureWorker) run() {
@@ -158,6 +416,11 @@ func (w *CaptureWorker) run() {
 		}
 	}()
 
+	if !w.settle() {
+		w.logger.Info("Capture worker stopped", "camera", w.camera.ID())
+		return
+	}
+
 	ticker := time.NewTicker(w.interval)
 	defer ticker.Stop()
 
@@ -166,8 +429,24 @@ func (w *CaptureWorker) run() {
 	w.nextCaptureTime = time.Now().Add(w.interval)
 	w.mu.Unlock()
 
+	// A capture held back by the minimum interval; later requests join it
+	var deferred <-chan time.Time
+	floorAllows := func(reason string) bool {
+		wait := w.minIntervalWait(time.Now())
+		if wait == 0 {
+			return true
+		}
+		w.holdForMinInterval(reason, wait, deferred != nil)
+		if deferred == nil {
+			deferred = time.After(wait)
+		}
+		return false
+	}
+
 	// Initial capture
-	w.capture()
+	if floorAllows("initial") {
+		w.capture()
+	}
 
 	for {
 		select {
@@ -175,13 +454,17 @@ func (w *CaptureWorker) run() {
 			w.logger.Info("Capture worker stopped", "camera", w.camera.ID())
 			return
 
-		case <-ticker.C:
-			// Check if previous capture is still running
-			w.mu.RLock()
-			isCapturing := w.currentlyCapturing
-			w.mu.RUnlock()
+		case <-deferred:
+			deferred = nil
+			if w.captureBlocked() {
+				continue
+			}
+			if floorAllows("deferred") {
+				w.capture()
+			}
 
-			if isCapturing {
+		case tick := <-ticker.C:
+			if w.overlapsPrevious(tick) {
 				w.logger.Warn("Skipping capture - previous job still running",
 					"camera", w.camera.ID(),
 					"interval", w.interval)
@@ -200,16 +483,44 @@ func (w *CaptureWorker) run() {
 				continue
 			}
 
+			// Capture less often while uploads are down
+			if w.slowedForOutage(time.Now()) {
+				continue
+			}
+
 			// Check backoff
 			w.mu.RLock()
 			nextAttempt := w.state.NextAttempt
 			w.mu.RUnlock()
 
 			if time.Now().Before(nextAttempt) {
+				w.queueOfflineImage()
 				continue
 			}
 
-			w.capture()
+			if floorAllows("interval") {
+				w.capture()
+				w.queueOfflineImage() // Only if that capture failed too
+			}
+
+		case reason := <-w.trigger:
+			if w.eventCaptureAllowed() && floorAllows(reason) {
+				w.logger.Debug("Event-triggered capture", "camera", w.camera.ID(), "event", reason)
+				w.mu.Lock()
+				w.eventCaptures++
+				w.mu.Unlock()
+				w.capture()
+			}
+
+		case <-w.realign:
+			ticker.Reset(w.interval)
+			w.mu.Lock()
+			w.nextCaptureTime = time.Now().Add(w.interval)
+			w.mu.Unlock()
+			w.logger.Info("Capture schedule realigned after clock jump", "camera", w.camera.ID())
+			if !w.captureBlocked() && floorAllows("realign") {
+				w.capture()
+			}
 
 		case <-w.queue.ResumeCapture():
 			w.logger.Info("Capture resumed", "camera", w.camera.ID())
@@ -217,10 +528,91 @@ func (w *CaptureWorker) run() {
 	}
 }
 
+// overlapsPrevious reports whether a capture scheduled at tick would overlap the
+// previous one, counting it as skipped if so. That is the case while a camera read
+// abandoned by the watchdog is still running, and for a tick that fell due during a
+// slow capture and was held by the ticker, so a slow camera is never asked for two
+// frames at once or back to back.
+func (w *CaptureWorker) overlapsPrevious(tick time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.currentlyCapturing && !w.cameraBusy && !tick.Before(w.lastCaptureTime) {
+		return false
+	}
+	w.skippedOverlap++
+	return true
+}
+
+// eventCaptureAllowed applies the interval path's guards (queue pressure, backoff) plus
+// the event cooldown, so a chattering motion sensor cannot flood the queue
+func (w *CaptureWorker) eventCaptureAllowed() bool {
+	if w.queue.IsCapturePaused() {
+		return false
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	now := time.Now()
+	if w.currentlyCapturing || w.cameraBusy || now.Before(w.state.NextAttempt) {
+		return false
+	}
+	return w.lastCaptureTime.IsZero() || now.Sub(w.lastCaptureTime) >= w.config.EventCooldown
+}
+
+// Extra exiftool attempts before falling back to the builtin injector
+const (
+	defaultExifStampRetries = 1
+	maxExifStampRetries     = 3
+)
+
+// exifStampRetries returns the configured extra exiftool attempts
+func (w *CaptureWorker) exifStampRetries() int {
+	retries := w.config.ExifStampRetries
+	if retries <= 0 {
+		return defaultExifStampRetries
+	}
+	return min(retries, maxExifStampRetries)
+}
+
+// recordStampMethod counts how a frame was stamped
+func (w *CaptureWorker) recordStampMethod(result timepkg.EXIFStampResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stampMethods == nil {
+		w.stampMethods = make(map[string]int64)
+	}
+	w.stampMethods[result.Method]++
+	w.lastStampMethod = result.Method
+	switch result.Method {
+	case timepkg.StampMethodBuiltin:
+		w.exifStampFallbacks++
+	case timepkg.StampMethodNone:
+		w.exifWriteFailed++
+	}
+}
+
+// copyCounts copies a counter map (caller must hold the owning lock)
+func copyCounts(counts map[string]int64) map[string]int64 {
+	if len(counts) == 0 {
+		return nil
+	}
+	result := make(map[string]int64, len(counts))
+	for k, v := range counts {
+		result[k] = v
+	}
+	return result
+}
+
 func (w *CaptureWorker) capture() {
+	if w.pausedForTime() {
+		return
+	}
+	w.applyRegressionPolicy()
+
 	w.mu.Lock()
 	w.capturesTotal++
 	w.currentlyCapturing = true
+	w.lastCaptureStart = time.Now()
 	captureInterval := w.interval
 	w.mu.Unlock()
 
@@ -239,6 +631,9 @@ func (w *CaptureWorker) capture() {
 		w.mu.Unlock()
 	}()
 
+	timer := newPhaseTimer()
+	var timing CaptureTiming
+
 	// Adaptive throttling: delay if system is under pressure
 	// This protects the web UI by slowing down background work
 	if w.resourceLimiter != nil {
@@ -254,15 +649,27 @@ func (w *CaptureWorker) capture() {
 		}
 	}
 
+	timing.ThrottleMs = timer.lap()
+
 	// Record capture start time (bridge clock) for time authority
 	captureStartUTC := time.Now().UTC()
 
-	// Create context with timeout for camera capture (30s is reasonable for most cameras)
-	ctx, cancel := context.WithTimeout(jobCtx, 30*time.Second)
+	// Create context with timeout for camera capture (30s default is reasonable for most cameras)
+	ctx, cancel := context.WithTimeout(jobCtx, w.captureTimeout())
 	defer cancel()
 
-	// Capture image from camera
-	imageData, err := w.camera.Capture(ctx)
+	// Capture image from camera (large frames may be spooled straight to the queue directory)
+	imageData, spoolPath, err := w.captureWithWatchdog(ctx)
+	if errors.Is(err, camera.ErrNoImage) {
+		// Nothing new (e.g. an empty folder): skip the cycle without backing off
+		w.mu.Lock()
+		w.emptyCaptures++
+		if errors.Is(err, camera.ErrNotModified) {
+			w.notModified++
+		}
+		w.mu.Unlock()
+		return
+	}
 	if err != nil {
 		// Check if we hit the job timeout
 		if jobCtx.Err() == context.DeadlineExceeded {
@@ -275,10 +682,37 @@ func (w *CaptureWorker) capture() {
 		return
 	}
 
+	timing.FetchMs = timer.lap()
+
+	if spoolPath != "" {
+		w.finishSpooledCapture(jobCtx, spoolPath, captureStartUTC, timer, timing)
+		return
+	}
+
+	if w.config.RawConverter != nil {
+		converted, err := w.config.RawConverter.Convert(imageData)
+		if err != nil {
+			w.handleCaptureError(fmt.Errorf("convert raw frame: %w", err))
+			return
+		}
+		imageData = converted
+	}
+	if w.config.RepairJPEG {
+		imageData = w.repairJPEG(imageData)
+	}
+	if w.config.TrimJPEG {
+		imageData = w.trimJPEG(imageData)
+	}
+	timing.ProcessMs = timer.lap()
+
+	if w.isRepeatFrame(imageData) {
+		return
+	}
+
 	// Try to read camera EXIF timestamp (via exiftool)
 	// Use resource limiter to serialize exiftool operations
 	var cameraTime *time.Time
-	if w.exifHelper != nil {
+	if w.exifReader() != nil && w.config.TimeSource != timepkg.PreferBridge {
 		if w.resourceLimiter != nil {
 			if err := w.resourceLimiter.AcquireExifOperation(jobCtx); err != nil {
 				w.logger.Debug("Skipping EXIF read due to context cancellation",
@@ -292,31 +726,22 @@ func (w *CaptureWorker) capture() {
 		}
 	}
 
-	// Determine observation time using authority
-	var observation timepkg.ObservationResult
-	if w.authority != nil {
-		observation = w.authority.DetermineObservationTime(captureStartUTC, cameraTime)
-	} else {
-		observation = timepkg.ObservationResult{
-			Time:       captureStartUTC,
-			Source:     timepkg.SourceBridgeClock,
-			Confidence: timepkg.ConfidenceHigh,
-		}
-	}
+	observation := w.determineObservation(captureStartUTC, cameraTime)
+	timing.ExifReadMs = timer.lap()
 
-	// Log any time warnings
-	if observation.Warning != nil {
-		w.logger.Warn("Time observation warning",
-			"camera", w.camera.ID(),
-			"code", observation.Warning.Code,
-			"message", observation.Warning.Message)
-	}
+	// Regions crop the capture as received, masking it themselves
+	source := imageData
 
-	// Apply image processing if configured (resize/quality)
+	// Apply image processing if configured (resize/quality/privacy zones)
 	// Use resource limiter to limit concurrent CPU-intensive work
 	if w.config.ImageProcessor != nil {
 		if w.resourceLimiter != nil {
 			if err := w.resourceLimiter.AcquireImageProcessing(jobCtx); err != nil {
+				if w.config.ImageProcessor.Masks() {
+					w.logger.Warn("Privacy masking skipped due to context cancellation, frame dropped",
+						"camera", w.camera.ID())
+					return
+				}
 				w.logger.Warn("Image processing skipped due to context cancellation",
 					"camera", w.camera.ID())
 			} else {
@@ -326,38 +751,50 @@ func (w *CaptureWorker) capture() {
 				processedData, err := w.config.ImageProcessor.Process(imageData)
 				w.resourceLimiter.ReleaseImageProcessing()
 
-				if err != nil {
-					w.logger.Warn("Image processing failed, using original",
-						"camera", w.camera.ID(),
-						"error", err)
-				} else {
-					imageData = processedData
+				if !w.applyProcessed(&imageData, processedData, err) {
+					return
 				}
 			}
 		} else {
 			processedData, err := w.config.ImageProcessor.Process(imageData)
-			if err != nil {
-				w.logger.Warn("Image processing failed, using original",
-					"camera", w.camera.ID(),
-					"error", err)
-			} else {
-				imageData = processedData
+			if !w.applyProcessed(&imageData, processedData, err) {
+				return
 			}
 		}
 	}
 
-	// Stamp EXIF with bridge marker using exiftool
-	// Must use exiftool (not manual injection) for server compatibility
-	stampResult := timepkg.StampBridgeEXIFWithTool(imageData, observation)
-	if !stampResult.Stamped {
-		w.mu.Lock()
-		w.exifWriteFailed++
-		w.mu.Unlock()
-		w.logger.Warn("EXIF stamp failed, using original image",
-			"camera", w.camera.ID())
-		// Continue with original image data
-		stampResult.Data = imageData
+	hooked, ok := w.applyPostCaptureHook(jobCtx, imageData, observation)
+	if !ok {
+		return
 	}
+	imageData = hooked
+	timing.ProcessMs += timer.lap()
+
+	// Stamp EXIF with bridge marker using exiftool (preferred for server compatibility),
+	// retrying and then falling back to the builtin injector. Stamping must stay after
+	// processing: re-encoding drops EXIF, and the stamped dimensions describe these bytes
+	stampResult := timepkg.EXIFStampResult{Data: imageData, ObservationUTC: observation.Time}
+	var meta timepkg.FrameMeta
+	if w.shouldStamp(observation) {
+		meta = w.frameMeta()
+		stampResult = timepkg.StampBridgeEXIFWithFallback(imageData, observation, meta, w.exifStampRetries())
+		w.recordStampMethod(stampResult)
+		if !stampResult.Stamped {
+			w.logger.Warn("EXIF stamp failed, using original image",
+				"camera", w.camera.ID(),
+				"attempts", stampResult.Attempts)
+			// Continue with original image data
+			stampResult.Data = imageData
+		} else if stampResult.Method == timepkg.StampMethodBuiltin {
+			w.logger.Warn("exiftool stamping failed, used builtin EXIF injector",
+				"camera", w.camera.ID(),
+				"attempts", stampResult.Attempts)
+		}
+		if stampResult.Stamped {
+			stampResult.Data = w.commentJPEG(stampResult.Data, stampResult.Marker)
+		}
+	}
+	timing.ExifStampMs = timer.lap()
 
 	// Enqueue for upload
 	err = w.queue.Enqueue(
@@ -368,34 +805,219 @@ func (w *CaptureWorker) capture() {
 	)
 
 	if err != nil {
-		if err == queue.ErrCapturePaused {
-			w.logger.Debug("Capture paused, image dropped",
-				"camera", w.camera.ID())
-		} else {
-			w.logger.Error("Failed to enqueue image",
-				"camera", w.camera.ID(),
-				"error", err)
+		w.logEnqueueError(err)
+		return
+	}
+	timing.EnqueueMs = timer.lap()
+	if notifier, ok := camera.Underlying(w.camera).(camera.QueuedNotifier); ok {
+		notifier.FrameQueued()
+	}
+
+	w.recordCaptureSuccess(observation)
+	w.recordCaptured(len(stampResult.Data))
+	w.recordTiming(timing, timer)
+
+	w.queueThumbnail(jobCtx, imageData, observation, meta)
+	w.queueRegions(jobCtx, source, observation, meta)
+	w.startSpectrogram(observation)
+	w.sampleQuality(jobCtx, imageData, observation.Time)
+
+	// Notify callback with processed image (before EXIF stamping for cleaner preview)
+	if w.onCapture != nil {
+		w.onCapture(w.camera.ID(), imageData, observation.Time)
+	}
+}
+
+// applyProcessed replaces *imageData with the processed image. On failure the
+// original is kept, unless privacy zones could not be masked: then the frame is
+// dropped and false is returned.
+func (w *CaptureWorker) applyProcessed(imageData *[]byte, processed []byte, err error) bool {
+	if err == nil {
+		*imageData = processed
+		return true
+	}
+	if w.config.ImageProcessor.Masks() {
+		w.logger.Error("Privacy masking failed, frame dropped",
+			"camera", w.camera.ID(),
+			"error", err)
+		return false
+	}
+	w.logger.Warn("Image processing failed, using original",
+		"camera", w.camera.ID(),
+		"error", err)
+	return true
+}
+
+// determineObservation resolves the observation time via the time authority and logs warnings
+func (w *CaptureWorker) determineObservation(captureStartUTC time.Time, cameraTime *time.Time) timepkg.ObservationResult {
+	var observation timepkg.ObservationResult
+	if w.authority != nil {
+		observation = w.authority.DetermineObservationTimeWith(w.config.TimeSource, captureStartUTC, cameraTime)
+	} else {
+		observation = timepkg.ObservationResult{
+			Time:       captureStartUTC,
+			Source:     timepkg.SourceBridgeClock,
+			Confidence: timepkg.ConfidenceHigh,
 		}
+	}
+
+	// Log any time warnings
+	if observation.Warning != nil {
+		w.logger.Warn("Time observation warning",
+			"camera", w.camera.ID(),
+			"code", observation.Warning.Code,
+			"message", observation.Warning.Message)
+	}
+
+	return observation
+}
+
+// logEnqueueError logs a failed enqueue; paused capture is expected and not an error
+func (w *CaptureWorker) logEnqueueError(err error) {
+	w.reportQueueStorage()
+	if errors.Is(err, queue.ErrReadOnlyFilesystem) {
+		return // Reported once by reportQueueStorage
+	}
+	if err == queue.ErrCapturePaused {
+		w.logger.Debug("Capture paused, image dropped",
+			"camera", w.camera.ID())
 		return
 	}
+	if err == queue.ErrTimestampRegression {
+		return // Logged by the queue with both timestamps
+	}
+	w.logger.Error("Failed to enqueue image",
+		"camera", w.camera.ID(),
+		"error", err)
+}
 
-	// Success
+// reportQueueStorage logs the queue's filesystem turning read-only, the queue
+// moving to its fallback directory and the filesystem recovering, once per change
+// rather than once per failed frame
+func (w *CaptureWorker) reportQueueStorage() {
+	since, fallback := w.queue.StorageStatus()
+	readOnly := !since.IsZero()
+	w.mu.Lock()
+	changed := readOnly != w.queueReadOnly || fallback != w.queueFallback
+	w.queueReadOnly, w.queueFallback = readOnly, fallback
+	w.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	switch {
+	case fallback != "":
+		w.logger.Error("Queue filesystem is read-only - frames are being queued in the fallback directory",
+			"camera", w.camera.ID(),
+			"since", since,
+			"fallback", fallback)
+	case readOnly:
+		w.logger.Error("Queue filesystem is read-only - frames cannot be queued until it is writable or queue_fallback_path is set",
+			"camera", w.camera.ID(),
+			"since", since)
+	default:
+		w.logger.Info("Queue filesystem is writable again",
+			"camera", w.camera.ID())
+	}
+}
+
+// recordCaptureSuccess resets failure state after an image is queued
+func (w *CaptureWorker) recordCaptureSuccess(observation timepkg.ObservationResult) {
+	w.reportQueueStorage()
 	w.mu.Lock()
 	w.state.LastSuccess = time.Now()
 	w.state.LastError = nil
 	w.state.FailureCount = 0
 	ResetBackoff(w.state)
+	w.lastConfidence = observation.Confidence
+	w.lastTimeSource = observation.Source
 	w.mu.Unlock()
+	w.recordRecovered()
 
 	w.logger.Debug("Image captured and queued",
 		"camera", w.camera.ID(),
 		"observation_time", observation.Time.Format(time.RFC3339),
 		"source", observation.Source)
+}
 
-	// Notify callback with processed image (before EXIF stamping for cleaner preview)
-	if w.onCapture != nil {
-		w.onCapture(w.camera.ID(), imageData, observation.Time)
+// trimJPEG strips bytes outside the JPEG SOI/EOI markers.
+// If the data cannot be parsed, the original is kept so capture still proceeds.
+func (w *CaptureWorker) trimJPEG(imageData []byte) []byte {
+	trimmed, leading, trailing, err := image.TrimJPEG(imageData)
+	if err != nil {
+		w.logger.Warn("JPEG trim failed, using original",
+			"camera", w.camera.ID(),
+			"error", err)
+		return imageData
+	}
+	if leading > 0 || trailing > 0 {
+		w.logger.Info("Trimmed bytes outside JPEG markers",
+			"camera", w.camera.ID(),
+			"leading_bytes", leading,
+			"trailing_bytes", trailing)
 	}
+	return trimmed
+}
+
+// frameMeta returns the note and, with ExifSequence, the next frame number to stamp
+func (w *CaptureWorker) frameMeta() timepkg.FrameMeta {
+	meta := timepkg.FrameMeta{Note: w.config.ExifNote}
+	if !w.config.ExifSequence || w.sequences == nil {
+		return meta
+	}
+	seq, err := w.sequences.Next(w.camera.ID())
+	if err != nil {
+		w.logger.Warn("Frame sequence not saved; it may repeat after a restart",
+			"camera", w.camera.ID(),
+			"sequence", seq,
+			"error", err)
+	}
+	meta.Sequence = seq
+	return meta
+}
+
+// lastSequence returns the last frame number stamped, or 0 without ExifSequence
+func (w *CaptureWorker) lastSequence() uint32 {
+	if !w.config.ExifSequence || w.sequences == nil {
+		return 0
+	}
+	return w.sequences.Last(w.camera.ID())
+}
+
+// commentJPEG adds the bridge COM segment when configured. If it cannot be added,
+// the stamped frame is kept unchanged.
+func (w *CaptureWorker) commentJPEG(imageData []byte, marker string) []byte {
+	if !w.config.JPEGComment {
+		return imageData
+	}
+	commented, err := timepkg.InjectComment(imageData, timepkg.BridgeComment(marker, w.camera.ID(), w.config.ExifNote))
+	if err != nil {
+		w.logger.Warn("JPEG comment not added",
+			"camera", w.camera.ID(),
+			"error", err)
+		return imageData
+	}
+	return commented
+}
+
+// repairJPEG salvages frames whose only defect is at the EOI marker.
+// Anything it cannot repair is passed through unchanged.
+func (w *CaptureWorker) repairJPEG(imageData []byte) []byte {
+	repaired, repair, err := image.RepairJPEG(imageData)
+	if err != nil || repair == image.RepairNone {
+		return imageData
+	}
+
+	w.mu.Lock()
+	w.jpegRepaired++
+	w.mu.Unlock()
+
+	w.logger.Info("Repaired JPEG end marker",
+		"camera", w.camera.ID(),
+		"repair", string(repair),
+		"original_bytes", len(imageData),
+		"repaired_bytes", len(repaired))
+	return repaired
 }
 
 // readCameraEXIF reads EXIF timestamp from image data via exiftool
@@ -415,7 +1037,16 @@ func (w *CaptureWorker) readCameraEXIF(imageData []byte) *time.Time {
 		return nil // Can't proceed with incomplete/corrupt temp file
 	}
 
-	result, err := w.exifHelper.ReadEXIF(tmpPath)
+	return w.readCameraEXIFFile(tmpPath)
+}
+
+// readCameraEXIFFile reads EXIF timestamp from an image file via exiftool
+func (w *CaptureWorker) readCameraEXIFFile(path string) *time.Time {
+	helper := w.exifReader()
+	if helper == nil {
+		return nil
+	}
+	result, err := helper.ReadEXIF(path)
 	if err != nil || !result.Success {
 		w.mu.Lock()
 		w.exifReadFailed++
@@ -423,10 +1054,19 @@ func (w *CaptureWorker) readCameraEXIF(imageData []byte) *time.Time {
 		return nil
 	}
 
-	cameraTime, _ := w.exifHelper.ParseCameraTime(result)
+	cameraTime, _ := helper.ParseCameraTime(result)
 	return cameraTime
 }
 
+// exifReader returns the exiftool helper for camera EXIF reads, or nil while
+// exiftool is missing
+func (w *CaptureWorker) exifReader() *timepkg.ExifToolHelper {
+	if w.exifTool == nil {
+		return nil
+	}
+	return w.exifTool()
+}
+
 func (w *CaptureWorker) handleCaptureError(err error) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -434,6 +1074,7 @@ func (w *CaptureWorker) handleCaptureError(err error) {
 	w.capturesFailed++
 	w.state.LastError = err
 	w.state.LastErrorTime = time.Now()
+	w.recordFailing(w.state.LastErrorTime)
 	w.state.FailureCount++
 
 	UpdateBackoff(w.state, DefaultBackoffConfig())
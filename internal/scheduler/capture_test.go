@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestCaptureWorker_FolderCamera(t *testing.T) {
+	dir := t.TempDir()
+	cam, err := camera.NewFolderCamera(camera.Config{
+		ID:     "folder-cam",
+		Type:   "folder",
+		Folder: &camera.FolderConfig{Path: dir, DeleteAfterQueue: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := queue.NewQueue("folder-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "folder-cam"},
+		Queue:        q,
+	})
+
+	// An empty folder skips the cycle without counting a failure or backing off
+	w.capture()
+	stats := w.GetStats()
+	if stats.EmptyCaptures != 1 || stats.CapturesFailed != 0 || w.GetState().FailureCount != 0 {
+		t.Fatalf("empty folder: %+v", stats)
+	}
+
+	path := filepath.Join(dir, "frame.jpg")
+	if err := os.WriteFile(path, minimalTestJPEG(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	os.Chtimes(path, old, old)
+
+	w.capture()
+	if q.GetImageCount() != 1 {
+		t.Fatalf("queued %d images, want 1", q.GetImageCount())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("file should be deleted once queued")
+	}
+}
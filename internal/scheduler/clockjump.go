@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// clockJumpCheckInterval is how often the wall clock is compared with the time
+// that actually passed
+const clockJumpCheckInterval = 5 * time.Second
+
+// ClockJumpStats reports wall-clock jumps seen since startup
+type ClockJumpStats struct {
+	Detected int64         `json:"detected"`
+	LastAt   time.Time     `json:"last_at,omitempty"`
+	LastGap  time.Duration `json:"last_gap,omitempty"` // Negative when the clock went back
+}
+
+// watchClockJumps checks for wall-clock jumps until ctx is done
+func (o *Orchestrator) watchClockJumps(ctx context.Context, threshold time.Duration) {
+	ticker := time.NewTicker(clockJumpCheckInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if gap := clockGap(last, now, clockJumpCheckInterval); gap >= threshold || gap <= -threshold {
+				o.handleClockJump(now, gap)
+			}
+			last = now
+		}
+	}
+}
+
+// clockGap returns how far the wall clock moved between last and now beyond the
+// expected interval. The monotonic clock stops while the system is suspended, so
+// wall time is compared with the interval rather than with monotonic time.
+func clockGap(last, now time.Time, expected time.Duration) time.Duration {
+	return now.Round(0).Sub(last.Round(0)) - expected
+}
+
+// handleClockJump realigns capture timers and the daily upload counter and
+// re-checks time health after a suspend/resume or clock change
+func (o *Orchestrator) handleClockJump(now time.Time, gap time.Duration) {
+	o.mu.Lock()
+	o.clockJumps.Detected++
+	o.clockJumps.LastAt = now
+	o.clockJumps.LastGap = gap
+	workers := make([]*CaptureWorker, 0, len(o.captureWorkers))
+	for _, worker := range o.captureWorkers {
+		workers = append(workers, worker)
+	}
+	uploadWorker := o.uploadWorker
+	timeHealth := o.timeHealth
+	o.mu.Unlock()
+
+	o.logger.Warn("System resume or clock jump detected, realigning schedules",
+		"gap", gap.Round(time.Second),
+		"cameras", len(workers))
+
+	for _, worker := range workers {
+		worker.Realign()
+	}
+	if uploadWorker != nil {
+		uploadWorker.RealignDay(now)
+	}
+	if timeHealth != nil {
+		go timeHealth.Recheck()
+	}
+}
+
+// clockJumpStatsLocked returns the jump counters, or nil when detection is
+// disabled (caller must hold o.mu for reading)
+func (o *Orchestrator) clockJumpStatsLocked() *ClockJumpStats {
+	if o.config.ClockJumpThreshold <= 0 {
+		return nil
+	}
+	stats := o.clockJumps
+	return &stats
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockGap(t *testing.T) {
+	last := time.Now()
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Duration
+	}{
+		{"on time", last.Add(5 * time.Second), 0},
+		{"suspended an hour", last.Add(time.Hour + 5*time.Second), time.Hour},
+		{"clock set back", last.Add(-10 * time.Minute), -10*time.Minute - 5*time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Only the wall clock moves across a suspend, so compare stripped times
+			if got := clockGap(last, tt.now.Round(0), 5*time.Second); got != tt.want {
+				t.Errorf("clockGap = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadWorker_RealignDayAfterClockSetBack(t *testing.T) {
+	w := NewUploadWorker(UploadWorkerConfig{})
+	now := time.Now()
+
+	// Counting a day that the clock has since been set back from
+	w.mu.Lock()
+	w.uploadsToday = 5
+	w.todayDate = startOfDay(now.Add(48*time.Hour), w.location)
+	w.mu.Unlock()
+
+	w.RealignDay(now)
+	if s := w.GetStats(); s.UploadsToday != 0 {
+		t.Errorf("UploadsToday = %d after the day changed, want 0", s.UploadsToday)
+	}
+
+	// The same day keeps its count
+	w.recordSuccess(time.Millisecond)
+	w.RealignDay(now)
+	if s := w.GetStats(); s.UploadsToday != 1 {
+		t.Errorf("UploadsToday = %d on the same day, want 1", s.UploadsToday)
+	}
+}
+
+func TestOrchestrator_HandleClockJump(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	config.QueueBasePath = t.TempDir()
+	config.ClockJumpThreshold = 2 * time.Minute
+	orch, err := NewOrchestrator(config)
+	if err != nil {
+		t.Fatalf("NewOrchestrator() error = %v", err)
+	}
+	defer orch.Stop()
+
+	cam := &mockCamera{id: "jump-cam", camType: "http"}
+	if err := orch.AddCamera(cam, CameraConfig{ID: "jump-cam", RemotePath: "jump", Enabled: true}, 60, &mockUploader{}, nil); err != nil {
+		t.Fatalf("AddCamera() error = %v", err)
+	}
+
+	now := time.Now()
+	orch.handleClockJump(now, time.Hour)
+	orch.handleClockJump(now, time.Hour) // Coalesced with the pending realign
+
+	worker := orch.captureWorkers["jump-cam"]
+	if len(worker.realign) != 1 {
+		t.Errorf("pending realigns = %d, want 1", len(worker.realign))
+	}
+	stats := orch.GetStatus().ClockJumps
+	if stats == nil || stats.Detected != 2 || stats.LastGap != time.Hour || !stats.LastAt.Equal(now) {
+		t.Errorf("ClockJumps = %+v", stats)
+	}
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import "time"
+
+// ConcurrencyTuning bounds automatic adjustment of concurrent uploads
+type ConcurrencyTuning struct {
+	Min           int           // Lowest limit (default: 1)
+	Max           int           // Highest limit; also the worker pool size (default: MaxConcurrent)
+	TargetLatency time.Duration // Uploads slower than this count as congestion (default: 60s)
+}
+
+const (
+	defaultTargetLatency = 60 * time.Second
+
+	// decreaseHold ignores further failures for a while after a decrease, since uploads
+	// already in flight when the link degraded report one congestion event, not several
+	decreaseHold = 10 * time.Second
+)
+
+// ConcurrencyStats describes the auto-tuned upload concurrency
+type ConcurrencyStats struct {
+	Min        int       `json:"min"`
+	Max        int       `json:"max"`
+	Increases  int64     `json:"increases"`
+	Decreases  int64     `json:"decreases"`
+	LastChange time.Time `json:"last_change,omitempty"`
+}
+
+// concurrencyController is an AIMD controller for the upload limit: one more slot
+// after a run of good uploads as long as the current limit, halved on a failure,
+// timeout, auth backoff or slow upload
+type concurrencyController struct {
+	tuning       ConcurrencyTuning
+	limit        int
+	streak       int // Good uploads since the last change
+	lastDecrease time.Time
+	stats        ConcurrencyStats
+}
+
+// newConcurrencyController starts at initial, clamped to the tuning range
+func newConcurrencyController(tuning ConcurrencyTuning, initial int) *concurrencyController {
+	if tuning.Min <= 0 {
+		tuning.Min = 1
+	}
+	if tuning.Max < tuning.Min {
+		tuning.Max = max(initial, tuning.Min)
+	}
+	if tuning.TargetLatency <= 0 {
+		tuning.TargetLatency = defaultTargetLatency
+	}
+	return &concurrencyController{
+		tuning: tuning,
+		limit:  min(max(initial, tuning.Min), tuning.Max),
+		stats:  ConcurrencyStats{Min: tuning.Min, Max: tuning.Max},
+	}
+}
+
+// record adjusts the limit after an upload finished; ok is false for failures
+func (c *concurrencyController) record(ok bool, latency time.Duration, now time.Time) {
+	if ok && latency <= c.tuning.TargetLatency {
+		c.streak++
+		if c.streak >= c.limit && c.limit < c.tuning.Max {
+			c.limit++
+			c.streak = 0
+			c.stats.Increases++
+			c.stats.LastChange = now
+		}
+		return
+	}
+
+	c.streak = 0
+	if now.Sub(c.lastDecrease) < decreaseHold {
+		return
+	}
+	c.lastDecrease = now
+	if next := max(c.limit/2, c.tuning.Min); next < c.limit {
+		c.limit = next
+		c.stats.Decreases++
+		c.stats.LastChange = now
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConcurrencyController_AIMD(t *testing.T) {
+	c := newConcurrencyController(ConcurrencyTuning{Min: 1, Max: 4, TargetLatency: 10 * time.Second}, 2)
+	now := time.Now()
+
+	// Additive increase: one slot per run of good uploads as long as the limit
+	for i := 0; i < 2+3+4; i++ {
+		c.record(true, time.Second, now)
+	}
+	if c.limit != 4 {
+		t.Fatalf("limit = %d, want 4 (capped at max)", c.limit)
+	}
+
+	// Multiplicative decrease, once per congestion event
+	c.record(false, 0, now)
+	c.record(false, 0, now.Add(time.Second))
+	if c.limit != 2 || c.stats.Decreases != 1 {
+		t.Fatalf("limit = %d decreases = %d, want 2/1", c.limit, c.stats.Decreases)
+	}
+
+	// A slow upload is congestion too
+	c.record(true, time.Minute, now.Add(decreaseHold))
+	c.record(false, 0, now.Add(2*decreaseHold))
+	if c.limit != 1 {
+		t.Errorf("limit = %d, want floor of 1", c.limit)
+	}
+	if c.stats.Increases != 2 || c.stats.Decreases != 2 {
+		t.Errorf("stats = %+v", c.stats)
+	}
+}
+
+func TestConcurrencyController_Defaults(t *testing.T) {
+	c := newConcurrencyController(ConcurrencyTuning{}, 3)
+	if c.tuning.Min != 1 || c.tuning.Max != 3 || c.limit != 3 || c.tuning.TargetLatency != defaultTargetLatency {
+		t.Errorf("tuning = %+v limit = %d", c.tuning, c.limit)
+	}
+	if c := newConcurrencyController(ConcurrencyTuning{Min: 2, Max: 6}, 8); c.limit != 6 {
+		t.Errorf("initial limit = %d, want clamped to max", c.limit)
+	}
+}
+
+func TestUploadWorker_AutoTunedConcurrency(t *testing.T) {
+	fixed := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 3})
+	if stats := fixed.GetStats(); stats.Concurrency != 3 || stats.ConcurrencyAutoTune != nil {
+		t.Errorf("fixed: concurrency = %d autotune = %+v", stats.Concurrency, stats.ConcurrencyAutoTune)
+	}
+
+	tuned := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 2, Concurrency: &ConcurrencyTuning{Min: 1, Max: 5}})
+	if tuned.maxConcurrent != 5 {
+		t.Errorf("pool size = %d, want the tuning max", tuned.maxConcurrent)
+	}
+	tuned.recordConcurrency(false, time.Second)
+	stats := tuned.GetStats()
+	if stats.Concurrency != 1 || stats.ConcurrencyAutoTune == nil || stats.ConcurrencyAutoTune.Decreases != 1 {
+		t.Errorf("tuned: concurrency = %d autotune = %+v", stats.Concurrency, stats.ConcurrencyAutoTune)
+	}
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import "crypto/sha256"
+
+// MaxDedupWindow caps how many frame hashes a camera remembers (32 bytes each)
+const MaxDedupWindow = 1024
+
+// frameHistory remembers the hashes of the last N distinct frames so repeats are
+// caught even when they are not consecutive (e.g. a looping demo stream)
+type frameHistory struct {
+	ring  [][sha256.Size]byte
+	index map[[sha256.Size]byte]struct{}
+	next  int
+}
+
+// newFrameHistory returns a history of n hashes, or nil when n <= 0 (disabled)
+func newFrameHistory(n int) *frameHistory {
+	if n <= 0 {
+		return nil
+	}
+	if n > MaxDedupWindow {
+		n = MaxDedupWindow
+	}
+	return &frameHistory{
+		ring:  make([][sha256.Size]byte, 0, n),
+		index: make(map[[sha256.Size]byte]struct{}, n),
+	}
+}
+
+// observe records a frame hash and reports whether it was already in the window.
+// Repeats are not re-added, so a loop no longer than the window stays detected.
+func (h *frameHistory) observe(sum [sha256.Size]byte) bool {
+	if _, ok := h.index[sum]; ok {
+		return true
+	}
+
+	if len(h.ring) < cap(h.ring) {
+		h.ring = append(h.ring, sum)
+	} else {
+		delete(h.index, h.ring[h.next])
+		h.ring[h.next] = sum
+		h.next = (h.next + 1) % len(h.ring)
+	}
+	h.index[sum] = struct{}{}
+	return false
+}
+
+// isRepeatFrame reports whether imageData matches one of the camera's recent frames,
+// logging when repetition starts and stops
+func (w *CaptureWorker) isRepeatFrame(imageData []byte) bool {
+	if w.frames == nil {
+		return false
+	}
+	sum := sha256.Sum256(imageData)
+
+	w.mu.Lock()
+	repeat := w.frames.observe(sum)
+	changed := repeat != w.repetitionDetected
+	w.repetitionDetected = repeat
+	if repeat {
+		w.framesSuppressed++
+	}
+	w.mu.Unlock()
+
+	if changed {
+		if repeat {
+			w.logger.Warn("Repeated camera frame detected, suppressing duplicates",
+				"camera", w.camera.ID(),
+				"window", cap(w.frames.ring))
+		} else {
+			w.logger.Info("Camera producing new frames again", "camera", w.camera.ID())
+		}
+	}
+	return repeat
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestFrameHistory_Window(t *testing.T) {
+	if newFrameHistory(0) != nil {
+		t.Error("window 0 should disable dedup")
+	}
+	if h := newFrameHistory(MaxDedupWindow * 2); cap(h.ring) != MaxDedupWindow {
+		t.Errorf("window not capped: %d", cap(h.ring))
+	}
+
+	h := newFrameHistory(2)
+	a, b, c := sha256.Sum256([]byte("a")), sha256.Sum256([]byte("b")), sha256.Sum256([]byte("c"))
+
+	if h.observe(a) || h.observe(b) {
+		t.Fatal("first sightings should not be repeats")
+	}
+	if !h.observe(a) {
+		t.Error("non-consecutive repeat within the window should be detected")
+	}
+	if h.observe(c) {
+		t.Error("new frame reported as repeat")
+	}
+	// c evicted a, the oldest
+	if h.observe(a) {
+		t.Error("frame outside the window should not be a repeat")
+	}
+	if len(h.index) != 2 {
+		t.Errorf("index holds %d hashes, want 2", len(h.index))
+	}
+}
+
+func TestCaptureWorker_SuppressesRepeatedFrames(t *testing.T) {
+	q, err := queue.NewQueue("loop-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	frameA := minimalTestJPEG()
+	frameB := append(append([]byte{}, frameA...), 0x00)
+	frameC := append(append([]byte{}, frameA...), 0x01)
+
+	cam := &mockCamera{id: "loop-cam", camType: "http"}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "loop-cam", DedupWindow: 4},
+		Queue:        q,
+	})
+
+	for _, frame := range [][]byte{frameA, frameB, frameA} {
+		cam.data = frame
+		w.capture()
+	}
+
+	stats := w.GetStats()
+	if q.GetImageCount() != 2 {
+		t.Errorf("queued %d images, want 2 (repeat suppressed)", q.GetImageCount())
+	}
+	if !stats.RepetitionDetected || stats.FramesSuppressed != 1 {
+		t.Errorf("RepetitionDetected=%v FramesSuppressed=%d, want true/1", stats.RepetitionDetected, stats.FramesSuppressed)
+	}
+
+	cam.data = frameC
+	w.capture()
+	if w.GetStats().RepetitionDetected {
+		t.Error("repetition flag should clear once a new frame arrives")
+	}
+	if q.GetImageCount() != 3 {
+		t.Errorf("queued %d images, want 3", q.GetImageCount())
+	}
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+// mockEventCamera fires one event once its subscription starts
+type mockEventCamera struct {
+	mockCamera
+	events []string
+}
+
+func (m *mockEventCamera) EventsEnabled() bool { return len(m.events) > 0 }
+
+func (m *mockEventCamera) WatchEvents(ctx context.Context, trigger func(topic string)) {
+	for _, topic := range m.events {
+		trigger(topic)
+	}
+	<-ctx.Done()
+}
+
+func (m *mockEventCamera) EventStatus() camera.EventStatus {
+	return camera.EventStatus{Active: true, Topics: m.events}
+}
+
+func TestCaptureWorker_EventTriggeredCapture(t *testing.T) {
+	q, err := queue.NewQueue("motion-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	cam := &mockEventCamera{
+		mockCamera: mockCamera{id: "motion-cam", camType: "onvif", data: minimalTestJPEG()},
+		events:     []string{"tns1:RuleEngine/CellMotionDetector/Motion"},
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "motion-cam"},
+		Queue:        q,
+		IntervalSecs: 1800,
+	})
+	w.Start()
+	defer w.Stop()
+
+	// Initial interval capture plus one event capture; the next tick is 30 minutes away
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && w.GetStats().EventCaptures == 0 {
+		time.Sleep(20 * time.Millisecond)
+	}
+	stats := w.GetStats()
+	if stats.EventCaptures != 1 {
+		t.Fatalf("EventCaptures = %d, want 1", stats.EventCaptures)
+	}
+	if stats.Events == nil || !stats.Events.Active {
+		t.Errorf("expected event subscription status in stats, got %+v", stats.Events)
+	}
+}
+
+func TestCaptureWorker_EventCooldown(t *testing.T) {
+	q, err := queue.NewQueue("motion-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "motion-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "motion-cam", EventCooldown: time.Minute},
+		Queue:        q,
+	})
+
+	if !w.eventCaptureAllowed() {
+		t.Error("first event capture should be allowed")
+	}
+	w.lastCaptureTime = time.Now().Add(-30 * time.Second)
+	if w.eventCaptureAllowed() {
+		t.Error("event within cooldown should be skipped")
+	}
+	w.lastCaptureTime = time.Now().Add(-2 * time.Minute)
+	if !w.eventCaptureAllowed() {
+		t.Error("event after cooldown should be allowed")
+	}
+	w.state.NextAttempt = time.Now().Add(time.Minute)
+	if w.eventCaptureAllowed() {
+		t.Error("event during capture backoff should be skipped")
+	}
+
+	if w.GetStats().Events != nil {
+		t.Error("cameras without events should not report event status")
+	}
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"strings"
+
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// Filename time tokens (CameraConfig.FilenameTimeTokens). Whatever their order in
+// the config, they are appended to the timestamp in this order:
+// <unix_ms>[_<source>][_<confidence>][_warn].jpg
+const (
+	FilenameTokenSource     = "source"
+	FilenameTokenConfidence = "confidence"
+	FilenameTokenWarn       = "warn"
+)
+
+// unknownFilenameToken stands in for the source and confidence of a frame without
+// a bridge marker (e.g. stamping failed)
+const unknownFilenameToken = "unknown"
+
+// filenameTimeSuffix returns the tokens to append to a frame's remote filename,
+// read from the bridge marker in its EXIF; "" when tokens is empty. Underscores in
+// values become hyphens so the tokens split cleanly on "_".
+func filenameTimeSuffix(tokens []string, path string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	want := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		want[token] = true
+	}
+
+	source, confidence := unknownFilenameToken, unknownFilenameToken
+	marker, ok := timepkg.ReadBridgeMarker(path)
+	if ok {
+		source = strings.ReplaceAll(string(marker.Source), "_", "-")
+		confidence = strings.ReplaceAll(string(marker.Confidence), "_", "-")
+	}
+
+	var suffix strings.Builder
+	if want[FilenameTokenSource] {
+		suffix.WriteString("_" + source)
+	}
+	if want[FilenameTokenConfidence] {
+		suffix.WriteString("_" + confidence)
+	}
+	if want[FilenameTokenWarn] && marker.WarningCode != "" {
+		suffix.WriteString("_" + FilenameTokenWarn)
+	}
+	return suffix.String()
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+func TestFilenameTimeSuffix(t *testing.T) {
+	obs := timepkg.ObservationResult{
+		Time:       time.Now().UTC(),
+		Source:     timepkg.SourceCameraEXIF,
+		Confidence: timepkg.ConfidenceLow,
+		Warning:    &timepkg.TimeWarning{Code: "camera_drift"},
+	}
+	stamped := filepath.Join(t.TempDir(), "stamped.jpg")
+	if err := os.WriteFile(stamped, timepkg.StampBridgeEXIF(testJPEG(t, 32, 24), obs, timepkg.FrameMeta{}).Data, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	unstamped := filepath.Join(t.TempDir(), "unstamped.jpg")
+	if err := os.WriteFile(unstamped, testJPEG(t, 32, 24), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tokens []string
+		path   string
+		want   string
+	}{
+		{"disabled", nil, stamped, ""},
+		{"confidence", []string{"confidence"}, stamped, "_low"},
+		{"fixed order", []string{"warn", "confidence", "source"}, stamped, "_camera-exif_low_warn"},
+		{"no marker", []string{"source", "confidence", "warn"}, unstamped, "_unknown_unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filenameTimeSuffix(tt.tokens, tt.path); got != tt.want {
+				t.Errorf("filenameTimeSuffix = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Remote dedup still recognizes frames uploaded with tokens
+	w := NewUploadWorker(UploadWorkerConfig{})
+	name := filepath.Base(w.buildRemotePath("cam", "cam", time.UnixMilli(1735142730000), "_low_warn"))
+	if name != "1735142730000_low_warn.jpg" {
+		t.Fatalf("remote name = %q", name)
+	}
+	if !remoteTimestamps([]string{name})[1735142730000] {
+		t.Error("remoteTimestamps did not parse a tokened filename")
+	}
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import "time"
+
+// SLAEvent reports a camera crossing its freshness SLA in either direction
+type SLAEvent struct {
+	CameraID   string
+	Breached   bool // false = recovered
+	SLA        time.Duration
+	LastUpload time.Time // Zero if the camera has never uploaded
+	Age        time.Duration
+}
+
+// FreshnessStatus is a camera's freshness SLA state for status reporting
+type FreshnessStatus struct {
+	SLASeconds    int64     `json:"sla_seconds"`
+	AgeSeconds    int64     `json:"age_seconds"` // Since last upload, or since the camera was added
+	Breached      bool      `json:"breached"`
+	BreachedSince time.Time `json:"breached_since,omitempty"`
+}
+
+// freshnessAge returns how stale a camera's uploads are: the age of the last
+// successful upload, or time since it was added if it has never uploaded
+func freshnessAge(state *uploadFailureState, now time.Time) time.Duration {
+	if !state.lastSuccess.IsZero() {
+		return now.Sub(state.lastSuccess)
+	}
+	return now.Sub(state.added)
+}
+
+// checkFreshness compares each camera's upload age with its SLA and reports
+// transitions to onSLAChange. No new breach is raised during quiet hours, when a
+// stale server image is expected.
+func (w *UploadWorker) checkFreshness(now time.Time) {
+	var events []SLAEvent
+
+	w.mu.Lock()
+	for id, state := range w.cameraFailures {
+		sla := w.configs[id].FreshnessSLA
+		if sla <= 0 {
+			state.breachedSince = time.Time{}
+			continue
+		}
+		age := freshnessAge(state, now)
+		breached := age > sla
+		if breached == !state.breachedSince.IsZero() {
+			continue
+		}
+		if breached {
+			if w.quietHoursFor(id).Active(now, w.location) {
+				continue
+			}
+			state.breachedSince = now
+		} else {
+			state.breachedSince = time.Time{}
+		}
+		events = append(events, SLAEvent{
+			CameraID:   id,
+			Breached:   breached,
+			SLA:        sla,
+			LastUpload: state.lastSuccess,
+			Age:        age,
+		})
+	}
+	onChange := w.onSLAChange
+	w.mu.Unlock()
+
+	for _, e := range events {
+		if e.Breached {
+			w.logger.Warn("Camera freshness SLA breached",
+				"camera", e.CameraID,
+				"sla", e.SLA,
+				"age", e.Age.Round(time.Second))
+		} else {
+			w.logger.Info("Camera freshness SLA restored", "camera", e.CameraID)
+		}
+		if onChange != nil {
+			onChange(e)
+		}
+	}
+}
+
+// copyFreshness returns SLA state for cameras with a freshness SLA (caller must hold lock)
+func (w *UploadWorker) copyFreshness(now time.Time) map[string]FreshnessStatus {
+	var result map[string]FreshnessStatus
+	for id, state := range w.cameraFailures {
+		sla := w.configs[id].FreshnessSLA
+		if sla <= 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]FreshnessStatus)
+		}
+		result[id] = FreshnessStatus{
+			SLASeconds:    int64(sla.Seconds()),
+			AgeSeconds:    int64(freshnessAge(state, now).Seconds()),
+			Breached:      !state.breachedSince.IsZero(),
+			BreachedSince: state.breachedSince,
+		}
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func newTestQueue(t *testing.T, cameraID string) *queue.Queue {
+	t.Helper()
+	q, err := queue.NewQueue(cameraID, t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	return q
+}
+
+func TestUploadWorker_CheckFreshness(t *testing.T) {
+	var events []SLAEvent
+	worker := NewUploadWorker(UploadWorkerConfig{
+		Location:    time.UTC,
+		OnSLAChange: func(e SLAEvent) { events = append(events, e) },
+	})
+	worker.AddQueue("sla", newTestQueue(t, "sla"), CameraConfig{ID: "sla", FreshnessSLA: 2 * time.Minute}, &mockUploader{})
+	worker.AddQueue("no-sla", newTestQueue(t, "no-sla"), CameraConfig{ID: "no-sla"}, &mockUploader{})
+
+	now := time.Now()
+	worker.checkFreshness(now)
+	if len(events) != 0 {
+		t.Fatalf("new camera should be within SLA, got %v", events)
+	}
+
+	// Never uploaded since being added three minutes ago
+	worker.cameraFailures["sla"].added = now.Add(-3 * time.Minute)
+	worker.checkFreshness(now)
+	if len(events) != 1 || !events[0].Breached || events[0].CameraID != "sla" || !events[0].LastUpload.IsZero() {
+		t.Fatalf("expected breach event, got %+v", events)
+	}
+
+	// Still breached: no repeat alert
+	worker.checkFreshness(now.Add(time.Second))
+	if len(events) != 1 {
+		t.Errorf("breach should alert once, got %d events", len(events))
+	}
+
+	freshness := worker.GetStats().Freshness
+	if st, ok := freshness["sla"]; !ok || !st.Breached || st.SLASeconds != 120 || st.BreachedSince.IsZero() {
+		t.Errorf("unexpected freshness status: %+v", freshness)
+	}
+	if _, ok := freshness["no-sla"]; ok {
+		t.Error("camera without an SLA should not report freshness")
+	}
+
+	// A fresh upload clears the breach
+	worker.cameraFailures["sla"].lastSuccess = now
+	worker.checkFreshness(now.Add(2 * time.Second))
+	if len(events) != 2 || events[1].Breached {
+		t.Fatalf("expected recovery event, got %+v", events)
+	}
+	if worker.GetStats().Freshness["sla"].Breached {
+		t.Error("breach should clear after a fresh upload")
+	}
+}
+
+func TestUploadWorker_CheckFreshnessQuietHours(t *testing.T) {
+	var events []SLAEvent
+	now := time.Now().UTC()
+	worker := NewUploadWorker(UploadWorkerConfig{
+		Location:    time.UTC,
+		QuietHours:  windowAround(now),
+		OnSLAChange: func(e SLAEvent) { events = append(events, e) },
+	})
+	worker.AddQueue("sla", newTestQueue(t, "sla"), CameraConfig{ID: "sla", FreshnessSLA: time.Minute}, &mockUploader{})
+	worker.cameraFailures["sla"].added = now.Add(-time.Hour)
+
+	worker.checkFreshness(now)
+	if len(events) != 0 {
+		t.Errorf("no breach should be raised during quiet hours, got %+v", events)
+	}
+}
@@ -0,0 +1,190 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// PostCaptureHook runs an external command on each processed frame before it is
+// stamped. The frame goes to the command's stdin; what it writes to stdout (a JPEG)
+// replaces the frame, and writing nothing keeps the frame unchanged.
+type PostCaptureHook struct {
+	Command     []string      // Program and arguments, run without a shell
+	Timeout     time.Duration // 0 = DefaultHookTimeout
+	PassThrough bool          // On failure keep the unhooked frame instead of failing the capture
+}
+
+// DefaultHookTimeout bounds a post-capture hook run
+const DefaultHookTimeout = 10 * time.Second
+
+// Post-capture hook limits
+const (
+	hookMaxOutput  = 32 << 20        // Larger output fails the run
+	hookMaxStderr  = 1024            // Stderr kept for the failure message
+	hookWaitDelay  = 2 * time.Second // After a kill, for pipes held open by descendants
+	hookNoteLength = 200
+)
+
+// PostCaptureHookStats counts a camera's hook runs
+type PostCaptureHookStats struct {
+	Runs          int64   `json:"runs"`
+	Failures      int64   `json:"failures"`       // Timed out, exited non-zero or wrote a non-JPEG
+	PassedThrough int64   `json:"passed_through"` // Failures whose frame was kept unhooked
+	LastMs        float64 `json:"last_ms"`
+	LastError     string  `json:"last_error,omitempty"`
+}
+
+// hookState is a capture worker's hook statistics (guarded by w.mu)
+type hookState struct {
+	stats PostCaptureHookStats
+}
+
+// applyPostCaptureHook runs the camera's hook on data. It returns the frame to stamp,
+// or ok false if the capture failed and must not be queued.
+func (w *CaptureWorker) applyPostCaptureHook(ctx context.Context, data []byte, observation timepkg.ObservationResult) (result []byte, ok bool) {
+	hook := w.config.PostCaptureHook
+	if hook == nil {
+		return data, true
+	}
+
+	// Hooks are CPU work like image processing and share its limit
+	if w.resourceLimiter != nil {
+		if err := w.resourceLimiter.AcquireImageProcessing(ctx); err != nil {
+			return w.hookFailed(hook, data, fmt.Errorf("not run: %w", err), 0)
+		}
+		defer w.resourceLimiter.ReleaseImageProcessing()
+	}
+
+	start := time.Now()
+	out, err := runHook(ctx, hook, data, w.camera.ID(), observation.Time)
+	elapsed := time.Since(start)
+	if err != nil {
+		return w.hookFailed(hook, data, err, elapsed)
+	}
+
+	w.mu.Lock()
+	w.hook.stats.Runs++
+	w.hook.stats.LastMs = float64(elapsed.Microseconds()) / 1000
+	w.mu.Unlock()
+	if len(out) == 0 {
+		return data, true
+	}
+	return out, true
+}
+
+// hookFailed records a failed hook run and applies the camera's failure policy
+func (w *CaptureWorker) hookFailed(hook *PostCaptureHook, data []byte, err error, elapsed time.Duration) ([]byte, bool) {
+	w.mu.Lock()
+	w.hook.stats.Runs++
+	w.hook.stats.Failures++
+	w.hook.stats.LastMs = float64(elapsed.Microseconds()) / 1000
+	w.hook.stats.LastError = err.Error()
+	if hook.PassThrough {
+		w.hook.stats.PassedThrough++
+	}
+	w.mu.Unlock()
+
+	if hook.PassThrough {
+		w.logger.Warn("Post-capture hook failed, using the frame unhooked",
+			"camera", w.camera.ID(),
+			"error", err)
+		return data, true
+	}
+	w.handleCaptureError(fmt.Errorf("post-capture hook: %w", err))
+	return nil, false
+}
+
+// hookStatsLocked returns the hook statistics, or nil without a hook (caller must
+// hold w.mu)
+func (w *CaptureWorker) hookStatsLocked() *PostCaptureHookStats {
+	if w.config.PostCaptureHook == nil {
+		return nil
+	}
+	stats := w.hook.stats
+	return &stats
+}
+
+// runHook runs hook with data on stdin and returns its stdout. The command gets
+// its own process group, killed as a whole on timeout, and only a minimal
+// environment describing the frame.
+func runHook(ctx context.Context, hook *PostCaptureHook, data []byte, cameraID string, observed time.Time) ([]byte, error) {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = hookWaitDelay
+	cmd.Env = []string{
+		"PATH=" + os.Getenv("PATH"),
+		"AVIATIONWX_CAMERA_ID=" + cameraID,
+		"AVIATIONWX_OBSERVATION_TIME=" + observed.UTC().Format(time.RFC3339),
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	stdout := &limitedBuffer{max: hookMaxOutput}
+	stderr := &limitedBuffer{max: hookMaxStderr, truncate: true}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	err := cmd.Run()
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("timed out after %v", timeout)
+	case stdout.overflow:
+		return nil, fmt.Errorf("output larger than %d MB", hookMaxOutput>>20)
+	case err != nil:
+		if note := hookNote(stderr.Bytes()); note != "" {
+			return nil, fmt.Errorf("%w: %s", err, note)
+		}
+		return nil, err
+	}
+	out := stdout.Bytes()
+	if len(out) > 0 && !bytes.HasPrefix(out, []byte{0xFF, 0xD8}) {
+		return nil, errors.New("output is not a JPEG")
+	}
+	return out, nil
+}
+
+// hookNote is the start of a hook's stderr, on one line, for its failure message
+func hookNote(stderr []byte) string {
+	note := strings.Join(strings.Fields(string(stderr)), " ")
+	if len(note) > hookNoteLength {
+		note = note[:hookNoteLength] + "..."
+	}
+	return note
+}
+
+// limitedBuffer collects at most max bytes. Beyond that, writes fail (stopping the
+// copy from the command) unless truncate is set, in which case the rest is dropped.
+type limitedBuffer struct {
+	bytes.Buffer
+	max      int
+	truncate bool
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		if !b.truncate {
+			b.overflow = true
+			return 0, errors.New("output limit reached")
+		}
+		b.Buffer.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
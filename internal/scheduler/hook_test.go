@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// writeHookScript writes an executable shell script and returns its path
+func writeHookScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	return path
+}
+
+func newHookWorker(t *testing.T, hook *PostCaptureHook) *CaptureWorker {
+	return NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "hook-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "hook-cam", PostCaptureHook: hook},
+		Queue:        newTestQueue(t, "hook-cam"),
+	})
+}
+
+func TestRunHook_ReplacesFrame(t *testing.T) {
+	// The hook sees the frame on stdin and the camera in its environment
+	script := writeHookScript(t, `cat >/dev/null; printf '\377\330%s' "$AVIATIONWX_CAMERA_ID"`)
+	observed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	out, err := runHook(context.Background(), &PostCaptureHook{Command: []string{script}}, minimalTestJPEG(), "kspb", observed)
+	if err != nil {
+		t.Fatalf("runHook: %v", err)
+	}
+	if want := append([]byte{0xFF, 0xD8}, "kspb"...); !bytes.Equal(out, want) {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestRunHook_Failures(t *testing.T) {
+	tests := []struct {
+		name, body, want string
+	}{
+		{"exit status", "echo 'model missing' >&2; exit 3", "model missing"},
+		{"not a JPEG", "echo hello", "not a JPEG"},
+		{"timeout", "sleep 5", "timed out"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &PostCaptureHook{Command: []string{writeHookScript(t, tt.body)}, Timeout: 200 * time.Millisecond}
+			start := time.Now()
+			_, err := runHook(context.Background(), hook, minimalTestJPEG(), "kspb", time.Now())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 3*time.Second {
+				t.Errorf("hook took %v, want it killed at its timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestCaptureWorker_PostCaptureHook(t *testing.T) {
+	frame := minimalTestJPEG()
+	observation := timepkg.ObservationResult{Time: time.Now().UTC()}
+
+	// Empty output keeps the frame
+	w := newHookWorker(t, &PostCaptureHook{Command: []string{writeHookScript(t, "cat >/dev/null")}})
+	out, ok := w.applyPostCaptureHook(context.Background(), frame, observation)
+	if !ok || !bytes.Equal(out, frame) {
+		t.Errorf("empty hook output: ok = %v, frame changed = %v", ok, !bytes.Equal(out, frame))
+	}
+
+	// A failure fails the capture by default
+	failing := []string{writeHookScript(t, "exit 1")}
+	w = newHookWorker(t, &PostCaptureHook{Command: failing})
+	if _, ok := w.applyPostCaptureHook(context.Background(), frame, observation); ok {
+		t.Error("a failed hook should fail the capture")
+	}
+	stats := w.GetStats()
+	if stats.CapturesFailed != 1 || stats.PostCaptureHook == nil || stats.PostCaptureHook.Failures != 1 || stats.PostCaptureHook.LastError == "" {
+		t.Errorf("failed = %d, hook stats = %+v", stats.CapturesFailed, stats.PostCaptureHook)
+	}
+
+	// ...or passes the unhooked frame through
+	w = newHookWorker(t, &PostCaptureHook{Command: failing, PassThrough: true})
+	out, ok = w.applyPostCaptureHook(context.Background(), frame, observation)
+	if !ok || !bytes.Equal(out, frame) {
+		t.Error("a passthrough hook should keep the original frame")
+	}
+	if stats := w.GetStats(); stats.CapturesFailed != 0 || stats.PostCaptureHook.PassedThrough != 1 {
+		t.Errorf("failed = %d, hook stats = %+v", stats.CapturesFailed, stats.PostCaptureHook)
+	}
+
+	if newHookWorker(t, nil).GetStats().PostCaptureHook != nil {
+		t.Error("expected no hook stats without a hook")
+	}
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import "time"
+
+// minIntervalWait returns how long until a capture may start under the camera's
+// minimum interval, or 0 if it may start now
+func (w *CaptureWorker) minIntervalWait(now time.Time) time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	floor := w.config.MinInterval
+	if floor <= 0 || w.lastCaptureStart.IsZero() {
+		return 0
+	}
+	return max(0, floor-now.Sub(w.lastCaptureStart))
+}
+
+// holdForMinInterval counts and logs a capture request held back by the minimum
+// interval. joined is true when a deferred capture was already pending.
+func (w *CaptureWorker) holdForMinInterval(reason string, wait time.Duration, joined bool) {
+	w.mu.Lock()
+	w.minIntervalHeld++
+	w.mu.Unlock()
+	if joined {
+		w.logger.Debug("Capture request joined the capture deferred by the minimum interval",
+			"camera", w.camera.ID(),
+			"reason", reason)
+		return
+	}
+	w.logger.Info("Capture deferred by the camera's minimum interval",
+		"camera", w.camera.ID(),
+		"reason", reason,
+		"min_interval", w.config.MinInterval,
+		"wait", wait)
+}
+
+// captureBlocked reports whether a deferred capture must be dropped when it falls
+// due: capture is paused for queue pressure, the camera is in backoff, or a camera
+// read is still running
+func (w *CaptureWorker) captureBlocked() bool {
+	if w.queue.IsCapturePaused() {
+		return true
+	}
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.currentlyCapturing || w.cameraBusy || time.Now().Before(w.state.NextAttempt)
+}
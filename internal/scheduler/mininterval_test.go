@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// timedCamera records when each capture started
+type timedCamera struct {
+	mockCamera
+	mu     sync.Mutex
+	starts []time.Time
+}
+
+func (c *timedCamera) Capture(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	c.starts = append(c.starts, time.Now())
+	c.mu.Unlock()
+	return c.mockCamera.Capture(ctx)
+}
+
+func (c *timedCamera) captureStarts() []time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Time(nil), c.starts...)
+}
+
+func TestCaptureWorker_MinIntervalRaisesInterval(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "fragile", camType: "http"},
+		CameraConfig: CameraConfig{ID: "fragile", MinInterval: 2 * time.Minute},
+		Queue:        newTestQueue(t, "fragile"),
+		IntervalSecs: 1,
+	})
+	if got := w.GetStats().Interval; got != 2*time.Minute {
+		t.Errorf("interval = %v, want the 2m floor", got)
+	}
+}
+
+func TestCaptureWorker_MinIntervalDefersTriggers(t *testing.T) {
+	const floor = 300 * time.Millisecond
+	cam := &timedCamera{mockCamera: mockCamera{id: "fragile", camType: "http", data: minimalTestJPEG()}}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "fragile", MinInterval: floor},
+		Queue:        newTestQueue(t, "fragile"),
+		IntervalSecs: 60,
+	})
+	w.Start()
+	defer w.Stop()
+
+	waitFor := func(n int) []time.Time {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			if starts := cam.captureStarts(); len(starts) >= n {
+				return starts
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("got %d captures, want %d", len(cam.captureStarts()), n)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor(1)
+	for w.GetStats().LastCaptureTime.IsZero() {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Both triggers arrive inside the floor: the first is deferred, the second joins it
+	w.TriggerCapture("mqtt")
+	time.Sleep(20 * time.Millisecond)
+	w.TriggerCapture("mqtt")
+
+	starts := waitFor(2)
+	if gap := starts[1].Sub(starts[0]); gap < floor {
+		t.Errorf("second capture started %v after the first, want at least %v", gap, floor)
+	}
+	time.Sleep(floor + 100*time.Millisecond)
+	if n := len(cam.captureStarts()); n != 2 {
+		t.Errorf("got %d captures, want the triggers coalesced into one", n)
+	}
+	if held := w.GetStats().MinIntervalHeld; held != 2 {
+		t.Errorf("MinIntervalHeld = %d, want 2", held)
+	}
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"time"
+
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// OfflineImageConfig configures the image queued in place of frames while a camera
+// keeps failing
+type OfflineImageConfig struct {
+	Data  []byte        // Operator-supplied JPEG, loaded once
+	After time.Duration // How long captures must fail first
+}
+
+// queueOfflineImage queues the offline image, stamped with the current time, when
+// captures have been failing for longer than the configured duration. Called on
+// every capture tick, including those skipped by the failure backoff, so the image
+// keeps arriving at the normal interval.
+func (w *CaptureWorker) queueOfflineImage() {
+	cfg := w.config.OfflineImage
+	if cfg == nil || w.queue.IsCapturePaused() {
+		return
+	}
+
+	w.mu.Lock()
+	since := w.failingSince
+	if since.IsZero() || time.Since(since) < cfg.After {
+		w.mu.Unlock()
+		return
+	}
+	first := !w.offlineActive
+	w.offlineActive = true
+	w.mu.Unlock()
+	if first {
+		w.logger.Warn("Camera offline - uploading the offline image until captures recover",
+			"camera", w.camera.ID(),
+			"failing_since", since.Format(time.RFC3339))
+	}
+
+	observation := w.determineObservation(time.Now().UTC(), nil)
+	data := cfg.Data
+	if w.shouldStamp(observation) {
+		// Builtin stamping: the image is static, so there is nothing to gain from exiftool
+		if stamp := timepkg.StampBridgeEXIF(data, observation, timepkg.FrameMeta{Note: w.config.ExifNote}); stamp.Stamped {
+			data = w.commentJPEG(stamp.Data, stamp.Marker)
+		}
+	}
+	if err := w.queue.Enqueue(data, observation.Time, string(observation.Source), string(observation.Confidence)); err != nil {
+		w.logEnqueueError(err)
+		return
+	}
+	w.mu.Lock()
+	w.offlineQueued++
+	w.mu.Unlock()
+}
+
+// recordFailing starts the failure streak the offline image waits on (caller must hold lock)
+func (w *CaptureWorker) recordFailing(now time.Time) {
+	if w.failingSince.IsZero() {
+		w.failingSince = now
+	}
+}
+
+// recordRecovered ends the failure streak, logging when the offline image stops
+func (w *CaptureWorker) recordRecovered() {
+	w.mu.Lock()
+	wasOffline := w.offlineActive
+	w.failingSince = time.Time{}
+	w.offlineActive = false
+	w.mu.Unlock()
+	if wasOffline {
+		w.logger.Info("Camera recovered - offline image no longer uploaded", "camera", w.camera.ID())
+	}
+}
@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newOfflineWorker(t *testing.T, after time.Duration) (*CaptureWorker, *mockCamera) {
+	t.Helper()
+	cam := &mockCamera{id: "offline-cam", camType: "http", err: errors.New("connection refused")}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: cam,
+		CameraConfig: CameraConfig{ID: "offline-cam", OfflineImage: &OfflineImageConfig{
+			Data: testJPEG(t, 32, 24), After: after,
+		}},
+		Queue: newTestQueue(t, "offline-cam"),
+	})
+	return w, cam
+}
+
+func TestCaptureWorker_OfflineImageUntilRecovery(t *testing.T) {
+	w, cam := newOfflineWorker(t, 0)
+
+	// Nothing is queued before the camera has failed
+	w.queueOfflineImage()
+	if n := w.queue.GetImageCount(); n != 0 {
+		t.Fatalf("queued %d offline images before any failure", n)
+	}
+
+	w.capture()
+	before := time.Now()
+	w.queueOfflineImage()
+	if n := w.queue.GetImageCount(); n != 1 {
+		t.Fatalf("queued %d images after the failure, want the offline image", n)
+	}
+	images, _ := w.queue.Peek(1)
+	if age := before.Sub(images[0].Timestamp); age > time.Second || age < -time.Second {
+		t.Errorf("offline image timestamp %v, want the current time", images[0].Timestamp)
+	}
+	if stats := w.GetStats(); !stats.OfflineActive || stats.OfflineQueued != 1 {
+		t.Errorf("OfflineActive=%v OfflineQueued=%d, want true/1", stats.OfflineActive, stats.OfflineQueued)
+	}
+
+	cam.err = nil
+	cam.data = testJPEG(t, 64, 48)
+	w.capture()
+	w.queueOfflineImage()
+	if n := w.queue.GetImageCount(); n != 2 {
+		t.Errorf("queued %d images after recovery, want the offline image and the frame", n)
+	}
+	if w.GetStats().OfflineActive {
+		t.Error("offline image still active after a successful capture")
+	}
+}
+
+func TestCaptureWorker_OfflineImageWaitsForFailureDuration(t *testing.T) {
+	w, _ := newOfflineWorker(t, time.Hour)
+	w.capture()
+	w.queueOfflineImage()
+	if n := w.queue.GetImageCount(); n != 0 {
+		t.Errorf("queued %d offline images before the failure duration, want 0", n)
+	}
+}
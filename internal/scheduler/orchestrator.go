@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,7 +21,7 @@ type Orchestrator struct {
 	captureWorkers  map[string]*CaptureWorker
 	uploadWorker    *UploadWorker
 	authority       *timepkg.Authority
-	exifHelper      *timepkg.ExifToolHelper
+	timeHealth      *timepkg.TimeHealth // Kept so authority can be rebuilt on timezone change
 	resourceLimiter *resource.Limiter
 
 	// Configuration
@@ -34,6 +35,12 @@ type Orchestrator struct {
 
 	// Start time for uptime tracking
 	startTime time.Time
+
+	// Stops the queue manager's background workers; nil while idle without cameras
+	background context.CancelFunc
+
+	// Wall-clock jumps seen (ClockJumpThreshold)
+	clockJumps ClockJumpStats
 }
 
 // OrchestratorConfig configures the orchestrator
@@ -43,17 +50,54 @@ type OrchestratorConfig struct {
 	QueueMaxTotalMB int    // Default: 100
 	QueueMaxHeapMB  int    // Default: 400
 
+	// QueueFallbackPath is where queues move if QueueBasePath's filesystem turns
+	// read-only. Default: "" (enqueues fail until it is writable again)
+	QueueFallbackPath string
+
+	// Queue compaction (orphaned partial files, state drift)
+	QueueCompactionSecs   int // Default: 600
+	QueueOrphanMaxAgeSecs int // Default: 600
+	QueueReconcileSecs    int // Default: 60 (state drift only; skips unchanged queues)
+
 	// Time settings
-	Timezone string // IANA timezone, e.g., "America/Los_Angeles"
+	Timezone   string // IANA timezone, e.g., "America/Los_Angeles"
+	TimePolicy string // Capture behavior while time is unhealthy (default stamp_low)
+
+	// RegressionPolicy handles frames older than the newest queued frame: clamp
+	// (default), reject or auto. Regressions within RegressionTolerance are ignored
+	RegressionPolicy    string
+	RegressionTolerance time.Duration
 
 	// Upload settings
-	MinUploadInterval    time.Duration // Default: 1 second
-	AuthBackoffSecs      int           // Default: 60
-	MaxConcurrentUploads int           // Default: 2 (conservative for slow networks)
+	MinUploadInterval    time.Duration                    // Default: 1 second
+	AuthBackoffSecs      int                              // Default: 60
+	MaxConcurrentUploads int                              // Default: 2 (conservative for slow networks)
+	UploadConcurrency    *ConcurrencyTuning               // Auto-tune concurrent uploads within a range (default: fixed)
+	UploadCircuitBreaker *CircuitBreaker                  // Pause uploads to an unreachable server (default: disabled)
+	ConnectionInterval   time.Duration                    // Minimum time between new upload connections (default: 2s)
+	UploadQuietHours     *QuietHours                      // Daily window with no uploads, in Timezone (default: none)
+	OnSLAChange          func(SLAEvent)                   // Called on camera freshness SLA breach/recovery (optional)
+	OnUploadFailure      func(cameraID string, err error) // Called when an upload fails after its retry (optional)
+
+	// UploadMaxBytesPerSec caps the total upload rate across all cameras (default: 0,
+	// unlimited)
+	UploadMaxBytesPerSec int64
 
 	// Resource management
 	ResourceLimiter *resource.Limiter // Optional: limits concurrent CPU-intensive work
 
+	// Sequences numbers frames of cameras with ExifSequence (optional)
+	Sequences *SequenceStore
+
+	// StayActiveWithoutCameras keeps the upload loop and queue maintenance running
+	// with no cameras. Default: they stop until a camera is added
+	StayActiveWithoutCameras bool
+
+	// ClockJumpThreshold is how far the wall clock must jump, e.g. across a suspend
+	// and resume, before capture timers and the daily counter are realigned.
+	// Default: 0 (disabled)
+	ClockJumpThreshold time.Duration
+
 	// Logger
 	Logger Logger
 }
@@ -61,12 +105,15 @@ type OrchestratorConfig struct {
 // DefaultOrchestratorConfig returns sensible defaults
 func DefaultOrchestratorConfig() OrchestratorConfig {
 	return OrchestratorConfig{
-		QueueBasePath:        "/dev/shm/aviationwx",
-		QueueMaxTotalMB:      100,
-		QueueMaxHeapMB:       400,
-		MinUploadInterval:    time.Second,
-		AuthBackoffSecs:      60,
-		MaxConcurrentUploads: 2, // Conservative for slow networks
+		QueueBasePath:         "/dev/shm/aviationwx",
+		QueueMaxTotalMB:       100,
+		QueueMaxHeapMB:        400,
+		QueueCompactionSecs:   600,
+		QueueOrphanMaxAgeSecs: 600,
+		QueueReconcileSecs:    60,
+		MinUploadInterval:     time.Second,
+		AuthBackoffSecs:       60,
+		MaxConcurrentUploads:  2, // Conservative for slow networks
 	}
 }
 
@@ -86,6 +133,7 @@ func NewOrchestrator(config OrchestratorConfig) (*Orchestrator, error) {
 		MaxHeapMB:          config.QueueMaxHeapMB,
 		MemoryCheckSeconds: 5,
 		EmergencyThinRatio: 0.5,
+		FallbackPath:       config.QueueFallbackPath,
 	}
 
 	queueManager, err := queue.NewManager(queueConfig, nil)
@@ -95,25 +143,23 @@ func NewOrchestrator(config OrchestratorConfig) (*Orchestrator, error) {
 	}
 
 	// Create time authority
-	var authority *timepkg.Authority
-	authorityConfig := timepkg.DefaultAuthorityConfig()
-	if config.Timezone != "" {
-		authorityConfig.Timezone = config.Timezone
+	authority, err := newAuthority(nil, config.Timezone)
+	if err != nil && config.Timezone != "" {
+		// An invalid zone should not take capture down; fall back to the system zone
+		logger.Warn("Invalid timezone, using system timezone",
+			"timezone", config.Timezone,
+			"error", err)
+		config.Timezone = ""
+		authority, err = newAuthority(nil, "")
 	}
-
-	authority, err = timepkg.NewAuthority(nil, authorityConfig)
 	if err != nil {
 		cancel()
 		return nil, fmt.Errorf("create time authority: %w", err)
 	}
 
-	// Try to create exiftool helper (optional, may not be available)
-	var exifHelper *timepkg.ExifToolHelper
-	exifHelper, err = timepkg.DefaultExifToolHelper()
-	if err != nil {
-		logger.Warn("exiftool not available, camera EXIF reading disabled",
-			"error", err)
-		exifHelper = nil
+	// exiftool is optional; while it is missing, discovery is retried periodically
+	if exifHelper := timepkg.CurrentExifTool(); exifHelper == nil {
+		logger.Warn("exiftool not available, camera EXIF reading disabled until it is installed")
 	} else {
 		version, _ := exifHelper.GetVersion()
 		logger.Info("exiftool available", "version", version)
@@ -130,7 +176,6 @@ func NewOrchestrator(config OrchestratorConfig) (*Orchestrator, error) {
 		queueManager:    queueManager,
 		captureWorkers:  make(map[string]*CaptureWorker),
 		authority:       authority,
-		exifHelper:      exifHelper,
 		resourceLimiter: resourceLimiter,
 		config:          config,
 		ctx:             ctx,
@@ -146,74 +191,229 @@ func (o *Orchestrator) AddCamera(cam camera.Camera, config CameraConfig, interva
 
 	cameraID := cam.ID()
 
+	if config.CatchupThreshold <= 0 {
+		config.CatchupThreshold = CatchupThresholdForInterval(intervalSecs, 0)
+	}
+
 	// Create queue for this camera
-	queueConfig := queue.DefaultQueueConfig()
+	queueConfig := config.Queue.apply(queue.DefaultQueueConfig())
 	q, err := o.queueManager.CreateQueue(cameraID, queueConfig)
 	if err != nil {
 		return fmt.Errorf("create queue for camera %s: %w", cameraID, err)
 	}
 
+	// Thumbnail renditions get their own queue so they upload independently
+	var thumbQueue *queue.Queue
+	if config.Thumbnail != nil {
+		thumbQueue, err = o.queueManager.CreateQueue(thumbnailQueueID(cameraID), queueConfig)
+		if err != nil {
+			o.logger.Warn("Could not create thumbnail queue, uploading full images only",
+				"camera", cameraID,
+				"error", err)
+			config.Thumbnail = nil
+		}
+	}
+	var spectroQueue *queue.Queue
+	if config.Spectrogram != nil {
+		spectroQueue, err = o.queueManager.CreateQueue(spectrogramQueueID(cameraID), queueConfig)
+		if err != nil {
+			o.logger.Warn("Could not create spectrogram queue, uploading images only",
+				"camera", cameraID,
+				"error", err)
+			config.Spectrogram = nil
+		}
+	}
+	regionQueues := make(map[string]*queue.Queue, len(config.Regions))
+	regions := config.Regions[:0:0]
+	for _, region := range config.Regions {
+		rq, err := o.queueManager.CreateQueue(regionQueueID(cameraID, region.Name), queueConfig)
+		if err != nil {
+			o.logger.Warn("Could not create region queue, region not uploaded",
+				"camera", cameraID,
+				"region", region.Name,
+				"error", err)
+			continue
+		}
+		regionQueues[region.Name] = rq
+		regions = append(regions, region)
+	}
+	config.Regions = regions
+
 	// Create capture worker
+	logger := o.logger
+	if config.Logger != nil {
+		logger = config.Logger
+	}
 	workerConfig := CaptureWorkerConfig{
-		Camera:          cam,
-		CameraConfig:    config,
-		Queue:           q,
-		Authority:       o.authority,
-		ExifHelper:      o.exifHelper,
-		ResourceLimiter: o.resourceLimiter,
-		IntervalSecs:    intervalSecs,
-		Logger:          o.logger,
-		OnCapture:       onCapture,
+		Camera:              cam,
+		CameraConfig:        config,
+		Queue:               q,
+		ThumbnailQueue:      thumbQueue,
+		SpectrogramQueue:    spectroQueue,
+		RegionQueues:        regionQueues,
+		Authority:           o.authority,
+		ExifTool:            timepkg.CurrentExifTool,
+		ResourceLimiter:     o.resourceLimiter,
+		Sequences:           o.config.Sequences,
+		IntervalSecs:        intervalSecs,
+		TimePolicy:          o.config.TimePolicy,
+		RegressionPolicy:    o.config.RegressionPolicy,
+		RegressionTolerance: o.config.RegressionTolerance,
+		Logger:              logger,
+		OnCapture:           onCapture,
 	}
 
 	worker := NewCaptureWorker(workerConfig)
 	o.captureWorkers[cameraID] = worker
 
 	// Create upload worker if it doesn't exist yet
-	if o.uploadWorker == nil {
+	newUploadWorker := o.uploadWorker == nil
+	if newUploadWorker {
 		maxConcurrent := 2 // Default
 		if o.config.MaxConcurrentUploads > 0 {
 			maxConcurrent = o.config.MaxConcurrentUploads
 		}
 
 		uploadConfig := UploadWorkerConfig{
-			MinUploadInterval: o.config.MinUploadInterval,
-			AuthBackoff:       time.Duration(o.config.AuthBackoffSecs) * time.Second,
-			RetryDelay:        5 * time.Second,
-			MaxConcurrent:     maxConcurrent,
-			Logger:            o.logger,
+			Location:           o.authority.GetTimezone(),
+			MinUploadInterval:  o.config.MinUploadInterval,
+			AuthBackoff:        time.Duration(o.config.AuthBackoffSecs) * time.Second,
+			RetryDelay:         5 * time.Second,
+			MaxConcurrent:      maxConcurrent,
+			Concurrency:        o.config.UploadConcurrency,
+			CircuitBreaker:     o.config.UploadCircuitBreaker,
+			ConnectionInterval: o.config.ConnectionInterval,
+			QuietHours:         o.config.UploadQuietHours,
+			OnSLAChange:        o.config.OnSLAChange,
+			OnUploadFailure:    o.config.OnUploadFailure,
+			StayActive:         o.config.StayActiveWithoutCameras,
+			Logger:             o.logger,
+			MaxBytesPerSecond:  o.config.UploadMaxBytesPerSec,
 		}
 		o.uploadWorker = NewUploadWorker(uploadConfig)
 	}
 
 	// Add queue with camera-specific uploader
 	o.uploadWorker.AddQueue(cameraID, q, config, uploader)
+	uploadWorker := o.uploadWorker
+	worker.uploadFailingSince = func() time.Time { return uploadWorker.FailingSince(cameraID) }
+	if thumbQueue != nil {
+		o.uploadWorker.AddQueue(thumbnailQueueID(cameraID), thumbQueue, thumbnailUploadConfig(cameraID, config), uploader)
+	}
+	if spectroQueue != nil {
+		o.uploadWorker.AddQueue(spectrogramQueueID(cameraID), spectroQueue, spectrogramUploadConfig(cameraID, config), uploader)
+	}
+	for _, region := range config.Regions {
+		o.uploadWorker.AddQueue(regionQueueID(cameraID, region.Name), regionQueues[region.Name], regionUploadConfig(cameraID, region, config), uploader)
+	}
 
-	// If orchestrator has already been started, start this worker immediately
+	// If orchestrator has already been started, start this worker immediately, and
+	// wake up if it was idle without cameras
 	if !o.startTime.IsZero() {
+		if o.background == nil {
+			o.startBackgroundLocked()
+			o.logger.Info("Orchestrator resumed", "camera", cameraID)
+		}
+		if newUploadWorker {
+			o.uploadWorker.Start()
+		}
 		worker.Start()
 		o.logger.Info("Capture worker started (hot-reload)", "camera", cameraID)
 	}
 
 	o.logger.Info("Camera added",
 		"camera", cameraID,
-		"interval_secs", intervalSecs)
+		"interval_secs", intervalSecs,
+		"catchup_threshold", config.CatchupThreshold)
 
 	return nil
 }
 
+// thumbnailUploadConfig is the upload-side config of a camera's thumbnail queue: the
+// camera's settings with the thumbnail remote path and no freshness SLA of its own
+func thumbnailUploadConfig(cameraID string, config CameraConfig) CameraConfig {
+	thumb := config
+	thumb.ID = thumbnailQueueID(cameraID)
+	thumb.RemotePath = config.Thumbnail.RemotePath
+	thumb.Thumbnail = nil
+	thumb.FreshnessSLA = 0
+	thumb.SizeBand = nil
+	return thumb
+}
+
+// spectrogramUploadConfig is thumbnailUploadConfig for the spectrogram queue
+func spectrogramUploadConfig(cameraID string, config CameraConfig) CameraConfig {
+	spectro := config
+	spectro.ID = spectrogramQueueID(cameraID)
+	spectro.RemotePath = config.Spectrogram.RemotePath
+	spectro.Thumbnail = nil
+	spectro.Spectrogram = nil
+	spectro.FreshnessSLA = 0
+	spectro.SizeBand = nil
+	spectro.FilenameTimeTokens = nil // Spectrograms carry no bridge marker
+	return spectro
+}
+
+// regionUploadConfig is thumbnailUploadConfig for one region's queue
+func regionUploadConfig(cameraID string, region RegionConfig, config CameraConfig) CameraConfig {
+	cfg := config
+	cfg.ID = regionQueueID(cameraID, region.Name)
+	cfg.RemotePath = region.RemotePath
+	cfg.Thumbnail = nil
+	cfg.Spectrogram = nil
+	cfg.Regions = nil
+	cfg.FreshnessSLA = 0
+	cfg.SizeBand = nil
+	return cfg
+}
+
 // RemoveCamera removes a camera from the orchestrator
 func (o *Orchestrator) RemoveCamera(cameraID string) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
 	// Stop and remove capture worker
+	hasThumbnail, hasSpectrogram := false, false
+	var regions []string
 	if worker, ok := o.captureWorkers[cameraID]; ok {
 		worker.Stop()
+		hasThumbnail = worker.thumbQueue != nil
+		hasSpectrogram = worker.spectroQueue != nil
+		for name := range worker.regionQueues {
+			regions = append(regions, name)
+		}
 		delete(o.captureWorkers, cameraID)
 		o.logger.Info("Capture worker stopped", "camera", cameraID)
 	}
 
+	if hasThumbnail {
+		thumbID := thumbnailQueueID(cameraID)
+		if o.uploadWorker != nil {
+			o.uploadWorker.RemoveQueue(thumbID)
+		}
+		if err := o.queueManager.RemoveQueue(thumbID); err != nil {
+			o.logger.Warn("Could not remove thumbnail queue", "camera", cameraID, "error", err)
+		}
+	}
+	if hasSpectrogram {
+		spectroID := spectrogramQueueID(cameraID)
+		if o.uploadWorker != nil {
+			o.uploadWorker.RemoveQueue(spectroID)
+		}
+		if err := o.queueManager.RemoveQueue(spectroID); err != nil {
+			o.logger.Warn("Could not remove spectrogram queue", "camera", cameraID, "error", err)
+		}
+	}
+	for _, name := range regions {
+		regionID := regionQueueID(cameraID, name)
+		if o.uploadWorker != nil {
+			o.uploadWorker.RemoveQueue(regionID)
+		}
+		if err := o.queueManager.RemoveQueue(regionID); err != nil {
+			o.logger.Warn("Could not remove region queue", "camera", cameraID, "region", name, "error", err)
+		}
+	}
+
 	// Remove queue from upload worker
 	if o.uploadWorker != nil {
 		o.uploadWorker.RemoveQueue(cameraID)
@@ -225,30 +425,65 @@ func (o *Orchestrator) RemoveCamera(cameraID string) error {
 	}
 
 	o.logger.Info("Camera removed", "camera", cameraID)
+
+	if len(o.captureWorkers) == 0 && o.background != nil && !o.config.StayActiveWithoutCameras {
+		o.background()
+		o.background = nil
+		o.logger.Info("Orchestrator idle - no cameras, waiting for one to be added")
+	}
 	return nil
 }
 
 // SetTimeHealth sets the NTP time health checker
 func (o *Orchestrator) SetTimeHealth(timeHealth *timepkg.TimeHealth) {
-	// Recreate authority with time health
-	authorityConfig := timepkg.DefaultAuthorityConfig()
-	if o.config.Timezone != "" {
-		authorityConfig.Timezone = o.config.Timezone
-	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
 
-	authority, err := timepkg.NewAuthority(timeHealth, authorityConfig)
+	// Recreate authority with time health, keeping the current timezone
+	authority, err := newAuthority(timeHealth, o.config.Timezone)
 	if err != nil {
 		o.logger.Error("Failed to recreate authority with time health", "error", err)
 		return
 	}
 
+	o.timeHealth = timeHealth
+	o.setAuthorityLocked(authority)
+}
+
+// SetTimezone switches every time-dependent subsystem to a new IANA timezone:
+// the time authority used by capture workers and the daily upload counter.
+func (o *Orchestrator) SetTimezone(timezone string) error {
 	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	authority, err := newAuthority(o.timeHealth, timezone)
+	if err != nil {
+		return err
+	}
+
+	o.config.Timezone = timezone
+	o.setAuthorityLocked(authority)
+
+	o.logger.Info("Timezone updated for all subsystems", "timezone", authority.GetTimezoneName())
+	return nil
+}
+
+// newAuthority builds a time authority for timezone ("" = system zone)
+func newAuthority(timeHealth *timepkg.TimeHealth, timezone string) (*timepkg.Authority, error) {
+	authorityConfig := timepkg.DefaultAuthorityConfig()
+	authorityConfig.Timezone = timezone
+	return timepkg.NewAuthority(timeHealth, authorityConfig)
+}
+
+// setAuthorityLocked installs authority everywhere it is consumed (caller must hold o.mu)
+func (o *Orchestrator) setAuthorityLocked(authority *timepkg.Authority) {
 	o.authority = authority
-	// Update all capture workers
 	for _, worker := range o.captureWorkers {
 		worker.authority = authority
 	}
-	o.mu.Unlock()
+	if o.uploadWorker != nil {
+		o.uploadWorker.SetLocation(authority.GetTimezone())
+	}
 }
 
 // SetTimeAuthority updates the time authority for all workers
@@ -256,14 +491,91 @@ func (o *Orchestrator) SetTimeAuthority(authority *timepkg.Authority) {
 	o.mu.Lock()
 	defer o.mu.Unlock()
 
-	o.authority = authority
+	o.setAuthorityLocked(authority)
 
-	// Update all capture workers
+	o.logger.Info("Time authority updated for all workers")
+}
+
+// SetTimePolicy updates the time-unhealthy capture policy for all workers
+func (o *Orchestrator) SetTimePolicy(policy string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.config.TimePolicy = NormalizeTimePolicy(policy)
 	for _, worker := range o.captureWorkers {
-		worker.authority = authority
+		worker.SetTimePolicy(policy)
 	}
 
-	o.logger.Info("Time authority updated for all workers")
+	o.logger.Info("Time-unhealthy policy updated", "policy", o.config.TimePolicy)
+}
+
+// SetRegressionPolicy updates timestamp regression handling for all workers
+func (o *Orchestrator) SetRegressionPolicy(policy string, tolerance time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.config.RegressionPolicy = NormalizeRegressionPolicy(policy)
+	o.config.RegressionTolerance = tolerance
+	for _, worker := range o.captureWorkers {
+		worker.SetRegressionPolicy(policy, tolerance)
+	}
+
+	o.logger.Info("Timestamp regression policy updated", "policy", o.config.RegressionPolicy, "tolerance", tolerance)
+}
+
+// SetUploadQuietHours updates the global upload quiet window; nil disables it
+func (o *Orchestrator) SetUploadQuietHours(q *QuietHours) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.config.UploadQuietHours = q
+	if o.uploadWorker != nil {
+		o.uploadWorker.SetQuietHours(q)
+	}
+
+	o.logger.Info("Upload quiet hours updated", "window", q.String())
+}
+
+// SetUploadBandwidthLimit updates the total upload rate limit in bytes per second;
+// 0 removes it
+func (o *Orchestrator) SetUploadBandwidthLimit(bytesPerSec int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.config.UploadMaxBytesPerSec == bytesPerSec {
+		return
+	}
+	o.config.UploadMaxBytesPerSec = bytesPerSec
+	if o.uploadWorker != nil {
+		o.uploadWorker.SetBandwidthLimit(bytesPerSec)
+	}
+
+	o.logger.Info("Upload bandwidth limit updated", "bytes_per_sec", bytesPerSec)
+}
+
+// SetUploadConnectionInterval updates the minimum time between new upload connections;
+// 0 restores the default
+func (o *Orchestrator) SetUploadConnectionInterval(d time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.config.ConnectionInterval == d {
+		return
+	}
+	o.config.ConnectionInterval = d
+	if o.uploadWorker != nil {
+		o.uploadWorker.SetConnectionInterval(d)
+	}
+
+	o.logger.Info("Upload connection interval updated", "interval", d)
+}
+
+// secondsOrDefault converts a seconds setting to a duration, using def when unset
+func secondsOrDefault(secs, def int) time.Duration {
+	if secs <= 0 {
+		secs = def
+	}
+	return time.Duration(secs) * time.Second
 }
 
 // Start starts all workers
@@ -273,9 +585,11 @@ func (o *Orchestrator) Start() error {
 
 	o.startTime = time.Now()
 
-	// Start queue manager background workers
-	go o.queueManager.StartMemoryMonitor(o.ctx)
-	go o.queueManager.StartExpirationWorker(o.ctx, time.Minute)
+	if len(o.captureWorkers) > 0 || o.config.StayActiveWithoutCameras {
+		o.startBackgroundLocked()
+	} else {
+		o.logger.Info("Orchestrator idle - no cameras, waiting for one to be added")
+	}
 
 	// Start capture workers
 	for cameraID, worker := range o.captureWorkers {
@@ -297,6 +611,22 @@ func (o *Orchestrator) Start() error {
 	return nil
 }
 
+// startBackgroundLocked starts the queue manager's background workers
+func (o *Orchestrator) startBackgroundLocked() {
+	ctx, cancel := context.WithCancel(o.ctx)
+	o.background = cancel
+	go o.queueManager.StartMemoryMonitor(ctx)
+	go o.queueManager.StartExpirationWorker(ctx, time.Minute)
+	go o.queueManager.StartCompactionWorker(ctx,
+		secondsOrDefault(o.config.QueueCompactionSecs, 600),
+		secondsOrDefault(o.config.QueueOrphanMaxAgeSecs, 600))
+	go o.queueManager.StartReconcileWorker(ctx,
+		secondsOrDefault(o.config.QueueReconcileSecs, 60))
+	if o.config.ClockJumpThreshold > 0 {
+		go o.watchClockJumps(ctx, o.config.ClockJumpThreshold)
+	}
+}
+
 // Stop stops all workers gracefully
 func (o *Orchestrator) Stop() {
 	o.logger.Info("Stopping orchestrator...")
@@ -321,14 +651,88 @@ func (o *Orchestrator) Stop() {
 	o.logger.Info("Orchestrator stopped")
 }
 
+// GetQualitySeries returns the rolling quality self-check samples for a camera
+func (o *Orchestrator) GetQualitySeries(cameraID string) ([]QualitySample, bool) {
+	o.mu.RLock()
+	worker, ok := o.captureWorkers[cameraID]
+	o.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return worker.GetQualitySeries(), true
+}
+
+// GetCaptureStats returns the capture statistics for a camera
+func (o *Orchestrator) GetCaptureStats(cameraID string) (CaptureStats, bool) {
+	o.mu.RLock()
+	worker, ok := o.captureWorkers[cameraID]
+	o.mu.RUnlock()
+	if !ok {
+		return CaptureStats{}, false
+	}
+	return worker.GetStats(), true
+}
+
+// TriggerCapture requests an immediate capture for a camera outside its schedule. Like
+// ONVIF events, triggers during a capture, backoff or the event cooldown are ignored.
+func (o *Orchestrator) TriggerCapture(cameraID, reason string) error {
+	o.mu.RLock()
+	worker, ok := o.captureWorkers[cameraID]
+	o.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("camera not found: %s", cameraID)
+	}
+	worker.TriggerCapture(reason)
+	return nil
+}
+
 // GetStatus returns the current orchestrator status
 func (o *Orchestrator) GetStatus() OrchestratorStatus {
+	return o.GetStatusPage(StatusPage{})
+}
+
+// StatusPage selects the cameras whose stats a status includes, in camera ID order.
+// Totals (camera count, upload and global queue figures) always cover every camera.
+type StatusPage struct {
+	CameraID string // Only this camera ("" = all)
+	Offset   int    // Cameras skipped
+	Limit    int    // Maximum cameras included (0 = no limit)
+}
+
+// selectCamerasLocked returns the IDs of the cameras on the page, sorted
+func (o *Orchestrator) selectCamerasLocked(page StatusPage) []string {
+	if page.CameraID != "" {
+		if _, ok := o.captureWorkers[page.CameraID]; ok {
+			return []string{page.CameraID}
+		}
+		return nil
+	}
+	ids := make([]string, 0, len(o.captureWorkers))
+	for id := range o.captureWorkers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if page.Offset >= len(ids) {
+		return nil
+	}
+	ids = ids[max(page.Offset, 0):]
+	if page.Limit > 0 && page.Limit < len(ids) {
+		ids = ids[:page.Limit]
+	}
+	return ids
+}
+
+// GetStatusPage returns the orchestrator status with per-camera stats for one page of
+// cameras, so large deployments need not build every camera's stats on each poll
+func (o *Orchestrator) GetStatusPage(page StatusPage) OrchestratorStatus {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
 	// Gather camera stats
-	cameraStats := make([]CameraStatus, 0, len(o.captureWorkers))
-	for cameraID, worker := range o.captureWorkers {
+	selected := o.selectCamerasLocked(page)
+	cameraStats := make([]CameraStatus, 0, len(selected))
+	for _, cameraID := range selected {
+		worker := o.captureWorkers[cameraID]
 		q, ok := o.queueManager.GetQueue(cameraID)
 		if !ok {
 			continue
@@ -337,6 +741,9 @@ func (o *Orchestrator) GetStatus() OrchestratorStatus {
 		captureStats := worker.GetStats()
 		queueStats := q.GetStats()
 		state := worker.GetState()
+		if t := captureStats.Throughput; t != nil && o.uploadWorker != nil {
+			t.UploadsPerMin, t.UploadedBytesPerMin = o.uploadWorker.UploadThroughput(cameraID)
+		}
 
 		cameraStats = append(cameraStats, CameraStatus{
 			CameraID:     cameraID,
@@ -354,8 +761,21 @@ func (o *Orchestrator) GetStatus() OrchestratorStatus {
 		uploadStats = o.uploadWorker.GetStats()
 	}
 
-	// Global queue stats
+	// Global queue stats; per-camera queue stats only for the page
 	globalQueueStats := o.queueManager.GetGlobalStats()
+	if len(selected) < len(o.captureWorkers) {
+		onPage := make(map[string]bool, len(selected))
+		for _, id := range selected {
+			onPage[id] = true
+		}
+		pageStats := make([]queue.QueueStats, 0, len(selected))
+		for _, qs := range globalQueueStats.CameraStats {
+			if onPage[qs.CameraID] {
+				pageStats = append(pageStats, qs)
+			}
+		}
+		globalQueueStats.CameraStats = pageStats
+	}
 
 	// Time info
 	var timeInfo timepkg.TimeInfo
@@ -371,9 +791,61 @@ func (o *Orchestrator) GetStatus() OrchestratorStatus {
 		UploadStats:      uploadStats,
 		GlobalQueueStats: globalQueueStats,
 		TimeInfo:         timeInfo,
+		TimePolicy:       NormalizeTimePolicy(o.config.TimePolicy),
+		RegressionPolicy: NormalizeRegressionPolicy(o.config.RegressionPolicy),
+		Timezones:        o.timezoneStatusLocked(),
+		Idle:             !o.startTime.IsZero() && o.background == nil && o.ctx.Err() == nil,
+		ClockJumps:       o.clockJumpStatsLocked(),
 	}
 }
 
+// TimezoneStatus reports the zone each time-dependent subsystem is using, so a
+// subsystem that missed a timezone change shows up as inconsistent
+type TimezoneStatus struct {
+	Configured   string            `json:"configured"`
+	Authority    string            `json:"authority"`
+	UploadDaily  string            `json:"upload_daily,omitempty"` // Zone for the uploads_today reset
+	CaptureByCam map[string]string `json:"cameras"`
+	Consistent   bool              `json:"consistent"`
+}
+
+// timezoneStatusLocked gathers effective zones (caller must hold o.mu for reading)
+func (o *Orchestrator) timezoneStatusLocked() TimezoneStatus {
+	status := TimezoneStatus{
+		Configured:   o.config.Timezone,
+		CaptureByCam: make(map[string]string, len(o.captureWorkers)),
+		Consistent:   true,
+	}
+	if status.Configured == "" {
+		status.Configured = time.Local.String()
+	}
+
+	check := func(zone string) {
+		if zone != status.Configured {
+			status.Consistent = false
+		}
+	}
+
+	if o.authority != nil {
+		status.Authority = o.authority.GetTimezoneName()
+		check(status.Authority)
+	}
+	for cameraID, worker := range o.captureWorkers {
+		zone := ""
+		if worker.authority != nil {
+			zone = worker.authority.GetTimezoneName()
+		}
+		status.CaptureByCam[cameraID] = zone
+		check(zone)
+	}
+	if o.uploadWorker != nil {
+		status.UploadDaily = o.uploadWorker.Location().String()
+		check(status.UploadDaily)
+	}
+
+	return status
+}
+
 // OrchestratorStatus represents the full system status
 type OrchestratorStatus struct {
 	Running          bool                   `json:"running"`
@@ -383,6 +855,13 @@ type OrchestratorStatus struct {
 	UploadStats      UploadStats            `json:"upload_stats"`
 	GlobalQueueStats queue.GlobalQueueStats `json:"global_queue_stats"`
 	TimeInfo         timepkg.TimeInfo       `json:"time_info"`
+	TimePolicy       string                 `json:"time_unhealthy_policy"`
+	RegressionPolicy string                 `json:"time_regression_policy"`
+	Timezones        TimezoneStatus         `json:"timezones"`
+	Idle             bool                   `json:"idle,omitempty"` // No cameras; background work is paused
+
+	// Suspend/resume and clock jumps; nil when detection is disabled
+	ClockJumps *ClockJumpStats `json:"clock_jumps,omitempty"`
 }
 
 // CameraStatus represents status for a single camera
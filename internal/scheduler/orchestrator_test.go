@@ -1,7 +1,10 @@
 package scheduler
 
 import (
+	"errors"
 	"os"
+	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -308,6 +311,51 @@ func TestOrchestrator_GetStatus(t *testing.T) {
 	}
 }
 
+func TestOrchestrator_GetStatusPage(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	config.QueueBasePath = t.TempDir()
+	orch, err := NewOrchestrator(config)
+	if err != nil {
+		t.Fatalf("NewOrchestrator() error = %v", err)
+	}
+	defer orch.Stop()
+	for _, id := range []string{"cam-c", "cam-a", "cam-b"} {
+		orch.AddCamera(&mockCamera{id: id, camType: "http"}, CameraConfig{ID: id}, 60, &mockUploader{}, nil)
+	}
+
+	ids := func(s OrchestratorStatus) []string {
+		var out []string
+		for _, cs := range s.CameraStats {
+			out = append(out, cs.CameraID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		page StatusPage
+		want []string
+	}{
+		{StatusPage{}, []string{"cam-a", "cam-b", "cam-c"}},
+		{StatusPage{Offset: 1, Limit: 1}, []string{"cam-b"}},
+		{StatusPage{Offset: 2, Limit: 5}, []string{"cam-c"}},
+		{StatusPage{Offset: 3}, nil},
+		{StatusPage{CameraID: "cam-b"}, []string{"cam-b"}},
+		{StatusPage{CameraID: "missing"}, nil},
+	}
+	for _, tt := range tests {
+		status := orch.GetStatusPage(tt.page)
+		if got := ids(status); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%+v: cameras = %v, want %v", tt.page, got, tt.want)
+		}
+		if status.CameraCount != 3 {
+			t.Errorf("%+v: CameraCount = %d, want all 3", tt.page, status.CameraCount)
+		}
+		if len(status.GlobalQueueStats.CameraStats) != len(tt.want) {
+			t.Errorf("%+v: %d queue stats, want %d", tt.page, len(status.GlobalQueueStats.CameraStats), len(tt.want))
+		}
+	}
+}
+
 // TestDefaultOrchestratorConfig tests the default configuration
 func TestDefaultOrchestratorConfig(t *testing.T) {
 	config := DefaultOrchestratorConfig()
@@ -369,3 +417,130 @@ func TestOrchestrator_WithOnCaptureCallback(t *testing.T) {
 		t.Error("Callback should be stored in capture worker")
 	}
 }
+
+func TestOrchestrator_SetTimezonePropagates(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	config.QueueBasePath = t.TempDir()
+	config.Timezone = "America/Chicago"
+
+	orch, err := NewOrchestrator(config)
+	if err != nil {
+		t.Fatalf("NewOrchestrator: %v", err)
+	}
+	defer orch.Stop()
+
+	cam := &mockCamera{id: "tz-cam", camType: "http", data: minimalTestJPEG()}
+	if err := orch.AddCamera(cam, CameraConfig{ID: "tz-cam"}, 60, &mockUploader{}, nil); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+
+	tz := orch.GetStatus().Timezones
+	if !tz.Consistent || tz.Authority != "America/Chicago" || tz.UploadDaily != "America/Chicago" {
+		t.Fatalf("unexpected initial zones: %+v", tz)
+	}
+
+	if err := orch.SetTimezone("Europe/Berlin"); err != nil {
+		t.Fatalf("SetTimezone: %v", err)
+	}
+
+	tz = orch.GetStatus().Timezones
+	if !tz.Consistent {
+		t.Errorf("expected consistent zones after change: %+v", tz)
+	}
+	if tz.Configured != "Europe/Berlin" || tz.UploadDaily != "Europe/Berlin" || tz.CaptureByCam["tz-cam"] != "Europe/Berlin" {
+		t.Errorf("timezone not propagated: %+v", tz)
+	}
+
+	// Rebuilding the authority for time health must keep the new zone
+	orch.SetTimeHealth(nil)
+	if got := orch.GetStatus().Timezones.Authority; got != "Europe/Berlin" {
+		t.Errorf("authority zone after SetTimeHealth = %q, want Europe/Berlin", got)
+	}
+
+	if err := orch.SetTimezone("Not/AZone"); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+	if got := orch.GetStatus().Timezones.Configured; got != "Europe/Berlin" {
+		t.Errorf("invalid timezone should leave config unchanged, got %q", got)
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	// 05:00 UTC is 21:00 the previous day in Los Angeles (PST)
+	now := time.Date(2025, 1, 15, 5, 0, 0, 0, time.UTC)
+	got := startOfDay(now, loc)
+	want := time.Date(2025, 1, 14, 0, 0, 0, 0, loc)
+	if !got.Equal(want) {
+		t.Errorf("startOfDay = %v, want %v", got, want)
+	}
+}
+
+// countingUploader counts successful uploads
+type countingUploader struct {
+	uploads atomic.Int32
+}
+
+func (u *countingUploader) Upload(remotePath string, data []byte) error {
+	u.uploads.Add(1)
+	return nil
+}
+
+func (u *countingUploader) TestConnection() error { return nil }
+
+// TestOrchestrator_IdlesWithoutCameras tests that a bridge with no cameras idles
+// and resumes capturing and uploading once one is added
+func TestOrchestrator_IdlesWithoutCameras(t *testing.T) {
+	config := DefaultOrchestratorConfig()
+	config.QueueBasePath = t.TempDir()
+	orch, err := NewOrchestrator(config)
+	if err != nil {
+		t.Fatalf("NewOrchestrator() error = %v", err)
+	}
+	defer orch.Stop()
+	if err := orch.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if status := orch.GetStatus(); !status.Idle || !status.Running {
+		t.Fatalf("status = idle %v, running %v; want idle and still responding", status.Idle, status.Running)
+	}
+
+	// The upload worker is created after Start and must still upload
+	uploader := &countingUploader{}
+	cam := &mockCamera{id: "cam", camType: "http", err: errors.New("offline")}
+	if err := orch.AddCamera(cam, CameraConfig{ID: "cam", Enabled: true}, 3600, uploader, nil); err != nil {
+		t.Fatalf("AddCamera() error = %v", err)
+	}
+	if orch.GetStatus().Idle {
+		t.Error("still idle after adding a camera")
+	}
+	q, _ := orch.queueManager.GetQueue("cam")
+	if err := q.Enqueue(minimalTestJPEG(), time.Now().UTC(), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	waitFor(t, 10*time.Second, func() bool { return uploader.uploads.Load() == 1 })
+
+	// Removing the last camera idles again
+	if err := orch.RemoveCamera("cam"); err != nil {
+		t.Fatalf("RemoveCamera() error = %v", err)
+	}
+	if !orch.GetStatus().Idle {
+		t.Error("not idle after removing the last camera")
+	}
+	waitFor(t, 5*time.Second, func() bool { return orch.GetStatus().UploadStats.Idle })
+}
+
+// waitFor polls cond until it holds or timeout passes
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before the timeout")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
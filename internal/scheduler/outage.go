@@ -0,0 +1,92 @@
+package scheduler
+
+import "time"
+
+// OutageSlowdown slows a camera's interval captures while its uploads keep failing,
+// so a server outage does not fill the queue at full rate. Unlike queue pressure
+// pausing, it starts before the queue is in trouble and ends with the outage.
+type OutageSlowdown struct {
+	After      time.Duration // Uploads failing this long, with none succeeding, slows capture
+	Multiplier float64       // Capture interval multiplier while slowed (> 1)
+}
+
+// OutageSlowdownStatus reports whether capture is slowed and why
+type OutageSlowdownStatus struct {
+	Active   bool          `json:"active"`
+	Since    time.Time     `json:"since,omitempty"`
+	Reason   string        `json:"reason,omitempty"`
+	Interval time.Duration `json:"interval,omitempty"` // Capture interval while slowed
+	Skipped  int64         `json:"skipped"`            // Interval captures skipped while slowed
+}
+
+// outageState is a capture worker's outage slowdown state (guarded by w.mu)
+type outageState struct {
+	since   time.Time // Zero while capturing at the normal rate
+	reason  string
+	skipped int64
+}
+
+// slowedForOutage reports whether the interval capture due at now is skipped
+// because the camera's uploads have been failing for longer than the policy allows.
+// Capture returns to the normal rate as soon as an upload succeeds.
+func (w *CaptureWorker) slowedForOutage(now time.Time) bool {
+	policy := w.config.OutageSlowdown
+	if policy == nil || w.uploadFailingSince == nil {
+		return false
+	}
+	failingSince := w.uploadFailingSince()
+	outage := !failingSince.IsZero() && now.Sub(failingSince) >= policy.After
+	slowInterval := w.outageInterval()
+
+	w.mu.Lock()
+	started := outage && w.outage.since.IsZero()
+	ended := !outage && !w.outage.since.IsZero()
+	switch {
+	case started:
+		w.outage.since = now
+		w.outage.reason = "uploads failing since " + failingSince.UTC().Format(time.RFC3339)
+	case ended:
+		w.outage.since = time.Time{}
+		w.outage.reason = ""
+	}
+	// Half an interval of slack so a tick landing just early is not skipped
+	skip := outage && now.Sub(w.lastCaptureTime) < slowInterval-w.interval/2
+	if skip {
+		w.outage.skipped++
+	}
+	w.mu.Unlock()
+
+	switch {
+	case started:
+		w.logger.Warn("Slowing capture while uploads fail",
+			"camera", w.camera.ID(),
+			"failing_since", failingSince,
+			"interval", slowInterval)
+	case ended:
+		w.logger.Info("Uploads recovered, capture back to normal rate",
+			"camera", w.camera.ID(),
+			"interval", w.interval)
+	}
+	return skip
+}
+
+// outageInterval is the capture interval while slowed
+func (w *CaptureWorker) outageInterval() time.Duration {
+	return time.Duration(float64(w.interval) * w.config.OutageSlowdown.Multiplier)
+}
+
+// outageStatusLocked returns the slowdown status, or nil when it is not configured
+// (caller must hold w.mu)
+func (w *CaptureWorker) outageStatusLocked() *OutageSlowdownStatus {
+	if w.config.OutageSlowdown == nil {
+		return nil
+	}
+	status := &OutageSlowdownStatus{Skipped: w.outage.skipped}
+	if !w.outage.since.IsZero() {
+		status.Active = true
+		status.Since = w.outage.since
+		status.Reason = w.outage.reason
+		status.Interval = w.outageInterval()
+	}
+	return status
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCaptureWorker_SlowedForOutage(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "outage-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "outage-cam", OutageSlowdown: &OutageSlowdown{After: 10 * time.Minute, Multiplier: 4}},
+		Queue:        newTestQueue(t, "outage-cam"),
+		IntervalSecs: 60,
+	})
+	now := time.Now()
+	var failingSince time.Time
+	w.uploadFailingSince = func() time.Time { return failingSince }
+	w.lastCaptureTime = now.Add(-time.Minute)
+
+	// A short failure streak does not slow capture
+	failingSince = now.Add(-5 * time.Minute)
+	if w.slowedForOutage(now) {
+		t.Fatal("slowed before the outage threshold")
+	}
+
+	// Past the threshold, ticks are skipped until 4 intervals have passed
+	failingSince = now.Add(-15 * time.Minute)
+	if !w.slowedForOutage(now) {
+		t.Fatal("not slowed during a sustained outage")
+	}
+	w.lastCaptureTime = now.Add(-4 * time.Minute)
+	if w.slowedForOutage(now) {
+		t.Error("skipped the capture due at the slowed interval")
+	}
+	status := w.GetStats().OutageSlowdown
+	if status == nil || !status.Active || status.Skipped != 1 || status.Interval != 4*time.Minute || status.Reason == "" {
+		t.Errorf("status during outage = %+v", status)
+	}
+
+	// A successful upload restores the normal rate
+	failingSince = time.Time{}
+	w.lastCaptureTime = now.Add(-time.Minute)
+	if w.slowedForOutage(now) {
+		t.Error("still slowed after uploads recovered")
+	}
+	if status := w.GetStats().OutageSlowdown; status == nil || status.Active || status.Skipped != 1 {
+		t.Errorf("status after recovery = %+v", status)
+	}
+}
+
+func TestUploadWorker_FailingSince(t *testing.T) {
+	w := NewUploadWorker(UploadWorkerConfig{})
+	w.AddQueue("cam", newTestQueue(t, "cam"), CameraConfig{ID: "cam"}, &mockUploader{})
+
+	if got := w.FailingSince("cam"); !got.IsZero() {
+		t.Fatalf("FailingSince before any failure = %v", got)
+	}
+	w.recordFailure("cam", errors.New("connection refused"))
+	first := w.FailingSince("cam")
+	if first.IsZero() {
+		t.Fatal("FailingSince not set by a failure")
+	}
+	w.recordFailure("cam", errors.New("connection refused"))
+	if got := w.FailingSince("cam"); !got.Equal(first) {
+		t.Errorf("FailingSince moved to %v on a later failure, want the streak start %v", got, first)
+	}
+	if got := w.FailingSince("other"); !got.IsZero() {
+		t.Errorf("FailingSince of an unknown camera = %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
+)
+
+// qualitySeriesSize is the number of quality samples kept per camera
+const qualitySeriesSize = 60
+
+// QualitySample is one quality self-check result for a captured frame
+type QualitySample struct {
+	Time time.Time `json:"time"` // Observation time (UTC)
+	image.QualityMetrics
+}
+
+// shouldSampleQuality decides whether this frame is analyzed. An accumulator spreads
+// samples evenly (rate 0.25 = every 4th frame) rather than randomly.
+func (w *CaptureWorker) shouldSampleQuality() bool {
+	rate := w.config.QualitySampleRate
+	if rate <= 0 {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.qualityAccum += rate
+	if w.qualityAccum < 1 {
+		return false
+	}
+	w.qualityAccum -= 1
+	return true
+}
+
+// sampleQuality analyzes a frame and appends it to the rolling quality series
+func (w *CaptureWorker) sampleQuality(ctx context.Context, imageData []byte, observationTime time.Time) {
+	if !w.shouldSampleQuality() {
+		return
+	}
+	if w.resourceLimiter != nil && w.resourceLimiter.ShouldShed(resource.WorkQualityCheck) {
+		return
+	}
+
+	if w.resourceLimiter != nil {
+		if err := w.resourceLimiter.AcquireImageProcessing(ctx); err != nil {
+			return
+		}
+		defer w.resourceLimiter.ReleaseImageProcessing()
+		resource.YieldToHigherPriority()
+	}
+
+	metrics, err := image.AnalyzeQuality(imageData)
+	if err != nil {
+		w.logger.Debug("Quality self-check failed",
+			"camera", w.camera.ID(),
+			"error", err)
+		return
+	}
+
+	w.mu.Lock()
+	w.qualitySeries = append(w.qualitySeries, QualitySample{Time: observationTime, QualityMetrics: metrics})
+	if len(w.qualitySeries) > qualitySeriesSize {
+		w.qualitySeries = w.qualitySeries[len(w.qualitySeries)-qualitySeriesSize:]
+	}
+	w.mu.Unlock()
+}
+
+// GetQualitySeries returns the rolling quality samples, oldest first
+func (w *CaptureWorker) GetQualitySeries() []QualitySample {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	series := make([]QualitySample, len(w.qualitySeries))
+	copy(series, w.qualitySeries)
+	return series
+}
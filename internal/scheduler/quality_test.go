@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+func TestShouldSampleQuality(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want int
+	}{
+		{0, 0},
+		{0.25, 5},
+		{0.5, 10},
+		{1, 20},
+	}
+
+	for _, tt := range tests {
+		w := &CaptureWorker{config: CameraConfig{QualitySampleRate: tt.rate}}
+		got := 0
+		for i := 0; i < 20; i++ {
+			if w.shouldSampleQuality() {
+				got++
+			}
+		}
+		if got != tt.want {
+			t.Errorf("rate %.2f: sampled %d of 20 frames, want %d", tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestSampleQuality_RollingSeries(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 32, 24)), nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	w := &CaptureWorker{
+		camera: &mockCamera{id: "q-cam"},
+		config: CameraConfig{QualitySampleRate: 1},
+		logger: &defaultLogger{},
+	}
+
+	start := time.Now().UTC()
+	for i := 0; i < qualitySeriesSize+5; i++ {
+		w.sampleQuality(context.Background(), buf.Bytes(), start.Add(time.Duration(i)*time.Second))
+	}
+
+	series := w.GetQualitySeries()
+	if len(series) != qualitySeriesSize {
+		t.Fatalf("series length = %d, want %d", len(series), qualitySeriesSize)
+	}
+	if !series[0].Time.Equal(start.Add(5 * time.Second)) {
+		t.Errorf("oldest sample time = %v, want %v", series[0].Time, start.Add(5*time.Second))
+	}
+	if series[0].Width != 32 || series[0].Height != 24 {
+		t.Errorf("dimensions = %dx%d, want 32x24", series[0].Width, series[0].Height)
+	}
+}
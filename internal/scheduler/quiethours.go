@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// QuietHours is a daily window, in the upload worker's timezone, during which uploads
+// are skipped while capture and queueing continue
+type QuietHours struct {
+	Start time.Duration // Offset from local midnight
+	End   time.Duration // Exclusive; earlier than Start means the window crosses midnight
+}
+
+// ParseQuietHours parses "HH:MM" start and end times. Equal times yield an empty
+// window, which lets a camera opt out of a global schedule.
+func ParseQuietHours(start, end string) (*QuietHours, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	return &QuietHours{Start: s, End: e}, nil
+}
+
+// parseClock converts "HH:MM" into an offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (want HH:MM)", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Active reports whether t falls inside the window, using wall-clock time in loc
+func (q *QuietHours) Active(t time.Time, loc *time.Location) bool {
+	if q == nil || q.Start == q.End {
+		return false
+	}
+	local := t.In(loc)
+	offset := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+	if q.Start < q.End {
+		return offset >= q.Start && offset < q.End
+	}
+	return offset >= q.Start || offset < q.End
+}
+
+// String formats the window as "HH:MM-HH:MM"
+func (q *QuietHours) String() string {
+	if q == nil {
+		return ""
+	}
+	return formatClock(q.Start) + "-" + formatClock(q.End)
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// SetQuietHours changes the global upload quiet window; nil disables it.
+// Cameras with their own window keep it.
+func (w *UploadWorker) SetQuietHours(q *QuietHours) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.quietHours = q
+}
+
+// quietHoursFor returns the window that applies to a camera (caller must hold lock)
+func (w *UploadWorker) quietHoursFor(cameraID string) *QuietHours {
+	if cfg, ok := w.configs[cameraID]; ok && cfg.QuietHours != nil {
+		return cfg.QuietHours
+	}
+	return w.quietHours
+}
+
+// inQuietHours reports whether uploads for a camera are suspended at now, logging
+// transitions so operators can see the backlog build and drain
+func (w *UploadWorker) inQuietHours(cameraID string, now time.Time) bool {
+	w.mu.Lock()
+	window := w.quietHoursFor(cameraID)
+	active := window.Active(now, w.location)
+	changed := active != w.quietActive[cameraID]
+	if active {
+		w.quietActive[cameraID] = true
+	} else {
+		delete(w.quietActive, cameraID)
+	}
+	w.mu.Unlock()
+
+	if changed {
+		if active {
+			w.logger.Info("Upload quiet hours started, uploads paused",
+				"camera", cameraID,
+				"window", window.String())
+		} else {
+			w.logger.Info("Upload quiet hours ended, draining backlog", "camera", cameraID)
+		}
+	}
+	return active
+}
+
+// copyActiveQuietHours returns the window for each camera currently in quiet hours
+// (caller must hold lock)
+func (w *UploadWorker) copyActiveQuietHours(now time.Time) map[string]string {
+	var active map[string]string
+	for id := range w.queues {
+		window := w.quietHoursFor(id)
+		if !window.Active(now, w.location) {
+			continue
+		}
+		if active == nil {
+			active = make(map[string]string)
+		}
+		active[id] = window.String()
+	}
+	return active
+}
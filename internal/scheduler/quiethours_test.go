@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	q, err := ParseQuietHours("22:30", "06:00")
+	if err != nil {
+		t.Fatalf("ParseQuietHours: %v", err)
+	}
+	if q.Start != 22*time.Hour+30*time.Minute || q.End != 6*time.Hour {
+		t.Errorf("got %+v", q)
+	}
+	if q.String() != "22:30-06:00" {
+		t.Errorf("String() = %q", q.String())
+	}
+
+	for _, bad := range [][2]string{{"25:00", "06:00"}, {"22:00", "6am"}, {"", "06:00"}} {
+		if _, err := ParseQuietHours(bad[0], bad[1]); err == nil {
+			t.Errorf("ParseQuietHours(%q, %q) expected error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestQuietHours_Active(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2025, 6, 1, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		start  string
+		end    string
+		t      time.Time
+		active bool
+	}{
+		{"same day inside", "02:00", "04:00", at(3, 0), true},
+		{"same day at start", "02:00", "04:00", at(2, 0), true},
+		{"same day at end", "02:00", "04:00", at(4, 0), false},
+		{"same day outside", "02:00", "04:00", at(12, 0), false},
+		{"overnight late", "22:00", "06:00", at(23, 30), true},
+		{"overnight early", "22:00", "06:00", at(5, 59), true},
+		{"overnight outside", "22:00", "06:00", at(12, 0), false},
+		{"empty window", "03:00", "03:00", at(3, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuietHours(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("ParseQuietHours: %v", err)
+			}
+			if got := q.Active(tt.t, time.UTC); got != tt.active {
+				t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.active)
+			}
+		})
+	}
+
+	var none *QuietHours
+	if none.Active(at(3, 0), time.UTC) {
+		t.Error("nil window should never be active")
+	}
+}
+
+func TestQuietHours_ActiveUsesLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+	q, _ := ParseQuietHours("01:00", "02:00")
+	// 08:30 UTC is 01:30 MST
+	if !q.Active(time.Date(2025, 1, 15, 8, 30, 0, 0, time.UTC), loc) {
+		t.Error("expected window to be evaluated in the configured timezone")
+	}
+}
+
+// windowAround returns a quiet window covering now in UTC
+func windowAround(now time.Time) *QuietHours {
+	offset := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute
+	return &QuietHours{
+		Start: (offset + 23*time.Hour) % (24 * time.Hour),
+		End:   (offset + time.Hour) % (24 * time.Hour),
+	}
+}
+
+func TestUploadWorker_QuietHours(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	quiet, _ := queueMgr.CreateQueue("quiet", queue.DefaultQueueConfig())
+	optOut, _ := queueMgr.CreateQueue("opt-out", queue.DefaultQueueConfig())
+	ts := time.Now().UTC().Add(-time.Second)
+	if err := quiet.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := optOut.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	global := windowAround(time.Now().UTC())
+	worker := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 2, Location: time.UTC, QuietHours: global})
+	worker.AddQueue("quiet", quiet, CameraConfig{ID: "quiet"}, &mockUploader{})
+	worker.AddQueue("opt-out", optOut, CameraConfig{ID: "opt-out", QuietHours: &QuietHours{}}, &mockUploader{})
+
+	workChan := make(chan uploadTask, 4)
+	worker.scheduleUploads(workChan)
+	close(workChan)
+
+	var scheduled []string
+	for task := range workChan {
+		scheduled = append(scheduled, task.cameraID)
+	}
+	if len(scheduled) != 1 || scheduled[0] != "opt-out" {
+		t.Errorf("scheduled = %v, want only opt-out", scheduled)
+	}
+	if quiet.GetImageCount() != 1 {
+		t.Errorf("quiet camera should keep its backlog, count = %d", quiet.GetImageCount())
+	}
+
+	stats := worker.GetStats()
+	if stats.QuietHours["quiet"] != global.String() {
+		t.Errorf("QuietHours = %v, want quiet=%s", stats.QuietHours, global.String())
+	}
+	if _, ok := stats.QuietHours["opt-out"]; ok {
+		t.Error("camera with an empty window should not report quiet hours")
+	}
+
+	// Clearing the global window resumes uploads
+	worker.SetQuietHours(nil)
+	workChan = make(chan uploadTask, 4)
+	worker.scheduleUploads(workChan)
+	close(workChan)
+	if task, ok := <-workChan; !ok || task.cameraID != "quiet" {
+		t.Error("expected quiet camera to upload after quiet hours end")
+	}
+	if stats := worker.GetStats(); stats.QuietHours != nil {
+		t.Errorf("expected no active quiet hours, got %v", stats.QuietHours)
+	}
+}
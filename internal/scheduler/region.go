@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// regionQueueID names the queue of one of a camera's regions; like thumbnailQueueID
+// it cannot collide with a camera
+func regionQueueID(cameraID, region string) string {
+	return cameraID + ".roi." + region
+}
+
+// queueRegions crops each region from the capture as received (before the full
+// image's processing) and queues it for its own upload. Like thumbnails, a failed
+// region is counted and logged without affecting the full image or other regions.
+func (w *CaptureWorker) queueRegions(ctx context.Context, imageData []byte, observation timepkg.ObservationResult, meta timepkg.FrameMeta) {
+	for _, region := range w.config.Regions {
+		q := w.regionQueues[region.Name]
+		if q == nil {
+			continue
+		}
+		err := w.queueRegion(ctx, region, q, imageData, observation, meta)
+		if err == nil || err == queue.ErrCapturePaused || ctx.Err() != nil {
+			continue
+		}
+
+		w.mu.Lock()
+		w.regionsFailed++
+		w.mu.Unlock()
+		w.logger.Warn("Region not queued",
+			"camera", w.camera.ID(),
+			"region", region.Name,
+			"error", err)
+	}
+}
+
+// queueRegion processes, stamps and queues one region
+func (w *CaptureWorker) queueRegion(ctx context.Context, region RegionConfig, q *queue.Queue, imageData []byte, observation timepkg.ObservationResult, meta timepkg.FrameMeta) error {
+	if w.resourceLimiter != nil {
+		if err := w.resourceLimiter.AcquireImageProcessing(ctx); err != nil {
+			return err
+		}
+		defer w.resourceLimiter.ReleaseImageProcessing()
+	}
+
+	data, err := region.Processor.Process(imageData)
+	if err != nil {
+		return err
+	}
+	// Builtin stamping keeps this off exiftool, as for thumbnails
+	if w.shouldStamp(observation) {
+		if stamp := timepkg.StampBridgeEXIF(data, observation, meta); stamp.Stamped {
+			data = w.commentJPEG(stamp.Data, stamp.Marker)
+		}
+	}
+	return q.Enqueue(data, observation.Time, string(observation.Source), string(observation.Confidence))
+}
@@ -0,0 +1,92 @@
+package scheduler
+
+import (
+	"bytes"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestCaptureWorker_QueuesRegions(t *testing.T) {
+	east := config.Region{Name: "east", Rect: config.Rect{X: 0.5, Width: 0.5, Height: 1, Normalized: true}, MaxWidth: 160}
+	regions := []RegionConfig{
+		{Name: "east", Processor: image.NewProcessor(east.ImageProcessing(nil)).WithCrop(east.Rect), RemotePath: "east"},
+		// Past the edge of the 640x480 frame, so it fails on its own
+		{Name: "west", Processor: image.NewProcessor(nil).WithCrop(config.Rect{X: 600, Width: 100, Height: 100}), RemotePath: "west"},
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: &mockCamera{id: "roi-cam", camType: "http", data: testJPEG(t, 640, 480)},
+		CameraConfig: CameraConfig{
+			ID:             "roi-cam",
+			ImageProcessor: image.NewProcessor(&config.ImageProcessing{MaxWidth: 320}),
+			Regions:        regions,
+		},
+		Queue: newTestQueue(t, "roi-cam"),
+		RegionQueues: map[string]*queue.Queue{
+			"east": newTestQueue(t, regionQueueID("roi-cam", "east")),
+			"west": newTestQueue(t, regionQueueID("roi-cam", "west")),
+		},
+	})
+	w.capture()
+
+	if w.queue.GetImageCount() != 1 || w.regionQueues["east"].GetImageCount() != 1 || w.regionQueues["west"].GetImageCount() != 0 {
+		t.Fatalf("queued full=%d east=%d west=%d, want 1/1/0", w.queue.GetImageCount(),
+			w.regionQueues["east"].GetImageCount(), w.regionQueues["west"].GetImageCount())
+	}
+	full, _ := w.queue.Peek(1)
+	crop, _ := w.regionQueues["east"].Peek(1)
+	if !full[0].Timestamp.Equal(crop[0].Timestamp) {
+		t.Errorf("region timestamp %v differs from full image %v", crop[0].Timestamp, full[0].Timestamp)
+	}
+
+	// Cropped from the 640x480 capture, not the 320-wide full image
+	data, err := os.ReadFile(crop[0].FilePath)
+	if err != nil {
+		t.Fatalf("read region: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode region: %v", err)
+	}
+	if cfg.Width != 160 || cfg.Height != 240 {
+		t.Errorf("region is %dx%d, want 160x240", cfg.Width, cfg.Height)
+	}
+	if stats := w.GetStats(); stats.RegionsFailed != 1 || stats.CapturesFailed != 0 {
+		t.Errorf("RegionsFailed=%d CapturesFailed=%d, want 1/0", stats.RegionsFailed, stats.CapturesFailed)
+	}
+}
+
+func TestOrchestrator_RegionQueues(t *testing.T) {
+	cfg := DefaultOrchestratorConfig()
+	cfg.QueueBasePath = t.TempDir()
+	orch, err := NewOrchestrator(cfg)
+	if err != nil {
+		t.Fatalf("NewOrchestrator: %v", err)
+	}
+	defer orch.Stop()
+
+	camCfg := CameraConfig{ID: "north", RemotePath: ".", FreshnessSLA: 1,
+		Regions: []RegionConfig{{Name: "rwy16", Processor: image.NewProcessor(nil), RemotePath: "rwy16"}}}
+	if err := orch.AddCamera(&mockCamera{id: "north", camType: "http"}, camCfg, 60, &mockUploader{}, nil); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+	id := regionQueueID("north", "rwy16")
+	if _, ok := orch.queueManager.GetQueue(id); !ok {
+		t.Fatalf("no queue %s", id)
+	}
+	got := regionUploadConfig("north", camCfg.Regions[0], camCfg)
+	if got.ID != id || got.RemotePath != "rwy16" || got.Regions != nil || got.FreshnessSLA != 0 {
+		t.Errorf("unexpected region upload config: %+v", got)
+	}
+
+	if err := orch.RemoveCamera("north"); err != nil {
+		t.Fatalf("RemoveCamera: %v", err)
+	}
+	if _, ok := orch.queueManager.GetQueue(id); ok {
+		t.Error("region queue left behind")
+	}
+}
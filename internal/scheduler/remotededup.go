@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
+)
+
+// removeAlreadyUploaded runs once when a queue with RemoteDedup is added: it lists
+// the camera's remote directory and drops queued frames from the last RemoteDedup
+// that the server already has, e.g. ones uploaded just before an unclean shutdown
+// but never marked uploaded. The camera's uploads wait until it finishes; any
+// failure leaves the queue as it is.
+func (w *UploadWorker) removeAlreadyUploaded(cameraID string, q *queue.Queue, config CameraConfig, uploader upload.Client) {
+	defer func() {
+		w.mu.Lock()
+		if w.deduping[cameraID] == q {
+			delete(w.deduping, cameraID)
+		}
+		w.mu.Unlock()
+	}()
+
+	log := w.cameraLogger(cameraID)
+	lister, ok := uploader.(upload.Lister)
+	if !ok {
+		log.Warn("Upload client cannot list remote files - skipping remote dedup", "camera", cameraID)
+		return
+	}
+	since := time.Now().Add(-config.RemoteDedup)
+	if q.GetImageCount() == 0 || q.GetState().NewestTimestamp.Before(since) {
+		return // Nothing queued in the window; skip the connection
+	}
+
+	w.waitForConnection()
+	dir := strings.TrimSuffix(w.remoteFilePath(config.RemotePath, cameraID, ""), "/")
+	names, err := lister.ListDir(dir)
+	if err != nil {
+		log.Warn("Could not list remote files - uploading queued frames without dedup",
+			"camera", cameraID,
+			"path", dir,
+			"error", err)
+		return
+	}
+
+	removed := q.RemoveAlreadyUploaded(since, remoteTimestamps(names))
+	if removed == 0 {
+		return
+	}
+	w.mu.Lock()
+	w.alreadyUploaded += int64(removed)
+	w.mu.Unlock()
+	log.Info("Dropped queued frames already on the server",
+		"camera", cameraID,
+		"removed", removed,
+		"window", config.RemoteDedup)
+}
+
+// remoteTimestamps returns the capture times (Unix milliseconds) of the frame files
+// among names, which are named as by buildRemotePath
+func remoteTimestamps(names []string) map[int64]bool {
+	timestamps := make(map[int64]bool, len(names))
+	for _, name := range names {
+		base, ok := strings.CutSuffix(name, ".jpg")
+		if !ok {
+			continue
+		}
+		base, _, _ = strings.Cut(base, "_") // Drop filename time tokens
+		if ms, err := strconv.ParseInt(base, 10, 64); err == nil {
+			timestamps[ms] = true
+		}
+	}
+	return timestamps
+}
+
+// isDeduping reports whether a camera's remote dedup has not finished yet
+func (w *UploadWorker) isDeduping(cameraID string) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.deduping[cameraID] != nil
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+// listingUploader is a mockUploader whose remote directory can be listed
+type listingUploader struct {
+	mockUploader
+	names []string
+	err   error
+	dirs  chan string
+}
+
+func (u *listingUploader) ListDir(remoteDir string) ([]string, error) {
+	u.dirs <- remoteDir
+	return u.names, u.err
+}
+
+// waitDedup waits for a camera's remote dedup to finish
+func waitDedup(t *testing.T, w *UploadWorker, cameraID string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for w.isDeduping(cameraID) {
+		if time.Now().After(deadline) {
+			t.Fatal("remote dedup did not finish")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestUploadWorker_RemoteDedup(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	q, _ := queueMgr.CreateQueue("cam", queue.DefaultQueueConfig())
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	old, uploaded, pending := now.Add(-40*time.Minute), now.Add(-2*time.Minute), now.Add(-time.Minute)
+	for _, ts := range []time.Time{old, uploaded, pending} {
+		if err := q.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	// The old frame is on the server too, but outside the window
+	uploader := &listingUploader{
+		names: []string{fmt.Sprintf("%d.jpg", old.UnixMilli()), fmt.Sprintf("%d.jpg", uploaded.UnixMilli()), "latest.jpg"},
+		dirs:  make(chan string, 1),
+	}
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: time.Millisecond})
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam", RemotePath: "kspb/north/", RemoteDedup: 30 * time.Minute}, uploader)
+	waitDedup(t, worker, "cam")
+
+	if dir := <-uploader.dirs; dir != "kspb/north" {
+		t.Errorf("listed %q, want the camera's remote directory", dir)
+	}
+	if got := q.GetImageCount(); got != 2 {
+		t.Fatalf("queued = %d, want only the frame already uploaded in the window dropped", got)
+	}
+	images, _ := q.Peek(2)
+	if !images[0].Timestamp.Equal(old) || !images[1].Timestamp.Equal(pending) {
+		t.Errorf("kept %v and %v, want the old and pending frames", images[0].Timestamp, images[1].Timestamp)
+	}
+	if got := worker.GetStats().AlreadyUploaded; got != 1 {
+		t.Errorf("AlreadyUploaded = %d, want 1", got)
+	}
+	if got := q.GetStats().AlreadyUploaded; got != 1 {
+		t.Errorf("queue AlreadyUploaded = %d, want 1", got)
+	}
+}
+
+func TestUploadWorker_RemoteDedupListFailureKeepsQueue(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	q, _ := queueMgr.CreateQueue("cam", queue.DefaultQueueConfig())
+	if err := q.Enqueue(minimalTestJPEG(), time.Now().UTC().Add(-time.Minute), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	uploader := &listingUploader{err: errors.New("permission denied"), dirs: make(chan string, 1)}
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: time.Millisecond})
+	worker.AddQueue("cam", q, CameraConfig{ID: "cam", RemoteDedup: time.Hour}, uploader)
+	waitDedup(t, worker, "cam")
+
+	if got := q.GetImageCount(); got != 1 {
+		t.Errorf("queued = %d, want the frame kept for upload", got)
+	}
+
+	// Uploaders that cannot list are left alone
+	worker.AddQueue("plain", q, CameraConfig{ID: "plain", RemoteDedup: time.Hour}, &mockUploader{})
+	waitDedup(t, worker, "plain")
+	if got := q.GetImageCount(); got != 1 {
+		t.Errorf("queued = %d after a non-listing uploader", got)
+	}
+}
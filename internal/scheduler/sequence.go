@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// SequenceStore hands out per-camera frame sequence numbers and saves the last one
+// issued, so numbering continues across restarts and the server can treat any gap
+// as a dropped frame
+type SequenceStore struct {
+	path string
+
+	mu   sync.Mutex
+	last map[string]uint32 // Camera ID -> last number issued
+}
+
+// OpenSequenceStore loads the numbers saved at path; a missing file starts every
+// camera at 1. If the file cannot be read, the returned store starts empty and the
+// error says why.
+func OpenSequenceStore(path string) (*SequenceStore, error) {
+	s := &SequenceStore{path: path, last: make(map[string]uint32)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &s.last)
+	}
+	if err != nil {
+		s.last = make(map[string]uint32)
+		return s, fmt.Errorf("load frame sequences: %w", err)
+	}
+	return s, nil
+}
+
+// Next issues cameraID's next sequence number, wrapping to 1 after the largest
+// EXIF ImageNumber. The number is saved before it is returned; if saving fails it
+// is still issued and the error is returned with it.
+func (s *SequenceStore) Next(cameraID string) (uint32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.last[cameraID] + 1
+	if n == 0 {
+		n = 1
+	}
+	s.last[cameraID] = n
+	return n, s.save()
+}
+
+// Last returns the last number issued for cameraID, or 0 if none
+func (s *SequenceStore) Last(cameraID string) uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last[cameraID]
+}
+
+// save writes all numbers atomically (tmp + rename); caller must hold mu
+func (s *SequenceStore) save() error {
+	data, err := json.Marshal(s.last)
+	if err != nil {
+		return err
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath) // Best-effort cleanup
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSequenceStore_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequences.json")
+	s, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("OpenSequenceStore() on missing file: %v", err)
+	}
+	for want := uint32(1); want <= 3; want++ {
+		if got, err := s.Next("north"); got != want || err != nil {
+			t.Fatalf("Next() = %d, %v; want %d", got, err, want)
+		}
+	}
+	s.Next("south")
+
+	s, err = OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if got := s.Last("north"); got != 3 {
+		t.Errorf("Last(north) after reopen = %d, want 3", got)
+	}
+	if got, _ := s.Next("south"); got != 2 {
+		t.Errorf("Next(south) after reopen = %d, want 2", got)
+	}
+	if got := s.Last("east"); got != 0 {
+		t.Errorf("Last(east) = %d, want 0", got)
+	}
+}
+
+func TestSequenceStore_WrapsToOne(t *testing.T) {
+	s, _ := OpenSequenceStore(filepath.Join(t.TempDir(), "sequences.json"))
+	s.last["north"] = math.MaxUint32
+	if got, _ := s.Next("north"); got != 1 {
+		t.Errorf("Next() after MaxUint32 = %d, want 1", got)
+	}
+}
+
+func TestSequenceStore_CorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sequences.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+
+	s, err := OpenSequenceStore(path)
+	if err == nil {
+		t.Fatal("OpenSequenceStore() accepted a corrupt file")
+	}
+	if got, err := s.Next("north"); got != 1 || err != nil {
+		t.Errorf("Next() after corrupt file = %d, %v; want 1", got, err)
+	}
+}
+
+func TestCaptureWorker_ExifSequence(t *testing.T) {
+	sequences, _ := OpenSequenceStore(filepath.Join(t.TempDir(), "sequences.json"))
+	newWorker := func(id string, enabled bool) *CaptureWorker {
+		return NewCaptureWorker(CaptureWorkerConfig{
+			Camera:       &mockCamera{id: id, camType: "http", data: testJPEG(t, 64, 48)},
+			CameraConfig: CameraConfig{ID: id, ExifSequence: enabled},
+			Queue:        newTestQueue(t, id),
+			Sequences:    sequences,
+		})
+	}
+
+	w := newWorker("seq-cam", true)
+	w.capture()
+	w.capture()
+	if got := w.GetStats().Sequence; got != 2 {
+		t.Errorf("stats sequence = %d, want 2", got)
+	}
+	if queued, _ := w.queue.Peek(2); len(queued) != 2 {
+		t.Fatalf("queued %d images, want 2", len(queued))
+	}
+
+	// Cameras without exif_sequence consume no numbers
+	off := newWorker("plain-cam", false)
+	off.capture()
+	if got := sequences.Last("plain-cam"); got != 0 {
+		t.Errorf("plain camera issued sequence %d", got)
+	}
+	if got := off.GetStats().Sequence; got != 0 {
+		t.Errorf("plain camera stats sequence = %d, want 0", got)
+	}
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import "time"
+
+// settle waits out the camera's settle delay before the worker's first capture, so a
+// camera that was just powered on or re-added is not captured mid-boot. Event triggers
+// arriving meanwhile are dropped. Returns false if the worker was stopped while waiting.
+func (w *CaptureWorker) settle() bool {
+	w.mu.Lock()
+	delay := w.config.SettleDelay
+	if delay <= 0 || w.settled {
+		w.settled = true
+		w.mu.Unlock()
+		return true
+	}
+	w.settleUntil = time.Now().Add(delay)
+	w.nextCaptureTime = w.settleUntil
+	w.mu.Unlock()
+
+	w.logger.Info("Waiting for camera to settle before first capture",
+		"camera", w.camera.ID(),
+		"settle_delay", delay)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return false
+
+		case reason := <-w.trigger:
+			w.logger.Debug("Ignoring event trigger while settling", "camera", w.camera.ID(), "event", reason)
+
+		case <-timer.C:
+			w.mu.Lock()
+			w.settled = true
+			w.settleUntil = time.Time{}
+			w.mu.Unlock()
+			return true
+		}
+	}
+}
+
+// isSettlingLocked reports whether the worker is still in its settle delay (caller must hold lock)
+func (w *CaptureWorker) isSettlingLocked() bool {
+	return !w.settleUntil.IsZero()
+}
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureWorker_SettleDelaysFirstCapture(t *testing.T) {
+	cam := &mockCamera{id: "settle-cam", camType: "http", data: minimalTestJPEG()}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "settle-cam", SettleDelay: 150 * time.Millisecond},
+		Queue:        newTestQueue(t, "settle-cam"),
+		IntervalSecs: 60,
+	})
+	w.Start()
+	defer w.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	stats := w.GetStats()
+	if !stats.Settling || stats.SettleUntil.IsZero() {
+		t.Fatalf("expected worker to be settling, got settling=%v until=%v", stats.Settling, stats.SettleUntil)
+	}
+	if !stats.NextCaptureTime.Equal(stats.SettleUntil) {
+		t.Errorf("NextCaptureTime = %v, want settle end %v", stats.NextCaptureTime, stats.SettleUntil)
+	}
+
+	// Event triggers during the delay are dropped, not captured
+	w.TriggerCapture("motion")
+	time.Sleep(30 * time.Millisecond)
+	if stats := w.GetStats(); stats.CapturesTotal != 0 || stats.EventCaptures != 0 {
+		t.Fatalf("captured while settling: total=%d events=%d", stats.CapturesTotal, stats.EventCaptures)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for w.GetStats().CapturesTotal == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats = w.GetStats()
+	if stats.CapturesTotal != 1 || stats.Settling || stats.EventCaptures != 0 {
+		t.Errorf("after settling: total=%d settling=%v events=%d", stats.CapturesTotal, stats.Settling, stats.EventCaptures)
+	}
+}
+
+func TestCaptureWorker_SettleOnlyOnce(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "once-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "once-cam", SettleDelay: time.Millisecond},
+		Queue:        newTestQueue(t, "once-cam"),
+	})
+	defer w.Stop()
+
+	if !w.settle() {
+		t.Fatal("settle should complete")
+	}
+
+	// A restart after a panic must not wait again
+	w.config.SettleDelay = time.Hour
+	done := make(chan bool, 1)
+	go func() { done <- w.settle() }()
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Error("settle should report success")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second settle should return immediately")
+	}
+}
+
+func TestCaptureWorker_SettleStopped(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "stop-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "stop-cam", SettleDelay: time.Hour},
+		Queue:        newTestQueue(t, "stop-cam"),
+	})
+	w.Stop()
+	if w.settle() {
+		t.Error("settle should abort when the worker is stopped")
+	}
+}
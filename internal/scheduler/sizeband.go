@@ -0,0 +1,59 @@
+package scheduler
+
+import "os"
+
+// SizeBand is the range of frame sizes a camera may upload. Frames outside it are
+// likely corrupt (too small) or misconfigured (too large) and are not sent.
+type SizeBand struct {
+	MinBytes int64 // 0 = no minimum
+	MaxBytes int64 // 0 = no maximum
+	Keep     bool  // Leave rejected frames queued, counting a failed upload cycle; default: drop them
+}
+
+// contains reports whether size is within the band
+func (b *SizeBand) contains(size int64) bool {
+	return size >= b.MinBytes && (b.MaxBytes == 0 || size <= b.MaxBytes)
+}
+
+// rejectBySize is the last check before a frame is sent: a frame outside the
+// camera's size band is counted, logged and dropped (or kept, per the band) without
+// using a connection. Reports whether the frame was rejected.
+func (w *UploadWorker) rejectBySize(task uploadTask) bool {
+	band := task.config.SizeBand
+	if band == nil {
+		return false
+	}
+	size := task.image.SizeBytes
+	if info, err := os.Stat(task.image.FilePath); err == nil {
+		size = info.Size()
+	}
+	if band.contains(size) {
+		return false
+	}
+
+	w.breakerRelease(task.config.UploadServer)
+	w.mu.Lock()
+	w.sizeRejected++
+	w.mu.Unlock()
+	w.cameraLogger(task.cameraID).Warn("Frame size outside the upload band - not uploading",
+		"camera", task.cameraID,
+		"filename", task.image.Filename,
+		"size", size,
+		"min_bytes", band.MinBytes,
+		"max_bytes", band.MaxBytes,
+		"kept", band.Keep)
+
+	if band.Keep {
+		w.recordFrameFailure(task)
+		return true
+	}
+	w.mu.Lock()
+	w.frameAttempts.clear(task.cameraID, task.image.FilePath)
+	w.mu.Unlock()
+	if err := task.queue.MarkAbandoned(task.image); err != nil {
+		w.logger.Error("Failed to drop frame rejected by size",
+			"camera", task.cameraID,
+			"error", err)
+	}
+	return true
+}
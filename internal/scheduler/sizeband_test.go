@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+func TestUploadWorker_RejectBySize(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	q, _ := queueMgr.CreateQueue("cam", queue.DefaultQueueConfig())
+	ts := time.Now().UTC().Add(-time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(minimalTestJPEG(), ts.Add(time.Duration(i)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	images, err := q.Peek(2)
+	if err != nil {
+		t.Fatalf("Peek: %v", err)
+	}
+	size := images[0].SizeBytes
+	worker := NewUploadWorker(UploadWorkerConfig{})
+
+	inBand := CameraConfig{ID: "cam", SizeBand: &SizeBand{MinBytes: size, MaxBytes: size}}
+	if worker.rejectBySize(uploadTask{cameraID: "cam", image: images[0], queue: q, config: inBand}) {
+		t.Fatal("frame within the band rejected")
+	}
+
+	// Kept frames count as failed cycles until max_upload_attempts drops them
+	keep := CameraConfig{ID: "cam", MaxUploadAttempts: 2, SizeBand: &SizeBand{MinBytes: size + 1, Keep: true}}
+	task := uploadTask{cameraID: "cam", image: images[0], queue: q, config: keep}
+	if !worker.rejectBySize(task) || q.GetImageCount() != 2 {
+		t.Fatalf("small frame with keep: count = %d, want it rejected and kept", q.GetImageCount())
+	}
+	worker.rejectBySize(task)
+	if q.GetImageCount() != 1 {
+		t.Errorf("count = %d, want the kept frame dropped at max_upload_attempts", q.GetImageCount())
+	}
+
+	drop := CameraConfig{ID: "cam", SizeBand: &SizeBand{MaxBytes: size - 1}}
+	if !worker.rejectBySize(uploadTask{cameraID: "cam", image: images[1], queue: q, config: drop}) || q.GetImageCount() != 0 {
+		t.Errorf("large frame: count = %d, want it dropped", q.GetImageCount())
+	}
+	if got := worker.GetStats().SizeRejected; got != 3 {
+		t.Errorf("SizeRejected = %d, want 3", got)
+	}
+}
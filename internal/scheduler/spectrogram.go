@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/spectrogram"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// SpectrogramConfig configures a camera's audio spectrograms
+type SpectrogramConfig struct {
+	Duration   time.Duration // Audio recorded per spectrogram
+	Interval   time.Duration // Minimum time between spectrograms
+	RemotePath string        // Remote directory, distinct from the full image's
+	Width      int           // Image size; 0 = spectrogram defaults
+	Height     int
+}
+
+// SpectrogramStats counts a camera's spectrograms
+type SpectrogramStats struct {
+	Queued  int64     `json:"queued"`
+	Skipped int64     `json:"skipped"` // Not attempted: one still running, or the CPU was busy
+	Failed  int64     `json:"failed"`
+	NoAudio bool      `json:"no_audio"` // The last attempt found no audio track
+	Last    time.Time `json:"last,omitempty"`
+}
+
+// spectrogramQueueID names a camera's spectrogram queue; like thumbnailQueueID it
+// cannot collide with a camera
+func spectrogramQueueID(cameraID string) string {
+	return cameraID + ".audio"
+}
+
+// startSpectrogram records audio for a spectrogram after a queued capture, when one
+// is due. It runs in the background on its own stream connection, so it never delays
+// or fails image capture; while one runs, later captures do not start another.
+func (w *CaptureWorker) startSpectrogram(observation timepkg.ObservationResult) {
+	if w.config.Spectrogram == nil || w.spectroQueue == nil {
+		return
+	}
+	src, ok := camera.Underlying(w.camera).(camera.AudioSource)
+	if !ok {
+		return
+	}
+
+	w.mu.Lock()
+	due := time.Since(w.spectro.Last) >= w.config.Spectrogram.Interval
+	if !due || w.spectroRunning {
+		if due {
+			w.spectro.Skipped++
+		}
+		w.mu.Unlock()
+		return
+	}
+	w.spectroRunning = true
+	w.spectro.Last = time.Now()
+	w.mu.Unlock()
+
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			w.spectroRunning = false
+			w.mu.Unlock()
+		}()
+		w.recordSpectrogram(src, observation)
+	}()
+}
+
+// recordSpectrogram captures, renders and queues one spectrogram
+func (w *CaptureWorker) recordSpectrogram(src camera.AudioSource, observation timepkg.ObservationResult) {
+	cfg := w.config.Spectrogram
+	clip, err := src.CaptureAudio(w.ctx, cfg.Duration)
+	if errors.Is(err, camera.ErrNoAudio) {
+		w.mu.Lock()
+		logged := w.spectro.NoAudio
+		w.spectro.NoAudio = true
+		w.mu.Unlock()
+		if !logged {
+			w.logger.Info("Camera stream has no audio, skipping spectrograms until it does",
+				"camera", w.camera.ID())
+		}
+		return
+	}
+	if err == nil {
+		w.mu.Lock()
+		w.spectro.NoAudio = false
+		w.mu.Unlock()
+		err = w.queueSpectrogram(clip, observation)
+	}
+	if err == nil || err == queue.ErrCapturePaused {
+		return
+	}
+	if w.ctx.Err() != nil {
+		return // Worker stopped mid-recording
+	}
+
+	w.mu.Lock()
+	w.spectro.Failed++
+	w.mu.Unlock()
+	w.logger.Warn("Spectrogram not queued",
+		"camera", w.camera.ID(),
+		"error", err)
+}
+
+// queueSpectrogram renders clip, skipping it when no image processing slot is free
+func (w *CaptureWorker) queueSpectrogram(clip camera.AudioClip, observation timepkg.ObservationResult) error {
+	if w.resourceLimiter != nil {
+		if !w.resourceLimiter.TryAcquireImageProcessing() {
+			w.mu.Lock()
+			w.spectro.Skipped++
+			w.mu.Unlock()
+			return nil
+		}
+		defer w.resourceLimiter.ReleaseImageProcessing()
+	}
+
+	cfg := w.config.Spectrogram
+	data, err := spectrogram.Encode(clip.Samples, spectrogram.Options{Width: cfg.Width, Height: cfg.Height})
+	if err != nil {
+		return err
+	}
+	if err := w.spectroQueue.Enqueue(data, observation.Time, string(observation.Source), string(observation.Confidence)); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	w.spectro.Queued++
+	w.mu.Unlock()
+	return nil
+}
+
+// spectrogramStats returns spectrogram counters, or nil when disabled (caller must hold lock)
+func (w *CaptureWorker) spectrogramStats() *SpectrogramStats {
+	if w.spectroQueue == nil {
+		return nil
+	}
+	stats := w.spectro
+	return &stats
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+)
+
+// audioCamera is a mockCamera whose stream carries a tone, or no audio when silent
+type audioCamera struct {
+	mockCamera
+	noAudio bool
+	clips   atomic.Int32
+}
+
+func (c *audioCamera) CaptureAudio(ctx context.Context, d time.Duration) (camera.AudioClip, error) {
+	c.clips.Add(1)
+	if c.noAudio {
+		return camera.AudioClip{}, camera.ErrNoAudio
+	}
+	samples := make([]int16, int(d.Seconds()*camera.AudioSampleRate))
+	for i := range samples {
+		samples[i] = int16(8000 * math.Sin(2*math.Pi*1000*float64(i)/camera.AudioSampleRate))
+	}
+	return camera.AudioClip{Samples: samples, SampleRate: camera.AudioSampleRate}, nil
+}
+
+func newSpectrogramWorker(t *testing.T, cam *audioCamera) *CaptureWorker {
+	t.Helper()
+	cam.mockCamera = mockCamera{id: "audio-cam", camType: "rtsp", data: testJPEG(t, 64, 48)}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: cam,
+		CameraConfig: CameraConfig{ID: "audio-cam", Spectrogram: &SpectrogramConfig{
+			Duration: 100 * time.Millisecond, Interval: time.Hour, RemotePath: "spectrogram", Width: 32, Height: 16,
+		}},
+		Queue:            newTestQueue(t, "audio-cam"),
+		SpectrogramQueue: newTestQueue(t, spectrogramQueueID("audio-cam")),
+	})
+	t.Cleanup(w.Stop)
+	return w
+}
+
+// waitSpectrogramIdle waits for the background recording to finish
+func waitSpectrogramIdle(t *testing.T, w *CaptureWorker) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		w.mu.RLock()
+		running := w.spectroRunning
+		w.mu.RUnlock()
+		if !running {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("spectrogram still running")
+}
+
+func TestCaptureWorker_QueuesSpectrogram(t *testing.T) {
+	cam := &audioCamera{}
+	w := newSpectrogramWorker(t, cam)
+	w.capture()
+	waitSpectrogramIdle(t, w)
+
+	if w.queue.GetImageCount() != 1 || w.spectroQueue.GetImageCount() != 1 {
+		t.Fatalf("queued frames=%d spectrograms=%d, want 1/1", w.queue.GetImageCount(), w.spectroQueue.GetImageCount())
+	}
+	full, _ := w.queue.Peek(1)
+	spectro, _ := w.spectroQueue.Peek(1)
+	if !full[0].Timestamp.Equal(spectro[0].Timestamp) {
+		t.Errorf("spectrogram timestamp %v differs from the frame's %v", spectro[0].Timestamp, full[0].Timestamp)
+	}
+
+	// The next capture falls within the interval: no new recording
+	w.capture()
+	waitSpectrogramIdle(t, w)
+	if cam.clips.Load() != 1 || w.spectroQueue.GetImageCount() != 1 {
+		t.Errorf("clips=%d spectrograms=%d within the interval, want 1/1", cam.clips.Load(), w.spectroQueue.GetImageCount())
+	}
+	if stats := w.GetStats().Spectrogram; stats == nil || stats.Queued != 1 || stats.Failed != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestCaptureWorker_SpectrogramWithoutAudio(t *testing.T) {
+	w := newSpectrogramWorker(t, &audioCamera{noAudio: true})
+	w.capture()
+	waitSpectrogramIdle(t, w)
+
+	if w.queue.GetImageCount() != 1 || w.spectroQueue.GetImageCount() != 0 {
+		t.Errorf("queued frames=%d spectrograms=%d, want 1/0", w.queue.GetImageCount(), w.spectroQueue.GetImageCount())
+	}
+	stats := w.GetStats()
+	if stats.Spectrogram == nil || !stats.Spectrogram.NoAudio || stats.Spectrogram.Failed != 0 || stats.CapturesFailed != 0 {
+		t.Errorf("stats = %+v, captures failed %d", stats.Spectrogram, stats.CapturesFailed)
+	}
+}
+
+func TestFinishSpooledCapture_QueuesSpectrogram(t *testing.T) {
+	cam := &audioCamera{}
+	w := newSpectrogramWorker(t, cam)
+	path := filepath.Join(t.TempDir(), "spooled.jpg")
+	if err := os.WriteFile(path, cam.data, 0o644); err != nil {
+		t.Fatalf("write spool file: %v", err)
+	}
+	w.finishSpooledCapture(context.Background(), path, time.Now().UTC(), newPhaseTimer(), CaptureTiming{})
+	waitSpectrogramIdle(t, w)
+
+	if w.queue.GetImageCount() != 1 || w.spectroQueue.GetImageCount() != 1 {
+		t.Errorf("queued frames=%d spectrograms=%d, want 1/1", w.queue.GetImageCount(), w.spectroQueue.GetImageCount())
+	}
+}
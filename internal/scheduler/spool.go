@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/camera"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// spoolWriter buffers a capture in memory until it exceeds threshold, then moves
+// it to a file so the rest of a large frame never lands on the heap
+type spoolWriter struct {
+	threshold int64
+	create    func() (*os.File, error)
+	buf       bytes.Buffer
+	file      *os.File
+}
+
+func (s *spoolWriter) Write(p []byte) (int, error) {
+	if s.file == nil && int64(s.buf.Len()+len(p)) <= s.threshold {
+		return s.buf.Write(p)
+	}
+
+	if s.file == nil {
+		f, err := s.create()
+		if err != nil {
+			return 0, err
+		}
+		s.file = f
+		if _, err := f.Write(s.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		s.buf = bytes.Buffer{}
+	}
+
+	return s.file.Write(p)
+}
+
+// finish closes the spool file, if any, and returns its path
+func (s *spoolWriter) finish() (string, error) {
+	if s.file == nil {
+		return "", nil
+	}
+	return s.file.Name(), s.file.Close()
+}
+
+// discard removes a partially written spool file
+func (s *spoolWriter) discard() {
+	if s.file != nil {
+		_ = s.file.Close()
+		_ = os.Remove(s.file.Name()) // Best effort cleanup
+	}
+}
+
+// captureImage captures a frame, streaming it to a queue spool file when the camera
+// supports it and the frame exceeds the configured threshold. Exactly one of the
+// returned data and spool path is set on success.
+func (w *CaptureWorker) captureImage(ctx context.Context) ([]byte, string, error) {
+	streamer, ok := w.camera.(camera.StreamingCamera)
+	if !ok || w.config.SpoolThresholdBytes <= 0 || w.mustSeeFrames() {
+		data, err := w.camera.Capture(ctx)
+		return data, "", err
+	}
+
+	sw := &spoolWriter{
+		threshold: w.config.SpoolThresholdBytes,
+		create:    w.queue.CreateSpoolFile,
+	}
+	if _, err := streamer.CaptureTo(ctx, sw); err != nil {
+		sw.discard()
+		return nil, "", err
+	}
+
+	path, err := sw.finish()
+	if err != nil {
+		sw.discard()
+		return nil, "", err
+	}
+	if path == "" {
+		return sw.buf.Bytes(), "", nil
+	}
+	return nil, path, nil
+}
+
+// mustSeeFrames reports whether every frame has to pass through memory: privacy masks
+// and the post-capture hook cannot run on a spooled file, and skipping them would
+// upload pixels meant to be hidden. Validation refuses these with spooling, but a
+// camera loaded despite failing it must still fail closed.
+func (w *CaptureWorker) mustSeeFrames() bool {
+	masks := w.config.ImageProcessor != nil && w.config.ImageProcessor.Masks()
+	return masks || w.config.PostCaptureHook != nil
+}
+
+// finishSpooledCapture stamps and enqueues a capture that was streamed to disk.
+// Steps that need the image in memory (processing, trim and repair, dedup, quality
+// samples, thumbnails and regions) cannot be configured with spooling; the preview
+// callback is skipped.
+func (w *CaptureWorker) finishSpooledCapture(jobCtx context.Context, path string, captureStartUTC time.Time, timer *phaseTimer, timing CaptureTiming) {
+	w.logger.Debug("Capture spooled to disk",
+		"camera", w.camera.ID(),
+		"threshold_bytes", w.config.SpoolThresholdBytes)
+
+	var cameraTime *time.Time
+	if w.exifReader() != nil && w.config.TimeSource != timepkg.PreferBridge {
+		if w.resourceLimiter != nil {
+			if err := w.resourceLimiter.AcquireExifOperation(jobCtx); err != nil {
+				w.logger.Debug("Skipping EXIF read due to context cancellation",
+					"camera", w.camera.ID())
+			} else {
+				defer w.resourceLimiter.ReleaseExifOperation()
+				cameraTime = w.readCameraEXIFFile(path)
+			}
+		} else {
+			cameraTime = w.readCameraEXIFFile(path)
+		}
+	}
+
+	observation := w.determineObservation(captureStartUTC, cameraTime)
+	timing.ExifReadMs = timer.lap()
+
+	// Spooled frames are stamped in place by exiftool only; the builtin injector
+	// would need the whole frame in memory
+	if w.shouldStamp(observation) {
+		method := timepkg.StampMethodExifTool
+		if _, err := timepkg.StampBridgeEXIFFileWithTool(path, observation, w.frameMeta()); err != nil {
+			method = timepkg.StampMethodNone
+			w.logger.Warn("EXIF stamp failed, using original image",
+				"camera", w.camera.ID(),
+				"error", err)
+		}
+		w.recordStampMethod(timepkg.EXIFStampResult{Method: method})
+	}
+	timing.ExifStampMs = timer.lap()
+
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	if err := w.queue.EnqueueFile(path, observation.Time,
+		string(observation.Source), string(observation.Confidence)); err != nil {
+		w.logEnqueueError(err)
+		return
+	}
+	timing.EnqueueMs = timer.lap()
+	timing.Spooled = true
+	if notifier, ok := camera.Underlying(w.camera).(camera.QueuedNotifier); ok {
+		notifier.FrameQueued()
+	}
+
+	w.recordCaptureSuccess(observation)
+	w.recordCaptured(int(size))
+	w.recordTiming(timing, timer)
+
+	// Audio comes from its own stream connection, so spooled frames get spectrograms too
+	w.startSpectrogram(observation)
+}
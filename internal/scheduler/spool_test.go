@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+// mockStreamingCamera writes its data to the capture writer in small chunks
+type mockStreamingCamera struct {
+	mockCamera
+}
+
+func (m *mockStreamingCamera) CaptureTo(ctx context.Context, w io.Writer) (int64, error) {
+	var n int64
+	for off := 0; off < len(m.data); off += 16 {
+		end := off + 16
+		if end > len(m.data) {
+			end = len(m.data)
+		}
+		written, err := w.Write(m.data[off:end])
+		n += int64(written)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, m.err
+}
+
+func newSpoolTestWorker(t *testing.T, data []byte, threshold int64) *CaptureWorker {
+	t.Helper()
+	q, err := queue.NewQueue("spool-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	cam := &mockStreamingCamera{mockCamera{id: "spool-cam", camType: "rtsp", data: data}}
+	return NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "spool-cam", SpoolThresholdBytes: threshold},
+		Queue:        q,
+	})
+}
+
+func TestCaptureImage_BelowThresholdStaysInMemory(t *testing.T) {
+	data := minimalTestJPEG()
+	w := newSpoolTestWorker(t, data, int64(len(data)))
+
+	got, path, err := w.captureImage(context.Background())
+	if err != nil {
+		t.Fatalf("captureImage: %v", err)
+	}
+	if path != "" {
+		t.Errorf("expected no spool file, got %s", path)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("in-memory capture data mismatch")
+	}
+}
+
+func TestCaptureImage_AboveThresholdSpools(t *testing.T) {
+	data := minimalTestJPEG()
+	w := newSpoolTestWorker(t, data, 32)
+
+	got, path, err := w.captureImage(context.Background())
+	if err != nil {
+		t.Fatalf("captureImage: %v", err)
+	}
+	if got != nil {
+		t.Error("spooled capture should not return in-memory data")
+	}
+	defer os.Remove(path)
+
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read spool file: %v", err)
+	}
+	if !bytes.Equal(onDisk, data) {
+		t.Error("spool file contents mismatch")
+	}
+}
+
+func TestCaptureImage_ErrorDiscardsSpool(t *testing.T) {
+	data := minimalTestJPEG()
+	w := newSpoolTestWorker(t, data, 32)
+	w.camera.(*mockStreamingCamera).err = io.ErrUnexpectedEOF
+
+	if _, _, err := w.captureImage(context.Background()); err == nil {
+		t.Fatal("expected capture error")
+	}
+
+	entries, _ := os.ReadDir(w.queue.GetState().Directory)
+	if len(entries) != 0 {
+		t.Errorf("expected spool file to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestFinishSpooledCapture_RecordsThroughput(t *testing.T) {
+	data := minimalTestJPEG()
+	w := newSpoolTestWorker(t, data, 32)
+	w.config.ThroughputHalfLife = time.Minute
+
+	_, path, err := w.captureImage(context.Background())
+	if err != nil || path == "" {
+		t.Fatalf("captureImage: path %q, err %v", path, err)
+	}
+	w.finishSpooledCapture(context.Background(), path, time.Now().UTC(), newPhaseTimer(), CaptureTiming{})
+
+	if got := w.queue.GetImageCount(); got != 1 {
+		t.Fatalf("queue has %d frames, want 1", got)
+	}
+	if tp := w.GetStats().Throughput; tp == nil || tp.CapturesPerMin <= 0 || tp.CapturedBytesPerMin <= 0 {
+		t.Errorf("throughput after a spooled frame = %+v", tp)
+	}
+}
+
+func TestCaptureImage_PrivacyZonesNeverSpool(t *testing.T) {
+	data := minimalTestJPEG()
+	w := newSpoolTestWorker(t, data, 32)
+	w.config.ImageProcessor = image.NewProcessor(&config.ImageProcessing{
+		PrivacyZones: []config.PrivacyZone{{Width: 1, Height: 1}},
+	})
+
+	// A spooled frame would skip masking, so it is captured in memory instead
+	got, path, err := w.captureImage(context.Background())
+	if err != nil {
+		t.Fatalf("captureImage: %v", err)
+	}
+	if path != "" || !bytes.Equal(got, data) {
+		t.Errorf("masked camera spooled its frame to %q", path)
+	}
+
+	w.config.ImageProcessor = nil
+	w.config.PostCaptureHook = &PostCaptureHook{Command: []string{"/bin/cat"}}
+	if _, path, _ := w.captureImage(context.Background()); path != "" {
+		os.Remove(path)
+		t.Error("hooked camera spooled its frame")
+	}
+}
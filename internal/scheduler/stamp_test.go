@@ -0,0 +1,166 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+func TestCaptureWorker_RecordStampMethod(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "stamp-cam", camType: "http"},
+		CameraConfig: CameraConfig{ID: "stamp-cam"},
+		Queue:        newTestQueue(t, "stamp-cam"),
+	})
+
+	for _, method := range []string{timepkg.StampMethodExifTool, timepkg.StampMethodBuiltin, timepkg.StampMethodNone, timepkg.StampMethodBuiltin} {
+		w.recordStampMethod(timepkg.EXIFStampResult{Method: method})
+	}
+
+	stats := w.GetStats()
+	if stats.ExifStampFallbacks != 2 || stats.ExifWriteFailed != 1 {
+		t.Errorf("fallbacks=%d failed=%d, want 2/1", stats.ExifStampFallbacks, stats.ExifWriteFailed)
+	}
+	if stats.ExifStampMethods[timepkg.StampMethodExifTool] != 1 || stats.LastStampMethod != timepkg.StampMethodBuiltin {
+		t.Errorf("methods=%v last=%q", stats.ExifStampMethods, stats.LastStampMethod)
+	}
+}
+
+func TestCaptureWorker_ExifStampRetries(t *testing.T) {
+	for _, tt := range []struct{ configured, want int }{{0, 1}, {2, 2}, {10, 3}} {
+		w := &CaptureWorker{config: CameraConfig{ExifStampRetries: tt.configured}}
+		if got := w.exifStampRetries(); got != tt.want {
+			t.Errorf("ExifStampRetries=%d: got %d, want %d", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureWorker_StampDescribesResizedImage(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: &mockCamera{id: "resize-cam", camType: "http", data: testJPEG(t, 640, 480)},
+		CameraConfig: CameraConfig{
+			ID:             "resize-cam",
+			ImageProcessor: image.NewProcessor(&config.ImageProcessing{MaxWidth: 320}),
+		},
+		Queue: newTestQueue(t, "resize-cam"),
+	})
+	w.capture()
+
+	queued, _ := w.queue.Peek(1)
+	if len(queued) != 1 {
+		t.Fatalf("queued %d images, want 1", len(queued))
+	}
+	data, err := os.ReadFile(queued[0].FilePath)
+	if err != nil {
+		t.Fatalf("read queued image: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode queued image: %v", err)
+	}
+	if cfg.Width != 320 || cfg.Height != 240 {
+		t.Fatalf("queued image is %dx%d, want 320x240", cfg.Width, cfg.Height)
+	}
+
+	width, height, ok := exifPixelDimensions(data)
+	if !ok {
+		t.Fatal("queued image has no EXIF pixel dimensions")
+	}
+	if width != cfg.Width || height != cfg.Height {
+		t.Errorf("EXIF says %dx%d, uploaded image is %dx%d", width, height, cfg.Width, cfg.Height)
+	}
+}
+
+func TestCaptureWorker_JPEGComment(t *testing.T) {
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "com-cam", camType: "http", data: testJPEG(t, 64, 48)},
+		CameraConfig: CameraConfig{ID: "com-cam", ExifNote: "KSPB", JPEGComment: true},
+		Queue:        newTestQueue(t, "com-cam"),
+	})
+	w.capture()
+
+	queued, _ := w.queue.Peek(1)
+	if len(queued) != 1 {
+		t.Fatalf("queued %d images, want 1", len(queued))
+	}
+	data, err := os.ReadFile(queued[0].FilePath)
+	if err != nil {
+		t.Fatalf("read queued image: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("queued image does not decode: %v", err)
+	}
+	idx := bytes.Index(data, []byte{0xFF, 0xFE})
+	if idx < 0 {
+		t.Fatal("queued image has no COM segment")
+	}
+	comment := string(data[idx+4 : idx+2+int(binary.BigEndian.Uint16(data[idx+2:]))])
+	if !strings.HasPrefix(comment, "AviationWX-Bridge:UTC:v1:") || !strings.HasSuffix(comment, " camera=com-cam note=KSPB") {
+		t.Errorf("comment = %q", comment)
+	}
+	if exif := bytes.Index(data, []byte("Exif\x00\x00")); exif < 0 || exif > idx {
+		t.Error("EXIF segment should precede the comment")
+	}
+}
+
+// exifPixelDimensions reads PixelXDimension/PixelYDimension from a JPEG's EXIF IFD in
+// either byte order (exiftool writes big-endian, the builtin injector little-endian)
+func exifPixelDimensions(data []byte) (width, height int, ok bool) {
+	idx := bytes.Index(data, []byte("Exif\x00\x00"))
+	if idx < 0 {
+		return 0, 0, false
+	}
+	tiff := data[idx+6:]
+	if len(tiff) < 8 {
+		return 0, 0, false
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if string(tiff[:2]) == "MM" {
+		order = binary.BigEndian
+	}
+
+	// entries calls fn for each tag in the IFD at offset with its type and value field
+	entries := func(offset uint32, fn func(tag, typ uint16, value []byte)) {
+		if int(offset)+2 > len(tiff) {
+			return
+		}
+		count := int(order.Uint16(tiff[offset:]))
+		for i := 0; i < count; i++ {
+			e := int(offset) + 2 + i*12
+			if e+12 > len(tiff) {
+				return
+			}
+			fn(order.Uint16(tiff[e:]), order.Uint16(tiff[e+2:]), tiff[e+8:e+12])
+		}
+	}
+
+	var exifIFD uint32
+	entries(order.Uint32(tiff[4:]), func(tag, _ uint16, value []byte) {
+		if tag == 0x8769 {
+			exifIFD = order.Uint32(value)
+		}
+	})
+	if exifIFD == 0 {
+		return 0, 0, false
+	}
+	entries(exifIFD, func(tag, typ uint16, value []byte) {
+		v := int(order.Uint32(value))
+		if typ == 3 { // SHORT
+			v = int(order.Uint16(value))
+		}
+		switch tag {
+		case 0xA002:
+			width = v
+		case 0xA003:
+			height = v
+		}
+	})
+	return width, height, width > 0 && height > 0
+}
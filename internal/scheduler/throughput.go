@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+)
+
+// Throughput compares a camera's configured capture rate with the smoothed rates it
+// actually achieves. Rates are per minute, averaged with an exponential decay.
+type Throughput struct {
+	ConfiguredPerMin    float64 `json:"configured_captures_per_min"` // From the capture interval
+	CapturesPerMin      float64 `json:"captures_per_min"`            // Frames captured and queued
+	CapturedBytesPerMin float64 `json:"captured_bytes_per_min"`
+	UploadsPerMin       float64 `json:"uploads_per_min"` // Frames uploaded successfully
+	UploadedBytesPerMin float64 `json:"uploaded_bytes_per_min"`
+	HalfLife            float64 `json:"half_life_seconds"`
+}
+
+// rateMeter is an exponentially decaying per-minute rate of events and their bytes.
+// An event's weight halves every halfLife, so the rate follows changes within a few
+// half-lives without being thrown by a single slow capture. Not safe for concurrent
+// use; callers hold their worker's lock.
+type rateMeter struct {
+	halfLife time.Duration
+	started  time.Time // First event; rates before a full window are scaled up
+	last     time.Time // Time events and bytes were last decayed to
+	events   float64   // Decayed event count
+	bytes    float64   // Decayed byte count
+}
+
+func newRateMeter(halfLife time.Duration) *rateMeter {
+	return &rateMeter{halfLife: halfLife}
+}
+
+// add records one event of size bytes at now
+func (m *rateMeter) add(now time.Time, size int64) {
+	if m.started.IsZero() {
+		m.started = now
+		m.last = now
+	}
+	m.events = m.decayed(m.events, now)
+	m.bytes = m.decayed(m.bytes, now)
+	m.last = now
+	m.events++
+	m.bytes += float64(size)
+}
+
+// perMinute returns the smoothed events and bytes per minute at now
+func (m *rateMeter) perMinute(now time.Time) (events, bytes float64) {
+	if m.started.IsZero() {
+		return 0, 0
+	}
+	events, bytes = m.decayed(m.events, now), m.decayed(m.bytes, now)
+
+	// The decayed sum of a steady rate r approaches r·halfLife/ln2; until the meter
+	// has run several half-lives, divide by the weight the elapsed time can hold
+	lambda := math.Ln2 / m.halfLife.Minutes()
+	elapsed := now.Sub(m.started).Minutes()
+	window := (1 - math.Exp(-lambda*elapsed)) / lambda
+	if window < m.halfLife.Minutes()/10 {
+		window = m.halfLife.Minutes() / 10 // A lone first event is not a huge rate
+	}
+	return events / window, bytes / window
+}
+
+// decayed ages a sum from m.last to now
+func (m *rateMeter) decayed(sum float64, now time.Time) float64 {
+	dt := now.Sub(m.last)
+	if dt <= 0 {
+		return sum
+	}
+	return sum * math.Exp(-math.Ln2*dt.Seconds()/m.halfLife.Seconds())
+}
+
+// recordCaptured adds a queued frame to the capture rate, when rates are enabled
+func (w *CaptureWorker) recordCaptured(size int) {
+	if w.config.ThroughputHalfLife <= 0 {
+		return
+	}
+	w.mu.Lock()
+	if w.captured == nil {
+		w.captured = newRateMeter(w.config.ThroughputHalfLife)
+	}
+	w.captured.add(time.Now(), int64(size))
+	w.mu.Unlock()
+}
+
+// throughputLocked returns the capture side of the camera's rates, or nil when rates
+// are disabled (caller must hold w.mu)
+func (w *CaptureWorker) throughputLocked(now time.Time) *Throughput {
+	halfLife := w.config.ThroughputHalfLife
+	if halfLife <= 0 {
+		return nil
+	}
+	t := &Throughput{
+		ConfiguredPerMin: time.Minute.Seconds() / w.interval.Seconds(),
+		HalfLife:         halfLife.Seconds(),
+	}
+	if w.captured != nil {
+		t.CapturesPerMin, t.CapturedBytesPerMin = w.captured.perMinute(now)
+	}
+	return t
+}
+
+// recordUploadedLocked adds an uploaded frame to the camera's upload rate (caller must
+// hold w.mu)
+func (w *UploadWorker) recordUploadedLocked(state *uploadFailureState, config CameraConfig, now time.Time, size int64) {
+	if config.ThroughputHalfLife <= 0 {
+		return
+	}
+	if state.uploaded == nil {
+		state.uploaded = newRateMeter(config.ThroughputHalfLife)
+	}
+	state.uploaded.add(now, size)
+}
+
+// UploadThroughput returns a camera's smoothed uploads and bytes per minute; both
+// are 0 until a frame has been uploaded with rates enabled
+func (w *UploadWorker) UploadThroughput(cameraID string) (uploads, bytes float64) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	state, ok := w.cameraFailures[cameraID]
+	if !ok || state.uploaded == nil {
+		return 0, 0
+	}
+	return state.uploaded.perMinute(time.Now())
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRateMeter_SteadyRate(t *testing.T) {
+	m := newRateMeter(5 * time.Minute)
+	start := time.Now()
+
+	// Two 1000-byte frames a minute for an hour
+	now := start
+	for i := 0; i < 120; i++ {
+		m.add(now, 1000)
+		now = now.Add(30 * time.Second)
+	}
+	events, bytes := m.perMinute(now)
+	if math.Abs(events-2) > 0.1 || math.Abs(bytes-2000) > 100 {
+		t.Errorf("perMinute = %.2f events, %.0f bytes, want about 2 and 2000", events, bytes)
+	}
+
+	// Early rates are not understated while the meter warms up
+	early := newRateMeter(5 * time.Minute)
+	for i := 0; i < 4; i++ {
+		early.add(start.Add(time.Duration(i)*30*time.Second), 1000)
+	}
+	if events, _ := early.perMinute(start.Add(2 * time.Minute)); math.Abs(events-2) > 0.3 {
+		t.Errorf("early perMinute = %.2f events, want about 2", events)
+	}
+}
+
+func TestRateMeter_Decays(t *testing.T) {
+	m := newRateMeter(time.Minute)
+	start := time.Now()
+	for i := 0; i < 60; i++ {
+		m.add(start.Add(time.Duration(i)*time.Second*10), 500)
+	}
+	end := start.Add(10 * time.Minute)
+	before, _ := m.perMinute(end)
+	after, _ := m.perMinute(end.Add(time.Minute))
+	if math.Abs(after-before/2) > 0.01 {
+		t.Errorf("rate one half-life later = %.3f, want half of %.3f", after, before)
+	}
+	if events, bytes := newRateMeter(time.Minute).perMinute(end); events != 0 || bytes != 0 {
+		t.Errorf("empty meter = %v, %v, want 0", events, bytes)
+	}
+}
+
+func TestCaptureWorker_Throughput(t *testing.T) {
+	cam := &mockCamera{id: "rate-cam", camType: "http"}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "rate-cam", ThroughputHalfLife: time.Minute},
+		Queue:        newTestQueue(t, "rate-cam"),
+		IntervalSecs: 30,
+	})
+	w.recordCaptured(2048)
+
+	stats := w.GetStats()
+	if stats.Throughput == nil {
+		t.Fatal("Throughput = nil with a half-life set")
+	}
+	if stats.Throughput.ConfiguredPerMin != 2 || stats.Throughput.CapturesPerMin <= 0 || stats.Throughput.CapturedBytesPerMin <= 0 {
+		t.Errorf("Throughput = %+v", stats.Throughput)
+	}
+
+	off := NewCaptureWorker(CaptureWorkerConfig{Camera: cam, CameraConfig: CameraConfig{ID: "rate-cam"}, Queue: newTestQueue(t, "rate-cam")})
+	off.recordCaptured(2048)
+	if s := off.GetStats(); s.Throughput != nil || off.captured != nil {
+		t.Errorf("Throughput = %+v with rates disabled", s.Throughput)
+	}
+}
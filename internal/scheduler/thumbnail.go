@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// thumbnailQueueID names a camera's thumbnail queue. Camera IDs cannot contain dots,
+// so it never collides with a real camera
+func thumbnailQueueID(cameraID string) string {
+	return cameraID + ".thumb"
+}
+
+// queueThumbnail derives the thumbnail from the processed (unstamped) full image and
+// queues it for its own upload. Failures are counted and logged but never affect the
+// full image, which is already queued.
+func (w *CaptureWorker) queueThumbnail(ctx context.Context, imageData []byte, observation timepkg.ObservationResult, meta timepkg.FrameMeta) {
+	if w.config.Thumbnail == nil || w.thumbQueue == nil {
+		return
+	}
+
+	if w.resourceLimiter != nil {
+		if err := w.resourceLimiter.AcquireImageProcessing(ctx); err != nil {
+			return
+		}
+		defer w.resourceLimiter.ReleaseImageProcessing()
+	}
+
+	thumb, err := w.config.Thumbnail.Processor.Process(imageData)
+	if err == nil {
+		// Builtin stamping keeps this off exiftool; re-encoding dropped the camera EXIF anyway
+		if w.shouldStamp(observation) {
+			if stamp := timepkg.StampBridgeEXIF(thumb, observation, meta); stamp.Stamped {
+				thumb = w.commentJPEG(stamp.Data, stamp.Marker)
+			}
+		}
+		err = w.thumbQueue.Enqueue(thumb, observation.Time, string(observation.Source), string(observation.Confidence))
+	}
+	if err == nil || err == queue.ErrCapturePaused {
+		return
+	}
+
+	w.mu.Lock()
+	w.thumbnailsFailed++
+	w.mu.Unlock()
+	w.logger.Warn("Thumbnail not queued",
+		"camera", w.camera.ID(),
+		"error", err)
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"bytes"
+	goimage "image"
+	"image/jpeg"
+	"os"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+)
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, goimage.NewGray(goimage.Rect(0, 0, width, height)), nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func newThumbnailWorker(t *testing.T, data []byte) *CaptureWorker {
+	t.Helper()
+	thumbCfg := &ThumbnailConfig{
+		Processor:  image.NewProcessor((&config.Thumbnail{MaxWidth: 160}).ImageProcessing()),
+		RemotePath: "thumb",
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:         &mockCamera{id: "thumb-cam", camType: "http", data: data},
+		CameraConfig:   CameraConfig{ID: "thumb-cam", Thumbnail: thumbCfg},
+		Queue:          newTestQueue(t, "thumb-cam"),
+		ThumbnailQueue: newTestQueue(t, thumbnailQueueID("thumb-cam")),
+	})
+	return w
+}
+
+func TestCaptureWorker_QueuesThumbnail(t *testing.T) {
+	w := newThumbnailWorker(t, testJPEG(t, 640, 480))
+	w.capture()
+
+	if w.queue.GetImageCount() != 1 || w.thumbQueue.GetImageCount() != 1 {
+		t.Fatalf("queued full=%d thumb=%d, want 1/1", w.queue.GetImageCount(), w.thumbQueue.GetImageCount())
+	}
+	full, _ := w.queue.Peek(1)
+	thumb, _ := w.thumbQueue.Peek(1)
+	if !full[0].Timestamp.Equal(thumb[0].Timestamp) {
+		t.Errorf("thumbnail timestamp %v differs from full image %v", thumb[0].Timestamp, full[0].Timestamp)
+	}
+
+	data, err := os.ReadFile(thumb[0].FilePath)
+	if err != nil {
+		t.Fatalf("read thumbnail: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode thumbnail: %v", err)
+	}
+	if cfg.Width != 160 || cfg.Height != 120 {
+		t.Errorf("thumbnail is %dx%d, want 160x120", cfg.Width, cfg.Height)
+	}
+	if stats := w.GetStats(); stats.ThumbnailsFailed != 0 {
+		t.Errorf("ThumbnailsFailed = %d, want 0", stats.ThumbnailsFailed)
+	}
+}
+
+func TestCaptureWorker_ThumbnailFailureKeepsFullImage(t *testing.T) {
+	// The stub frame is queued as-is but cannot be decoded, so only the thumbnail fails
+	w := newThumbnailWorker(t, minimalTestJPEG())
+	w.capture()
+
+	if w.queue.GetImageCount() != 1 {
+		t.Errorf("full image queued = %d, want 1", w.queue.GetImageCount())
+	}
+	if w.thumbQueue.GetImageCount() != 0 {
+		t.Errorf("thumbnail queued = %d, want 0", w.thumbQueue.GetImageCount())
+	}
+	stats := w.GetStats()
+	if stats.ThumbnailsFailed != 1 || stats.CapturesFailed != 0 {
+		t.Errorf("ThumbnailsFailed=%d CapturesFailed=%d, want 1/0", stats.ThumbnailsFailed, stats.CapturesFailed)
+	}
+}
+
+func TestThumbnailUploadConfig(t *testing.T) {
+	cam := CameraConfig{
+		RemotePath:   ".",
+		FreshnessSLA: 1,
+		Thumbnail:    &ThumbnailConfig{RemotePath: "thumb"},
+	}
+	got := thumbnailUploadConfig("north", cam)
+	if got.ID != "north.thumb" || got.RemotePath != "thumb" || got.Thumbnail != nil || got.FreshnessSLA != 0 {
+		t.Errorf("unexpected thumbnail upload config: %+v", got)
+	}
+	if cam.RemotePath != "." || cam.Thumbnail == nil {
+		t.Error("camera config was modified")
+	}
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// Capture behaviors while bridge time is unhealthy (NTP not synchronized)
+const (
+	TimePolicyStampLow  = "stamp_low" // Capture and stamp with low confidence and a warning code (default)
+	TimePolicyUnstamped = "unstamped" // Capture without the bridge EXIF stamp; server estimates time
+	TimePolicyPause     = "pause"     // Skip captures until time recovers
+)
+
+// NormalizeTimePolicy returns policy if recognized, otherwise the default
+func NormalizeTimePolicy(policy string) string {
+	switch policy {
+	case TimePolicyStampLow, TimePolicyUnstamped, TimePolicyPause:
+		return policy
+	default:
+		return TimePolicyStampLow
+	}
+}
+
+// SetTimePolicy changes the time-unhealthy policy
+func (w *CaptureWorker) SetTimePolicy(policy string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timePolicy = NormalizeTimePolicy(policy)
+}
+
+// pausedForTime reports whether capture should be skipped because time is unhealthy
+// under the pause policy, logging transitions in and out of the paused state
+func (w *CaptureWorker) pausedForTime() bool {
+	w.mu.Lock()
+	paused := w.timePolicy == TimePolicyPause && w.authority != nil && !w.authority.IsNTPHealthy()
+	changed := paused != w.timePaused
+	w.timePaused = paused
+	w.mu.Unlock()
+
+	if changed {
+		if paused {
+			w.logger.Warn("Capture paused until bridge time is healthy", "camera", w.camera.ID())
+		} else {
+			w.logger.Info("Bridge time healthy, capture resumed", "camera", w.camera.ID())
+		}
+	}
+	return paused
+}
+
+// shouldStamp reports whether the bridge EXIF marker should be written for observation
+func (w *CaptureWorker) shouldStamp(observation timepkg.ObservationResult) bool {
+	w.mu.RLock()
+	policy := w.timePolicy
+	w.mu.RUnlock()
+
+	unhealthy := observation.Warning != nil && observation.Warning.Code == timepkg.WarningNTPUnhealthy
+	if policy == TimePolicyUnstamped && unhealthy {
+		w.logger.Debug("EXIF stamp skipped while bridge time is unhealthy", "camera", w.camera.ID())
+		return false
+	}
+	return true
+}
+
+// RegressionPolicyAuto clamps timestamp regressions while bridge time is healthy (the
+// clock was corrected, so keep frames flowing in order) and rejects them while it is
+// unhealthy (the new time itself is suspect). The other policies are queue.RegressionClamp
+// and queue.RegressionReject.
+const RegressionPolicyAuto = "auto"
+
+// NormalizeRegressionPolicy returns policy if recognized, otherwise the default (clamp)
+func NormalizeRegressionPolicy(policy string) string {
+	if policy == RegressionPolicyAuto {
+		return policy
+	}
+	return queue.NormalizeRegressionPolicy(policy)
+}
+
+// SetRegressionPolicy changes how frames older than the newest queued frame are handled
+func (w *CaptureWorker) SetRegressionPolicy(policy string, tolerance time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.regressionPolicy = NormalizeRegressionPolicy(policy)
+	w.regressionTolerance = tolerance
+}
+
+// effectiveRegressionPolicy resolves the auto policy against current time health
+func (w *CaptureWorker) effectiveRegressionPolicy() string {
+	w.mu.RLock()
+	policy := w.regressionPolicy
+	w.mu.RUnlock()
+
+	if policy != RegressionPolicyAuto {
+		return queue.NormalizeRegressionPolicy(policy)
+	}
+	if w.authority != nil && !w.authority.IsNTPHealthy() {
+		return queue.RegressionReject
+	}
+	return queue.RegressionClamp
+}
+
+// applyRegressionPolicy pushes the effective regression policy to the camera's queues
+// before a capture is enqueued
+func (w *CaptureWorker) applyRegressionPolicy() {
+	policy := w.effectiveRegressionPolicy()
+	w.mu.RLock()
+	tolerance := w.regressionTolerance
+	w.mu.RUnlock()
+
+	w.queue.SetRegressionPolicy(policy, tolerance)
+	if w.thumbQueue != nil {
+		w.thumbQueue.SetRegressionPolicy(policy, tolerance)
+	}
+	if w.spectroQueue != nil {
+		w.spectroQueue.SetRegressionPolicy(policy, tolerance)
+	}
+	for _, q := range w.regionQueues {
+		q.SetRegressionPolicy(policy, tolerance)
+	}
+}
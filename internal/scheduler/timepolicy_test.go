@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
+)
+
+// newUnhealthyTimeWorker returns a worker whose authority reports NTP as unhealthy
+func newUnhealthyTimeWorker(t *testing.T, policy string) (*CaptureWorker, *queue.Queue) {
+	t.Helper()
+	q, err := queue.NewQueue("time-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	// A TimeHealth that has never run a check is unhealthy
+	authority, err := timepkg.NewAuthority(timepkg.NewTimeHealth(timepkg.Config{}), timepkg.DefaultAuthorityConfig())
+	if err != nil {
+		t.Fatalf("NewAuthority: %v", err)
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "time-cam", camType: "http", data: minimalTestJPEG()},
+		CameraConfig: CameraConfig{ID: "time-cam"},
+		Queue:        q,
+		Authority:    authority,
+		TimePolicy:   policy,
+	})
+	return w, q
+}
+
+func TestNormalizeTimePolicy(t *testing.T) {
+	tests := map[string]string{
+		"":                  TimePolicyStampLow,
+		"bogus":             TimePolicyStampLow,
+		TimePolicyStampLow:  TimePolicyStampLow,
+		TimePolicyUnstamped: TimePolicyUnstamped,
+		TimePolicyPause:     TimePolicyPause,
+	}
+	for in, want := range tests {
+		if got := NormalizeTimePolicy(in); got != want {
+			t.Errorf("NormalizeTimePolicy(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTimePolicy_PauseSkipsCapture(t *testing.T) {
+	w, q := newUnhealthyTimeWorker(t, TimePolicyPause)
+
+	w.capture()
+
+	stats := w.GetStats()
+	if !stats.TimePaused {
+		t.Error("expected TimePaused while time is unhealthy")
+	}
+	if stats.CapturesTotal != 0 {
+		t.Errorf("CapturesTotal = %d, want 0", stats.CapturesTotal)
+	}
+	if n := q.GetStats().ImageCount; n != 0 {
+		t.Errorf("queued %d images, want 0", n)
+	}
+}
+
+func TestTimePolicy_StampLowCapturesWithLowConfidence(t *testing.T) {
+	w, q := newUnhealthyTimeWorker(t, "")
+
+	w.capture()
+
+	stats := w.GetStats()
+	if stats.TimePaused {
+		t.Error("stamp_low policy should not pause capture")
+	}
+	if stats.TimeConfidence != string(timepkg.ConfidenceLow) {
+		t.Errorf("TimeConfidence = %q, want %q", stats.TimeConfidence, timepkg.ConfidenceLow)
+	}
+	if stats.TimeSource != string(timepkg.SourceBridgeClock) {
+		t.Errorf("TimeSource = %q, want %q", stats.TimeSource, timepkg.SourceBridgeClock)
+	}
+	if n := q.GetStats().ImageCount; n != 1 {
+		t.Errorf("queued %d images, want 1", n)
+	}
+}
+
+func TestCaptureWorker_TimeSourcePreference(t *testing.T) {
+	w, _ := newUnhealthyTimeWorker(t, "")
+	now := time.Now().UTC()
+	cameraTime := now.Add(-time.Second)
+
+	// A trusted camera clock is used even while the bridge clock is unhealthy
+	w.config.TimeSource = timepkg.PreferCamera
+	if obs := w.determineObservation(now, &cameraTime); obs.Source != timepkg.SourceCameraEXIF {
+		t.Errorf("camera preference used %v", obs.Source)
+	}
+	w.config.TimeSource = timepkg.PreferBridge
+	if obs := w.determineObservation(now, &cameraTime); obs.Source != timepkg.SourceBridgeClock {
+		t.Errorf("bridge preference used %v", obs.Source)
+	}
+}
+
+func TestTimePolicy_ShouldStamp(t *testing.T) {
+	unhealthy := timepkg.ObservationResult{
+		Confidence: timepkg.ConfidenceLow,
+		Warning:    &timepkg.TimeWarning{Code: timepkg.WarningNTPUnhealthy},
+	}
+	healthy := timepkg.ObservationResult{Confidence: timepkg.ConfidenceHigh}
+
+	w, _ := newUnhealthyTimeWorker(t, TimePolicyUnstamped)
+	if w.shouldStamp(unhealthy) {
+		t.Error("unstamped policy should skip stamping while time is unhealthy")
+	}
+	if !w.shouldStamp(healthy) {
+		t.Error("unstamped policy should still stamp when time is healthy")
+	}
+
+	w.SetTimePolicy(TimePolicyStampLow)
+	if !w.shouldStamp(unhealthy) {
+		t.Error("stamp_low policy should stamp while time is unhealthy")
+	}
+}
+
+func TestRegressionPolicy_Effective(t *testing.T) {
+	w, q := newUnhealthyTimeWorker(t, TimePolicyStampLow)
+
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", queue.RegressionClamp},
+		{queue.RegressionReject, queue.RegressionReject},
+		{RegressionPolicyAuto, queue.RegressionReject}, // Time is unhealthy
+	}
+	for _, tt := range tests {
+		w.SetRegressionPolicy(tt.policy, 0)
+		if got := w.effectiveRegressionPolicy(); got != tt.want {
+			t.Errorf("policy %q: effective = %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+
+	// Without an authority, time is assumed healthy
+	w.authority = nil
+	if got := w.effectiveRegressionPolicy(); got != queue.RegressionClamp {
+		t.Errorf("auto without authority = %q, want clamp", got)
+	}
+
+	// The resolved policy reaches the queue before the next enqueue
+	w.SetRegressionPolicy(queue.RegressionReject, 0)
+	w.applyRegressionPolicy()
+	now := time.Now().UTC()
+	if err := q.Enqueue(minimalTestJPEG(), now, "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := q.Enqueue(minimalTestJPEG(), now.Add(-time.Minute), "bridge_clock", "high"); err != queue.ErrTimestampRegression {
+		t.Errorf("Enqueue after regression = %v, want ErrTimestampRegression", err)
+	}
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import "time"
+
+// CaptureTiming breaks down where a capture cycle's time went, in milliseconds
+type CaptureTiming struct {
+	Completed   time.Time `json:"completed"`
+	ThrottleMs  float64   `json:"throttle_ms"`   // Waiting on the resource limiter's pressure delay
+	FetchMs     float64   `json:"fetch_ms"`      // Camera request/stream
+	ExifReadMs  float64   `json:"exif_read_ms"`  // Reading camera EXIF (exiftool), including slot wait
+	ProcessMs   float64   `json:"process_ms"`    // JPEG trim and resize/re-encode
+	ExifStampMs float64   `json:"exif_stamp_ms"` // Writing the bridge EXIF marker (exiftool)
+	EnqueueMs   float64   `json:"enqueue_ms"`    // Writing to the queue
+	TotalMs     float64   `json:"total_ms"`
+	Spooled     bool      `json:"spooled,omitempty"` // Frame was streamed to disk (no processing phase)
+}
+
+// phaseTimer measures consecutive phases of a capture cycle
+type phaseTimer struct {
+	start time.Time
+	last  time.Time
+}
+
+func newPhaseTimer() *phaseTimer {
+	now := time.Now()
+	return &phaseTimer{start: now, last: now}
+}
+
+// lap returns the milliseconds since the previous lap (or start)
+func (p *phaseTimer) lap() float64 {
+	now := time.Now()
+	d := now.Sub(p.last)
+	p.last = now
+	return durationMs(d)
+}
+
+// total returns the milliseconds since the timer started
+func (p *phaseTimer) total() float64 {
+	return durationMs(time.Since(p.start))
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// recordTiming stores the breakdown of the last completed capture cycle
+func (w *CaptureWorker) recordTiming(timing CaptureTiming, timer *phaseTimer) {
+	timing.TotalMs = timer.total()
+	timing.Completed = time.Now()
+
+	w.mu.Lock()
+	w.lastTiming = &timing
+	w.mu.Unlock()
+
+	w.logger.Debug("Capture cycle timing",
+		"camera", w.camera.ID(),
+		"fetch_ms", timing.FetchMs,
+		"exif_read_ms", timing.ExifReadMs,
+		"process_ms", timing.ProcessMs,
+		"exif_stamp_ms", timing.ExifStampMs,
+		"enqueue_ms", timing.EnqueueMs,
+		"total_ms", timing.TotalMs)
+}
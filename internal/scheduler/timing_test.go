@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+)
+
+// slowCamera delays each capture so the fetch phase is measurable
+type slowCamera struct {
+	mockCamera
+	delay time.Duration
+}
+
+func (s *slowCamera) Capture(ctx context.Context) ([]byte, error) {
+	time.Sleep(s.delay)
+	return s.mockCamera.Capture(ctx)
+}
+
+func TestCaptureTiming_RecordedOnSuccess(t *testing.T) {
+	q, err := queue.NewQueue("timing-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	cam := &slowCamera{mockCamera{id: "timing-cam", camType: "http", data: minimalTestJPEG()}, 20 * time.Millisecond}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "timing-cam"},
+		Queue:        q,
+	})
+
+	if w.GetStats().LastTiming != nil {
+		t.Fatal("expected no timing before first capture")
+	}
+
+	w.capture()
+
+	timing := w.GetStats().LastTiming
+	if timing == nil {
+		t.Fatal("expected timing after successful capture")
+	}
+	if timing.FetchMs < 20 {
+		t.Errorf("FetchMs = %.3f, want >= 20", timing.FetchMs)
+	}
+	phases := timing.ThrottleMs + timing.FetchMs + timing.ExifReadMs + timing.ProcessMs + timing.ExifStampMs + timing.EnqueueMs
+	if timing.TotalMs < phases {
+		t.Errorf("TotalMs = %.3f, less than sum of phases %.3f", timing.TotalMs, phases)
+	}
+	if timing.Completed.IsZero() {
+		t.Error("expected Completed to be set")
+	}
+}
+
+func TestCaptureTiming_NotRecordedOnFailure(t *testing.T) {
+	q, err := queue.NewQueue("timing-cam", t.TempDir(), queue.DefaultQueueConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewQueue: %v", err)
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       &mockCamera{id: "timing-cam", camType: "http", err: context.DeadlineExceeded},
+		CameraConfig: CameraConfig{ID: "timing-cam"},
+		Queue:        q,
+	})
+
+	w.capture()
+
+	if w.GetStats().LastTiming != nil {
+		t.Error("failed capture should not record timing")
+	}
+}
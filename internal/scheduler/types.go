@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	timepkg "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/time"
 )
 
 // CameraConfig represents camera configuration needed by scheduler
@@ -12,6 +14,197 @@ type CameraConfig struct {
 	RemotePath     string
 	Enabled        bool
 	ImageProcessor *image.Processor // Optional image processor for resize/quality
+	TrimJPEG       bool             // Trim bytes outside the JPEG SOI/EOI markers
+	UploadServer   string           // host:port uploads go to; cameras sharing it share a circuit breaker
+	RepairJPEG     bool             // Fix a missing/partial EOI or one stray trailing byte
+
+	// RawConverter turns 16-bit PNG and FITS frames into 8-bit JPEG before anything
+	// else; a frame it cannot convert is a failed capture. nil = frames are JPEG
+	RawConverter *image.RawConverter
+
+	// CatchupThreshold is the queue size above which this camera uploads newest-first.
+	// 0 = derived from the capture interval (see CatchupThresholdForInterval)
+	CatchupThreshold int
+
+	// SpoolThresholdBytes streams captures larger than this straight to the queue
+	// directory (streaming cameras only). 0 = always capture in memory
+	SpoolThresholdBytes int64
+
+	// QualitySampleRate is the fraction of frames (0-1) analyzed by the quality self-check
+	QualitySampleRate float64
+
+	// CaptureTimeout bounds each camera capture. 0 = default (30s)
+	CaptureTimeout time.Duration
+
+	// CaptureHangMargin is how long past CaptureTimeout a capture that ignores its
+	// context is waited for before it is abandoned. 0 = default (15s)
+	CaptureHangMargin time.Duration
+
+	// ExifStampRetries is the number of extra exiftool attempts before falling back to
+	// the builtin EXIF injector. 0 = default (1), max 3
+	ExifStampRetries int
+
+	// Thumbnail derives a smaller rendition of each capture, queued and uploaded
+	// separately from the full image. nil = disabled
+	Thumbnail *ThumbnailConfig
+
+	// Spectrogram records audio after captures (RTSP cameras) and uploads it as a
+	// spectrogram image, separately from the frames. nil = disabled
+	Spectrogram *SpectrogramConfig
+
+	// Regions are crops of each capture, each queued and uploaded separately from
+	// the full image
+	Regions []RegionConfig
+
+	// OfflineImage is queued at the capture interval once captures have failed for
+	// a while, until one succeeds. nil = disabled
+	OfflineImage *OfflineImageConfig
+
+	// SettleDelay is how long the worker waits after starting before its first capture,
+	// for cameras that produce boot screens right after power-on. 0 = capture immediately
+	SettleDelay time.Duration
+
+	// ExifNote is written to ImageDescription alongside the bridge marker
+	ExifNote string
+
+	// ExifSequence writes a per-camera frame number, persisted across restarts, to the
+	// EXIF ImageNumber tag of stamped frames so the server can detect gaps
+	ExifSequence bool
+
+	// JPEGComment also writes the bridge marker, camera ID and ExifNote to a JPEG COM
+	// segment of stamped frames, for tools that do not parse EXIF
+	JPEGComment bool
+
+	// TimeSource selects the clock observation times come from. "" = camera EXIF
+	// within tolerance, else the bridge clock
+	TimeSource timepkg.Preference
+
+	// DedupWindow is how many recent frame hashes are checked for repeats
+	// (1 = consecutive only). 0 = disabled
+	DedupWindow int
+
+	// MaxUploadAttempts drops a frame after this many failed upload cycles (each cycle
+	// includes the immediate retry; auth failures don't count). 0 = retry indefinitely
+	MaxUploadAttempts int
+
+	// EventCooldown is the minimum time since the last capture before a camera event
+	// triggers another (event-capable cameras only)
+	EventCooldown time.Duration
+
+	// MinInterval is a hard floor between capture starts for cameras that lock up when
+	// polled too often. It raises a shorter interval, and triggers arriving too soon
+	// are deferred until it allows. 0 = no floor
+	MinInterval time.Duration
+
+	// FreshnessSLA is the maximum age of the last successful upload before the camera
+	// is flagged as breaching its SLA. 0 = no SLA
+	FreshnessSLA time.Duration
+
+	// QuietHours overrides the global upload quiet window. nil = use global
+	QuietHours *QuietHours
+
+	// LatestName is a file in RemotePath overwritten with each newly uploaded frame,
+	// giving viewers a stable URL. Its failures never fail the frame. "" = disabled
+	LatestName string
+
+	// RemoteDedup, when the queue is added, drops frames queued within this window
+	// that already exist on the server. Needs an uploader that can list (see
+	// upload.Lister). 0 = disabled
+	RemoteDedup time.Duration
+
+	// SizeBand refuses to upload frames outside a size range. Not applied to
+	// thumbnails, regions or spectrograms. nil = any size
+	SizeBand *SizeBand
+
+	// FilenameTimeTokens appends the frame's time source, confidence and/or a warn
+	// flag to its remote filename (see FilenameTokenSource). nil = timestamp only
+	FilenameTimeTokens []string
+
+	// OutageSlowdown slows interval captures while the camera's uploads keep
+	// failing. Event-triggered captures are not slowed. nil = always full rate
+	OutageSlowdown *OutageSlowdown
+
+	// ThroughputHalfLife smooths the camera's achieved capture and upload rates
+	// (see Throughput). 0 = rates not tracked
+	ThroughputHalfLife time.Duration
+
+	// UploadBatchSize uploads up to this many queued frames over one connection
+	// before releasing it, marking each uploaded on its own. Needs an uploader that
+	// can hold a connection (see upload.Sessioner). 0 or 1 = one frame per upload
+	UploadBatchSize int
+
+	// PostCaptureHook runs an external command on each processed frame before it
+	// is stamped. Config validation refuses it with spooling or regions, which
+	// would bypass it; offline images are not hooked.
+	// nil = no hook
+	PostCaptureHook *PostCaptureHook
+
+	// LiveOnly, when catching up, uploads only the newest frame and drops the older
+	// backlog, favoring freshness over a complete archive
+	LiveOnly bool
+
+	// Queue sets the capacity of the camera's queue (and its thumbnail queue). Zero
+	// fields keep the queue defaults
+	Queue QueueLimits
+
+	// Logger replaces the orchestrator's logger for this camera's capture and upload
+	// messages, e.g. to log it at its own level. nil = the orchestrator's
+	Logger Logger
+}
+
+// QueueLimits are a camera queue's capacity limits and what happens when it fills
+type QueueLimits struct {
+	MaxFiles  int
+	MaxSizeMB int
+	MaxAge    time.Duration
+
+	// PreferPause turns thinning off and pauses capture at PauseAt of capacity
+	// instead (default 0.80), keeping every queued frame at the cost of a coverage
+	// gap. Capture resumes below ResumeAt (default 0.70), which applies either way.
+	PreferPause bool
+	PauseAt     float64
+	ResumeAt    float64
+}
+
+// DefaultPreferPauseAt is where a queue preferring pause over thinning pauses capture
+const DefaultPreferPauseAt = 0.80
+
+// apply overrides cfg's capacity limits with the non-zero limits
+func (l QueueLimits) apply(cfg queue.QueueConfig) queue.QueueConfig {
+	if l.MaxFiles > 0 {
+		cfg.MaxFiles = l.MaxFiles
+	}
+	if l.MaxSizeMB > 0 {
+		cfg.MaxSizeMB = l.MaxSizeMB
+	}
+	if l.MaxAge > 0 {
+		cfg.MaxAgeSeconds = int(l.MaxAge / time.Second)
+	}
+	if l.ResumeAt > 0 {
+		cfg.ResumeThreshold = l.ResumeAt
+	}
+	if l.PreferPause {
+		cfg.ThinningEnabled = false
+		cfg.PauseCaptureOnCritical = true
+		cfg.PauseThreshold = DefaultPreferPauseAt
+		if l.PauseAt > 0 {
+			cfg.PauseThreshold = l.PauseAt
+		}
+	}
+	return cfg
+}
+
+// ThumbnailConfig configures a camera's thumbnail rendition
+type ThumbnailConfig struct {
+	Processor  *image.Processor // Resize/quality applied to the processed full image
+	RemotePath string           // Remote directory for thumbnails, distinct from the full image's
+}
+
+// RegionConfig configures one cropped region of a camera's captures
+type RegionConfig struct {
+	Name       string
+	Processor  *image.Processor // Crop, masking and resize applied to the unprocessed capture
+	RemotePath string           // Remote directory, distinct from the full image's
 }
 
 // CameraState tracks the state of a single camera
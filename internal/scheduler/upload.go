@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"runtime/debug"
@@ -28,11 +29,12 @@ type UploadWorker struct {
 	logger     Logger
 
 	// Concurrent upload configuration
-	maxConcurrent      int        // Max concurrent uploads (default: 3)
-	catchupThreshold   int        // Queue size to trigger LIFO mode (default: 20)
-	activeUploads      int        // Current number of active uploads
-	connectionMutex    sync.Mutex // Ensures only one connection established at a time
-	lastConnectionTime time.Time  // Track last connection for rate limiting
+	maxConcurrent      int                    // Max concurrent uploads (default: 3); the pool size when auto-tuning
+	tuner              *concurrencyController // Auto-tuned limit within maxConcurrent; nil = fixed
+	catchupThreshold   int                    // Fallback queue size to trigger LIFO mode (default: 20)
+	activeUploads      int                    // Current number of active uploads
+	connectionMutex    sync.Mutex             // Ensures only one connection established at a time
+	lastConnectionTime time.Time              // Track last connection for rate limiting
 
 	// In-flight tracking to prevent duplicate uploads
 	inFlight   map[string]bool // File paths currently being uploaded
@@ -44,29 +46,81 @@ type UploadWorker struct {
 	retryDelay         time.Duration // Delay before single retry
 	connectionInterval time.Duration // Minimum time between new connections (default: 2s)
 
+	// Quiet hours: uploads skipped during a daily window (per-camera overrides global)
+	quietHours  *QuietHours
+	quietActive map[string]bool // Cameras currently in quiet hours, for transition logging
+
+	// Failed upload cycles per queued frame, for cameras with MaxUploadAttempts
+	frameAttempts frameAttempts
+
+	// Per-server circuit breakers (nil when disabled)
+	breakers *breakers
+
+	// Total upload bandwidth limit shared by all uploads
+	bandwidth *bandwidthLimiter
+
+	// Queues whose remote dedup is still running; their uploads wait for it
+	deduping map[string]*queue.Queue
+
 	// Statistics
 	uploadsTotal      int64
 	uploadsSuccess    int64
 	uploadsFailed     int64
 	uploadsRetried    int64
-	uploadsToday      int64     // Daily counter
-	todayDate         time.Time // Track current day for reset
+	uploadsAbandoned  int64
+	liveOnlyDropped   int64          // Backlog frames dropped by live-only cameras
+	alreadyUploaded   int64          // Queued frames dropped by remote dedup
+	sizeRejected      int64          // Frames outside their camera's size band
+	verifyFailures    int64          // Uploads whose remote size did not match
+	latestUploads     int64          // Stable-name copies written
+	latestFailures    int64          // Stable-name copies that failed (frame still uploaded)
+	batchedUploads    int64          // Frames uploaded over a batch's already-open connection
+	uploadsToday      int64          // Daily counter
+	todayDate         time.Time      // Track current day for reset
+	location          *time.Location // Zone whose midnight resets uploadsToday
 	authFailures      int64
 	lastUploadTime    time.Time
 	lastSuccessTime   time.Time
+	lastDuration      time.Duration // Of the last successful upload, retries included
 	lastFailureTime   time.Time
 	lastFailureReason string
 
 	// Per-camera failure tracking (for fail2ban awareness)
 	cameraFailures map[string]*uploadFailureState
+
+	// Called when a camera breaches or recovers its freshness SLA
+	onSLAChange func(SLAEvent)
+
+	// Notified of each failed upload, e.g. for MQTT events
+	onUploadFailure func(cameraID string, err error)
+
+	// With no queues the coordinator stops ticking until AddQueue signals wake,
+	// unless stayActive
+	stayActive bool
+	wake       chan struct{}
+	idle       bool
 }
 
 // uploadFailureState tracks failures for a single camera
 type uploadFailureState struct {
 	consecutiveFailures int
 	lastFailure         time.Time
+	lastSuccess         time.Time
 	lastAuthFailure     time.Time
 	backoffUntil        time.Time
+	added               time.Time // Freshness reference before the first upload
+	latestTimestamp     time.Time // Capture time of the frame last written to LatestName
+	breachedSince       time.Time // Freshness SLA breach start; zero when within SLA
+	failingSince        time.Time // First failure since the last success; zero after a success
+
+	uploaded *rateMeter // Smoothed upload rate; nil until rates are tracked
+}
+
+// markFailing starts a failure streak at now unless one is already running
+func (s *uploadFailureState) markFailing(now time.Time) {
+	if s.failingSince.IsZero() {
+		s.failingSince = now
+	}
 }
 
 // uploadTask represents a single upload job
@@ -77,20 +131,39 @@ type uploadTask struct {
 	config     CameraConfig
 	uploader   upload.Client
 	remotePath string
+
+	// more are further frames of the camera's batch, uploaded after this one over
+	// the same connection (see CameraConfig.UploadBatchSize)
+	more []uploadTask
 }
 
 // UploadWorkerConfig configures the upload worker
 // Note: Individual uploaders are set per-camera via AddQueue
 type UploadWorkerConfig struct {
-	MaxConcurrent      int           // Maximum concurrent uploads (default: 2)
-	CatchupThreshold   int           // Queue size to trigger LIFO mode (default: 20)
-	MinUploadInterval  time.Duration // Minimum time between uploads (default: 1 second)
-	AuthBackoff        time.Duration // Backoff after auth failure (default: 60 seconds)
-	RetryDelay         time.Duration // Delay before single retry (default: 5 seconds)
-	ConnectionInterval time.Duration // Minimum time between new connections (default: 2 seconds)
+	MaxConcurrent      int                              // Maximum concurrent uploads (default: 2)
+	CatchupThreshold   int                              // Queue size to trigger LIFO mode for cameras without their own threshold (default: 20)
+	MinUploadInterval  time.Duration                    // Minimum time between uploads (default: 1 second)
+	AuthBackoff        time.Duration                    // Backoff after auth failure (default: 60 seconds)
+	RetryDelay         time.Duration                    // Delay before single retry (default: 5 seconds)
+	ConnectionInterval time.Duration                    // Minimum time between new connections (default: 2 seconds)
+	Location           *time.Location                   // Zone for the daily upload counter and quiet hours (default: time.Local)
+	QuietHours         *QuietHours                      // Daily window with no uploads for cameras without their own (default: none)
+	OnSLAChange        func(SLAEvent)                   // Called on freshness SLA breach/recovery (optional)
+	OnUploadFailure    func(cameraID string, err error) // Called when an upload fails after its retry (optional)
+	Concurrency        *ConcurrencyTuning               // Auto-tune concurrency within a range (default: fixed MaxConcurrent)
+	CircuitBreaker     *CircuitBreaker                  // Stop uploading to an unreachable server for a while (default: disabled)
+	StayActive         bool                             // Keep scheduling with no queues (default: idle until a queue is added)
 	Logger             Logger
+
+	// MaxBytesPerSecond caps the total upload rate across all concurrent uploads
+	// (default: 0, unlimited)
+	MaxBytesPerSecond int64
 }
 
+// defaultConnectionInterval staggers connection establishment so several cameras
+// logging in at once do not look like a brute-force attempt to fail2ban
+const defaultConnectionInterval = 2 * time.Second
+
 // NewUploadWorker creates a new upload worker
 func NewUploadWorker(cfg UploadWorkerConfig) *UploadWorker {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -123,7 +196,7 @@ func NewUploadWorker(cfg UploadWorkerConfig) *UploadWorker {
 
 	connectionInterval := cfg.ConnectionInterval
 	if connectionInterval == 0 {
-		connectionInterval = 2 * time.Second // Stagger connection establishment
+		connectionInterval = defaultConnectionInterval
 	}
 
 	logger := cfg.Logger
@@ -131,6 +204,25 @@ func NewUploadWorker(cfg UploadWorkerConfig) *UploadWorker {
 		logger = &defaultLogger{}
 	}
 
+	location := cfg.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	var tuner *concurrencyController
+	if cfg.Concurrency != nil {
+		tuner = newConcurrencyController(*cfg.Concurrency, maxConcurrent)
+		maxConcurrent = tuner.tuning.Max
+	}
+
+	var serverBreakers *breakers
+	if cfg.CircuitBreaker != nil {
+		serverBreakers = newBreakers(*cfg.CircuitBreaker)
+	}
+
+	bandwidth := &bandwidthLimiter{}
+	bandwidth.setLimit(cfg.MaxBytesPerSecond)
+
 	return &UploadWorker{
 		queues:             make(map[string]*queue.Queue),
 		queueOrder:         make([]string, 0),
@@ -140,15 +232,60 @@ func NewUploadWorker(cfg UploadWorkerConfig) *UploadWorker {
 		cancel:             cancel,
 		logger:             logger,
 		maxConcurrent:      maxConcurrent,
+		tuner:              tuner,
 		catchupThreshold:   catchupThreshold,
 		minUploadInterval:  minInterval,
 		authBackoff:        authBackoff,
 		retryDelay:         retryDelay,
 		connectionInterval: connectionInterval,
-		todayDate:          time.Now().Truncate(24 * time.Hour), // Initialize to today at 00:00
+		quietHours:         cfg.QuietHours,
+		quietActive:        make(map[string]bool),
+		frameAttempts:      make(frameAttempts),
+		breakers:           serverBreakers,
+		bandwidth:          bandwidth,
+		deduping:           make(map[string]*queue.Queue),
+		todayDate:          startOfDay(time.Now(), location),
+		location:           location,
 		cameraFailures:     make(map[string]*uploadFailureState),
 		inFlight:           make(map[string]bool),
+		onSLAChange:        cfg.OnSLAChange,
+		onUploadFailure:    cfg.OnUploadFailure,
+		stayActive:         cfg.StayActive,
+		wake:               make(chan struct{}, 1),
+	}
+}
+
+// Catch-up threshold defaults, expressed as minutes of backlog at the camera's cadence
+const (
+	DefaultCatchupMinutes = 10
+	minCatchupThreshold   = 2
+)
+
+// CatchupThresholdForInterval converts a backlog duration (minutes of captures) into
+// a queue size for the given capture interval, so a 5-second and a 30-minute camera
+// enter catch-up mode after a comparable delay rather than the same image count.
+// catchupMinutes <= 0 uses DefaultCatchupMinutes.
+func CatchupThresholdForInterval(intervalSecs, catchupMinutes int) int {
+	if intervalSecs <= 0 {
+		intervalSecs = 60
+	}
+	if catchupMinutes <= 0 {
+		catchupMinutes = DefaultCatchupMinutes
 	}
+
+	threshold := (catchupMinutes*60 + intervalSecs - 1) / intervalSecs
+	if threshold < minCatchupThreshold {
+		threshold = minCatchupThreshold
+	}
+	return threshold
+}
+
+// catchupThresholdFor returns the LIFO threshold for a camera (caller must hold lock)
+func (w *UploadWorker) catchupThresholdFor(cameraID string) int {
+	if cfg, ok := w.configs[cameraID]; ok && cfg.CatchupThreshold > 0 {
+		return cfg.CatchupThreshold
+	}
+	return w.catchupThreshold
 }
 
 // AddQueue adds a camera queue to the upload worker with its own uploader
@@ -160,7 +297,16 @@ func (w *UploadWorker) AddQueue(cameraID string, q *queue.Queue, config CameraCo
 	w.configs[cameraID] = config
 	w.uploaders[cameraID] = uploader
 	w.queueOrder = append(w.queueOrder, cameraID)
-	w.cameraFailures[cameraID] = &uploadFailureState{}
+	w.cameraFailures[cameraID] = &uploadFailureState{added: time.Now()}
+	if config.RemoteDedup > 0 && uploader != nil {
+		w.deduping[cameraID] = q
+		go w.removeAlreadyUploaded(cameraID, q, config, uploader)
+	}
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
 }
 
 // RemoveQueue removes a camera queue from the upload worker
@@ -172,6 +318,9 @@ func (w *UploadWorker) RemoveQueue(cameraID string) {
 	delete(w.configs, cameraID)
 	delete(w.uploaders, cameraID)
 	delete(w.cameraFailures, cameraID)
+	delete(w.quietActive, cameraID)
+	delete(w.frameAttempts, cameraID)
+	delete(w.deduping, cameraID)
 
 	// Remove from queueOrder
 	for i, id := range w.queueOrder {
@@ -212,21 +361,75 @@ func (w *UploadWorker) GetStats() UploadStats {
 	}
 
 	return UploadStats{
-		UploadsTotal:       w.uploadsTotal,
-		UploadsSuccess:     w.uploadsSuccess,
-		UploadsFailed:      w.uploadsFailed,
-		UploadsRetried:     w.uploadsRetried,
-		UploadsToday:       w.uploadsToday,
-		AuthFailures:       w.authFailures,
-		QueuedImages:       queuedTotal,
-		LastUploadTime:     w.lastUploadTime,
-		LastSuccessTime:    w.lastSuccessTime,
-		LastFailureTime:    w.lastFailureTime,
-		LastFailureReason:  w.lastFailureReason,
-		UploadRatePerMin:   uploadRate,
-		PerCameraFailures:  w.copyFailureStats(),
-		CurrentlyUploading: w.activeUploads > 0,
-		ActiveUploads:      w.activeUploads,
+		UploadsTotal:        w.uploadsTotal,
+		UploadsSuccess:      w.uploadsSuccess,
+		UploadsFailed:       w.uploadsFailed,
+		UploadsRetried:      w.uploadsRetried,
+		UploadsAbandoned:    w.uploadsAbandoned,
+		LiveOnlyDropped:     w.liveOnlyDropped,
+		AlreadyUploaded:     w.alreadyUploaded,
+		SizeRejected:        w.sizeRejected,
+		VerifyFailures:      w.verifyFailures,
+		Concurrency:         w.concurrencyLimit(),
+		ConcurrencyAutoTune: w.concurrencyStats(),
+		CircuitBreakers:     w.breakerStats(),
+		Bandwidth:           w.bandwidth.stats(),
+		LatestUploads:       w.latestUploads,
+		LatestFailures:      w.latestFailures,
+		BatchedUploads:      w.batchedUploads,
+		UploadsToday:        w.uploadsToday,
+		AuthFailures:        w.authFailures,
+		QueuedImages:        queuedTotal,
+		LastUploadTime:      w.lastUploadTime,
+		LastSuccessTime:     w.lastSuccessTime,
+		LastUploadMs:        float64(w.lastDuration) / float64(time.Millisecond),
+		LastFailureTime:     w.lastFailureTime,
+		LastFailureReason:   w.lastFailureReason,
+		UploadRatePerMin:    uploadRate,
+		PerCameraFailures:   w.copyFailureStats(),
+		PerCameraSuccess:    w.copyLastSuccess(),
+		CatchupThresholds:   w.copyCatchupThresholds(),
+		QuietHours:          w.copyActiveQuietHours(time.Now()),
+		Freshness:           w.copyFreshness(time.Now()),
+		CurrentlyUploading:  w.activeUploads > 0,
+		ActiveUploads:       w.activeUploads,
+		Idle:                w.idle,
+	}
+}
+
+// concurrencyLimit returns the current limit on concurrent uploads (caller must hold lock)
+func (w *UploadWorker) concurrencyLimit() int {
+	if w.tuner != nil {
+		return w.tuner.limit
+	}
+	return w.maxConcurrent
+}
+
+// concurrencyStats returns auto-tuning counters, or nil when concurrency is fixed
+// (caller must hold lock)
+func (w *UploadWorker) concurrencyStats() *ConcurrencyStats {
+	if w.tuner == nil {
+		return nil
+	}
+	stats := w.tuner.stats
+	return &stats
+}
+
+// recordConcurrency feeds an upload outcome to the auto-tuner, if enabled
+func (w *UploadWorker) recordConcurrency(ok bool, latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.tuner == nil {
+		return
+	}
+	before := w.tuner.limit
+	w.tuner.record(ok, latency, time.Now())
+	if w.tuner.limit != before {
+		w.logger.Info("Upload concurrency adjusted",
+			"from", before,
+			"to", w.tuner.limit,
+			"latency", latency.Round(time.Millisecond),
+			"success", ok)
 	}
 }
 
@@ -239,23 +442,61 @@ func (w *UploadWorker) copyFailureStats() map[string]int64 {
 	return copy
 }
 
+// copyLastSuccess returns the last successful upload time per camera (caller must hold lock)
+func (w *UploadWorker) copyLastSuccess() map[string]time.Time {
+	last := make(map[string]time.Time, len(w.cameraFailures))
+	for id, state := range w.cameraFailures {
+		if !state.lastSuccess.IsZero() {
+			last[id] = state.lastSuccess
+		}
+	}
+	return last
+}
+
+// copyCatchupThresholds returns the effective LIFO threshold per camera (caller must hold lock)
+func (w *UploadWorker) copyCatchupThresholds() map[string]int {
+	thresholds := make(map[string]int, len(w.queues))
+	for id := range w.queues {
+		thresholds[id] = w.catchupThresholdFor(id)
+	}
+	return thresholds
+}
+
 // UploadStats provides upload statistics
 type UploadStats struct {
-	UploadsTotal       int64            `json:"uploads_total"`
-	UploadsSuccess     int64            `json:"uploads_success"`
-	UploadsFailed      int64            `json:"uploads_failed"`
-	UploadsRetried     int64            `json:"uploads_retried"`
-	UploadsToday       int64            `json:"uploads_today"` // Successful uploads today (resets at midnight)
-	AuthFailures       int64            `json:"auth_failures"`
-	QueuedImages       int              `json:"queued_images"`
-	LastUploadTime     time.Time        `json:"last_upload_time"`
-	LastSuccessTime    time.Time        `json:"last_success_time"`
-	LastFailureTime    time.Time        `json:"last_failure_time"`
-	LastFailureReason  string           `json:"last_failure_reason"`
-	UploadRatePerMin   float64          `json:"upload_rate_per_min"`
-	PerCameraFailures  map[string]int64 `json:"per_camera_failures"` // Track failures per camera
-	CurrentlyUploading bool             `json:"currently_uploading"`
-	ActiveUploads      int              `json:"active_uploads"` // Number of concurrent uploads in progress
+	UploadsTotal        int64                      `json:"uploads_total"`
+	UploadsSuccess      int64                      `json:"uploads_success"`
+	UploadsFailed       int64                      `json:"uploads_failed"`
+	UploadsRetried      int64                      `json:"uploads_retried"`
+	UploadsAbandoned    int64                      `json:"uploads_abandoned"` // Frames dropped after MaxUploadAttempts failed cycles
+	LiveOnlyDropped     int64                      `json:"live_only_dropped"` // Backlog frames dropped by live-only cameras in catch-up
+	AlreadyUploaded     int64                      `json:"already_uploaded"`  // Queued frames the server already had (remote dedup)
+	SizeRejected        int64                      `json:"size_rejected"`     // Frames not sent for being outside their size band
+	VerifyFailures      int64                      `json:"verify_failures"`   // Uploads failed by size verification
+	LatestUploads       int64                      `json:"latest_uploads"`    // Stable "latest" copies written
+	LatestFailures      int64                      `json:"latest_failures"`   // Stable "latest" copies that failed
+	BatchedUploads      int64                      `json:"batched_uploads"`   // Frames sent over a batch's open connection
+	UploadsToday        int64                      `json:"uploads_today"`     // Successful uploads today (resets at midnight)
+	AuthFailures        int64                      `json:"auth_failures"`
+	QueuedImages        int                        `json:"queued_images"`
+	LastUploadTime      time.Time                  `json:"last_upload_time"`
+	LastSuccessTime     time.Time                  `json:"last_success_time"`
+	LastUploadMs        float64                    `json:"last_upload_ms"` // Duration of the last successful upload
+	LastFailureTime     time.Time                  `json:"last_failure_time"`
+	LastFailureReason   string                     `json:"last_failure_reason"`
+	UploadRatePerMin    float64                    `json:"upload_rate_per_min"`
+	PerCameraFailures   map[string]int64           `json:"per_camera_failures"`          // Track failures per camera
+	PerCameraSuccess    map[string]time.Time       `json:"per_camera_last_success"`      // Last successful upload per camera
+	CatchupThresholds   map[string]int             `json:"catchup_thresholds"`           // Queue size that triggers LIFO, per camera
+	QuietHours          map[string]string          `json:"upload_quiet_hours,omitempty"` // Window per camera currently in quiet hours
+	Freshness           map[string]FreshnessStatus `json:"freshness_sla,omitempty"`      // Cameras with a freshness SLA
+	CurrentlyUploading  bool                       `json:"currently_uploading"`
+	ActiveUploads       int                        `json:"active_uploads"`        // Number of concurrent uploads in progress
+	Concurrency         int                        `json:"effective_concurrency"` // Current limit on concurrent uploads
+	ConcurrencyAutoTune *ConcurrencyStats          `json:"concurrency_autotune,omitempty"`
+	CircuitBreakers     map[string]BreakerStatus   `json:"circuit_breakers,omitempty"` // Per upload server, once it has failed
+	Idle                bool                       `json:"idle,omitempty"`             // No queues; the coordinator is not ticking
+	Bandwidth           *BandwidthStats            `json:"bandwidth_limit,omitempty"`  // While a bandwidth limit is set
 }
 
 func (w *UploadWorker) run() {
@@ -279,7 +520,8 @@ func (w *UploadWorker) run() {
 
 	w.logger.Info("Upload worker started",
 		"max_concurrent", w.maxConcurrent,
-		"catchup_threshold", w.catchupThreshold)
+		"auto_tune", w.tuner != nil,
+		"default_catchup_threshold", w.catchupThreshold)
 
 	// Work channel for distributing upload tasks
 	workChan := make(chan uploadTask, w.maxConcurrent*2)
@@ -294,25 +536,67 @@ func (w *UploadWorker) run() {
 		}(i)
 	}
 
+	stop := func() {
+		w.logger.Info("Upload worker stopping")
+		close(workChan)
+		wg.Wait()
+		w.logger.Info("Upload worker stopped")
+	}
+
 	// Main coordinator loop
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
 	for {
+		if w.waitForQueues(ticker) {
+			stop()
+			return
+		}
 		select {
 		case <-w.ctx.Done():
-			w.logger.Info("Upload worker stopping")
-			close(workChan)
-			wg.Wait()
-			w.logger.Info("Upload worker stopped")
+			stop()
 			return
 
 		case <-ticker.C:
 			w.scheduleUploads(workChan)
+			w.checkFreshness(time.Now())
 		}
 	}
 }
 
+// waitForQueues idles the coordinator while there are no queues, with ticker
+// stopped, until a queue is added. It returns true if the worker was stopped.
+func (w *UploadWorker) waitForQueues(ticker *time.Ticker) bool {
+	if w.stayActive {
+		return false
+	}
+	select {
+	case <-w.wake: // Drop a signal for a queue that was already scheduled
+	default:
+	}
+	w.mu.Lock()
+	w.idle = len(w.queues) == 0
+	idle := w.idle
+	w.mu.Unlock()
+	if !idle {
+		return false
+	}
+
+	ticker.Stop()
+	w.logger.Info("Upload worker idle - no camera queues")
+	select {
+	case <-w.ctx.Done():
+		return true
+	case <-w.wake:
+	}
+	w.mu.Lock()
+	w.idle = false
+	w.mu.Unlock()
+	w.logger.Info("Upload worker resumed")
+	ticker.Reset(time.Second)
+	return false
+}
+
 // uploadWorkerRoutine is a worker goroutine that processes upload tasks
 func (w *UploadWorker) uploadWorkerRoutine(workerID int, workChan <-chan uploadTask) {
 	for task := range workChan {
@@ -328,6 +612,9 @@ func (w *UploadWorker) uploadWorkerRoutine(workerID int, workChan <-chan uploadT
 				// Cleanup: must run even on panic
 				w.inFlightMu.Lock()
 				delete(w.inFlight, task.image.FilePath)
+				for _, next := range task.more {
+					delete(w.inFlight, next.image.FilePath)
+				}
 				w.inFlightMu.Unlock()
 				w.mu.Lock()
 				w.activeUploads--
@@ -342,25 +629,49 @@ func (w *UploadWorker) uploadWorkerRoutine(workerID int, workChan <-chan uploadT
 				}
 			}()
 
-			success := w.uploadWithRetry(task.cameraID, task.uploader, task.image, task.remotePath)
-
-			if success {
-				if err := task.queue.MarkUploaded(task.image); err != nil {
-					w.logger.Error("Failed to mark uploaded",
-						"worker", workerID,
-						"camera", task.cameraID,
-						"error", err)
-				}
-				w.mu.Lock()
-				if failState, exists := w.cameraFailures[task.cameraID]; exists {
-					failState.consecutiveFailures = 0
-				}
-				w.mu.Unlock()
+			if len(task.more) > 0 {
+				w.uploadBatch(workerID, task)
+				return
 			}
+			w.uploadFrame(workerID, task)
 		}()
 	}
 }
 
+// uploadFrame uploads one queued frame and marks it uploaded. Reports false if the
+// upload failed; a frame rejected by its size band is not a failure.
+func (w *UploadWorker) uploadFrame(workerID int, task uploadTask) bool {
+	if w.rejectBySize(task) {
+		return true
+	}
+	err := w.uploadWithRetry(task.cameraID, task.uploader, task.image, task.remotePath)
+	if err != nil {
+		// Auth failures say nothing about the frame itself
+		if !w.isAuthError(err) {
+			w.recordFrameFailure(task)
+		}
+		return false
+	}
+	w.uploadLatest(task)
+
+	if err := task.queue.MarkUploaded(task.image); err != nil {
+		w.logger.Error("Failed to mark uploaded",
+			"worker", workerID,
+			"camera", task.cameraID,
+			"error", err)
+	}
+	w.mu.Lock()
+	if failState, exists := w.cameraFailures[task.cameraID]; exists {
+		failState.consecutiveFailures = 0
+		failState.lastSuccess = time.Now()
+		failState.failingSince = time.Time{}
+		w.recordUploadedLocked(failState, task.config, time.Now(), task.image.SizeBytes)
+	}
+	w.frameAttempts.clear(task.cameraID, task.image.FilePath)
+	w.mu.Unlock()
+	return true
+}
+
 // scheduleUploads coordinates which images to upload next
 func (w *UploadWorker) scheduleUploads(workChan chan<- uploadTask) {
 	w.mu.RLock()
@@ -370,27 +681,12 @@ func (w *UploadWorker) scheduleUploads(workChan chan<- uploadTask) {
 	}
 
 	// Check how many upload slots are available
-	availableSlots := w.maxConcurrent - w.activeUploads
+	availableSlots := w.concurrencyLimit() - w.activeUploads
 	if availableSlots <= 0 {
 		w.mu.RUnlock()
 		return
 	}
 
-	// Get total queue size to determine if we're catching up
-	totalQueued := 0
-	for _, q := range w.queues {
-		totalQueued += q.GetImageCount()
-	}
-
-	// Determine mode: LIFO (newest first) when catching up, FIFO (oldest first) otherwise
-	newestFirst := totalQueued > w.catchupThreshold
-
-	if newestFirst {
-		w.logger.Debug("Catch-up mode active (LIFO)",
-			"queued", totalQueued,
-			"threshold", w.catchupThreshold)
-	}
-
 	// Round-robin across cameras
 	cameras := make([]string, len(w.queueOrder))
 	copy(cameras, w.queueOrder)
@@ -407,6 +703,7 @@ func (w *UploadWorker) scheduleUploads(workChan chan<- uploadTask) {
 		config := w.configs[cameraID]
 		uploader := w.uploaders[cameraID]
 		failState := w.cameraFailures[cameraID]
+		threshold := w.catchupThresholdFor(cameraID)
 		w.mu.RUnlock()
 
 		// Skip if camera was removed (nil check for safety)
@@ -424,9 +721,34 @@ func (w *UploadWorker) scheduleUploads(workChan chan<- uploadTask) {
 			continue
 		}
 
-		// Try to get an image from this camera's queue
-		img, err := q.Dequeue()
-		if err == queue.ErrQueueEmpty {
+		// Skip until frames the server already has are dropped
+		if w.isDeduping(cameraID) {
+			continue
+		}
+
+		// Skip during quiet hours; the backlog drains via catch-up mode afterward
+		if w.inQuietHours(cameraID, time.Now()) {
+			continue
+		}
+
+		// Skip while the upload server's circuit breaker is open
+		if !w.breakerReady(config.UploadServer) {
+			continue
+		}
+
+		// Determine mode per camera: LIFO (newest first) when catching up, FIFO (oldest first) otherwise
+		queued := q.GetImageCount()
+		newestFirst := queued > threshold
+		if newestFirst {
+			w.logger.Debug("Catch-up mode active (LIFO)",
+				"camera", cameraID,
+				"queued", queued,
+				"threshold", threshold)
+		}
+
+		// Try to get an image (or a batch) from this camera's queue
+		images, err := q.DequeueBatch(max(config.UploadBatchSize, 1), newestFirst)
+		if err == queue.ErrQueueEmpty || (err == nil && len(images) == 0) {
 			continue
 		}
 		if err != nil {
@@ -436,66 +758,124 @@ func (w *UploadWorker) scheduleUploads(workChan chan<- uploadTask) {
 			continue
 		}
 
-		// Check if this image is already being uploaded (prevent duplicate uploads)
+		if newestFirst && config.LiveOnly {
+			images = images[:1] // The rest of the backlog is dropped
+			w.dropBacklog(cameraID, q, images[0])
+		}
+
+		// Check if this image is already being uploaded (prevent duplicate uploads);
+		// the rest of a batch leaves out frames another task still has
 		w.inFlightMu.Lock()
-		if w.inFlight[img.FilePath] {
+		if w.inFlight[images[0].FilePath] {
 			w.inFlightMu.Unlock()
 			continue // Already being processed, skip
 		}
+		batch := []*queue.QueuedImage{images[0]}
+		for _, img := range images[1:] {
+			if !w.inFlight[img.FilePath] {
+				batch = append(batch, img)
+			}
+		}
 		// Mark as in-flight before sending to worker
-		w.inFlight[img.FilePath] = true
+		for _, img := range batch {
+			w.inFlight[img.FilePath] = true
+		}
 		w.inFlightMu.Unlock()
 
-		// Build remote path
-		remotePath := w.buildRemotePath(config.RemotePath, cameraID, img.Timestamp)
+		// Another camera on the same server may have just taken the half-open probe
+		if !w.breakerAllow(config.UploadServer) {
+			w.releaseInFlight(batch)
+			continue
+		}
+
+		tasks := make([]uploadTask, len(batch))
+		for i, img := range batch {
+			tasks[i] = uploadTask{
+				cameraID: cameraID,
+				image:    img,
+				queue:    q,
+				config:   config,
+				uploader: uploader,
+				remotePath: w.buildRemotePath(config.RemotePath, cameraID, img.Timestamp,
+					filenameTimeSuffix(config.FilenameTimeTokens, img.FilePath)),
+			}
+		}
+		task := tasks[0]
+		task.more = tasks[1:]
 
 		// Send task to workers
 		select {
-		case workChan <- uploadTask{
-			cameraID:   cameraID,
-			image:      img,
-			queue:      q,
-			config:     config,
-			uploader:   uploader,
-			remotePath: remotePath,
-		}:
+		case workChan <- task:
 			tasksScheduled++
 		default:
 			// Channel full, remove from in-flight and try again next tick
-			w.inFlightMu.Lock()
-			delete(w.inFlight, img.FilePath)
-			w.inFlightMu.Unlock()
+			w.releaseInFlight(batch)
+			w.breakerRelease(config.UploadServer)
 		}
 	}
 }
 
-func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client, img *queue.QueuedImage, remotePath string) bool {
+// releaseInFlight forgets images that were marked in flight but not sent to a worker
+func (w *UploadWorker) releaseInFlight(images []*queue.QueuedImage) {
+	w.inFlightMu.Lock()
+	defer w.inFlightMu.Unlock()
+	for _, img := range images {
+		delete(w.inFlight, img.FilePath)
+	}
+}
+
+// dropBacklog removes frames older than img from a live-only camera's queue, so
+// catch-up ends after this upload instead of draining stale frames
+func (w *UploadWorker) dropBacklog(cameraID string, q *queue.Queue, img *queue.QueuedImage) {
+	dropped := q.DropOlderThan(img, func(path string) bool {
+		w.inFlightMu.Lock()
+		defer w.inFlightMu.Unlock()
+		return w.inFlight[path]
+	})
+	if dropped == 0 {
+		return
+	}
+	w.mu.Lock()
+	w.liveOnlyDropped += int64(dropped)
+	w.mu.Unlock()
+	w.logger.Info("Live-only camera dropped upload backlog",
+		"camera", cameraID,
+		"dropped", dropped,
+		"kept", img.Filename)
+}
+
+// SetConnectionInterval changes the minimum time between new upload connections,
+// starting with the next connection; 0 restores the default
+func (w *UploadWorker) SetConnectionInterval(d time.Duration) {
+	if d <= 0 {
+		d = defaultConnectionInterval
+	}
+	w.connectionMutex.Lock()
+	defer w.connectionMutex.Unlock()
+	w.connectionInterval = d
+}
+
+// uploadWithRetry uploads one image, retrying once on a non-auth error.
+// Returns nil on success, otherwise the final error.
+func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client, img *queue.QueuedImage, remotePath string) error {
 	w.mu.Lock()
 	w.uploadsTotal++
 	w.lastUploadTime = time.Now()
 	w.mu.Unlock()
 
-	// Connection rate limiting: only one new connection at a time
-	// This prevents triggering fail2ban with multiple simultaneous logins
-	w.connectionMutex.Lock()
-	if !w.lastConnectionTime.IsZero() {
-		elapsed := time.Since(w.lastConnectionTime)
-		if elapsed < w.connectionInterval {
-			time.Sleep(w.connectionInterval - elapsed)
-		}
-	}
-	w.lastConnectionTime = time.Now()
-	w.connectionMutex.Unlock()
+	log := w.cameraLogger(cameraID)
+	w.waitToConnect(uploader)
 
 	// Read image data first to determine size
 	imageData, err := readImageFile(img.FilePath)
 	if err != nil {
-		w.logger.Error("Failed to read image file",
+		log.Error("Failed to read image file",
 			"camera", cameraID,
 			"path", img.FilePath,
 			"error", err)
+		w.breakerRelease(w.uploadServer(cameraID))
 		w.recordFailure(cameraID, err)
-		return false
+		return err
 	}
 
 	// Calculate timeout based on file size and concurrent uploads
@@ -511,6 +891,14 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 		maxUploadTime = 15 * time.Minute
 	}
 
+	// A bandwidth limit can make uploads slower than assumed; allow for every slot
+	// sharing it, beyond the cap
+	if rate := w.bandwidthTimeoutRate(); rate > 0 && rate < minBytesPerSecond {
+		if limited := baseTimeout + time.Duration(len(imageData)/rate)*time.Second; limited > maxUploadTime {
+			maxUploadTime = limited
+		}
+	}
+
 	// Ensure minimum timeout of 3 minutes (generous for slow/shared connections)
 	if maxUploadTime < 3*time.Minute {
 		maxUploadTime = 3 * time.Minute
@@ -518,7 +906,7 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 
 	uploadDeadline := time.After(maxUploadTime)
 
-	w.logger.Debug("Upload timeout calculated",
+	log.Debug("Upload timeout calculated",
 		"camera", cameraID,
 		"file_size_kb", len(imageData)/1024,
 		"timeout", maxUploadTime)
@@ -529,12 +917,13 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 		err     error
 	}
 	resultCh := make(chan uploadResult, 1)
+	started := time.Now()
 
 	// Run upload in goroutine with timeout protection
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				w.logger.Error("Upload panicked in upload goroutine",
+				log.Error("Upload panicked in upload goroutine",
 					"camera", cameraID,
 					"path", remotePath,
 					"panic", r,
@@ -544,9 +933,9 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 		}()
 
 		// First attempt
-		err := uploader.Upload(remotePath, imageData)
+		err := w.send(uploader, remotePath, imageData)
 		if err == nil {
-			w.logger.Debug("Upload successful",
+			log.Debug("Upload successful",
 				"camera", cameraID,
 				"path", remotePath,
 				"size", len(imageData))
@@ -555,7 +944,7 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 		}
 
 		// First attempt failed - analyze error
-		w.logger.Warn("Upload failed, will retry once",
+		log.Warn("Upload failed, will retry once",
 			"camera", cameraID,
 			"error", err)
 
@@ -568,21 +957,23 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 		// Wait before retry
 		time.Sleep(w.retryDelay)
 
-		// Second (and final) attempt
+		// Second (and final) attempt, spaced like a new connection since a failed
+		// batch session has to log in again
 		w.mu.Lock()
 		w.uploadsRetried++
 		w.mu.Unlock()
 
-		err = uploader.Upload(remotePath, imageData)
+		w.waitToConnect(uploader)
+		err = w.send(uploader, remotePath, imageData)
 		if err == nil {
-			w.logger.Info("Upload succeeded on retry",
+			log.Info("Upload succeeded on retry",
 				"camera", cameraID,
 				"path", remotePath)
 			resultCh <- uploadResult{true, nil}
 			return
 		}
 
-		w.logger.Error("Upload failed after retry",
+		log.Error("Upload failed after retry",
 			"camera", cameraID,
 			"error", err)
 		resultCh <- uploadResult{false, err}
@@ -591,27 +982,108 @@ func (w *UploadWorker) uploadWithRetry(cameraID string, uploader upload.Client,
 	// Wait for result or timeout
 	select {
 	case result := <-resultCh:
+		w.recordConcurrency(result.success, time.Since(started))
+		w.recordBreaker(cameraID, !serverUnreachable(result.err, w.isAuthError(result.err)))
 		if result.success {
-			w.recordSuccess()
-			return true
+			w.recordSuccess(time.Since(started))
+			return nil
 		}
 		w.recordFailure(cameraID, result.err)
 		if w.isAuthError(result.err) {
 			w.handleAuthFailure(cameraID)
 		}
-		return false
+		w.notifyUploadFailure(cameraID, result.err)
+		return result.err
 
 	case <-uploadDeadline:
-		w.logger.Error("Upload exceeded maximum time",
+		log.Error("Upload exceeded maximum time",
 			"camera", cameraID,
 			"file_size_kb", len(imageData)/1024,
 			"max_time", maxUploadTime)
-		w.recordFailure(cameraID, fmt.Errorf("upload timeout after %v", maxUploadTime))
-		return false
+		err := fmt.Errorf("%w after %v", errUploadDeadline, maxUploadTime)
+		w.recordConcurrency(false, maxUploadTime)
+		w.recordBreaker(cameraID, false)
+		w.recordFailure(cameraID, err)
+		w.notifyUploadFailure(cameraID, err)
+		return err
+	}
+}
+
+// cameraLogger returns the camera's own logger, or the worker's
+func (w *UploadWorker) cameraLogger(cameraID string) Logger {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if l := w.configs[cameraID].Logger; l != nil {
+		return l
 	}
+	return w.logger
 }
 
-func (w *UploadWorker) buildRemotePath(basePath, cameraID string, timestamp time.Time) string {
+// errUploadDeadline means an upload did not finish within its size-based time limit
+var errUploadDeadline = errors.New("upload timeout")
+
+// waitForConnection rate limits new connections: only one at a time, spaced by the
+// connection interval, so simultaneous logins do not trigger fail2ban
+func (w *UploadWorker) waitForConnection() {
+	w.connectionMutex.Lock()
+	defer w.connectionMutex.Unlock()
+	if !w.lastConnectionTime.IsZero() {
+		elapsed := time.Since(w.lastConnectionTime)
+		if elapsed < w.connectionInterval {
+			time.Sleep(w.connectionInterval - elapsed)
+		}
+	}
+	w.lastConnectionTime = time.Now()
+}
+
+// uploadLatest overwrites the camera's stable-name file with a just-uploaded frame.
+// Older frames (e.g. a backlog drained oldest-first after newer ones) are skipped so
+// the file never goes back in time. Failures are counted and logged only.
+func (w *UploadWorker) uploadLatest(task uploadTask) {
+	if task.config.LatestName == "" {
+		return
+	}
+	w.mu.RLock()
+	failState := w.cameraFailures[task.cameraID]
+	stale := failState == nil || !task.image.Timestamp.After(failState.latestTimestamp)
+	w.mu.RUnlock()
+	if stale {
+		return
+	}
+
+	data, err := readImageFile(task.image.FilePath)
+	if err == nil {
+		w.waitToConnect(task.uploader)
+		err = w.send(task.uploader, w.remoteFilePath(task.config.RemotePath, task.cameraID, task.config.LatestName), data)
+	}
+
+	log := w.logger
+	if task.config.Logger != nil {
+		log = task.config.Logger
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.latestFailures++
+		log.Warn("Latest image upload failed",
+			"camera", task.cameraID,
+			"name", task.config.LatestName,
+			"error", err)
+		return
+	}
+	w.latestUploads++
+	if failState.latestTimestamp.Before(task.image.Timestamp) {
+		failState.latestTimestamp = task.image.Timestamp
+	}
+}
+
+func (w *UploadWorker) buildRemotePath(basePath, cameraID string, timestamp time.Time, suffix string) string {
+	// Use millisecond timestamp for filename, then any time tokens
+	return w.remoteFilePath(basePath, cameraID, fmt.Sprintf("%d%s.jpg", timestamp.UnixMilli(), suffix))
+}
+
+// remoteFilePath returns the remote path of filename in the camera's directory
+func (w *UploadWorker) remoteFilePath(basePath, cameraID, filename string) string {
 	if basePath == "" {
 		basePath = cameraID
 	}
@@ -619,9 +1091,6 @@ func (w *UploadWorker) buildRemotePath(basePath, cameraID string, timestamp time
 	// Ensure path doesn't end with /
 	basePath = strings.TrimSuffix(basePath, "/")
 
-	// Use millisecond timestamp for filename
-	filename := fmt.Sprintf("%d.jpg", timestamp.UnixMilli())
-
 	return fmt.Sprintf("%s/%s", basePath, filename)
 }
 
@@ -652,6 +1121,7 @@ func (w *UploadWorker) handleAuthFailure(cameraID string) {
 	failState.lastAuthFailure = time.Now()
 	failState.backoffUntil = time.Now().Add(w.authBackoff)
 	failState.consecutiveFailures++
+	failState.markFailing(failState.lastAuthFailure)
 
 	w.logger.Warn("Auth failure - backing off to avoid fail2ban",
 		"camera", cameraID,
@@ -659,23 +1129,71 @@ func (w *UploadWorker) handleAuthFailure(cameraID string) {
 		"consecutive_failures", failState.consecutiveFailures)
 }
 
-func (w *UploadWorker) recordSuccess() {
+// SetLocation changes the zone whose midnight resets the daily upload counter.
+// The current count is kept; the next reset happens at midnight in the new zone.
+func (w *UploadWorker) SetLocation(loc *time.Location) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	w.location = loc
+	w.todayDate = startOfDay(time.Now(), loc)
+}
 
-	now := time.Now()
-	today := now.Truncate(24 * time.Hour)
+// Location returns the zone used for the daily upload counter
+func (w *UploadWorker) Location() *time.Location {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.location
+}
 
-	// Reset daily counter if it's a new day
-	if today.After(w.todayDate) {
-		w.uploadsToday = 0
-		w.todayDate = today
-		w.logger.Info("Daily upload counter reset", "date", today.Format("2006-01-02"))
+// FailingSince returns when the camera's current streak of failed uploads began, or
+// zero if its last upload succeeded (or none has failed yet)
+func (w *UploadWorker) FailingSince(cameraID string) time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if failState := w.cameraFailures[cameraID]; failState != nil {
+		return failState.failingSince
 	}
+	return time.Time{}
+}
+
+// RealignDay resets the daily upload counter if now falls on a different day than
+// the one being counted, as after a suspend or a clock jump in either direction
+func (w *UploadWorker) RealignDay(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rollDayLocked(now)
+}
+
+// rollDayLocked resets the daily counter when the day changes. Any other day
+// counts, so a clock set back past midnight starts a fresh count rather than
+// leaving the counter stuck until the old date comes round (caller must hold w.mu)
+func (w *UploadWorker) rollDayLocked(now time.Time) {
+	today := startOfDay(now, w.location)
+	if today.Equal(w.todayDate) {
+		return
+	}
+	w.uploadsToday = 0
+	w.todayDate = today
+	w.logger.Info("Daily upload counter reset", "date", today.Format("2006-01-02"))
+}
+
+// startOfDay returns local midnight of t's day in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+func (w *UploadWorker) recordSuccess(elapsed time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.rollDayLocked(now)
 
 	w.uploadsSuccess++
 	w.uploadsToday++
 	w.lastSuccessTime = now
+	w.lastDuration = elapsed
 }
 
 func (w *UploadWorker) recordFailure(cameraID string, err error) {
@@ -685,10 +1203,14 @@ func (w *UploadWorker) recordFailure(cameraID string, err error) {
 	w.uploadsFailed++
 	w.lastFailureTime = time.Now()
 	w.lastFailureReason = err.Error()
+	if errors.Is(err, upload.ErrSizeMismatch) {
+		w.verifyFailures++
+	}
 
 	failState := w.cameraFailures[cameraID]
 	failState.lastFailure = time.Now()
 	failState.consecutiveFailures++
+	failState.markFailing(failState.lastFailure)
 
 	// Exponential backoff for repeated failures (but less aggressive than auth)
 	if failState.consecutiveFailures > 3 {
@@ -700,6 +1222,13 @@ func (w *UploadWorker) recordFailure(cameraID string, err error) {
 	}
 }
 
+// notifyUploadFailure reports a failed upload to the optional callback
+func (w *UploadWorker) notifyUploadFailure(cameraID string, err error) {
+	if w.onUploadFailure != nil {
+		w.onUploadFailure(cameraID, err)
+	}
+}
+
 // readImageFile reads image data from a file
 func readImageFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/queue"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/upload"
 )
 
 // TestUploadWorker_PerCameraUploaders tests that each camera uses its own uploader
@@ -236,7 +238,7 @@ func TestUploadWorker_BuildRemotePath(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := worker.buildRemotePath(tt.basePath, tt.cameraID, tt.timestamp)
+			result := worker.buildRemotePath(tt.basePath, tt.cameraID, tt.timestamp, "")
 
 			if len(result) < len(tt.wantPrefix)+len(tt.wantSuffix) {
 				t.Errorf("Result too short: %s", result)
@@ -320,6 +322,80 @@ func TestUploadWorker_GetStats(t *testing.T) {
 	}
 }
 
+// TestUploadWorker_VerifyFailures tests that size mismatches are counted separately
+func TestUploadWorker_VerifyFailures(t *testing.T) {
+	worker := NewUploadWorker(UploadWorkerConfig{})
+	worker.AddQueue("cam", newTestQueue(t, "cam"), CameraConfig{ID: "cam"}, &mockUploader{})
+
+	worker.recordFailure("cam", fmt.Errorf("connection reset"))
+	worker.recordFailure("cam", &upload.UploadError{RemotePath: "cam/1.jpg", Message: "remote has 3 bytes, sent 4", Err: upload.ErrSizeMismatch})
+
+	stats := worker.GetStats()
+	if stats.UploadsFailed != 2 || stats.VerifyFailures != 1 {
+		t.Errorf("UploadsFailed = %d, VerifyFailures = %d; want 2 and 1", stats.UploadsFailed, stats.VerifyFailures)
+	}
+}
+
+// pathUploader records uploaded paths, failing those in fail
+type pathUploader struct {
+	mu    sync.Mutex
+	paths []string
+	fail  map[string]bool
+}
+
+func (p *pathUploader) Upload(remotePath string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paths = append(p.paths, remotePath)
+	if p.fail[remotePath] {
+		return fmt.Errorf("permission denied")
+	}
+	return nil
+}
+
+func (p *pathUploader) TestConnection() error { return nil }
+
+// TestUploadWorker_UploadLatest tests the stable-name copy only moves forward in time
+// and that its failures stay out of the frame's upload stats
+func TestUploadWorker_UploadLatest(t *testing.T) {
+	q := newTestQueue(t, "cam")
+	base := time.Now().UTC().Add(-time.Minute)
+	for _, offset := range []int{0, 10, 5} {
+		if err := q.Enqueue(minimalTestJPEG(), base.Add(time.Duration(offset)*time.Second), "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	images, err := q.DequeueBatch(3, false)
+	if err != nil || len(images) != 3 {
+		t.Fatalf("DequeueBatch: %d images, %v", len(images), err)
+	}
+
+	uploader := &pathUploader{}
+	config := CameraConfig{ID: "cam", RemotePath: "kspb/", LatestName: "latest.jpg"}
+	worker := NewUploadWorker(UploadWorkerConfig{ConnectionInterval: time.Millisecond})
+	worker.AddQueue("cam", q, config, uploader)
+
+	// Oldest first: 0s, 5s, 10s; then the 5s frame again after 10s was written
+	for _, img := range []*queue.QueuedImage{images[0], images[1], images[2], images[1]} {
+		worker.uploadLatest(uploadTask{cameraID: "cam", image: img, config: config, uploader: uploader})
+	}
+	if len(uploader.paths) != 3 || uploader.paths[0] != "kspb/latest.jpg" {
+		t.Errorf("latest uploads = %v, want 3 to kspb/latest.jpg", uploader.paths)
+	}
+
+	uploader.fail = map[string]bool{"kspb/latest.jpg": true}
+	if err := q.Enqueue(minimalTestJPEG(), base.Add(20*time.Second), "bridge_clock", "high"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	newest, _ := q.DequeueBatch(1, true)
+	worker.uploadLatest(uploadTask{cameraID: "cam", image: newest[0], config: config, uploader: uploader})
+
+	stats := worker.GetStats()
+	if stats.LatestUploads != 3 || stats.LatestFailures != 1 || stats.UploadsFailed != 0 {
+		t.Errorf("latest=%d failures=%d uploads_failed=%d, want 3/1/0", stats.LatestUploads, stats.LatestFailures, stats.UploadsFailed)
+	}
+}
+
 // TestUploadWorker_ConfigDefaults tests configuration defaults
 func TestUploadWorker_ConfigDefaults(t *testing.T) {
 	worker := NewUploadWorker(UploadWorkerConfig{})
@@ -444,3 +520,147 @@ func TestReadImageFile(t *testing.T) {
 		t.Error("readImageFile() should error on non-existent file")
 	}
 }
+
+// TestCatchupThresholdForInterval tests interval-proportional catch-up thresholds
+func TestCatchupThresholdForInterval(t *testing.T) {
+	tests := []struct {
+		name           string
+		intervalSecs   int
+		catchupMinutes int
+		want           int
+	}{
+		{"default interval and minutes", 60, 0, 10},
+		{"fast camera", 5, 0, 120},
+		{"slow camera clamps to minimum", 1800, 0, 2},
+		{"custom minutes", 60, 30, 30},
+		{"rounds up partial captures", 45, 1, 2},
+		{"invalid interval uses 60s", 0, 5, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CatchupThresholdForInterval(tt.intervalSecs, tt.catchupMinutes)
+			if got != tt.want {
+				t.Errorf("CatchupThresholdForInterval(%d, %d) = %d, want %d",
+					tt.intervalSecs, tt.catchupMinutes, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUploadWorker_PerCameraCatchup tests that LIFO is decided per camera
+func TestUploadWorker_PerCameraCatchup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           tmpDir,
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	fast, _ := queueMgr.CreateQueue("fast", queue.DefaultQueueConfig())
+	slow, _ := queueMgr.CreateQueue("slow", queue.DefaultQueueConfig())
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := 3; i >= 1; i-- {
+		ts := now.Add(-time.Duration(i) * time.Second)
+		if err := fast.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue fast: %v", err)
+		}
+		if err := slow.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high"); err != nil {
+			t.Fatalf("Enqueue slow: %v", err)
+		}
+	}
+
+	worker := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 2})
+	worker.AddQueue("fast", fast, CameraConfig{ID: "fast", CatchupThreshold: 10}, &mockUploader{})
+	worker.AddQueue("slow", slow, CameraConfig{ID: "slow", CatchupThreshold: 2}, &mockUploader{})
+
+	workChan := make(chan uploadTask, 4)
+	worker.scheduleUploads(workChan)
+	close(workChan)
+
+	got := make(map[string]time.Time)
+	for task := range workChan {
+		got[task.cameraID] = task.image.Timestamp
+	}
+
+	if want := now.Add(-3 * time.Second); !got["fast"].Equal(want) {
+		t.Errorf("fast camera below threshold should upload oldest first: got %v, want %v", got["fast"], want)
+	}
+	if want := now.Add(-1 * time.Second); !got["slow"].Equal(want) {
+		t.Errorf("slow camera above threshold should upload newest first: got %v, want %v", got["slow"], want)
+	}
+
+	stats := worker.GetStats()
+	if stats.CatchupThresholds["fast"] != 10 || stats.CatchupThresholds["slow"] != 2 {
+		t.Errorf("CatchupThresholds = %v, want fast=10 slow=2", stats.CatchupThresholds)
+	}
+}
+
+func TestUploadWorker_LiveOnlyDropsBacklog(t *testing.T) {
+	queueMgr, err := queue.NewManager(queue.GlobalQueueConfig{
+		BasePath:           t.TempDir(),
+		MaxTotalSizeMB:     10,
+		MaxHeapMB:          50,
+		MemoryCheckSeconds: 60,
+		EmergencyThinRatio: 0.5,
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	live, _ := queueMgr.CreateQueue("live", queue.DefaultQueueConfig())
+	archive, _ := queueMgr.CreateQueue("archive", queue.DefaultQueueConfig())
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for i := 4; i >= 1; i-- {
+		ts := now.Add(-time.Duration(i) * time.Second)
+		live.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high")
+		archive.Enqueue(minimalTestJPEG(), ts, "bridge_clock", "high")
+	}
+
+	worker := NewUploadWorker(UploadWorkerConfig{MaxConcurrent: 2})
+	worker.AddQueue("live", live, CameraConfig{ID: "live", CatchupThreshold: 2, LiveOnly: true}, &mockUploader{})
+	worker.AddQueue("archive", archive, CameraConfig{ID: "archive", CatchupThreshold: 2}, &mockUploader{})
+
+	workChan := make(chan uploadTask, 4)
+	worker.scheduleUploads(workChan)
+	close(workChan)
+	for task := range workChan {
+		if want := now.Add(-time.Second); !task.image.Timestamp.Equal(want) {
+			t.Errorf("%s uploaded %v, want newest %v", task.cameraID, task.image.Timestamp, want)
+		}
+	}
+
+	if n := live.GetImageCount(); n != 1 {
+		t.Errorf("live-only queue has %d frames, want only the newest", n)
+	}
+	if n := archive.GetImageCount(); n != 4 {
+		t.Errorf("archive queue has %d frames, want all 4", n)
+	}
+	if got := worker.GetStats().LiveOnlyDropped; got != 3 {
+		t.Errorf("LiveOnlyDropped = %d, want 3", got)
+	}
+}
+
+func TestUploadWorker_SetConnectionInterval(t *testing.T) {
+	w := NewUploadWorker(UploadWorkerConfig{})
+	if w.connectionInterval != defaultConnectionInterval {
+		t.Fatalf("default interval = %v, want %v", w.connectionInterval, defaultConnectionInterval)
+	}
+
+	w.SetConnectionInterval(500 * time.Millisecond)
+	if w.connectionInterval != 500*time.Millisecond {
+		t.Errorf("interval = %v, want 500ms", w.connectionInterval)
+	}
+
+	w.SetConnectionInterval(0)
+	if w.connectionInterval != defaultConnectionInterval {
+		t.Errorf("interval = %v, want default after reset", w.connectionInterval)
+	}
+}
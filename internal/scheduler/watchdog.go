@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Capture watchdog defaults
+const (
+	defaultCaptureTimeout    = 30 * time.Second
+	defaultCaptureHangMargin = 15 * time.Second
+	maxHangStackBytes        = 256 * 1024
+)
+
+func (w *CaptureWorker) captureTimeout() time.Duration {
+	if w.config.CaptureTimeout > 0 {
+		return w.config.CaptureTimeout
+	}
+	return defaultCaptureTimeout
+}
+
+func (w *CaptureWorker) captureHangMargin() time.Duration {
+	if w.config.CaptureHangMargin > 0 {
+		return w.config.CaptureHangMargin
+	}
+	return defaultCaptureHangMargin
+}
+
+// captureWithWatchdog runs captureImage in its own goroutine, mirroring the upload
+// worker's deadline channel. Camera reads are meant to honour ctx, but some blocking
+// network reads ignore cancellation; if the capture outlives ctx's deadline by the hang
+// margin, the goroutine stacks are logged and the capture is abandoned so the worker can
+// back off and try again instead of stalling forever.
+func (w *CaptureWorker) captureWithWatchdog(ctx context.Context) ([]byte, string, error) {
+	type captureResult struct {
+		data      []byte
+		spoolPath string
+		err       error
+	}
+	resultCh := make(chan captureResult, 1)
+
+	// The camera stays busy until the read returns, even after it was abandoned
+	w.mu.Lock()
+	w.cameraBusy = true
+	w.mu.Unlock()
+	finish := func(result captureResult) {
+		w.mu.Lock()
+		w.cameraBusy = false
+		w.mu.Unlock()
+		resultCh <- result
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				w.logger.Error("Capture panicked in capture goroutine",
+					"camera", w.camera.ID(),
+					"panic", r,
+					"stack", string(debug.Stack()))
+				finish(captureResult{err: fmt.Errorf("panic: %v", r)})
+			}
+		}()
+		data, spoolPath, err := w.captureImage(ctx)
+		finish(captureResult{data, spoolPath, err})
+	}()
+
+	limit := w.captureHangMargin()
+	if deadline, ok := ctx.Deadline(); ok {
+		limit += time.Until(deadline)
+	}
+	hangDeadline := time.NewTimer(limit)
+	defer hangDeadline.Stop()
+
+	select {
+	case result := <-resultCh:
+		return result.data, result.spoolPath, result.err
+
+	case <-hangDeadline.C:
+		w.mu.Lock()
+		w.captureHangs++
+		w.mu.Unlock()
+
+		w.logger.Error("Capture did not return after its timeout, abandoning it",
+			"camera", w.camera.ID(),
+			"timeout", w.captureTimeout(),
+			"hang_margin", w.captureHangMargin(),
+			"stack", goroutineStacks())
+
+		// Remove the spool file if the abandoned capture ever finishes
+		go func() {
+			if result := <-resultCh; result.spoolPath != "" {
+				_ = os.Remove(result.spoolPath) // Best effort cleanup
+			}
+		}()
+		return nil, "", fmt.Errorf("capture hung: no result %v after timeout", w.captureHangMargin())
+	}
+}
+
+// goroutineStacks returns the stacks of all goroutines, truncated to maxHangStackBytes
+func goroutineStacks() string {
+	buf := make([]byte, maxHangStackBytes)
+	return string(buf[:runtime.Stack(buf, true)])
+}
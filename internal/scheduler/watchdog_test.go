@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// hangingCamera blocks in Capture until released, ignoring ctx like a stuck net read
+type hangingCamera struct {
+	mockCamera
+	release chan struct{}
+}
+
+func (h *hangingCamera) Capture(ctx context.Context) ([]byte, error) {
+	<-h.release
+	return h.data, nil
+}
+
+func TestCaptureWorker_WatchdogAbandonsHungCapture(t *testing.T) {
+	cam := &hangingCamera{
+		mockCamera: mockCamera{id: "hung-cam", camType: "http", data: minimalTestJPEG()},
+		release:    make(chan struct{}),
+	}
+	defer close(cam.release)
+
+	q := newTestQueue(t, "hung-cam")
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: cam,
+		CameraConfig: CameraConfig{
+			ID:                "hung-cam",
+			CaptureTimeout:    20 * time.Millisecond,
+			CaptureHangMargin: 20 * time.Millisecond,
+		},
+		Queue: q,
+	})
+
+	done := make(chan struct{})
+	go func() {
+		w.capture()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("capture should be abandoned after timeout plus hang margin")
+	}
+
+	stats := w.GetStats()
+	if stats.CaptureHangs != 1 || stats.CapturesFailed != 1 {
+		t.Errorf("CaptureHangs=%d CapturesFailed=%d, want 1/1", stats.CaptureHangs, stats.CapturesFailed)
+	}
+	state := w.GetState()
+	if state.LastError == nil || !strings.Contains(state.LastError.Error(), "capture hung") {
+		t.Errorf("LastError = %v", state.LastError)
+	}
+	if state.NextAttempt.IsZero() {
+		t.Error("expected backoff after hung capture")
+	}
+	if q.GetImageCount() != 0 {
+		t.Error("abandoned capture should not be queued")
+	}
+}
+
+func TestCaptureWorker_WatchdogPassesResult(t *testing.T) {
+	cam := &mockCamera{id: "ok-cam", camType: "http", data: minimalTestJPEG()}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera:       cam,
+		CameraConfig: CameraConfig{ID: "ok-cam"},
+		Queue:        newTestQueue(t, "ok-cam"),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data, spoolPath, err := w.captureWithWatchdog(ctx)
+	if err != nil || spoolPath != "" || len(data) != len(cam.data) {
+		t.Fatalf("got %d bytes, spool %q, err %v", len(data), spoolPath, err)
+	}
+	if w.GetStats().CaptureHangs != 0 {
+		t.Error("completed capture should not count as a hang")
+	}
+}
+
+func TestCaptureWorker_SkipsOverlappingCaptures(t *testing.T) {
+	cam := &hangingCamera{
+		mockCamera: mockCamera{id: "slow-cam", camType: "http", data: minimalTestJPEG()},
+		release:    make(chan struct{}),
+	}
+	w := NewCaptureWorker(CaptureWorkerConfig{
+		Camera: cam,
+		CameraConfig: CameraConfig{
+			ID:                "slow-cam",
+			CaptureTimeout:    20 * time.Millisecond,
+			CaptureHangMargin: 20 * time.Millisecond,
+		},
+		Queue: newTestQueue(t, "slow-cam"),
+	})
+
+	// A tick held by the ticker during a slow capture is skipped once it finishes
+	start := time.Now()
+	w.capture()
+	if !w.overlapsPrevious(start) {
+		t.Error("tick due during the previous capture was not skipped")
+	}
+
+	// The abandoned read keeps the camera busy, so later ticks are skipped too
+	if !w.overlapsPrevious(time.Now()) {
+		t.Error("tick while the abandoned read is running was not skipped")
+	}
+	if w.eventCaptureAllowed() {
+		t.Error("event capture allowed while the abandoned read is running")
+	}
+
+	close(cam.release)
+	deadline := time.Now().Add(2 * time.Second)
+	for w.overlapsPrevious(time.Now()) {
+		if time.Now().After(deadline) {
+			t.Fatal("camera still busy after the read returned")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := w.GetStats().SkippedOverlap; n < 2 {
+		t.Errorf("SkippedOverlap = %d, want at least 2", n)
+	}
+}
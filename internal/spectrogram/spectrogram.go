@@ -0,0 +1,134 @@
+// Package spectrogram renders audio clips as spectrogram images
+package spectrogram
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"math/cmplx"
+	"runtime"
+)
+
+// Defaults for Options
+const (
+	DefaultWidth  = 640
+	DefaultHeight = 240
+)
+
+// windowSize is the FFT length: 32 ms at 16 kHz, giving 31 Hz frequency bins
+const windowSize = 512
+
+// floorDB is the level drawn black, relative to a full-scale sine
+const floorDB = -90.0
+
+// ErrTooShort means a clip is shorter than one FFT window
+var ErrTooShort = errors.New("audio clip too short for a spectrogram")
+
+// Options controls the rendered image
+type Options struct {
+	Width  int // Time columns (default 640)
+	Height int // Frequency rows, 0 Hz at the bottom (default 240)
+}
+
+// Render draws samples as a spectrogram: time runs left to right and frequency
+// bottom to top, up to half the sample rate, colored by level in dB.
+func Render(samples []int16, opts Options) (image.Image, error) {
+	if len(samples) < windowSize {
+		return nil, ErrTooShort
+	}
+	if opts.Width <= 0 {
+		opts.Width = DefaultWidth
+	}
+	if opts.Height <= 0 {
+		opts.Height = DefaultHeight
+	}
+
+	window := make([]float64, windowSize)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(windowSize-1))
+	}
+	// A full-scale sine peaks at amplitude * sum(window) / 2
+	fullScale := 32768 * float64(windowSize-1) / 4
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	buf := make([]complex128, windowSize)
+	span := len(samples) - windowSize
+	for x := 0; x < opts.Width; x++ {
+		// Yield like image resizing, so spectrograms never starve the web console
+		if x%50 == 0 && x > 0 {
+			runtime.Gosched()
+		}
+		start := 0
+		if opts.Width > 1 {
+			start = span * x / (opts.Width - 1)
+		}
+		for i := range buf {
+			buf[i] = complex(float64(samples[start+i])*window[i], 0)
+		}
+		fft(buf)
+
+		for y := 0; y < opts.Height; y++ {
+			// Rows cover bins 0 to windowSize/2, low frequencies at the bottom
+			bin := (opts.Height - 1 - y) * (windowSize / 2) / opts.Height
+			level := 20 * math.Log10(cmplx.Abs(buf[bin])/fullScale+1e-12)
+			img.Set(x, y, heat(1-level/floorDB))
+		}
+	}
+	return img, nil
+}
+
+// Encode renders samples and encodes the spectrogram as a JPEG
+func Encode(samples []int16, opts Options) ([]byte, error) {
+	img, err := Render(samples, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// heat maps v (0-1, clamped) from black through blue, red and yellow to white
+func heat(v float64) color.RGBA {
+	v = min(max(v, 0), 1)
+	stops := []color.RGBA{
+		{0, 0, 0, 255}, {32, 0, 128, 255}, {200, 0, 64, 255}, {255, 200, 0, 255}, {255, 255, 255, 255},
+	}
+	pos := v * float64(len(stops)-1)
+	i := min(int(pos), len(stops)-2)
+	f := pos - float64(i)
+	lerp := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*f + 0.5) }
+	a, b := stops[i], stops[i+1]
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 255}
+}
+
+// fft transforms x in place; len(x) must be a power of two
+func fft(x []complex128) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j |= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = even+odd, even-odd
+				w *= step
+			}
+		}
+	}
+}
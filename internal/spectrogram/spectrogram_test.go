@@ -0,0 +1,71 @@
+package spectrogram
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"math"
+	"testing"
+)
+
+// tone returns seconds of a sine at freq Hz, sampled at rate
+func tone(freq float64, rate int, seconds float64, amplitude float64) []int16 {
+	samples := make([]int16, int(float64(rate)*seconds))
+	for i := range samples {
+		samples[i] = int16(amplitude * 32767 * math.Sin(2*math.Pi*freq*float64(i)/float64(rate)))
+	}
+	return samples
+}
+
+// brightness sums a pixel's channels
+func brightness(img image.Image, x, y int) uint32 {
+	r, g, b, _ := img.At(x, y).RGBA()
+	return r + g + b
+}
+
+func TestRender_ToneLandsOnItsFrequency(t *testing.T) {
+	// 2 kHz of a 16 kHz clip sits a quarter of the way up from the bottom
+	img, err := Render(tone(2000, 16000, 1, 0.5), Options{Width: 64, Height: 100})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 100 {
+		t.Fatalf("size = %v, want 64x100", b)
+	}
+	for _, x := range []int{0, 32, 63} {
+		brightest := 0
+		for y := 1; y < 100; y++ {
+			if brightness(img, x, y) > brightness(img, x, brightest) {
+				brightest = y
+			}
+		}
+		if brightest < 73 || brightest > 76 {
+			t.Errorf("column %d: brightest row %d, want about 75", x, brightest)
+		}
+	}
+}
+
+func TestRender_SilenceIsBlack(t *testing.T) {
+	img, err := Render(make([]int16, 4000), Options{Width: 8, Height: 8})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if b := brightness(img, 4, 4); b != 0 {
+		t.Errorf("silence brightness = %d, want black", b)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	if _, err := Encode(make([]int16, windowSize-1), Options{}); !errors.Is(err, ErrTooShort) {
+		t.Errorf("short clip error = %v, want ErrTooShort", err)
+	}
+	data, err := Encode(tone(440, 16000, 0.5, 0.2), Options{})
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width != DefaultWidth || cfg.Height != DefaultHeight {
+		t.Errorf("output = %dx%d (%v), want the default size", cfg.Width, cfg.Height, err)
+	}
+}
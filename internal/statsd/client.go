@@ -0,0 +1,237 @@
+// Package statsd pushes bridge metrics to a StatsD collector over UDP. Counters are
+// sampled as running totals and sent as the increase since the previous flush.
+package statsd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults
+const (
+	DefaultPrefix        = "aviationwx_bridge"
+	DefaultFlushInterval = 10 * time.Second
+)
+
+// maxPacket keeps each datagram within a typical Ethernet MTU
+const maxPacket = 1432
+
+// Sample is one reading of every metric. Counters are running totals since
+// startup; gauges are current values.
+type Sample struct {
+	Counters map[string]int64
+	Gauges   map[string]float64
+}
+
+// Logger is the logging interface used by Client
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// Config configures a Client
+type Config struct {
+	Address       string        // Collector host:port
+	Prefix        string        // Prepended to every metric name. Default: aviationwx_bridge
+	FlushInterval time.Duration // Default: 10s
+	Sample        func() Sample // Called once per flush
+	Logger        Logger
+}
+
+// Status describes delivery to the collector for monitoring
+type Status struct {
+	Flushes       int64     `json:"flushes"`
+	Packets       int64     `json:"packets"`
+	Errors        int64     `json:"errors"` // Flushes that failed to resolve or send
+	LastError     string    `json:"last_error,omitempty"`
+	LastErrorTime time.Time `json:"last_error_time,omitempty"`
+}
+
+// Client flushes samples to the collector until Stop
+type Client struct {
+	config Config
+	stop   chan struct{}
+	done   chan struct{}
+
+	// Owned by the flush loop
+	conn    net.Conn         // Dialed lazily; UDP dials only resolve the address
+	prev    map[string]int64 // Counter totals at the last flush
+	failing bool             // Last flush failed; the next success is logged
+
+	mu     sync.Mutex
+	status Status
+}
+
+// New validates cfg and returns a client; call Start to begin flushing
+func New(cfg Config) (*Client, error) {
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("address must be host:port: %w", err)
+	}
+	if cfg.Sample == nil {
+		return nil, fmt.Errorf("sample func is required")
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	cfg.Prefix = strings.TrimSuffix(cfg.Prefix, ".")
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	return &Client{
+		config: cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		prev:   make(map[string]int64),
+	}, nil
+}
+
+// Start flushes every interval in the background
+func (c *Client) Start() {
+	go c.run()
+}
+
+// Stop sends a final flush and waits for the flush loop to exit
+func (c *Client) Stop() {
+	close(c.stop)
+	<-c.done
+}
+
+// Status returns delivery counters
+func (c *Client) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+func (c *Client) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			c.flush()
+			if c.conn != nil {
+				c.conn.Close()
+			}
+			return
+		case <-ticker.C:
+			c.flush()
+		}
+	}
+}
+
+// flush samples the metrics and sends them. An unreachable collector only counts
+// an error: UDP delivery is best effort and nothing is buffered for later.
+func (c *Client) flush() {
+	packets := c.packets(c.lines(c.config.Sample()))
+	err := c.send(packets)
+
+	c.mu.Lock()
+	c.status.Flushes++
+	if err != nil {
+		c.status.Errors++
+		c.status.LastError = err.Error()
+		c.status.LastErrorTime = time.Now()
+	} else {
+		c.status.Packets += int64(len(packets))
+	}
+	c.mu.Unlock()
+
+	if c.config.Logger == nil {
+		c.failing = err != nil
+		return
+	}
+	switch {
+	case err != nil && !c.failing:
+		c.config.Logger.Warn("StatsD collector unreachable - metrics dropped until it recovers",
+			"address", c.config.Address, "error", err)
+	case err == nil && c.failing:
+		c.config.Logger.Info("StatsD collector reachable again", "address", c.config.Address)
+	}
+	c.failing = err != nil
+}
+
+// lines renders s as StatsD lines, sorted so packets are stable. Counters that went
+// backwards (e.g. a restarted worker) restart from their new total.
+func (c *Client) lines(s Sample) []string {
+	lines := make([]string, 0, len(s.Counters)+len(s.Gauges))
+	for name, total := range s.Counters {
+		delta := total - c.prev[name]
+		if delta < 0 {
+			delta = total
+		}
+		c.prev[name] = total
+		if delta > 0 {
+			lines = append(lines, fmt.Sprintf("%s.%s:%d|c", c.config.Prefix, name, delta))
+		}
+	}
+	for name, value := range s.Gauges {
+		line := fmt.Sprintf("%s.%s:%g|g", c.config.Prefix, name, value)
+		if value < 0 {
+			// A signed value adjusts the gauge, so it is first reset to zero
+			line = fmt.Sprintf("%s.%s:0|g\n%s", c.config.Prefix, name, line)
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// packets joins lines into newline-separated datagrams of at most maxPacket bytes
+func (c *Client) packets(lines []string) [][]byte {
+	var packets [][]byte
+	var buf []byte
+	for _, line := range lines {
+		if len(buf) > 0 && len(buf)+1+len(line) > maxPacket {
+			packets = append(packets, buf)
+			buf = nil
+		}
+		if len(buf) > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, line...)
+	}
+	if len(buf) > 0 {
+		packets = append(packets, buf)
+	}
+	return packets
+}
+
+// send writes packets, dialing on first use and again after a failure so a
+// collector whose address changed is found
+func (c *Client) send(packets [][]byte) error {
+	if len(packets) == 0 {
+		return nil
+	}
+	if c.conn == nil {
+		conn, err := net.Dial("udp", c.config.Address)
+		if err != nil {
+			return fmt.Errorf("dial: %w", err)
+		}
+		c.conn = conn
+	}
+	for _, p := range packets {
+		if _, err := c.conn.Write(p); err != nil {
+			c.conn.Close()
+			c.conn = nil
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+	return nil
+}
+
+// Name makes s safe as one segment of a metric name, e.g. a camera ID
+func Name(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, s)
+}
@@ -0,0 +1,96 @@
+package statsd
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listen returns a UDP collector and a func reading its next packet's lines
+func listen(t *testing.T) (string, func() []string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn.LocalAddr().String(), func() []string {
+		buf := make([]byte, 2048)
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return strings.Split(string(buf[:n]), "\n")
+	}
+}
+
+func TestClient_SendsCounterDeltasAndGauges(t *testing.T) {
+	addr, read := listen(t)
+	sample := Sample{
+		Counters: map[string]int64{"captures": 5, "uploads_failed": 0},
+		Gauges:   map[string]float64{"queue.images": 12},
+	}
+	c, err := New(Config{Address: addr, Prefix: "bridge.", Sample: func() Sample { return sample }})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	c.flush()
+	got := strings.Join(read(), " ")
+	if got != "bridge.captures:5|c bridge.queue.images:12|g" {
+		t.Errorf("first flush = %q", got)
+	}
+
+	// Only the increase is sent; unchanged counters are omitted
+	sample.Counters["captures"] = 8
+	sample.Gauges["queue.images"] = 3
+	c.flush()
+	got = strings.Join(read(), " ")
+	if got != "bridge.captures:3|c bridge.queue.images:3|g" {
+		t.Errorf("second flush = %q", got)
+	}
+	if s := c.Status(); s.Flushes != 2 || s.Packets != 2 || s.Errors != 0 {
+		t.Errorf("status = %+v", s)
+	}
+}
+
+func TestClient_UnreachableCollector(t *testing.T) {
+	c, err := New(Config{
+		Address: "statsd.invalid:8125",
+		Sample:  func() Sample { return Sample{Gauges: map[string]float64{"up": 1}} },
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	c.Start()
+	done := make(chan struct{})
+	go func() {
+		c.Stop() // Final flush fails to resolve but must not block or panic
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Stop blocked on an unreachable collector")
+	}
+	if s := c.Status(); s.Errors != 1 || s.LastError == "" {
+		t.Errorf("status = %+v, want one failed flush", s)
+	}
+}
+
+func TestPackets_SplitAtMTU(t *testing.T) {
+	c := &Client{}
+	line := strings.Repeat("x", 600)
+	packets := c.packets([]string{line, line, line})
+	if len(packets) != 2 || len(packets[0]) != 1201 || len(packets[1]) != 600 {
+		t.Errorf("got %d packets", len(packets))
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("kpdx north.cam/1"); got != "kpdx_north_cam_1" {
+		t.Errorf("Name() = %q", got)
+	}
+}
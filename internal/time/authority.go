@@ -13,6 +13,24 @@ const (
 	SourceBridgeClock TimeSource = "bridge_clock"
 )
 
+// Preference selects which clock a camera's observation times come from
+type Preference string
+
+const (
+	PreferBridge                Preference = "bridge"                     // Always the bridge clock
+	PreferCamera                Preference = "camera"                     // Camera EXIF whenever present (trusted, e.g. GPS-synced, clock)
+	PreferCameraWithinTolerance Preference = "camera_if_within_tolerance" // Camera EXIF unless it drifts past camera_warn_drift_seconds (default)
+)
+
+// ValidPreference reports whether p is a known preference ("" selects the default)
+func ValidPreference(p string) bool {
+	switch Preference(p) {
+	case "", PreferBridge, PreferCamera, PreferCameraWithinTolerance:
+		return true
+	}
+	return false
+}
+
 // Confidence indicates how confident we are in the observation time
 type Confidence string
 
@@ -22,6 +40,9 @@ const (
 	ConfidenceLow    Confidence = "low"    // NTP unhealthy or large drift
 )
 
+// WarningNTPUnhealthy is the warning code used when the bridge clock is not synchronized
+const WarningNTPUnhealthy = "ntp_unhealthy"
+
 // ObservationResult contains the determined observation time and metadata
 type ObservationResult struct {
 	Time       time.Time    // Observation time in UTC
@@ -90,6 +111,21 @@ func NewAuthority(ntpHealth *TimeHealth, config AuthorityConfig) (*Authority, er
 // captureStartUTC is when the bridge started the capture request
 // cameraEXIF is the parsed EXIF time from the camera (may be nil)
 func (a *Authority) DetermineObservationTime(captureStartUTC time.Time, cameraEXIF *time.Time) ObservationResult {
+	return a.DetermineObservationTimeWith(PreferCameraWithinTolerance, captureStartUTC, cameraEXIF)
+}
+
+// DetermineObservationTimeWith determines the observation time honoring a camera's
+// clock preference. Without camera EXIF every preference falls back to the bridge clock.
+func (a *Authority) DetermineObservationTimeWith(pref Preference, captureStartUTC time.Time, cameraEXIF *time.Time) ObservationResult {
+	switch pref {
+	case PreferBridge:
+		cameraEXIF = nil
+	case PreferCamera:
+		if cameraEXIF != nil {
+			return a.trustCameraTime(captureStartUTC, *cameraEXIF)
+		}
+	}
+
 	result := ObservationResult{
 		Time: captureStartUTC, // Default to bridge clock
 	}
@@ -111,7 +147,7 @@ func (a *Authority) DetermineObservationTime(captureStartUTC time.Time, cameraEX
 		}
 
 		result.Warning = &TimeWarning{
-			Code:    "ntp_unhealthy",
+			Code:    WarningNTPUnhealthy,
 			Message: "Bridge NTP is not synchronized. Observation times may be inaccurate.",
 			Details: map[string]interface{}{
 				"ntp_offset_ms": offsetMs,
@@ -129,14 +165,7 @@ func (a *Authority) DetermineObservationTime(captureStartUTC time.Time, cameraEX
 		return result
 	}
 
-	// Convert camera EXIF (local time) to UTC
-	// Camera EXIF comes as a naive time (no timezone), interpret it in bridge's timezone
-	cameraLocalTime := time.Date(
-		cameraEXIF.Year(), cameraEXIF.Month(), cameraEXIF.Day(),
-		cameraEXIF.Hour(), cameraEXIF.Minute(), cameraEXIF.Second(),
-		cameraEXIF.Nanosecond(), a.localTZ,
-	)
-	cameraUTC := cameraLocalTime.UTC()
+	cameraUTC := a.cameraUTC(*cameraEXIF)
 
 	// Calculate drift between camera and bridge
 	drift := captureStartUTC.Sub(cameraUTC)
@@ -208,6 +237,51 @@ func (a *Authority) DetermineObservationTime(captureStartUTC time.Time, cameraEX
 	return result
 }
 
+// trustCameraTime uses the camera's EXIF time whatever its drift. Confidence is high
+// only while the bridge clock is healthy enough to cross-check it; drift beyond the
+// reject threshold is still reported.
+func (a *Authority) trustCameraTime(captureStartUTC, cameraEXIF time.Time) ObservationResult {
+	cameraUTC := a.cameraUTC(cameraEXIF)
+	result := ObservationResult{
+		Time:       cameraUTC,
+		Source:     SourceCameraEXIF,
+		Confidence: ConfidenceHigh,
+	}
+	if !a.IsNTPHealthy() {
+		result.Confidence = ConfidenceMedium
+		return result
+	}
+
+	drift := captureStartUTC.Sub(cameraUTC)
+	absDrift := drift
+	if absDrift < 0 {
+		absDrift = -absDrift
+	}
+	if absDrift > time.Duration(a.config.CameraRejectDriftSeconds)*time.Second {
+		result.Warning = &TimeWarning{
+			Code:    "camera_clock_drift",
+			Message: fmt.Sprintf("Camera clock is %.1f minutes %s. Using camera time as configured.", absDrift.Minutes(), driftDirection(drift)),
+			Details: map[string]interface{}{
+				"drift_seconds": drift.Seconds(),
+				"camera_time":   cameraUTC.Format(time.RFC3339),
+				"bridge_time":   captureStartUTC.Format(time.RFC3339),
+				"action":        "using_camera_time",
+			},
+		}
+	}
+	return result
+}
+
+// cameraUTC converts a camera EXIF time to UTC. EXIF times are naive (no timezone),
+// so they are interpreted in the bridge's timezone.
+func (a *Authority) cameraUTC(cameraEXIF time.Time) time.Time {
+	return time.Date(
+		cameraEXIF.Year(), cameraEXIF.Month(), cameraEXIF.Day(),
+		cameraEXIF.Hour(), cameraEXIF.Minute(), cameraEXIF.Second(),
+		cameraEXIF.Nanosecond(), a.localTZ,
+	).UTC()
+}
+
 // GetTimezone returns the configured timezone
 func (a *Authority) GetTimezone() *time.Location {
 	return a.localTZ
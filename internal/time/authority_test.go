@@ -219,6 +219,60 @@ func TestAuthority_DetermineObservationTime_WithUnhealthyNTP(t *testing.T) {
 	}
 }
 
+func TestAuthority_DetermineObservationTimeWith_Preferences(t *testing.T) {
+	config := DefaultAuthorityConfig()
+	config.Timezone = "UTC"
+	authority, _ := NewAuthority(nil, config)
+
+	captureTime := time.Now().UTC()
+	near := captureTime.Add(-2 * time.Second)
+	drifted := captureTime.Add(-2 * time.Minute)
+	far := captureTime.Add(-10 * time.Minute)
+
+	tests := []struct {
+		name       string
+		pref       Preference
+		cameraTime *time.Time
+		wantSource TimeSource
+		wantTime   time.Time
+		wantCode   string
+	}{
+		{"bridge ignores camera", PreferBridge, &near, SourceBridgeClock, captureTime, ""},
+		{"camera within tolerance", PreferCameraWithinTolerance, &near, SourceCameraEXIF, near, ""},
+		{"tolerance rejects drift", PreferCameraWithinTolerance, &drifted, SourceBridgeClock, captureTime, "camera_clock_rejected"},
+		{"camera trusted despite drift", PreferCamera, &far, SourceCameraEXIF, far, "camera_clock_drift"},
+		{"camera falls back without EXIF", PreferCamera, nil, SourceBridgeClock, captureTime, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := authority.DetermineObservationTimeWith(tt.pref, captureTime, tt.cameraTime)
+			if result.Source != tt.wantSource || !result.Time.Equal(tt.wantTime) {
+				t.Errorf("got %v at %v, want %v at %v", result.Source, result.Time, tt.wantSource, tt.wantTime)
+			}
+			code := ""
+			if result.Warning != nil {
+				code = result.Warning.Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("warning = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthority_PreferCamera_UnhealthyNTP(t *testing.T) {
+	timeHealth := NewTimeHealth(Config{Enabled: true, MaxOffsetSeconds: 5})
+	authority, _ := NewAuthority(timeHealth, DefaultAuthorityConfig())
+
+	captureTime := time.Now().UTC()
+	cameraTime := captureTime.Add(-1 * time.Second)
+	result := authority.DetermineObservationTimeWith(PreferCamera, captureTime, &cameraTime)
+
+	if result.Source != SourceCameraEXIF || result.Confidence != ConfidenceMedium {
+		t.Errorf("got %v/%v, want camera time with medium confidence", result.Source, result.Confidence)
+	}
+}
+
 func TestAuthority_TimezoneConversion(t *testing.T) {
 	config := DefaultAuthorityConfig()
 	config.Timezone = "America/Los_Angeles" // PST = UTC-8
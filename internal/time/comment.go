@@ -0,0 +1,74 @@
+package time
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// bridgeCommentPrefix starts every COM segment written by the bridge; it matches
+// the EXIF UserComment marker so both carry the same provenance
+const bridgeCommentPrefix = "AviationWX-Bridge:"
+
+// BridgeComment returns the text of the JPEG comment for a frame: the bridge
+// marker, camera ID and optional operator note (sanitized like the EXIF note)
+func BridgeComment(marker, cameraID, note string) string {
+	comment := marker + " camera=" + cameraID
+	if note = SanitizeExifNote(note); note != "" {
+		comment += " note=" + note
+	}
+	return comment
+}
+
+// InjectComment writes comment as a JPEG COM segment placed after the APPn and
+// existing COM segments, so EXIF APP1 keeps its required position right after SOI
+// (and JFIF APP0). Earlier bridge comments are replaced; other comments are kept.
+// Scan data is copied unchanged.
+func InjectComment(data []byte, comment string) ([]byte, error) {
+	if !strings.HasPrefix(comment, bridgeCommentPrefix) {
+		return nil, fmt.Errorf("comment must start with %q", bridgeCommentPrefix)
+	}
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG")
+	}
+	segLen := 2 + len(comment)
+	if segLen > 0xFFFF {
+		return nil, fmt.Errorf("comment too large")
+	}
+	com := make([]byte, 0, segLen+2)
+	com = append(com, 0xFF, 0xFE, byte(segLen>>8), byte(segLen))
+	com = append(com, comment...)
+
+	out := make([]byte, 0, len(data)+len(com))
+	out = append(out, 0xFF, 0xD8)
+	inserted := false
+	pos := 2
+	for {
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG header at offset %d", pos)
+		}
+		marker := data[pos+1]
+		if marker == 0xDA { // SOS: the rest is scan data
+			break
+		}
+		length := int(data[pos+2])<<8 | int(data[pos+3])
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("truncated JPEG segment at offset %d", pos)
+		}
+		segment := data[pos:end]
+
+		if !inserted && (marker < 0xE0 || marker > 0xEF) && marker != 0xFE {
+			out = append(out, com...)
+			inserted = true
+		}
+		if marker != 0xFE || !bytes.HasPrefix(segment[4:], []byte(bridgeCommentPrefix)) {
+			out = append(out, segment...)
+		}
+		pos = end
+	}
+	if !inserted {
+		out = append(out, com...)
+	}
+	return append(out, data[pos:]...), nil
+}
@@ -0,0 +1,80 @@
+package time
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/jpeg"
+	"testing"
+	"time"
+)
+
+// readSegments returns the markers of the header segments, in order, and the text
+// of each COM segment
+func readSegments(data []byte) (markers []byte, comments []string) {
+	for pos := 2; pos+4 <= len(data) && data[pos+1] != 0xDA; {
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		markers = append(markers, data[pos+1])
+		if data[pos+1] == 0xFE {
+			comments = append(comments, string(data[pos+4:end]))
+		}
+		pos = end
+	}
+	return markers, comments
+}
+
+func TestBridgeComment(t *testing.T) {
+	marker := "AviationWX-Bridge:UTC:v1:bridge_clock:high"
+	if got := BridgeComment(marker, "kspb-north", ""); got != marker+" camera=kspb-north" {
+		t.Errorf("without note = %q", got)
+	}
+	if got := BridgeComment(marker, "kspb-north", "KSPB\nrunway 33"); got != marker+" camera=kspb-north note=KSPB runway 33" {
+		t.Errorf("with note = %q", got)
+	}
+}
+
+func TestInjectComment(t *testing.T) {
+	obs := ObservationResult{
+		Time:       time.Date(2024, 12, 25, 10, 30, 0, 0, time.UTC),
+		Source:     SourceBridgeClock,
+		Confidence: ConfidenceHigh,
+	}
+	stamped := StampBridgeEXIF(encodeTestJPEG(t), obs, FrameMeta{})
+	comment := BridgeComment(stamped.Marker, "kspb-north", "")
+
+	data, err := InjectComment(stamped.Data, comment)
+	if err != nil {
+		t.Fatalf("InjectComment: %v", err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("commented image no longer decodes: %v", err)
+	}
+	markers, comments := readSegments(data)
+	if len(comments) != 1 || comments[0] != comment {
+		t.Fatalf("comments = %q", comments)
+	}
+	// EXIF keeps its place right after SOI; the comment follows it
+	if markers[0] != 0xE1 || markers[1] != 0xFE {
+		t.Errorf("segment order = % X", markers)
+	}
+	if _, segments := readEXIFTags(t, data); segments != 1 {
+		t.Errorf("EXIF segments = %d, want 1", segments)
+	}
+
+	// Re-injecting replaces the bridge comment but keeps other comments
+	other := append([]byte{0xFF, 0xD8, 0xFF, 0xFE, 0x00, 0x07}, "hello"...)
+	other = append(other, data[2:]...)
+	data, err = InjectComment(other, BridgeComment(stamped.Marker, "kspb-south", ""))
+	if err != nil {
+		t.Fatalf("InjectComment: %v", err)
+	}
+	if _, comments = readSegments(data); len(comments) != 2 || comments[0] != "hello" || comments[1] != stamped.Marker+" camera=kspb-south" {
+		t.Errorf("comments after re-inject = %q", comments)
+	}
+
+	if _, err := InjectComment([]byte("not a jpeg"), comment); err == nil {
+		t.Error("expected error for non-JPEG data")
+	}
+	if _, err := InjectComment(stamped.Data, "unrelated"); err == nil {
+		t.Error("expected error for a comment without the bridge prefix")
+	}
+}
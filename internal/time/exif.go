@@ -1,6 +1,11 @@
 package time
 
-import "time"
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
 
 // EXIFStampResult contains the result of EXIF stamping
 type EXIFStampResult struct {
@@ -8,4 +13,201 @@ type EXIFStampResult struct {
 	Stamped        bool      // Whether EXIF was successfully stamped
 	Marker         string    // The marker string that was added
 	ObservationUTC time.Time // The observation time that was stamped
+	Method         string    // StampMethod* that produced Data
+	Attempts       int       // exiftool attempts made
+}
+
+// Stamping methods reported in EXIFStampResult.Method
+const (
+	StampMethodExifTool = "exiftool"
+	StampMethodBuiltin  = "builtin" // exiftool failed; written by StampBridgeEXIF
+	StampMethodNone     = "none"    // Every method failed; image is unstamped
+)
+
+// exifRetryDelay spaces exiftool attempts so a transient temp-file or PATH problem can clear
+var exifRetryDelay = 100 * time.Millisecond
+
+// stampWithTool is the exiftool stamping step, replaceable in tests
+var stampWithTool = StampBridgeEXIFWithMeta
+
+// StampBridgeEXIFWithFallback stamps with exiftool, retrying up to retries extra times,
+// then falls back to the builtin StampBridgeEXIF injector so a transient exiftool
+// failure does not ship an unstamped frame. While exiftool is bypassed after
+// repeated hangs, the builtin injector is used directly (Attempts is 0).
+func StampBridgeEXIFWithFallback(imageData []byte, obs ObservationResult, meta FrameMeta, retries int) EXIFStampResult {
+	attempts := 0
+	if hangs.bypassed(time.Now()) {
+		retries = -1
+	}
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(exifRetryDelay)
+		}
+		attempts++
+		result := stampWithTool(imageData, obs, meta)
+		if result.Stamped {
+			result.Method = StampMethodExifTool
+			result.Attempts = attempts
+			return result
+		}
+	}
+
+	result := StampBridgeEXIF(imageData, obs, meta)
+	result.Attempts = attempts
+	return result
+}
+
+// StampBridgeEXIF writes the bridge EXIF (DateTimeOriginal, OffsetTimeOriginal,
+// UserComment marker, image dimensions and optional ImageDescription and ImageNumber)
+// without exiftool. Any existing
+// EXIF segment is replaced, so camera metadata is lost; it is only used when
+// exiftool is unavailable.
+func StampBridgeEXIF(imageData []byte, obs ObservationResult, meta FrameMeta) EXIFStampResult {
+	opts := bridgeEXIFOptions(obs, meta)
+	opts.PixelWidth, opts.PixelHeight = jpegDimensions(bytes.NewReader(imageData))
+	result := EXIFStampResult{
+		Data:           imageData,
+		ObservationUTC: obs.Time,
+		Marker:         opts.UserComment,
+		Method:         StampMethodNone,
+	}
+
+	stamped, err := injectEXIF(imageData, buildEXIF(opts))
+	if err != nil {
+		return result
+	}
+	result.Data = stamped
+	result.Stamped = true
+	result.Method = StampMethodBuiltin
+	return result
+}
+
+// exifHeader prefixes the TIFF structure in an APP1 segment
+var exifHeader = []byte("Exif\x00\x00")
+
+// injectEXIF inserts an APP1 EXIF segment after SOI and any APP0 (JFIF) segments,
+// dropping existing EXIF APP1 segments. Scan data is copied unchanged.
+func injectEXIF(data, tiff []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG")
+	}
+	segLen := 2 + len(exifHeader) + len(tiff)
+	if segLen > 0xFFFF {
+		return nil, fmt.Errorf("EXIF segment too large")
+	}
+	app1 := make([]byte, 0, segLen+2)
+	app1 = append(app1, 0xFF, 0xE1, byte(segLen>>8), byte(segLen))
+	app1 = append(app1, exifHeader...)
+	app1 = append(app1, tiff...)
+
+	out := make([]byte, 0, len(data)+len(app1))
+	out = append(out, 0xFF, 0xD8)
+	inserted := false
+	pos := 2
+	for {
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG header at offset %d", pos)
+		}
+		marker := data[pos+1]
+		if marker == 0xDA { // SOS: the rest is scan data
+			break
+		}
+		length := int(data[pos+2])<<8 | int(data[pos+3])
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("truncated JPEG segment at offset %d", pos)
+		}
+		segment := data[pos:end]
+
+		if !inserted && marker != 0xE0 {
+			out = append(out, app1...)
+			inserted = true
+		}
+		if marker != 0xE1 || !bytes.HasPrefix(segment[4:], exifHeader) {
+			out = append(out, segment...)
+		}
+		pos = end
+	}
+	if !inserted {
+		out = append(out, app1...)
+	}
+	return append(out, data[pos:]...), nil
+}
+
+// EXIF tags and TIFF field types written by buildEXIF
+const (
+	tagImageDescription   = 0x010E
+	tagExifIFDPointer     = 0x8769
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagImageNumber        = 0x9211
+	tagUserComment        = 0x9286
+	tagPixelXDimension    = 0xA002 // ExifImageWidth
+	tagPixelYDimension    = 0xA003 // ExifImageHeight
+
+	typeASCII     = 2
+	typeLong      = 4
+	typeUndefined = 7
+)
+
+type ifdEntry struct {
+	tag   uint16
+	typ   uint16
+	value []byte // ASCII values include the NUL terminator
+}
+
+// buildEXIF returns a little-endian TIFF structure holding the bridge tags
+func buildEXIF(opts ExifWriteOptions) []byte {
+	ascii := func(s string) []byte { return append([]byte(s), 0) }
+
+	var ifd0 []ifdEntry
+	if opts.ImageDescription != "" {
+		ifd0 = append(ifd0, ifdEntry{tagImageDescription, typeASCII, ascii(opts.ImageDescription)})
+	}
+	ifd0 = append(ifd0, ifdEntry{tagExifIFDPointer, typeLong, make([]byte, 4)})
+
+	// Entries must stay in ascending tag order
+	exif := []ifdEntry{
+		{tagDateTimeOriginal, typeASCII, ascii(opts.DateTimeOriginal)},
+		{tagOffsetTimeOriginal, typeASCII, ascii(opts.OffsetTimeOriginal)},
+	}
+	if opts.ImageNumber > 0 {
+		exif = append(exif, ifdEntry{tagImageNumber, typeLong, binary.LittleEndian.AppendUint32(nil, opts.ImageNumber)})
+	}
+	exif = append(exif, ifdEntry{tagUserComment, typeUndefined, append([]byte("ASCII\x00\x00\x00"), opts.UserComment...)})
+	if opts.PixelWidth > 0 && opts.PixelHeight > 0 {
+		exif = append(exif,
+			ifdEntry{tagPixelXDimension, typeLong, binary.LittleEndian.AppendUint32(nil, uint32(opts.PixelWidth))},
+			ifdEntry{tagPixelYDimension, typeLong, binary.LittleEndian.AppendUint32(nil, uint32(opts.PixelHeight))})
+	}
+
+	ifdSize := func(n int) int { return 2 + 12*n + 4 }
+	exifOffset := 8 + ifdSize(len(ifd0))
+	binary.LittleEndian.PutUint32(ifd0[len(ifd0)-1].value, uint32(exifOffset))
+
+	buf := []byte{'I', 'I', 42, 0, 8, 0, 0, 0}
+	dataOffset := exifOffset + ifdSize(len(exif))
+	var data []byte
+	writeIFD := func(entries []ifdEntry) {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(len(entries)))
+		for _, e := range entries {
+			buf = binary.LittleEndian.AppendUint16(buf, e.tag)
+			buf = binary.LittleEndian.AppendUint16(buf, e.typ)
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(e.value)))
+			if len(e.value) <= 4 {
+				buf = append(buf, e.value...)
+				buf = append(buf, make([]byte, 4-len(e.value))...)
+				continue
+			}
+			buf = binary.LittleEndian.AppendUint32(buf, uint32(dataOffset+len(data)))
+			data = append(data, e.value...)
+			if len(data)%2 != 0 {
+				data = append(data, 0) // Values start on word boundaries
+			}
+		}
+		buf = binary.LittleEndian.AppendUint32(buf, 0) // No next IFD
+	}
+	writeIFD(ifd0)
+	writeIFD(exif)
+	return append(buf, data...)
 }
@@ -0,0 +1,229 @@
+package time
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/jpeg"
+	"strings"
+	"testing"
+	"time"
+)
+
+func encodeTestJPEG(t testing.TB) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 16, 16)), nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// readEXIFTags returns the tag values of every IFD reachable from IFD0 in the
+// first EXIF APP1 segment, and the number of EXIF segments found
+func readEXIFTags(t *testing.T, data []byte) (map[uint16][]byte, int) {
+	t.Helper()
+	tags := map[uint16][]byte{}
+	segments := 0
+	for pos := 2; pos+4 <= len(data) && data[pos+1] != 0xDA; {
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if data[pos+1] == 0xE1 && bytes.HasPrefix(data[pos+4:], exifHeader) {
+			segments++
+			if segments == 1 {
+				tiff := data[pos+4+len(exifHeader) : end]
+				readIFD(tiff, binary.LittleEndian.Uint32(tiff[4:]), tags)
+			}
+		}
+		pos = end
+	}
+	return tags, segments
+}
+
+func readIFD(tiff []byte, offset uint32, tags map[uint16][]byte) {
+	le := binary.LittleEndian
+	count := int(le.Uint16(tiff[offset:]))
+	for i := 0; i < count; i++ {
+		entry := tiff[int(offset)+2+12*i:]
+		tag, n := le.Uint16(entry), le.Uint32(entry[4:])
+		value := entry[8 : 8+min(n, 4)]
+		if n > 4 {
+			start := le.Uint32(entry[8:])
+			value = tiff[start : start+n]
+		}
+		tags[tag] = value
+		if tag == tagExifIFDPointer {
+			readIFD(tiff, le.Uint32(value), tags)
+		}
+	}
+}
+
+func TestStampBridgeEXIF(t *testing.T) {
+	obs := ObservationResult{
+		Time:       time.Date(2024, 12, 25, 10, 30, 0, 0, time.UTC),
+		Source:     SourceBridgeClock,
+		Confidence: ConfidenceHigh,
+	}
+	original := encodeTestJPEG(t)
+
+	result := StampBridgeEXIF(original, obs, FrameMeta{Note: "KSPB north"})
+	if !result.Stamped || result.Method != StampMethodBuiltin {
+		t.Fatalf("expected builtin stamp, got stamped=%v method=%q", result.Stamped, result.Method)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(result.Data)); err != nil {
+		t.Fatalf("stamped image no longer decodes: %v", err)
+	}
+
+	tags, segments := readEXIFTags(t, result.Data)
+	if segments != 1 {
+		t.Fatalf("EXIF segments = %d, want 1", segments)
+	}
+	want := map[uint16]string{
+		tagDateTimeOriginal:   "2024:12:25 10:30:00\x00",
+		tagOffsetTimeOriginal: "+00:00\x00",
+		tagImageDescription:   "KSPB north\x00",
+		tagUserComment:        "ASCII\x00\x00\x00" + result.Marker,
+		tagPixelXDimension:    "\x10\x00\x00\x00",
+		tagPixelYDimension:    "\x10\x00\x00\x00",
+	}
+	for tag, value := range want {
+		if got := string(tags[tag]); got != value {
+			t.Errorf("tag 0x%04X = %q, want %q", tag, got, value)
+		}
+	}
+	if !strings.HasPrefix(result.Marker, "AviationWX-Bridge:UTC:v1:") {
+		t.Errorf("unexpected marker %q", result.Marker)
+	}
+
+	// Restamping replaces the previous segment rather than adding another
+	restamped := StampBridgeEXIF(result.Data, obs, FrameMeta{})
+	tags, segments = readEXIFTags(t, restamped.Data)
+	if segments != 1 {
+		t.Errorf("EXIF segments after restamp = %d, want 1", segments)
+	}
+	if _, ok := tags[tagImageDescription]; ok {
+		t.Error("ImageDescription should be omitted without a note")
+	}
+}
+
+func TestStampBridgeEXIF_Sequence(t *testing.T) {
+	obs := ObservationResult{Time: time.Now().UTC(), Source: SourceBridgeClock, Confidence: ConfidenceHigh}
+	result := StampBridgeEXIF(encodeTestJPEG(t), obs, FrameMeta{Sequence: 70000})
+	if !result.Stamped {
+		t.Fatal("stamp failed")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(result.Data)); err != nil {
+		t.Fatalf("stamped image no longer decodes: %v", err)
+	}
+	tags, _ := readEXIFTags(t, result.Data)
+	if got := tags[tagImageNumber]; len(got) != 4 || binary.LittleEndian.Uint32(got) != 70000 {
+		t.Errorf("ImageNumber = %v, want 70000", got)
+	}
+	if got := string(tags[tagUserComment]); got != "ASCII\x00\x00\x00"+result.Marker {
+		t.Errorf("UserComment = %q", got)
+	}
+
+	// Without a sequence the tag is omitted
+	tags, _ = readEXIFTags(t, StampBridgeEXIF(encodeTestJPEG(t), obs, FrameMeta{}).Data)
+	if _, ok := tags[tagImageNumber]; ok {
+		t.Error("ImageNumber written without a sequence")
+	}
+}
+
+func TestStampBridgeEXIF_DimensionsMatchFinalImage(t *testing.T) {
+	// A resized frame still carrying the camera's full-resolution EXIF
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 64, 48)), nil); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	stale, err := injectEXIF(buf.Bytes(), buildEXIF(ExifWriteOptions{
+		DateTimeOriginal: "2020:01:01 00:00:00",
+		PixelWidth:       4000,
+		PixelHeight:      3000,
+	}))
+	if err != nil {
+		t.Fatalf("inject: %v", err)
+	}
+
+	obs := ObservationResult{Time: time.Date(2024, 12, 25, 10, 30, 0, 0, time.UTC), Source: SourceBridgeClock, Confidence: ConfidenceHigh}
+	result := StampBridgeEXIF(stale, obs, FrameMeta{})
+	tags, _ := readEXIFTags(t, result.Data)
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(result.Data))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	width := binary.LittleEndian.Uint32(tags[tagPixelXDimension])
+	height := binary.LittleEndian.Uint32(tags[tagPixelYDimension])
+	if int(width) != cfg.Width || int(height) != cfg.Height {
+		t.Errorf("EXIF dimensions %dx%d, image is %dx%d", width, height, cfg.Width, cfg.Height)
+	}
+	if got := string(tags[tagDateTimeOriginal]); got != "2024:12:25 10:30:00\x00" {
+		t.Errorf("DateTimeOriginal = %q, want the observation time", got)
+	}
+}
+
+func TestStampBridgeEXIF_UndecodableFrameOmitsDimensions(t *testing.T) {
+	// SOI followed directly by SOS: stampable, but there is no frame header to size
+	data := []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02, 0x00, 0xFF, 0xD9}
+	result := StampBridgeEXIF(data, ObservationResult{Time: time.Now().UTC()}, FrameMeta{})
+	if !result.Stamped {
+		t.Fatal("expected stamped result")
+	}
+	tags, _ := readEXIFTags(t, result.Data)
+	if _, ok := tags[tagPixelXDimension]; ok {
+		t.Error("dimensions should be omitted when the frame size is unknown")
+	}
+}
+
+func TestStampBridgeEXIF_NotJPEG(t *testing.T) {
+	data := []byte("not a jpeg")
+	result := StampBridgeEXIF(data, ObservationResult{Time: time.Now().UTC()}, FrameMeta{})
+	if result.Stamped || result.Method != StampMethodNone {
+		t.Errorf("expected unstamped result, got stamped=%v method=%q", result.Stamped, result.Method)
+	}
+	if !bytes.Equal(result.Data, data) {
+		t.Error("original data should be returned unchanged")
+	}
+}
+
+func TestStampBridgeEXIFWithFallback(t *testing.T) {
+	origTool, origDelay := stampWithTool, exifRetryDelay
+	t.Cleanup(func() { stampWithTool, exifRetryDelay = origTool, origDelay })
+	exifRetryDelay = 0
+
+	obs := ObservationResult{Time: time.Now().UTC(), Source: SourceBridgeClock, Confidence: ConfidenceHigh}
+	jpegData := encodeTestJPEG(t)
+
+	tests := []struct {
+		name         string
+		toolFailures int // Attempts that fail before exiftool succeeds
+		retries      int
+		wantMethod   string
+		wantAttempts int
+	}{
+		{"first attempt", 0, 1, StampMethodExifTool, 1},
+		{"retry succeeds", 1, 1, StampMethodExifTool, 2},
+		{"falls back", 5, 1, StampMethodBuiltin, 2},
+		{"no retries", 5, 0, StampMethodBuiltin, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			stampWithTool = func(data []byte, obs ObservationResult, meta FrameMeta) EXIFStampResult {
+				calls++
+				if calls <= tt.toolFailures {
+					return EXIFStampResult{Data: data}
+				}
+				return EXIFStampResult{Data: data, Stamped: true}
+			}
+
+			result := StampBridgeEXIFWithFallback(jpegData, obs, FrameMeta{}, tt.retries)
+			if !result.Stamped {
+				t.Fatal("expected stamped result")
+			}
+			if result.Method != tt.wantMethod || result.Attempts != tt.wantAttempts {
+				t.Errorf("method=%q attempts=%d, want %q/%d", result.Method, result.Attempts, tt.wantMethod, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -1,15 +1,19 @@
 package time
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"image/jpeg"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
+	"unicode"
 )
 
 // ExifToolHelper wraps exiftool CLI for EXIF operations
@@ -50,6 +54,13 @@ type ExifWriteOptions struct {
 	DateTimeOriginal   string // Format: "2024:12:25 10:30:00"
 	OffsetTimeOriginal string // Format: "+00:00" for UTC
 	UserComment        string // Bridge marker
+	ImageDescription   string // Optional operator note (kept out of the marker)
+	ImageNumber        uint32 // Optional frame sequence number; 0 = not written
+
+	// Pixel dimensions of the image being stamped, written as ExifImageWidth/Height so
+	// they match the uploaded image rather than the camera's original. 0 = not written
+	PixelWidth  int
+	PixelHeight int
 }
 
 // NewExifToolHelper creates a new exiftool helper
@@ -85,15 +96,16 @@ func (h *ExifToolHelper) SetNice(enabled bool, level int) {
 	}
 }
 
-// createCommand creates an exec.Cmd for exiftool, optionally wrapped with nice
+// createCommand creates an exec.Cmd for exiftool, optionally wrapped with nice.
+// A run outliving ctx is killed with its process group (see newExifCommand).
 func (h *ExifToolHelper) createCommand(ctx context.Context, args ...string) *exec.Cmd {
 	if h.useNice {
 		// Prepend nice command
 		niceArgs := []string{"-n", fmt.Sprintf("%d", h.niceLevel), h.exiftoolPath}
 		niceArgs = append(niceArgs, args...)
-		return exec.CommandContext(ctx, "nice", niceArgs...)
+		return newExifCommand(ctx, "nice", niceArgs...)
 	}
-	return exec.CommandContext(ctx, h.exiftoolPath, args...)
+	return newExifCommand(ctx, h.exiftoolPath, args...)
 }
 
 // ReadEXIF reads EXIF data from an image file
@@ -113,6 +125,9 @@ func (h *ExifToolHelper) ReadEXIF(imagePath string) (*ExifReadResult, error) {
 	)
 
 	output, err := cmd.Output()
+	if ctx.Err() == nil {
+		hangs.finished()
+	}
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("exiftool read timeout after %v", h.timeout)
@@ -169,25 +184,17 @@ func (h *ExifToolHelper) WriteEXIF(imagePath string, opts ExifWriteOptions) erro
 	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
 	defer cancel()
 
-	args := []string{
+	args := append([]string{
 		"-overwrite_original", // Don't create backup files
-	}
-
-	if opts.DateTimeOriginal != "" {
-		args = append(args, fmt.Sprintf("-DateTimeOriginal=%s", opts.DateTimeOriginal))
-	}
-	if opts.OffsetTimeOriginal != "" {
-		args = append(args, fmt.Sprintf("-OffsetTimeOriginal=%s", opts.OffsetTimeOriginal))
-	}
-	if opts.UserComment != "" {
-		args = append(args, fmt.Sprintf("-UserComment=%s", opts.UserComment))
-	}
-
+	}, exifWriteArgs(opts)...)
 	args = append(args, imagePath)
 
 	// Wrapped with nice on Linux to run at lower priority
 	cmd := h.createCommand(ctx, args...)
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() == nil {
+		hangs.finished()
+	}
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("exiftool write timeout after %v", h.timeout)
@@ -198,9 +205,40 @@ func (h *ExifToolHelper) WriteEXIF(imagePath string, opts ExifWriteOptions) erro
 	return nil
 }
 
-// WriteEXIFToData writes EXIF to image data and returns the modified data
-// This writes to a temp file and reads it back
+// exifWriteArgs returns the tag assignments for opts
+func exifWriteArgs(opts ExifWriteOptions) []string {
+	var args []string
+	if opts.DateTimeOriginal != "" {
+		args = append(args, fmt.Sprintf("-DateTimeOriginal=%s", opts.DateTimeOriginal))
+	}
+	if opts.OffsetTimeOriginal != "" {
+		args = append(args, fmt.Sprintf("-OffsetTimeOriginal=%s", opts.OffsetTimeOriginal))
+	}
+	if opts.UserComment != "" {
+		args = append(args, fmt.Sprintf("-UserComment=%s", opts.UserComment))
+	}
+	if opts.ImageDescription != "" {
+		args = append(args, fmt.Sprintf("-ImageDescription=%s", opts.ImageDescription))
+	}
+	if opts.ImageNumber > 0 {
+		args = append(args, fmt.Sprintf("-ExifIFD:ImageNumber=%d", opts.ImageNumber))
+	}
+	if opts.PixelWidth > 0 && opts.PixelHeight > 0 {
+		args = append(args,
+			fmt.Sprintf("-ExifIFD:ExifImageWidth=%d", opts.PixelWidth),
+			fmt.Sprintf("-ExifIFD:ExifImageHeight=%d", opts.PixelHeight))
+	}
+	return args
+}
+
+// WriteEXIFToData writes EXIF to image data and returns the modified data.
+// In stay-open mode the persistent exiftool process is used; otherwise, or if it
+// fails, this writes to a temp file and reads it back.
 func (h *ExifToolHelper) WriteEXIFToData(imageData []byte, opts ExifWriteOptions) ([]byte, error) {
+	if data, ok, err := stayOpen.write(h, imageData, opts); ok {
+		return data, err
+	}
+
 	// Create temp file
 	tmpFile, err := os.CreateTemp("", "aviationwx-*.jpg")
 	if err != nil {
@@ -308,7 +346,7 @@ func (h *ExifToolHelper) IsAvailable() bool {
 	defer cancel()
 
 	// Don't use nice for version check - it's quick
-	cmd := exec.CommandContext(ctx, h.exiftoolPath, "-ver")
+	cmd := newExifCommand(ctx, h.exiftoolPath, "-ver")
 	return cmd.Run() == nil
 }
 
@@ -318,7 +356,7 @@ func (h *ExifToolHelper) GetVersion() (string, error) {
 	defer cancel()
 
 	// Don't use nice for version check - it's quick
-	cmd := exec.CommandContext(ctx, h.exiftoolPath, "-ver")
+	cmd := newExifCommand(ctx, h.exiftoolPath, "-ver")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -362,8 +400,15 @@ func DefaultExifToolHelper() (*ExifToolHelper, error) {
 // This is the preferred method for production use as it ensures compatibility
 // with the aviationwx.org server which also uses exiftool.
 func StampBridgeEXIFWithTool(imageData []byte, obs ObservationResult) EXIFStampResult {
-	helper, err := DefaultExifToolHelper()
-	if err != nil {
+	return StampBridgeEXIFWithMeta(imageData, obs, FrameMeta{})
+}
+
+// StampBridgeEXIFWithMeta is StampBridgeEXIFWithTool plus the frame's note and
+// sequence number, written to their own tags so the UserComment marker format is
+// unaffected
+func StampBridgeEXIFWithMeta(imageData []byte, obs ObservationResult, meta FrameMeta) EXIFStampResult {
+	helper := CurrentExifTool()
+	if helper == nil {
 		return EXIFStampResult{
 			Data:           imageData,
 			Stamped:        false,
@@ -371,22 +416,9 @@ func StampBridgeEXIFWithTool(imageData []byte, obs ObservationResult) EXIFStampR
 		}
 	}
 
-	// Format timestamp for EXIF
-	dateTimeOriginal := obs.Time.Format("2006:01:02 15:04:05")
-
-	// Build user comment marker
-	marker := fmt.Sprintf("AviationWX-Bridge:UTC:v1:%s:%s",
-		obs.Source, obs.Confidence)
-
-	if obs.Warning != nil {
-		marker += fmt.Sprintf(":warn:%s", obs.Warning.Code)
-	}
-
-	opts := ExifWriteOptions{
-		DateTimeOriginal:   dateTimeOriginal,
-		OffsetTimeOriginal: "+00:00",
-		UserComment:        marker,
-	}
+	opts := bridgeEXIFOptions(obs, meta)
+	opts.PixelWidth, opts.PixelHeight = jpegDimensions(bytes.NewReader(imageData))
+	marker := opts.UserComment
 
 	modifiedData, err := helper.WriteEXIFToData(imageData, opts)
 	if err != nil {
@@ -406,6 +438,82 @@ func StampBridgeEXIFWithTool(imageData []byte, obs ObservationResult) EXIFStampR
 	}
 }
 
+// StampBridgeEXIFFileWithTool stamps bridge EXIF into an image file in place.
+// Used for captures streamed to disk, where loading the image into memory is avoided.
+func StampBridgeEXIFFileWithTool(imagePath string, obs ObservationResult, meta FrameMeta) (string, error) {
+	helper := CurrentExifTool()
+	if helper == nil {
+		return "", fmt.Errorf("exiftool not found")
+	}
+
+	opts := bridgeEXIFOptions(obs, meta)
+	if f, err := os.Open(imagePath); err == nil {
+		opts.PixelWidth, opts.PixelHeight = jpegDimensions(f)
+		f.Close()
+	}
+	if err := helper.WriteEXIF(imagePath, opts); err != nil {
+		return "", err
+	}
+	return opts.UserComment, nil
+}
+
+// FrameMeta is optional per-frame metadata stamped alongside the bridge marker
+type FrameMeta struct {
+	Note     string // Operator note for ImageDescription; "" = none
+	Sequence uint32 // Per-camera frame sequence number for ImageNumber; 0 = none
+}
+
+// bridgeEXIFOptions builds the UTC timestamp and bridge marker written to every image
+func bridgeEXIFOptions(obs ObservationResult, meta FrameMeta) ExifWriteOptions {
+	// Build user comment marker
+	marker := fmt.Sprintf("%s%s:%s", bridgeMarkerPrefix,
+		obs.Source, obs.Confidence)
+
+	if obs.Warning != nil {
+		marker += fmt.Sprintf(":warn:%s", obs.Warning.Code)
+	}
+
+	return ExifWriteOptions{
+		DateTimeOriginal:   obs.Time.Format("2006:01:02 15:04:05"),
+		OffsetTimeOriginal: "+00:00",
+		UserComment:        marker,
+		ImageDescription:   SanitizeExifNote(meta.Note),
+		ImageNumber:        meta.Sequence,
+	}
+}
+
+// jpegDimensions returns the pixel size from a JPEG's frame header, or 0, 0 if it
+// cannot be read. Only the header is parsed, not the scan data.
+func jpegDimensions(r io.Reader) (width, height int) {
+	cfg, err := jpeg.DecodeConfig(r)
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// maxExifNoteLen caps operator notes; EXIF ASCII tags have no hard limit but
+// the note is meant for short station identifiers
+const maxExifNoteLen = 200
+
+// SanitizeExifNote makes an operator note safe to embed: control characters
+// (including newlines) become spaces, runs of whitespace collapse, and the
+// result is trimmed to maxExifNoteLen runes
+func SanitizeExifNote(note string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, note)
+	cleaned = strings.Join(strings.Fields(cleaned), " ")
+
+	if runes := []rune(cleaned); len(runes) > maxExifNoteLen {
+		cleaned = strings.TrimSpace(string(runes[:maxExifNoteLen]))
+	}
+	return cleaned
+}
+
 // GetExifToolPath returns the resolved path to exiftool binary
 func GetExifToolPath() (string, error) {
 	// Check environment variable first
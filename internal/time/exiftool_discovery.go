@@ -0,0 +1,100 @@
+package time
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultExifToolRecheck is how often discovery is retried while exiftool is missing
+const DefaultExifToolRecheck = 5 * time.Minute
+
+// EXIF methods reported in ExifToolDiscoveryStats.Method
+const (
+	ExifMethodExifTool = "exiftool"
+	ExifMethodBuiltin  = "builtin" // exiftool not found: builtin writer, no camera EXIF reads
+)
+
+// ExifToolDiscoveryStats reports whether exiftool was found and when it was looked for
+type ExifToolDiscoveryStats struct {
+	Method    string    `json:"exif_method"`
+	Path      string    `json:"path,omitempty"`
+	Checks    int64     `json:"checks"` // Discovery attempts, including the first
+	CheckedAt time.Time `json:"checked_at"`
+	FoundAt   time.Time `json:"found_at,omitempty"` // When exiftool was found after startup
+}
+
+// exifToolDiscovery caches the exiftool helper shared by stamping and camera EXIF
+// reads. Once found it is kept; while missing, discovery is retried at most once per
+// interval, so installing exiftool takes effect without a restart.
+type exifToolDiscovery struct {
+	mu       sync.Mutex
+	interval time.Duration
+	helper   *ExifToolHelper
+	stats    ExifToolDiscoveryStats
+}
+
+var discovery = &exifToolDiscovery{interval: DefaultExifToolRecheck}
+
+// findExifTool locates exiftool, replaceable in tests
+var findExifTool = DefaultExifToolHelper
+
+// SetExifToolRecheckInterval sets how often a missing exiftool is looked for again
+// (0 = default)
+func SetExifToolRecheckInterval(d time.Duration) {
+	if d <= 0 {
+		d = DefaultExifToolRecheck
+	}
+	discovery.mu.Lock()
+	defer discovery.mu.Unlock()
+	discovery.interval = d
+}
+
+// CurrentExifTool returns the exiftool helper, or nil while exiftool is missing.
+// The first call looks for exiftool; later calls look again once the recheck
+// interval has passed since the last attempt.
+func CurrentExifTool() *ExifToolHelper {
+	discovery.mu.Lock()
+	defer discovery.mu.Unlock()
+	now := time.Now()
+	if discovery.helper == nil && (discovery.stats.Checks == 0 || now.Sub(discovery.stats.CheckedAt) >= discovery.interval) {
+		discovery.checkLocked(now)
+	}
+	return discovery.helper
+}
+
+// RediscoverExifTool looks for exiftool now if it is missing, e.g. right after
+// installing it, and returns the result
+func RediscoverExifTool() ExifToolDiscoveryStats {
+	discovery.mu.Lock()
+	defer discovery.mu.Unlock()
+	if discovery.helper == nil {
+		discovery.checkLocked(time.Now())
+	}
+	return discovery.stats
+}
+
+// ExifToolDiscoveryStatus returns the discovery state without looking for exiftool
+func ExifToolDiscoveryStatus() ExifToolDiscoveryStats {
+	discovery.mu.Lock()
+	defer discovery.mu.Unlock()
+	return discovery.stats
+}
+
+// checkLocked runs discovery (caller must hold d.mu)
+func (d *exifToolDiscovery) checkLocked(now time.Time) {
+	first := d.stats.Checks == 0
+	d.stats.Checks++
+	d.stats.CheckedAt = now
+	d.stats.Method = ExifMethodBuiltin
+
+	helper, err := findExifTool()
+	if err != nil {
+		return
+	}
+	d.helper = helper
+	d.stats.Method = ExifMethodExifTool
+	d.stats.Path = helper.exiftoolPath
+	if !first {
+		d.stats.FoundAt = now
+	}
+}
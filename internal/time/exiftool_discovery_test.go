@@ -0,0 +1,75 @@
+package time
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeDiscovery replaces exiftool discovery for a test; installed controls whether
+// the next lookup finds exiftool, and the returned counter counts lookups
+func fakeDiscovery(t *testing.T, interval time.Duration, installed *bool) *int {
+	t.Helper()
+	origDiscovery, origFind := discovery, findExifTool
+	discovery = &exifToolDiscovery{interval: interval}
+	lookups := 0
+	findExifTool = func() (*ExifToolHelper, error) {
+		lookups++
+		if !*installed {
+			return nil, errors.New("exiftool not found")
+		}
+		return &ExifToolHelper{exiftoolPath: "/usr/bin/exiftool"}, nil
+	}
+	t.Cleanup(func() { discovery, findExifTool = origDiscovery, origFind })
+	return &lookups
+}
+
+func TestCurrentExifTool_RechecksWhileMissing(t *testing.T) {
+	installed := false
+	lookups := fakeDiscovery(t, time.Hour, &installed)
+
+	if CurrentExifTool() != nil {
+		t.Fatal("CurrentExifTool() found exiftool before it was installed")
+	}
+	if s := ExifToolDiscoveryStatus(); s.Method != ExifMethodBuiltin || s.Checks != 1 {
+		t.Errorf("status = %+v, want builtin after 1 check", s)
+	}
+
+	// Installed, but the recheck interval has not passed
+	installed = true
+	if CurrentExifTool() != nil || *lookups != 1 {
+		t.Errorf("looked again before the interval (lookups = %d)", *lookups)
+	}
+
+	discovery.mu.Lock()
+	discovery.stats.CheckedAt = time.Now().Add(-2 * time.Hour)
+	discovery.mu.Unlock()
+	if CurrentExifTool() == nil {
+		t.Fatal("CurrentExifTool() did not find exiftool after the interval")
+	}
+	s := ExifToolDiscoveryStatus()
+	if s.Method != ExifMethodExifTool || s.Path != "/usr/bin/exiftool" || s.FoundAt.IsZero() {
+		t.Errorf("status = %+v, want exiftool found after startup", s)
+	}
+
+	// Once found, it is not looked for again
+	CurrentExifTool()
+	RediscoverExifTool()
+	if *lookups != 2 {
+		t.Errorf("lookups = %d after exiftool was found, want 2", *lookups)
+	}
+}
+
+func TestRediscoverExifTool(t *testing.T) {
+	installed := false
+	fakeDiscovery(t, time.Hour, &installed)
+	CurrentExifTool()
+
+	installed = true
+	if s := RediscoverExifTool(); s.Method != ExifMethodExifTool || s.Checks != 2 {
+		t.Errorf("RediscoverExifTool() = %+v, want exiftool on the second check", s)
+	}
+	if CurrentExifTool() == nil {
+		t.Error("CurrentExifTool() = nil after rediscovery found exiftool")
+	}
+}
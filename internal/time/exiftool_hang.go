@@ -0,0 +1,101 @@
+package time
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// exiftoolWaitDelay bounds how long reaping a killed exiftool waits for its output
+// pipes, in case something it started still holds them
+const exiftoolWaitDelay = 2 * time.Second
+
+// Defaults for switching to the builtin writer while exiftool keeps hanging
+const (
+	DefaultExifToolHangLimit    = 3
+	DefaultExifToolHangCooldown = 5 * time.Minute
+)
+
+// ExifToolStats counts exiftool runs killed after hanging past their timeout
+type ExifToolStats struct {
+	Killed           int64     `json:"killed"`
+	ConsecutiveKills int       `json:"consecutive_kills"`        // Reset by a run that finishes
+	Bypasses         int64     `json:"bypasses"`                 // Times stamping switched to the builtin writer
+	BypassedUntil    time.Time `json:"bypassed_until,omitempty"` // Builtin writer in use until then
+}
+
+// exifToolHangs tracks killed exiftool runs for every helper. After limit kills in
+// a row, stamping skips exiftool for cooldown rather than blocking each capture for
+// a full timeout.
+type exifToolHangs struct {
+	mu       sync.Mutex
+	limit    int
+	cooldown time.Duration
+	stats    ExifToolStats
+}
+
+var hangs = &exifToolHangs{limit: DefaultExifToolHangLimit, cooldown: DefaultExifToolHangCooldown}
+
+// SetExifToolHangPolicy sets how many consecutive killed runs switch stamping to
+// the builtin writer, and for how long. 0 uses the defaults.
+func SetExifToolHangPolicy(limit int, cooldown time.Duration) {
+	if limit <= 0 {
+		limit = DefaultExifToolHangLimit
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultExifToolHangCooldown
+	}
+	hangs.mu.Lock()
+	defer hangs.mu.Unlock()
+	hangs.limit, hangs.cooldown = limit, cooldown
+}
+
+// ExifToolHangStats returns killed-run counters and whether exiftool is bypassed
+func ExifToolHangStats() ExifToolStats {
+	hangs.mu.Lock()
+	defer hangs.mu.Unlock()
+	return hangs.stats
+}
+
+// killed records a run killed on timeout, starting the bypass at the limit
+func (h *exifToolHangs) killed(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.Killed++
+	h.stats.ConsecutiveKills++
+	if h.stats.ConsecutiveKills >= h.limit {
+		h.stats.ConsecutiveKills = 0
+		h.stats.Bypasses++
+		h.stats.BypassedUntil = now.Add(h.cooldown)
+	}
+}
+
+// finished records a run that ended on its own, whatever its exit status
+func (h *exifToolHangs) finished() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stats.ConsecutiveKills = 0
+}
+
+// bypassed reports whether stamping should skip exiftool at now
+func (h *exifToolHangs) bypassed(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return now.Before(h.stats.BypassedUntil)
+}
+
+// newExifCommand starts name in its own process group. When ctx ends, the whole
+// group is killed rather than only the direct child (nice, or exiftool's perl), and
+// Wait reaps it without blocking on pipes held by anything left behind.
+func newExifCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		hangs.killed(time.Now())
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = exiftoolWaitDelay
+	return cmd
+}
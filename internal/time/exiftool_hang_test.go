@@ -0,0 +1,78 @@
+package time
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// resetHangs restores the package hang tracker after a test
+func resetHangs(t *testing.T) {
+	t.Helper()
+	orig := hangs
+	hangs = &exifToolHangs{limit: DefaultExifToolHangLimit, cooldown: DefaultExifToolHangCooldown}
+	t.Cleanup(func() { hangs = orig })
+}
+
+func TestNewExifCommand_KillsProcessGroupOnTimeout(t *testing.T) {
+	resetHangs(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The background sleep holds stdout open; killing only sh would leave Output waiting
+	start := time.Now()
+	cmd := newExifCommand(ctx, "sh", "-c", "sleep 30 & sleep 30")
+	if _, err := cmd.Output(); err == nil {
+		t.Fatal("hung command succeeded")
+	}
+	if elapsed := time.Since(start); elapsed >= exiftoolWaitDelay {
+		t.Errorf("killed command took %v, the group should die before the wait delay", elapsed)
+	}
+	if cmd.ProcessState == nil {
+		t.Error("killed command was not reaped")
+	}
+	if got := ExifToolHangStats().Killed; got != 1 {
+		t.Errorf("Killed = %d, want 1", got)
+	}
+
+	// A command that finishes is not counted
+	if err := newExifCommand(context.Background(), "true").Run(); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if got := ExifToolHangStats().Killed; got != 1 {
+		t.Errorf("Killed after a clean run = %d, want 1", got)
+	}
+}
+
+func TestExifToolHangs_BypassAfterRepeatedKills(t *testing.T) {
+	resetHangs(t)
+	SetExifToolHangPolicy(2, time.Minute)
+	now := time.Now()
+
+	hangs.killed(now)
+	hangs.finished() // A clean run in between restarts the count
+	hangs.killed(now)
+	if hangs.bypassed(now) {
+		t.Fatal("bypassed before the limit was reached in a row")
+	}
+	hangs.killed(now)
+	if !hangs.bypassed(now) || hangs.bypassed(now.Add(time.Minute)) {
+		t.Error("want exiftool bypassed for exactly the cooldown")
+	}
+	if s := ExifToolHangStats(); s.Killed != 3 || s.Bypasses != 1 || s.ConsecutiveKills != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	// While bypassed, stamping goes straight to the builtin writer
+	origTool := stampWithTool
+	t.Cleanup(func() { stampWithTool = origTool })
+	stampWithTool = func(data []byte, obs ObservationResult, meta FrameMeta) EXIFStampResult {
+		t.Error("exiftool used while bypassed")
+		return EXIFStampResult{Data: data}
+	}
+	obs := ObservationResult{Time: time.Now().UTC(), Source: SourceBridgeClock, Confidence: ConfidenceHigh}
+	result := StampBridgeEXIFWithFallback(encodeTestJPEG(t), obs, FrameMeta{}, 3)
+	if !result.Stamped || result.Method != StampMethodBuiltin || result.Attempts != 0 {
+		t.Errorf("result = stamped %v, method %q, attempts %d", result.Stamped, result.Method, result.Attempts)
+	}
+}
@@ -0,0 +1,296 @@
+package time
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultExifToolIdleTimeout is how long an idle stay-open exiftool keeps running
+const DefaultExifToolIdleTimeout = 5 * time.Minute
+
+// ExifToolStayOpenStats describes the persistent exiftool process
+type ExifToolStayOpenStats struct {
+	Enabled   bool   `json:"enabled"`
+	Running   bool   `json:"running"`
+	Starts    int64  `json:"starts"`    // Processes started, including restarts after a crash or idle stop
+	Frames    int64  `json:"frames"`    // Frames stamped through the persistent process
+	Fallbacks int64  `json:"fallbacks"` // Frames stamped by a one-shot exiftool run instead
+	LastError string `json:"last_error,omitempty"`
+}
+
+// errStayOpenTimeout is a stay-open run killed at the timeout. It is not retried
+// through a one-shot run, which would likely wait out a second timeout.
+var errStayOpenTimeout = errors.New("exiftool stay-open timeout")
+
+// exifToolServer is a long-lived `exiftool -stay_open True -@ -` process. Arguments
+// go in on stdin and the stamped image comes back on stdout (-o -), followed by a
+// numbered {readyN} line. exiftool cannot read successive images from a pipe, so
+// each frame is written to one scratch file that is reused for the process's
+// lifetime. Commands run one at a time.
+type exifToolServer struct {
+	mu      sync.Mutex
+	enabled bool
+	idle    time.Duration
+	stats   ExifToolStayOpenStats
+
+	// Running process; cmd is nil when stopped
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	stdout  *bufio.Reader
+	stderr  *lockedBuffer
+	exited  chan struct{}
+	scratch string // Scratch image path, in a private directory
+	seq     int
+	timer   *time.Timer // Idle stop
+}
+
+var stayOpen = &exifToolServer{idle: DefaultExifToolIdleTimeout}
+
+// SetExifToolStayOpen enables or disables stamping through a persistent exiftool
+// process, stopped after idle without frames (0 = default). Disabling stops it.
+func SetExifToolStayOpen(enabled bool, idle time.Duration) {
+	if idle <= 0 {
+		idle = DefaultExifToolIdleTimeout
+	}
+	stayOpen.mu.Lock()
+	defer stayOpen.mu.Unlock()
+	stayOpen.enabled, stayOpen.idle = enabled, idle
+	stayOpen.stats.Enabled = enabled
+	if !enabled {
+		stayOpen.stopLocked()
+	}
+}
+
+// StopExifToolStayOpen stops the persistent exiftool process, if running. A later
+// frame starts it again while stay-open mode is enabled.
+func StopExifToolStayOpen() {
+	stayOpen.mu.Lock()
+	defer stayOpen.mu.Unlock()
+	stayOpen.stopLocked()
+}
+
+// ExifToolStayOpenStatus returns stay-open counters and whether the process is running
+func ExifToolStayOpenStatus() ExifToolStayOpenStats {
+	stayOpen.mu.Lock()
+	defer stayOpen.mu.Unlock()
+	stats := stayOpen.stats
+	stats.Running = stayOpen.cmd != nil
+	return stats
+}
+
+// write stamps data through the persistent process. ok is false when stay-open mode
+// is disabled or failed in a way a one-shot run may recover from.
+func (s *exifToolServer) write(h *ExifToolHelper, data []byte, opts ExifWriteOptions) (out []byte, ok bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return nil, false, nil
+	}
+	out, err = s.runLocked(h, data, opts)
+	if err == nil {
+		s.stats.Frames++
+		return out, true, nil
+	}
+	s.stats.LastError = err.Error()
+	if errors.Is(err, errStayOpenTimeout) {
+		return nil, true, err
+	}
+	s.stats.Fallbacks++
+	return nil, false, nil
+}
+
+func (s *exifToolServer) runLocked(h *ExifToolHelper, data []byte, opts ExifWriteOptions) ([]byte, error) {
+	args := exifWriteArgs(opts)
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "\r\n") {
+			return nil, fmt.Errorf("argument contains a line break")
+		}
+	}
+	if s.cmd != nil {
+		select {
+		case <-s.exited:
+			s.stopLocked() // Crashed since the last frame: clean up and restart
+		default:
+		}
+	}
+	if s.cmd == nil {
+		if err := s.startLocked(h); err != nil {
+			return nil, err
+		}
+	}
+	s.resetIdleLocked()
+
+	if err := os.WriteFile(s.scratch, data, 0600); err != nil {
+		return nil, fmt.Errorf("write scratch image: %w", err)
+	}
+	s.seq++
+	sentinel := []byte("{ready" + strconv.Itoa(s.seq) + "}\n")
+	command := strings.Join(append(args, "-o", "-", s.scratch), "\n") + "\n-execute" + strconv.Itoa(s.seq) + "\n"
+	s.stderr.Reset()
+	if _, err := io.WriteString(s.stdin, command); err != nil {
+		s.stopLocked()
+		return nil, fmt.Errorf("send command: %w", err)
+	}
+
+	type result struct {
+		out []byte
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		out, err := readUntil(s.stdout, sentinel)
+		done <- result{out, err}
+	}()
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	var r result
+	select {
+	case r = <-done:
+	case <-timer.C:
+		hangs.killed(time.Now())
+		s.killLocked()
+		<-done
+		s.stopLocked()
+		return nil, fmt.Errorf("%w after %v", errStayOpenTimeout, h.timeout)
+	}
+	if r.err != nil {
+		s.stopLocked()
+		return nil, fmt.Errorf("read output: %w", r.err)
+	}
+	hangs.finished()
+
+	out := r.out[:len(r.out)-len(sentinel)]
+	if !bytes.HasPrefix(out, []byte{0xFF, 0xD8}) {
+		return nil, fmt.Errorf("exiftool write failed: %s", strings.TrimSpace(s.stderr.String()))
+	}
+	return out, nil
+}
+
+// startLocked starts the persistent process and its scratch directory
+func (s *exifToolServer) startLocked(h *ExifToolHelper) error {
+	dir, err := os.MkdirTemp("", "aviationwx-exiftool-*")
+	if err != nil {
+		return fmt.Errorf("create scratch directory: %w", err)
+	}
+	// Timeouts are enforced per frame in runLocked; the process itself lives on
+	cmd := h.createCommand(context.Background(), "-stay_open", "True", "-@", "-")
+	stdin, err := cmd.StdinPipe()
+	if err == nil {
+		var stdout io.ReadCloser
+		if stdout, err = cmd.StdoutPipe(); err == nil {
+			s.stdout = bufio.NewReader(stdout)
+		}
+	}
+	s.stderr = &lockedBuffer{}
+	cmd.Stderr = s.stderr
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("start exiftool: %w", err)
+	}
+
+	s.cmd, s.stdin = cmd, stdin
+	s.scratch = filepath.Join(dir, "frame.jpg")
+	s.exited = make(chan struct{})
+	s.stats.Starts++
+	exited := s.exited
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return nil
+}
+
+// stopLocked asks the process to exit, killing it if it does not, and removes the
+// scratch directory
+func (s *exifToolServer) stopLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.cmd == nil {
+		return
+	}
+	_, _ = io.WriteString(s.stdin, "-stay_open\nFalse\n")
+	_ = s.stdin.Close()
+	select {
+	case <-s.exited:
+	case <-time.After(exiftoolWaitDelay):
+		s.killLocked()
+		<-s.exited
+	}
+	os.RemoveAll(filepath.Dir(s.scratch))
+	s.cmd = nil
+}
+
+// killLocked kills the process group, like a timed-out one-shot run
+func (s *exifToolServer) killLocked() {
+	if s.cmd != nil && s.cmd.Process != nil {
+		_ = syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// resetIdleLocked restarts the idle countdown
+func (s *exifToolServer) resetIdleLocked() {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(s.idle, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.stopLocked()
+	})
+}
+
+// readUntil reads r up to and including sentinel
+func readUntil(r *bufio.Reader, sentinel []byte) ([]byte, error) {
+	var buf []byte
+	for {
+		line, err := r.ReadBytes('\n')
+		buf = append(buf, line...)
+		if bytes.HasSuffix(buf, sentinel) {
+			return buf, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// lockedBuffer is a bytes.Buffer safe for exec's stderr copier and readers
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
@@ -0,0 +1,153 @@
+package time
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeStayOpen installs a fresh stay-open server and returns a helper running a
+// fake exiftool. In stay-open mode the fake runs onExecute for each command, with
+// $file the image argument and $n the command number; one-shot runs leave the
+// image as it is.
+func fakeStayOpen(t *testing.T, onExecute string, idle time.Duration) *ExifToolHelper {
+	t.Helper()
+	resetHangs(t)
+	orig := stayOpen
+	stayOpen = &exifToolServer{idle: DefaultExifToolIdleTimeout}
+	t.Cleanup(func() {
+		StopExifToolStayOpen()
+		stayOpen = orig
+	})
+	SetExifToolStayOpen(true, idle)
+
+	script := fmt.Sprintf(`#!/bin/sh
+[ "$1" = "-stay_open" ] || exit 0
+file=
+while IFS= read -r line; do
+	case "$line" in
+	-execute*) n=${line#-execute}; %s ;;
+	False) exit 0 ;;
+	*) file=$line ;;
+	esac
+done
+`, onExecute)
+	path := filepath.Join(t.TempDir(), "exiftool")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return &ExifToolHelper{exiftoolPath: path, timeout: 5 * time.Second}
+}
+
+const echoImage = `cat "$file"; printf '{ready%s}\n' "$n"`
+
+func TestStayOpen_ReusesProcess(t *testing.T) {
+	helper := fakeStayOpen(t, echoImage, 0)
+	image := encodeTestJPEG(t)
+
+	for i := 0; i < 3; i++ {
+		out, err := helper.WriteEXIFToData(image, ExifWriteOptions{UserComment: "frame"})
+		if err != nil || !bytes.Equal(out, image) {
+			t.Fatalf("frame %d: got %d bytes, %v", i, len(out), err)
+		}
+	}
+	s := ExifToolStayOpenStatus()
+	if !s.Running || s.Starts != 1 || s.Frames != 3 || s.Fallbacks != 0 {
+		t.Errorf("stats = %+v, want one process for all frames", s)
+	}
+
+	scratch := filepath.Dir(stayOpen.scratch)
+	SetExifToolStayOpen(false, 0)
+	if ExifToolStayOpenStatus().Running {
+		t.Error("process still running after disabling stay-open mode")
+	}
+	if _, err := os.Stat(scratch); !os.IsNotExist(err) {
+		t.Errorf("scratch directory left behind: %v", err)
+	}
+}
+
+func TestStayOpen_CrashFallsBackThenRestarts(t *testing.T) {
+	helper := fakeStayOpen(t, "exit 1", 0)
+	image := encodeTestJPEG(t)
+
+	// The crashed frame is stamped by a one-shot run instead
+	out, err := helper.WriteEXIFToData(image, ExifWriteOptions{UserComment: "frame"})
+	if err != nil || !bytes.Equal(out, image) {
+		t.Fatalf("fallback: got %d bytes, %v", len(out), err)
+	}
+	if s := ExifToolStayOpenStatus(); s.Running || s.Fallbacks != 1 || s.LastError == "" {
+		t.Errorf("stats after crash = %+v", s)
+	}
+
+	_, _ = helper.WriteEXIFToData(image, ExifWriteOptions{})
+	if s := ExifToolStayOpenStatus(); s.Starts != 2 || s.Fallbacks != 2 {
+		t.Errorf("stats = %+v, want a restart on the next frame", s)
+	}
+}
+
+func TestStayOpen_TimeoutKillsProcess(t *testing.T) {
+	helper := fakeStayOpen(t, "sleep 30", 0)
+	helper.timeout = 200 * time.Millisecond
+
+	start := time.Now()
+	_, err := helper.WriteEXIFToData(encodeTestJPEG(t), ExifWriteOptions{})
+	if !errors.Is(err, errStayOpenTimeout) {
+		t.Fatalf("err = %v, want a stay-open timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed >= exiftoolWaitDelay {
+		t.Errorf("timed-out frame took %v", elapsed)
+	}
+	if s := ExifToolStayOpenStatus(); s.Running || s.Fallbacks != 0 {
+		t.Errorf("stats = %+v, want the process killed without a one-shot retry", s)
+	}
+	if got := ExifToolHangStats().Killed; got != 1 {
+		t.Errorf("Killed = %d, want 1", got)
+	}
+}
+
+func TestStayOpen_StopsWhenIdle(t *testing.T) {
+	helper := fakeStayOpen(t, echoImage, 100*time.Millisecond)
+	if _, err := helper.WriteEXIFToData(encodeTestJPEG(t), ExifWriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for ExifToolStayOpenStatus().Running {
+		if time.Now().After(deadline) {
+			t.Fatal("idle process was not stopped")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// BenchmarkWriteEXIFToData compares one-shot runs with the stay-open process
+// using the installed exiftool
+func BenchmarkWriteEXIFToData(b *testing.B) {
+	helper, err := DefaultExifToolHelper()
+	if err != nil {
+		b.Skipf("exiftool not available: %v", err)
+	}
+	image := encodeTestJPEG(b)
+	opts := ExifWriteOptions{DateTimeOriginal: "2026:01:02 03:04:05", OffsetTimeOriginal: "+00:00", UserComment: "AviationWX-Bridge"}
+
+	orig := stayOpen
+	b.Cleanup(func() { stayOpen = orig })
+	for _, mode := range []struct {
+		name    string
+		enabled bool
+	}{{"one-shot", false}, {"stay-open", true}} {
+		b.Run(mode.name, func(b *testing.B) {
+			stayOpen = &exifToolServer{idle: DefaultExifToolIdleTimeout}
+			SetExifToolStayOpen(mode.enabled, 0)
+			defer StopExifToolStayOpen()
+			for i := 0; i < b.N; i++ {
+				if _, err := helper.WriteEXIFToData(image, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
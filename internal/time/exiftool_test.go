@@ -605,3 +605,50 @@ func createTestJPEG(size int) []byte {
 
 	return minimalJPEG
 }
+
+func TestSanitizeExifNote(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "KSEA north field", "KSEA north field"},
+		{"colons kept", "station:KSEA", "station:KSEA"},
+		{"newlines and tabs", "line1\nline2\r\n\tline3", "line1 line2 line3"},
+		{"nul and escape", "a\x00b\x1bc", "a b c"},
+		{"trimmed", "   padded   ", "padded"},
+		{"empty", "", ""},
+		{"truncated", strings.Repeat("x", maxExifNoteLen+50), strings.Repeat("x", maxExifNoteLen)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeExifNote(tt.in); got != tt.want {
+				t.Errorf("SanitizeExifNote(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBridgeEXIFOptions_NoteDoesNotAffectMarker(t *testing.T) {
+	obs := ObservationResult{
+		Time:       time.Date(2024, 12, 25, 10, 30, 0, 0, time.UTC),
+		Source:     SourceBridgeClock,
+		Confidence: ConfidenceHigh,
+	}
+
+	plain := bridgeEXIFOptions(obs, FrameMeta{})
+	withNote := bridgeEXIFOptions(obs, FrameMeta{Note: "station:KSEA\nsecond line"})
+
+	if withNote.UserComment != plain.UserComment {
+		t.Errorf("marker changed by note: %q vs %q", withNote.UserComment, plain.UserComment)
+	}
+	if withNote.UserComment != "AviationWX-Bridge:UTC:v1:bridge_clock:high" {
+		t.Errorf("unexpected marker %q", withNote.UserComment)
+	}
+	if withNote.ImageDescription != "station:KSEA second line" {
+		t.Errorf("ImageDescription = %q", withNote.ImageDescription)
+	}
+	if plain.ImageDescription != "" {
+		t.Errorf("expected empty ImageDescription without note, got %q", plain.ImageDescription)
+	}
+}
@@ -0,0 +1,67 @@
+package time
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+)
+
+// bridgeMarkerPrefix starts the EXIF UserComment marker the bridge writes:
+// AviationWX-Bridge:UTC:v1:source:confidence[:warn:code]
+const bridgeMarkerPrefix = "AviationWX-Bridge:UTC:v1:"
+
+// markerScanBytes bounds how much of a file is searched for the marker; EXIF sits
+// in the JPEG header, well before the scan data
+const markerScanBytes = 64 * 1024
+
+// BridgeMarker is the time provenance recorded in a frame's bridge marker
+type BridgeMarker struct {
+	Source      TimeSource
+	Confidence  Confidence
+	WarningCode string // "" when the frame was stamped without a warning
+}
+
+// FindBridgeMarker looks for the bridge marker in the header bytes of a JPEG
+func FindBridgeMarker(header []byte) (BridgeMarker, bool) {
+	i := bytes.Index(header, []byte(bridgeMarkerPrefix))
+	if i < 0 {
+		return BridgeMarker{}, false
+	}
+	rest := header[i+len(bridgeMarkerPrefix):]
+	end := bytes.IndexFunc(rest, func(r rune) bool { return !isMarkerChar(r) })
+	if end >= 0 {
+		rest = rest[:end]
+	}
+
+	parts := strings.Split(string(rest), ":")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return BridgeMarker{}, false
+	}
+	marker := BridgeMarker{Source: TimeSource(parts[0]), Confidence: Confidence(parts[1])}
+	if len(parts) >= 4 && parts[2] == "warn" {
+		marker.WarningCode = parts[3]
+	}
+	return marker, true
+}
+
+// ReadBridgeMarker reads the bridge marker from the JPEG file at path
+func ReadBridgeMarker(path string) (BridgeMarker, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return BridgeMarker{}, false
+	}
+	defer f.Close()
+	header, err := io.ReadAll(io.LimitReader(f, markerScanBytes))
+	if err != nil {
+		return BridgeMarker{}, false
+	}
+	return FindBridgeMarker(header)
+}
+
+// isMarkerChar reports whether r can appear in a marker field; the marker ends at
+// the first byte outside this set (the EXIF NUL terminator or the next tag)
+func isMarkerChar(r rune) bool {
+	return r == ':' || r == '_' || r == '-' || r == '.' ||
+		('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9')
+}
@@ -0,0 +1,53 @@
+package time
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindBridgeMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   BridgeMarker
+		ok     bool
+	}{
+		{"plain", "ASCII\x00\x00\x00AviationWX-Bridge:UTC:v1:bridge_clock:high\x00", BridgeMarker{Source: SourceBridgeClock, Confidence: ConfidenceHigh}, true},
+		{"warning", "AviationWX-Bridge:UTC:v1:camera_exif:low:warn:camera_drift\xff\xd9", BridgeMarker{Source: SourceCameraEXIF, Confidence: ConfidenceLow, WarningCode: "camera_drift"}, true},
+		{"no marker", "AviationWX-Bridge: camera=north", BridgeMarker{}, false},
+		{"truncated", "AviationWX-Bridge:UTC:v1:bridge_clock", BridgeMarker{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindBridgeMarker([]byte(tt.header))
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("FindBridgeMarker = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestReadBridgeMarker_StampedFrame(t *testing.T) {
+	obs := ObservationResult{
+		Time:       time.Date(2024, 12, 25, 10, 30, 0, 0, time.UTC),
+		Source:     SourceBridgeClock,
+		Confidence: ConfidenceMedium,
+		Warning:    &TimeWarning{Code: "ntp_unhealthy"},
+	}
+	result := StampBridgeEXIF(encodeTestJPEG(t), obs, FrameMeta{Note: "KSPB north", Sequence: 7})
+	path := filepath.Join(t.TempDir(), "frame.jpg")
+	if err := os.WriteFile(path, result.Data, 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	got, ok := ReadBridgeMarker(path)
+	want := BridgeMarker{Source: SourceBridgeClock, Confidence: ConfidenceMedium, WarningCode: "ntp_unhealthy"}
+	if !ok || got != want {
+		t.Errorf("ReadBridgeMarker = %+v, %v; want %+v", got, ok, want)
+	}
+	if _, ok := ReadBridgeMarker(filepath.Join(t.TempDir(), "missing.jpg")); ok {
+		t.Error("found a marker in a missing file")
+	}
+}
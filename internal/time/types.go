@@ -124,6 +124,15 @@ func (th *TimeHealth) Stop() {
 	}
 }
 
+// Recheck runs an SNTP check now rather than at the next interval, e.g. after the
+// system clock jumped. It blocks until the check completes.
+func (th *TimeHealth) Recheck() {
+	if th.ctx.Err() != nil {
+		return
+	}
+	th.check()
+}
+
 // check performs a single SNTP check
 func (th *TimeHealth) check() {
 	// Try each server until one succeeds
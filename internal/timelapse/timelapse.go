@@ -0,0 +1,295 @@
+// Package timelapse renders a sequence of retained frames into a GIF or MP4
+package timelapse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	imgproc "github.com/alexwitherspoon/AviationWX.org-Bridge/internal/image"
+)
+
+// Output formats
+const (
+	FormatMP4 = "mp4" // H.264 via ffmpeg
+	FormatGIF = "gif" // Built in; every frame is held in memory
+)
+
+// Defaults applied by Render
+const (
+	DefaultFPS       = 10
+	DefaultMaxWidth  = 640
+	DefaultMaxFrames = 240
+	frameQuality     = 85 // JPEG quality of downscaled frames fed to ffmpeg
+)
+
+// minFrames is the fewest usable frames worth rendering
+const minFrames = 2
+
+// ErrTooFewFrames is returned when fewer than two frames could be used
+var ErrTooFewFrames = errors.New("too few frames for a timelapse")
+
+// ffmpegPath is the encoder used for MP4 (overridden in tests)
+var ffmpegPath = "ffmpeg"
+
+// Frame is one input frame, loaded only when it is rendered
+type Frame struct {
+	Time time.Time
+	Load func() ([]byte, error)
+}
+
+// Options controls the rendered timelapse
+type Options struct {
+	Format    string // Default: mp4
+	FPS       int    // Default: 10
+	MaxWidth  int    // Frames are downscaled to this width. Default: 640
+	MaxFrames int    // Frames are sampled evenly down to this count. Default: 240
+}
+
+// Slots bounds concurrent image work; each frame holds one slot while decoded
+type Slots interface {
+	AcquireImageProcessing(ctx context.Context) error
+	ReleaseImageProcessing()
+}
+
+// Result is a rendered timelapse
+type Result struct {
+	Data    []byte
+	Frames  int // Frames in the output
+	Skipped int // Frames that could not be loaded or decoded, or changed size
+}
+
+// Render encodes frames, oldest first, into a timelapse. Frames that fail to load
+// or decode, or whose size differs from the first usable frame (e.g. the camera's
+// resolution changed during the day), are skipped. slots may be nil.
+func Render(ctx context.Context, frames []Frame, opts Options, slots Slots) (Result, error) {
+	opts = withDefaults(opts)
+	frames = Sample(frames, opts.MaxFrames)
+	proc := imgproc.NewProcessor(&config.ImageProcessing{MaxWidth: opts.MaxWidth, Quality: frameQuality})
+
+	var enc encoder
+	switch opts.Format {
+	case FormatGIF:
+		enc = &gifEncoder{delay: max(2, 100/opts.FPS)}
+	case FormatMP4:
+		enc = &mp4Encoder{fps: opts.FPS}
+	default:
+		return Result{}, fmt.Errorf("unknown timelapse format %q", opts.Format)
+	}
+	defer enc.abort()
+
+	var result Result
+	var size image.Point
+	for _, f := range frames {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+		data, err := scaleFrame(ctx, f, proc, slots)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Result{}, ctx.Err()
+			}
+			result.Skipped++
+			continue
+		}
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			result.Skipped++
+			continue
+		}
+		if result.Frames == 0 {
+			size = image.Pt(cfg.Width, cfg.Height)
+		} else if image.Pt(cfg.Width, cfg.Height) != size {
+			result.Skipped++
+			continue
+		}
+		if err := enc.add(ctx, data); err != nil {
+			return Result{}, err
+		}
+		result.Frames++
+	}
+	if result.Frames < minFrames {
+		return Result{}, fmt.Errorf("%w: %d usable of %d", ErrTooFewFrames, result.Frames, len(frames))
+	}
+
+	out, err := enc.finish(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	result.Data = out
+	return result, nil
+}
+
+// scaleFrame loads and downscales one frame while holding an image processing slot
+func scaleFrame(ctx context.Context, f Frame, proc *imgproc.Processor, slots Slots) ([]byte, error) {
+	data, err := f.Load()
+	if err != nil {
+		return nil, err
+	}
+	if slots != nil {
+		if err := slots.AcquireImageProcessing(ctx); err != nil {
+			return nil, err
+		}
+		defer slots.ReleaseImageProcessing()
+	}
+	return proc.Process(data)
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Format == "" {
+		opts.Format = FormatMP4
+	}
+	if opts.FPS <= 0 {
+		opts.FPS = DefaultFPS
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = DefaultMaxWidth
+	}
+	if opts.MaxFrames <= 0 {
+		opts.MaxFrames = DefaultMaxFrames
+	}
+	return opts
+}
+
+// Sample returns at most n frames spread evenly over frames, keeping the first
+// and last
+func Sample(frames []Frame, n int) []Frame {
+	if n <= 0 || len(frames) <= n {
+		return frames
+	}
+	if n == 1 {
+		return frames[:1]
+	}
+	sampled := make([]Frame, n)
+	for i := range sampled {
+		sampled[i] = frames[i*(len(frames)-1)/(n-1)]
+	}
+	return sampled
+}
+
+// encoder accumulates JPEG frames of one size into an output file
+type encoder interface {
+	add(ctx context.Context, jpegData []byte) error
+	finish(ctx context.Context) ([]byte, error)
+	abort() // Releases resources; a no-op after finish
+}
+
+// gifEncoder quantizes each frame to the Plan 9 palette with dithering
+type gifEncoder struct {
+	delay int // Per frame, in hundredths of a second
+	anim  gif.GIF
+}
+
+func (e *gifEncoder) add(_ context.Context, jpegData []byte) error {
+	img, err := jpeg.Decode(bytes.NewReader(jpegData))
+	if err != nil {
+		return fmt.Errorf("decode frame: %w", err)
+	}
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+	e.anim.Image = append(e.anim.Image, paletted)
+	e.anim.Delay = append(e.anim.Delay, e.delay)
+	return nil
+}
+
+func (e *gifEncoder) finish(context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, &e.anim); err != nil {
+		return nil, fmt.Errorf("encode gif: %w", err)
+	}
+	e.anim = gif.GIF{}
+	return buf.Bytes(), nil
+}
+
+func (e *gifEncoder) abort() { e.anim = gif.GIF{} }
+
+// mp4Encoder streams frames to ffmpeg, which writes H.264 to a temporary file so
+// the index can be moved to the front for progressive playback
+type mp4Encoder struct {
+	fps    int
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stderr bytes.Buffer
+	out    string
+}
+
+func (e *mp4Encoder) start(ctx context.Context) error {
+	f, err := os.CreateTemp("", "timelapse-*.mp4")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	f.Close()
+	e.out = f.Name()
+
+	e.cmd = exec.CommandContext(ctx, ffmpegPath,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-f", "image2pipe", "-c:v", "mjpeg", "-framerate", fmt.Sprint(e.fps), "-i", "-",
+		// libx264 with yuv420p needs even dimensions
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+		"-c:v", "libx264", "-pix_fmt", "yuv420p", "-movflags", "+faststart",
+		e.out)
+	e.cmd.Stderr = &e.stderr
+	if e.stdin, err = e.cmd.StdinPipe(); err != nil {
+		return fmt.Errorf("ffmpeg stdin: %w", err)
+	}
+	if err := e.cmd.Start(); err != nil {
+		e.cmd = nil
+		return fmt.Errorf("start ffmpeg: %w", err)
+	}
+	return nil
+}
+
+func (e *mp4Encoder) add(ctx context.Context, jpegData []byte) error {
+	if e.cmd == nil {
+		if err := e.start(ctx); err != nil {
+			return err
+		}
+	}
+	if _, err := e.stdin.Write(jpegData); err != nil {
+		return fmt.Errorf("write frame to ffmpeg: %w (%s)", err, bytes.TrimSpace(e.stderr.Bytes()))
+	}
+	return nil
+}
+
+func (e *mp4Encoder) finish(ctx context.Context) ([]byte, error) {
+	e.stdin.Close()
+	err := e.cmd.Wait()
+	e.cmd = nil
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("ffmpeg: %w (%s)", err, bytes.TrimSpace(e.stderr.Bytes()))
+	}
+	data, err := os.ReadFile(e.out)
+	if err != nil {
+		return nil, fmt.Errorf("read mp4: %w", err)
+	}
+	return data, nil
+}
+
+func (e *mp4Encoder) abort() {
+	if e.cmd != nil {
+		e.stdin.Close()
+		if e.cmd.Process != nil {
+			_ = e.cmd.Process.Kill()
+		}
+		_ = e.cmd.Wait()
+		e.cmd = nil
+	}
+	if e.out != "" {
+		os.Remove(e.out)
+	}
+}
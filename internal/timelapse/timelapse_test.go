@@ -0,0 +1,119 @@
+package timelapse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func testJPEG(t *testing.T, w, h int, shade uint8) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{shade, uint8(x), uint8(y), 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func frameOf(data []byte, err error) Frame {
+	return Frame{Time: time.Now(), Load: func() ([]byte, error) { return data, err }}
+}
+
+// countingSlots records acquired image processing slots
+type countingSlots struct{ held, acquired int }
+
+func (s *countingSlots) AcquireImageProcessing(context.Context) error {
+	s.held++
+	s.acquired++
+	return nil
+}
+
+func (s *countingSlots) ReleaseImageProcessing() { s.held-- }
+
+func TestRender_GIFSkipsUnusableFrames(t *testing.T) {
+	frames := []Frame{
+		frameOf(testJPEG(t, 64, 48, 0), nil),
+		frameOf(nil, errors.New("evicted")),   // Gone from history
+		frameOf([]byte("not a jpeg"), nil),    // Corrupt
+		frameOf(testJPEG(t, 32, 24, 50), nil), // Resolution changed
+		frameOf(testJPEG(t, 64, 48, 100), nil),
+		frameOf(testJPEG(t, 64, 48, 200), nil),
+	}
+	slots := &countingSlots{}
+	result, err := Render(context.Background(), frames, Options{Format: FormatGIF, FPS: 5}, slots)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if result.Frames != 3 || result.Skipped != 3 {
+		t.Errorf("frames = %d, skipped = %d, want 3 and 3", result.Frames, result.Skipped)
+	}
+	if slots.held != 0 || slots.acquired != 5 {
+		t.Errorf("slots held %d, acquired %d, want 0 and 5", slots.held, slots.acquired)
+	}
+
+	anim, err := gif.DecodeAll(bytes.NewReader(result.Data))
+	if err != nil {
+		t.Fatalf("decode gif: %v", err)
+	}
+	if len(anim.Image) != 3 || anim.Delay[0] != 20 {
+		t.Errorf("gif has %d frames with delay %d, want 3 with 20", len(anim.Image), anim.Delay[0])
+	}
+}
+
+func TestRender_TooFewFrames(t *testing.T) {
+	frames := []Frame{frameOf(testJPEG(t, 16, 16, 0), nil), frameOf(nil, errors.New("evicted"))}
+	_, err := Render(context.Background(), frames, Options{Format: FormatGIF}, nil)
+	if !errors.Is(err, ErrTooFewFrames) {
+		t.Errorf("Render() error = %v, want ErrTooFewFrames", err)
+	}
+}
+
+func TestRender_MP4(t *testing.T) {
+	if _, err := exec.LookPath(ffmpegPath); err != nil {
+		t.Skip("ffmpeg not installed")
+	}
+	var frames []Frame
+	for i := 0; i < 5; i++ {
+		frames = append(frames, frameOf(testJPEG(t, 65, 49, uint8(i*40)), nil)) // Odd size is padded even
+	}
+	result, err := Render(context.Background(), frames, Options{Format: FormatMP4}, nil)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if result.Frames != 5 || !bytes.Contains(result.Data[:32], []byte("ftyp")) {
+		t.Errorf("frames = %d, header %q, want 5 frames of mp4", result.Frames, result.Data[:12])
+	}
+}
+
+func TestSample(t *testing.T) {
+	var frames []Frame
+	for i := 0; i < 10; i++ {
+		frames = append(frames, Frame{Time: time.Unix(int64(i), 0)})
+	}
+	got := Sample(frames, 4)
+	want := []int64{0, 3, 6, 9}
+	if len(got) != len(want) {
+		t.Fatalf("Sample() returned %d frames, want %d", len(got), len(want))
+	}
+	for i, f := range got {
+		if f.Time.Unix() != want[i] {
+			t.Errorf("frame %d = %d, want %d", i, f.Time.Unix(), want[i])
+		}
+	}
+	if len(Sample(frames, 20)) != 10 {
+		t.Error("Sample() should keep every frame under the limit")
+	}
+}
@@ -0,0 +1,423 @@
+// Package tunnel forwards a local port to a camera through an outbound SSH connection,
+// for cameras behind CGNAT that are only reachable from a host the bridge can log in to
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Tunnel defaults
+const (
+	defaultSSHPort           = 22
+	defaultDialTimeout       = 15 * time.Second
+	defaultKeepaliveInterval = 30 * time.Second
+	defaultRetryMin          = 2 * time.Second
+	defaultRetryMax          = 2 * time.Minute
+)
+
+// Config configures a local port forward (ssh -L) to Target through an SSH server
+type Config struct {
+	Host     string // SSH server
+	Port     int    // Default: 22
+	Username string
+	Password string // Password auth (optional if PrivateKey is set)
+
+	PrivateKey []byte // PEM private key (optional if Password is set)
+
+	// HostKeyFingerprint pins the SSH server key (SHA256, as printed by ssh-keygen -lf).
+	// Required unless InsecureIgnoreHostKey is set.
+	HostKeyFingerprint    string
+	InsecureIgnoreHostKey bool
+
+	Target    string // host:port of the camera as seen from the SSH server
+	LocalPort int    // Port on 127.0.0.1; 0 picks a free port
+
+	DialTimeout       time.Duration // Default: 15s
+	KeepaliveInterval time.Duration // Default: 30s
+	Logger            Logger
+}
+
+// Logger is the logging interface used by Tunnel
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// Status is a snapshot of tunnel health
+type Status struct {
+	SSHHost        string    `json:"ssh_host"`
+	Target         string    `json:"target"`
+	LocalAddr      string    `json:"local_addr"`
+	Connected      bool      `json:"connected"`
+	ConnectedSince time.Time `json:"connected_since,omitempty"`
+	Connects       int64     `json:"connects"`     // SSH sessions established
+	Drops          int64     `json:"drops"`        // SSH sessions lost
+	ActiveConns    int       `json:"active_conns"` // Forwarded connections in flight
+	LastError      string    `json:"last_error,omitempty"`
+	LastErrorTime  time.Time `json:"last_error_time,omitempty"`
+}
+
+// Tunnel is a running local port forward. The SSH session is established on demand
+// and kept alive in the background, reconnecting with backoff after it drops.
+type Tunnel struct {
+	cfg       Config
+	addr      string
+	sshConfig *ssh.ClientConfig
+	listener  net.Listener
+	logger    Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	connectMu sync.Mutex // Serializes dialing
+	mu        sync.Mutex
+	client    *ssh.Client
+	status    Status
+
+	retryMin time.Duration
+	retryMax time.Duration
+}
+
+// New validates cfg and listens on the local port. Call Start to begin forwarding.
+func New(cfg Config) (*Tunnel, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("tunnel host is required")
+	}
+	if cfg.Username == "" {
+		return nil, fmt.Errorf("tunnel username is required")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Target); err != nil {
+		return nil, fmt.Errorf("tunnel target must be host:port: %w", err)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = defaultSSHPort
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultDialTimeout
+	}
+	if cfg.KeepaliveInterval <= 0 {
+		cfg.KeepaliveInterval = defaultKeepaliveInterval
+	}
+
+	sshConfig, err := clientConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(cfg.LocalPort)))
+	if err != nil {
+		return nil, fmt.Errorf("listen on local port: %w", err)
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = nopLogger{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	return &Tunnel{
+		cfg:       cfg,
+		addr:      addr,
+		sshConfig: sshConfig,
+		listener:  listener,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+		status: Status{
+			SSHHost:   addr,
+			Target:    cfg.Target,
+			LocalAddr: listener.Addr().String(),
+		},
+		retryMin: defaultRetryMin,
+		retryMax: defaultRetryMax,
+	}, nil
+}
+
+// clientConfig builds the SSH client config, refusing to run without a pinned host
+// key unless explicitly told to
+func clientConfig(cfg Config) (*ssh.ClientConfig, error) {
+	var auth []ssh.AuthMethod
+	if len(cfg.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(cfg.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("parse tunnel private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if cfg.Password != "" {
+		auth = append(auth, ssh.Password(cfg.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("tunnel password or private key is required")
+	}
+
+	var hostKey ssh.HostKeyCallback
+	switch {
+	case cfg.HostKeyFingerprint != "":
+		hostKey = pinnedHostKey(cfg.HostKeyFingerprint)
+	case cfg.InsecureIgnoreHostKey:
+		hostKey = ssh.InsecureIgnoreHostKey()
+	default:
+		return nil, fmt.Errorf("tunnel host key fingerprint is required (or set insecure_ignore_host_key)")
+	}
+
+	return &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auth,
+		HostKeyCallback: hostKey,
+		Timeout:         cfg.DialTimeout,
+	}, nil
+}
+
+// pinnedHostKey accepts only a server key whose SHA256 fingerprint matches
+func pinnedHostKey(fingerprint string) ssh.HostKeyCallback {
+	want := "SHA256:" + strings.TrimPrefix(strings.TrimSpace(fingerprint), "SHA256:")
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		if got := ssh.FingerprintSHA256(key); got != want {
+			return fmt.Errorf("host key mismatch for %s: got %s", hostname, got)
+		}
+		return nil
+	}
+}
+
+// LocalAddr returns the 127.0.0.1:port address cameras should connect to
+func (t *Tunnel) LocalAddr() string {
+	return t.listener.Addr().String()
+}
+
+// Start accepts local connections and keeps the SSH session alive until Close
+func (t *Tunnel) Start() {
+	t.wg.Add(2)
+	go t.acceptLoop()
+	go t.maintain()
+}
+
+// Close stops the tunnel; forwarded connections are closed with the SSH session
+func (t *Tunnel) Close() error {
+	t.cancel()
+	err := t.listener.Close()
+
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client != nil {
+		t.drop(client, nil)
+	}
+
+	t.wg.Wait()
+	return err
+}
+
+// Status returns a snapshot of tunnel health
+func (t *Tunnel) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+func (t *Tunnel) acceptLoop() {
+	defer t.wg.Done()
+	for {
+		local, err := t.listener.Accept()
+		if err != nil {
+			if t.ctx.Err() != nil {
+				return
+			}
+			t.recordError(fmt.Errorf("accept: %w", err))
+			continue
+		}
+		go t.forward(local)
+	}
+}
+
+// maintain connects the SSH session with backoff and probes it with keepalives so a
+// silently dead session is replaced before the next capture needs it
+func (t *Tunnel) maintain() {
+	defer t.wg.Done()
+	retry := t.retryMin
+	for {
+		wait := t.cfg.KeepaliveInterval
+		client, err := t.connect()
+		if err == nil {
+			if _, _, err = client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				t.drop(client, fmt.Errorf("keepalive: %w", err))
+			}
+		}
+		if err != nil {
+			wait = retry
+			retry = min(retry*2, t.retryMax)
+		} else {
+			retry = t.retryMin
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-t.ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// connect returns the current SSH session, dialing a new one if there is none
+func (t *Tunnel) connect() (*ssh.Client, error) {
+	t.connectMu.Lock()
+	defer t.connectMu.Unlock()
+
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+	if t.ctx.Err() != nil {
+		return nil, errors.New("tunnel closed")
+	}
+
+	client, err := ssh.Dial("tcp", t.addr, t.sshConfig)
+	if err != nil {
+		err = fmt.Errorf("ssh dial %s: %w", t.addr, err)
+		t.recordError(err)
+		return nil, err
+	}
+
+	t.mu.Lock()
+	if t.ctx.Err() != nil {
+		t.mu.Unlock()
+		_ = client.Close() // Closed while dialing
+		return nil, errors.New("tunnel closed")
+	}
+	t.client = client
+	t.status.Connected = true
+	t.status.ConnectedSince = time.Now()
+	t.status.Connects++
+	t.mu.Unlock()
+	t.logger.Info("Tunnel connected", "ssh_host", t.addr, "target", t.cfg.Target, "local_addr", t.LocalAddr())
+
+	// Notice a closed session immediately rather than at the next keepalive
+	go func() {
+		_ = client.Wait()
+		t.drop(client, errors.New("ssh connection closed"))
+	}()
+	return client, nil
+}
+
+// drop discards a dead or closing SSH session; err is nil for a deliberate close
+func (t *Tunnel) drop(client *ssh.Client, err error) {
+	t.mu.Lock()
+	current := t.client == client
+	if current {
+		t.client = nil
+		t.status.Connected = false
+		if err != nil && t.ctx.Err() == nil {
+			t.status.Drops++
+		}
+	}
+	t.mu.Unlock()
+	_ = client.Close()
+
+	if current && err != nil && t.ctx.Err() == nil {
+		t.recordError(err)
+		t.logger.Warn("Tunnel dropped, reconnecting", "ssh_host", t.addr, "target", t.cfg.Target, "error", err)
+	}
+}
+
+// forward connects one local connection to the target through the SSH session
+func (t *Tunnel) forward(local net.Conn) {
+	defer local.Close()
+
+	client, err := t.connect()
+	if err != nil {
+		return
+	}
+	remote, err := client.Dial("tcp", t.cfg.Target)
+	if err != nil {
+		t.recordError(fmt.Errorf("dial target %s: %w", t.cfg.Target, err))
+		return
+	}
+	defer remote.Close()
+
+	t.mu.Lock()
+	t.status.ActiveConns++
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.status.ActiveConns--
+		t.mu.Unlock()
+	}()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	// Either side finishing ends the exchange; the deferred closes stop the other copy
+	<-done
+}
+
+func (t *Tunnel) recordError(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.status.LastError = err.Error()
+	t.status.LastErrorTime = time.Now()
+}
+
+// Default camera ports by URL scheme, used when a URL has no explicit port
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"rtsp":  "554",
+}
+
+// TargetFromURL returns the host:port a camera URL points at
+func TargetFromURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse camera URL: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("camera URL has no host")
+	}
+	port := u.Port()
+	if port == "" {
+		port = defaultPorts[strings.ToLower(u.Scheme)]
+	}
+	if port == "" {
+		return "", fmt.Errorf("camera URL has no port and scheme %q has no default", u.Scheme)
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// RewriteURL points a camera URL at the tunnel's local address, keeping the scheme,
+// credentials, path and query
+func RewriteURL(rawURL, localAddr string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse camera URL: %w", err)
+	}
+	u.Host = localAddr
+	return u.String(), nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...interface{}) {}
+func (nopLogger) Warn(string, ...interface{}) {}
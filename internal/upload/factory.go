@@ -7,21 +7,52 @@ import (
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
 )
 
+// DefaultPort is the aviationwx.org SFTP port
+const DefaultPort = 2222
+
 // NewClientFromConfig creates an SFTP upload client from the config package's Upload type.
 // Protocol "ftps" and "ftp" are migrated to SFTP (port 2222) for backward compatibility.
 func NewClientFromConfig(cfg config.Upload) (Client, error) {
+	uploadConfig, err := configFromUpload(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return NewSFTPClient(uploadConfig)
+}
+
+// ClientFromConfig is NewClientFromConfig for a camera sharing its account's
+// connection through the pool
+func (p *Pool) ClientFromConfig(cameraID string, cfg config.Upload) (Client, error) {
+	uploadConfig, err := configFromUpload(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.Client(cameraID, uploadConfig)
+}
+
+// ServerAddress returns the host:port uploads for cfg connect to
+func ServerAddress(cfg config.Upload) string {
+	port := cfg.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return fmt.Sprintf("%s:%d", cfg.Host, port)
+}
+
+// configFromUpload applies protocol migration and aviationwx.org defaults
+func configFromUpload(cfg config.Upload) (Config, error) {
 	// Normalize protocol: migrate deprecated FTPS/FTP to SFTP
 	protocol := strings.ToLower(strings.TrimSpace(cfg.Protocol))
 	if protocol == "" || protocol == "ftps" || protocol == "ftp" {
 		protocol = "sftp"
 	}
 	if protocol != "sftp" {
-		return nil, fmt.Errorf("unsupported upload protocol: %s (SFTP only)", protocol)
+		return Config{}, fmt.Errorf("unsupported upload protocol: %s (SFTP only)", protocol)
 	}
 
 	port := cfg.Port
 	if port == 0 {
-		port = 2222 // aviationwx.org SFTP port
+		port = DefaultPort
 	}
 
 	basePath := cfg.BasePath
@@ -29,7 +60,7 @@ func NewClientFromConfig(cfg config.Upload) (Client, error) {
 		basePath = "/files" // Default SFTP upload directory
 	}
 
-	uploadConfig := Config{
+	return Config{
 		Host:                  cfg.Host,
 		Port:                  port,
 		Username:              cfg.Username,
@@ -37,7 +68,7 @@ func NewClientFromConfig(cfg config.Upload) (Client, error) {
 		TimeoutConnectSeconds: cfg.TimeoutConnectSeconds,
 		TimeoutUploadSeconds:  cfg.TimeoutUploadSeconds,
 		BasePath:              basePath,
-	}
-
-	return NewSFTPClient(uploadConfig)
+		MkdirEveryUpload:      cfg.MkdirEveryUpload,
+		VerifySize:            cfg.VerifySize,
+	}, nil
 }
@@ -0,0 +1,302 @@
+package upload
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPoolIdleTimeout closes a shared connection no camera has used for this long
+const DefaultPoolIdleTimeout = 2 * time.Minute
+
+// Pool shares one SFTP connection per account among cameras with identical
+// credentials, so several cameras under one login do not log in for every upload.
+// Uploads over an account's connection are serialized and taken round-robin by
+// camera; each camera keeps its own base path and upload options.
+type Pool struct {
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	accounts map[string]*account
+}
+
+// PoolStats describes one shared account connection
+type PoolStats struct {
+	Account   string   `json:"account"` // username@host:port
+	Cameras   []string `json:"cameras"`
+	Connected bool     `json:"connected"`
+	Connects  int64    `json:"connects"` // Logins made; fewer than uploads when the connection is reused
+	Uploads   int64    `json:"uploads"`
+}
+
+// account is one shared connection and the queue of cameras waiting to use it
+type account struct {
+	name        string
+	conn        *SFTPClient // Only used by the camera holding the turn
+	idleTimeout time.Duration
+
+	mu        sync.Mutex // Guards the fields below
+	busy      bool
+	connected bool
+	waiting   map[string][]chan struct{} // Per camera, in arrival order
+	turns     []string                   // Cameras with waiters, in round-robin order
+	cameras   map[string]bool
+	lastUsed  time.Time
+	idle      *time.Timer
+	connects  int64
+	uploads   int64
+}
+
+// NewPool creates a pool closing connections idle for idleTimeout (0 = default)
+func NewPool(idleTimeout time.Duration) *Pool {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultPoolIdleTimeout
+	}
+	return &Pool{idleTimeout: idleTimeout, accounts: make(map[string]*account)}
+}
+
+// Client returns an upload client for a camera that shares the connection of every
+// camera with the same host, port, username and password. A camera re-added with
+// new credentials moves to its new account.
+func (p *Pool) Client(cameraID string, cfg Config) (Client, error) {
+	conn, err := NewSFTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg = conn.config // With defaults applied
+	key := fmt.Sprintf("%s\x00%d\x00%s\x00%s", cfg.Host, cfg.Port, cfg.Username, cfg.Password)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.release(cameraID)
+	a := p.accounts[key]
+	if a == nil {
+		a = &account{
+			name:        fmt.Sprintf("%s@%s:%d", cfg.Username, cfg.Host, cfg.Port),
+			conn:        conn,
+			idleTimeout: p.idleTimeout,
+			waiting:     make(map[string][]chan struct{}),
+			cameras:     make(map[string]bool),
+		}
+		p.accounts[key] = a
+	}
+	a.mu.Lock()
+	a.cameras[cameraID] = true
+	a.mu.Unlock()
+	return &pooledClient{account: a, cameraID: cameraID, config: cfg}, nil
+}
+
+// Release stops counting a removed camera as a user of its account. An account no
+// camera uses any more is dropped and its connection closed once idle.
+func (p *Pool) Release(cameraID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.release(cameraID)
+}
+
+func (p *Pool) release(cameraID string) {
+	for key, a := range p.accounts {
+		a.mu.Lock()
+		delete(a.cameras, cameraID)
+		unused := len(a.cameras) == 0
+		a.mu.Unlock()
+		if unused {
+			// An upload in flight finishes; the idle timer closes the connection after it
+			a.closeIdle(0)
+			delete(p.accounts, key)
+		}
+	}
+}
+
+// Stats returns each shared account, sorted by account name
+func (p *Pool) Stats() []PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]PoolStats, 0, len(p.accounts))
+	for _, a := range p.accounts {
+		a.mu.Lock()
+		s := PoolStats{
+			Account:   a.name,
+			Connected: a.connected,
+			Connects:  a.connects,
+			Uploads:   a.uploads,
+		}
+		for id := range a.cameras {
+			s.Cameras = append(s.Cameras, id)
+		}
+		a.mu.Unlock()
+		sort.Strings(s.Cameras)
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Account < stats[j].Account })
+	return stats
+}
+
+// Close closes every shared connection that is not in use
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, a := range p.accounts {
+		a.closeIdle(0)
+	}
+}
+
+// acquire waits for the camera's turn on the connection
+func (a *account) acquire(cameraID string) {
+	a.mu.Lock()
+	if !a.busy {
+		a.busy = true
+		a.mu.Unlock()
+		return
+	}
+	turn := make(chan struct{})
+	if len(a.waiting[cameraID]) == 0 {
+		a.turns = append(a.turns, cameraID)
+	}
+	a.waiting[cameraID] = append(a.waiting[cameraID], turn)
+	a.mu.Unlock()
+	<-turn
+}
+
+// release hands the connection to the next camera in round-robin order, or starts
+// the idle timer when nobody is waiting
+func (a *account) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lastUsed = time.Now()
+	if len(a.turns) == 0 {
+		a.busy = false
+		if a.idle == nil {
+			a.idle = time.AfterFunc(a.idleTimeout, func() { a.closeIdle(a.idleTimeout) })
+		} else {
+			a.idle.Reset(a.idleTimeout)
+		}
+		return
+	}
+	cameraID := a.turns[0]
+	a.turns = a.turns[1:]
+	queue := a.waiting[cameraID]
+	next := queue[0]
+	if len(queue) > 1 {
+		a.waiting[cameraID] = queue[1:]
+		a.turns = append(a.turns, cameraID) // Back of the line behind other cameras
+	} else {
+		delete(a.waiting, cameraID)
+	}
+	close(next) // busy stays set: the turn passes directly
+}
+
+// closeIdle closes the connection if nobody holds it and it has been unused for at
+// least idleFor
+func (a *account) closeIdle(idleFor time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.busy || time.Since(a.lastUsed) < idleFor {
+		return
+	}
+	_ = a.conn.Close() // Best-effort; the next upload reconnects
+	a.connected = false
+}
+
+// disconnect closes the connection held by the current turn
+func (a *account) disconnect() {
+	_ = a.conn.Close() // Best-effort; the next upload reconnects
+	a.mu.Lock()
+	a.connected = false
+	a.mu.Unlock()
+}
+
+// upload writes data with cfg's options, connecting first if needed. A reused
+// connection may have been dropped by the server while idle, so a failure on one is
+// retried once on a fresh connection.
+func (a *account) upload(cfg Config, remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	reused := a.conn.sftpClient != nil
+	err := a.uploadOnce(cfg, remotePath, data, throttle)
+	if err != nil && reused {
+		err = a.uploadOnce(cfg, remotePath, data, throttle)
+	}
+	if err == nil {
+		a.mu.Lock()
+		a.uploads++
+		a.mu.Unlock()
+	}
+	return err
+}
+
+func (a *account) uploadOnce(cfg Config, remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	if err := a.connect(); err != nil {
+		return err
+	}
+	if err := a.conn.put(cfg, remotePath, data, throttle); err != nil {
+		a.disconnect() // Start over on a fresh connection next time
+		return err
+	}
+	return nil
+}
+
+// connect opens the shared connection unless it is already open
+func (a *account) connect() error {
+	if a.conn.sftpClient != nil {
+		return nil
+	}
+	if err := a.conn.connect(); err != nil {
+		a.conn.forgetDirs() // The server may come back with a different tree
+		return err
+	}
+	a.mu.Lock()
+	a.connects++
+	a.connected = true
+	a.mu.Unlock()
+	return nil
+}
+
+// pooledClient uploads for one camera over its account's shared connection
+type pooledClient struct {
+	account  *account
+	cameraID string
+	config   Config
+}
+
+// Upload waits for the camera's turn on the shared connection and uploads over it
+func (c *pooledClient) Upload(remotePath string, data []byte) error {
+	c.account.acquire(c.cameraID)
+	defer c.account.release()
+	return c.account.upload(c.config, remotePath, data, nil)
+}
+
+// UploadThrottled is Upload with the data sent through throttle
+func (c *pooledClient) UploadThrottled(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	c.account.acquire(c.cameraID)
+	defer c.account.release()
+	return c.account.upload(c.config, remotePath, data, throttle)
+}
+
+// ListDir waits for the camera's turn and lists remoteDir over the shared connection
+func (c *pooledClient) ListDir(remoteDir string) ([]string, error) {
+	c.account.acquire(c.cameraID)
+	defer c.account.release()
+	if err := c.account.connect(); err != nil {
+		return nil, err
+	}
+	names, err := c.account.conn.list(c.config, remoteDir)
+	if err != nil {
+		c.account.disconnect()
+	}
+	return names, err
+}
+
+// TestConnection checks the shared connection can see the camera's base path
+func (c *pooledClient) TestConnection() error {
+	c.account.acquire(c.cameraID)
+	defer c.account.release()
+	if err := c.account.connect(); err != nil {
+		return err
+	}
+	if err := c.account.conn.statBase(c.config.BasePath); err != nil {
+		c.account.disconnect()
+		return err
+	}
+	return nil
+}
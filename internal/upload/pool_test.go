@@ -0,0 +1,151 @@
+package upload
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPool_SharesConnectionPerAccount(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	pool := NewPool(time.Minute)
+	defer pool.Close()
+
+	north, err := pool.Client("north", Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files/north"})
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+	south, err := pool.Client("south", Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files/south"})
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range []Client{north, south} {
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func(c Client, i int) {
+				defer wg.Done()
+				if err := c.Upload(fmt.Sprintf("%d.jpg", i), []byte("jpeg")); err != nil {
+					t.Errorf("upload: %v", err)
+				}
+			}(c, i)
+		}
+	}
+	wg.Wait()
+
+	h.mu.Lock()
+	logins := h.logins
+	h.mu.Unlock()
+	if logins != 1 {
+		t.Errorf("logins = %d, want 1 shared connection", logins)
+	}
+
+	stats := pool.Stats()
+	if len(stats) != 1 || stats[0].Uploads != 6 || stats[0].Connects != 1 || !stats[0].Connected {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if !reflect.DeepEqual(stats[0].Cameras, []string{"north", "south"}) {
+		t.Errorf("cameras = %v", stats[0].Cameras)
+	}
+
+	check, _ := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test"})
+	if err := check.connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer check.Close()
+	for _, dir := range []string{"/files/north", "/files/south"} {
+		if entries, _ := check.sftpClient.ReadDir(dir); len(entries) != 3 {
+			t.Errorf("%s has %d files, want 3", dir, len(entries))
+		}
+	}
+}
+
+func TestPool_ReconnectsDroppedConnection(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	pool := NewPool(time.Minute)
+	defer pool.Close()
+	client, err := pool.Client("cam", Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Upload("cam/1.jpg", []byte("jpeg")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The server dropping an idle connection must not fail the next upload
+	client.(*pooledClient).account.conn.sshClient.Close()
+	if err := client.Upload("cam/2.jpg", []byte("jpeg")); err != nil {
+		t.Fatalf("upload after drop: %v", err)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.logins != 2 {
+		t.Errorf("logins = %d, want a reconnect", h.logins)
+	}
+}
+
+func TestPool_SeparatesAccountsAndReleases(t *testing.T) {
+	pool := NewPool(time.Minute)
+	cfg := Config{Host: "sftp.example.com", Username: "a", Password: "p"}
+	pool.Client("cam-1", cfg)
+	pool.Client("cam-2", cfg)
+	cfg.Username = "b"
+	pool.Client("cam-3", cfg)
+	if stats := pool.Stats(); len(stats) != 2 || stats[0].Account != "a@sftp.example.com:22" || len(stats[0].Cameras) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	// New credentials move the camera; an account nobody uses is dropped
+	pool.Client("cam-3", Config{Host: "sftp.example.com", Username: "a", Password: "p"})
+	if stats := pool.Stats(); len(stats) != 1 || len(stats[0].Cameras) != 3 {
+		t.Fatalf("after move: %+v", stats)
+	}
+	for _, id := range []string{"cam-1", "cam-2", "cam-3"} {
+		pool.Release(id)
+	}
+	if stats := pool.Stats(); len(stats) != 0 {
+		t.Errorf("after release: %+v", stats)
+	}
+}
+
+func TestAccount_RoundRobin(t *testing.T) {
+	a := &account{conn: &SFTPClient{}, waiting: make(map[string][]chan struct{}), idleTimeout: time.Minute}
+	a.acquire("holder")
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(cameraID, label string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.acquire(cameraID)
+			mu.Lock()
+			order = append(order, label)
+			mu.Unlock()
+			a.release()
+		}()
+		// Wait until queued so arrival order is deterministic
+		for {
+			a.mu.Lock()
+			n := len(a.waiting[cameraID])
+			a.mu.Unlock()
+			if n > 0 && (label != "a2" || n == 2) {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	enqueue("a", "a1")
+	enqueue("a", "a2")
+	enqueue("b", "b1")
+
+	a.release()
+	wg.Wait()
+	if want := []string{"a1", "b1", "a2"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,86 @@
+package upload
+
+import (
+	"errors"
+	"io"
+)
+
+// errSessionClosed means an upload was attempted on a closed session
+var errSessionClosed = errors.New("upload session closed")
+
+// errSessionBroken means an upload was attempted after one failed in the session
+var errSessionBroken = errors.New("upload session broken by a failed upload")
+
+// OpenSession connects and holds the client until the session is closed
+func (c *SFTPClient) OpenSession() (Session, error) {
+	c.mu.Lock()
+	if err := c.connect(); err != nil {
+		c.forgetDirs() // The server may come back with a different tree
+		c.mu.Unlock()
+		return nil, err
+	}
+	return &sftpSession{client: c}, nil
+}
+
+// sftpSession uploads over an SFTPClient's connection, holding its lock
+type sftpSession struct {
+	client *SFTPClient
+	broken bool // An upload failed; the connection may be gone
+	closed bool
+}
+
+func (s *sftpSession) Upload(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	c := s.client
+	if s.closed {
+		return errSessionClosed
+	}
+	if s.broken {
+		return errSessionBroken
+	}
+	err := c.put(c.config, remotePath, data, throttle)
+	s.broken = err != nil
+	return err
+}
+
+func (s *sftpSession) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.client.Close()
+	s.client.mu.Unlock()
+	return err
+}
+
+// OpenSession waits for the camera's turn on the shared connection and keeps it
+// until the session is closed
+func (c *pooledClient) OpenSession() (Session, error) {
+	c.account.acquire(c.cameraID)
+	if err := c.account.connect(); err != nil {
+		c.account.release()
+		return nil, err
+	}
+	return &pooledSession{client: c}, nil
+}
+
+// pooledSession uploads over a shared connection during one camera's turn. The
+// connection stays open for other cameras after Close.
+type pooledSession struct {
+	client *pooledClient
+	closed bool
+}
+
+func (s *pooledSession) Upload(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	if s.closed {
+		return errSessionClosed
+	}
+	return s.client.account.upload(s.client.config, remotePath, data, throttle)
+}
+
+func (s *pooledSession) Close() error {
+	if !s.closed {
+		s.closed = true
+		s.client.account.release()
+	}
+	return nil
+}
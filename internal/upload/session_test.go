@@ -0,0 +1,77 @@
+package upload
+
+import (
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestSFTPClient_SessionUsesOneLogin(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	session, err := client.OpenSession()
+	if err != nil {
+		t.Fatalf("OpenSession: %v", err)
+	}
+	paced := 0
+	throttle := func(r io.Reader) io.Reader { paced++; return r }
+	for i := 0; i < 3; i++ {
+		if err := session.Upload(fmt.Sprintf("cam/%d.jpg", i), []byte("jpeg"), throttle); err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	if err := session.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if err := session.Upload("cam/late.jpg", []byte("jpeg"), nil); err == nil {
+		t.Error("expected an upload on a closed session to fail")
+	}
+
+	h.mu.Lock()
+	logins := h.logins
+	h.mu.Unlock()
+	if logins != 1 || paced != 3 {
+		t.Errorf("logins = %d, paced = %d; want 1 login and 3 paced uploads", logins, paced)
+	}
+
+	// The client is released for ordinary uploads
+	if err := client.Upload("cam/after.jpg", []byte("jpeg")); err != nil {
+		t.Fatalf("upload after session: %v", err)
+	}
+}
+
+func TestPool_SessionKeepsTurn(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	pool := NewPool(0)
+	defer pool.Close()
+	client, err := pool.Client("north", Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files/north"})
+	if err != nil {
+		t.Fatalf("Client: %v", err)
+	}
+
+	session, err := client.(Sessioner).OpenSession()
+	if err != nil {
+		t.Fatalf("OpenSession: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := session.Upload(fmt.Sprintf("%d.jpg", i), []byte("jpeg"), nil); err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	_ = session.Close()
+	_ = session.Close() // Closing twice releases the turn once
+
+	if err := client.Upload("after.jpg", []byte("jpeg")); err != nil {
+		t.Fatalf("upload after session: %v", err)
+	}
+	h.mu.Lock()
+	logins := h.logins
+	h.mu.Unlock()
+	if logins != 1 {
+		t.Errorf("logins = %d, want 1", logins)
+	}
+}
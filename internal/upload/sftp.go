@@ -1,7 +1,13 @@
 package upload
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
 	"path"
 	"strings"
 	"sync"
@@ -18,6 +24,10 @@ type SFTPClient struct {
 	config     Config
 	sshClient  *ssh.Client
 	sftpClient *sftp.Client
+
+	// Remote directories already ensured to exist. Kept across uploads until a
+	// connection fails or a directory turns out to be missing
+	ensuredDirs map[string]bool
 }
 
 // NewSFTPClient creates a new SFTP upload client
@@ -47,43 +57,82 @@ func (c *SFTPClient) Upload(remotePath string, data []byte) error {
 
 	// Connect
 	if err := c.connect(); err != nil {
-		return fmt.Errorf("connection failed: %w", err)
+		c.forgetDirs() // The server may come back with a different tree
+		return err
 	}
 	defer func() { _ = c.Close() }() // Best-effort cleanup
 
+	return c.put(c.config, remotePath, data, nil)
+}
+
+// UploadThrottled is Upload with the data sent through throttle
+func (c *SFTPClient) UploadThrottled(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		c.forgetDirs() // The server may come back with a different tree
+		return err
+	}
+	defer func() { _ = c.Close() }() // Best-effort cleanup
+
+	return c.put(c.config, remotePath, data, throttle)
+}
+
+// put writes data over the open connection using cfg's base path and options, which
+// may belong to another camera sharing this connection (see Pool). A non-nil throttle
+// paces the write.
+func (c *SFTPClient) put(cfg Config, remotePath string, data []byte, throttle func(io.Reader) io.Reader) error {
 	// Normalize remote path and prepend base path
 	// Use path.Join (not filepath.Join) because SFTP always uses forward slashes
 	remotePath = normalizeRemotePath(remotePath)
-	if c.config.BasePath != "" {
-		remotePath = path.Join(c.config.BasePath, remotePath)
+	if cfg.BasePath != "" {
+		remotePath = path.Join(cfg.BasePath, remotePath)
 	}
 
 	// Create remote directory if needed
 	remoteDir := path.Dir(remotePath)
-	if err := c.sftpClient.MkdirAll(remoteDir); err != nil {
-		// Log but continue - directory may already exist, or we may not have permission
-		// to create parent directories but can still write to existing ones
-		_ = err // Best-effort directory creation
+	cached := c.ensuredDirs[remoteDir]
+	if !cached {
+		c.ensureDir(remoteDir, cfg.MkdirEveryUpload)
 	}
 
 	// Atomic upload: write to .tmp, then rename
 	tmpPath := fmt.Sprintf("%s.tmp.%d", remotePath, time.Now().UnixNano())
 
 	remote, err := c.sftpClient.Create(tmpPath)
+	if err != nil && cached && errors.Is(err, os.ErrNotExist) {
+		// The directory vanished since it was ensured; recreate it and retry once
+		delete(c.ensuredDirs, remoteDir)
+		c.ensureDir(remoteDir, cfg.MkdirEveryUpload)
+		remote, err = c.sftpClient.Create(tmpPath)
+	}
 	if err != nil {
 		return fmt.Errorf("create remote file: %w", err)
 	}
 
 	// Write data
-	_, err = remote.Write(data)
+	if throttle != nil {
+		_, err = io.Copy(remote, throttle(bytes.NewReader(data)))
+	} else {
+		_, err = remote.Write(data)
+	}
 	_ = remote.Close() // Close before checking write error
 	if err != nil {
 		_ = c.sftpClient.Remove(tmpPath) // Cleanup on failure (best-effort)
 		return fmt.Errorf("upload failed: %w", err)
 	}
 
-	// Atomic rename
-	if err := c.sftpClient.Rename(tmpPath, remotePath); err != nil {
+	// Verify before the rename so a truncated file never appears under its final name
+	if cfg.VerifySize {
+		if err := c.verifySize(tmpPath, remotePath, int64(len(data))); err != nil {
+			_ = c.sftpClient.Remove(tmpPath) // Best-effort cleanup
+			return err
+		}
+	}
+
+	// Atomic rename, replacing any existing file (e.g. a stable "latest" name)
+	if err := c.rename(tmpPath, remotePath); err != nil {
 		_ = c.sftpClient.Remove(tmpPath) // Cleanup on rename failure (best-effort)
 		return fmt.Errorf("rename failed: %w", err)
 	}
@@ -91,6 +140,62 @@ func (c *SFTPClient) Upload(remotePath string, data []byte) error {
 	return nil
 }
 
+// rename moves tmpPath to remotePath, replacing an existing file. posix-rename is
+// atomic; servers without it get remove-then-rename, briefly leaving no file.
+func (c *SFTPClient) rename(tmpPath, remotePath string) error {
+	if _, ok := c.sftpClient.HasExtension("posix-rename@openssh.com"); ok {
+		return c.sftpClient.PosixRename(tmpPath, remotePath)
+	}
+	err := c.sftpClient.Rename(tmpPath, remotePath)
+	if err == nil {
+		return nil
+	}
+	if _, statErr := c.sftpClient.Stat(remotePath); statErr != nil {
+		return err
+	}
+	if err := c.sftpClient.Remove(remotePath); err != nil {
+		return err
+	}
+	return c.sftpClient.Rename(tmpPath, remotePath)
+}
+
+// verifySize checks the uploaded temp file holds exactly size bytes
+func (c *SFTPClient) verifySize(tmpPath, remotePath string, size int64) error {
+	info, err := c.sftpClient.Stat(tmpPath)
+	if err != nil {
+		return &UploadError{RemotePath: remotePath, Message: "verify size", Err: err}
+	}
+	if info.Size() != size {
+		return &UploadError{
+			RemotePath: remotePath,
+			Message:    fmt.Sprintf("remote has %d bytes, sent %d", info.Size(), size),
+			Err:        ErrSizeMismatch,
+		}
+	}
+	return nil
+}
+
+// ensureDir creates remoteDir if needed and remembers it on success, unless
+// mkdirEveryUpload is set
+func (c *SFTPClient) ensureDir(remoteDir string, mkdirEveryUpload bool) {
+	if err := c.sftpClient.MkdirAll(remoteDir); err != nil {
+		// Continue - directory may already exist, or we may not have permission
+		// to create parent directories but can still write to existing ones
+		return
+	}
+	if !mkdirEveryUpload {
+		if c.ensuredDirs == nil {
+			c.ensuredDirs = make(map[string]bool)
+		}
+		c.ensuredDirs[remoteDir] = true
+	}
+}
+
+// forgetDirs drops the ensured directory cache so the next upload re-creates them
+func (c *SFTPClient) forgetDirs() {
+	clear(c.ensuredDirs)
+}
+
 // TestConnection tests the SFTP connection and authentication
 func (c *SFTPClient) TestConnection() error {
 	c.mu.Lock()
@@ -101,10 +206,102 @@ func (c *SFTPClient) TestConnection() error {
 	}
 	defer func() { _ = c.Close() }() // Best-effort cleanup
 
+	return c.statBase(c.config.BasePath)
+}
+
+// ListDir lists the files in remoteDir under the base path
+func (c *SFTPClient) ListDir(remoteDir string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = c.Close() }() // Best-effort cleanup
+
+	return c.list(c.config, remoteDir)
+}
+
+// list reads remoteDir over the open connection using cfg's base path
+func (c *SFTPClient) list(cfg Config, remoteDir string) ([]string, error) {
+	remoteDir = normalizeRemotePath(remoteDir)
+	if cfg.BasePath != "" {
+		remoteDir = path.Join(cfg.BasePath, remoteDir)
+	}
+	entries, err := c.sftpClient.ReadDir(remoteDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list remote directory: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Mode().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// RoundTripResult describes a test upload of a real image
+type RoundTripResult struct {
+	RemotePath string `json:"remote_path"`
+	Bytes      int    `json:"bytes"`
+	Deleted    bool   `json:"deleted"` // False when kept, or when removing it failed
+	DeleteErr  string `json:"delete_error,omitempty"`
+}
+
+// TestRoundTrip uploads a tiny JPEG under a temporary name in the base path, the
+// same way as real uploads (temp file, then rename), checks its remote size, and
+// removes it unless keep is set. Unlike TestConnection this proves the account can
+// write there. A failed removal is reported but does not fail the test.
+func (c *SFTPClient) TestRoundTrip(keep bool) (RoundTripResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.connect(); err != nil {
+		return RoundTripResult{}, fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { _ = c.Close() }() // Best-effort cleanup
+
+	data := testJPEG()
+	name := fmt.Sprintf("aviationwx-bridge-test-%d.jpg", time.Now().UnixNano())
+	cfg := c.config
+	cfg.VerifySize = true
+	if err := c.put(cfg, name, data, nil); err != nil {
+		return RoundTripResult{}, fmt.Errorf("test upload: %w", err)
+	}
+
+	result := RoundTripResult{RemotePath: path.Join(c.config.BasePath, name), Bytes: len(data)}
+	if keep {
+		return result, nil
+	}
+	if err := c.sftpClient.Remove(result.RemotePath); err != nil {
+		result.DeleteErr = err.Error()
+	} else {
+		result.Deleted = true
+	}
+	return result, nil
+}
+
+// testJPEG returns a small gray JPEG for test uploads
+func testJPEG() []byte {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	var buf bytes.Buffer
+	_ = jpeg.Encode(&buf, img, nil) // Cannot fail writing to memory
+	return buf.Bytes()
+}
+
+// statBase verifies the open connection can see basePath
+func (c *SFTPClient) statBase(basePath string) error {
 	// Try to stat the base path directory to verify connection works
 	testPath := "."
-	if c.config.BasePath != "" {
-		testPath = c.config.BasePath
+	if basePath != "" {
+		testPath = basePath
 	}
 	if _, err := c.sftpClient.Stat(testPath); err != nil {
 		return fmt.Errorf("connection test failed (path: %s): %w", testPath, err)
@@ -113,7 +310,7 @@ func (c *SFTPClient) TestConnection() error {
 	return nil
 }
 
-// connect establishes SSH and SFTP connections
+// connect establishes SSH and SFTP connections. Failures are *ConnectionError.
 func (c *SFTPClient) connect() error {
 	// SSH client config
 	timeout := time.Duration(c.config.TimeoutConnectSeconds) * time.Second
@@ -135,14 +332,14 @@ func (c *SFTPClient) connect() error {
 	var err error
 	c.sshClient, err = ssh.Dial("tcp", addr, sshConfig)
 	if err != nil {
-		return fmt.Errorf("ssh dial: %w", err)
+		return &ConnectionError{Message: "ssh dial", Err: err}
 	}
 
 	// Open SFTP session
 	c.sftpClient, err = sftp.NewClient(c.sshClient)
 	if err != nil {
 		_ = c.sshClient.Close() // Best-effort cleanup on SFTP session failure
-		return fmt.Errorf("sftp session: %w", err)
+		return &ConnectionError{Message: "sftp session", Err: err}
 	}
 
 	return nil
@@ -168,6 +365,7 @@ func (c *SFTPClient) Close() error {
 			errs = append(errs, fmt.Errorf("ssh close: %w", err))
 		}
 	}
+	c.sftpClient, c.sshClient = nil, nil
 
 	if len(errs) > 0 {
 		return fmt.Errorf("close errors: %v", errs)
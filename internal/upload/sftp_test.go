@@ -1,8 +1,20 @@
 package upload
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
 	"sync"
 	"testing"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestNewSFTPClient(t *testing.T) {
@@ -174,3 +186,336 @@ func TestSFTPClient_Close(t *testing.T) {
 		t.Errorf("Close() without connection returned error: %v", err)
 	}
 }
+
+// countingHandlers wraps the in-memory SFTP backend, counting directory checks and
+// optionally failing writes as if the target directory had been removed, or silently
+// dropping the last byte of writes
+type countingHandlers struct {
+	mem sftp.Handlers
+
+	mu            sync.Mutex
+	logins        int
+	stats         int
+	mkdirs        int
+	missingPuts   int
+	truncatedPuts int
+}
+
+func (h *countingHandlers) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	return h.mem.FileGet.Fileread(r)
+}
+
+func (h *countingHandlers) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	h.mu.Lock()
+	missing := h.missingPuts > 0
+	if missing {
+		h.missingPuts--
+	}
+	h.mu.Unlock()
+	if missing {
+		return nil, os.ErrNotExist
+	}
+	w, err := h.mem.FilePut.Filewrite(r)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil && h.truncatedPuts > 0 {
+		h.truncatedPuts--
+		return truncatingWriter{w}, nil
+	}
+	return w, err
+}
+
+// truncatingWriter loses the last byte of every write while reporting success
+type truncatingWriter struct{ w io.WriterAt }
+
+func (t truncatingWriter) WriteAt(p []byte, off int64) (int, error) {
+	if len(p) > 0 {
+		if _, err := t.w.WriteAt(p[:len(p)-1], off); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (h *countingHandlers) Filecmd(r *sftp.Request) error {
+	if r.Method == "Mkdir" {
+		h.mu.Lock()
+		h.mkdirs++
+		h.mu.Unlock()
+	}
+	return h.mem.FileCmd.Filecmd(r)
+}
+
+// PosixRename passes the overwriting rename extension through to the backend
+func (h *countingHandlers) PosixRename(r *sftp.Request) error {
+	return h.mem.FileCmd.(sftp.PosixRenameFileCmder).PosixRename(r)
+}
+
+func (h *countingHandlers) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if r.Method == "Stat" {
+		h.mu.Lock()
+		h.stats++
+		h.mu.Unlock()
+	}
+	return h.mem.FileList.Filelist(r)
+}
+
+func (h *countingHandlers) counts() (stats, mkdirs int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stats, h.mkdirs
+}
+
+// newTestSFTPServer serves an in-memory SFTP filesystem on a loopback port
+func newTestSFTPServer(t *testing.T) (*countingHandlers, int) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate host key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("host key signer: %v", err)
+	}
+	h := &countingHandlers{mem: sftp.InMemHandler()}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "test" && string(pass) == "test" {
+				h.mu.Lock()
+				h.logins++
+				h.mu.Unlock()
+				return nil, nil
+			}
+			return nil, fmt.Errorf("denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	handlers := sftp.Handlers{FileGet: h, FilePut: h, FileCmd: h, FileList: h}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSFTP(conn, config, handlers)
+		}
+	}()
+	return h, listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveSFTP(conn net.Conn, config *ssh.ServerConfig, handlers sftp.Handlers) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			newChan.Reject(ssh.UnknownChannelType, "unsupported")
+			continue
+		}
+		ch, chReqs, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for req := range chReqs {
+				ok := req.Type == "subsystem" && string(req.Payload[4:]) == "sftp"
+				req.Reply(ok, nil)
+				if ok {
+					server := sftp.NewRequestServer(ch, handlers)
+					go func() {
+						server.Serve()
+						server.Close()
+					}()
+				}
+			}
+		}()
+	}
+}
+
+func TestSFTPClient_EnsuresDirectoryOnce(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.Upload(fmt.Sprintf("cam/%d.jpg", i), []byte("jpeg")); err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	stats, mkdirs := h.counts()
+	if mkdirs != 2 {
+		t.Errorf("mkdirs = %d, want 2 (/files and /files/cam)", mkdirs)
+	}
+	statsAfterFirst := stats
+
+	// A missing directory is recreated and the upload retried
+	h.mu.Lock()
+	h.missingPuts = 1
+	h.mu.Unlock()
+	if err := client.Upload("cam/vanished.jpg", []byte("jpeg")); err != nil {
+		t.Fatalf("upload after directory vanished: %v", err)
+	}
+	if stats, _ := h.counts(); stats != statsAfterFirst+1 {
+		t.Errorf("stats = %d, want one re-check after the missing directory (%d)", stats, statsAfterFirst+1)
+	}
+}
+
+func TestSFTPClient_MkdirEveryUpload(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", MkdirEveryUpload: true})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := client.Upload(fmt.Sprintf("cam/%d.jpg", i), []byte("jpeg")); err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	if stats, _ := h.counts(); stats < 3 {
+		t.Errorf("stats = %d, want the directory checked on every upload", stats)
+	}
+}
+
+func TestSFTPClient_VerifySize(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files", VerifySize: true})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	if err := client.Upload("cam/ok.jpg", []byte("jpeg")); err != nil {
+		t.Fatalf("verified upload: %v", err)
+	}
+
+	h.mu.Lock()
+	h.truncatedPuts = 1
+	h.mu.Unlock()
+	err = client.Upload("cam/short.jpg", []byte("jpeg"))
+	if !errors.Is(err, ErrSizeMismatch) {
+		t.Fatalf("expected ErrSizeMismatch, got %v", err)
+	}
+
+	if err := client.connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	if info, err := client.sftpClient.Stat("/files/cam/ok.jpg"); err != nil || info.Size() != 4 {
+		t.Errorf("verified file: %v, %v", info, err)
+	}
+	entries, _ := client.sftpClient.ReadDir("/files/cam")
+	if len(entries) != 1 {
+		t.Errorf("truncated upload left files behind: %d entries in /files/cam", len(entries))
+	}
+}
+
+func TestSFTPClient_UploadReplacesExistingFile(t *testing.T) {
+	_, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	for _, data := range []string{"first", "second frame"} {
+		if err := client.Upload("cam/latest.jpg", []byte(data)); err != nil {
+			t.Fatalf("upload %q: %v", data, err)
+		}
+	}
+
+	if err := client.connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	f, err := client.sftpClient.Open("/files/cam/latest.jpg")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	got, _ := io.ReadAll(f)
+	if string(got) != "second frame" {
+		t.Errorf("latest.jpg = %q, want the second upload", got)
+	}
+}
+
+func TestSFTPClient_TestRoundTrip(t *testing.T) {
+	_, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	result, err := client.TestRoundTrip(false)
+	if err != nil {
+		t.Fatalf("TestRoundTrip: %v", err)
+	}
+	if !result.Deleted || result.Bytes == 0 || !strings.HasPrefix(result.RemotePath, "/files/aviationwx-bridge-test-") {
+		t.Errorf("result = %+v", result)
+	}
+
+	kept, err := client.TestRoundTrip(true)
+	if err != nil || kept.Deleted {
+		t.Fatalf("TestRoundTrip(keep) = %+v, %v", kept, err)
+	}
+
+	if err := client.connect(); err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	entries, _ := client.sftpClient.ReadDir("/files")
+	if len(entries) != 1 || "/files/"+entries[0].Name() != kept.RemotePath {
+		t.Errorf("remote files = %v, want only the kept test image", entries)
+	}
+}
+
+func TestSFTPClient_TestRoundTripWriteDenied(t *testing.T) {
+	h, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+	// Login works but the write is rejected
+	h.mu.Lock()
+	h.missingPuts = 1
+	h.mu.Unlock()
+	if _, err := client.TestRoundTrip(false); err == nil {
+		t.Fatal("expected the round trip to fail when the server rejects the write")
+	}
+}
+
+func TestSFTPClient_ListDir(t *testing.T) {
+	_, port := newTestSFTPServer(t)
+	client, err := NewSFTPClient(Config{Host: "127.0.0.1", Port: port, Username: "test", Password: "test", BasePath: "/files"})
+	if err != nil {
+		t.Fatalf("NewSFTPClient: %v", err)
+	}
+
+	names, err := client.ListDir("cam")
+	if err != nil || len(names) != 0 {
+		t.Fatalf("ListDir() before any upload = %v, %v; want no files", names, err)
+	}
+	for _, name := range []string{"cam/1000.jpg", "cam/2000.jpg", "cam/sub/3000.jpg"} {
+		if err := client.Upload(name, []byte("frame")); err != nil {
+			t.Fatalf("upload %s: %v", name, err)
+		}
+	}
+	names, err = client.ListDir("/cam")
+	if err != nil {
+		t.Fatalf("ListDir: %v", err)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "1000.jpg,2000.jpg" {
+		t.Errorf("ListDir() = %v, want the two files and no subdirectory", names)
+	}
+}
@@ -1,13 +1,19 @@
 package upload
 
 import (
+	"errors"
+	"io"
 	"time"
 )
 
+// ErrSizeMismatch means the uploaded file's remote size differs from the local data
+var ErrSizeMismatch = errors.New("remote size does not match local size")
+
 // Client defines the interface for upload clients
 type Client interface {
 	// Upload uploads image data to the remote path using atomic operations
-	// Uploads to .tmp file first, then renames to final filename
+	// Uploads to .tmp file first, then renames to final filename, replacing any
+	// existing file of that name
 	// Returns error if upload or rename fails
 	Upload(remotePath string, data []byte) error
 
@@ -16,6 +22,46 @@ type Client interface {
 	TestConnection() error
 }
 
+// ThrottledUploader is implemented by clients that can pace the bytes they send.
+// throttle wraps the reader the data is sent from; it is applied afresh to every
+// attempt, so a retried upload is paced too.
+type ThrottledUploader interface {
+	UploadThrottled(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error
+}
+
+// Sessioner is implemented by clients that can keep one connection open for a run of
+// uploads, e.g. to drain a backlog without logging in for every frame
+type Sessioner interface {
+	// OpenSession connects and holds the client for the session's uploads until it
+	// is closed; other uploads through the client wait meanwhile
+	OpenSession() (Session, error)
+}
+
+// Session uploads over one open connection
+type Session interface {
+	// Upload is Client.Upload over the session's connection, paced by throttle unless
+	// it is nil. A failed upload ends the session: later ones fail, and the caller
+	// opens a new session so that the reconnect is spaced like any other login.
+	Upload(remotePath string, data []byte, throttle func(io.Reader) io.Reader) error
+
+	// Close closes the connection and releases the client
+	Close() error
+}
+
+// RoundTripTester is implemented by clients that can prove write access by uploading
+// a real test image
+type RoundTripTester interface {
+	TestRoundTrip(keep bool) (RoundTripResult, error)
+}
+
+// Lister is implemented by clients that can list a remote directory, for servers
+// that allow it
+type Lister interface {
+	// ListDir returns the names of the files in remoteDir, relative to the base path.
+	// A directory that does not exist yet has no files.
+	ListDir(remoteDir string) ([]string, error)
+}
+
 // Config represents upload configuration (SFTP)
 type Config struct {
 	Host                  string
@@ -25,6 +71,14 @@ type Config struct {
 	TimeoutConnectSeconds int
 	TimeoutUploadSeconds  int
 	BasePath              string // Base directory for uploads (default: /files)
+
+	// MkdirEveryUpload ensures the remote directory on every upload instead of once
+	// until the next connection failure or missing-directory error
+	MkdirEveryUpload bool
+
+	// VerifySize stats the uploaded file and fails the upload unless its remote size
+	// equals the local size, catching silent truncation
+	VerifySize bool
 }
 
 // Error types for upload operations
@@ -0,0 +1,92 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// forwardedMiddleware resolves the real client behind trusted reverse proxies.
+// When the connection comes from a trusted proxy, r.RemoteAddr is replaced with
+// the client address from X-Forwarded-For, and r.URL.Scheme is set from
+// X-Forwarded-Proto. Forwarded headers from anyone else are ignored, so clients
+// cannot spoof their address. Settings are read per request.
+func (s *Server) forwardedMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wc := s.configService.GetWebConsole()
+		if len(wc.TrustedProxies) > 0 {
+			// Validated on save; an unparseable list trusts nobody
+			if trusted, err := wc.TrustedProxyPrefixes(); err == nil {
+				if client, proto, ok := forwardedClient(r, trusted); ok {
+					r = r.Clone(r.Context())
+					r.RemoteAddr = net.JoinHostPort(client.String(), "0")
+					if proto == "http" || proto == "https" {
+						r.URL.Scheme = proto
+					}
+				}
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// forwardedClient returns the client address and protocol forwarded by a trusted
+// peer. X-Forwarded-For is read right to left, skipping trusted proxies, so the
+// client is the first address not appended by one of them.
+func forwardedClient(r *http.Request, trusted []netip.Prefix) (netip.Addr, string, bool) {
+	peer, ok := remoteAddr(r.RemoteAddr)
+	if !ok || !isTrusted(peer, trusted) {
+		return netip.Addr{}, "", false
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break // Garbage: keep the last address a trusted proxy vouched for
+		}
+		client = addr.Unmap()
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+
+	// Each proxy may append its own value; the first is the client's
+	proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+	return client, strings.ToLower(strings.TrimSpace(proto)), true
+}
+
+// remoteAddr parses an http.Request RemoteAddr
+func remoteAddr(s string) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(s)
+	if err != nil {
+		host = s
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the request's client address, after forwardedMiddleware
+func clientIP(r *http.Request) string {
+	if addr, ok := remoteAddr(r.RemoteAddr); ok {
+		return addr.String()
+	}
+	return r.RemoteAddr
+}
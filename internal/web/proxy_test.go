@@ -0,0 +1,90 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+func TestForwardedMiddleware(t *testing.T) {
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create config service: %v", err)
+	}
+	server := NewServer(ServerConfig{ConfigService: svc})
+
+	var gotIP, gotScheme string
+	handler := server.forwardedMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotIP, gotScheme = clientIP(r), r.URL.Scheme
+	}))
+	send := func(peer string, headers map[string]string) {
+		req := httptest.NewRequest("GET", "/api/status", nil)
+		req.RemoteAddr = peer
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		gotIP, gotScheme = "", ""
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	spoofed := map[string]string{"X-Forwarded-For": "203.0.113.9", "X-Forwarded-Proto": "https"}
+
+	// Off by default: forwarded headers are ignored
+	send("10.0.0.2:5000", spoofed)
+	if gotIP != "10.0.0.2" || gotScheme != "" {
+		t.Errorf("without trusted proxies: client %q scheme %q, want the peer", gotIP, gotScheme)
+	}
+
+	req := httptest.NewRequest("PUT", "/api/config", bytes.NewBufferString(
+		`{"web_console": {"port": 1229, "trusted_proxies": ["10.0.0.0/24", "::1"]}}`))
+	req.SetBasicAuth("admin", svc.GetWebPassword())
+	w := httptest.NewRecorder()
+	server.GetMux().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("setting trusted_proxies: got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		peer       string
+		headers    map[string]string
+		wantIP     string
+		wantScheme string
+	}{
+		{name: "trusted proxy", peer: "10.0.0.2:5000", headers: spoofed, wantIP: "203.0.113.9", wantScheme: "https"},
+		{name: "untrusted peer", peer: "192.0.2.7:5000", headers: spoofed, wantIP: "192.0.2.7"},
+		{
+			// The client prepended a fake address; the proxy appended the real one
+			name:    "spoofed hop before the real client",
+			peer:    "[::1]:5000",
+			headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.1, 10.0.0.5"},
+			wantIP:  "198.51.100.1",
+		},
+		{name: "no header", peer: "10.0.0.2:5000", wantIP: "10.0.0.2"},
+		{
+			name:    "garbage hop",
+			peer:    "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "not-an-ip", "X-Forwarded-Proto": "gopher"},
+			wantIP:  "10.0.0.2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			send(tt.peer, tt.headers)
+			if gotIP != tt.wantIP || gotScheme != tt.wantScheme {
+				t.Errorf("client %q scheme %q, want %q %q", gotIP, gotScheme, tt.wantIP, tt.wantScheme)
+			}
+		})
+	}
+
+	req = httptest.NewRequest("PUT", "/api/config", bytes.NewBufferString(
+		`{"web_console": {"port": 1229, "trusted_proxies": ["10.0.0.0/33"]}}`))
+	req.SetBasicAuth("admin", svc.GetWebPassword())
+	w = httptest.NewRecorder()
+	server.GetMux().ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid trusted_proxies: got %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,89 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// mjpegBoundary separates frames in the multipart MJPEG stream
+const mjpegBoundary = "aviationwx-frame"
+
+// Restream timing
+const (
+	restreamKeepalive    = 5 * time.Second  // Resend an unchanged frame this often so players do not time out
+	restreamWriteTimeout = 30 * time.Second // Per frame; replaces the server's whole-response write timeout
+)
+
+// handleCameraStream serves a camera's latest captured frames as MJPEG over HTTP
+// (multipart/x-mixed-replace), for displays that expect a video stream. Frames come
+// from the preview cache, so the stream never polls the camera itself; a frame is
+// sent when the preview changes, at most max_fps times a second.
+func (s *Server) handleCameraStream(w http.ResponseWriter, r *http.Request) {
+	cameraID := r.PathValue("id")
+	rs := s.configService.GetWebConsole().Restream
+	if rs == nil || !rs.Enabled {
+		http.Error(w, "Restream is disabled", http.StatusNotFound)
+		return
+	}
+	if _, err := s.configService.GetCamera(cameraID); err != nil {
+		http.Error(w, "Camera not found", http.StatusNotFound)
+		return
+	}
+	if s.getCameraImage == nil {
+		http.Error(w, "Preview not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	if n := s.streams.Add(1); int(n) > rs.EffectiveMaxClients() {
+		s.streams.Add(-1)
+		w.Header().Set("Retry-After", "10")
+		http.Error(w, "Too many stream clients", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.streams.Add(-1)
+
+	s.log.Info("Restream client connected", "camera", cameraID, "client", clientIP(r))
+	defer s.log.Info("Restream client disconnected", "camera", cameraID, "client", clientIP(r))
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+mjpegBoundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Connection", "close")
+	rc := http.NewResponseController(w)
+
+	ticker := time.NewTicker(time.Second / time.Duration(rs.EffectiveMaxFPS()))
+	defer ticker.Stop()
+	var last []byte
+	var lastSent time.Time
+	for {
+		frame, err := s.getCameraImage(cameraID)
+		if err == nil && len(frame) > 0 && (!bytes.Equal(frame, last) || time.Since(lastSent) >= restreamKeepalive) {
+			if err := writeMJPEGFrame(w, rc, frame); err != nil {
+				return
+			}
+			last, lastSent = frame, time.Now()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeMJPEGFrame writes one multipart part and flushes it to the client
+func writeMJPEGFrame(w http.ResponseWriter, rc *http.ResponseController, frame []byte) error {
+	_ = rc.SetWriteDeadline(time.Now().Add(restreamWriteTimeout)) // Unsupported by test recorders
+	if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", mjpegBoundary, len(frame)); err != nil {
+		return err
+	}
+	if _, err := w.Write(frame); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte("\r\n")); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
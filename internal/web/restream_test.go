@@ -0,0 +1,68 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+)
+
+func TestCameraStream(t *testing.T) {
+	fakeJPEG := []byte{0xFF, 0xD8, 0xFF, 0xD9}
+	server := testServerWithAuth(t, ServerConfig{
+		GetCameraImage: func(string) ([]byte, error) { return fakeJPEG, nil },
+	})
+	svc := server.configService
+	svc.AddCamera(config.Camera{
+		ID:      "stream-cam",
+		Name:    "Stream Test",
+		Type:    "http",
+		Enabled: true,
+		Upload:  &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+	})
+
+	stream := func(ctx context.Context, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil).WithContext(ctx)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	// Off by default
+	if w := stream(context.Background(), "/api/cameras/stream-cam/stream.mjpeg"); w.Code != http.StatusNotFound {
+		t.Fatalf("disabled restream returned %d, want 404", w.Code)
+	}
+
+	if err := svc.UpdateGlobal(func(g *config.GlobalSettings) error {
+		g.WebConsole.Restream = &config.Restream{Enabled: true, MaxFPS: 5, MaxClients: 1}
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+	if w := stream(context.Background(), "/api/cameras/missing/stream.mjpeg"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown camera returned %d, want 404", w.Code)
+	}
+
+	// The unchanged frame is sent once, not on every tick
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	w := stream(ctx, "/api/cameras/stream-cam/stream.mjpeg")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "multipart/x-mixed-replace") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if n := strings.Count(w.Body.String(), "--"+mjpegBoundary); n != 1 {
+		t.Errorf("sent %d frames, want 1", n)
+	}
+
+	// Past the client limit
+	server.streams.Add(1)
+	defer server.streams.Add(-1)
+	if w := stream(context.Background(), "/api/cameras/stream-cam/stream.mjpeg"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("over the client limit returned %d, want 503", w.Code)
+	}
+}
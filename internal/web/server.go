@@ -5,15 +5,21 @@ import (
 	"crypto/subtle"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/history"
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
 )
 
 //go:embed static/*
@@ -28,20 +34,43 @@ type Server struct {
 
 	// Callbacks to bridge services
 	getStatus       func() interface{}
+	getStatusPage   func(q StatusQuery) interface{}
+	getSummary      func() interface{}
 	testCamera      func(camConfig config.Camera) ([]byte, error)
 	testUpload      func(uploadConfig config.Upload) error
+	testRoundTrip   func(uploadConfig config.Upload, keep bool) (interface{}, error)
 	getCameraImage  func(cameraID string) ([]byte, error)
 	getWorkerStatus func(cameraID string) map[string]interface{}
+	getQuality      func(cameraID string) (interface{}, bool)
+	getHistory      func(cameraID string) ([]history.Frame, error)
+	getHistoryFrame func(cameraID string, ts int64) ([]byte, error)
+	exifToolVersion func() (string, error)
+	findExifTool    func() interface{}
+	metrics         http.Handler
+	limiter         *resource.Limiter
+
+	// Open MJPEG restream connections (web_console.restream)
+	streams atomic.Int32
 }
 
 // ServerConfig configures the web server
 type ServerConfig struct {
 	ConfigService   *config.Service
 	GetStatus       func() interface{}
+	GetStatusPage   func(q StatusQuery) interface{} // Status with a subset of cameras' stats
+	GetSummary      func() interface{}              // Compact fleet status for /api/summary
 	TestCamera      func(camConfig config.Camera) ([]byte, error)
 	TestUpload      func(uploadConfig config.Upload) error
+	TestRoundTrip   func(uploadConfig config.Upload, keep bool) (interface{}, error) // Uploads a test image
 	GetCameraImage  func(cameraID string) ([]byte, error)
 	GetWorkerStatus func(cameraID string) map[string]interface{}
+	GetQuality      func(cameraID string) (interface{}, bool)
+	GetHistory      func(cameraID string) ([]history.Frame, error) // Retained frames, newest first
+	GetHistoryFrame func(cameraID string, ts int64) ([]byte, error)
+	ExifToolVersion func() (string, error) // Reported in the support bundle
+	FindExifTool    func() interface{}     // Looks for a missing exiftool now (POST /api/exiftool/discover)
+	Metrics         http.Handler           // Served at /metrics when set
+	ResourceLimiter *resource.Limiter      // Optional: caps concurrent expensive requests
 }
 
 // NewServer creates a new web server
@@ -51,10 +80,20 @@ func NewServer(cfg ServerConfig) *Server {
 		mux:             http.NewServeMux(),
 		log:             logger.Default(),
 		getStatus:       cfg.GetStatus,
+		getStatusPage:   cfg.GetStatusPage,
+		getSummary:      cfg.GetSummary,
 		testCamera:      cfg.TestCamera,
 		testUpload:      cfg.TestUpload,
+		testRoundTrip:   cfg.TestRoundTrip,
 		getCameraImage:  cfg.GetCameraImage,
 		getWorkerStatus: cfg.GetWorkerStatus,
+		getQuality:      cfg.GetQuality,
+		getHistory:      cfg.GetHistory,
+		getHistoryFrame: cfg.GetHistoryFrame,
+		exifToolVersion: cfg.ExifToolVersion,
+		findExifTool:    cfg.FindExifTool,
+		metrics:         cfg.Metrics,
+		limiter:         cfg.ResourceLimiter,
 	}
 
 	s.setupRoutes()
@@ -63,18 +102,29 @@ func NewServer(cfg ServerConfig) *Server {
 
 func (s *Server) setupRoutes() {
 	// API routes (require auth)
-	s.mux.HandleFunc("/api/status", s.authMiddleware(s.handleStatus))
+	// Expensive handlers also go through the concurrency limiter
+	s.mux.HandleFunc("/api/status", s.authMiddleware(s.limitMiddleware(s.handleStatus)))
+	s.mux.HandleFunc("/api/summary", s.authMiddleware(s.limitMiddleware(s.handleSummary)))
 	s.mux.HandleFunc("/api/config", s.authMiddleware(s.handleConfig))
+	s.mux.HandleFunc("/api/config/validation", s.authMiddleware(s.handleConfigValidation))
 	s.mux.HandleFunc("/api/cameras", s.authMiddleware(s.handleCameras))
-	s.mux.HandleFunc("/api/cameras/", s.authMiddleware(s.handleCamera))
+	s.mux.HandleFunc("/api/cameras/", s.authMiddleware(s.limitMiddleware(s.handleCamera)))
+	// Long-lived; bounded by its own client limit rather than the request limiter
+	s.mux.HandleFunc("GET /api/cameras/{id}/stream.mjpeg", s.authMiddleware(s.handleCameraStream))
 	s.mux.HandleFunc("/api/time", s.authMiddleware(s.handleTime))
-	s.mux.HandleFunc("/api/test/camera", s.authMiddleware(s.handleTestCamera))
-	s.mux.HandleFunc("/api/test/upload", s.authMiddleware(s.handleTestUpload))
+	s.mux.HandleFunc("POST /api/exiftool/discover", s.authMiddleware(s.handleExifToolDiscover))
+	s.mux.HandleFunc("/api/test/camera", s.authMiddleware(s.limitMiddleware(s.handleTestCamera)))
+	s.mux.HandleFunc("/api/test/upload", s.authMiddleware(s.limitMiddleware(s.handleTestUpload)))
 	s.mux.HandleFunc("/api/update", s.authMiddleware(s.handleUpdate))
 
-	// Health check (no auth)
-	s.mux.HandleFunc("/healthz", s.handleHealthz)
-	s.mux.HandleFunc("/api/logs", s.authMiddleware(http.HandlerFunc(s.handleLogs)))
+	// Operational endpoints (protection configured per endpoint, default none).
+	// Health checks bypass the limiter so probes keep working under load.
+	s.mux.HandleFunc("/healthz", s.endpointAuthMiddleware(healthAuthMode, s.handleHealthz))
+	if s.metrics != nil {
+		s.mux.HandleFunc("/metrics", s.endpointAuthMiddleware(metricsAuthMode, s.limitMiddleware(s.metrics.ServeHTTP)))
+	}
+	s.mux.HandleFunc("/api/logs", s.authMiddleware(s.limitMiddleware(s.handleLogs)))
+	s.mux.HandleFunc("/api/support-bundle", s.authMiddleware(s.limitMiddleware(s.handleSupportBundle)))
 
 	// Static files (require auth except for login assets)
 	staticFS, _ := fs.Sub(staticFiles, "static")
@@ -82,12 +132,12 @@ func (s *Server) setupRoutes() {
 	s.mux.HandleFunc("/", s.staticMiddleware(fileServer))
 }
 
-// Start starts the web server
+// Start starts the web server. Under the localhost_only default password policy,
+// it listens on localhost only while the default password is in use.
 func (s *Server) Start() error {
-	port := s.configService.GetWebPort()
 	s.server = &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      s.mux,
+		Addr:         s.ListenAddr(),
+		Handler:      s.forwardedMiddleware(s.mux),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -95,6 +145,55 @@ func (s *Server) Start() error {
 	return s.server.ListenAndServe()
 }
 
+// ListenAddr returns the address Start listens on
+func (s *Server) ListenAddr() string {
+	port := s.configService.GetWebPort()
+	if s.configService.DefaultPasswordPolicy() == config.DefaultPasswordLocalhostOnly &&
+		s.configService.UsingDefaultWebPassword() {
+		return fmt.Sprintf("127.0.0.1:%d", port)
+	}
+	return fmt.Sprintf(":%d", port)
+}
+
+// blockedByDefaultPassword rejects a config change with 403 when the
+// require_change policy applies, returning true if it did
+func (s *Server) blockedByDefaultPassword(w http.ResponseWriter) bool {
+	if s.configService.DefaultPasswordPolicy() != config.DefaultPasswordRequireChange ||
+		!s.configService.UsingDefaultWebPassword() {
+		return false
+	}
+	http.Error(w, "Change the default web console password before saving configuration", http.StatusForbidden)
+	return true
+}
+
+// uploadCheckTimeout caps the connect timeout of the upload check run before saving
+// a camera
+const uploadCheckTimeout = 10 * time.Second
+
+// failedUploadCheck tests upload before a camera is saved, when the web console
+// checks uploads on save and the request has no ?skip_connectivity_check=true. It
+// writes a 400 and returns true if the server cannot be reached or rejects the login.
+func (s *Server) failedUploadCheck(w http.ResponseWriter, r *http.Request, cameraID string, upload config.Upload) bool {
+	if !s.configService.GetWebConsole().CheckUploadOnSave || s.testUpload == nil ||
+		r.URL.Query().Get("skip_connectivity_check") == "true" {
+		return false
+	}
+	if limit := int(uploadCheckTimeout.Seconds()); upload.TimeoutConnectSeconds <= 0 || upload.TimeoutConnectSeconds > limit {
+		upload.TimeoutConnectSeconds = limit
+	}
+	if err := s.testUpload(upload); err != nil {
+		s.log.Warn("Camera save rejected - upload check failed", "camera", cameraID, "host", upload.Host, "error", err)
+		http.Error(w, fmt.Sprintf("Upload check failed for %s:%d: %v", upload.Host, upload.Port, err), http.StatusBadRequest)
+		return true
+	}
+	return false
+}
+
+// changesWebPassword reports whether a settings update sets a non-default password
+func changesWebPassword(wc *config.WebConsole) bool {
+	return wc != nil && wc.Password != "" && wc.Password != config.DefaultWebPassword
+}
+
 // Stop stops the web server gracefully
 func (s *Server) Stop(ctx context.Context) error {
 	if s.server != nil {
@@ -118,6 +217,10 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		// Use constant-time comparison to prevent timing attacks
 		passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(expectedPassword)) == 1
 		if !ok || !passwordMatch {
+			if ok {
+				// Browsers probe without credentials first; only log real attempts
+				s.log.Warn("Web console login failed", "client", clientIP(r), "path", r.URL.Path)
+			}
 			w.Header().Set("WWW-Authenticate", `Basic realm="AviationWX.org Bridge"`)
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
@@ -126,6 +229,51 @@ func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// limitMiddleware rejects the request with 503 when too many expensive requests are in flight
+func (s *Server) limitMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	if s.limiter == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.limiter.TryAcquireWebRequest() {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Server busy, retry shortly", http.StatusServiceUnavailable)
+			return
+		}
+		defer s.limiter.ReleaseWebRequest()
+		next(w, r)
+	}
+}
+
+func healthAuthMode(wc config.WebConsole) string  { return wc.HealthAuth }
+func metricsAuthMode(wc config.WebConsole) string { return wc.MetricsAuth }
+
+// endpointAuthMiddleware protects an operational endpoint with the mode selected by
+// modeOf. Settings are read per request so changes apply without a restart.
+func (s *Server) endpointAuthMiddleware(modeOf func(config.WebConsole) string, next http.HandlerFunc) http.HandlerFunc {
+	basic := s.authMiddleware(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		wc := s.configService.GetWebConsole()
+		switch modeOf(wc) {
+		case "", config.EndpointAuthNone:
+			next(w, r)
+		case config.EndpointAuthToken:
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			// An unset token rejects everything rather than accepting an empty bearer
+			if !ok || wc.MetricsToken == "" ||
+				subtle.ConstantTimeCompare([]byte(token), []byte(wc.MetricsToken)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="AviationWX.org Bridge"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		default:
+			// basic, and any unrecognized mode, so a typo fails closed
+			basic(w, r)
+		}
+	}
+}
+
 func (s *Server) staticMiddleware(fileServer http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Allow access to root and static assets without auth for login page
@@ -157,11 +305,86 @@ func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := s.getStatus()
+	query, err := parseStatusQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var status interface{}
+	if query != (StatusQuery{}) && s.getStatusPage != nil {
+		if query.CameraID != "" {
+			if _, err := s.configService.GetCamera(query.CameraID); err != nil {
+				http.Error(w, "Camera not found", http.StatusNotFound)
+				return
+			}
+		}
+		status = s.getStatusPage(query)
+	} else {
+		status = s.getStatus()
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
 }
 
+// StatusQuery selects the cameras whose detailed stats a status response includes,
+// in camera ID order. The zero value includes every camera.
+type StatusQuery struct {
+	CameraID string // ?camera=: only this camera
+	Offset   int    // ?offset=: cameras skipped
+	Limit    int    // ?limit=: maximum cameras included (0 = no limit)
+}
+
+// parseStatusQuery reads the camera, offset and limit parameters of a status request
+func parseStatusQuery(r *http.Request) (StatusQuery, error) {
+	params := r.URL.Query()
+	q := StatusQuery{CameraID: params.Get("camera")}
+	for name, dst := range map[string]*int{"offset": &q.Offset, "limit": &q.Limit} {
+		v := params.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return StatusQuery{}, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		*dst = n
+	}
+	return q, nil
+}
+
+// handleSummary serves the compact fleet status for multi-bridge dashboards
+func (s *Server) handleSummary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.getSummary == nil {
+		http.Error(w, "Summary not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.getSummary())
+}
+
+// handleConfigValidation reports problems in the current configuration
+func (s *Server) handleConfigValidation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	problems := s.configService.Check()
+	if problems == nil {
+		problems = []config.Problem{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"valid":    len(problems) == 0,
+		"problems": problems,
+	})
+}
+
 func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -175,6 +398,25 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		if err := config.ValidateGlobal(updates.Global); err != nil {
+			http.Error(w, "Invalid config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := config.ValidateMQTT(updates.MQTT); err != nil {
+			http.Error(w, "Invalid config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := config.ValidateStatsD(updates.StatsD); err != nil {
+			http.Error(w, "Invalid config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := config.ValidateWebConsole(updates.WebConsole); err != nil {
+			http.Error(w, "Invalid config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !changesWebPassword(updates.WebConsole) && s.blockedByDefaultPassword(w) {
+			return
+		}
 
 		err := s.configService.UpdateGlobal(func(g *config.GlobalSettings) error {
 			// Update fields
@@ -193,6 +435,12 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 			if updates.SNTP != nil {
 				g.SNTP = updates.SNTP
 			}
+			if updates.MQTT != nil {
+				g.MQTT = updates.MQTT
+			}
+			if updates.StatsD != nil {
+				g.StatsD = updates.StatsD
+			}
 			return nil
 		})
 
@@ -236,6 +484,9 @@ func (s *Server) listCameras(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) addCamera(w http.ResponseWriter, r *http.Request) {
+	if s.blockedByDefaultPassword(w) {
+		return
+	}
 	var cam config.Camera
 	if err := json.NewDecoder(r.Body).Decode(&cam); err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
@@ -243,9 +494,14 @@ func (s *Server) addCamera(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
+	cam.ID = config.NormalizeCameraID(cam.ID)
 	if cam.ID == "" {
 		cam.ID = fmt.Sprintf("cam-%d", time.Now().Unix())
 	}
+	if err := config.ValidateCameraID(cam.ID); err != nil {
+		http.Error(w, "Invalid camera "+err.Error(), http.StatusBadRequest)
+		return
+	}
 	if cam.Name == "" {
 		cam.Name = cam.ID
 	}
@@ -253,10 +509,15 @@ func (s *Server) addCamera(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Upload credentials are required", http.StatusBadRequest)
 		return
 	}
+	// A hook runs a command on the host, so it is not settable over HTTP
+	if cam.PostCaptureHook != nil {
+		http.Error(w, "post_capture_hook can only be set in the config file", http.StatusBadRequest)
+		return
+	}
 
 	// Set defaults
-	if cam.CaptureIntervalSeconds == 0 {
-		cam.CaptureIntervalSeconds = 60
+	if cam.CaptureIntervalSeconds == 0 && cam.CapturesPerHour == 0 {
+		cam.CaptureIntervalSeconds = config.DefaultCaptureIntervalSeconds
 	}
 	if cam.Upload.Host == "" {
 		cam.Upload.Host = "upload.aviationwx.org"
@@ -264,6 +525,15 @@ func (s *Server) addCamera(w http.ResponseWriter, r *http.Request) {
 	if cam.Upload.Port == 0 {
 		cam.Upload.Port = 2222
 	}
+	// The same checks as the config file, so the console cannot save a camera the
+	// bridge would refuse to load
+	if err := config.ValidateCamera(&cam); err != nil {
+		http.Error(w, "Invalid camera: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.failedUploadCheck(w, r, cam.ID, *cam.Upload) {
+		return
+	}
 
 	// Add camera via ConfigService
 	if err := s.configService.AddCamera(cam); err != nil {
@@ -271,7 +541,11 @@ func (s *Server) addCamera(w http.ResponseWriter, r *http.Request) {
 			"camera", cam.ID,
 			"error", err,
 			"camera_type", cam.Type)
-		http.Error(w, fmt.Sprintf("Failed to add camera %s: %v", cam.ID, err), http.StatusInternalServerError)
+		status := http.StatusInternalServerError
+		if errors.Is(err, config.ErrCameraExists) {
+			status = http.StatusConflict
+		}
+		http.Error(w, fmt.Sprintf("Failed to add camera %s: %v", cam.ID, err), status)
 		return
 	}
 
@@ -301,6 +575,12 @@ func (s *Server) handleCamera(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case action == "preview" && r.Method == http.MethodGet:
 		s.getCameraPreview(w, r, cameraID)
+	case action == "quality" && r.Method == http.MethodGet:
+		s.getCameraQuality(w, r, cameraID)
+	case action == "history" && len(parts) == 2 && r.Method == http.MethodGet:
+		s.getCameraHistory(w, r, cameraID)
+	case action == "history" && len(parts) == 3 && r.Method == http.MethodGet:
+		s.getCameraHistoryFrame(w, r, cameraID, parts[2])
 	case action == "" && r.Method == http.MethodGet:
 		s.getCamera(w, r, cameraID)
 	case action == "" && r.Method == http.MethodPut:
@@ -325,40 +605,42 @@ func (s *Server) getCamera(w http.ResponseWriter, r *http.Request, cameraID stri
 }
 
 func (s *Server) updateCamera(w http.ResponseWriter, r *http.Request, cameraID string) {
-	var updates config.Camera
-	if err := json.NewDecoder(r.Body).Decode(&updates); err != nil {
+	if s.blockedByDefaultPassword(w) {
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	current, err := s.configService.GetCamera(cameraID)
+	if err != nil {
+		http.Error(w, "Camera not found", http.StatusNotFound)
+		return
+	}
+	updated, err := mergeCameraUpdate(*current, body)
+	if err != nil {
 		http.Error(w, "Invalid JSON: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := config.ValidateCamera(&updated); err != nil {
+		http.Error(w, "Invalid camera: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Only changed upload settings are checked, so other edits still save while the
+	// server is down
+	if updated.Upload != nil && (current.Upload == nil || *updated.Upload != *current.Upload) &&
+		s.failedUploadCheck(w, r, cameraID, *updated.Upload) {
+		return
+	}
 
-	err := s.configService.UpdateCamera(cameraID, func(cam *config.Camera) error {
-		// Preserve passwords if empty
-		if updates.Upload != nil && updates.Upload.Password == "" && cam.Upload != nil {
-			updates.Upload.Password = cam.Upload.Password
-		}
-		if updates.Auth != nil && updates.Auth.Password == "" && cam.Auth != nil {
-			updates.Auth.Password = cam.Auth.Password
-		}
-		if updates.RTSP != nil && updates.RTSP.Password == "" && cam.RTSP != nil {
-			updates.RTSP.Password = cam.RTSP.Password
-		}
-		if updates.ONVIF != nil && updates.ONVIF.Password == "" && cam.ONVIF != nil {
-			updates.ONVIF.Password = cam.ONVIF.Password
+	err = s.configService.UpdateCamera(cameraID, func(cam *config.Camera) error {
+		// Merged again under the lock, onto the camera as stored now
+		merged, err := mergeCameraUpdate(*cam, body)
+		if err != nil {
+			return err
 		}
-
-		// Update fields
-		cam.Name = updates.Name
-		cam.Type = updates.Type
-		cam.Enabled = updates.Enabled
-		cam.SnapshotURL = updates.SnapshotURL
-		cam.CaptureIntervalSeconds = updates.CaptureIntervalSeconds
-		cam.Auth = updates.Auth
-		cam.ONVIF = updates.ONVIF
-		cam.RTSP = updates.RTSP
-		cam.Image = updates.Image
-		cam.Upload = updates.Upload
-		cam.Queue = updates.Queue
-
+		*cam = merged
 		return nil
 	})
 
@@ -375,7 +657,83 @@ func (s *Server) updateCamera(w http.ResponseWriter, r *http.Request, cameraID s
 	json.NewEncoder(w).Encode(s.cameraToMap(*cam, global.Timezone))
 }
 
+// mergeCameraUpdate applies a camera PUT body to the stored camera. Settings the body
+// omits are kept, including fields of objects it does send (the console form shows
+// only some of them), and null removes a setting. Empty passwords keep the stored
+// ones, since the form never shows them.
+func mergeCameraUpdate(stored config.Camera, body []byte) (config.Camera, error) {
+	var sent map[string]json.RawMessage
+	if err := json.Unmarshal(body, &sent); err != nil {
+		return config.Camera{}, err
+	}
+
+	// Decode onto a deep copy, so objects shared with the stored camera are untouched
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return config.Camera{}, err
+	}
+	var cam config.Camera
+	if err := json.Unmarshal(data, &cam); err != nil {
+		return config.Camera{}, err
+	}
+	// The tunnel, wakeup request and diagnostics endpoint are replaced when sent,
+	// and {} removes them
+	if _, ok := sent["tunnel"]; ok {
+		cam.Tunnel = nil
+	}
+	if _, ok := sent["wakeup"]; ok {
+		cam.Wakeup = nil
+	}
+	if _, ok := sent["diagnostics"]; ok {
+		cam.Diagnostics = nil
+	}
+	if err := json.Unmarshal(body, &cam); err != nil {
+		return config.Camera{}, err
+	}
+	if cam.Tunnel != nil && cam.Tunnel.Host == "" {
+		cam.Tunnel = nil
+	}
+	if cam.Wakeup != nil && cam.Wakeup.URL == "" {
+		cam.Wakeup = nil
+	}
+	if cam.Diagnostics != nil && cam.Diagnostics.URL == "" {
+		cam.Diagnostics = nil
+	}
+
+	cam.ID = stored.ID
+	// PostCaptureHook is config-file only (see addCamera) and kept as is
+	cam.PostCaptureHook = stored.PostCaptureHook
+
+	if cam.Upload != nil && cam.Upload.Password == "" && stored.Upload != nil {
+		cam.Upload.Password = stored.Upload.Password
+	}
+	if cam.Auth != nil && cam.Auth.Password == "" && stored.Auth != nil {
+		cam.Auth.Password = stored.Auth.Password
+	}
+	if cam.RTSP != nil && cam.RTSP.Password == "" && stored.RTSP != nil {
+		cam.RTSP.Password = stored.RTSP.Password
+	}
+	if cam.Tunnel != nil && cam.Tunnel.Password == "" && stored.Tunnel != nil {
+		cam.Tunnel.Password = stored.Tunnel.Password
+	}
+	if cam.ONVIF != nil && cam.ONVIF.Password == "" && stored.ONVIF != nil {
+		cam.ONVIF.Password = stored.ONVIF.Password
+	}
+	if cam.Wakeup != nil && cam.Wakeup.Auth != nil && cam.Wakeup.Auth.Password == "" &&
+		stored.Wakeup != nil && stored.Wakeup.Auth != nil {
+		cam.Wakeup.Auth.Password = stored.Wakeup.Auth.Password
+	}
+	if cam.Diagnostics != nil && cam.Diagnostics.Auth != nil && cam.Diagnostics.Auth.Password == "" &&
+		stored.Diagnostics != nil && stored.Diagnostics.Auth != nil {
+		cam.Diagnostics.Auth.Password = stored.Diagnostics.Auth.Password
+	}
+	return cam, nil
+}
+
 func (s *Server) deleteCamera(w http.ResponseWriter, r *http.Request, cameraID string) {
+	if s.blockedByDefaultPassword(w) {
+		return
+	}
 	if err := s.configService.DeleteCamera(cameraID); err != nil {
 		http.Error(w, "Failed to delete camera: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -413,6 +771,89 @@ func (s *Server) getCameraPreview(w http.ResponseWriter, r *http.Request, camera
 	w.Write(imageData)
 }
 
+func (s *Server) getCameraQuality(w http.ResponseWriter, r *http.Request, cameraID string) {
+	if _, err := s.configService.GetCamera(cameraID); err != nil {
+		http.Error(w, "Camera not found", http.StatusNotFound)
+		return
+	}
+
+	if s.getQuality == nil {
+		http.Error(w, "Quality self-check not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	samples, ok := s.getQuality(cameraID)
+	if !ok {
+		http.Error(w, "Camera not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"camera_id": cameraID,
+		"samples":   samples,
+	})
+}
+
+func (s *Server) getCameraHistory(w http.ResponseWriter, r *http.Request, cameraID string) {
+	if _, err := s.configService.GetCamera(cameraID); err != nil {
+		http.Error(w, "Camera not found", http.StatusNotFound)
+		return
+	}
+
+	if s.getHistory == nil {
+		http.Error(w, "History not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	frames, err := s.getHistory(cameraID)
+	if err != nil {
+		http.Error(w, "Failed to read history: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if frames == nil {
+		frames = []history.Frame{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"camera_id": cameraID,
+		"frames":    frames,
+	})
+}
+
+func (s *Server) getCameraHistoryFrame(w http.ResponseWriter, r *http.Request, cameraID, tsParam string) {
+	ts, err := strconv.ParseInt(tsParam, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid frame timestamp", http.StatusBadRequest)
+		return
+	}
+	if _, err := s.configService.GetCamera(cameraID); err != nil {
+		http.Error(w, "Camera not found", http.StatusNotFound)
+		return
+	}
+
+	if s.getHistoryFrame == nil {
+		http.Error(w, "History not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	data, err := s.getHistoryFrame(cameraID, ts)
+	if errors.Is(err, history.ErrNotFound) {
+		http.Error(w, "Frame not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to read frame: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Frames never change once written, unlike the preview
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	w.Write(data)
+}
+
 func (s *Server) handleTime(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -427,6 +868,9 @@ func (s *Server) handleTime(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode(response)
 
 	case http.MethodPut:
+		if s.blockedByDefaultPassword(w) {
+			return
+		}
 		var update struct {
 			Timezone string `json:"timezone"`
 		}
@@ -454,6 +898,17 @@ func (s *Server) handleTime(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleExifToolDiscover looks for exiftool now instead of at the next periodic
+// check, so an install during setup takes effect straight away
+func (s *Server) handleExifToolDiscover(w http.ResponseWriter, r *http.Request) {
+	if s.findExifTool == nil {
+		http.Error(w, "exiftool discovery not available", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.findExifTool())
+}
+
 func (s *Server) handleTestCamera(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -493,6 +948,23 @@ func (s *Server) handleTestUpload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// ?round_trip=true uploads and removes a small test image (kept with keep=true)
+	// instead of only logging in, catching accounts that cannot write
+	if r.URL.Query().Get("round_trip") == "true" {
+		if s.testRoundTrip == nil {
+			http.Error(w, "Test not available", http.StatusServiceUnavailable)
+			return
+		}
+		result, err := s.testRoundTrip(upload, r.URL.Query().Get("keep") == "true")
+		if err != nil {
+			http.Error(w, "Upload test failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "round_trip": result})
+		return
+	}
+
 	if s.testUpload == nil {
 		http.Error(w, "Test not available", http.StatusServiceUnavailable)
 		return
@@ -695,15 +1167,139 @@ func (s *Server) cameraToMap(cam config.Camera, timezone string) map[string]inte
 	if cam.RTSP != nil {
 		result["rtsp"] = cam.RTSP
 	}
+	if cam.Folder != nil {
+		result["folder"] = cam.Folder
+	}
+	if cam.Agent != nil {
+		result["agent"] = cam.Agent
+	}
+	if cam.CapturesPerHour > 0 {
+		result["captures_per_hour"] = cam.CapturesPerHour
+	}
+	if cam.MinIntervalSeconds > 0 {
+		result["min_interval_seconds"] = cam.MinIntervalSeconds
+	}
+	if cam.Wakeup != nil {
+		result["wakeup"] = cam.Wakeup
+	}
+	if cam.Diagnostics != nil {
+		result["diagnostics"] = cam.Diagnostics
+	}
+	if cam.Tunnel != nil {
+		result["tunnel"] = cam.Tunnel
+	}
+	if cam.TLS != nil {
+		result["tls"] = cam.TLS
+	}
+	if cam.Rediscovery != nil {
+		result["rediscovery"] = cam.Rediscovery
+	}
+	if len(cam.FailOnHeaders) > 0 {
+		result["fail_on_headers"] = cam.FailOnHeaders
+	}
+	if cam.ConditionalRequests {
+		result["conditional_requests"] = true
+	}
+	if cam.MaxSnapshotKB > 0 {
+		result["max_snapshot_kb"] = cam.MaxSnapshotKB
+	}
+	if cam.Raw != nil {
+		result["raw"] = cam.Raw
+	}
 	if cam.Image != nil {
 		result["image"] = cam.Image
 	}
+	if cam.Thumbnail != nil {
+		result["thumbnail"] = cam.Thumbnail
+	}
+	if cam.Spectrogram != nil {
+		result["spectrogram"] = cam.Spectrogram
+	}
+	if len(cam.Regions) > 0 {
+		result["regions"] = cam.Regions
+	}
+	if cam.OfflineImagePath != "" {
+		result["offline_image_path"] = cam.OfflineImagePath
+		result["offline_after_seconds"] = cam.OfflineAfterSeconds
+	}
+	if cam.LogLevel != "" {
+		result["log_level"] = cam.LogLevel
+	}
+	if cam.TrimJPEG {
+		result["trim_jpeg"] = true
+	}
+	if cam.RepairJPEG {
+		result["repair_jpeg"] = true
+	}
+	if cam.DedupWindow > 0 {
+		result["dedup_window"] = cam.DedupWindow
+	}
+	if cam.MaxUploadAttempts > 0 {
+		result["max_upload_attempts"] = cam.MaxUploadAttempts
+	}
+	if cam.RemoteDedupMinutes > 0 {
+		result["remote_dedup_minutes"] = cam.RemoteDedupMinutes
+	}
+	if cam.UploadSizeBand != nil {
+		result["upload_size_band"] = cam.UploadSizeBand
+	}
+	if len(cam.FilenameTimeTokens) > 0 {
+		result["filename_time_tokens"] = cam.FilenameTimeTokens
+	}
+	if cam.UploadOutageSlowdown != nil {
+		result["upload_outage_slowdown"] = cam.UploadOutageSlowdown
+	}
+	if cam.PostCaptureHook != nil {
+		result["post_capture_hook"] = cam.PostCaptureHook
+	}
+	if cam.ExifStampRetries > 0 {
+		result["exif_stamp_retries"] = cam.ExifStampRetries
+	}
+	if cam.SettleDelaySeconds > 0 {
+		result["settle_delay_seconds"] = cam.SettleDelaySeconds
+	}
+	if cam.QualitySampleRate > 0 {
+		result["quality_sample_rate"] = cam.QualitySampleRate
+	}
+	if cam.ExifNote != "" {
+		result["exif_note"] = cam.ExifNote
+	}
+	if cam.JPEGComment {
+		result["jpeg_comment"] = true
+	}
+	if cam.ExifSequence {
+		result["exif_sequence"] = true
+	}
+	if cam.TimeSource != "" {
+		result["time_source"] = cam.TimeSource
+	}
 	if cam.Upload != nil {
 		result["upload"] = cam.Upload
 	}
 	if cam.Queue != nil {
 		result["queue"] = cam.Queue
 	}
+	if cam.CatchupMinutes > 0 {
+		result["catchup_minutes"] = cam.CatchupMinutes
+	}
+	if cam.LiveOnly {
+		result["live_only"] = true
+	}
+	if cam.UploadQuietHours != nil {
+		result["upload_quiet_hours"] = cam.UploadQuietHours
+	}
+	if cam.FreshnessSLASeconds > 0 {
+		result["freshness_sla_seconds"] = cam.FreshnessSLASeconds
+	}
+	if cam.LatestName != "" {
+		result["latest_name"] = cam.LatestName
+	}
+	if cam.History != nil {
+		result["history"] = cam.History
+	}
+	if cam.Timelapse != nil {
+		result["timelapse"] = cam.Timelapse
+	}
 
 	// Add worker status if available
 	if s.getWorkerStatus != nil {
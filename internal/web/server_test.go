@@ -6,11 +6,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/config"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/history"
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/resource"
 )
 
 // TestTimezoneUpdate tests the PUT /api/time endpoint
@@ -56,6 +59,102 @@ func TestTimezoneUpdate(t *testing.T) {
 	}
 }
 
+// TestGlobalConfigUpdate tests PUT /api/config validation of global settings
+func TestGlobalConfigUpdate(t *testing.T) {
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create config service: %v", err)
+	}
+	server := NewServer(ServerConfig{ConfigService: svc})
+
+	put := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("PUT", "/api/config", bytes.NewBufferString(body))
+		req.SetBasicAuth("admin", svc.GetWebPassword())
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := put(`{"global": {"upload_connection_interval_ms": 500}}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := svc.GetGlobal().Global.UploadConnectionIntervalMs; got != 500 {
+		t.Errorf("UploadConnectionIntervalMs = %d, want 500", got)
+	}
+
+	for _, body := range []string{
+		`{"global": {"upload_connection_interval_ms": -1}}`,
+		`{"global": {"upload_connection_interval_ms": 600000}}`,
+		`{"web_console": {"metrics_auth": "tokne"}}`,
+		`{"global": {"time_authority": {"unhealthy_policy": "pasue"}}}`,
+		`{"global": {"upload_quiet_hours": {"start": "25:00", "end": "03:00"}}}`,
+		`{"global": {"upload_quiet_hours": {"start": "", "end": "03:00"}}}`,
+		`{"global": {"upload_quiet_hours": {"start": "01:00", "end": "1:00"}}}`,
+	} {
+		if w := put(body); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, w.Code)
+		}
+	}
+	if got := svc.GetGlobal().Global.UploadConnectionIntervalMs; got != 500 {
+		t.Errorf("rejected update changed the interval to %d", got)
+	}
+}
+
+// TestDefaultPasswordPolicy tests that require_change blocks config changes until
+// the password is changed, and localhost_only narrows the listen address
+func TestDefaultPasswordPolicy(t *testing.T) {
+	svc, err := config.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create config service: %v", err)
+	}
+	server := NewServer(ServerConfig{ConfigService: svc})
+
+	send := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.SetBasicAuth("admin", svc.GetWebPassword())
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send("PUT", "/api/config", `{"web_console": {"port": 1229, "default_password_policy": "require_change"}}`); code != http.StatusOK {
+		t.Fatalf("setting policy: got %d", code)
+	}
+	if code := send("PUT", "/api/config", `{"global": {"upload_connection_interval_ms": 500}}`); code != http.StatusForbidden {
+		t.Errorf("config change with default password: got %d, want 403", code)
+	}
+	if code := send("POST", "/api/cameras", `{"id": "cam1"}`); code != http.StatusForbidden {
+		t.Errorf("camera add with default password: got %d, want 403", code)
+	}
+	if code := send("GET", "/api/config", ""); code != http.StatusOK {
+		t.Errorf("reading config: got %d, want 200", code)
+	}
+	if server.ListenAddr() != ":1229" {
+		t.Errorf("ListenAddr() = %q, want all interfaces", server.ListenAddr())
+	}
+
+	// Changing the password itself is allowed, and lifts the block
+	if code := send("PUT", "/api/config", `{"web_console": {"port": 1229, "password": "s3cret", "default_password_policy": "localhost_only"}}`); code != http.StatusOK {
+		t.Fatalf("changing password: got %d", code)
+	}
+	if code := send("PUT", "/api/config", `{"global": {"upload_connection_interval_ms": 500}}`); code != http.StatusOK {
+		t.Errorf("config change after password change: got %d, want 200", code)
+	}
+	if server.ListenAddr() != ":1229" {
+		t.Errorf("ListenAddr() = %q with a changed password, want all interfaces", server.ListenAddr())
+	}
+
+	if code := send("PUT", "/api/config", `{"web_console": {"port": 1229, "default_password_policy": "localhost_only"}}`); code != http.StatusOK {
+		t.Fatalf("restoring default password: got %d", code)
+	}
+	if server.ListenAddr() != "127.0.0.1:1229" {
+		t.Errorf("ListenAddr() = %q, want localhost with the default password", server.ListenAddr())
+	}
+	if code := send("PUT", "/api/config", `{"web_console": {"default_password_policy": "lax"}}`); code != http.StatusBadRequest {
+		t.Errorf("unknown policy: got %d, want 400", code)
+	}
+}
+
 // TestCameraAddUpdateDelete tests full camera lifecycle
 func TestCameraAddUpdateDelete(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -556,6 +655,40 @@ func TestHandleTestUpload(t *testing.T) {
 		}
 	})
 
+	t.Run("round trip uploads a test image", func(t *testing.T) {
+		var gotKeep bool
+		server := testServerWithAuth(t, ServerConfig{
+			TestUpload: func(config.Upload) error {
+				t.Error("login-only test used for a round trip")
+				return nil
+			},
+			TestRoundTrip: func(_ config.Upload, keep bool) (interface{}, error) {
+				gotKeep = keep
+				return map[string]interface{}{"remote_path": "/files/test.jpg", "deleted": false}, nil
+			},
+		})
+
+		uploadJSON := `{"host":"upload.example.com","port":2222,"username":"u","password":"p"}`
+		req := httptest.NewRequest("POST", "/api/test/upload?round_trip=true&keep=true", bytes.NewBufferString(uploadJSON))
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var result struct {
+			Status    string                 `json:"status"`
+			RoundTrip map[string]interface{} `json:"round_trip"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if result.Status != "ok" || result.RoundTrip["remote_path"] != "/files/test.jpg" || !gotKeep {
+			t.Errorf("Unexpected result %+v (keep=%v)", result, gotKeep)
+		}
+	})
+
 	t.Run("failure returns 500", func(t *testing.T) {
 		server := testServerWithAuth(t, ServerConfig{
 			TestUpload: func(config.Upload) error {
@@ -575,3 +708,647 @@ func TestHandleTestUpload(t *testing.T) {
 		}
 	})
 }
+
+// TestCameraSaveUploadCheck tests check_upload_on_save for added and updated cameras
+func TestCameraSaveUploadCheck(t *testing.T) {
+	var tested []config.Upload
+	uploadErr := fmt.Errorf("connection refused")
+	server := testServerWithAuth(t, ServerConfig{
+		TestUpload: func(u config.Upload) error {
+			tested = append(tested, u)
+			return uploadErr
+		},
+	})
+	if err := server.configService.UpdateGlobal(func(g *config.GlobalSettings) error {
+		g.WebConsole.CheckUploadOnSave = true
+		return nil
+	}); err != nil {
+		t.Fatalf("UpdateGlobal: %v", err)
+	}
+
+	save := func(method, path, host, password string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"id":"cam","name":"Cam","type":"http","enabled":true,"snapshot_url":"http://cam/snap.jpg",
+			"capture_interval_seconds":60,"upload":{"host":%q,"port":2222,"username":"u","password":%q}}`, host, password)
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	w := save("POST", "/api/cameras", "uplaod.example.com", "p")
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Upload check failed for uplaod.example.com:2222") {
+		t.Fatalf("add with unreachable host = %d %q, want 400", w.Code, w.Body.String())
+	}
+	if _, err := server.configService.GetCamera("cam"); err == nil {
+		t.Fatal("camera saved despite the failed check")
+	}
+	if len(tested) != 1 || tested[0].TimeoutConnectSeconds != 10 {
+		t.Errorf("tested = %+v, want one check with a 10s connect timeout", tested)
+	}
+
+	// Skipping the check saves anyway
+	if w := save("POST", "/api/cameras?skip_connectivity_check=true", "upload.example.com", "p"); w.Code != http.StatusCreated {
+		t.Fatalf("add with skip = %d %q", w.Code, w.Body.String())
+	}
+	if len(tested) != 1 {
+		t.Error("upload checked despite skip_connectivity_check")
+	}
+
+	// Unchanged upload settings (with the password preserved) are not checked
+	if w := save("PUT", "/api/cameras/cam", "upload.example.com", ""); w.Code != http.StatusOK {
+		t.Fatalf("unchanged update = %d %q", w.Code, w.Body.String())
+	}
+	if len(tested) != 1 {
+		t.Error("unchanged upload settings were checked")
+	}
+
+	// A changed host is checked with the stored password
+	uploadErr = nil
+	if w := save("PUT", "/api/cameras/cam", "upload2.example.com", ""); w.Code != http.StatusOK {
+		t.Fatalf("changed update = %d %q", w.Code, w.Body.String())
+	}
+	if len(tested) != 2 || tested[1].Host != "upload2.example.com" || tested[1].Password != "p" {
+		t.Errorf("tested = %+v, want the new host checked with the stored password", tested)
+	}
+}
+
+// TestCameraQuality tests GET /api/cameras/{id}/quality
+func TestCameraQuality(t *testing.T) {
+	addCam := func(svc *config.Service) {
+		svc.AddCamera(config.Camera{
+			ID:      "quality-cam",
+			Name:    "Quality Test",
+			Type:    "http",
+			Enabled: true,
+			Upload:  &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+		})
+	}
+
+	t.Run("success returns samples", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{
+			GetQuality: func(cameraID string) (interface{}, bool) {
+				return []map[string]interface{}{{"sharpness": 42.0}}, true
+			},
+		})
+		addCam(server.configService)
+
+		req := httptest.NewRequest("GET", "/api/cameras/quality-cam/quality", nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			CameraID string                   `json:"camera_id"`
+			Samples  []map[string]interface{} `json:"samples"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if resp.CameraID != "quality-cam" || len(resp.Samples) != 1 {
+			t.Errorf("Unexpected response: %+v", resp)
+		}
+	})
+
+	t.Run("camera not running returns 503", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{
+			GetQuality: func(string) (interface{}, bool) { return nil, false },
+		})
+		addCam(server.configService)
+
+		req := httptest.NewRequest("GET", "/api/cameras/quality-cam/quality", nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected 503, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown camera returns 404", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{
+			GetQuality: func(string) (interface{}, bool) { return nil, true },
+		})
+
+		req := httptest.NewRequest("GET", "/api/cameras/missing/quality", nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+}
+
+// TestCameraHistory tests GET /api/cameras/{id}/history and /history/{ts}
+func TestCameraHistory(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	captured := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.Add("history-cam", []byte("frame"), captured, history.Limits{}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := testServerWithAuth(t, ServerConfig{
+		GetHistory:      store.List,
+		GetHistoryFrame: store.Get,
+	})
+	server.configService.AddCamera(config.Camera{
+		ID:      "history-cam",
+		Name:    "History Test",
+		Type:    "http",
+		Enabled: true,
+		Upload:  &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/cameras/history-cam/history")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Frames []history.Frame `json:"frames"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(resp.Frames) != 1 || resp.Frames[0].Timestamp != captured.UnixMilli() {
+		t.Fatalf("Unexpected frames: %+v", resp.Frames)
+	}
+
+	w = get(fmt.Sprintf("/api/cameras/history-cam/history/%d", captured.UnixMilli()))
+	if w.Code != http.StatusOK || w.Body.String() != "frame" || w.Header().Get("Content-Type") != "image/jpeg" {
+		t.Errorf("frame: got %d %q", w.Code, w.Body.String())
+	}
+
+	for path, want := range map[string]int{
+		"/api/cameras/history-cam/history/1":      http.StatusNotFound,
+		"/api/cameras/history-cam/history/latest": http.StatusBadRequest,
+		"/api/cameras/missing/history":            http.StatusNotFound,
+	} {
+		if w := get(path); w.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestEndpointAuth(t *testing.T) {
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("bridge_up 1\n"))
+	})
+
+	setAuth := func(t *testing.T, server *Server, health, metricsMode, token string) {
+		t.Helper()
+		if err := server.configService.UpdateGlobal(func(g *config.GlobalSettings) error {
+			g.WebConsole.HealthAuth = health
+			g.WebConsole.MetricsAuth = metricsMode
+			g.WebConsole.MetricsToken = token
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateGlobal: %v", err)
+		}
+	}
+
+	do := func(server *Server, path string, prep func(*http.Request)) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if prep != nil {
+			prep(req)
+		}
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w.Code
+	}
+	bearer := func(token string) func(*http.Request) {
+		return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	}
+	basic := func(r *http.Request) { r.SetBasicAuth("admin", "test") }
+
+	t.Run("default is unauthenticated", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{Metrics: metrics})
+		if code := do(server, "/healthz", nil); code == http.StatusUnauthorized {
+			t.Errorf("/healthz: got 401 with default settings")
+		}
+		if code := do(server, "/metrics", nil); code != http.StatusOK {
+			t.Errorf("/metrics: expected 200, got %d", code)
+		}
+	})
+
+	t.Run("metrics token independent of health", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{Metrics: metrics})
+		setAuth(t, server, config.EndpointAuthNone, config.EndpointAuthToken, "s3cret")
+
+		if code := do(server, "/healthz", nil); code == http.StatusUnauthorized {
+			t.Errorf("/healthz: expected unauthenticated access")
+		}
+		if code := do(server, "/metrics", nil); code != http.StatusUnauthorized {
+			t.Errorf("/metrics without token: expected 401, got %d", code)
+		}
+		if code := do(server, "/metrics", bearer("wrong")); code != http.StatusUnauthorized {
+			t.Errorf("/metrics wrong token: expected 401, got %d", code)
+		}
+		if code := do(server, "/metrics", bearer("s3cret")); code != http.StatusOK {
+			t.Errorf("/metrics with token: expected 200, got %d", code)
+		}
+	})
+
+	t.Run("token mode without token rejects", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{Metrics: metrics})
+		setAuth(t, server, "", config.EndpointAuthToken, "")
+
+		if code := do(server, "/metrics", bearer("")); code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", code)
+		}
+	})
+
+	t.Run("health basic auth", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{})
+		setAuth(t, server, config.EndpointAuthBasic, "", "")
+
+		if code := do(server, "/healthz", nil); code != http.StatusUnauthorized {
+			t.Errorf("/healthz without auth: expected 401, got %d", code)
+		}
+		if code := do(server, "/healthz", basic); code == http.StatusUnauthorized {
+			t.Errorf("/healthz with basic auth: got 401")
+		}
+	})
+
+	t.Run("unknown mode fails closed", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{Metrics: metrics})
+		setAuth(t, server, "", "bogus", "")
+
+		if code := do(server, "/metrics", nil); code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", code)
+		}
+	})
+
+	t.Run("metrics not registered without handler", func(t *testing.T) {
+		server := testServerWithAuth(t, ServerConfig{})
+		if code := do(server, "/metrics", basic); code == http.StatusOK {
+			t.Errorf("expected /metrics to be unavailable, got 200")
+		}
+	})
+}
+
+func TestRequestLimiter(t *testing.T) {
+	limiter := resource.NewLimiter(resource.Config{MaxConcurrentWebRequests: 1})
+	server := testServerWithAuth(t, ServerConfig{ResourceLimiter: limiter})
+
+	do := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("/api/status"); w.Code != http.StatusOK {
+		t.Fatalf("Expected 200 with free slot, got %d", w.Code)
+	}
+
+	// Occupy the only slot, as an in-flight request would
+	if !limiter.TryAcquireWebRequest() {
+		t.Fatal("TryAcquireWebRequest failed")
+	}
+	defer limiter.ReleaseWebRequest()
+
+	w := do("/api/status")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when at capacity, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header")
+	}
+
+	if w := do("/healthz"); w.Code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") != "" {
+		t.Error("/healthz should bypass the limiter")
+	}
+	if w := do("/api/config"); w.Code != http.StatusOK {
+		t.Errorf("Cheap endpoint /api/config should bypass the limiter, got %d", w.Code)
+	}
+}
+
+func TestSummaryEndpoint(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{
+		GetSummary: func() interface{} {
+			return map[string]interface{}{"bridge_id": "kabc-bridge", "camera_count": 2}
+		},
+	})
+
+	req := httptest.NewRequest("GET", "/api/summary", nil)
+	w := httptest.NewRecorder()
+	server.GetMux().ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/api/summary", nil)
+	req.SetBasicAuth("admin", "test")
+	w = httptest.NewRecorder()
+	server.GetMux().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	var summary map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if summary["bridge_id"] != "kabc-bridge" {
+		t.Errorf("bridge_id = %v", summary["bridge_id"])
+	}
+}
+
+func TestStatusQuery(t *testing.T) {
+	var got []StatusQuery
+	server := testServerWithAuth(t, ServerConfig{
+		GetStatus: func() interface{} { return map[string]interface{}{"page": false} },
+		GetStatusPage: func(q StatusQuery) interface{} {
+			got = append(got, q)
+			return map[string]interface{}{"page": true}
+		},
+	})
+	server.configService.AddCamera(config.Camera{ID: "cam-1", Name: "One", Type: "http"})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/api/status"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"page":false`) {
+		t.Errorf("unfiltered: %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/status?offset=10&limit=5"); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"page":true`) {
+		t.Errorf("paged: %d %s", w.Code, w.Body.String())
+	}
+	if w := get("/api/status?camera=cam-1"); w.Code != http.StatusOK {
+		t.Errorf("camera: %d", w.Code)
+	}
+	if w := get("/api/status?camera=missing"); w.Code != http.StatusNotFound {
+		t.Errorf("unknown camera: %d, want 404", w.Code)
+	}
+	if w := get("/api/status?limit=-1"); w.Code != http.StatusBadRequest {
+		t.Errorf("bad limit: %d, want 400", w.Code)
+	}
+
+	want := []StatusQuery{{Offset: 10, Limit: 5}, {CameraID: "cam-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("queries = %+v, want %+v", got, want)
+	}
+}
+
+func TestCameraCaptureRate(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/cameras", strings.NewReader(body))
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+	upload := `"upload":{"host":"upload.example.com","username":"u","password":"p"}`
+
+	if w := post(`{"id":"both","type":"http","capture_interval_seconds":60,"captures_per_hour":60,` + upload + `}`); w.Code != http.StatusBadRequest {
+		t.Errorf("both set: %d, want 400", w.Code)
+	}
+	if w := post(`{"id":"hourly","type":"http","snapshot_url":"http://cam.local/snap.jpg","captures_per_hour":12,` + upload + `}`); w.Code != http.StatusOK && w.Code != http.StatusCreated {
+		t.Fatalf("per hour: %d %s", w.Code, w.Body.String())
+	}
+	cam, err := server.configService.GetCamera("hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cam.CaptureIntervalSeconds != 0 || cam.EffectiveCaptureIntervalSeconds() != 300 {
+		t.Errorf("stored interval %d, effective %d", cam.CaptureIntervalSeconds, cam.EffectiveCaptureIntervalSeconds())
+	}
+}
+
+func TestCameraPostCaptureHookConfigOnly(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+	upload := `"upload":{"host":"upload.example.com","username":"u","password":"p"}`
+
+	hook := `"post_capture_hook":{"command":["/usr/local/bin/blur"]},`
+	if w := send("POST", "/api/cameras", `{"id":"hooked","type":"http",`+hook+upload+`}`); w.Code != http.StatusBadRequest {
+		t.Errorf("add with a hook: %d, want 400", w.Code)
+	}
+
+	// A hook from the config file survives edits from the dashboard
+	cam := config.Camera{ID: "hooked", Name: "Hooked", Type: "http", SnapshotURL: "http://cam.local/snap.jpg", Upload: &config.Upload{Host: "upload.example.com", Username: "u", Password: "p"},
+		PostCaptureHook: &config.PostCaptureHook{Command: []string{"/usr/local/bin/blur"}}}
+	if err := server.configService.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+	if w := send("PUT", "/api/cameras/hooked", `{"id":"hooked","type":"http","name":"Renamed",`+upload+`}`); w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body.String())
+	}
+	got, _ := server.configService.GetCamera("hooked")
+	if got.PostCaptureHook == nil || got.PostCaptureHook.Command[0] != "/usr/local/bin/blur" {
+		t.Errorf("hook after update = %+v", got.PostCaptureHook)
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	get := func() (valid bool, problems []config.Problem) {
+		req := httptest.NewRequest("GET", "/api/config/validation", nil)
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		var body struct {
+			Valid    bool             `json:"valid"`
+			Problems []config.Problem `json:"problems"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v (status %d)", err, w.Code)
+		}
+		return body.Valid, body.Problems
+	}
+
+	if valid, problems := get(); !valid || len(problems) != 0 {
+		t.Errorf("empty config: valid=%v problems=%v", valid, problems)
+	}
+	server.configService.AddCamera(config.Camera{ID: "cam", Name: "Cam", Type: "http",
+		Upload: &config.Upload{Host: "example.com", Username: "u", Password: "p"}})
+	valid, problems := get()
+	if valid || len(problems) != 1 || problems[0].Camera != "cam" || problems[0].Field != "snapshot_url" {
+		t.Errorf("valid=%v problems=%+v, want snapshot_url problem for cam", valid, problems)
+	}
+}
+
+// putCameraForm sends a camera update shaped like the console's camera form
+func putCameraForm(t *testing.T, server *Server, id, body string) {
+	t.Helper()
+	req := httptest.NewRequest("PUT", "/api/cameras/"+id, strings.NewReader(body))
+	req.SetBasicAuth("admin", "test")
+	w := httptest.NewRecorder()
+	server.GetMux().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCameraUpdateKeepsHiddenSettings(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	hour := 3
+	cam := config.Camera{ID: "kspb", Name: "KSPB", Type: "http", Enabled: true,
+		SnapshotURL:            "http://cam.local/snap.jpg",
+		CaptureIntervalSeconds: 60,
+		Auth:                   &config.Auth{Type: "basic", Username: "viewer", Password: "secret"},
+		Upload:                 &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+		Thumbnail:              &config.Thumbnail{MaxWidth: 200},
+		Regions:                []config.Region{{Name: "north", Rect: config.Rect{Width: 0.5, Height: 1, Normalized: true}}},
+		History:                &config.History{Enabled: true, MaxFrames: 100},
+		Timelapse:              &config.Timelapse{Enabled: true, Hour: &hour},
+		DedupWindow:            4,
+		LatestName:             "latest.jpg",
+		UploadQuietHours:       &config.QuietHours{Start: "22:00", End: "06:00"},
+		FreshnessSLASeconds:    600,
+		CatchupMinutes:         30,
+	}
+	if err := server.configService.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+
+	// Exactly what saveCamera in app.js sends after renaming the camera, switching
+	// it to captures per hour and clearing its credentials
+	putCameraForm(t, server, "kspb", `{
+		"id": "kspb", "name": "KSPB North", "type": "http", "enabled": true,
+		"captures_per_hour": 12, "capture_interval_seconds": 0,
+		"upload": {"protocol": "sftp", "host": "upload.example.com", "port": 2222, "username": "u", "base_path": ""},
+		"image": {"max_width": 0, "max_height": 0, "quality": 0, "rotate": 0},
+		"snapshot_url": "http://cam.local/snap.jpg",
+		"auth": null
+	}`)
+
+	got, _ := server.configService.GetCamera("kspb")
+	if got.Name != "KSPB North" || got.CapturesPerHour != 12 || got.CaptureIntervalSeconds != 0 || got.Auth != nil {
+		t.Errorf("form fields not applied: name %q, per hour %d, interval %d, auth %+v",
+			got.Name, got.CapturesPerHour, got.CaptureIntervalSeconds, got.Auth)
+	}
+	if got.Upload.Password != "p" {
+		t.Errorf("upload password = %q, want it kept", got.Upload.Password)
+	}
+	if got.Thumbnail == nil || len(got.Regions) != 1 || got.History == nil || got.Timelapse == nil ||
+		got.Timelapse.Hour == nil || *got.Timelapse.Hour != 3 {
+		t.Errorf("renditions lost: thumbnail %+v, regions %+v, history %+v, timelapse %+v",
+			got.Thumbnail, got.Regions, got.History, got.Timelapse)
+	}
+	if got.DedupWindow != 4 || got.LatestName != "latest.jpg" || got.UploadQuietHours == nil ||
+		got.FreshnessSLASeconds != 600 || got.CatchupMinutes != 30 {
+		t.Errorf("settings lost: %+v", got)
+	}
+}
+
+func TestCameraUpdateKeepsPrivacyZones(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	cam := config.Camera{ID: "kspb", Name: "KSPB", Type: "http", Enabled: true,
+		SnapshotURL:            "http://cam.local/snap.jpg",
+		CaptureIntervalSeconds: 60,
+		Upload:                 &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+		Image: &config.ImageProcessing{
+			PrivacyZones: []config.PrivacyZone{{X: 0.8, Width: 0.2, Height: 0.3, Normalized: true}},
+			PrivacyFill:  "#808080",
+		},
+	}
+	if err := server.configService.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+
+	// The form shows the resize and rotate settings only, and sends them even unset
+	for _, image := range []string{
+		`{"max_width": 1280, "max_height": 0, "quality": 80, "rotate": 0}`,
+		`{"max_width": 0, "max_height": 0, "quality": 0, "rotate": 0}`,
+	} {
+		putCameraForm(t, server, "kspb", `{
+			"id": "kspb", "name": "KSPB", "type": "http", "enabled": true,
+			"capture_interval_seconds": 60, "captures_per_hour": 0,
+			"upload": {"protocol": "sftp", "host": "upload.example.com", "port": 2222, "username": "u", "base_path": ""},
+			"image": `+image+`,
+			"snapshot_url": "http://cam.local/snap.jpg",
+			"auth": null
+		}`)
+		got, _ := server.configService.GetCamera("kspb")
+		if got.Image == nil || len(got.Image.PrivacyZones) != 1 || got.Image.PrivacyFill != "#808080" {
+			t.Fatalf("image %s: privacy mask lost, image = %+v", image, got.Image)
+		}
+	}
+
+	// An explicit empty list removes the zones
+	putCameraForm(t, server, "kspb", `{"image": {"privacy_zones": []}}`)
+	if got, _ := server.configService.GetCamera("kspb"); len(got.Image.PrivacyZones) != 0 {
+		t.Errorf("zones = %+v, want them removed", got.Image.PrivacyZones)
+	}
+}
+
+func TestCameraUpdateKeepsRTSPSettings(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	cam := config.Camera{ID: "kspb", Name: "KSPB", Type: "rtsp", Enabled: true,
+		CaptureIntervalSeconds: 60,
+		RTSP: &config.RTSP{URL: "rtsp://cam.local/stream", Username: "viewer", Password: "secret",
+			SpoolThresholdKB: 4096, ReconnectInitialSeconds: 5, ReconnectMaxSeconds: 120},
+		Upload: &config.Upload{Host: "upload.example.com", Port: 2222, Username: "u", Password: "p"},
+	}
+	if err := server.configService.AddCamera(cam); err != nil {
+		t.Fatalf("AddCamera: %v", err)
+	}
+
+	putCameraForm(t, server, "kspb", `{
+		"id": "kspb", "name": "KSPB", "type": "rtsp", "enabled": true,
+		"capture_interval_seconds": 60, "captures_per_hour": 0,
+		"upload": {"protocol": "sftp", "host": "upload.example.com", "port": 2222, "username": "u", "base_path": ""},
+		"image": {"max_width": 0, "max_height": 0, "quality": 0, "rotate": 0},
+		"rtsp": {"url": "rtsp://cam.local/stream2", "username": "viewer", "password": ""}
+	}`)
+	got, _ := server.configService.GetCamera("kspb")
+	want := config.RTSP{URL: "rtsp://cam.local/stream2", Username: "viewer", Password: "secret",
+		SpoolThresholdKB: 4096, ReconnectInitialSeconds: 5, ReconnectMaxSeconds: 120}
+	if got.RTSP == nil || *got.RTSP != want {
+		t.Errorf("rtsp = %+v, want %+v", got.RTSP, want)
+	}
+}
+
+func TestCameraSaveValidation(t *testing.T) {
+	server := testServerWithAuth(t, ServerConfig{})
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth("admin", "test")
+		w := httptest.NewRecorder()
+		server.GetMux().ServeHTTP(w, req)
+		return w
+	}
+	camera := `"type":"http","snapshot_url":"http://cam.local/snap.jpg","upload":{"host":"upload.example.com","username":"u","password":"p"}`
+
+	if w := send("POST", "/api/cameras", `{"id":"kspb",`+camera+`,"upload_quiet_hours":{"start":"25:00","end":"06:00"}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("add with bad quiet hours: %d, want 400", w.Code)
+	}
+	if w := send("POST", "/api/cameras", `{"id":"kspb",`+camera+`}`); w.Code != http.StatusCreated {
+		t.Fatalf("add: %d %s", w.Code, w.Body.String())
+	}
+	w := send("PUT", "/api/cameras/kspb", `{"upload_quiet_hours":{"start":"22:00","end":"6pm"}}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "Invalid camera") {
+		t.Errorf("update with bad quiet hours: %d %s, want 400", w.Code, w.Body.String())
+	}
+	if cam, _ := server.configService.GetCamera("kspb"); cam.UploadQuietHours != nil {
+		t.Errorf("invalid update saved: %+v", cam.UploadQuietHours)
+	}
+}
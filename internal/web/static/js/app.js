@@ -181,6 +181,10 @@ function updateStatusDisplay() {
     
     // Update uploads today (if available)
     document.getElementById('statUploads').textContent = status.uploads_today || 0;
+    const bandwidth = status.orchestrator?.upload_stats?.bandwidth_limit;
+    document.getElementById('statUploadsLabel').textContent = bandwidth
+        ? `Uploads Today (max ${Math.round(bandwidth.limit_bytes_per_sec / 1024)} KB/s)`
+        : 'Uploads Today';
     
     // Update system resources display
     updateSystemResourcesDisplay(status.system, status.queued_images);
@@ -295,7 +299,7 @@ function updateCameraOverview() {
             </div>
             <div class="camera-info">
                 <div class="camera-name">${escapeHtml(cam.name)}</div>
-                <div class="camera-meta">${cam.type} • ${cam.capture_interval_seconds}s interval</div>
+                <div class="camera-meta">${cam.type} • ${formatCaptureRate(cam)}</div>
                 <div class="camera-status-info">${nextCaptureInfo} ${statusBadge}</div>
             </div>
             <div class="camera-status">
@@ -411,6 +415,10 @@ function buildCameraCardDetails(cam) {
         if (camStats) {
             if (camStats.capture_stats?.currently_capturing) {
                 captureStatusText = '<span class="status-active">🔴 Capturing now</span>';
+            } else if (camStats.capture_stats?.settling) {
+                const settleEnd = new Date(camStats.capture_stats.settle_until);
+                const secondsLeft = Math.max(0, Math.floor((settleEnd - new Date()) / 1000));
+                captureStatusText = `<span class="status-info">Settling: ${secondsLeft}s</span>`;
             } else if (camStats.capture_stats?.next_capture_time) {
                 const nextTime = new Date(camStats.capture_stats.next_capture_time);
                 const now = new Date();
@@ -494,7 +502,7 @@ function buildCameraCardDetails(cam) {
         </div>
         <div class="detail-row">
             <span class="label">Capture Interval</span>
-            <span class="value">${cam.capture_interval_seconds}s</span>
+            <span class="value">${formatCaptureRate(cam)}</span>
         </div>
         <div class="detail-row">
             <span class="label">Status</span>
@@ -679,6 +687,8 @@ function getCameraFormHtml(cam = null) {
                         <option value="http" ${cam?.type === 'http' ? 'selected' : ''}>HTTP Snapshot</option>
                         <option value="rtsp" ${cam?.type === 'rtsp' ? 'selected' : ''}>RTSP Stream</option>
                         <option value="onvif" ${cam?.type === 'onvif' ? 'selected' : ''}>ONVIF Camera</option>
+                        <option value="folder" ${cam?.type === 'folder' ? 'selected' : ''}>Image Folder</option>
+                        <option value="agent" ${cam?.type === 'agent' ? 'selected' : ''}>Bridge Agent</option>
                     </select>
                 </div>
                 
@@ -756,13 +766,75 @@ function getCameraFormHtml(cam = null) {
                     </div>
                 </div>
                 
-                <div class="form-group">
-                    <label for="camInterval">Capture Interval (seconds)</label>
-                    <input type="number" id="camInterval" class="form-control" 
-                           value="${cam?.capture_interval_seconds || 60}"
-                           min="1" max="1800" required>
-                    <p class="form-help">How often to capture images (1 second to 30 minutes)</p>
+                <div id="folderFields" style="display: ${cam?.type === 'folder' ? 'block' : 'none'}">
+                    <div class="form-group">
+                        <label for="camFolderPath">Folder Path</label>
+                        <input type="text" id="camFolderPath" class="form-control" 
+                               value="${cam?.folder?.path || ''}"
+                               placeholder="/data/captures">
+                        <small>Directory another program writes JPEG images to; the newest new image is captured each cycle</small>
+                    </div>
+                    <div class="form-group">
+                        <label>
+                            <input type="checkbox" id="camFolderDelete" ${cam?.folder?.delete_after_queue ? 'checked' : ''}>
+                            Delete each image once queued for upload
+                        </label>
+                    </div>
+                </div>
+                
+                <div id="agentFields" style="display: ${cam?.type === 'agent' ? 'block' : 'none'}">
+                    <div class="form-group">
+                        <label for="camAgentUrl">Agent URL</label>
+                        <input type="url" id="camAgentUrl" class="form-control" 
+                               value="${cam?.agent?.url || ''}"
+                               placeholder="https://10.0.5.2:8443">
+                        <small>Agent on a host that can reach the camera</small>
+                    </div>
+                    <div class="form-group">
+                        <label for="camAgentCamera">Camera on Agent</label>
+                        <input type="text" id="camAgentCamera" class="form-control" 
+                               value="${cam?.agent?.camera_id || ''}"
+                               placeholder="Same as Camera ID">
+                    </div>
+                    <div class="form-row">
+                        <div class="form-group">
+                            <label for="camAgentCert">Client Certificate</label>
+                            <input type="text" id="camAgentCert" class="form-control" 
+                                   value="${cam?.agent?.cert_file || ''}"
+                                   placeholder="/data/agent/client.pem">
+                        </div>
+                        <div class="form-group">
+                            <label for="camAgentKey">Client Key</label>
+                            <input type="text" id="camAgentKey" class="form-control" 
+                                   value="${cam?.agent?.key_file || ''}"
+                                   placeholder="/data/agent/client-key.pem">
+                        </div>
+                    </div>
+                    <div class="form-group">
+                        <label for="camAgentCa">Agent CA Certificate</label>
+                        <input type="text" id="camAgentCa" class="form-control" 
+                               value="${cam?.agent?.ca_file || ''}"
+                               placeholder="/data/agent/ca.pem">
+                        <small>Leave empty to trust the system certificate authorities</small>
+                    </div>
+                </div>
+                
+                <div class="form-row">
+                    <div class="form-group">
+                        <label for="camInterval">Capture Rate</label>
+                        <input type="number" id="camInterval" class="form-control" 
+                               value="${cam?.captures_per_hour || cam?.capture_interval_seconds || 60}"
+                               min="1" max="3600" required>
+                    </div>
+                    <div class="form-group">
+                        <label for="camIntervalUnit">Unit</label>
+                        <select id="camIntervalUnit" class="form-control">
+                            <option value="seconds" ${cam?.captures_per_hour ? '' : 'selected'}>Seconds between images</option>
+                            <option value="per_hour" ${cam?.captures_per_hour ? 'selected' : ''}>Images per hour</option>
+                        </select>
+                    </div>
                 </div>
+                <p class="form-help">How often to capture images (every 1 second to 30 minutes, or 2 to 3600 per hour)</p>
                 
                 <button type="button" class="btn" onclick="testCamera()">Test Snapshot</button>
                 <div id="cameraTestResult" class="camera-test-result"></div>
@@ -812,6 +884,14 @@ function getCameraFormHtml(cam = null) {
                         </div>
                     </div>
                 </div>
+                
+                <div class="form-group">
+                    <label for="imageRotate">Rotation</label>
+                    <select id="imageRotate" class="form-control">
+                        ${[0, 90, 180, 270].map(deg => `<option value="${deg}" ${(cam?.image?.rotate || 0) === deg ? 'selected' : ''}>${deg === 0 ? 'None' : deg + '° clockwise'}</option>`).join('')}
+                    </select>
+                    <p class="form-help">For cameras mounted on their side or upside down</p>
+                </div>
             </div>
             
             <div class="form-section">
@@ -859,6 +939,7 @@ function getCameraFormHtml(cam = null) {
                 </div>
                 
                 <button type="button" class="btn" onclick="testUpload()">Test Connection</button>
+                <button type="button" class="btn" onclick="testUpload(true)" title="Uploads and deletes a small test image">Test Upload</button>
                 <div id="uploadTestResult"></div>
             </div>
             
@@ -882,6 +963,8 @@ function updateCameraTypeFields() {
     document.getElementById('httpFields').style.display = type === 'http' ? 'block' : 'none';
     document.getElementById('rtspFields').style.display = type === 'rtsp' ? 'block' : 'none';
     document.getElementById('onvifFields').style.display = type === 'onvif' ? 'block' : 'none';
+    document.getElementById('folderFields').style.display = type === 'folder' ? 'block' : 'none';
+    document.getElementById('agentFields').style.display = type === 'agent' ? 'block' : 'none';
 }
 
 function updateImagePreset() {
@@ -912,6 +995,23 @@ function updateImagePreset() {
     }
 }
 
+// captureRateFields returns the capture rate in the unit chosen in the camera form
+function captureRateFields() {
+    const value = parseInt(document.getElementById('camInterval').value, 10);
+    // Both are sent: the server keeps fields a camera update omits
+    if (document.getElementById('camIntervalUnit').value === 'per_hour') {
+        return { captures_per_hour: value, capture_interval_seconds: 0 };
+    }
+    return { capture_interval_seconds: value, captures_per_hour: 0 };
+}
+
+function formatCaptureRate(cam) {
+    if (cam.captures_per_hour) {
+        return `${cam.captures_per_hour}/hour`;
+    }
+    return `${cam.capture_interval_seconds}s interval`;
+}
+
 async function saveCamera(event, existingId = null) {
     event.preventDefault();
     
@@ -922,7 +1022,7 @@ async function saveCamera(event, existingId = null) {
         name: document.getElementById('camName').value || document.getElementById('camId').value,
         type: type,
         enabled: document.getElementById('camEnabled').checked,
-        capture_interval_seconds: parseInt(document.getElementById('camInterval').value, 10),
+        ...captureRateFields(),
         upload: {
             protocol: 'sftp',
             host: document.getElementById('uploadHost').value || 'upload.aviationwx.org',
@@ -933,30 +1033,33 @@ async function saveCamera(event, existingId = null) {
         }
     };
     
-    // Image processing settings
+    // Image processing settings. Sent even when unset, so clearing them takes effect;
+    // settings the form does not show (e.g. privacy zones) are kept by the server
     const maxWidth = parseInt(document.getElementById('imageMaxWidth').value, 10) || 0;
     const maxHeight = parseInt(document.getElementById('imageMaxHeight').value, 10) || 0;
     const quality = parseInt(document.getElementById('imageQuality').value, 10) || 0;
+    const rotate = parseInt(document.getElementById('imageRotate').value, 10) || 0;
     
-    if (maxWidth > 0 || maxHeight > 0 || (quality > 0 && quality !== 85)) {
+    if (maxWidth > 0 || maxHeight > 0 || (quality > 0 && quality !== 85) || rotate > 0) {
         camera.image = {
             max_width: maxWidth,
             max_height: maxHeight,
             quality: quality,
+            rotate: rotate,
         };
+    } else {
+        camera.image = { max_width: 0, max_height: 0, quality: 0, rotate: 0 };
     }
     
     if (type === 'http') {
         camera.snapshot_url = document.getElementById('camSnapshotUrl').value;
         const authUser = document.getElementById('camAuthUser').value;
         const authPass = document.getElementById('camAuthPass').value;
-        if (authUser) {
-            camera.auth = {
-                type: 'basic',
-                username: authUser,
-                password: authPass,
-            };
-        }
+        camera.auth = authUser ? {
+            type: 'basic',
+            username: authUser,
+            password: authPass,
+        } : null;
     } else if (type === 'rtsp') {
         camera.rtsp = {
             url: document.getElementById('camRtspUrl').value,
@@ -968,21 +1071,36 @@ async function saveCamera(event, existingId = null) {
             endpoint: document.getElementById('camOnvifEndpoint').value,
             username: document.getElementById('camOnvifUser').value,
             password: document.getElementById('camOnvifPass').value,
-            profile_token: document.getElementById('camOnvifProfile').value || undefined,
+            profile_token: document.getElementById('camOnvifProfile').value,
+        };
+    } else if (type === 'folder') {
+        camera.folder = {
+            path: document.getElementById('camFolderPath').value,
+            delete_after_queue: document.getElementById('camFolderDelete').checked,
+        };
+    } else if (type === 'agent') {
+        camera.agent = {
+            url: document.getElementById('camAgentUrl').value,
+            camera_id: document.getElementById('camAgentCamera').value,
+            cert_file: document.getElementById('camAgentCert').value,
+            key_file: document.getElementById('camAgentKey').value,
+            ca_file: document.getElementById('camAgentCa').value,
         };
     }
     
+    const save = (query) => existingId
+        ? api(`/cameras/${existingId}${query}`, { method: 'PUT', body: JSON.stringify(camera) })
+        : api(`/cameras${query}`, { method: 'POST', body: JSON.stringify(camera) });
     try {
-        if (existingId) {
-            await api(`/cameras/${existingId}`, {
-                method: 'PUT',
-                body: JSON.stringify(camera),
-            });
-        } else {
-            await api('/cameras', {
-                method: 'POST',
-                body: JSON.stringify(camera),
-            });
+        try {
+            await save('');
+        } catch (err) {
+            // The upload server check can be skipped, e.g. when it is not set up yet
+            if (!err.message.startsWith('Upload check failed') ||
+                !confirm(err.message.trim() + '\n\nSave anyway?')) {
+                throw err;
+            }
+            await save('?skip_connectivity_check=true');
         }
         
         closeModal();
@@ -1067,16 +1185,23 @@ function buildCameraConfigFromForm() {
         onvif_user: document.getElementById('camOnvifUser')?.value,
         onvif_pass: document.getElementById('camOnvifPass')?.value,
         onvif_profile: document.getElementById('camOnvifProfile')?.value,
+        folder_path: document.getElementById('camFolderPath')?.value,
+        folder_delete: document.getElementById('camFolderDelete')?.checked,
+        agent_url: document.getElementById('camAgentUrl')?.value,
+        agent_camera: document.getElementById('camAgentCamera')?.value,
+        agent_cert: document.getElementById('camAgentCert')?.value,
+        agent_key: document.getElementById('camAgentKey')?.value,
+        agent_ca: document.getElementById('camAgentCa')?.value,
     };
     return window.buildCameraConfigFromFormValues(values);
 }
 
-async function testUpload() {
+async function testUpload(roundTrip = false) {
     const resultDiv = document.getElementById('uploadTestResult');
-    resultDiv.innerHTML = '<div class="test-result" style="background: var(--color-bg)">Testing connection...</div>';
+    resultDiv.innerHTML = `<div class="test-result" style="background: var(--color-bg)">${roundTrip ? 'Uploading test image...' : 'Testing connection...'}</div>`;
 
     try {
-        const result = await api('/test/upload', {
+        const result = await api(roundTrip ? '/test/upload?round_trip=true' : '/test/upload', {
             method: 'POST',
             body: JSON.stringify({
                 protocol: 'sftp',
@@ -1088,7 +1213,10 @@ async function testUpload() {
             }),
         });
 
-        if (result.status === 'ok') {
+        if (result.status === 'ok' && result.round_trip) {
+            const cleanup = result.round_trip.deleted ? 'and removed' : `but could not be removed (${result.round_trip.delete_error || 'kept'})`;
+            resultDiv.innerHTML = `<div class="test-result success">✓ Test image written to ${result.round_trip.remote_path} ${cleanup}</div>`;
+        } else if (result.status === 'ok') {
             resultDiv.innerHTML = '<div class="test-result success">✓ SFTP connection successful!</div>';
         } else {
             resultDiv.innerHTML = `<div class="test-result error">✗ ${result.error || 'Connection failed'}</div>`;
@@ -1112,6 +1240,7 @@ async function saveWebSettings() {
             body: JSON.stringify({
                 ...config,
                 web_console: {
+                    ...config.web_console,
                     enabled: true,
                     password: password,
                 },
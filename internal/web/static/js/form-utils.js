@@ -6,7 +6,7 @@
  * buildCameraConfigFromFormValues builds a camera config object from form values.
  * Returns null if type is missing or required fields for the type are empty.
  * @param {Object} values - Form field values
- * @param {string} [values.type] - Camera type: "http", "rtsp", "onvif"
+ * @param {string} [values.type] - Camera type: "http", "rtsp", "onvif", "folder", "agent"
  * @param {string} [values.id] - Camera ID (default: "test")
  * @param {string} [values.snapshot_url] - HTTP snapshot URL
  * @param {string} [values.auth_user] - Basic auth username
@@ -18,6 +18,13 @@
  * @param {string} [values.onvif_user] - ONVIF username
  * @param {string} [values.onvif_pass] - ONVIF password
  * @param {string} [values.onvif_profile] - ONVIF profile token
+ * @param {string} [values.folder_path] - Image folder path
+ * @param {boolean} [values.folder_delete] - Delete folder images once queued
+ * @param {string} [values.agent_url] - Bridge agent URL
+ * @param {string} [values.agent_camera] - Camera name on the agent
+ * @param {string} [values.agent_cert] - Client certificate file for the agent
+ * @param {string} [values.agent_key] - Client key file for the agent
+ * @param {string} [values.agent_ca] - CA file for the agent's certificate
  * @returns {Object|null} Camera config or null
  */
 export function buildCameraConfigFromFormValues(values) {
@@ -53,6 +60,20 @@ export function buildCameraConfigFromFormValues(values) {
             password: values.onvif_pass,
             profile_token: values.onvif_profile || undefined,
         };
+    } else if (type === 'folder') {
+        const path = values.folder_path;
+        if (!path) return null;
+        camera.folder = { path, delete_after_queue: !!values.folder_delete };
+    } else if (type === 'agent') {
+        const url = values.agent_url;
+        if (!url) return null;
+        camera.agent = {
+            url,
+            camera_id: values.agent_camera || undefined,
+            cert_file: values.agent_cert,
+            key_file: values.agent_key,
+            ca_file: values.agent_ca || undefined,
+        };
     } else {
         return null;
     }
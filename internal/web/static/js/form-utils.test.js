@@ -87,6 +87,42 @@ test('buildCameraConfigFromFormValues returns null for RTSP when rtsp_url is mis
     assert.strictEqual(buildCameraConfigFromFormValues({ type: 'rtsp' }), null);
 });
 
+test('buildCameraConfigFromFormValues builds folder camera config', () => {
+    const result = buildCameraConfigFromFormValues({
+        type: 'folder',
+        folder_path: '/data/captures',
+        folder_delete: true,
+    });
+    assert.deepStrictEqual(result, {
+        id: 'test',
+        type: 'folder',
+        folder: { path: '/data/captures', delete_after_queue: true },
+    });
+    assert.strictEqual(buildCameraConfigFromFormValues({ type: 'folder' }), null);
+});
+
+test('buildCameraConfigFromFormValues builds agent camera config', () => {
+    const result = buildCameraConfigFromFormValues({
+        type: 'agent',
+        id: 'north',
+        agent_url: 'https://10.0.5.2:8443',
+        agent_cert: '/data/agent/client.pem',
+        agent_key: '/data/agent/client-key.pem',
+    });
+    assert.deepStrictEqual(result, {
+        id: 'north',
+        type: 'agent',
+        agent: {
+            url: 'https://10.0.5.2:8443',
+            camera_id: undefined,
+            cert_file: '/data/agent/client.pem',
+            key_file: '/data/agent/client-key.pem',
+            ca_file: undefined,
+        },
+    });
+    assert.strictEqual(buildCameraConfigFromFormValues({ type: 'agent' }), null);
+});
+
 test('buildCameraConfigFromFormValues builds ONVIF camera config', () => {
     const result = buildCameraConfigFromFormValues({
         type: 'onvif',
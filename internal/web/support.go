@@ -0,0 +1,111 @@
+package web
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alexwitherspoon/AviationWX.org-Bridge/internal/logger"
+)
+
+// supportBundleLogLines is how many buffered log entries go into a support bundle
+const supportBundleLogLines = 1000
+
+// handleSupportBundle serves a zip of redacted diagnostics for bug reports:
+// config, recent logs, status, health, per-camera worker stats and exiftool version.
+func (s *Server) handleSupportBundle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	now := time.Now().UTC()
+	filename := fmt.Sprintf("aviationwx-bridge-support-%s.zip", now.Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := s.writeSupportBundle(zip.NewWriter(w), now); err != nil {
+		// Headers are already sent; the truncated zip fails to open
+		s.log.Error("Failed to write support bundle", "error", err)
+		return
+	}
+	s.log.Info("Support bundle downloaded")
+}
+
+func (s *Server) writeSupportBundle(zw *zip.Writer, now time.Time) error {
+	cameras := s.configService.ListCameras()
+	files := []struct {
+		name  string
+		write func() ([]byte, error)
+	}{
+		{"config.json", func() ([]byte, error) {
+			return redactedJSON(map[string]interface{}{
+				"global":  s.configService.GetGlobal(),
+				"cameras": cameras,
+			})
+		}},
+		{"logs.txt", func() ([]byte, error) {
+			var b strings.Builder
+			for _, entry := range logger.GetRecentLogs(supportBundleLogLines) {
+				b.WriteString(logger.FormatEntry(redactLogEntry(entry)))
+				b.WriteByte('\n')
+			}
+			return []byte(b.String()), nil
+		}},
+		{"status.json", func() ([]byte, error) {
+			if s.getStatus == nil {
+				return redactedJSON(nil)
+			}
+			return redactedJSON(s.getStatus())
+		}},
+		{"health.json", func() ([]byte, error) {
+			return redactedJSON(s.buildHealthStatus())
+		}},
+		{"workers.json", func() ([]byte, error) {
+			workers := map[string]interface{}{}
+			if s.getWorkerStatus != nil {
+				for _, cam := range cameras {
+					workers[cam.ID] = s.getWorkerStatus(cam.ID)
+				}
+			}
+			return redactedJSON(workers)
+		}},
+		{"exiftool.txt", func() ([]byte, error) {
+			if s.exifToolVersion == nil {
+				return []byte("unknown\n"), nil
+			}
+			version, err := s.exifToolVersion()
+			if err != nil {
+				return []byte("unavailable: " + redactString(err.Error()) + "\n"), nil
+			}
+			return []byte(version + "\n"), nil
+		}},
+	}
+
+	for _, f := range files {
+		data, err := f.write()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// redactedJSON encodes v indented with every secret masked
+func redactedJSON(v interface{}) ([]byte, error) {
+	redacted, err := redactJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(redacted, "", "  ")
+}
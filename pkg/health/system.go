@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -167,15 +168,16 @@ func (m *SystemMonitor) getDiskStats() (usedMB, freeMB, totalMB float64) {
 		return 0, 0, 0
 	}
 
-	// Use statfs to get filesystem stats
-	// This is implemented in the queue package, so we do a simple file-based check here
-	info, err := os.Stat(m.queueBasePath)
-	if err != nil {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(m.queueBasePath, &stat); err != nil {
 		return 0, 0, 0
 	}
-	_ = info // We need syscall for proper stats, handled by caller
-
-	return 0, 0, 0 // Placeholder - actual values come from queue manager
+	const mb = 1024 * 1024
+	blockSize := float64(stat.Bsize)
+	totalMB = float64(stat.Blocks) * blockSize / mb
+	freeMB = float64(stat.Bavail) * blockSize / mb
+	usedMB = float64(stat.Blocks-stat.Bfree) * blockSize / mb
+	return usedMB, freeMB, totalMB
 }
 
 // readCPUStats reads CPU statistics from /proc/stat (Linux)